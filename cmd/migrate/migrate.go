@@ -0,0 +1,48 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/traefik/paerser/cli"
+	"github.com/traefik/traefik/v2/pkg/provider/acme"
+)
+
+// Configuration holds the options of the ACME storage migration.
+type Configuration struct {
+	From         string `description:"Path of the ACME storage file to migrate from."`
+	To           string `description:"Path of the ACME storage file to migrate to."`
+	FromResolver string `description:"Name of the certificates resolver to migrate (all the resolvers if empty)."`
+	ToResolver   string `description:"Name of the certificates resolver to migrate to (the same name if empty)."`
+}
+
+// NewCmd builds a new ACME storage migration command.
+func NewCmd() *cli.Command {
+	config := &Configuration{}
+
+	return &cli.Command{
+		Name:          "migrate-acme",
+		Description:   `Copies the ACME accounts and certificates from a storage file to another, after checking their integrity.`,
+		Configuration: config,
+		Run: func(_ []string) error {
+			return run(config)
+		},
+	}
+}
+
+func run(config *Configuration) error {
+	if config.From == "" || config.To == "" {
+		return errors.New("both the storage to migrate from and the storage to migrate to must be set")
+	}
+
+	count, err := acme.MigrateStorage(config.From, config.To, acme.MigrationOptions{
+		FromResolver: config.FromResolver,
+		ToResolver:   config.ToResolver,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d certificate(s) migrated from %s to %s\n", count, config.From, config.To)
+	return nil
+}
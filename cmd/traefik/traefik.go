@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	stdlog "log"
@@ -18,25 +17,29 @@ import (
 	"github.com/coreos/go-systemd/daemon"
 	assetfs "github.com/elazarl/go-bindata-assetfs"
 	"github.com/go-acme/lego/v4/challenge"
-	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/sirupsen/logrus"
 	"github.com/traefik/paerser/cli"
 	"github.com/traefik/traefik/v2/autogen/genstatic"
 	"github.com/traefik/traefik/v2/cmd"
 	"github.com/traefik/traefik/v2/cmd/healthcheck"
+	"github.com/traefik/traefik/v2/cmd/migrate"
+	"github.com/traefik/traefik/v2/cmd/validate"
 	cmdVersion "github.com/traefik/traefik/v2/cmd/version"
 	tcli "github.com/traefik/traefik/v2/pkg/cli"
 	"github.com/traefik/traefik/v2/pkg/collector"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/gc"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
 	"github.com/traefik/traefik/v2/pkg/pilot"
 	"github.com/traefik/traefik/v2/pkg/provider/acme"
 	"github.com/traefik/traefik/v2/pkg/provider/aggregator"
+	"github.com/traefik/traefik/v2/pkg/provider/kv"
 	"github.com/traefik/traefik/v2/pkg/provider/traefik"
+	"github.com/traefik/traefik/v2/pkg/provider/vault"
 	"github.com/traefik/traefik/v2/pkg/safe"
 	"github.com/traefik/traefik/v2/pkg/server"
 	"github.com/traefik/traefik/v2/pkg/server/middleware"
@@ -59,8 +62,8 @@ func main() {
 Complete documentation is available at https://traefik.io`,
 		Configuration: tConfig,
 		Resources:     loaders,
-		Run: func(_ []string) error {
-			return runCmd(&tConfig.Configuration)
+		Run: func(args []string) error {
+			return runCmd(&tConfig.Configuration, staticConfigurationLoader(args))
 		},
 	}
 
@@ -76,6 +79,18 @@ Complete documentation is available at https://traefik.io`,
 		os.Exit(1)
 	}
 
+	err = cmdTraefik.AddCommand(migrate.NewCmd())
+	if err != nil {
+		stdlog.Println(err)
+		os.Exit(1)
+	}
+
+	err = cmdTraefik.AddCommand(validate.NewCmd(&tConfig.Configuration, loaders))
+	if err != nil {
+		stdlog.Println(err)
+		os.Exit(1)
+	}
+
 	err = cli.Execute(cmdTraefik)
 	if err != nil {
 		stdlog.Println(err)
@@ -85,7 +100,33 @@ Complete documentation is available at https://traefik.io`,
 	logrus.Exit(0)
 }
 
-func runCmd(staticConfiguration *static.Configuration) error {
+// staticConfigurationLoader returns a loader of the static configuration from the same sources as at startup,
+// used to reload it on SIGHUP.
+func staticConfigurationLoader(args []string) server.StaticConfigurationLoader {
+	return func() (*static.Configuration, error) {
+		tConfig := cmd.NewTraefikConfiguration()
+
+		cmdTraefik := &cli.Command{Name: "traefik", Configuration: tConfig}
+		for _, loader := range []cli.ResourceLoader{&tcli.FileLoader{}, &tcli.FlagLoader{}, &tcli.EnvLoader{}} {
+			done, err := loader.Load(args, cmdTraefik)
+			if err != nil {
+				return nil, err
+			}
+			if done {
+				break
+			}
+		}
+
+		tConfig.Configuration.SetEffectiveConfiguration()
+		if err := tConfig.Configuration.ValidateConfiguration(); err != nil {
+			return nil, err
+		}
+
+		return &tConfig.Configuration, nil
+	}
+}
+
+func runCmd(staticConfiguration *static.Configuration, loader server.StaticConfigurationLoader) error {
 	configureLogging(staticConfiguration)
 
 	http.DefaultTransport.(*http.Transport).Proxy = http.ProxyFromEnvironment
@@ -101,6 +142,8 @@ func runCmd(staticConfiguration *static.Configuration) error {
 
 	log.WithoutContext().Infof("Traefik version %s built on %s", version.Version, version.BuildDate)
 
+	gc.Configure(staticConfiguration.Runtime)
+
 	jsonConf, err := json.Marshal(staticConfiguration)
 	if err != nil {
 		log.WithoutContext().Errorf("Could not marshal static configuration: %v", err)
@@ -124,6 +167,8 @@ func runCmd(staticConfiguration *static.Configuration) error {
 		return err
 	}
 
+	svr.SetStaticConfigurationReloader(staticConfiguration, loader)
+
 	ctx, _ := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 
 	if staticConfiguration.Ping != nil {
@@ -195,6 +240,10 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 
 	acmeProviders := initACMEProvider(staticConfiguration, &providerAggregator, tlsManager, httpChallengeProvider, tlsChallengeProvider)
 
+	// Vault
+
+	vaultProviders := initVaultProviders(staticConfiguration, &providerAggregator)
+
 	// Entrypoints
 
 	serverEntryPointsTCP, err := server.NewTCPEntryPoints(staticConfiguration.EntryPoints)
@@ -252,19 +301,41 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 	if pilotRegistry != nil {
 		metricRegistries = append(metricRegistries, pilotRegistry)
 	}
+
+	var usageRegistry *metrics.UsageRegistry
+	if staticConfiguration.API != nil && staticConfiguration.API.Usage != nil {
+		usageRegistry = metrics.RegisterUsage(time.Duration(staticConfiguration.API.Usage.Period))
+		metricRegistries = append(metricRegistries, usageRegistry)
+	}
 	metricsRegistry := metrics.NewMultiRegistry(metricRegistries)
 
-	// Service manager factory
+	if len(metricRegistries) > 0 {
+		routinesPool.GoCtx(func(ctx context.Context) {
+			gc.ReportPauses(ctx, metricsRegistry, 10*time.Second)
+		})
+	}
 
-	roundTripperManager := service.NewRoundTripperManager()
-	acmeHTTPHandler := getHTTPChallengeHandler(acmeProviders, httpChallengeProvider)
-	managerFactory := service.NewManagerFactory(*staticConfiguration, routinesPool, metricsRegistry, roundTripperManager, acmeHTTPHandler)
+	// TLS certificates metrics
 
-	// Router factory
+	if len(metricRegistries) > 0 {
+		tlsManager.SetCertificateMetrics(traefiktls.NewCertificateMetrics(metricsRegistry.TLSCertsNotAfterTimestampGauge(), metricsRegistry.TLSCertsHandshakesCounter()))
+	}
 
-	accessLog := setupAccessLog(staticConfiguration.AccessLog)
-	chainBuilder := middleware.NewChainBuilder(*staticConfiguration, metricsRegistry, accessLog)
-	routerFactory := server.NewRouterFactory(*staticConfiguration, managerFactory, tlsManager, chainBuilder, pluginBuilder, metricsRegistry)
+	// OCSP stapling
+
+	if staticConfiguration.OCSP != nil {
+		ocspStapler := traefiktls.NewOCSPStapler(staticConfiguration.OCSP, metricsRegistry.TLSOCSPStapleAgeGauge(), metricsRegistry.TLSOCSPFailuresCounter())
+		tlsManager.SetOCSPStapler(ocspStapler)
+		routinesPool.GoCtx(ocspStapler.Run)
+	}
+
+	// TLS session tickets
+
+	if staticConfiguration.SessionTickets != nil {
+		if rotator := initSessionTicketRotator(staticConfiguration.SessionTickets, tlsManager); rotator != nil {
+			routinesPool.GoCtx(rotator.Run)
+		}
+	}
 
 	// Watcher
 
@@ -272,19 +343,27 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		routinesPool,
 		providerAggregator,
 		time.Duration(staticConfiguration.Providers.ProvidersThrottleDuration),
+		time.Duration(staticConfiguration.Providers.GlobalThrottleDuration),
 		getDefaultsEntrypoints(staticConfiguration),
 		"internal",
 	)
 
+	// Service manager factory
+
+	roundTripperManager := service.NewRoundTripperManager()
+	acmeHTTPHandler := getHTTPChallengeHandler(acmeProviders, httpChallengeProvider)
+	managerFactory := service.NewManagerFactory(*staticConfiguration, routinesPool, metricsRegistry, roundTripperManager, acmeHTTPHandler, watcher, usageRegistry, acmeProviders)
+
+	// Router factory
+
+	accessLog := setupAccessLog(staticConfiguration.AccessLog)
+	chainBuilder := middleware.NewChainBuilder(*staticConfiguration, metricsRegistry, accessLog)
+	routerFactory := server.NewRouterFactory(*staticConfiguration, managerFactory, tlsManager, chainBuilder, pluginBuilder, metricsRegistry)
+
 	// TLS
 	watcher.AddListener(func(conf dynamic.Configuration) {
 		ctx := context.Background()
 		tlsManager.UpdateConfigs(ctx, conf.TLS.Stores, conf.TLS.Options, conf.TLS.Certificates)
-
-		gauge := metricsRegistry.TLSCertsNotAfterTimestampGauge()
-		for _, certificate := range tlsManager.GetCertificates() {
-			appendCertMetric(gauge, certificate)
-		}
 	})
 
 	// Metrics
@@ -299,7 +378,7 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 	})
 
 	// Switch router
-	watcher.AddListener(switchRouter(routerFactory, serverEntryPointsTCP, serverEntryPointsUDP, aviator))
+	watcher.AddListener(switchRouter(routerFactory, server.EntryPointTimeouts(staticConfiguration.EntryPoints), serverEntryPointsTCP, serverEntryPointsUDP, aviator))
 
 	// Metrics
 	if metricsRegistry.IsEpEnabled() || metricsRegistry.IsSvcEnabled() {
@@ -322,6 +401,12 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		watcher.AddListener(p.ListenConfiguration)
 	}
 
+	// Vault
+	for _, p := range vaultProviders {
+		resolverNames[p.ResolverName] = struct{}{}
+		watcher.AddListener(p.ListenConfiguration)
+	}
+
 	// Certificate resolver logs
 	watcher.AddListener(func(config dynamic.Configuration) {
 		for rtName, rt := range config.HTTP.Routers {
@@ -333,6 +418,20 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 				log.WithoutContext().Errorf("the router %s uses a non-existent resolver: %s", rtName, rt.TLS.CertResolver)
 			}
 		}
+
+		if config.TCP == nil {
+			return
+		}
+
+		for rtName, rt := range config.TCP.Routers {
+			if rt.TLS == nil || rt.TLS.CertResolver == "" {
+				continue
+			}
+
+			if _, ok := resolverNames[rt.TLS.CertResolver]; !ok {
+				log.WithoutContext().Errorf("the TCP router %s uses a non-existent resolver: %s", rtName, rt.TLS.CertResolver)
+			}
+		}
 	})
 
 	return server.NewServer(routinesPool, serverEntryPointsTCP, serverEntryPointsUDP, watcher, chainBuilder, accessLog), nil
@@ -367,21 +466,50 @@ func getDefaultsEntrypoints(staticConfiguration *static.Configuration) []string
 	return defaultEntryPoints
 }
 
-func switchRouter(routerFactory *server.RouterFactory, serverEntryPointsTCP server.TCPEntryPoints, serverEntryPointsUDP server.UDPEntryPoints, aviator *pilot.Pilot) func(conf dynamic.Configuration) {
+func switchRouter(routerFactory *server.RouterFactory, entryPointTimeouts map[string]runtime.EntryPointTimeouts, serverEntryPointsTCP server.TCPEntryPoints, serverEntryPointsUDP server.UDPEntryPoints, aviator *pilot.Pilot) func(conf dynamic.Configuration) {
 	return func(conf dynamic.Configuration) {
-		rtConf := runtime.NewConfig(conf)
-
-		routers, udpRouters := routerFactory.CreateRouters(rtConf)
-
 		if aviator != nil {
 			aviator.SetDynamicConfiguration(conf)
 		}
 
+		if routerFactory.UpdateMiddlewares(conf) {
+			return
+		}
+
+		rtConf := runtime.NewConfig(conf)
+
+		for _, err := range rtConf.CheckTimeouts(entryPointTimeouts, conf.HTTP.ServersTransports) {
+			log.WithoutContext().Warnf("Contradicting timeouts: %v", err)
+		}
+
+		routers, udpRouters := routerFactory.CreateRouters(rtConf)
+
 		serverEntryPointsTCP.Switch(routers)
 		serverEntryPointsUDP.Switch(udpRouters)
 	}
 }
 
+// initSessionTicketRotator creates the rotator of the TLS session ticket keys, sharing them through the KV store if any.
+func initSessionTicketRotator(config *traefiktls.SessionTicketsConfig, tlsManager *traefiktls.Manager) *traefiktls.SessionTicketRotator {
+	var store traefiktls.SessionTicketKeysStore
+	if config.KVStorage != nil && len(config.Keys) == 0 {
+		kvStore, err := kv.NewSessionTicketKeysStore(context.Background(), config.KVStorage)
+		if err != nil {
+			log.WithoutContext().Errorf("Unable to share the TLS session ticket keys, they are rotated locally: %v", err)
+		} else {
+			store = kvStore
+		}
+	}
+
+	rotator, err := traefiktls.NewSessionTicketRotator(config, store, tlsManager.SetSessionTicketKeys)
+	if err != nil {
+		log.WithoutContext().Errorf("Invalid TLS session tickets configuration, the default keys are used: %v", err)
+		return nil
+	}
+
+	return rotator
+}
+
 // initACMEProvider creates an acme provider from the ACME part of globalConfiguration.
 func initACMEProvider(c *static.Configuration, providerAggregator *aggregator.ProviderAggregator, tlsManager *traefiktls.Manager, httpChallengeProvider, tlsChallengeProvider challenge.Provider) []*acme.Provider {
 	localStores := map[string]*acme.LocalStore{}
@@ -392,13 +520,28 @@ func initACMEProvider(c *static.Configuration, providerAggregator *aggregator.Pr
 			continue
 		}
 
-		if localStores[resolver.ACME.Storage] == nil {
-			localStores[resolver.ACME.Storage] = acme.NewLocalStore(resolver.ACME.Storage)
+		var store acme.Store
+		if resolver.ACME.KVStorage != nil {
+			ctx := log.With(context.Background(), log.Str(log.ProviderName, name+".acme"))
+
+			kvStore, err := acme.NewKVStore(ctx, resolver.ACME.KVStorage)
+			if err != nil {
+				log.WithoutContext().Errorf("The ACME resolver %q is skipped from the resolvers list because: %v", name, err)
+				continue
+			}
+
+			store = kvStore
+		} else {
+			if localStores[resolver.ACME.Storage] == nil {
+				localStores[resolver.ACME.Storage] = acme.NewLocalStore(resolver.ACME.Storage)
+			}
+
+			store = localStores[resolver.ACME.Storage]
 		}
 
 		p := &acme.Provider{
 			Configuration:         resolver.ACME,
-			Store:                 localStores[resolver.ACME.Storage],
+			Store:                 store,
 			ResolverName:          name,
 			HTTPChallengeProvider: httpChallengeProvider,
 			TLSChallengeProvider:  tlsChallengeProvider,
@@ -419,6 +562,32 @@ func initACMEProvider(c *static.Configuration, providerAggregator *aggregator.Pr
 	return resolvers
 }
 
+// initVaultProviders creates the Vault providers from the Vault part of the certificates resolvers.
+func initVaultProviders(c *static.Configuration, providerAggregator *aggregator.ProviderAggregator) []*vault.Provider {
+	var resolvers []*vault.Provider
+	for name, resolver := range c.CertificatesResolvers {
+		if resolver.Vault == nil {
+			continue
+		}
+
+		p := &vault.Provider{
+			Configuration: resolver.Vault,
+			ResolverName:  name,
+		}
+
+		if err := providerAggregator.AddProvider(p); err != nil {
+			log.WithoutContext().Errorf("The Vault resolver %q is skipped from the resolvers list because: %v", name, err)
+			continue
+		}
+
+		p.SetConfigListenerChan(make(chan dynamic.Configuration))
+
+		resolvers = append(resolvers, p)
+	}
+
+	return resolvers
+}
+
 func registerMetricClients(metricsConfig *types.Metrics) []metrics.Registry {
 	if metricsConfig == nil {
 		return nil
@@ -459,20 +628,6 @@ func registerMetricClients(metricsConfig *types.Metrics) []metrics.Registry {
 	return registries
 }
 
-func appendCertMetric(gauge gokitmetrics.Gauge, certificate *x509.Certificate) {
-	sort.Strings(certificate.DNSNames)
-
-	labels := []string{
-		"cn", certificate.Subject.CommonName,
-		"serial", certificate.SerialNumber.String(),
-		"sans", strings.Join(certificate.DNSNames, ","),
-	}
-
-	notAfter := float64(certificate.NotAfter.Unix())
-
-	gauge.With(labels...).Set(notAfter)
-}
-
 func setupAccessLog(conf *types.AccessLog) *accesslog.Handler {
 	if conf == nil {
 		return nil
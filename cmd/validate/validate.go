@@ -0,0 +1,105 @@
+package validate
+
+import (
+	"fmt"
+
+	"github.com/traefik/paerser/cli"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/server"
+	"github.com/traefik/traefik/v2/pkg/server/provider"
+)
+
+// NewCmd builds a new configuration validation command.
+func NewCmd(traefikConfiguration *static.Configuration, loaders []cli.ResourceLoader) *cli.Command {
+	return &cli.Command{
+		Name: "validate",
+		Description: `Validates the static configuration, and checks that the timeouts of the entry points,
+of the servers transports, of the health checks, and of the retry middlewares of the file provider configuration do not contradict each other.`,
+		Configuration: traefikConfiguration,
+		Run:           runCmd(traefikConfiguration),
+		Resources:     loaders,
+	}
+}
+
+func runCmd(traefikConfiguration *static.Configuration) func(_ []string) error {
+	return func(_ []string) error {
+		traefikConfiguration.SetEffectiveConfiguration()
+
+		if err := traefikConfiguration.ValidateConfiguration(); err != nil {
+			return err
+		}
+
+		conf, err := buildConfiguration(traefikConfiguration)
+		if err != nil {
+			return err
+		}
+
+		errs := runtime.NewConfig(conf).CheckTimeouts(server.EntryPointTimeouts(traefikConfiguration.EntryPoints), conf.HTTP.ServersTransports)
+		for _, err := range errs {
+			fmt.Println(err)
+		}
+
+		if len(errs) > 0 {
+			return fmt.Errorf("%d contradicting timeout(s) found", len(errs))
+		}
+
+		fmt.Println("OK: no contradicting timeouts found")
+		return nil
+	}
+}
+
+// buildConfiguration returns the dynamic configuration the timeouts are checked on,
+// made of the default servers transport and of the configuration of the file provider, if enabled.
+func buildConfiguration(staticConfiguration *static.Configuration) (dynamic.Configuration, error) {
+	conf := dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers:           make(map[string]*dynamic.Router),
+			Middlewares:       make(map[string]*dynamic.Middleware),
+			Services:          make(map[string]*dynamic.Service),
+			ServersTransports: make(map[string]*dynamic.ServersTransport),
+		},
+	}
+
+	if staticConfiguration.ServersTransport != nil && staticConfiguration.ServersTransport.ForwardingTimeouts != nil {
+		conf.HTTP.ServersTransports["default@internal"] = &dynamic.ServersTransport{
+			ForwardingTimeouts: &dynamic.ForwardingTimeouts{
+				DialTimeout:           staticConfiguration.ServersTransport.ForwardingTimeouts.DialTimeout,
+				ResponseHeaderTimeout: staticConfiguration.ServersTransport.ForwardingTimeouts.ResponseHeaderTimeout,
+				IdleConnTimeout:       staticConfiguration.ServersTransport.ForwardingTimeouts.IdleConnTimeout,
+			},
+		}
+	}
+
+	if staticConfiguration.Providers == nil || staticConfiguration.Providers.File == nil {
+		return conf, nil
+	}
+
+	fileConf, err := staticConfiguration.Providers.File.BuildConfiguration()
+	if err != nil {
+		return conf, fmt.Errorf("cannot load the file provider configuration: %w", err)
+	}
+
+	if fileConf == nil || fileConf.HTTP == nil {
+		return conf, nil
+	}
+
+	for name, router := range fileConf.HTTP.Routers {
+		conf.HTTP.Routers[provider.MakeQualifiedName("file", name)] = router
+	}
+
+	for name, middleware := range fileConf.HTTP.Middlewares {
+		conf.HTTP.Middlewares[provider.MakeQualifiedName("file", name)] = middleware
+	}
+
+	for name, service := range fileConf.HTTP.Services {
+		conf.HTTP.Services[provider.MakeQualifiedName("file", name)] = service
+	}
+
+	for name, serversTransport := range fileConf.HTTP.ServersTransports {
+		conf.HTTP.ServersTransports[provider.MakeQualifiedName("file", name)] = serversTransport
+	}
+
+	return conf, nil
+}
@@ -1,6 +1,7 @@
 package integration
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
@@ -14,6 +15,7 @@ import (
 	"github.com/traefik/traefik/v2/integration/try"
 	"github.com/traefik/traefik/v2/pkg/config/static"
 	"github.com/traefik/traefik/v2/pkg/provider/acme"
+	"github.com/traefik/traefik/v2/pkg/provider/acme/acmetest"
 	"github.com/traefik/traefik/v2/pkg/testhelpers"
 	"github.com/traefik/traefik/v2/pkg/types"
 	checker "github.com/vdemeester/shakers"
@@ -57,30 +59,19 @@ func (s *AcmeSuite) getAcmeURL() string {
 	return fmt.Sprintf("https://%s:14000/dir", s.pebbleIP)
 }
 
-func setupPebbleRootCA() (*http.Transport, error) {
+func (s *AcmeSuite) pebble() (acmetest.Pebble, error) {
 	path, err := filepath.Abs("fixtures/acme/ssl/pebble.minica.pem")
 	if err != nil {
-		return nil, err
+		return acmetest.Pebble{}, err
 	}
 
 	os.Setenv("LEGO_CA_CERTIFICATES", path)
 	os.Setenv("LEGO_CA_SERVER_NAME", "pebble")
 
-	customCAs, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
-
-	certPool := x509.NewCertPool()
-	if ok := certPool.AppendCertsFromPEM(customCAs); !ok {
-		return nil, fmt.Errorf("error creating x509 cert pool from %q: %w", path, err)
-	}
-
-	return &http.Transport{
-		TLSClientConfig: &tls.Config{
-			ServerName: "pebble",
-			RootCAs:    certPool,
-		},
+	return acmetest.Pebble{
+		DirectoryURL: s.getAcmeURL(),
+		RootCA:       path,
+		ServerName:   "pebble",
 	}, nil
 }
 
@@ -92,25 +83,16 @@ func (s *AcmeSuite) SetUpSuite(c *check.C) {
 
 	s.pebbleIP = s.composeProject.Container(c, "pebble").NetworkSettings.IPAddress
 
-	pebbleTransport, err := setupPebbleRootCA()
-	if err != nil {
-		c.Fatal(err)
-	}
+	pebble, err := s.pebble()
+	c.Assert(err, checker.IsNil)
 
-	// wait for pebble
-	req := testhelpers.MustNewRequest(http.MethodGet, s.getAcmeURL(), nil)
+	pebbleTransport, err := pebble.Transport()
+	c.Assert(err, checker.IsNil)
 
-	client := &http.Client{
-		Transport: pebbleTransport,
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-	err = try.Do(5*time.Second, func() error {
-		resp, errGet := client.Do(req)
-		if errGet != nil {
-			return errGet
-		}
-		return try.StatusCodeIs(http.StatusOK)(resp)
-	})
+	err = pebble.WaitReady(ctx, pebbleTransport)
 	c.Assert(err, checker.IsNil)
 }
 
@@ -12,6 +12,11 @@ import (
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/config/static"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares/auth"
+	"github.com/traefik/traefik/v2/pkg/middlewares/httpcache"
+	"github.com/traefik/traefik/v2/pkg/middlewares/maintenance"
+	"github.com/traefik/traefik/v2/pkg/provider/acme"
 	"github.com/traefik/traefik/v2/pkg/version"
 )
 
@@ -48,19 +53,49 @@ type RunTimeRepresentation struct {
 
 // Handler serves the configuration and status of Traefik on API endpoints.
 type Handler struct {
-	dashboard       bool
-	debug           bool
-	staticConfig    static.Configuration
-	dashboardAssets *assetfs.AssetFS
+	dashboard           bool
+	debug               bool
+	staticConfig        static.Configuration
+	dashboardAssets     *assetfs.AssetFS
+	providers           ProvidersController
+	authBypasses        *auth.Bypasses
+	usage               *metrics.UsageRegistry
+	httpCaches          *httpcache.Caches
+	maintenanceSwitches *maintenance.Switches
+	acmeProviders       []*acme.Provider
 
 	// runtimeConfiguration is the data set used to create all the data representations exposed by the API.
 	runtimeConfiguration *runtime.Configuration
 }
 
+// BuilderOptions holds the components backing the optional endpoints of the API.
+// The endpoints of a nil component are not available.
+type BuilderOptions struct {
+	// Providers backs the maintenance endpoints.
+	Providers ProvidersController
+	// AuthBypasses back the auth bypass endpoints.
+	AuthBypasses *auth.Bypasses
+	// Usage backs the usage endpoint.
+	Usage *metrics.UsageRegistry
+	// HTTPCaches back the cache endpoints.
+	HTTPCaches *httpcache.Caches
+	// MaintenanceSwitches back the maintenance middleware endpoints.
+	MaintenanceSwitches *maintenance.Switches
+	// ACMEProviders back the ACME resolvers endpoint.
+	ACMEProviders []*acme.Provider
+}
+
 // NewBuilder returns a http.Handler builder based on runtime.Configuration.
-func NewBuilder(staticConfig static.Configuration) func(*runtime.Configuration) http.Handler {
+func NewBuilder(staticConfig static.Configuration, opts BuilderOptions) func(*runtime.Configuration) http.Handler {
 	return func(configuration *runtime.Configuration) http.Handler {
-		return New(staticConfig, configuration).createRouter()
+		handler := New(staticConfig, configuration)
+		handler.providers = opts.Providers
+		handler.authBypasses = opts.AuthBypasses
+		handler.usage = opts.Usage
+		handler.httpCaches = opts.HTTPCaches
+		handler.maintenanceSwitches = opts.MaintenanceSwitches
+		handler.acmeProviders = opts.ACMEProviders
+		return handler.createRouter()
 	}
 }
 
@@ -116,6 +151,40 @@ func (h Handler) createRouter() *mux.Router {
 	router.Methods(http.MethodGet).Path("/api/udp/services").HandlerFunc(h.getUDPServices)
 	router.Methods(http.MethodGet).Path("/api/udp/services/{serviceID}").HandlerFunc(h.getUDPService)
 
+	router.Methods(http.MethodGet).Path("/api/openapi").HandlerFunc(h.getOpenAPIHosts)
+	router.Methods(http.MethodGet).Path("/api/openapi/{host}").HandlerFunc(h.getOpenAPISpec)
+
+	if h.staticConfig.API.Maintenance && h.providers != nil {
+		router.Methods(http.MethodGet).Path("/api/providers/paused").HandlerFunc(h.getPausedProviders)
+		router.Methods(http.MethodPut).Path("/api/providers/{providerID}/pause").HandlerFunc(h.pauseProvider)
+		router.Methods(http.MethodPut).Path("/api/providers/{providerID}/resume").HandlerFunc(h.resumeProvider)
+	}
+
+	if h.staticConfig.API.Maintenance && h.maintenanceSwitches != nil {
+		router.Methods(http.MethodGet).Path("/api/http/maintenance").HandlerFunc(h.getMaintenanceSwitches)
+		router.Methods(http.MethodPut).Path("/api/http/middlewares/{middlewareID}/maintenance").HandlerFunc(h.switchMaintenance)
+		router.Methods(http.MethodDelete).Path("/api/http/middlewares/{middlewareID}/maintenance").HandlerFunc(h.resetMaintenance)
+	}
+
+	if h.staticConfig.API.AuthBypass != nil && h.authBypasses != nil {
+		router.Methods(http.MethodGet).Path("/api/http/bypasses").HandlerFunc(h.getAuthBypasses)
+		router.Methods(http.MethodPut).Path("/api/http/routers/{routerID}/bypasses/{middlewareID}").HandlerFunc(h.addAuthBypass)
+		router.Methods(http.MethodDelete).Path("/api/http/routers/{routerID}/bypasses/{middlewareID}").HandlerFunc(h.removeAuthBypass)
+	}
+
+	if h.staticConfig.API.Usage != nil && h.usage != nil {
+		router.Methods(http.MethodGet).Path("/api/usage").HandlerFunc(h.getUsage)
+	}
+
+	if h.staticConfig.API.Caches && h.httpCaches != nil {
+		router.Methods(http.MethodGet).Path("/api/http/caches").HandlerFunc(h.getHTTPCaches)
+		router.Methods(http.MethodDelete).Path("/api/http/caches/{middlewareID}").HandlerFunc(h.purgeHTTPCache)
+	}
+
+	if len(h.acmeProviders) > 0 {
+		router.Methods(http.MethodGet).Path("/api/acme/resolvers").HandlerFunc(h.getACMEResolvers)
+	}
+
 	version.Handler{}.Append(router)
 
 	if h.dashboard {
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider/acme"
+)
+
+type acmeResolversRepresentation struct {
+	Resolvers []acme.OrdersState `json:"resolvers"`
+}
+
+func (h Handler) getACMEResolvers(rw http.ResponseWriter, request *http.Request) {
+	result := acmeResolversRepresentation{Resolvers: make([]acme.OrdersState, 0, len(h.acmeProviders))}
+	for _, provider := range h.acmeProviders {
+		result.Resolvers = append(result.Resolvers, provider.OrdersState())
+	}
+
+	sort.Slice(result.Resolvers, func(i, j int) bool {
+		return result.Resolvers[i].Resolver < result.Resolvers[j].Resolver
+	})
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(rw).Encode(result)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
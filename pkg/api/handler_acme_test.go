@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/provider/acme"
+)
+
+func TestHandler_ACMEResolvers(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		resolvers          []string
+		expectedStatusCode int
+		expected           []acme.OrdersState
+	}{
+		{
+			desc:               "resolvers",
+			resolvers:          []string{"tenant", "le"},
+			expectedStatusCode: http.StatusOK,
+			expected:           []acme.OrdersState{{Resolver: "le"}, {Resolver: "tenant"}},
+		},
+		{
+			desc:               "no ACME resolver",
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			store := acme.NewLocalStore(filepath.Join(t.TempDir(), "acme.json"))
+
+			var providers []*acme.Provider
+			for _, resolver := range test.resolvers {
+				config := &acme.Configuration{}
+				config.SetDefaults()
+
+				provider := &acme.Provider{Configuration: config, Store: store, ResolverName: resolver}
+				require.NoError(t, provider.Init())
+
+				providers = append(providers, provider)
+			}
+
+			conf := static.Configuration{API: &static.API{}, Global: &static.Global{}}
+
+			server := httptest.NewServer(NewBuilder(conf, BuilderOptions{ACMEProviders: providers})(&runtime.Configuration{}))
+			defer server.Close()
+
+			resp, err := http.DefaultClient.Get(server.URL + "/api/acme/resolvers")
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			assert.Equal(t, test.expectedStatusCode, resp.StatusCode)
+
+			if test.expectedStatusCode != http.StatusOK {
+				return
+			}
+
+			var resolversRepr acmeResolversRepresentation
+			err = json.NewDecoder(resp.Body).Decode(&resolversRepr)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, resolversRepr.Resolvers)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares/auth"
+)
+
+type authBypassesRepresentation struct {
+	Bypasses []auth.Bypass `json:"bypasses"`
+}
+
+type authBypassRequest struct {
+	Duration ptypes.Duration `json:"duration"`
+	Reason   string          `json:"reason"`
+}
+
+func (h Handler) getAuthBypasses(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(rw).Encode(authBypassesRepresentation{Bypasses: h.authBypasses.List()})
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h Handler) addAuthBypass(rw http.ResponseWriter, request *http.Request) {
+	routerID := mux.Vars(request)["routerID"]
+	middlewareID := mux.Vars(request)["middlewareID"]
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	if err := h.checkAuthBypass(routerID, middlewareID); err != nil {
+		writeError(rw, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	var bypassRequest authBypassRequest
+	if err := json.NewDecoder(request.Body).Decode(&bypassRequest); err != nil {
+		writeError(rw, fmt.Sprintf("invalid bypass request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if bypassRequest.Reason == "" {
+		writeError(rw, "the reason of the bypass is missing", http.StatusBadRequest)
+		return
+	}
+
+	bypass, err := h.authBypasses.Add(routerID, middlewareID, time.Duration(bypassRequest.Duration), bypassRequest.Reason, request.RemoteAddr)
+	if err != nil {
+		if errors.Is(err, auth.ErrBypassDuration) {
+			writeError(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(rw).Encode(bypass)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h Handler) removeAuthBypass(rw http.ResponseWriter, request *http.Request) {
+	routerID := mux.Vars(request)["routerID"]
+	middlewareID := mux.Vars(request)["middlewareID"]
+
+	err := h.authBypasses.Remove(routerID, middlewareID, request.RemoteAddr)
+	if err != nil {
+		rw.Header().Set("Content-Type", "application/json")
+
+		if errors.Is(err, auth.ErrBypassNotFound) {
+			writeError(rw, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// checkAuthBypass checks that the given router exists, and that the given middleware is an auth middleware.
+func (h Handler) checkAuthBypass(routerID, middlewareID string) error {
+	if _, ok := h.runtimeConfiguration.Routers[routerID]; !ok {
+		return fmt.Errorf("router not found: %s", routerID)
+	}
+
+	midInfo, ok := h.runtimeConfiguration.Middlewares[middlewareID]
+	if !ok || midInfo.Middleware == nil {
+		return fmt.Errorf("middleware not found: %s", middlewareID)
+	}
+
+	if midInfo.BasicAuth == nil && midInfo.DigestAuth == nil && midInfo.ForwardAuth == nil && midInfo.OIDCAuth == nil {
+		return fmt.Errorf("not an auth middleware: %s", middlewareID)
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/middlewares/auth"
+)
+
+func TestHandler_AuthBypasses(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		method             string
+		path               string
+		body               string
+		disabled           bool
+		expectedStatusCode int
+		expectedBody       string
+		expectedBypassed   bool
+	}{
+		{
+			desc:               "auth bypass disabled",
+			method:             http.MethodPut,
+			path:               "/api/http/routers/foo@file/bypasses/auth@file",
+			body:               `{"duration":"10m","reason":"idp outage"}`,
+			disabled:           true,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			desc:               "bypasses",
+			method:             http.MethodGet,
+			path:               "/api/http/bypasses",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"bypasses":[]}` + "\n",
+		},
+		{
+			desc:               "add bypass",
+			method:             http.MethodPut,
+			path:               "/api/http/routers/foo@file/bypasses/auth@file",
+			body:               `{"duration":"10m","reason":"idp outage"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedBypassed:   true,
+		},
+		{
+			desc:               "add bypass without reason",
+			method:             http.MethodPut,
+			path:               "/api/http/routers/foo@file/bypasses/auth@file",
+			body:               `{"duration":"10m"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"message":"the reason of the bypass is missing"}` + "\n",
+		},
+		{
+			desc:               "add bypass longer than the maximum duration",
+			method:             http.MethodPut,
+			path:               "/api/http/routers/foo@file/bypasses/auth@file",
+			body:               `{"duration":"2h","reason":"idp outage"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"message":"invalid bypass duration: 2h0m0s is not between 0s and 1h0m0s"}` + "\n",
+		},
+		{
+			desc:               "add bypass of an unknown router",
+			method:             http.MethodPut,
+			path:               "/api/http/routers/bar@file/bypasses/auth@file",
+			body:               `{"duration":"10m","reason":"idp outage"}`,
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"message":"router not found: bar@file"}` + "\n",
+		},
+		{
+			desc:               "add bypass of a middleware which is not an auth one",
+			method:             http.MethodPut,
+			path:               "/api/http/routers/foo@file/bypasses/prefix@file",
+			body:               `{"duration":"10m","reason":"idp outage"}`,
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"message":"not an auth middleware: prefix@file"}` + "\n",
+		},
+		{
+			desc:               "remove unknown bypass",
+			method:             http.MethodDelete,
+			path:               "/api/http/routers/foo@file/bypasses/auth@file",
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"message":"bypass not found"}` + "\n",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			conf := static.Configuration{API: &static.API{}, Global: &static.Global{}}
+			if !test.disabled {
+				conf.API.AuthBypass = &static.AuthBypass{MaxDuration: ptypes.Duration(time.Hour)}
+			}
+
+			rtConf := &runtime.Configuration{
+				Routers: map[string]*runtime.RouterInfo{
+					"foo@file": {Router: &dynamic.Router{Middlewares: []string{"auth@file"}}},
+				},
+				Middlewares: map[string]*runtime.MiddlewareInfo{
+					"auth@file":   {Middleware: &dynamic.Middleware{BasicAuth: &dynamic.BasicAuth{}}},
+					"prefix@file": {Middleware: &dynamic.Middleware{AddPrefix: &dynamic.AddPrefix{}}},
+				},
+			}
+
+			bypasses := auth.NewBypasses(time.Hour)
+
+			server := httptest.NewServer(NewBuilder(conf, BuilderOptions{AuthBypasses: bypasses})(rtConf))
+			defer server.Close()
+
+			req, err := http.NewRequest(test.method, server.URL+test.path, strings.NewReader(test.body))
+			require.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			assert.Equal(t, test.expectedStatusCode, resp.StatusCode)
+			assert.Equal(t, test.expectedBypassed, bypasses.Active("foo@file", "auth@file"))
+
+			if test.expectedBody == "" {
+				return
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedBody, string(body))
+		})
+	}
+}
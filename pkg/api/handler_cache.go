@@ -0,0 +1,52 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares/httpcache"
+)
+
+type httpCachesRepresentation struct {
+	Caches []httpcache.CacheInfo `json:"caches"`
+}
+
+type httpCachePurgeRepresentation struct {
+	Purged int `json:"purged"`
+}
+
+func (h Handler) getHTTPCaches(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(rw).Encode(httpCachesRepresentation{Caches: h.httpCaches.List()})
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h Handler) purgeHTTPCache(rw http.ResponseWriter, request *http.Request) {
+	middlewareID := mux.Vars(request)["middlewareID"]
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	purged, err := h.httpCaches.Purge(middlewareID, request.URL.Query().Get("prefix"))
+	if err != nil {
+		if errors.Is(err, httpcache.ErrCacheNotFound) {
+			writeError(rw, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(rw).Encode(httpCachePurgeRepresentation{Purged: purged})
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
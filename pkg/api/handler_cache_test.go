@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/middlewares/httpcache"
+)
+
+func TestHandler_HTTPCaches(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		method             string
+		path               string
+		disabled           bool
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			desc:               "caches endpoints disabled",
+			method:             http.MethodGet,
+			path:               "/api/http/caches",
+			disabled:           true,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			desc:               "caches",
+			method:             http.MethodGet,
+			path:               "/api/http/caches",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"caches":[{"middleware":"cache@file","entries":2,"size":164,"maxSize":1024,"hits":0,"misses":2}]}` + "\n",
+		},
+		{
+			desc:               "purge cache",
+			method:             http.MethodDelete,
+			path:               "/api/http/caches/cache@file",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"purged":2}` + "\n",
+		},
+		{
+			desc:               "purge cache by prefix",
+			method:             http.MethodDelete,
+			path:               "/api/http/caches/cache@file?prefix=/foo",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"purged":1}` + "\n",
+		},
+		{
+			desc:               "purge unknown cache",
+			method:             http.MethodDelete,
+			path:               "/api/http/caches/unknown@file",
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"message":"cache not found"}` + "\n",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			conf := static.Configuration{API: &static.API{Caches: !test.disabled}, Global: &static.Global{}}
+
+			caches := httpcache.NewCaches(nil)
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Cache-Control", "max-age=60")
+				_, _ = rw.Write([]byte("ok"))
+			})
+
+			handler, err := httpcache.New(context.Background(), next, dynamic.HTTPCache{MaxSize: 1024}, "cache@file", caches)
+			require.NoError(t, err)
+
+			for _, path := range []string{"/foo", "/bar"} {
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+			}
+
+			server := httptest.NewServer(NewBuilder(conf, BuilderOptions{HTTPCaches: caches})(&runtime.Configuration{}))
+			defer server.Close()
+
+			req, err := http.NewRequest(test.method, server.URL+test.path, nil)
+			require.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			assert.Equal(t, test.expectedStatusCode, resp.StatusCode)
+
+			if test.expectedBody == "" {
+				return
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedBody, string(body))
+		})
+	}
+}
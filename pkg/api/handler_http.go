@@ -15,15 +15,17 @@ import (
 
 type routerRepresentation struct {
 	*runtime.RouterInfo
-	Name     string `json:"name,omitempty"`
-	Provider string `json:"provider,omitempty"`
+	GRPCMismatches []string `json:"grpcMismatches,omitempty"`
+	Name           string   `json:"name,omitempty"`
+	Provider       string   `json:"provider,omitempty"`
 }
 
 func newRouterRepresentation(name string, rt *runtime.RouterInfo) routerRepresentation {
 	return routerRepresentation{
-		RouterInfo: rt,
-		Name:       name,
-		Provider:   getProviderName(name),
+		RouterInfo:     rt,
+		GRPCMismatches: rt.GetGRPCMismatches(),
+		Name:           name,
+		Provider:       getProviderName(name),
 	}
 }
 
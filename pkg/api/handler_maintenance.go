@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares/maintenance"
+)
+
+type maintenanceSwitchesRepresentation struct {
+	Switches []maintenance.Switch `json:"switches"`
+}
+
+type maintenanceSwitchRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+func (h Handler) getMaintenanceSwitches(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(rw).Encode(maintenanceSwitchesRepresentation{Switches: h.maintenanceSwitches.List()})
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h Handler) switchMaintenance(rw http.ResponseWriter, request *http.Request) {
+	middlewareID := mux.Vars(request)["middlewareID"]
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	if err := h.checkMaintenance(middlewareID); err != nil {
+		writeError(rw, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	var switchRequest maintenanceSwitchRequest
+	if err := json.NewDecoder(request.Body).Decode(&switchRequest); err != nil {
+		writeError(rw, fmt.Sprintf("invalid maintenance request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if switchRequest.Enabled == nil {
+		writeError(rw, "the maintenance state is missing", http.StatusBadRequest)
+		return
+	}
+
+	sw := h.maintenanceSwitches.Set(middlewareID, *switchRequest.Enabled, request.RemoteAddr)
+
+	err := json.NewEncoder(rw).Encode(sw)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h Handler) resetMaintenance(rw http.ResponseWriter, request *http.Request) {
+	middlewareID := mux.Vars(request)["middlewareID"]
+
+	err := h.maintenanceSwitches.Reset(middlewareID, request.RemoteAddr)
+	if err != nil {
+		rw.Header().Set("Content-Type", "application/json")
+
+		if errors.Is(err, maintenance.ErrSwitchNotFound) {
+			writeError(rw, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// checkMaintenance checks that the given middleware exists, and that it is a maintenance middleware.
+func (h Handler) checkMaintenance(middlewareID string) error {
+	midInfo, ok := h.runtimeConfiguration.Middlewares[middlewareID]
+	if !ok || midInfo.Middleware == nil {
+		return fmt.Errorf("middleware not found: %s", middlewareID)
+	}
+
+	if midInfo.Maintenance == nil {
+		return fmt.Errorf("not a maintenance middleware: %s", middlewareID)
+	}
+
+	return nil
+}
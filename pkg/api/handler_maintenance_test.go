@@ -0,0 +1,142 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/middlewares/maintenance"
+)
+
+func TestHandler_Maintenance(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		method             string
+		path               string
+		body               string
+		disabled           bool
+		switched           bool
+		expectedStatusCode int
+		expectedBody       string
+		expectedSwitches   int
+	}{
+		{
+			desc:               "maintenance endpoints disabled",
+			method:             http.MethodPut,
+			path:               "/api/http/middlewares/maintenance@file/maintenance",
+			body:               `{"enabled":true}`,
+			disabled:           true,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			desc:               "switches",
+			method:             http.MethodGet,
+			path:               "/api/http/maintenance",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"switches":[]}` + "\n",
+		},
+		{
+			desc:               "enable maintenance",
+			method:             http.MethodPut,
+			path:               "/api/http/middlewares/maintenance@file/maintenance",
+			body:               `{"enabled":true}`,
+			expectedStatusCode: http.StatusOK,
+			expectedSwitches:   1,
+		},
+		{
+			desc:               "disable maintenance",
+			method:             http.MethodPut,
+			path:               "/api/http/middlewares/maintenance@file/maintenance",
+			body:               `{"enabled":false}`,
+			expectedStatusCode: http.StatusOK,
+			expectedSwitches:   1,
+		},
+		{
+			desc:               "switch maintenance without state",
+			method:             http.MethodPut,
+			path:               "/api/http/middlewares/maintenance@file/maintenance",
+			body:               `{}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"message":"the maintenance state is missing"}` + "\n",
+		},
+		{
+			desc:               "switch maintenance of an unknown middleware",
+			method:             http.MethodPut,
+			path:               "/api/http/middlewares/unknown@file/maintenance",
+			body:               `{"enabled":true}`,
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"message":"middleware not found: unknown@file"}` + "\n",
+		},
+		{
+			desc:               "switch maintenance of a middleware which is not a maintenance one",
+			method:             http.MethodPut,
+			path:               "/api/http/middlewares/prefix@file/maintenance",
+			body:               `{"enabled":true}`,
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"message":"not a maintenance middleware: prefix@file"}` + "\n",
+		},
+		{
+			desc:               "reset maintenance",
+			method:             http.MethodDelete,
+			path:               "/api/http/middlewares/maintenance@file/maintenance",
+			switched:           true,
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			desc:               "reset maintenance which has not been switched",
+			method:             http.MethodDelete,
+			path:               "/api/http/middlewares/maintenance@file/maintenance",
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"message":"maintenance switch not found"}` + "\n",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			conf := static.Configuration{API: &static.API{Maintenance: !test.disabled}, Global: &static.Global{}}
+
+			rtConf := &runtime.Configuration{
+				Middlewares: map[string]*runtime.MiddlewareInfo{
+					"maintenance@file": {Middleware: &dynamic.Middleware{Maintenance: &dynamic.Maintenance{}}},
+					"prefix@file":      {Middleware: &dynamic.Middleware{AddPrefix: &dynamic.AddPrefix{}}},
+				},
+			}
+
+			switches := maintenance.NewSwitches()
+			if test.switched {
+				switches.Set("maintenance@file", true, "127.0.0.1")
+			}
+
+			server := httptest.NewServer(NewBuilder(conf, BuilderOptions{MaintenanceSwitches: switches})(rtConf))
+			defer server.Close()
+
+			req, err := http.NewRequest(test.method, server.URL+test.path, strings.NewReader(test.body))
+			require.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			assert.Equal(t, test.expectedStatusCode, resp.StatusCode)
+			assert.Len(t, switches.List(), test.expectedSwitches)
+
+			if test.expectedBody == "" {
+				return
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedBody, string(body))
+		})
+	}
+}
@@ -0,0 +1,412 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/rules"
+)
+
+// openAPISpecTTL is the duration during which a fetched OpenAPI spec is reused.
+const openAPISpecTTL = time.Minute
+
+var (
+	openAPIClient = &http.Client{Timeout: 5 * time.Second}
+
+	// openAPISpecs holds the fetched OpenAPI specs, shared by the handlers, as a handler is built on every configuration update.
+	openAPISpecs = &openAPISpecCache{entries: make(map[string]openAPISpecEntry)}
+)
+
+// openAPISource is the OpenAPI spec of a service, as exposed by a router.
+type openAPISource struct {
+	specURL string
+	scheme  string
+	// pathPrefix is the first PathPrefix of the router rule, if any.
+	pathPrefix string
+	// rewrites are the inverse of the path modifications made by the middlewares of the router, in reverse order.
+	rewrites []pathRewrite
+}
+
+// pathRewrite is the inverse of a middleware modifying the path, from the path of the service to the public path.
+type pathRewrite struct {
+	// addPrefix is the prefix stripped by a stripPrefix middleware.
+	addPrefix string
+	// trimPrefix is the prefix added by an addPrefix middleware.
+	trimPrefix string
+}
+
+// publicPath returns the path exposed by the router for the given path of the service,
+// and false if the path is not reachable through the router.
+func (s openAPISource) publicPath(path string) (string, bool) {
+	for _, rewrite := range s.rewrites {
+		if rewrite.trimPrefix != "" {
+			if !strings.HasPrefix(path, rewrite.trimPrefix) {
+				return "", false
+			}
+
+			path = strings.TrimPrefix(path, rewrite.trimPrefix)
+			if !strings.HasPrefix(path, "/") {
+				path = "/" + path
+			}
+		}
+
+		if rewrite.addPrefix != "" {
+			path = strings.TrimSuffix(rewrite.addPrefix, "/") + path
+		}
+	}
+
+	if !strings.HasPrefix(path, s.pathPrefix) {
+		return "", false
+	}
+
+	return path, true
+}
+
+func (h Handler) getOpenAPIHosts(rw http.ResponseWriter, request *http.Request) {
+	sources := h.openAPISources(request.Context())
+
+	hosts := make([]string, 0, len(sources))
+	for host := range sources {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(rw).Encode(hosts)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h Handler) getOpenAPISpec(rw http.ResponseWriter, request *http.Request) {
+	host := strings.ToLower(mux.Vars(request)["host"])
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	sources, ok := h.openAPISources(request.Context())[host]
+	if !ok {
+		writeError(rw, fmt.Sprintf("OpenAPI spec not found for host: %s", host), http.StatusNotFound)
+		return
+	}
+
+	var servers []interface{}
+	schemes := make(map[string]bool)
+	for _, source := range sources {
+		if !schemes[source.scheme] {
+			schemes[source.scheme] = true
+			servers = append(servers, map[string]interface{}{"url": source.scheme + "://" + host})
+		}
+	}
+
+	spec := map[string]interface{}{
+		"info":    map[string]interface{}{"title": host},
+		"servers": servers,
+	}
+	paths := make(map[string]interface{})
+	components := make(map[string]map[string]interface{})
+
+	// The specs are fetched concurrently.
+	sourceSpecs := make([]map[string]interface{}, len(sources))
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		wg.Add(1)
+		go func(i int, specURL string) {
+			defer wg.Done()
+
+			sourceSpec, err := openAPISpecs.get(request.Context(), specURL)
+			if err != nil {
+				log.FromContext(request.Context()).Errorf("Unable to fetch OpenAPI spec %s: %v", specURL, err)
+				return
+			}
+
+			sourceSpecs[i] = sourceSpec
+		}(i, source.specURL)
+	}
+	wg.Wait()
+
+	for i, source := range sources {
+		sourceSpec := sourceSpecs[i]
+		if sourceSpec == nil {
+			continue
+		}
+
+		if _, ok := spec["openapi"]; !ok {
+			spec["openapi"] = sourceSpec["openapi"]
+		}
+
+		sourcePaths, _ := sourceSpec["paths"].(map[string]interface{})
+		for path, item := range sourcePaths {
+			publicPath, ok := source.publicPath(path)
+			if !ok {
+				continue
+			}
+
+			if _, exists := paths[publicPath]; exists {
+				continue
+			}
+
+			// When the routers do not use the same scheme, each path is served by the server of its router.
+			if itemFields, ok := item.(map[string]interface{}); ok && len(schemes) > 1 {
+				pathItem := make(map[string]interface{}, len(itemFields)+1)
+				for key, value := range itemFields {
+					pathItem[key] = value
+				}
+				pathItem["servers"] = []interface{}{map[string]interface{}{"url": source.scheme + "://" + host}}
+				item = pathItem
+			}
+
+			paths[publicPath] = item
+		}
+
+		sourceComponents, _ := sourceSpec["components"].(map[string]interface{})
+		for kind, values := range sourceComponents {
+			named, ok := values.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			if components[kind] == nil {
+				components[kind] = make(map[string]interface{})
+			}
+
+			for name, value := range named {
+				if _, exists := components[kind][name]; !exists {
+					components[kind][name] = value
+				}
+			}
+		}
+	}
+
+	spec["paths"] = paths
+	if len(components) > 0 {
+		spec["components"] = components
+	}
+
+	err := json.NewEncoder(rw).Encode(spec)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// openAPISources returns, for each host, the OpenAPI specs of the services exposed on this host.
+func (h Handler) openAPISources(ctx context.Context) map[string][]openAPISource {
+	routerNames := make([]string, 0, len(h.runtimeConfiguration.Routers))
+	for name := range h.runtimeConfiguration.Routers {
+		routerNames = append(routerNames, name)
+	}
+	sort.Strings(routerNames)
+
+	sources := make(map[string][]openAPISource)
+	for _, routerName := range routerNames {
+		router := h.runtimeConfiguration.Routers[routerName]
+		if router.Status == runtime.StatusDisabled {
+			continue
+		}
+
+		serviceName := router.Service
+		if !strings.Contains(serviceName, "@") {
+			serviceName += "@" + getProviderName(routerName)
+		}
+
+		service, ok := h.runtimeConfiguration.Services[serviceName]
+		if !ok || service.LoadBalancer == nil || service.LoadBalancer.OpenAPI == nil || service.LoadBalancer.OpenAPI.URL == "" {
+			continue
+		}
+
+		specURL, err := resolveOpenAPIURL(service.LoadBalancer.OpenAPI.URL, service.LoadBalancer.Servers)
+		if err != nil {
+			log.FromContext(ctx).Debugf("Invalid OpenAPI spec URL for service %s: %v", serviceName, err)
+			continue
+		}
+
+		domains, err := rules.ParseDomains(router.Rule)
+		if err != nil {
+			continue
+		}
+
+		var pathPrefix string
+		if prefixes, err := rules.ParsePathPrefixes(router.Rule); err == nil && len(prefixes) > 0 {
+			pathPrefix = prefixes[0]
+		}
+
+		rewrites, err := h.pathRewrites(routerName, router.Middlewares, pathPrefix)
+		if err != nil {
+			log.FromContext(ctx).Debugf("OpenAPI spec of service %s ignored for router %s: %v", serviceName, routerName, err)
+			continue
+		}
+
+		// The TLS option of the entry points is applied to the routers, so the router TLS config determines the scheme.
+		scheme := "http"
+		if router.TLS != nil {
+			scheme = "https"
+		}
+
+		for _, domain := range domains {
+			sources[domain] = append(sources[domain], openAPISource{
+				specURL:    specURL,
+				scheme:     scheme,
+				pathPrefix: pathPrefix,
+				rewrites:   rewrites,
+			})
+		}
+	}
+
+	return sources
+}
+
+// pathRewrites returns the inverse of the path modifications made by the given middlewares of the router or chain, in reverse order.
+// It returns an error if a middleware modifies the path in a way which cannot be inverted.
+func (h Handler) pathRewrites(parentName string, middlewares []string, pathPrefix string) ([]pathRewrite, error) {
+	var rewrites []pathRewrite
+	for _, middlewareName := range middlewares {
+		if !strings.Contains(middlewareName, "@") {
+			middlewareName += "@" + getProviderName(parentName)
+		}
+
+		middleware, ok := h.runtimeConfiguration.Middlewares[middlewareName]
+		if !ok || middleware.Middleware == nil {
+			return nil, fmt.Errorf("middleware %s not found", middlewareName)
+		}
+
+		var rewrite *pathRewrite
+		switch {
+		case middleware.Chain != nil:
+			chainRewrites, err := h.pathRewrites(middlewareName, middleware.Chain.Middlewares, pathPrefix)
+			if err != nil {
+				return nil, err
+			}
+
+			rewrites = append(chainRewrites, rewrites...)
+
+		case middleware.StripPrefix != nil && len(middleware.StripPrefix.Prefixes) > 0:
+			// The stripped prefix is the one matched by the router, if any.
+			prefix := middleware.StripPrefix.Prefixes[0]
+			for _, p := range middleware.StripPrefix.Prefixes {
+				if strings.HasPrefix(pathPrefix, p) {
+					prefix = p
+					break
+				}
+			}
+
+			rewrite = &pathRewrite{addPrefix: prefix}
+
+		case middleware.AddPrefix != nil && middleware.AddPrefix.Prefix != "":
+			rewrite = &pathRewrite{trimPrefix: middleware.AddPrefix.Prefix}
+
+		case middleware.StripPrefixRegex != nil, middleware.ReplacePath != nil, middleware.ReplacePathRegex != nil:
+			return nil, fmt.Errorf("the path modified by the middleware %s cannot be mapped", middlewareName)
+		}
+
+		if rewrite != nil {
+			rewrites = append([]pathRewrite{*rewrite}, rewrites...)
+		}
+	}
+
+	return rewrites, nil
+}
+
+// resolveOpenAPIURL resolves the spec URL, relatively to the first server when it is a path.
+func resolveOpenAPIURL(rawURL string, servers []dynamic.Server) (string, error) {
+	specURL, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	if specURL.IsAbs() {
+		return specURL.String(), nil
+	}
+
+	if len(servers) == 0 {
+		return "", fmt.Errorf("no server to resolve the path %s", rawURL)
+	}
+
+	serverURL, err := url.Parse(servers[0].URL)
+	if err != nil {
+		return "", err
+	}
+
+	return serverURL.ResolveReference(specURL).String(), nil
+}
+
+// openAPISpecCache caches the fetched OpenAPI specs, keyed by URL.
+// The specs which cannot be fetched are not cached.
+type openAPISpecCache struct {
+	mu      sync.Mutex
+	entries map[string]openAPISpecEntry
+}
+
+type openAPISpecEntry struct {
+	spec   map[string]interface{}
+	expiry time.Time
+}
+
+// get returns the OpenAPI spec at the given URL, which is fetched unless it has been fetched recently.
+// The returned spec must not be modified, as it is shared.
+func (c *openAPISpecCache) get(ctx context.Context, specURL string) (map[string]interface{}, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[specURL]
+	c.mu.Unlock()
+
+	if ok && now.Before(entry.expiry) {
+		return entry.spec, nil
+	}
+
+	spec, err := fetchOpenAPISpec(ctx, specURL)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The expired specs are dropped, so that the specs of the removed services are not kept.
+	for u, e := range c.entries {
+		if !now.Before(e.expiry) {
+			delete(c.entries, u)
+		}
+	}
+
+	c.entries[specURL] = openAPISpecEntry{spec: spec, expiry: now.Add(openAPISpecTTL)}
+
+	return spec, nil
+}
+
+func fetchOpenAPISpec(ctx context.Context, specURL string) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, specURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := openAPIClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var spec map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&spec); err != nil {
+		return nil, err
+	}
+
+	return spec, nil
+}
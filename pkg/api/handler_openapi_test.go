@@ -0,0 +1,229 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+func TestHandler_OpenAPI(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/openapi.json":
+			_, _ = rw.Write([]byte(`{
+				"openapi": "3.0.0",
+				"servers": [{"url": "http://internal:8080"}],
+				"paths": {"/users": {"get": {}}, "/items": {"get": {}}},
+				"components": {"schemas": {"User": {"type": "object"}}}
+			}`))
+		case "/admin.json":
+			_, _ = rw.Write([]byte(`{
+				"openapi": "3.0.0",
+				"paths": {"/v1/admin/stats": {"get": {}}, "/v1/other": {"get": {}}, "/stats": {"get": {}}}
+			}`))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(backend.Close)
+
+	rtConf := &runtime.Configuration{
+		Routers: map[string]*runtime.RouterInfo{
+			"api@myprovider": {
+				Router: &dynamic.Router{
+					Rule:        "Host(`foo.bar`) && PathPrefix(`/api`)",
+					Service:     "api",
+					Middlewares: []string{"strip"},
+					TLS:         &dynamic.RouterTLSConfig{},
+				},
+				Status: runtime.StatusEnabled,
+			},
+			"admin@myprovider": {
+				Router: &dynamic.Router{
+					Rule:        "Host(`foo.bar`) && PathPrefix(`/admin`)",
+					Service:     "admin",
+					Middlewares: []string{"chain"},
+				},
+				Status: runtime.StatusEnabled,
+			},
+			"replaced@myprovider": {
+				Router: &dynamic.Router{
+					Rule:        "Host(`foo.bar`) && PathPrefix(`/replaced`)",
+					Service:     "admin",
+					Middlewares: []string{"replace"},
+				},
+				Status: runtime.StatusEnabled,
+			},
+			"web@myprovider": {
+				Router: &dynamic.Router{
+					Rule:    "Host(`foo.bar`)",
+					Service: "web@otherprovider",
+				},
+				Status: runtime.StatusEnabled,
+			},
+			"missing@myprovider": {
+				Router: &dynamic.Router{
+					Rule:    "Host(`bar.foo`)",
+					Service: "missing",
+				},
+				Status: runtime.StatusEnabled,
+			},
+		},
+		Middlewares: map[string]*runtime.MiddlewareInfo{
+			"strip@myprovider": {
+				Middleware: &dynamic.Middleware{StripPrefix: &dynamic.StripPrefix{Prefixes: []string{"/foo", "/api"}}},
+			},
+			"chain@myprovider": {
+				Middleware: &dynamic.Middleware{Chain: &dynamic.Chain{Middlewares: []string{"add", "headers"}}},
+			},
+			"add@myprovider": {
+				Middleware: &dynamic.Middleware{AddPrefix: &dynamic.AddPrefix{Prefix: "/v1"}},
+			},
+			"headers@myprovider": {
+				Middleware: &dynamic.Middleware{Headers: &dynamic.Headers{}},
+			},
+			"replace@myprovider": {
+				Middleware: &dynamic.Middleware{ReplacePath: &dynamic.ReplacePath{Path: "/stats"}},
+			},
+		},
+		Services: map[string]*runtime.ServiceInfo{
+			"api@myprovider": {
+				Service: &dynamic.Service{
+					LoadBalancer: &dynamic.ServersLoadBalancer{
+						Servers: []dynamic.Server{{URL: backend.URL}},
+						OpenAPI: &dynamic.OpenAPI{URL: "/openapi.json"},
+					},
+				},
+			},
+			"admin@myprovider": {
+				Service: &dynamic.Service{
+					LoadBalancer: &dynamic.ServersLoadBalancer{
+						Servers: []dynamic.Server{{URL: backend.URL}},
+						OpenAPI: &dynamic.OpenAPI{URL: "/admin.json"},
+					},
+				},
+			},
+			"web@otherprovider": {
+				Service: &dynamic.Service{
+					LoadBalancer: &dynamic.ServersLoadBalancer{
+						Servers: []dynamic.Server{{URL: "http://127.0.0.1"}},
+					},
+				},
+			},
+			"missing@myprovider": {
+				Service: &dynamic.Service{
+					LoadBalancer: &dynamic.ServersLoadBalancer{
+						OpenAPI: &dynamic.OpenAPI{URL: "/openapi.json"},
+					},
+				},
+			},
+		},
+	}
+
+	handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, rtConf)
+	server := httptest.NewServer(handler.createRouter())
+	t.Cleanup(server.Close)
+
+	resp, err := http.Get(server.URL + "/api/openapi")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var hosts []string
+	err = json.NewDecoder(resp.Body).Decode(&hosts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"foo.bar"}, hosts)
+
+	resp, err = http.Get(server.URL + "/api/openapi/foo.bar")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var spec map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&spec)
+	require.NoError(t, err)
+
+	// The paths are mapped through the stripPrefix and addPrefix middlewares of the routers,
+	// and each path is served with the scheme of its router.
+	expected := map[string]interface{}{
+		"openapi": "3.0.0",
+		"info":    map[string]interface{}{"title": "foo.bar"},
+		"servers": []interface{}{
+			map[string]interface{}{"url": "http://foo.bar"},
+			map[string]interface{}{"url": "https://foo.bar"},
+		},
+		"paths": map[string]interface{}{
+			"/admin/stats": map[string]interface{}{
+				"get":     map[string]interface{}{},
+				"servers": []interface{}{map[string]interface{}{"url": "http://foo.bar"}},
+			},
+			"/api/users": map[string]interface{}{
+				"get":     map[string]interface{}{},
+				"servers": []interface{}{map[string]interface{}{"url": "https://foo.bar"}},
+			},
+			"/api/items": map[string]interface{}{
+				"get":     map[string]interface{}{},
+				"servers": []interface{}{map[string]interface{}{"url": "https://foo.bar"}},
+			},
+		},
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{"User": map[string]interface{}{"type": "object"}},
+		},
+	}
+	assert.Equal(t, expected, spec)
+
+	resp, err = http.Get(server.URL + "/api/openapi/bar.foo")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestOpenAPISpecCache(t *testing.T) {
+	var fetches int32
+	backend := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+
+		if req.URL.Path == "/missing" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = rw.Write([]byte(`{"openapi": "3.0.0"}`))
+	}))
+	t.Cleanup(backend.Close)
+
+	cache := &openAPISpecCache{entries: make(map[string]openAPISpecEntry)}
+
+	spec, err := cache.get(context.Background(), backend.URL)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"openapi": "3.0.0"}, spec)
+
+	// The spec is reused until it expires.
+	_, err = cache.get(context.Background(), backend.URL)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+
+	cache.entries[backend.URL] = openAPISpecEntry{spec: spec, expiry: time.Now()}
+
+	_, err = cache.get(context.Background(), backend.URL)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+
+	// The specs which cannot be fetched are not cached.
+	_, err = cache.get(context.Background(), backend.URL+"/missing")
+	assert.Error(t, err)
+	assert.Len(t, cache.entries, 1)
+}
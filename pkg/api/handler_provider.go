@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+var (
+	// ErrProviderNotFound is returned when pausing a provider which has not provided any configuration.
+	ErrProviderNotFound = errors.New("provider not found")
+	// ErrProviderNotPaused is returned when resuming a provider which is not paused.
+	ErrProviderNotPaused = errors.New("provider not paused")
+)
+
+// ProvidersController pauses and resumes the providers.
+// While a provider is paused, its last applied configuration is kept,
+// and the configurations it provides are held until it is resumed.
+type ProvidersController interface {
+	PauseProvider(providerName string) error
+	ResumeProvider(providerName string) error
+	PausedProviders() []string
+}
+
+type pausedProvidersRepresentation struct {
+	Providers []string `json:"providers"`
+}
+
+func (h Handler) getPausedProviders(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(rw).Encode(pausedProvidersRepresentation{Providers: h.providers.PausedProviders()})
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h Handler) pauseProvider(rw http.ResponseWriter, request *http.Request) {
+	providerID := mux.Vars(request)["providerID"]
+
+	err := h.providers.PauseProvider(providerID)
+	if err != nil {
+		writeProviderError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (h Handler) resumeProvider(rw http.ResponseWriter, request *http.Request) {
+	providerID := mux.Vars(request)["providerID"]
+
+	err := h.providers.ResumeProvider(providerID)
+	if err != nil {
+		writeProviderError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func writeProviderError(rw http.ResponseWriter, err error) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	switch {
+	case errors.Is(err, ErrProviderNotFound):
+		writeError(rw, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrProviderNotPaused):
+		writeError(rw, err.Error(), http.StatusConflict)
+	default:
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+type providersControllerMock struct {
+	providers map[string]bool
+}
+
+func (p *providersControllerMock) PauseProvider(providerName string) error {
+	if _, ok := p.providers[providerName]; !ok {
+		return fmt.Errorf("%w: %s", ErrProviderNotFound, providerName)
+	}
+
+	p.providers[providerName] = true
+	return nil
+}
+
+func (p *providersControllerMock) ResumeProvider(providerName string) error {
+	if !p.providers[providerName] {
+		return fmt.Errorf("%w: %s", ErrProviderNotPaused, providerName)
+	}
+
+	p.providers[providerName] = false
+	return nil
+}
+
+func (p *providersControllerMock) PausedProviders() []string {
+	var names []string
+	for name, paused := range p.providers {
+		if paused {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func TestHandler_Providers(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		method             string
+		path               string
+		maintenance        bool
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			desc:               "maintenance disabled",
+			method:             http.MethodPut,
+			path:               "/api/providers/docker/pause",
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			desc:               "paused providers",
+			method:             http.MethodGet,
+			path:               "/api/providers/paused",
+			maintenance:        true,
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"providers":["file"]}` + "\n",
+		},
+		{
+			desc:               "pause provider",
+			method:             http.MethodPut,
+			path:               "/api/providers/docker/pause",
+			maintenance:        true,
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			desc:               "pause unknown provider",
+			method:             http.MethodPut,
+			path:               "/api/providers/unknown/pause",
+			maintenance:        true,
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"message":"provider not found: unknown"}` + "\n",
+		},
+		{
+			desc:               "resume provider",
+			method:             http.MethodPut,
+			path:               "/api/providers/file/resume",
+			maintenance:        true,
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			desc:               "resume provider not paused",
+			method:             http.MethodPut,
+			path:               "/api/providers/docker/resume",
+			maintenance:        true,
+			expectedStatusCode: http.StatusConflict,
+			expectedBody:       `{"message":"provider not paused: docker"}` + "\n",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			providers := &providersControllerMock{providers: map[string]bool{"docker": false, "file": true}}
+			conf := static.Configuration{API: &static.API{Maintenance: test.maintenance}, Global: &static.Global{}}
+
+			server := httptest.NewServer(NewBuilder(conf, BuilderOptions{Providers: providers})(&runtime.Configuration{}))
+			defer server.Close()
+
+			req, err := http.NewRequest(test.method, server.URL+test.path, nil)
+			require.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			assert.Equal(t, test.expectedStatusCode, resp.StatusCode)
+
+			if test.expectedBody == "" {
+				return
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedBody, string(body))
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+)
+
+type usageRepresentation struct {
+	Current  metrics.UsageReport  `json:"current"`
+	Previous *metrics.UsageReport `json:"previous,omitempty"`
+}
+
+func (h Handler) getUsage(rw http.ResponseWriter, request *http.Request) {
+	current, previous := h.usage.Reports()
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(rw).Encode(usageRepresentation{Current: current, Previous: previous})
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+)
+
+func TestHandler_Usage(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		disabled           bool
+		expectedStatusCode int
+	}{
+		{
+			desc:               "usage report",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:               "usage endpoint disabled",
+			disabled:           true,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			conf := static.Configuration{API: &static.API{}, Global: &static.Global{}}
+			if !test.disabled {
+				conf.API.Usage = &static.Usage{Period: ptypes.Duration(time.Hour)}
+			}
+
+			usage := metrics.RegisterUsage(time.Hour)
+			usage.RouterReqsCounter().With("router", "foo@file", "service", "bar@file").Add(1)
+			usage.RouterReqsBytesCounter().With("router", "foo@file", "service", "bar@file").Add(100)
+			usage.RouterRespsBytesCounter().With("router", "foo@file", "service", "bar@file").Add(1000)
+
+			server := httptest.NewServer(NewBuilder(conf, BuilderOptions{Usage: usage})(&runtime.Configuration{}))
+			defer server.Close()
+
+			resp, err := http.DefaultClient.Get(server.URL + "/api/usage")
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			assert.Equal(t, test.expectedStatusCode, resp.StatusCode)
+
+			if test.disabled {
+				return
+			}
+
+			var usageRepr usageRepresentation
+			err = json.NewDecoder(resp.Body).Decode(&usageRepr)
+			require.NoError(t, err)
+
+			assert.Equal(t, time.Hour, usageRepr.Current.End.Sub(usageRepr.Current.Start))
+			assert.Equal(t, map[string]metrics.RouterUsage{
+				"foo@file": {Requests: 1, RequestBytes: 100, ResponseBytes: 1000},
+			}, usageRepr.Current.Routers)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 	"os"
+	"reflect"
 	"strings"
 
 	"github.com/traefik/paerser/cli"
@@ -10,6 +11,9 @@ import (
 	"github.com/traefik/traefik/v2/pkg/log"
 )
 
+// fileSuffix is the suffix of the environment variables referencing a file which holds the value of an option.
+const fileSuffix = "_FILE"
+
 // EnvLoader loads a configuration from all the environment variables prefixed with "TRAEFIK_".
 type EnvLoader struct{}
 
@@ -20,8 +24,13 @@ func (e *EnvLoader) Load(_ []string, cmd *cli.Command) (bool, error) {
 		return false, nil
 	}
 
+	vars, err := resolveFileEnvVars(vars, cmd.Configuration)
+	if err != nil {
+		return false, fmt.Errorf("failed to decode configuration from environment variables: %w ", err)
+	}
+
 	if err := env.Decode(vars, env.DefaultNamePrefix, cmd.Configuration); err != nil {
-		log.WithoutContext().Debug("environment variables", strings.Join(vars, ", "))
+		log.WithoutContext().Debug("environment variables", strings.Join(envVarNames(vars), ", "))
 		return false, fmt.Errorf("failed to decode configuration from environment variables: %w ", err)
 	}
 
@@ -29,3 +38,126 @@ func (e *EnvLoader) Load(_ []string, cmd *cli.Command) (bool, error) {
 
 	return true, nil
 }
+
+// resolveFileEnvVars replaces the environment variables suffixed with "_FILE", which are not options themselves,
+// by the option they are suffixing, with the content of the file they reference as value.
+// It allows to give the secrets as files, such as the Docker and Kubernetes secrets.
+func resolveFileEnvVars(vars []string, element interface{}) ([]string, error) {
+	names := make(map[string]struct{}, len(vars))
+	for _, name := range envVarNames(vars) {
+		names[name] = struct{}{}
+	}
+
+	resolved := make([]string, 0, len(vars))
+	for _, v := range vars {
+		name, value := splitEnvVar(v)
+
+		if !strings.HasSuffix(name, fileSuffix) || isOption(element, name) {
+			resolved = append(resolved, v)
+			continue
+		}
+
+		optionName := strings.TrimSuffix(name, fileSuffix)
+		if !isOption(element, optionName) {
+			resolved = append(resolved, v)
+			continue
+		}
+
+		if _, ok := names[optionName]; ok {
+			return nil, fmt.Errorf("both %s and %s are set", optionName, name)
+		}
+
+		content, err := os.ReadFile(value)
+		if err != nil {
+			return nil, fmt.Errorf("reading the value of %s: %w", optionName, err)
+		}
+
+		resolved = append(resolved, optionName+"="+strings.TrimRight(string(content), "\r\n"))
+	}
+
+	return resolved, nil
+}
+
+// isOption reports whether the given environment variable name is an option of the given configuration element.
+func isOption(element interface{}, name string) bool {
+	if element == nil || !strings.HasPrefix(strings.ToUpper(name), env.DefaultNamePrefix) {
+		return false
+	}
+
+	path := strings.Split(strings.ToLower(name[len(env.DefaultNamePrefix):]), "_")
+
+	return isOptionPath(reflect.TypeOf(element), path)
+}
+
+func isOptionPath(rootType reflect.Type, path []string) bool {
+	for rootType.Kind() == reflect.Ptr {
+		rootType = rootType.Elem()
+	}
+
+	if len(path) == 0 {
+		return true
+	}
+
+	switch rootType.Kind() {
+	case reflect.Struct:
+		for i := 0; i < rootType.NumField(); i++ {
+			field := rootType.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+
+			if field.Anonymous {
+				if isOptionPath(field.Type, path) {
+					return true
+				}
+				continue
+			}
+
+			if strings.EqualFold(field.Name, path[0]) && isOptionPath(field.Type, path[1:]) {
+				return true
+			}
+		}
+
+		return false
+
+	case reflect.Map:
+		// The first element of the path is the key of the map.
+		return isOptionPath(rootType.Elem(), path[1:])
+
+	case reflect.Slice:
+		elemType := rootType.Elem()
+		for elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+
+		if elemType.Kind() != reflect.Struct {
+			return false
+		}
+
+		// The first element of the path is the index of the slice.
+		return isOptionPath(elemType, path[1:])
+
+	default:
+		return false
+	}
+}
+
+// envVarNames returns the names of the given environment variables, without their values.
+func envVarNames(vars []string) []string {
+	names := make([]string, 0, len(vars))
+	for _, v := range vars {
+		name, _ := splitEnvVar(v)
+		names = append(names, name)
+	}
+
+	return names
+}
+
+func splitEnvVar(v string) (string, string) {
+	parts := strings.SplitN(v, "=", 2)
+	if len(parts) < 2 {
+		return parts[0], ""
+	}
+
+	return parts[0], parts[1]
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type envTestConfiguration struct {
+	Providers *envTestProviders
+	Resolvers map[string]*envTestResolver
+}
+
+type envTestProviders struct {
+	File *struct {
+		Filename string
+	}
+}
+
+type envTestResolver struct {
+	Domains []struct {
+		Main string
+	}
+	EAB *struct {
+		HmacEncoded string
+	}
+}
+
+func Test_resolveFileEnvVars(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(secret, []byte("s3cr3t\n"), 0o600))
+
+	testCases := []struct {
+		desc          string
+		vars          []string
+		expected      []string
+		expectedError bool
+	}{
+		{
+			desc:     "options",
+			vars:     []string{"TRAEFIK_PROVIDERS_FILE=true", "TRAEFIK_PROVIDERS_FILE_FILENAME=foo.toml"},
+			expected: []string{"TRAEFIK_PROVIDERS_FILE=true", "TRAEFIK_PROVIDERS_FILE_FILENAME=foo.toml"},
+		},
+		{
+			desc:     "option value in a file",
+			vars:     []string{"TRAEFIK_RESOLVERS_LE_EAB_HMACENCODED_FILE=" + secret},
+			expected: []string{"TRAEFIK_RESOLVERS_LE_EAB_HMACENCODED=s3cr3t"},
+		},
+		{
+			desc:     "slice option value in a file",
+			vars:     []string{"TRAEFIK_RESOLVERS_LE_DOMAINS_0_MAIN_FILE=" + secret},
+			expected: []string{"TRAEFIK_RESOLVERS_LE_DOMAINS_0_MAIN=s3cr3t"},
+		},
+		{
+			desc:     "unknown option",
+			vars:     []string{"TRAEFIK_RESOLVERS_LE_FOO_FILE=" + secret},
+			expected: []string{"TRAEFIK_RESOLVERS_LE_FOO_FILE=" + secret},
+		},
+		{
+			desc:          "option value and file",
+			vars:          []string{"TRAEFIK_RESOLVERS_LE_EAB_HMACENCODED=foo", "TRAEFIK_RESOLVERS_LE_EAB_HMACENCODED_FILE=" + secret},
+			expectedError: true,
+		},
+		{
+			desc:          "missing file",
+			vars:          []string{"TRAEFIK_RESOLVERS_LE_EAB_HMACENCODED_FILE=" + secret + ".missing"},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			vars, err := resolveFileEnvVars(test.vars, &envTestConfiguration{})
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, vars)
+		})
+	}
+}
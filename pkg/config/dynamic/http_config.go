@@ -26,6 +26,16 @@ type HTTPConfiguration struct {
 type Model struct {
 	Middlewares []string         `json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty" export:"true"`
 	TLS         *RouterTLSConfig `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+
+	SNICertResolvers []SNICertResolver `json:"sniCertResolvers,omitempty" toml:"sniCertResolvers,omitempty" yaml:"sniCertResolvers,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// SNICertResolver is the certificate resolver for the routers whose domains all match a server name pattern.
+type SNICertResolver struct {
+	SNI          string `json:"sni,omitempty" toml:"sni,omitempty" yaml:"sni,omitempty" export:"true"`
+	CertResolver string `json:"certResolver,omitempty" toml:"certResolver,omitempty" yaml:"certResolver,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -35,18 +45,22 @@ type Service struct {
 	LoadBalancer *ServersLoadBalancer `json:"loadBalancer,omitempty" toml:"loadBalancer,omitempty" yaml:"loadBalancer,omitempty" export:"true"`
 	Weighted     *WeightedRoundRobin  `json:"weighted,omitempty" toml:"weighted,omitempty" yaml:"weighted,omitempty" label:"-" export:"true"`
 	Mirroring    *Mirroring           `json:"mirroring,omitempty" toml:"mirroring,omitempty" yaml:"mirroring,omitempty" label:"-" export:"true"`
+	Priority     *Priority            `json:"priority,omitempty" toml:"priority,omitempty" yaml:"priority,omitempty" label:"-" export:"true"`
+	Hedging      *Hedging             `json:"hedging,omitempty" toml:"hedging,omitempty" yaml:"hedging,omitempty" label:"-" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
 
 // Router holds the router configuration.
 type Router struct {
-	EntryPoints []string         `json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty" export:"true"`
-	Middlewares []string         `json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty" export:"true"`
-	Service     string           `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
-	Rule        string           `json:"rule,omitempty" toml:"rule,omitempty" yaml:"rule,omitempty"`
-	Priority    int              `json:"priority,omitempty" toml:"priority,omitempty,omitzero" yaml:"priority,omitempty" export:"true"`
-	TLS         *RouterTLSConfig `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	EntryPoints []string `json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty" export:"true"`
+	Middlewares []string `json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty" export:"true"`
+	// InlineMiddlewares are the middlewares defined in the router, which are applied after the Middlewares.
+	InlineMiddlewares []Middleware     `json:"inlineMiddlewares,omitempty" toml:"inlineMiddlewares,omitempty" yaml:"inlineMiddlewares,omitempty" export:"true"`
+	Service           string           `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	Rule              string           `json:"rule,omitempty" toml:"rule,omitempty" yaml:"rule,omitempty"`
+	Priority          int              `json:"priority,omitempty" toml:"priority,omitempty,omitzero" yaml:"priority,omitempty" export:"true"`
+	TLS               *RouterTLSConfig `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -112,6 +126,57 @@ func (w *WRRService) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// Priority is a load-balancer of services by priority:
+// the requests are sent to the first service until it is saturated or down,
+// and then spill over to the next service.
+type Priority struct {
+	Services []PriorityService `json:"services,omitempty" toml:"services,omitempty" yaml:"services,omitempty" export:"true"`
+	// HealthCheck enables automatic self-healthcheck for this service,
+	// so that a child service reported as down is skipped, as if it were saturated.
+	HealthCheck *HealthCheck `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// PriorityService is a reference to a service load-balanced by priority.
+type PriorityService struct {
+	Name string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
+	// MaxConcurrentRequests is the number of requests in progress from which the service is saturated (0 means unlimited).
+	MaxConcurrentRequests int64 `json:"maxConcurrentRequests,omitempty" toml:"maxConcurrentRequests,omitempty" yaml:"maxConcurrentRequests,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Hedging is a load-balancer of services sending a copy of the idempotent requests to a second service
+// when the first one has not responded within the hedging delay, and keeping the first response.
+type Hedging struct {
+	Services []HedgingService `json:"services,omitempty" toml:"services,omitempty" yaml:"services,omitempty" export:"true"`
+	// Percentile is the percentile of the recent response times used as the hedging delay.
+	Percentile float64 `json:"percentile,omitempty" toml:"percentile,omitempty" yaml:"percentile,omitempty" export:"true"`
+	// MinDelay is the lower bound of the hedging delay.
+	MinDelay ptypes.Duration `json:"minDelay,omitempty" toml:"minDelay,omitempty" yaml:"minDelay,omitempty" export:"true"`
+	// MaxDelay is the upper bound of the hedging delay, which is also used until enough response times are recorded.
+	MaxDelay ptypes.Duration `json:"maxDelay,omitempty" toml:"maxDelay,omitempty" yaml:"maxDelay,omitempty" export:"true"`
+	// HealthCheck enables automatic self-healthcheck for this service,
+	// so that a child service reported as down is skipped.
+	HealthCheck *HealthCheck `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// SetDefaults Default values for a Hedging service.
+func (h *Hedging) SetDefaults() {
+	h.Percentile = 95
+	h.MaxDelay = ptypes.Duration(time.Second)
+}
+
+// +k8s:deepcopy-gen=true
+
+// HedgingService is a reference to a service load-balanced with hedging.
+type HedgingService struct {
+	Name string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // Sticky holds the sticky configuration.
 type Sticky struct {
 	Cookie *Cookie `json:"cookie,omitempty" toml:"cookie,omitempty" yaml:"cookie,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
@@ -141,6 +206,11 @@ type ServersLoadBalancer struct {
 	PassHostHeader     *bool               `json:"passHostHeader" toml:"passHostHeader" yaml:"passHostHeader" export:"true"`
 	ResponseForwarding *ResponseForwarding `json:"responseForwarding,omitempty" toml:"responseForwarding,omitempty" yaml:"responseForwarding,omitempty" export:"true"`
 	ServersTransport   string              `json:"serversTransport,omitempty" toml:"serversTransport,omitempty" yaml:"serversTransport,omitempty" export:"true"`
+	// OpenAPI declares the OpenAPI spec of the service, republished by the API per host.
+	OpenAPI *OpenAPI `json:"openAPI,omitempty" toml:"openAPI,omitempty" yaml:"openAPI,omitempty" export:"true"`
+	// GRPCReflection enables the validation of the gRPC routes of the routers using this service,
+	// against the gRPC reflection service of its servers.
+	GRPCReflection *GRPCReflection `json:"grpcReflection,omitempty" toml:"grpcReflection,omitempty" yaml:"grpcReflection,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 }
 
 // Mergeable tells if the given service is mergeable.
@@ -168,6 +238,27 @@ func (l *ServersLoadBalancer) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// OpenAPI holds the location of the OpenAPI spec of a service.
+type OpenAPI struct {
+	// URL is either an absolute URL, or a path on the first server of the load-balancer.
+	URL string `json:"url,omitempty" toml:"url,omitempty" yaml:"url,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// GRPCReflection holds the configuration of the validation of the gRPC routes against the gRPC reflection service of the servers.
+type GRPCReflection struct {
+	// Timeout is the timeout of the queries to the reflection service of a server.
+	Timeout ptypes.Duration `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (g *GRPCReflection) SetDefaults() {
+	g.Timeout = ptypes.Duration(5 * time.Second)
+}
+
+// +k8s:deepcopy-gen=true
+
 // ResponseForwarding holds configuration for the forward of the response.
 type ResponseForwarding struct {
 	FlushInterval string `json:"flushInterval,omitempty" toml:"flushInterval,omitempty" yaml:"flushInterval,omitempty" export:"true"`
@@ -225,6 +316,7 @@ type ServersTransport struct {
 	MaxIdleConnsPerHost int                        `description:"If non-zero, controls the maximum idle (keep-alive) to keep per-host. If zero, DefaultMaxIdleConnsPerHost is used" json:"maxIdleConnsPerHost,omitempty" toml:"maxIdleConnsPerHost,omitempty" yaml:"maxIdleConnsPerHost,omitempty" export:"true"`
 	ForwardingTimeouts  *ForwardingTimeouts        `description:"Timeouts for requests forwarded to the backend servers." json:"forwardingTimeouts,omitempty" toml:"forwardingTimeouts,omitempty" yaml:"forwardingTimeouts,omitempty" export:"true"`
 	DisableHTTP2        bool                       `description:"Disable HTTP/2 for connections with backend servers." json:"disableHTTP2,omitempty" toml:"disableHTTP2,omitempty" yaml:"disableHTTP2,omitempty" export:"true"`
+	HTTP1Fallback       bool                       `description:"Retry once over HTTP/1.1 the requests failing with an HTTP/2 protocol error." json:"http1Fallback,omitempty" toml:"http1Fallback,omitempty" yaml:"http1Fallback,omitempty" export:"true"`
 	PeerCertURI         string                     `description:"URI used to match against SAN URI during the peer certificate verification." json:"peerCertURI,omitempty" toml:"peerCertURI,omitempty" yaml:"peerCertURI,omitempty" export:"true"`
 }
 
@@ -4,6 +4,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"net/http"
 	"os"
 	"time"
 
@@ -22,7 +23,12 @@ type Middleware struct {
 	ReplacePathRegex  *ReplacePathRegex  `json:"replacePathRegex,omitempty" toml:"replacePathRegex,omitempty" yaml:"replacePathRegex,omitempty" export:"true"`
 	Chain             *Chain             `json:"chain,omitempty" toml:"chain,omitempty" yaml:"chain,omitempty" export:"true"`
 	IPWhiteList       *IPWhiteList       `json:"ipWhiteList,omitempty" toml:"ipWhiteList,omitempty" yaml:"ipWhiteList,omitempty" export:"true"`
+	IPAllowList       *IPAllowList       `json:"ipAllowList,omitempty" toml:"ipAllowList,omitempty" yaml:"ipAllowList,omitempty" export:"true"`
+	GeoIP             *GeoIP             `json:"geoIP,omitempty" toml:"geoIP,omitempty" yaml:"geoIP,omitempty" export:"true"`
+	BotBlocker        *BotBlocker        `json:"botBlocker,omitempty" toml:"botBlocker,omitempty" yaml:"botBlocker,omitempty" export:"true"`
 	Headers           *Headers           `json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
+	StripHeaders      *StripHeaders      `json:"stripHeaders,omitempty" toml:"stripHeaders,omitempty" yaml:"stripHeaders,omitempty" export:"true"`
+	HTTPCache         *HTTPCache         `json:"httpCache,omitempty" toml:"httpCache,omitempty" yaml:"httpCache,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	Errors            *ErrorPage         `json:"errors,omitempty" toml:"errors,omitempty" yaml:"errors,omitempty" export:"true"`
 	RateLimit         *RateLimit         `json:"rateLimit,omitempty" toml:"rateLimit,omitempty" yaml:"rateLimit,omitempty" export:"true"`
 	RedirectRegex     *RedirectRegex     `json:"redirectRegex,omitempty" toml:"redirectRegex,omitempty" yaml:"redirectRegex,omitempty" export:"true"`
@@ -30,13 +36,22 @@ type Middleware struct {
 	BasicAuth         *BasicAuth         `json:"basicAuth,omitempty" toml:"basicAuth,omitempty" yaml:"basicAuth,omitempty" export:"true"`
 	DigestAuth        *DigestAuth        `json:"digestAuth,omitempty" toml:"digestAuth,omitempty" yaml:"digestAuth,omitempty" export:"true"`
 	ForwardAuth       *ForwardAuth       `json:"forwardAuth,omitempty" toml:"forwardAuth,omitempty" yaml:"forwardAuth,omitempty" export:"true"`
+	HMACAuth          *HMACAuth          `json:"hmacAuth,omitempty" toml:"hmacAuth,omitempty" yaml:"hmacAuth,omitempty" export:"true"`
+	LDAPAuth          *LDAPAuth          `json:"ldapAuth,omitempty" toml:"ldapAuth,omitempty" yaml:"ldapAuth,omitempty" export:"true"`
+	OIDCAuth          *OIDCAuth          `json:"oidcAuth,omitempty" toml:"oidcAuth,omitempty" yaml:"oidcAuth,omitempty" export:"true"`
 	InFlightReq       *InFlightReq       `json:"inFlightReq,omitempty" toml:"inFlightReq,omitempty" yaml:"inFlightReq,omitempty" export:"true"`
+	Maintenance       *Maintenance       `json:"maintenance,omitempty" toml:"maintenance,omitempty" yaml:"maintenance,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	Buffering         *Buffering         `json:"buffering,omitempty" toml:"buffering,omitempty" yaml:"buffering,omitempty" export:"true"`
 	CircuitBreaker    *CircuitBreaker    `json:"circuitBreaker,omitempty" toml:"circuitBreaker,omitempty" yaml:"circuitBreaker,omitempty" export:"true"`
 	Compress          *Compress          `json:"compress,omitempty" toml:"compress,omitempty" yaml:"compress,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	PassTLSClientCert *PassTLSClientCert `json:"passTLSClientCert,omitempty" toml:"passTLSClientCert,omitempty" yaml:"passTLSClientCert,omitempty" export:"true"`
 	Retry             *Retry             `json:"retry,omitempty" toml:"retry,omitempty" yaml:"retry,omitempty" export:"true"`
 	ContentType       *ContentType       `json:"contentType,omitempty" toml:"contentType,omitempty" yaml:"contentType,omitempty" export:"true"`
+	FeatureFlags      *FeatureFlags      `json:"featureFlags,omitempty" toml:"featureFlags,omitempty" yaml:"featureFlags,omitempty" export:"true"`
+	RequestCollapsing *RequestCollapsing `json:"requestCollapsing,omitempty" toml:"requestCollapsing,omitempty" yaml:"requestCollapsing,omitempty" export:"true"`
+	RequestID         *RequestID         `json:"requestID,omitempty" toml:"requestID,omitempty" yaml:"requestID,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	RewriteBody       *RewriteBody       `json:"rewriteBody,omitempty" toml:"rewriteBody,omitempty" yaml:"rewriteBody,omitempty" export:"true"`
+	ThreatIntel       *ThreatIntel       `json:"threatIntel,omitempty" toml:"threatIntel,omitempty" yaml:"threatIntel,omitempty" export:"true"`
 
 	Plugin map[string]PluginConf `json:"plugin,omitempty" toml:"plugin,omitempty" yaml:"plugin,omitempty" export:"true"`
 }
@@ -97,6 +112,22 @@ type Chain struct {
 // CircuitBreaker holds the circuit breaker configuration.
 type CircuitBreaker struct {
 	Expression string `json:"expression,omitempty" toml:"expression,omitempty" yaml:"expression,omitempty" export:"true"`
+	// CheckPeriod is the interval between successive checks of the circuit breaker condition (when in standby state).
+	CheckPeriod ptypes.Duration `json:"checkPeriod,omitempty" toml:"checkPeriod,omitempty" yaml:"checkPeriod,omitempty" export:"true"`
+	// FallbackDuration is the duration for which the circuit breaker will wait before trying to recover (from a tripped state).
+	FallbackDuration ptypes.Duration `json:"fallbackDuration,omitempty" toml:"fallbackDuration,omitempty" yaml:"fallbackDuration,omitempty" export:"true"`
+	// RecoveryDuration is the duration for which the circuit breaker will try to recover (as soon as it is in recovering state).
+	RecoveryDuration ptypes.Duration `json:"recoveryDuration,omitempty" toml:"recoveryDuration,omitempty" yaml:"recoveryDuration,omitempty" export:"true"`
+	// ResponseCode is the status code that the circuit breaker will return while it is in the open state.
+	ResponseCode int `json:"responseCode,omitempty" toml:"responseCode,omitempty" yaml:"responseCode,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a CircuitBreaker.
+func (c *CircuitBreaker) SetDefaults() {
+	c.CheckPeriod = ptypes.Duration(100 * time.Millisecond)
+	c.FallbackDuration = ptypes.Duration(10 * time.Second)
+	c.RecoveryDuration = ptypes.Duration(10 * time.Second)
+	c.ResponseCode = http.StatusServiceUnavailable
 }
 
 // +k8s:deepcopy-gen=true
@@ -104,6 +135,10 @@ type CircuitBreaker struct {
 // Compress holds the compress configuration.
 type Compress struct {
 	ExcludedContentTypes []string `json:"excludedContentTypes,omitempty" toml:"excludedContentTypes,omitempty" yaml:"excludedContentTypes,omitempty" export:"true"`
+	// MinResponseBodyBytes is the minimum size of the response bodies which are compressed.
+	MinResponseBodyBytes int `json:"minResponseBodyBytes,omitempty" toml:"minResponseBodyBytes,omitempty" yaml:"minResponseBodyBytes,omitempty" export:"true"`
+	// Encodings is the list of the supported encodings (gzip, br, zstd), by order of preference.
+	Encodings []string `json:"encodings,omitempty" toml:"encodings,omitempty" yaml:"encodings,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -140,6 +175,109 @@ type ForwardAuth struct {
 
 // +k8s:deepcopy-gen=true
 
+// HMACAuth holds the HMAC signature authentication middleware configuration.
+// This middleware verifies the HMAC signature of the request bodies, as sent by the webhook providers,
+// with any of the keys, to allow their rotation.
+type HMACAuth struct {
+	Keys            []string        `json:"keys,omitempty" toml:"keys,omitempty" yaml:"keys,omitempty"`
+	KeysFile        string          `json:"keysFile,omitempty" toml:"keysFile,omitempty" yaml:"keysFile,omitempty"`
+	Header          string          `json:"header,omitempty" toml:"header,omitempty" yaml:"header,omitempty" export:"true"`
+	Prefix          string          `json:"prefix,omitempty" toml:"prefix,omitempty" yaml:"prefix,omitempty" export:"true"`
+	Algorithm       string          `json:"algorithm,omitempty" toml:"algorithm,omitempty" yaml:"algorithm,omitempty" export:"true"`
+	Encoding        string          `json:"encoding,omitempty" toml:"encoding,omitempty" yaml:"encoding,omitempty" export:"true"`
+	TimestampHeader string          `json:"timestampHeader,omitempty" toml:"timestampHeader,omitempty" yaml:"timestampHeader,omitempty" export:"true"`
+	ClockSkew       ptypes.Duration `json:"clockSkew,omitempty" toml:"clockSkew,omitempty" yaml:"clockSkew,omitempty" export:"true"`
+	MaxBodySize     int64           `json:"maxBodySize,omitempty" toml:"maxBodySize,omitempty" yaml:"maxBodySize,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a HMACAuth.
+func (h *HMACAuth) SetDefaults() {
+	h.Header = "X-Signature"
+	h.Algorithm = "sha256"
+	h.Encoding = "hex"
+	h.ClockSkew = ptypes.Duration(5 * time.Minute)
+	h.MaxBodySize = 1024 * 1024
+}
+
+// +k8s:deepcopy-gen=true
+
+// LDAPAuth holds the LDAP authentication middleware configuration.
+// This middleware authenticates the HTTP basic credentials of the users against an LDAP server,
+// by searching their entry with the bind DN, and binding with their password.
+type LDAPAuth struct {
+	URL           string          `json:"url,omitempty" toml:"url,omitempty" yaml:"url,omitempty"`
+	StartTLS      bool            `json:"startTLS,omitempty" toml:"startTLS,omitempty" yaml:"startTLS,omitempty" export:"true"`
+	TLS           *ClientTLS      `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	BindDN        string          `json:"bindDN,omitempty" toml:"bindDN,omitempty" yaml:"bindDN,omitempty"`
+	BindPassword  string          `json:"bindPassword,omitempty" toml:"bindPassword,omitempty" yaml:"bindPassword,omitempty"`
+	BaseDN        string          `json:"baseDN,omitempty" toml:"baseDN,omitempty" yaml:"baseDN,omitempty"`
+	SearchFilter  string          `json:"searchFilter,omitempty" toml:"searchFilter,omitempty" yaml:"searchFilter,omitempty"`
+	GroupBaseDN   string          `json:"groupBaseDN,omitempty" toml:"groupBaseDN,omitempty" yaml:"groupBaseDN,omitempty"`
+	Groups        []string        `json:"groups,omitempty" toml:"groups,omitempty" yaml:"groups,omitempty"`
+	Realm         string          `json:"realm,omitempty" toml:"realm,omitempty" yaml:"realm,omitempty"`
+	HeaderField   string          `json:"headerField,omitempty" toml:"headerField,omitempty" yaml:"headerField,omitempty" export:"true"`
+	RemoveHeader  bool            `json:"removeHeader,omitempty" toml:"removeHeader,omitempty" yaml:"removeHeader,omitempty" export:"true"`
+	CacheDuration ptypes.Duration `json:"cacheDuration,omitempty" toml:"cacheDuration,omitempty" yaml:"cacheDuration,omitempty" export:"true"`
+	MaxIdleConns  int             `json:"maxIdleConns,omitempty" toml:"maxIdleConns,omitempty" yaml:"maxIdleConns,omitempty" export:"true"`
+	Timeout       ptypes.Duration `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a LDAPAuth.
+func (l *LDAPAuth) SetDefaults() {
+	l.SearchFilter = "(uid={username})"
+	l.MaxIdleConns = 10
+	l.Timeout = ptypes.Duration(10 * time.Second)
+}
+
+// +k8s:deepcopy-gen=true
+
+// OIDCAuth holds the OpenID Connect authentication middleware configuration.
+// This middleware authenticates the users with the authorization code flow of an OpenID Connect provider,
+// and forwards the claims of their ID token to the backend as request headers.
+type OIDCAuth struct {
+	Issuer       string            `json:"issuer,omitempty" toml:"issuer,omitempty" yaml:"issuer,omitempty"`
+	ClientID     string            `json:"clientID,omitempty" toml:"clientID,omitempty" yaml:"clientID,omitempty"`
+	ClientSecret string            `json:"clientSecret,omitempty" toml:"clientSecret,omitempty" yaml:"clientSecret,omitempty"`
+	Scopes       []string          `json:"scopes,omitempty" toml:"scopes,omitempty" yaml:"scopes,omitempty" export:"true"`
+	RedirectPath string            `json:"redirectPath,omitempty" toml:"redirectPath,omitempty" yaml:"redirectPath,omitempty" export:"true"`
+	SessionKey   string            `json:"sessionKey,omitempty" toml:"sessionKey,omitempty" yaml:"sessionKey,omitempty"`
+	CookieName   string            `json:"cookieName,omitempty" toml:"cookieName,omitempty" yaml:"cookieName,omitempty" export:"true"`
+	ClaimHeaders map[string]string `json:"claimHeaders,omitempty" toml:"claimHeaders,omitempty" yaml:"claimHeaders,omitempty" export:"true"`
+	TLS          *ClientTLS        `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on an OIDCAuth.
+func (o *OIDCAuth) SetDefaults() {
+	o.Scopes = []string{"openid"}
+	o.RedirectPath = "/oauth2/callback"
+	o.CookieName = "_traefik_oidc"
+}
+
+// +k8s:deepcopy-gen=true
+
+// FeatureFlags holds the feature flags middleware configuration.
+// This middleware evaluates feature flags with the attributes of each request,
+// against a provider implementing the OpenFeature Remote Evaluation Protocol (OFREP),
+// and forwards the results to the backend as request headers.
+type FeatureFlags struct {
+	Endpoint           string            `json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	Flags              []string          `json:"flags,omitempty" toml:"flags,omitempty" yaml:"flags,omitempty" export:"true"`
+	HeaderPrefix       string            `json:"headerPrefix,omitempty" toml:"headerPrefix,omitempty" yaml:"headerPrefix,omitempty" export:"true"`
+	TargetingKeyHeader string            `json:"targetingKeyHeader,omitempty" toml:"targetingKeyHeader,omitempty" yaml:"targetingKeyHeader,omitempty" export:"true"`
+	ContextHeaders     []string          `json:"contextHeaders,omitempty" toml:"contextHeaders,omitempty" yaml:"contextHeaders,omitempty" export:"true"`
+	Headers            map[string]string `json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty"`
+	TLS                *ClientTLS        `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	Timeout            ptypes.Duration   `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a FeatureFlags.
+func (f *FeatureFlags) SetDefaults() {
+	f.HeaderPrefix = "X-Feature-"
+	f.Timeout = ptypes.Duration(time.Second)
+}
+
+// +k8s:deepcopy-gen=true
+
 // Headers holds the custom header configuration.
 type Headers struct {
 	CustomRequestHeaders  map[string]string `json:"customRequestHeaders,omitempty" toml:"customRequestHeaders,omitempty" yaml:"customRequestHeaders,omitempty" export:"true"`
@@ -282,14 +420,98 @@ type IPWhiteList struct {
 
 // +k8s:deepcopy-gen=true
 
+// IPAllowList holds the IP allow list configuration.
+type IPAllowList struct {
+	SourceRange []string    `json:"sourceRange,omitempty" toml:"sourceRange,omitempty" yaml:"sourceRange,omitempty"`
+	IPStrategy  *IPStrategy `json:"ipStrategy,omitempty" toml:"ipStrategy,omitempty" yaml:"ipStrategy,omitempty"  label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// BotBlocker holds the bot blocker middleware configuration.
+// This middleware denies, or tarpits, the requests whose user agent, path, or headers match regular expressions,
+// listed in the configuration or in list files.
+type BotBlocker struct {
+	UserAgents []string           `json:"userAgents,omitempty" toml:"userAgents,omitempty" yaml:"userAgents,omitempty" export:"true"`
+	Paths      []string           `json:"paths,omitempty" toml:"paths,omitempty" yaml:"paths,omitempty" export:"true"`
+	Headers    []BotBlockerHeader `json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
+	// ListFiles are files listing regular expressions, shared by the middlewares and reloaded when they are modified.
+	ListFiles []string `json:"listFiles,omitempty" toml:"listFiles,omitempty" yaml:"listFiles,omitempty"`
+	// Action is the action taken on the matching requests: deny (the default), or tarpit.
+	Action     string `json:"action,omitempty" toml:"action,omitempty" yaml:"action,omitempty" export:"true"`
+	StatusCode int    `json:"statusCode,omitempty" toml:"statusCode,omitempty" yaml:"statusCode,omitempty" export:"true"`
+	// TarpitDelay is the delay before the tarpitted requests are answered, 10s by default.
+	TarpitDelay ptypes.Duration `json:"tarpitDelay,omitempty" toml:"tarpitDelay,omitempty" yaml:"tarpitDelay,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// BotBlockerHeader holds a request header name, and a regular expression matched against its values.
+type BotBlockerHeader struct {
+	Name  string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
+	Regex string `json:"regex,omitempty" toml:"regex,omitempty" yaml:"regex,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// GeoIP holds the GeoIP middleware configuration.
+// This middleware looks up the client IP in MaxMind databases (mmdb),
+// rejects the requests according to the country and the autonomous system number (ASN) of the client,
+// and optionally forwards the geolocation of the client to the backend in request headers.
+type GeoIP struct {
+	DatabaseFiles    []string      `json:"databaseFiles,omitempty" toml:"databaseFiles,omitempty" yaml:"databaseFiles,omitempty"`
+	AllowedCountries []string      `json:"allowedCountries,omitempty" toml:"allowedCountries,omitempty" yaml:"allowedCountries,omitempty" export:"true"`
+	DeniedCountries  []string      `json:"deniedCountries,omitempty" toml:"deniedCountries,omitempty" yaml:"deniedCountries,omitempty" export:"true"`
+	AllowedASNs      []int         `json:"allowedASNs,omitempty" toml:"allowedASNs,omitempty" yaml:"allowedASNs,omitempty" export:"true"`
+	DeniedASNs       []int         `json:"deniedASNs,omitempty" toml:"deniedASNs,omitempty" yaml:"deniedASNs,omitempty" export:"true"`
+	IPStrategy       *IPStrategy   `json:"ipStrategy,omitempty" toml:"ipStrategy,omitempty" yaml:"ipStrategy,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Headers          *GeoIPHeaders `json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// GeoIPHeaders holds the names of the request headers set with the geolocation of the client.
+// The headers without a name are not set.
+type GeoIPHeaders struct {
+	Country        string `json:"country,omitempty" toml:"country,omitempty" yaml:"country,omitempty" export:"true"`
+	City           string `json:"city,omitempty" toml:"city,omitempty" yaml:"city,omitempty" export:"true"`
+	ASN            string `json:"asn,omitempty" toml:"asn,omitempty" yaml:"asn,omitempty" export:"true"`
+	ASOrganization string `json:"asOrganization,omitempty" toml:"asOrganization,omitempty" yaml:"asOrganization,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // InFlightReq limits the number of requests being processed and served concurrently.
 type InFlightReq struct {
-	Amount          int64            `json:"amount,omitempty" toml:"amount,omitempty" yaml:"amount,omitempty" export:"true"`
+	Amount int64 `json:"amount,omitempty" toml:"amount,omitempty" yaml:"amount,omitempty" export:"true"`
+	// Global limits the requests across all the sources, instead of per source.
+	// It is mutually exclusive with SourceCriterion.
+	Global          bool             `json:"global,omitempty" toml:"global,omitempty" yaml:"global,omitempty" export:"true"`
 	SourceCriterion *SourceCriterion `json:"sourceCriterion,omitempty" toml:"sourceCriterion,omitempty" yaml:"sourceCriterion,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
 
+// Maintenance holds the maintenance middleware configuration.
+// This middleware answers the requests with a static response while the maintenance is enabled,
+// except the requests from the bypass source ranges, or with one of the bypass headers.
+type Maintenance struct {
+	// Enabled enables the maintenance, unless it is switched at runtime through the API.
+	Enabled    bool              `json:"enabled,omitempty" toml:"enabled,omitempty" yaml:"enabled,omitempty" export:"true"`
+	StatusCode int               `json:"statusCode,omitempty" toml:"statusCode,omitempty" yaml:"statusCode,omitempty" export:"true"`
+	Headers    map[string]string `json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
+	// Body is the body of the response. It is mutually exclusive with BodyFile.
+	Body     string `json:"body,omitempty" toml:"body,omitempty" yaml:"body,omitempty"`
+	BodyFile string `json:"bodyFile,omitempty" toml:"bodyFile,omitempty" yaml:"bodyFile,omitempty"`
+	// BypassSourceRange lists the client IPs or CIDRs which are not affected by the maintenance.
+	BypassSourceRange []string    `json:"bypassSourceRange,omitempty" toml:"bypassSourceRange,omitempty" yaml:"bypassSourceRange,omitempty"`
+	IPStrategy        *IPStrategy `json:"ipStrategy,omitempty" toml:"ipStrategy,omitempty" yaml:"ipStrategy,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	// BypassHeaders lists the request headers, by name, whose value lets the request through.
+	BypassHeaders map[string]string `json:"bypassHeaders,omitempty" toml:"bypassHeaders,omitempty" yaml:"bypassHeaders,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // PassTLSClientCert holds the TLS client cert headers configuration.
 type PassTLSClientCert struct {
 	PEM  bool                      `json:"pem,omitempty" toml:"pem,omitempty" yaml:"pem,omitempty" export:"true"`
@@ -369,10 +591,117 @@ type ReplacePathRegex struct {
 
 // +k8s:deepcopy-gen=true
 
+// RequestCollapsing holds the request collapsing middleware configuration.
+// This middleware coalesces the concurrent identical GET requests into a single request to the backend,
+// whose response is sent to all the clients.
+type RequestCollapsing struct {
+	KeyHeaders           []string `json:"keyHeaders,omitempty" toml:"keyHeaders,omitempty" yaml:"keyHeaders,omitempty" export:"true"`
+	IgnoreQuery          bool     `json:"ignoreQuery,omitempty" toml:"ignoreQuery,omitempty" yaml:"ignoreQuery,omitempty" export:"true"`
+	MaxResponseBodyBytes int64    `json:"maxResponseBodyBytes,omitempty" toml:"maxResponseBodyBytes,omitempty" yaml:"maxResponseBodyBytes,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a RequestCollapsing.
+func (r *RequestCollapsing) SetDefaults() {
+	r.MaxResponseBodyBytes = 1024 * 1024
+}
+
+// +k8s:deepcopy-gen=true
+
+// HTTPCache holds the HTTP cache middleware configuration.
+// This middleware caches the responses in memory, following RFC 7234.
+type HTTPCache struct {
+	// MaxSize is the maximum size of the cached responses, 64MiB by default.
+	MaxSize int64 `json:"maxSize,omitempty" toml:"maxSize,omitempty" yaml:"maxSize,omitempty" export:"true"`
+	// MaxResponseBodyBytes is the maximum size of the body of a cached response, 1MiB by default.
+	MaxResponseBodyBytes int64 `json:"maxResponseBodyBytes,omitempty" toml:"maxResponseBodyBytes,omitempty" yaml:"maxResponseBodyBytes,omitempty" export:"true"`
+	// DefaultTTL is the freshness lifetime of the responses without explicit freshness information.
+	// They are not cached by default.
+	DefaultTTL ptypes.Duration `json:"defaultTTL,omitempty" toml:"defaultTTL,omitempty" yaml:"defaultTTL,omitempty" export:"true"`
+	// MaxTTL caps the freshness lifetime of the responses.
+	MaxTTL      ptypes.Duration `json:"maxTTL,omitempty" toml:"maxTTL,omitempty" yaml:"maxTTL,omitempty" export:"true"`
+	KeyHeaders  []string        `json:"keyHeaders,omitempty" toml:"keyHeaders,omitempty" yaml:"keyHeaders,omitempty" export:"true"`
+	IgnoreQuery bool            `json:"ignoreQuery,omitempty" toml:"ignoreQuery,omitempty" yaml:"ignoreQuery,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RequestID holds the request ID middleware configuration.
+// This middleware sets a unique ID on each request, unless it already has one,
+// and adds it to the request and response headers.
+type RequestID struct {
+	// HeaderName is the name of the header holding the request ID, X-Request-Id by default.
+	HeaderName string `json:"headerName,omitempty" toml:"headerName,omitempty" yaml:"headerName,omitempty" export:"true"`
+	// Overwrite replaces the request ID sent by the client with a generated one.
+	Overwrite bool `json:"overwrite,omitempty" toml:"overwrite,omitempty" yaml:"overwrite,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RewriteBody holds the rewrite body middleware configuration.
+// This middleware rewrites the body of the responses with regular expressions.
+type RewriteBody struct {
+	Rewrites []RewriteBodyRule `json:"rewrites,omitempty" toml:"rewrites,omitempty" yaml:"rewrites,omitempty" export:"true"`
+	// ContentTypes are the media types of the rewritten responses, text/html by default.
+	ContentTypes []string `json:"contentTypes,omitempty" toml:"contentTypes,omitempty" yaml:"contentTypes,omitempty" export:"true"`
+	// MaxResponseBodyBytes is the maximum size of the rewritten response bodies, 1MiB by default.
+	// The larger responses are sent unchanged.
+	MaxResponseBodyBytes int64 `json:"maxResponseBodyBytes,omitempty" toml:"maxResponseBodyBytes,omitempty" yaml:"maxResponseBodyBytes,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RewriteBodyRule holds a regular expression and its replacement.
+type RewriteBodyRule struct {
+	Regex       string `json:"regex,omitempty" toml:"regex,omitempty" yaml:"regex,omitempty" export:"true"`
+	Replacement string `json:"replacement,omitempty" toml:"replacement,omitempty" yaml:"replacement,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ThreatIntel holds the threat intelligence middleware configuration.
+// This middleware queries an enrichment service with the client IP, the JA3 fingerprint, and the user agent of each request,
+// and rejects the requests the service gives a block verdict for.
+type ThreatIntel struct {
+	Endpoint      string            `json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	JA3Header     string            `json:"ja3Header,omitempty" toml:"ja3Header,omitempty" yaml:"ja3Header,omitempty" export:"true"`
+	IPStrategy    *IPStrategy       `json:"ipStrategy,omitempty" toml:"ipStrategy,omitempty" yaml:"ipStrategy,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Headers       map[string]string `json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty"`
+	TLS           *ClientTLS        `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	Timeout       ptypes.Duration   `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+	CacheDuration ptypes.Duration   `json:"cacheDuration,omitempty" toml:"cacheDuration,omitempty" yaml:"cacheDuration,omitempty" export:"true"`
+	FailOpen      bool              `json:"failOpen,omitempty" toml:"failOpen,omitempty" yaml:"failOpen,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a ThreatIntel.
+func (t *ThreatIntel) SetDefaults() {
+	t.Timeout = ptypes.Duration(time.Second)
+	t.CacheDuration = ptypes.Duration(time.Minute)
+}
+
+// +k8s:deepcopy-gen=true
+
 // Retry holds the retry configuration.
 type Retry struct {
 	Attempts        int             `json:"attempts,omitempty" toml:"attempts,omitempty" yaml:"attempts,omitempty" export:"true"`
 	InitialInterval ptypes.Duration `json:"initialInterval,omitempty" toml:"initialInterval,omitempty" yaml:"initialInterval,omitempty" export:"true"`
+	// Status defines the ranges of status codes of the backend responses which are retried,
+	// in addition to the requests which could not reach the backend.
+	// Only the requests without a body are retried on these responses.
+	Status []string `json:"status,omitempty" toml:"status,omitempty" yaml:"status,omitempty" export:"true"`
+	// IdempotentOnly restricts the retries to the requests with an idempotent method.
+	IdempotentOnly bool `json:"idempotentOnly,omitempty" toml:"idempotentOnly,omitempty" yaml:"idempotentOnly,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// StripHeaders holds the strip headers middleware configuration.
+// This middleware removes the given request headers, unless the request comes from a trusted IP,
+// so that they cannot be spoofed by the clients.
+type StripHeaders struct {
+	// Headers are the names of the headers to remove. A name ending with * matches the headers starting with the name.
+	Headers    []string    `json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
+	TrustedIPs []string    `json:"trustedIPs,omitempty" toml:"trustedIPs,omitempty" yaml:"trustedIPs,omitempty"`
+	IPStrategy *IPStrategy `json:"ipStrategy,omitempty" toml:"ipStrategy,omitempty" yaml:"ipStrategy,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
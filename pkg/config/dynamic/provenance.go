@@ -0,0 +1,86 @@
+package dynamic
+
+// +k8s:deepcopy-gen=true
+
+// Provenance holds the sources of the objects of a dynamic configuration, by object name.
+type Provenance struct {
+	Routers        map[string][]Source
+	Middlewares    map[string][]Source
+	Services       map[string][]Source
+	TCPRouters     map[string][]Source
+	TCPMiddlewares map[string][]Source
+	TCPServices    map[string][]Source
+	UDPRouters     map[string][]Source
+	UDPServices    map[string][]Source
+}
+
+// +k8s:deepcopy-gen=true
+
+// Source describes where a dynamic configuration object comes from.
+type Source struct {
+	// Provider is the name of the provider of the object.
+	Provider string `json:"provider,omitempty"`
+	// Kind is the kind of the object the configuration object is built from, e.g. Ingress or Container.
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	UID       string `json:"uid,omitempty"`
+	// Label is the label, or the annotation, of the source object defining the configuration object.
+	Label string `json:"label,omitempty"`
+}
+
+// AddRouter records a source of the HTTP router with the given name.
+func (p *Provenance) AddRouter(name string, source Source) {
+	p.Routers = addSource(p.Routers, name, source)
+}
+
+// AddMiddleware records a source of the HTTP middleware with the given name.
+func (p *Provenance) AddMiddleware(name string, source Source) {
+	p.Middlewares = addSource(p.Middlewares, name, source)
+}
+
+// AddService records a source of the HTTP service with the given name.
+func (p *Provenance) AddService(name string, source Source) {
+	p.Services = addSource(p.Services, name, source)
+}
+
+// AddTCPRouter records a source of the TCP router with the given name.
+func (p *Provenance) AddTCPRouter(name string, source Source) {
+	p.TCPRouters = addSource(p.TCPRouters, name, source)
+}
+
+// AddTCPMiddleware records a source of the TCP middleware with the given name.
+func (p *Provenance) AddTCPMiddleware(name string, source Source) {
+	p.TCPMiddlewares = addSource(p.TCPMiddlewares, name, source)
+}
+
+// AddTCPService records a source of the TCP service with the given name.
+func (p *Provenance) AddTCPService(name string, source Source) {
+	p.TCPServices = addSource(p.TCPServices, name, source)
+}
+
+// AddUDPRouter records a source of the UDP router with the given name.
+func (p *Provenance) AddUDPRouter(name string, source Source) {
+	p.UDPRouters = addSource(p.UDPRouters, name, source)
+}
+
+// AddUDPService records a source of the UDP service with the given name.
+func (p *Provenance) AddUDPService(name string, source Source) {
+	p.UDPServices = addSource(p.UDPServices, name, source)
+}
+
+func addSource(sources map[string][]Source, name string, source Source) map[string][]Source {
+	if sources == nil {
+		sources = make(map[string][]Source)
+	}
+
+	for _, s := range sources[name] {
+		if s == source {
+			return sources
+		}
+	}
+
+	sources[name] = append(sources[name], source)
+
+	return sources
+}
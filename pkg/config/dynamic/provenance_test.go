@@ -0,0 +1,28 @@
+package dynamic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProvenance_AddRouter(t *testing.T) {
+	first := Source{Kind: "Container", Name: "first", UID: "1"}
+	second := Source{Kind: "Container", Name: "second", UID: "2", Label: "traefik.http.routers.foo"}
+
+	provenance := &Provenance{}
+	provenance.AddRouter("foo", first)
+	provenance.AddRouter("foo", second)
+	// Recording the same source twice is a no-op.
+	provenance.AddRouter("foo", first)
+	provenance.AddRouter("bar", first)
+
+	expected := &Provenance{
+		Routers: map[string][]Source{
+			"foo": {first, second},
+			"bar": {first},
+		},
+	}
+
+	assert.Equal(t, expected, provenance)
+}
@@ -21,6 +21,7 @@ type TCPConfiguration struct {
 type TCPService struct {
 	LoadBalancer *TCPServersLoadBalancer `json:"loadBalancer,omitempty" toml:"loadBalancer,omitempty" yaml:"loadBalancer,omitempty" export:"true"`
 	Weighted     *TCPWeightedRoundRobin  `json:"weighted,omitempty" toml:"weighted,omitempty" yaml:"weighted,omitempty" label:"-" export:"true"`
+	Tunnel       *TCPTunnel              `json:"tunnel,omitempty" toml:"tunnel,omitempty" yaml:"tunnel,omitempty" label:"-" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -46,6 +47,23 @@ func (w *TCPWRRService) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// TCPTunnel is a tcp service forwarding each connection to the service requested by the client,
+// once authenticated with a TLS client certificate or one of the tokens.
+type TCPTunnel struct {
+	Services []TCPTunnelService `json:"services,omitempty" toml:"services,omitempty" yaml:"services,omitempty" export:"true"`
+	Tokens   []string           `json:"tokens,omitempty" toml:"tokens,omitempty" yaml:"tokens,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPTunnelService is a reference to a tcp service reachable through a tunnel under a name.
+type TCPTunnelService struct {
+	Name    string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
+	Service string `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // TCPRouter holds the router configuration.
 type TCPRouter struct {
 	EntryPoints []string            `json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty" export:"true"`
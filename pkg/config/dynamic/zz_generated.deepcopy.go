@@ -70,6 +70,58 @@ func (in *BasicAuth) DeepCopy() *BasicAuth {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BotBlocker) DeepCopyInto(out *BotBlocker) {
+	*out = *in
+	if in.UserAgents != nil {
+		in, out := &in.UserAgents, &out.UserAgents
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]BotBlockerHeader, len(*in))
+		copy(*out, *in)
+	}
+	if in.ListFiles != nil {
+		in, out := &in.ListFiles, &out.ListFiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BotBlocker.
+func (in *BotBlocker) DeepCopy() *BotBlocker {
+	if in == nil {
+		return nil
+	}
+	out := new(BotBlocker)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BotBlockerHeader) DeepCopyInto(out *BotBlockerHeader) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BotBlockerHeader.
+func (in *BotBlockerHeader) DeepCopy() *BotBlockerHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(BotBlockerHeader)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Buffering) DeepCopyInto(out *Buffering) {
 	*out = *in
@@ -147,6 +199,11 @@ func (in *Compress) DeepCopyInto(out *Compress) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Encodings != nil {
+		in, out := &in.Encodings, &out.Encodings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -183,6 +240,11 @@ func (in *Configuration) DeepCopyInto(out *Configuration) {
 		*out = new(TLSConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Provenance != nil {
+		in, out := &in.Provenance, &out.Provenance
+		*out = new(Provenance)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -300,6 +362,44 @@ func (in *ErrorPage) DeepCopy() *ErrorPage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FeatureFlags) DeepCopyInto(out *FeatureFlags) {
+	*out = *in
+	if in.Flags != nil {
+		in, out := &in.Flags, &out.Flags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ContextHeaders != nil {
+		in, out := &in.ContextHeaders, &out.ContextHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(ClientTLS)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureFlags.
+func (in *FeatureFlags) DeepCopy() *FeatureFlags {
+	if in == nil {
+		return nil
+	}
+	out := new(FeatureFlags)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ForwardAuth) DeepCopyInto(out *ForwardAuth) {
 	*out = *in
@@ -347,6 +447,110 @@ func (in *ForwardingTimeouts) DeepCopy() *ForwardingTimeouts {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GRPCReflection) DeepCopyInto(out *GRPCReflection) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GRPCReflection.
+func (in *GRPCReflection) DeepCopy() *GRPCReflection {
+	if in == nil {
+		return nil
+	}
+	out := new(GRPCReflection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GeoIP) DeepCopyInto(out *GeoIP) {
+	*out = *in
+	if in.DatabaseFiles != nil {
+		in, out := &in.DatabaseFiles, &out.DatabaseFiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedCountries != nil {
+		in, out := &in.AllowedCountries, &out.AllowedCountries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedCountries != nil {
+		in, out := &in.DeniedCountries, &out.DeniedCountries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedASNs != nil {
+		in, out := &in.AllowedASNs, &out.AllowedASNs
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedASNs != nil {
+		in, out := &in.DeniedASNs, &out.DeniedASNs
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPStrategy != nil {
+		in, out := &in.IPStrategy, &out.IPStrategy
+		*out = new(IPStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = new(GeoIPHeaders)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeoIP.
+func (in *GeoIP) DeepCopy() *GeoIP {
+	if in == nil {
+		return nil
+	}
+	out := new(GeoIP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GeoIPHeaders) DeepCopyInto(out *GeoIPHeaders) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeoIPHeaders.
+func (in *GeoIPHeaders) DeepCopy() *GeoIPHeaders {
+	if in == nil {
+		return nil
+	}
+	out := new(GeoIPHeaders)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HMACAuth) DeepCopyInto(out *HMACAuth) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HMACAuth.
+func (in *HMACAuth) DeepCopy() *HMACAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(HMACAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPConfiguration) DeepCopyInto(out *HTTPConfiguration) {
 	*out = *in
@@ -510,6 +714,27 @@ func (in *Headers) DeepCopy() *Headers {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPCache) DeepCopyInto(out *HTTPCache) {
+	*out = *in
+	if in.KeyHeaders != nil {
+		in, out := &in.KeyHeaders, &out.KeyHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPCache.
+func (in *HTTPCache) DeepCopy() *HTTPCache {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HealthCheck) DeepCopyInto(out *HealthCheck) {
 	*out = *in
@@ -526,6 +751,74 @@ func (in *HealthCheck) DeepCopy() *HealthCheck {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Hedging) DeepCopyInto(out *Hedging) {
+	*out = *in
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]HedgingService, len(*in))
+		copy(*out, *in)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HealthCheck)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Hedging.
+func (in *Hedging) DeepCopy() *Hedging {
+	if in == nil {
+		return nil
+	}
+	out := new(Hedging)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HedgingService) DeepCopyInto(out *HedgingService) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HedgingService.
+func (in *HedgingService) DeepCopy() *HedgingService {
+	if in == nil {
+		return nil
+	}
+	out := new(HedgingService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPAllowList) DeepCopyInto(out *IPAllowList) {
+	*out = *in
+	if in.SourceRange != nil {
+		in, out := &in.SourceRange, &out.SourceRange
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPStrategy != nil {
+		in, out := &in.IPStrategy, &out.IPStrategy
+		*out = new(IPStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPAllowList.
+func (in *IPAllowList) DeepCopy() *IPAllowList {
+	if in == nil {
+		return nil
+	}
+	out := new(IPAllowList)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IPStrategy) DeepCopyInto(out *IPStrategy) {
 	*out = *in
@@ -595,47 +888,113 @@ func (in *InFlightReq) DeepCopy() *InFlightReq {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Message) DeepCopyInto(out *Message) {
+func (in *LDAPAuth) DeepCopyInto(out *LDAPAuth) {
 	*out = *in
-	if in.Configuration != nil {
-		in, out := &in.Configuration, &out.Configuration
-		*out = new(Configuration)
-		(*in).DeepCopyInto(*out)
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(ClientTLS)
+		**out = **in
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Message.
-func (in *Message) DeepCopy() *Message {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPAuth.
+func (in *LDAPAuth) DeepCopy() *LDAPAuth {
 	if in == nil {
 		return nil
 	}
-	out := new(Message)
+	out := new(LDAPAuth)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Middleware) DeepCopyInto(out *Middleware) {
+func (in *Maintenance) DeepCopyInto(out *Maintenance) {
 	*out = *in
-	if in.AddPrefix != nil {
-		in, out := &in.AddPrefix, &out.AddPrefix
-		*out = new(AddPrefix)
-		**out = **in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	if in.StripPrefix != nil {
-		in, out := &in.StripPrefix, &out.StripPrefix
-		*out = new(StripPrefix)
-		(*in).DeepCopyInto(*out)
+	if in.BypassSourceRange != nil {
+		in, out := &in.BypassSourceRange, &out.BypassSourceRange
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.StripPrefixRegex != nil {
-		in, out := &in.StripPrefixRegex, &out.StripPrefixRegex
-		*out = new(StripPrefixRegex)
+	if in.IPStrategy != nil {
+		in, out := &in.IPStrategy, &out.IPStrategy
+		*out = new(IPStrategy)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.ReplacePath != nil {
-		in, out := &in.ReplacePath, &out.ReplacePath
-		*out = new(ReplacePath)
+	if in.BypassHeaders != nil {
+		in, out := &in.BypassHeaders, &out.BypassHeaders
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Maintenance.
+func (in *Maintenance) DeepCopy() *Maintenance {
+	if in == nil {
+		return nil
+	}
+	out := new(Maintenance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Message) DeepCopyInto(out *Message) {
+	*out = *in
+	if in.Configuration != nil {
+		in, out := &in.Configuration, &out.Configuration
+		*out = new(Configuration)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Message.
+func (in *Message) DeepCopy() *Message {
+	if in == nil {
+		return nil
+	}
+	out := new(Message)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Middleware) DeepCopyInto(out *Middleware) {
+	*out = *in
+	if in.AddPrefix != nil {
+		in, out := &in.AddPrefix, &out.AddPrefix
+		*out = new(AddPrefix)
+		**out = **in
+	}
+	if in.StripPrefix != nil {
+		in, out := &in.StripPrefix, &out.StripPrefix
+		*out = new(StripPrefix)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StripPrefixRegex != nil {
+		in, out := &in.StripPrefixRegex, &out.StripPrefixRegex
+		*out = new(StripPrefixRegex)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplacePath != nil {
+		in, out := &in.ReplacePath, &out.ReplacePath
+		*out = new(ReplacePath)
 		**out = **in
 	}
 	if in.ReplacePathRegex != nil {
@@ -653,11 +1012,36 @@ func (in *Middleware) DeepCopyInto(out *Middleware) {
 		*out = new(IPWhiteList)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.IPAllowList != nil {
+		in, out := &in.IPAllowList, &out.IPAllowList
+		*out = new(IPAllowList)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GeoIP != nil {
+		in, out := &in.GeoIP, &out.GeoIP
+		*out = new(GeoIP)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BotBlocker != nil {
+		in, out := &in.BotBlocker, &out.BotBlocker
+		*out = new(BotBlocker)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Headers != nil {
 		in, out := &in.Headers, &out.Headers
 		*out = new(Headers)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.StripHeaders != nil {
+		in, out := &in.StripHeaders, &out.StripHeaders
+		*out = new(StripHeaders)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTPCache != nil {
+		in, out := &in.HTTPCache, &out.HTTPCache
+		*out = new(HTTPCache)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Errors != nil {
 		in, out := &in.Errors, &out.Errors
 		*out = new(ErrorPage)
@@ -693,11 +1077,31 @@ func (in *Middleware) DeepCopyInto(out *Middleware) {
 		*out = new(ForwardAuth)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HMACAuth != nil {
+		in, out := &in.HMACAuth, &out.HMACAuth
+		*out = new(HMACAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LDAPAuth != nil {
+		in, out := &in.LDAPAuth, &out.LDAPAuth
+		*out = new(LDAPAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OIDCAuth != nil {
+		in, out := &in.OIDCAuth, &out.OIDCAuth
+		*out = new(OIDCAuth)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.InFlightReq != nil {
 		in, out := &in.InFlightReq, &out.InFlightReq
 		*out = new(InFlightReq)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(Maintenance)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Buffering != nil {
 		in, out := &in.Buffering, &out.Buffering
 		*out = new(Buffering)
@@ -721,13 +1125,38 @@ func (in *Middleware) DeepCopyInto(out *Middleware) {
 	if in.Retry != nil {
 		in, out := &in.Retry, &out.Retry
 		*out = new(Retry)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.ContentType != nil {
 		in, out := &in.ContentType, &out.ContentType
 		*out = new(ContentType)
 		**out = **in
 	}
+	if in.FeatureFlags != nil {
+		in, out := &in.FeatureFlags, &out.FeatureFlags
+		*out = new(FeatureFlags)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequestCollapsing != nil {
+		in, out := &in.RequestCollapsing, &out.RequestCollapsing
+		*out = new(RequestCollapsing)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequestID != nil {
+		in, out := &in.RequestID, &out.RequestID
+		*out = new(RequestID)
+		**out = **in
+	}
+	if in.RewriteBody != nil {
+		in, out := &in.RewriteBody, &out.RewriteBody
+		*out = new(RewriteBody)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ThreatIntel != nil {
+		in, out := &in.ThreatIntel, &out.ThreatIntel
+		*out = new(ThreatIntel)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Plugin != nil {
 		in, out := &in.Plugin, &out.Plugin
 		*out = make(map[string]PluginConf, len(*in))
@@ -785,192 +1214,508 @@ func (in *Mirroring) DeepCopyInto(out *Mirroring) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Mirroring.
-func (in *Mirroring) DeepCopy() *Mirroring {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Mirroring.
+func (in *Mirroring) DeepCopy() *Mirroring {
+	if in == nil {
+		return nil
+	}
+	out := new(Mirroring)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Model) DeepCopyInto(out *Model) {
+	*out = *in
+	if in.Middlewares != nil {
+		in, out := &in.Middlewares, &out.Middlewares
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(RouterTLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SNICertResolvers != nil {
+		in, out := &in.SNICertResolvers, &out.SNICertResolvers
+		*out = make([]SNICertResolver, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Model.
+func (in *Model) DeepCopy() *Model {
+	if in == nil {
+		return nil
+	}
+	out := new(Model)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCAuth) DeepCopyInto(out *OIDCAuth) {
+	*out = *in
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClaimHeaders != nil {
+		in, out := &in.ClaimHeaders, &out.ClaimHeaders
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(ClientTLS)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCAuth.
+func (in *OIDCAuth) DeepCopy() *OIDCAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenAPI) DeepCopyInto(out *OpenAPI) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAPI.
+func (in *OpenAPI) DeepCopy() *OpenAPI {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenAPI)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PassTLSClientCert) DeepCopyInto(out *PassTLSClientCert) {
+	*out = *in
+	if in.Info != nil {
+		in, out := &in.Info, &out.Info
+		*out = new(TLSClientCertificateInfo)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PassTLSClientCert.
+func (in *PassTLSClientCert) DeepCopy() *PassTLSClientCert {
+	if in == nil {
+		return nil
+	}
+	out := new(PassTLSClientCert)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Priority) DeepCopyInto(out *Priority) {
+	*out = *in
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]PriorityService, len(*in))
+		copy(*out, *in)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HealthCheck)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Priority.
+func (in *Priority) DeepCopy() *Priority {
+	if in == nil {
+		return nil
+	}
+	out := new(Priority)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PriorityService) DeepCopyInto(out *PriorityService) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PriorityService.
+func (in *PriorityService) DeepCopy() *PriorityService {
+	if in == nil {
+		return nil
+	}
+	out := new(PriorityService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyProtocol) DeepCopyInto(out *ProxyProtocol) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyProtocol.
+func (in *ProxyProtocol) DeepCopy() *ProxyProtocol {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyProtocol)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Provenance) DeepCopyInto(out *Provenance) {
+	*out = *in
+	if in.Routers != nil {
+		in, out := &in.Routers, &out.Routers
+		*out = make(map[string][]Source, len(*in))
+		for key, val := range *in {
+			var outVal []Source
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]Source, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Middlewares != nil {
+		in, out := &in.Middlewares, &out.Middlewares
+		*out = make(map[string][]Source, len(*in))
+		for key, val := range *in {
+			var outVal []Source
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]Source, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make(map[string][]Source, len(*in))
+		for key, val := range *in {
+			var outVal []Source
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]Source, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.TCPRouters != nil {
+		in, out := &in.TCPRouters, &out.TCPRouters
+		*out = make(map[string][]Source, len(*in))
+		for key, val := range *in {
+			var outVal []Source
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]Source, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.TCPMiddlewares != nil {
+		in, out := &in.TCPMiddlewares, &out.TCPMiddlewares
+		*out = make(map[string][]Source, len(*in))
+		for key, val := range *in {
+			var outVal []Source
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]Source, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.TCPServices != nil {
+		in, out := &in.TCPServices, &out.TCPServices
+		*out = make(map[string][]Source, len(*in))
+		for key, val := range *in {
+			var outVal []Source
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]Source, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.UDPRouters != nil {
+		in, out := &in.UDPRouters, &out.UDPRouters
+		*out = make(map[string][]Source, len(*in))
+		for key, val := range *in {
+			var outVal []Source
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]Source, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.UDPServices != nil {
+		in, out := &in.UDPServices, &out.UDPServices
+		*out = make(map[string][]Source, len(*in))
+		for key, val := range *in {
+			var outVal []Source
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]Source, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Provenance.
+func (in *Provenance) DeepCopy() *Provenance {
+	if in == nil {
+		return nil
+	}
+	out := new(Provenance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimit) DeepCopyInto(out *RateLimit) {
+	*out = *in
+	if in.SourceCriterion != nil {
+		in, out := &in.SourceCriterion, &out.SourceCriterion
+		*out = new(SourceCriterion)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimit.
+func (in *RateLimit) DeepCopy() *RateLimit {
 	if in == nil {
 		return nil
 	}
-	out := new(Mirroring)
+	out := new(RateLimit)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Model) DeepCopyInto(out *Model) {
+func (in *RedirectRegex) DeepCopyInto(out *RedirectRegex) {
 	*out = *in
-	if in.Middlewares != nil {
-		in, out := &in.Middlewares, &out.Middlewares
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.TLS != nil {
-		in, out := &in.TLS, &out.TLS
-		*out = new(RouterTLSConfig)
-		(*in).DeepCopyInto(*out)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Model.
-func (in *Model) DeepCopy() *Model {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedirectRegex.
+func (in *RedirectRegex) DeepCopy() *RedirectRegex {
 	if in == nil {
 		return nil
 	}
-	out := new(Model)
+	out := new(RedirectRegex)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PassTLSClientCert) DeepCopyInto(out *PassTLSClientCert) {
+func (in *RedirectScheme) DeepCopyInto(out *RedirectScheme) {
 	*out = *in
-	if in.Info != nil {
-		in, out := &in.Info, &out.Info
-		*out = new(TLSClientCertificateInfo)
-		(*in).DeepCopyInto(*out)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PassTLSClientCert.
-func (in *PassTLSClientCert) DeepCopy() *PassTLSClientCert {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedirectScheme.
+func (in *RedirectScheme) DeepCopy() *RedirectScheme {
 	if in == nil {
 		return nil
 	}
-	out := new(PassTLSClientCert)
+	out := new(RedirectScheme)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProxyProtocol) DeepCopyInto(out *ProxyProtocol) {
+func (in *ReplacePath) DeepCopyInto(out *ReplacePath) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyProtocol.
-func (in *ProxyProtocol) DeepCopy() *ProxyProtocol {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplacePath.
+func (in *ReplacePath) DeepCopy() *ReplacePath {
 	if in == nil {
 		return nil
 	}
-	out := new(ProxyProtocol)
+	out := new(ReplacePath)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RateLimit) DeepCopyInto(out *RateLimit) {
+func (in *ReplacePathRegex) DeepCopyInto(out *ReplacePathRegex) {
 	*out = *in
-	if in.SourceCriterion != nil {
-		in, out := &in.SourceCriterion, &out.SourceCriterion
-		*out = new(SourceCriterion)
-		(*in).DeepCopyInto(*out)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimit.
-func (in *RateLimit) DeepCopy() *RateLimit {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplacePathRegex.
+func (in *ReplacePathRegex) DeepCopy() *ReplacePathRegex {
 	if in == nil {
 		return nil
 	}
-	out := new(RateLimit)
+	out := new(ReplacePathRegex)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RedirectRegex) DeepCopyInto(out *RedirectRegex) {
+func (in *ResponseForwarding) DeepCopyInto(out *ResponseForwarding) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedirectRegex.
-func (in *RedirectRegex) DeepCopy() *RedirectRegex {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResponseForwarding.
+func (in *ResponseForwarding) DeepCopy() *ResponseForwarding {
 	if in == nil {
 		return nil
 	}
-	out := new(RedirectRegex)
+	out := new(ResponseForwarding)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RedirectScheme) DeepCopyInto(out *RedirectScheme) {
+func (in *RequestCollapsing) DeepCopyInto(out *RequestCollapsing) {
 	*out = *in
+	if in.KeyHeaders != nil {
+		in, out := &in.KeyHeaders, &out.KeyHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedirectScheme.
-func (in *RedirectScheme) DeepCopy() *RedirectScheme {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequestCollapsing.
+func (in *RequestCollapsing) DeepCopy() *RequestCollapsing {
 	if in == nil {
 		return nil
 	}
-	out := new(RedirectScheme)
+	out := new(RequestCollapsing)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReplacePath) DeepCopyInto(out *ReplacePath) {
+func (in *RequestID) DeepCopyInto(out *RequestID) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplacePath.
-func (in *ReplacePath) DeepCopy() *ReplacePath {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequestID.
+func (in *RequestID) DeepCopy() *RequestID {
 	if in == nil {
 		return nil
 	}
-	out := new(ReplacePath)
+	out := new(RequestID)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReplacePathRegex) DeepCopyInto(out *ReplacePathRegex) {
+func (in *Retry) DeepCopyInto(out *Retry) {
 	*out = *in
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplacePathRegex.
-func (in *ReplacePathRegex) DeepCopy() *ReplacePathRegex {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Retry.
+func (in *Retry) DeepCopy() *Retry {
 	if in == nil {
 		return nil
 	}
-	out := new(ReplacePathRegex)
+	out := new(Retry)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResponseForwarding) DeepCopyInto(out *ResponseForwarding) {
+func (in *RewriteBody) DeepCopyInto(out *RewriteBody) {
 	*out = *in
+	if in.Rewrites != nil {
+		in, out := &in.Rewrites, &out.Rewrites
+		*out = make([]RewriteBodyRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.ContentTypes != nil {
+		in, out := &in.ContentTypes, &out.ContentTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResponseForwarding.
-func (in *ResponseForwarding) DeepCopy() *ResponseForwarding {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RewriteBody.
+func (in *RewriteBody) DeepCopy() *RewriteBody {
 	if in == nil {
 		return nil
 	}
-	out := new(ResponseForwarding)
+	out := new(RewriteBody)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Retry) DeepCopyInto(out *Retry) {
+func (in *RewriteBodyRule) DeepCopyInto(out *RewriteBodyRule) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Retry.
-func (in *Retry) DeepCopy() *Retry {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RewriteBodyRule.
+func (in *RewriteBodyRule) DeepCopy() *RewriteBodyRule {
 	if in == nil {
 		return nil
 	}
-	out := new(Retry)
+	out := new(RewriteBodyRule)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -988,6 +1733,13 @@ func (in *Router) DeepCopyInto(out *Router) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.InlineMiddlewares != nil {
+		in, out := &in.InlineMiddlewares, &out.InlineMiddlewares
+		*out = make([]Middleware, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.TLS != nil {
 		in, out := &in.TLS, &out.TLS
 		*out = new(RouterTLSConfig)
@@ -1052,6 +1804,22 @@ func (in *RouterTLSConfig) DeepCopy() *RouterTLSConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SNICertResolver) DeepCopyInto(out *SNICertResolver) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SNICertResolver.
+func (in *SNICertResolver) DeepCopy() *SNICertResolver {
+	if in == nil {
+		return nil
+	}
+	out := new(SNICertResolver)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Server) DeepCopyInto(out *Server) {
 	*out = *in
@@ -1124,6 +1892,16 @@ func (in *ServersLoadBalancer) DeepCopyInto(out *ServersLoadBalancer) {
 		*out = new(ResponseForwarding)
 		**out = **in
 	}
+	if in.OpenAPI != nil {
+		in, out := &in.OpenAPI, &out.OpenAPI
+		*out = new(OpenAPI)
+		**out = **in
+	}
+	if in.GRPCReflection != nil {
+		in, out := &in.GRPCReflection, &out.GRPCReflection
+		*out = new(GRPCReflection)
+		**out = **in
+	}
 	return
 }
 
@@ -1186,6 +1964,16 @@ func (in *Service) DeepCopyInto(out *Service) {
 		*out = new(Mirroring)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(Priority)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hedging != nil {
+		in, out := &in.Hedging, &out.Hedging
+		*out = new(Hedging)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1220,6 +2008,22 @@ func (in *SourceCriterion) DeepCopy() *SourceCriterion {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Source) DeepCopyInto(out *Source) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Source.
+func (in *Source) DeepCopy() *Source {
+	if in == nil {
+		return nil
+	}
+	out := new(Source)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Sticky) DeepCopyInto(out *Sticky) {
 	*out = *in
@@ -1241,6 +2045,37 @@ func (in *Sticky) DeepCopy() *Sticky {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StripHeaders) DeepCopyInto(out *StripHeaders) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TrustedIPs != nil {
+		in, out := &in.TrustedIPs, &out.TrustedIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IPStrategy != nil {
+		in, out := &in.IPStrategy, &out.IPStrategy
+		*out = new(IPStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StripHeaders.
+func (in *StripHeaders) DeepCopy() *StripHeaders {
+	if in == nil {
+		return nil
+	}
+	out := new(StripHeaders)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StripPrefix) DeepCopyInto(out *StripPrefix) {
 	*out = *in
@@ -1344,6 +2179,27 @@ func (in *TCPConfiguration) DeepCopy() *TCPConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPIPAllowList) DeepCopyInto(out *TCPIPAllowList) {
+	*out = *in
+	if in.SourceRange != nil {
+		in, out := &in.SourceRange, &out.SourceRange
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPIPAllowList.
+func (in *TCPIPAllowList) DeepCopy() *TCPIPAllowList {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPIPAllowList)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TCPIPWhiteList) DeepCopyInto(out *TCPIPWhiteList) {
 	*out = *in
@@ -1373,6 +2229,11 @@ func (in *TCPMiddleware) DeepCopyInto(out *TCPMiddleware) {
 		*out = new(TCPIPWhiteList)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.IPAllowList != nil {
+		in, out := &in.IPAllowList, &out.IPAllowList
+		*out = new(TCPIPAllowList)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1477,6 +2338,11 @@ func (in *TCPService) DeepCopyInto(out *TCPService) {
 		*out = new(TCPWeightedRoundRobin)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Tunnel != nil {
+		in, out := &in.Tunnel, &out.Tunnel
+		*out = new(TCPTunnel)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1490,6 +2356,48 @@ func (in *TCPService) DeepCopy() *TCPService {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPTunnel) DeepCopyInto(out *TCPTunnel) {
+	*out = *in
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]TCPTunnelService, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tokens != nil {
+		in, out := &in.Tokens, &out.Tokens
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPTunnel.
+func (in *TCPTunnel) DeepCopy() *TCPTunnel {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPTunnel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPTunnelService) DeepCopyInto(out *TCPTunnelService) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPTunnelService.
+func (in *TCPTunnelService) DeepCopy() *TCPTunnelService {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPTunnelService)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TCPWRRService) DeepCopyInto(out *TCPWRRService) {
 	*out = *in
@@ -1633,6 +2541,39 @@ func (in *TLSConfiguration) DeepCopy() *TLSConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ThreatIntel) DeepCopyInto(out *ThreatIntel) {
+	*out = *in
+	if in.IPStrategy != nil {
+		in, out := &in.IPStrategy, &out.IPStrategy
+		*out = new(IPStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(ClientTLS)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ThreatIntel.
+func (in *ThreatIntel) DeepCopy() *ThreatIntel {
+	if in == nil {
+		return nil
+	}
+	out := new(ThreatIntel)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UDPConfiguration) DeepCopyInto(out *UDPConfiguration) {
 	*out = *in
@@ -35,12 +35,17 @@ func NewConfig(conf dynamic.Configuration) *Configuration {
 
 	runtimeConfig := &Configuration{}
 
+	var provenance dynamic.Provenance
+	if conf.Provenance != nil {
+		provenance = *conf.Provenance
+	}
+
 	if conf.HTTP != nil {
 		routers := conf.HTTP.Routers
 		if len(routers) > 0 {
 			runtimeConfig.Routers = make(map[string]*RouterInfo, len(routers))
 			for k, v := range routers {
-				runtimeConfig.Routers[k] = &RouterInfo{Router: v, Status: StatusEnabled}
+				runtimeConfig.Routers[k] = &RouterInfo{Router: v, Status: StatusEnabled, Sources: provenance.Routers[k]}
 			}
 		}
 
@@ -48,7 +53,7 @@ func NewConfig(conf dynamic.Configuration) *Configuration {
 		if len(services) > 0 {
 			runtimeConfig.Services = make(map[string]*ServiceInfo, len(services))
 			for k, v := range services {
-				runtimeConfig.Services[k] = &ServiceInfo{Service: v, Status: StatusEnabled}
+				runtimeConfig.Services[k] = &ServiceInfo{Service: v, Status: StatusEnabled, Sources: provenance.Services[k]}
 			}
 		}
 
@@ -56,7 +61,7 @@ func NewConfig(conf dynamic.Configuration) *Configuration {
 		if len(middlewares) > 0 {
 			runtimeConfig.Middlewares = make(map[string]*MiddlewareInfo, len(middlewares))
 			for k, v := range middlewares {
-				runtimeConfig.Middlewares[k] = &MiddlewareInfo{Middleware: v, Status: StatusEnabled}
+				runtimeConfig.Middlewares[k] = &MiddlewareInfo{Middleware: v, Status: StatusEnabled, Sources: provenance.Middlewares[k]}
 			}
 		}
 	}
@@ -65,21 +70,21 @@ func NewConfig(conf dynamic.Configuration) *Configuration {
 		if len(conf.TCP.Routers) > 0 {
 			runtimeConfig.TCPRouters = make(map[string]*TCPRouterInfo, len(conf.TCP.Routers))
 			for k, v := range conf.TCP.Routers {
-				runtimeConfig.TCPRouters[k] = &TCPRouterInfo{TCPRouter: v, Status: StatusEnabled}
+				runtimeConfig.TCPRouters[k] = &TCPRouterInfo{TCPRouter: v, Status: StatusEnabled, Sources: provenance.TCPRouters[k]}
 			}
 		}
 
 		if len(conf.TCP.Services) > 0 {
 			runtimeConfig.TCPServices = make(map[string]*TCPServiceInfo, len(conf.TCP.Services))
 			for k, v := range conf.TCP.Services {
-				runtimeConfig.TCPServices[k] = &TCPServiceInfo{TCPService: v, Status: StatusEnabled}
+				runtimeConfig.TCPServices[k] = &TCPServiceInfo{TCPService: v, Status: StatusEnabled, Sources: provenance.TCPServices[k]}
 			}
 		}
 
 		if len(conf.TCP.Middlewares) > 0 {
 			runtimeConfig.TCPMiddlewares = make(map[string]*TCPMiddlewareInfo, len(conf.TCP.Middlewares))
 			for k, v := range conf.TCP.Middlewares {
-				runtimeConfig.TCPMiddlewares[k] = &TCPMiddlewareInfo{TCPMiddleware: v, Status: StatusEnabled}
+				runtimeConfig.TCPMiddlewares[k] = &TCPMiddlewareInfo{TCPMiddleware: v, Status: StatusEnabled, Sources: provenance.TCPMiddlewares[k]}
 			}
 		}
 	}
@@ -88,14 +93,14 @@ func NewConfig(conf dynamic.Configuration) *Configuration {
 		if len(conf.UDP.Routers) > 0 {
 			runtimeConfig.UDPRouters = make(map[string]*UDPRouterInfo, len(conf.UDP.Routers))
 			for k, v := range conf.UDP.Routers {
-				runtimeConfig.UDPRouters[k] = &UDPRouterInfo{UDPRouter: v, Status: StatusEnabled}
+				runtimeConfig.UDPRouters[k] = &UDPRouterInfo{UDPRouter: v, Status: StatusEnabled, Sources: provenance.UDPRouters[k]}
 			}
 		}
 
 		if len(conf.UDP.Services) > 0 {
 			runtimeConfig.UDPServices = make(map[string]*UDPServiceInfo, len(conf.UDP.Services))
 			for k, v := range conf.UDP.Services {
-				runtimeConfig.UDPServices[k] = &UDPServiceInfo{UDPService: v, Status: StatusEnabled}
+				runtimeConfig.UDPServices[k] = &UDPServiceInfo{UDPService: v, Status: StatusEnabled, Sources: provenance.UDPServices[k]}
 			}
 		}
 	}
@@ -75,8 +75,30 @@ type RouterInfo struct {
 	// Status reports whether the router is disabled, in a warning state, or all good (enabled).
 	// If not in "enabled" state, the reason for it should be in the list of Err.
 	// It is the caller's responsibility to set the initial status.
-	Status string   `json:"status,omitempty"`
-	Using  []string `json:"using,omitempty"` // Effective entry points used by that router.
+	Status  string           `json:"status,omitempty"`
+	Using   []string         `json:"using,omitempty"`   // Effective entry points used by that router.
+	Sources []dynamic.Source `json:"sources,omitempty"` // Where the router comes from.
+
+	grpcMismatchesMu sync.RWMutex
+	grpcMismatches   []string
+}
+
+// SetGRPCMismatches sets the mismatches between the gRPC routes of the router,
+// and the gRPC services exposed by the servers of its service.
+func (r *RouterInfo) SetGRPCMismatches(mismatches []string) {
+	r.grpcMismatchesMu.Lock()
+	defer r.grpcMismatchesMu.Unlock()
+
+	r.grpcMismatches = mismatches
+}
+
+// GetGRPCMismatches returns the mismatches between the gRPC routes of the router,
+// and the gRPC services exposed by the servers of its service.
+func (r *RouterInfo) GetGRPCMismatches() []string {
+	r.grpcMismatchesMu.RLock()
+	defer r.grpcMismatchesMu.RUnlock()
+
+	return append([]string(nil), r.grpcMismatches...)
 }
 
 // AddError adds err to r.Err, if it does not already exist.
@@ -104,9 +126,10 @@ func (r *RouterInfo) AddError(err error, critical bool) {
 type MiddlewareInfo struct {
 	*dynamic.Middleware // dynamic configuration
 	// Err contains all the errors that occurred during service creation.
-	Err    []string `json:"error,omitempty"`
-	Status string   `json:"status,omitempty"`
-	UsedBy []string `json:"usedBy,omitempty"` // list of routers and services using that middleware.
+	Err     []string         `json:"error,omitempty"`
+	Status  string           `json:"status,omitempty"`
+	UsedBy  []string         `json:"usedBy,omitempty"`  // list of routers and services using that middleware.
+	Sources []dynamic.Source `json:"sources,omitempty"` // Where the middleware comes from.
 }
 
 // AddError adds err to s.Err, if it does not already exist.
@@ -138,8 +161,9 @@ type ServiceInfo struct {
 	// Status reports whether the service is disabled, in a warning state, or all good (enabled).
 	// If not in "enabled" state, the reason for it should be in the list of Err.
 	// It is the caller's responsibility to set the initial status.
-	Status string   `json:"status,omitempty"`
-	UsedBy []string `json:"usedBy,omitempty"` // list of routers using that service
+	Status  string           `json:"status,omitempty"`
+	UsedBy  []string         `json:"usedBy,omitempty"`  // list of routers using that service
+	Sources []dynamic.Source `json:"sources,omitempty"` // Where the service comes from.
 
 	serverStatusMu sync.RWMutex
 	serverStatus   map[string]string // keyed by server URL
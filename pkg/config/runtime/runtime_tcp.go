@@ -50,8 +50,9 @@ type TCPRouterInfo struct {
 	// Status reports whether the router is disabled, in a warning state, or all good (enabled).
 	// If not in "enabled" state, the reason for it should be in the list of Err.
 	// It is the caller's responsibility to set the initial status.
-	Status string   `json:"status,omitempty"`
-	Using  []string `json:"using,omitempty"` // Effective entry points used by that router.
+	Status  string           `json:"status,omitempty"`
+	Using   []string         `json:"using,omitempty"`   // Effective entry points used by that router.
+	Sources []dynamic.Source `json:"sources,omitempty"` // Where the router comes from.
 }
 
 // AddError adds err to r.Err, if it does not already exist.
@@ -82,8 +83,9 @@ type TCPServiceInfo struct {
 	// Status reports whether the service is disabled, in a warning state, or all good (enabled).
 	// If not in "enabled" state, the reason for it should be in the list of Err.
 	// It is the caller's responsibility to set the initial status.
-	Status string   `json:"status,omitempty"`
-	UsedBy []string `json:"usedBy,omitempty"` // list of routers using that service
+	Status  string           `json:"status,omitempty"`
+	UsedBy  []string         `json:"usedBy,omitempty"`  // list of routers using that service
+	Sources []dynamic.Source `json:"sources,omitempty"` // Where the service comes from.
 }
 
 // AddError adds err to s.Err, if it does not already exist.
@@ -111,9 +113,10 @@ func (s *TCPServiceInfo) AddError(err error, critical bool) {
 type TCPMiddlewareInfo struct {
 	*dynamic.TCPMiddleware // dynamic configuration
 	// Err contains all the errors that occurred during service creation.
-	Err    []string `json:"error,omitempty"`
-	Status string   `json:"status,omitempty"`
-	UsedBy []string `json:"usedBy,omitempty"` // list of TCP routers and services using that middleware.
+	Err     []string         `json:"error,omitempty"`
+	Status  string           `json:"status,omitempty"`
+	UsedBy  []string         `json:"usedBy,omitempty"`  // list of TCP routers and services using that middleware.
+	Sources []dynamic.Source `json:"sources,omitempty"` // Where the middleware comes from.
 }
 
 // AddError adds err to s.Err, if it does not already exist.
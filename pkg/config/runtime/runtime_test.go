@@ -688,3 +688,29 @@ func TestPopulateUsedBy(t *testing.T) {
 		})
 	}
 }
+
+func TestNewConfig_sources(t *testing.T) {
+	conf := dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"foo@myprovider": {Service: "bar@myprovider"},
+			},
+			Services: map[string]*dynamic.Service{
+				"bar@myprovider": {},
+			},
+		},
+		Provenance: &dynamic.Provenance{
+			Routers: map[string][]dynamic.Source{
+				"foo@myprovider": {{Provider: "myprovider", Kind: "Container", Name: "foo"}},
+			},
+		},
+	}
+
+	runtimeConfig := runtime.NewConfig(conf)
+
+	require.Contains(t, runtimeConfig.Routers, "foo@myprovider")
+	assert.Equal(t, []dynamic.Source{{Provider: "myprovider", Kind: "Container", Name: "foo"}}, runtimeConfig.Routers["foo@myprovider"].Sources)
+
+	require.Contains(t, runtimeConfig.Services, "bar@myprovider")
+	assert.Empty(t, runtimeConfig.Services["bar@myprovider"].Sources)
+}
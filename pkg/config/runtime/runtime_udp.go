@@ -56,8 +56,9 @@ type UDPRouterInfo struct {
 	// Status reports whether the router is disabled, in a warning state, or all good (enabled).
 	// If not in "enabled" state, the reason for it should be in the list of Err.
 	// It is the caller's responsibility to set the initial status.
-	Status string   `json:"status,omitempty"`
-	Using  []string `json:"using,omitempty"` // Effective entry points used by that router.
+	Status  string           `json:"status,omitempty"`
+	Using   []string         `json:"using,omitempty"`   // Effective entry points used by that router.
+	Sources []dynamic.Source `json:"sources,omitempty"` // Where the router comes from.
 }
 
 // AddError adds err to r.Err, if it does not already exist.
@@ -88,8 +89,9 @@ type UDPServiceInfo struct {
 	// Status reports whether the service is disabled, in a warning state, or all good (enabled).
 	// If not in "enabled" state, the reason for it should be in the list of Err.
 	// It is the caller's responsibility to set the initial status.
-	Status string   `json:"status,omitempty"`
-	UsedBy []string `json:"usedBy,omitempty"` // list of routers using that service
+	Status  string           `json:"status,omitempty"`
+	UsedBy  []string         `json:"usedBy,omitempty"`  // list of routers using that service
+	Sources []dynamic.Source `json:"sources,omitempty"` // Where the service comes from.
 }
 
 // AddError adds err to s.Err, if it does not already exist.
@@ -0,0 +1,213 @@
+package runtime
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+// Health check defaults, as applied by the service manager.
+const (
+	defaultHealthCheckInterval = 30 * time.Second
+	defaultHealthCheckTimeout  = 5 * time.Second
+)
+
+// defaultServersTransport is the name of the servers transport used by the services which do not reference one.
+const defaultServersTransport = "default@internal"
+
+// EntryPointTimeouts holds the responding timeouts of an entry point which are checked against the other timeouts.
+type EntryPointTimeouts struct {
+	WriteTimeout time.Duration
+}
+
+// CheckTimeouts cross-checks the timeouts of the entry points, of the HTTP services and their servers transports,
+// of the health checks, and of the retry middlewares, and adds a warning to the routers and services
+// whose timeouts contradict each other, e.g. a backend allowed to respond after the response to the client timed out.
+// The routers without entry points are checked against all the given entry points.
+// It returns the contradictions found, sorted.
+func (c *Configuration) CheckTimeouts(entryPoints map[string]EntryPointTimeouts, serversTransports map[string]*dynamic.ServersTransport) []error {
+	if c == nil {
+		return nil
+	}
+
+	var errs []error
+
+	for serviceName, serviceInfo := range c.Services {
+		if err := checkHealthCheckTimeouts(serviceInfo.Service); err != nil {
+			err = fmt.Errorf("service %q: %w", serviceName, err)
+			serviceInfo.AddError(err, false)
+			errs = append(errs, err)
+		}
+	}
+
+	for routerName, routerInfo := range c.Routers {
+		providerName := getProviderName(routerName)
+
+		serviceName := qualifyName(providerName, routerInfo.Service)
+		responseHeaderTimeout := c.responseHeaderTimeout(serviceName, serversTransports, make(map[string]struct{}))
+		if responseHeaderTimeout <= 0 {
+			continue
+		}
+
+		attempts := c.retryAttempts(providerName, routerInfo.Middlewares, make(map[string]struct{}))
+
+		epNames := routerInfo.EntryPoints
+		if len(epNames) == 0 {
+			for epName := range entryPoints {
+				epNames = append(epNames, epName)
+			}
+			sort.Strings(epNames)
+		}
+
+		for _, epName := range epNames {
+			ep, ok := entryPoints[epName]
+			if !ok || ep.WriteTimeout <= 0 {
+				continue
+			}
+
+			var err error
+			switch {
+			case responseHeaderTimeout > ep.WriteTimeout:
+				err = fmt.Errorf("router %q: the response header timeout (%s) of service %q is longer than the write timeout (%s) of entry point %q, the response to the client can time out before the backend responds",
+					routerName, responseHeaderTimeout, serviceName, ep.WriteTimeout, epName)
+			case attempts > 1 && time.Duration(attempts)*responseHeaderTimeout > ep.WriteTimeout:
+				err = fmt.Errorf("router %q: %d retry attempts with the response header timeout (%s) of service %q can last longer than the write timeout (%s) of entry point %q",
+					routerName, attempts, responseHeaderTimeout, serviceName, ep.WriteTimeout, epName)
+			default:
+				continue
+			}
+
+			routerInfo.AddError(err, false)
+			errs = append(errs, err)
+		}
+	}
+
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Error() < errs[j].Error()
+	})
+
+	return errs
+}
+
+// checkHealthCheckTimeouts checks that the timeout of the health check of the given service is shorter than its interval.
+func checkHealthCheckTimeouts(service *dynamic.Service) error {
+	if service == nil || service.LoadBalancer == nil || service.LoadBalancer.HealthCheck == nil {
+		return nil
+	}
+
+	hc := service.LoadBalancer.HealthCheck
+	if hc.Path == "" {
+		return nil
+	}
+
+	interval := defaultHealthCheckInterval
+	if d, err := time.ParseDuration(hc.Interval); err == nil && d > 0 {
+		interval = d
+	}
+
+	timeout := defaultHealthCheckTimeout
+	if d, err := time.ParseDuration(hc.Timeout); err == nil && d > 0 {
+		timeout = d
+	}
+
+	if timeout < interval {
+		return nil
+	}
+
+	return fmt.Errorf("the health check timeout (%s) is not shorter than the health check interval (%s)", timeout, interval)
+}
+
+// responseHeaderTimeout returns the longest response header timeout of the servers transports
+// used by the given service, and by its children services.
+func (c *Configuration) responseHeaderTimeout(serviceName string, serversTransports map[string]*dynamic.ServersTransport, visited map[string]struct{}) time.Duration {
+	if _, ok := visited[serviceName]; ok {
+		return 0
+	}
+	visited[serviceName] = struct{}{}
+
+	serviceInfo, ok := c.Services[serviceName]
+	if !ok || serviceInfo.Service == nil {
+		return 0
+	}
+
+	providerName := getProviderName(serviceName)
+
+	var children []string
+	switch {
+	case serviceInfo.LoadBalancer != nil:
+		transportName := defaultServersTransport
+		if serviceInfo.LoadBalancer.ServersTransport != "" {
+			transportName = qualifyName(providerName, serviceInfo.LoadBalancer.ServersTransport)
+		}
+
+		transport, ok := serversTransports[transportName]
+		if !ok || transport == nil || transport.ForwardingTimeouts == nil {
+			return 0
+		}
+
+		return time.Duration(transport.ForwardingTimeouts.ResponseHeaderTimeout)
+
+	case serviceInfo.Weighted != nil:
+		for _, child := range serviceInfo.Weighted.Services {
+			children = append(children, child.Name)
+		}
+
+	case serviceInfo.Mirroring != nil:
+		children = append(children, serviceInfo.Mirroring.Service)
+
+	case serviceInfo.Priority != nil:
+		for _, child := range serviceInfo.Priority.Services {
+			children = append(children, child.Name)
+		}
+	}
+
+	var longest time.Duration
+	for _, child := range children {
+		if timeout := c.responseHeaderTimeout(qualifyName(providerName, child), serversTransports, visited); timeout > longest {
+			longest = timeout
+		}
+	}
+
+	return longest
+}
+
+// retryAttempts returns the number of attempts of the retry middleware among the given middlewares,
+// including the middlewares of chains, or zero if there is none.
+func (c *Configuration) retryAttempts(providerName string, middlewares []string, visited map[string]struct{}) int {
+	for _, name := range middlewares {
+		name = qualifyName(providerName, name)
+		if _, ok := visited[name]; ok {
+			continue
+		}
+		visited[name] = struct{}{}
+
+		midInfo, ok := c.Middlewares[name]
+		if !ok || midInfo.Middleware == nil {
+			continue
+		}
+
+		if midInfo.Retry != nil {
+			return midInfo.Retry.Attempts
+		}
+
+		if midInfo.Chain != nil {
+			if attempts := c.retryAttempts(getProviderName(name), midInfo.Chain.Middlewares, visited); attempts > 0 {
+				return attempts
+			}
+		}
+	}
+
+	return 0
+}
+
+// qualifyName returns the fully qualified name of the given element of the given provider,
+// or the element name as is when the provider is unknown.
+func qualifyName(providerName, elementName string) string {
+	if providerName == "" {
+		return elementName
+	}
+
+	return getQualifiedName(providerName, elementName)
+}
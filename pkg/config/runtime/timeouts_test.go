@@ -0,0 +1,172 @@
+package runtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestConfiguration_CheckTimeouts(t *testing.T) {
+	entryPoints := map[string]EntryPointTimeouts{
+		"web":       {WriteTimeout: 10 * time.Second},
+		"websecure": {},
+	}
+
+	serversTransports := map[string]*dynamic.ServersTransport{
+		"default@internal": {
+			ForwardingTimeouts: &dynamic.ForwardingTimeouts{ResponseHeaderTimeout: ptypes.Duration(5 * time.Second)},
+		},
+		"slow@myprovider": {
+			ForwardingTimeouts: &dynamic.ForwardingTimeouts{ResponseHeaderTimeout: ptypes.Duration(time.Minute)},
+		},
+	}
+
+	testCases := []struct {
+		desc             string
+		conf             dynamic.Configuration
+		expected         []string
+		expectedRouters  map[string][]string
+		expectedServices map[string][]string
+	}{
+		{
+			desc: "no contradiction",
+			conf: dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"foo@myprovider": {EntryPoints: []string{"web"}, Service: "foo"},
+					},
+					Services: map[string]*dynamic.Service{
+						"foo@myprovider": {LoadBalancer: &dynamic.ServersLoadBalancer{}},
+					},
+				},
+			},
+		},
+		{
+			desc: "response header timeout longer than the write timeout",
+			conf: dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"foo@myprovider": {EntryPoints: []string{"web", "websecure"}, Service: "foo"},
+					},
+					Services: map[string]*dynamic.Service{
+						"foo@myprovider": {LoadBalancer: &dynamic.ServersLoadBalancer{ServersTransport: "slow"}},
+					},
+				},
+			},
+			expected: []string{
+				`router "foo@myprovider": the response header timeout (1m0s) of service "foo@myprovider" is longer than the write timeout (10s) of entry point "web", the response to the client can time out before the backend responds`,
+			},
+			expectedRouters: map[string][]string{
+				"foo@myprovider": {
+					`router "foo@myprovider": the response header timeout (1m0s) of service "foo@myprovider" is longer than the write timeout (10s) of entry point "web", the response to the client can time out before the backend responds`,
+				},
+			},
+		},
+		{
+			desc: "router without entry points, through a weighted service",
+			conf: dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"foo@myprovider": {Service: "wrr"},
+					},
+					Services: map[string]*dynamic.Service{
+						"wrr@myprovider": {Weighted: &dynamic.WeightedRoundRobin{Services: []dynamic.WRRService{{Name: "foo"}, {Name: "bar"}}}},
+						"foo@myprovider": {LoadBalancer: &dynamic.ServersLoadBalancer{}},
+						"bar@myprovider": {LoadBalancer: &dynamic.ServersLoadBalancer{ServersTransport: "slow"}},
+					},
+				},
+			},
+			expected: []string{
+				`router "foo@myprovider": the response header timeout (1m0s) of service "wrr@myprovider" is longer than the write timeout (10s) of entry point "web", the response to the client can time out before the backend responds`,
+			},
+			expectedRouters: map[string][]string{
+				"foo@myprovider": {
+					`router "foo@myprovider": the response header timeout (1m0s) of service "wrr@myprovider" is longer than the write timeout (10s) of entry point "web", the response to the client can time out before the backend responds`,
+				},
+			},
+		},
+		{
+			desc: "retry attempts longer than the write timeout",
+			conf: dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"foo@myprovider": {EntryPoints: []string{"web"}, Service: "foo", Middlewares: []string{"chain"}},
+					},
+					Middlewares: map[string]*dynamic.Middleware{
+						"chain@myprovider": {Chain: &dynamic.Chain{Middlewares: []string{"retry"}}},
+						"retry@myprovider": {Retry: &dynamic.Retry{Attempts: 3}},
+					},
+					Services: map[string]*dynamic.Service{
+						"foo@myprovider": {LoadBalancer: &dynamic.ServersLoadBalancer{}},
+					},
+				},
+			},
+			expected: []string{
+				`router "foo@myprovider": 3 retry attempts with the response header timeout (5s) of service "foo@myprovider" can last longer than the write timeout (10s) of entry point "web"`,
+			},
+			expectedRouters: map[string][]string{
+				"foo@myprovider": {
+					`router "foo@myprovider": 3 retry attempts with the response header timeout (5s) of service "foo@myprovider" can last longer than the write timeout (10s) of entry point "web"`,
+				},
+			},
+		},
+		{
+			desc: "health check timeout longer than its interval",
+			conf: dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Services: map[string]*dynamic.Service{
+						"foo@myprovider": {LoadBalancer: &dynamic.ServersLoadBalancer{
+							HealthCheck: &dynamic.ServerHealthCheck{Path: "/health", Interval: "10s", Timeout: "20s"},
+						}},
+						"bar@myprovider": {LoadBalancer: &dynamic.ServersLoadBalancer{
+							HealthCheck: &dynamic.ServerHealthCheck{Path: "/health", Interval: "3s"},
+						}},
+						"baz@myprovider": {LoadBalancer: &dynamic.ServersLoadBalancer{
+							HealthCheck: &dynamic.ServerHealthCheck{Path: "/health", Interval: "10s", Timeout: "2s"},
+						}},
+					},
+				},
+			},
+			expected: []string{
+				`service "bar@myprovider": the health check timeout (5s) is not shorter than the health check interval (3s)`,
+				`service "foo@myprovider": the health check timeout (20s) is not shorter than the health check interval (10s)`,
+			},
+			expectedServices: map[string][]string{
+				"bar@myprovider": {`service "bar@myprovider": the health check timeout (5s) is not shorter than the health check interval (3s)`},
+				"foo@myprovider": {`service "foo@myprovider": the health check timeout (20s) is not shorter than the health check interval (10s)`},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rtConf := NewConfig(test.conf)
+
+			var errs []string
+			for _, err := range rtConf.CheckTimeouts(entryPoints, serversTransports) {
+				errs = append(errs, err.Error())
+			}
+			assert.Equal(t, test.expected, errs)
+
+			for name, routerInfo := range rtConf.Routers {
+				assert.Equal(t, test.expectedRouters[name], routerInfo.Err, name)
+				if len(test.expectedRouters[name]) > 0 {
+					assert.Equal(t, StatusWarning, routerInfo.Status, name)
+				}
+			}
+
+			for name, serviceInfo := range rtConf.Services {
+				assert.Equal(t, test.expectedServices[name], serviceInfo.Err, name)
+				if len(test.expectedServices[name]) > 0 {
+					assert.Equal(t, StatusWarning, serviceInfo.Status, name)
+				}
+			}
+		})
+	}
+}
@@ -3,8 +3,11 @@ package static
 import (
 	"fmt"
 	"math"
+	"strconv"
 	"strings"
+	"text/template"
 
+	"github.com/Masterminds/sprig/v3"
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/types"
 )
@@ -18,6 +21,47 @@ type EntryPoint struct {
 	HTTP             HTTPConfig            `description:"HTTP configuration." json:"http,omitempty" toml:"http,omitempty" yaml:"http,omitempty" export:"true"`
 	EnableHTTP3      bool                  `description:"Enable HTTP3." json:"enableHTTP3,omitempty" toml:"enableHTTP3,omitempty" yaml:"enableHTTP3,omitempty" export:"true"`
 	UDP              *UDPConfig            `description:"UDP configuration." json:"udp,omitempty" toml:"udp,omitempty" yaml:"udp,omitempty"`
+	TLSHandshakes    *TLSHandshakes        `description:"Rate limits of the TLS handshakes." json:"tlsHandshakes,omitempty" toml:"tlsHandshakes,omitempty" yaml:"tlsHandshakes,omitempty" export:"true"`
+	FairQueuing      *FairQueuing          `description:"Shares the concurrent requests slots fairly across source IPs." json:"fairQueuing,omitempty" toml:"fairQueuing,omitempty" yaml:"fairQueuing,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// InstanceIndexEnv is the name of the environment variable holding the index of the Traefik instance,
+// given as InstanceIndex to the entry point address templates.
+const InstanceIndexEnv = "TRAEFIK_INSTANCE_INDEX"
+
+// addressTemplateData is the data given to the entry point address templates.
+type addressTemplateData struct {
+	Name          string
+	InstanceIndex int
+}
+
+// renderAddress returns the address of the entry point with the given name,
+// rendered with the given instance index when it is a template.
+func (ep EntryPoint) renderAddress(name, instanceIndex string) (string, error) {
+	if !strings.Contains(ep.Address, "{{") {
+		return ep.Address, nil
+	}
+
+	data := addressTemplateData{Name: name}
+	if instanceIndex != "" {
+		index, err := strconv.Atoi(instanceIndex)
+		if err != nil {
+			return "", fmt.Errorf("invalid instance index %q: %w", instanceIndex, err)
+		}
+		data.InstanceIndex = index
+	}
+
+	tmpl, err := template.New(name).Funcs(sprig.TxtFuncMap()).Parse(ep.Address)
+	if err != nil {
+		return "", err
+	}
+
+	var address strings.Builder
+	if err := tmpl.Execute(&address, data); err != nil {
+		return "", err
+	}
+
+	return address.String(), nil
 }
 
 // GetAddress strips any potential protocol part of the address field of the
@@ -84,6 +128,48 @@ type TLSConfig struct {
 	Options      string         `description:"Default TLS options for the routers linked to the entry point." json:"options,omitempty" toml:"options,omitempty" yaml:"options,omitempty" export:"true"`
 	CertResolver string         `description:"Default certificate resolver for the routers linked to the entry point." json:"certResolver,omitempty" toml:"certResolver,omitempty" yaml:"certResolver,omitempty" export:"true"`
 	Domains      []types.Domain `description:"Default TLS domains for the routers linked to the entry point." json:"domains,omitempty" toml:"domains,omitempty" yaml:"domains,omitempty" export:"true"`
+
+	SNICertResolvers []SNICertResolver `description:"Certificate resolvers for the routers linked to the entry point, per server name pattern." json:"sniCertResolvers,omitempty" toml:"sniCertResolvers,omitempty" yaml:"sniCertResolvers,omitempty" export:"true"`
+}
+
+// SNICertResolver is the certificate resolver for the routers whose domains all match a server name pattern,
+// which overrides the default certificate resolver of the entry point.
+type SNICertResolver struct {
+	SNI          string `description:"Server name pattern, either a domain or a wildcard domain." json:"sni,omitempty" toml:"sni,omitempty" yaml:"sni,omitempty" export:"true"`
+	CertResolver string `description:"Certificate resolver for the routers whose domains match the pattern." json:"certResolver,omitempty" toml:"certResolver,omitempty" yaml:"certResolver,omitempty" export:"true"`
+}
+
+// TLSHandshakes holds the rate limits applied to the TLS handshakes of an entry point.
+// As a TLS handshake is far more expensive for the server than for the client,
+// these limits protect the entry point against handshake floods.
+type TLSHandshakes struct {
+	PerSourceIP   *HandshakeRateLimit `description:"Rate limit of the TLS handshakes per source IP." json:"perSourceIP,omitempty" toml:"perSourceIP,omitempty" yaml:"perSourceIP,omitempty" export:"true"`
+	PerServerName *HandshakeRateLimit `description:"Rate limit of the TLS handshakes per server name (SNI)." json:"perServerName,omitempty" toml:"perServerName,omitempty" yaml:"perServerName,omitempty" export:"true"`
+}
+
+// HandshakeRateLimit is a rate limit of TLS handshakes.
+type HandshakeRateLimit struct {
+	Average int64 `description:"Maximum average number of TLS handshakes per second." json:"average,omitempty" toml:"average,omitempty" yaml:"average,omitempty" export:"true"`
+	Burst   int64 `description:"Maximum number of TLS handshakes allowed in the same arbitrarily small period of time." json:"burst,omitempty" toml:"burst,omitempty" yaml:"burst,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (h *HandshakeRateLimit) SetDefaults() {
+	h.Burst = 1
+}
+
+// FairQueuing holds the fair queuing configuration of an entry point.
+// When all the concurrent requests slots are taken, the waiting requests are queued per source IP,
+// and the freed slots are given to the source IPs with the fewest requests in progress.
+type FairQueuing struct {
+	MaxConcurrentRequests int64 `description:"Maximum number of requests handled concurrently." json:"maxConcurrentRequests,omitempty" toml:"maxConcurrentRequests,omitempty" yaml:"maxConcurrentRequests,omitempty" export:"true"`
+	MaxQueuedRequests     int64 `description:"Maximum number of requests waiting for a slot, beyond which requests are rejected (0 means no limit)." json:"maxQueuedRequests,omitempty" toml:"maxQueuedRequests,omitempty" yaml:"maxQueuedRequests,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (f *FairQueuing) SetDefaults() {
+	f.MaxConcurrentRequests = 100
+	f.MaxQueuedRequests = 1000
 }
 
 // ForwardedHeaders Trust client forwarding headers.
@@ -65,3 +65,70 @@ func TestEntryPointProtocol(t *testing.T) {
 		})
 	}
 }
+
+func TestEntryPoint_renderAddress(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		address         string
+		instanceIndex   string
+		expectedAddress string
+		expectedError   bool
+	}{
+		{
+			desc:            "Without template",
+			address:         ":8000/udp",
+			instanceIndex:   "2",
+			expectedAddress: ":8000/udp",
+		},
+		{
+			desc:            "With instance index",
+			address:         ":{{ add 8000 .InstanceIndex }}/udp",
+			instanceIndex:   "2",
+			expectedAddress: ":8002/udp",
+		},
+		{
+			desc:            "Without instance index",
+			address:         ":{{ add 8000 .InstanceIndex }}",
+			expectedAddress: ":8000",
+		},
+		{
+			desc:            "With entry point name",
+			address:         "{{ .Name }}.local:80",
+			expectedAddress: "web.local:80",
+		},
+		{
+			desc:          "With invalid instance index",
+			address:       ":{{ add 8000 .InstanceIndex }}",
+			instanceIndex: "foo",
+			expectedError: true,
+		},
+		{
+			desc:          "With invalid template",
+			address:       ":{{ add 8000 .InstanceIndex",
+			expectedError: true,
+		},
+		{
+			desc:          "With unknown field",
+			address:       ":{{ .Port }}",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ep := EntryPoint{Address: test.address}
+
+			address, err := ep.renderAddress("web", test.instanceIndex)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedAddress, address)
+		})
+	}
+}
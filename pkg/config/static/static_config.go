@@ -1,8 +1,10 @@
 package static
 
 import (
+	"errors"
 	"fmt"
 	stdlog "log"
+	"os"
 	"strings"
 	"time"
 
@@ -16,8 +18,11 @@ import (
 	"github.com/traefik/traefik/v2/pkg/provider/consulcatalog"
 	"github.com/traefik/traefik/v2/pkg/provider/docker"
 	"github.com/traefik/traefik/v2/pkg/provider/ecs"
+	"github.com/traefik/traefik/v2/pkg/provider/eureka"
 	"github.com/traefik/traefik/v2/pkg/provider/file"
+	"github.com/traefik/traefik/v2/pkg/provider/grpc"
 	"github.com/traefik/traefik/v2/pkg/provider/http"
+	"github.com/traefik/traefik/v2/pkg/provider/kafka"
 	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd"
 	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/gateway"
 	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/ingress"
@@ -28,6 +33,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/provider/marathon"
 	"github.com/traefik/traefik/v2/pkg/provider/rancher"
 	"github.com/traefik/traefik/v2/pkg/provider/rest"
+	"github.com/traefik/traefik/v2/pkg/provider/vault"
 	"github.com/traefik/traefik/v2/pkg/tls"
 	"github.com/traefik/traefik/v2/pkg/tracing/datadog"
 	"github.com/traefik/traefik/v2/pkg/tracing/elastic"
@@ -69,14 +75,21 @@ type Configuration struct {
 	Metrics *types.Metrics `description:"Enable a metrics exporter." json:"metrics,omitempty" toml:"metrics,omitempty" yaml:"metrics,omitempty" export:"true"`
 	Ping    *ping.Handler  `description:"Enable ping." json:"ping,omitempty" toml:"ping,omitempty" yaml:"ping,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 
-	Log       *types.TraefikLog `description:"Traefik log settings." json:"log,omitempty" toml:"log,omitempty" yaml:"log,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
-	AccessLog *types.AccessLog  `description:"Access log settings." json:"accessLog,omitempty" toml:"accessLog,omitempty" yaml:"accessLog,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
-	Tracing   *Tracing          `description:"OpenTracing configuration." json:"tracing,omitempty" toml:"tracing,omitempty" yaml:"tracing,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Log         *types.TraefikLog  `description:"Traefik log settings." json:"log,omitempty" toml:"log,omitempty" yaml:"log,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	AccessLog   *types.AccessLog   `description:"Access log settings." json:"accessLog,omitempty" toml:"accessLog,omitempty" yaml:"accessLog,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	DecisionLog *types.DecisionLog `description:"Routing decision log settings." json:"decisionLog,omitempty" toml:"decisionLog,omitempty" yaml:"decisionLog,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Tracing     *Tracing           `description:"OpenTracing configuration." json:"tracing,omitempty" toml:"tracing,omitempty" yaml:"tracing,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 
 	HostResolver *types.HostResolverConfig `description:"Enable CNAME Flattening." json:"hostResolver,omitempty" toml:"hostResolver,omitempty" yaml:"hostResolver,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 
+	Runtime *types.Runtime `description:"Go runtime tuning options." json:"runtime,omitempty" toml:"runtime,omitempty" yaml:"runtime,omitempty" export:"true"`
+
 	CertificatesResolvers map[string]CertificateResolver `description:"Certificates resolvers configuration." json:"certificatesResolvers,omitempty" toml:"certificatesResolvers,omitempty" yaml:"certificatesResolvers,omitempty" export:"true"`
 
+	OCSP *tls.OCSPConfig `description:"Enable the stapling of the OCSP responses to the served certificates." json:"ocsp,omitempty" toml:"ocsp,omitempty" yaml:"ocsp,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+
+	SessionTickets *tls.SessionTicketsConfig `description:"Configure the keys encrypting the TLS session tickets." json:"sessionTickets,omitempty" toml:"sessionTickets,omitempty" yaml:"sessionTickets,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+
 	Pilot *Pilot `description:"Traefik Pilot configuration." json:"pilot,omitempty" toml:"pilot,omitempty" yaml:"pilot,omitempty" export:"true"`
 
 	Experimental *Experimental `description:"experimental features." json:"experimental,omitempty" toml:"experimental,omitempty" yaml:"experimental,omitempty" export:"true"`
@@ -84,7 +97,8 @@ type Configuration struct {
 
 // CertificateResolver contains the configuration for the different types of certificates resolver.
 type CertificateResolver struct {
-	ACME *acmeprovider.Configuration `description:"Enable ACME (Let's Encrypt): automatic SSL." json:"acme,omitempty" toml:"acme,omitempty" yaml:"acme,omitempty" export:"true"`
+	ACME  *acmeprovider.Configuration `description:"Enable ACME (Let's Encrypt): automatic SSL." json:"acme,omitempty" toml:"acme,omitempty" yaml:"acme,omitempty" export:"true"`
+	Vault *vault.Configuration        `description:"Enable Vault PKI: certificates issued by the PKI secrets engine of HashiCorp Vault." json:"vault,omitempty" toml:"vault,omitempty" yaml:"vault,omitempty" export:"true"`
 }
 
 // Global holds the global configuration.
@@ -103,9 +117,13 @@ type ServersTransport struct {
 
 // API holds the API configuration.
 type API struct {
-	Insecure  bool `description:"Activate API directly on the entryPoint named traefik." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
-	Dashboard bool `description:"Activate dashboard." json:"dashboard,omitempty" toml:"dashboard,omitempty" yaml:"dashboard,omitempty" export:"true"`
-	Debug     bool `description:"Enable additional endpoints for debugging and profiling." json:"debug,omitempty" toml:"debug,omitempty" yaml:"debug,omitempty" export:"true"`
+	Insecure    bool        `description:"Activate API directly on the entryPoint named traefik." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
+	Dashboard   bool        `description:"Activate dashboard." json:"dashboard,omitempty" toml:"dashboard,omitempty" yaml:"dashboard,omitempty" export:"true"`
+	Debug       bool        `description:"Enable additional endpoints for debugging and profiling." json:"debug,omitempty" toml:"debug,omitempty" yaml:"debug,omitempty" export:"true"`
+	Maintenance bool        `description:"Enable the endpoints to pause and resume providers, and to switch the maintenance middlewares." json:"maintenance,omitempty" toml:"maintenance,omitempty" yaml:"maintenance,omitempty" export:"true"`
+	Caches      bool        `description:"Enable the endpoints to inspect and purge the HTTP caches." json:"caches,omitempty" toml:"caches,omitempty" yaml:"caches,omitempty" export:"true"`
+	AuthBypass  *AuthBypass `description:"Enable the endpoints to bypass temporarily the auth middlewares." json:"authBypass,omitempty" toml:"authBypass,omitempty" yaml:"authBypass,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Usage       *Usage      `description:"Enable the endpoint reporting the traffic of the routers." json:"usage,omitempty" toml:"usage,omitempty" yaml:"usage,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	// TODO: Re-enable statistics
 	// Statistics      *types.Statistics `description:"Enable more detailed statistics." json:"statistics,omitempty" toml:"statistics,omitempty" yaml:"statistics,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
 	DashboardAssets *assetfs.AssetFS `json:"-" toml:"-" yaml:"-" label:"-" file:"-"`
@@ -116,6 +134,26 @@ func (a *API) SetDefaults() {
 	a.Dashboard = true
 }
 
+// AuthBypass holds the configuration of the emergency bypasses of the auth middlewares.
+type AuthBypass struct {
+	MaxDuration ptypes.Duration `description:"Maximum duration of a bypass." json:"maxDuration,omitempty" toml:"maxDuration,omitempty" yaml:"maxDuration,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (a *AuthBypass) SetDefaults() {
+	a.MaxDuration = ptypes.Duration(time.Hour)
+}
+
+// Usage holds the configuration of the usage reports of the routers.
+type Usage struct {
+	Period ptypes.Duration `description:"Duration of a usage report period." json:"period,omitempty" toml:"period,omitempty" yaml:"period,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (u *Usage) SetDefaults() {
+	u.Period = ptypes.Duration(time.Hour)
+}
+
 // RespondingTimeouts contains timeout configurations for incoming requests to the Traefik instance.
 type RespondingTimeouts struct {
 	ReadTimeout  ptypes.Duration `description:"ReadTimeout is the maximum duration for reading the entire request, including the body. If zero, no timeout is set." json:"readTimeout,omitempty" toml:"readTimeout,omitempty" yaml:"readTimeout,omitempty" export:"true"`
@@ -145,6 +183,7 @@ func (f *ForwardingTimeouts) SetDefaults() {
 type LifeCycle struct {
 	RequestAcceptGraceTimeout ptypes.Duration `description:"Duration to keep accepting requests before Traefik initiates the graceful shutdown procedure." json:"requestAcceptGraceTimeout,omitempty" toml:"requestAcceptGraceTimeout,omitempty" yaml:"requestAcceptGraceTimeout,omitempty" export:"true"`
 	GraceTimeOut              ptypes.Duration `description:"Duration to give active requests a chance to finish before Traefik stops." json:"graceTimeOut,omitempty" toml:"graceTimeOut,omitempty" yaml:"graceTimeOut,omitempty" export:"true"`
+	ShutdownPriority          int             `description:"Priority of the entry point in the shutdown procedure: the entry points with a higher priority are stopped before the others start their shutdown." json:"shutdownPriority,omitempty" toml:"shutdownPriority,omitempty" yaml:"shutdownPriority,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -173,6 +212,7 @@ func (t *Tracing) SetDefaults() {
 // Providers contains providers configuration.
 type Providers struct {
 	ProvidersThrottleDuration ptypes.Duration `description:"Backends throttle duration: minimum duration between 2 events from providers before applying a new configuration. It avoids unnecessary reloads if multiples events are sent in a short amount of time." json:"providersThrottleDuration,omitempty" toml:"providersThrottleDuration,omitempty" yaml:"providersThrottleDuration,omitempty" export:"true"`
+	GlobalThrottleDuration    ptypes.Duration `description:"Minimum duration between 2 applications of the configuration, whatever the provider. The configurations received from all the providers in the meantime are applied together. If zero, no global throttling is done." json:"globalThrottleDuration,omitempty" toml:"globalThrottleDuration,omitempty" yaml:"globalThrottleDuration,omitempty" export:"true"`
 
 	Docker            *docker.Provider        `description:"Enable Docker backend with default settings." json:"docker,omitempty" toml:"docker,omitempty" yaml:"docker,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
 	File              *file.Provider          `description:"Enable File backend with default settings." json:"file,omitempty" toml:"file,omitempty" yaml:"file,omitempty" export:"true"`
@@ -190,6 +230,9 @@ type Providers struct {
 	ZooKeeper *zk.Provider     `description:"Enable ZooKeeper backend with default settings." json:"zooKeeper,omitempty" toml:"zooKeeper,omitempty" yaml:"zooKeeper,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	Redis     *redis.Provider  `description:"Enable Redis backend with default settings." json:"redis,omitempty" toml:"redis,omitempty" yaml:"redis,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	HTTP      *http.Provider   `description:"Enable HTTP backend with default settings." json:"http,omitempty" toml:"http,omitempty" yaml:"http,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	GRPC      *grpc.Provider   `description:"Enable gRPC backend with default settings." json:"grpc,omitempty" toml:"grpc,omitempty" yaml:"grpc,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Kafka     *kafka.Provider  `description:"Enable Kafka backend with default settings." json:"kafka,omitempty" toml:"kafka,omitempty" yaml:"kafka,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Eureka    *eureka.Provider `description:"Enable Eureka backend with default settings." json:"eureka,omitempty" toml:"eureka,omitempty" yaml:"eureka,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 
 	Plugin map[string]PluginConf `description:"Plugins configuration." json:"plugin,omitempty" toml:"plugin,omitempty" yaml:"plugin,omitempty"`
 }
@@ -216,6 +259,13 @@ func (c *Configuration) SetEffectiveConfiguration() {
 		}
 	}
 
+	// Renders the entry point address templates, the invalid ones are reported by ValidateConfiguration.
+	for name, ep := range c.EntryPoints {
+		if address, err := ep.renderAddress(name, os.Getenv(InstanceIndexEnv)); err == nil {
+			ep.Address = address
+		}
+	}
+
 	if c.Providers.Docker != nil {
 		if c.Providers.Docker.SwarmModeRefreshSeconds <= 0 {
 			c.Providers.Docker.SwarmModeRefreshSeconds = ptypes.Duration(15 * time.Second)
@@ -280,8 +330,18 @@ func (c *Configuration) initACMEProvider() {
 
 // ValidateConfiguration validate that configuration is coherent.
 func (c *Configuration) ValidateConfiguration() error {
+	for name, ep := range c.EntryPoints {
+		if _, err := ep.renderAddress(name, os.Getenv(InstanceIndexEnv)); err != nil {
+			return fmt.Errorf("invalid address of entry point %q: %w", name, err)
+		}
+	}
+
 	var acmeEmail string
 	for name, resolver := range c.CertificatesResolvers {
+		if resolver.ACME != nil && resolver.Vault != nil {
+			return fmt.Errorf("unable to initialize certificates resolver %q, only one of acme and vault can be set", name)
+		}
+
 		if resolver.ACME == nil {
 			continue
 		}
@@ -296,6 +356,10 @@ func (c *Configuration) ValidateConfiguration() error {
 		acmeEmail = resolver.ACME.Email
 	}
 
+	if c.API != nil && c.API.Usage != nil && c.API.Usage.Period <= 0 {
+		return errors.New("the period of the API usage reports must be positive")
+	}
+
 	return nil
 }
 
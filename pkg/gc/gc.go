@@ -0,0 +1,84 @@
+// Package gc applies the Go runtime tuning options, and reports the garbage collection pauses.
+package gc
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+// ballast is never read, it only grows the heap to delay the garbage collections.
+// Since it is never written either, its pages are not backed by physical memory.
+var ballast []byte
+
+// Configure applies the given runtime tuning options.
+func Configure(config *types.Runtime) {
+	if config == nil {
+		return
+	}
+
+	logger := log.WithoutContext()
+
+	if config.GCPercent != 0 {
+		previous := debug.SetGCPercent(config.GCPercent)
+		logger.Infof("Garbage collection target percentage set to %d (was %d)", config.GCPercent, previous)
+	}
+
+	if config.MemoryLimit > 0 {
+		if err := setMemoryLimit(config.MemoryLimit); err != nil {
+			logger.Errorf("Cannot set the soft memory limit: %v", err)
+		} else {
+			logger.Infof("Soft memory limit set to %d bytes", config.MemoryLimit)
+		}
+	}
+
+	if config.Ballast > 0 {
+		ballast = make([]byte, config.Ballast)
+		logger.Infof("Memory ballast of %d bytes allocated", len(ballast))
+	}
+}
+
+// ReportPauses observes the garbage collection pauses on the given registry, every interval, until the context is done.
+func ReportPauses(ctx context.Context, registry metrics.Registry, interval time.Duration) {
+	histogram := registry.GCPauseDurationHistogram()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var stats debug.GCStats
+	debug.ReadGCStats(&stats)
+	numGC := stats.NumGC
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			debug.ReadGCStats(&stats)
+			numGC = observePauses(histogram, stats, numGC)
+		}
+	}
+}
+
+// observePauses observes the pauses of the garbage collections which happened since the given number of collections,
+// and returns the current number of collections.
+// Only the most recent pauses are kept by the runtime, so the older ones can be missed if the interval is too long.
+func observePauses(histogram metrics.ScalableHistogram, stats debug.GCStats, numGC int64) int64 {
+	count := stats.NumGC - numGC
+	if count > int64(len(stats.Pause)) {
+		count = int64(len(stats.Pause))
+	}
+
+	now := time.Now()
+	// The pauses are ordered from the most recent to the oldest.
+	for i := count - 1; i >= 0; i-- {
+		// The histograms only observe durations from a start time, in their own unit.
+		histogram.ObserveFromStart(now.Add(-stats.Pause[i]))
+	}
+
+	return stats.NumGC
+}
@@ -0,0 +1,69 @@
+package gc
+
+import (
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+)
+
+type histogramMock struct {
+	durations []time.Duration
+}
+
+func (h *histogramMock) With(...string) metrics.ScalableHistogram {
+	return h
+}
+
+func (h *histogramMock) Observe(float64) {}
+
+func (h *histogramMock) ObserveFromStart(start time.Time) {
+	h.durations = append(h.durations, time.Since(start).Round(time.Millisecond))
+}
+
+func Test_observePauses(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		stats         debug.GCStats
+		numGC         int64
+		expected      []time.Duration
+		expectedNumGC int64
+	}{
+		{
+			desc:          "no collection",
+			stats:         debug.GCStats{NumGC: 2, Pause: []time.Duration{time.Second, 2 * time.Second}},
+			numGC:         2,
+			expectedNumGC: 2,
+		},
+		{
+			desc:          "one collection",
+			stats:         debug.GCStats{NumGC: 3, Pause: []time.Duration{3 * time.Second, time.Second, 2 * time.Second}},
+			numGC:         2,
+			expected:      []time.Duration{3 * time.Second},
+			expectedNumGC: 3,
+		},
+		{
+			desc:          "more collections than kept pauses",
+			stats:         debug.GCStats{NumGC: 10, Pause: []time.Duration{3 * time.Second, time.Second}},
+			numGC:         2,
+			expected:      []time.Duration{time.Second, 3 * time.Second},
+			expectedNumGC: 10,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			histogram := &histogramMock{}
+
+			numGC := observePauses(histogram, test.stats, test.numGC)
+
+			assert.Equal(t, test.expectedNumGC, numGC)
+			assert.Equal(t, test.expected, histogram.durations)
+		})
+	}
+}
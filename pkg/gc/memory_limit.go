@@ -0,0 +1,10 @@
+// +build go1.19
+
+package gc
+
+import "runtime/debug"
+
+func setMemoryLimit(limit int64) error {
+	debug.SetMemoryLimit(limit)
+	return nil
+}
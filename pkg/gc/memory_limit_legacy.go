@@ -0,0 +1,9 @@
+// +build !go1.19
+
+package gc
+
+import "errors"
+
+func setMemoryLimit(_ int64) error {
+	return errors.New("the soft memory limit requires Traefik to be built with Go 1.19 or later")
+}
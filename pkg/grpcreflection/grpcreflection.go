@@ -0,0 +1,340 @@
+package grpcreflection
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/rules"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+)
+
+var (
+	// methodPathRegexp matches the path of a gRPC method: /package.Service/Method.
+	methodPathRegexp = regexp.MustCompile(`^/([A-Za-z_][\w.]*)/([A-Za-z_]\w*)$`)
+	// servicePathRegexp matches the path prefix of the methods of a gRPC service: /package.Service/.
+	servicePathRegexp = regexp.MustCompile(`^/([A-Za-z_][\w.]*)/?$`)
+)
+
+// Route is a gRPC route: either all the methods of a service, or a single method.
+type Route struct {
+	Service string
+	Method  string
+}
+
+func (r Route) String() string {
+	if r.Method == "" {
+		return "service " + r.Service
+	}
+
+	return "method " + r.Service + "/" + r.Method
+}
+
+// ParseRoutes returns the gRPC routes of the given rule:
+// the methods matched by its Path matchers, and the services matched by its PathPrefix matchers.
+// The paths which cannot be gRPC ones are ignored.
+func ParseRoutes(rule string) ([]Route, error) {
+	paths, err := rules.ParsePaths(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	prefixes, err := rules.ParsePathPrefixes(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	var routes []Route
+	for _, path := range paths {
+		if match := methodPathRegexp.FindStringSubmatch(path); match != nil {
+			routes = append(routes, Route{Service: match[1], Method: match[2]})
+		}
+	}
+
+	for _, prefix := range prefixes {
+		if match := servicePathRegexp.FindStringSubmatch(prefix); match != nil {
+			routes = append(routes, Route{Service: match[1]})
+		}
+	}
+
+	return routes, nil
+}
+
+// Services holds the gRPC services exposed by a server, and their methods when they are known.
+type Services map[string]map[string]struct{}
+
+// Mismatches returns the given routes which are not exposed by the server.
+func (s Services) Mismatches(routes []Route) []Route {
+	var mismatches []Route
+	for _, route := range routes {
+		methods, ok := s[route.Service]
+		if !ok {
+			mismatches = append(mismatches, route)
+			continue
+		}
+
+		if route.Method == "" || methods == nil {
+			continue
+		}
+
+		if _, ok := methods[route.Method]; !ok {
+			mismatches = append(mismatches, route)
+		}
+	}
+
+	return mismatches
+}
+
+// check is the validation of the gRPC routes of a router.
+type check struct {
+	name    string
+	router  *runtime.RouterInfo
+	routes  []Route
+	servers []string
+}
+
+// server is a server to query, with the gRPC services whose methods are needed.
+type server struct {
+	services map[string]struct{}
+	timeout  time.Duration
+
+	result Services
+	err    error
+}
+
+// Validate validates the gRPC routes of the routers whose service enables the gRPC reflection validation,
+// against the gRPC reflection service of the servers of their service, and sets the mismatches on the routers.
+// It returns once all the servers have been queried, or when the context is done.
+func Validate(ctx context.Context, conf *runtime.Configuration) {
+	checks, servers := plan(ctx, conf)
+	if len(checks) == 0 {
+		return
+	}
+
+	for serverURL, srv := range servers {
+		if ctx.Err() != nil {
+			return
+		}
+
+		srv.result, srv.err = FetchServices(ctx, serverURL, keys(srv.services), srv.timeout)
+	}
+
+	for _, c := range checks {
+		var mismatches []string
+		for _, serverURL := range c.servers {
+			srv := servers[serverURL]
+			if srv.err != nil {
+				mismatches = append(mismatches, fmt.Sprintf("unable to query the gRPC reflection service of %s: %v", serverURL, srv.err))
+				continue
+			}
+
+			for _, route := range srv.result.Mismatches(c.routes) {
+				mismatches = append(mismatches, fmt.Sprintf("gRPC %s not found on %s", route, serverURL))
+			}
+		}
+
+		if len(mismatches) > 0 {
+			log.FromContext(log.With(ctx, log.Str(log.RouterName, c.name))).
+				Warnf("gRPC routes not exposed by the servers: %s", strings.Join(mismatches, ", "))
+		}
+
+		c.router.SetGRPCMismatches(mismatches)
+	}
+}
+
+// plan returns the validations of the routers to do, and the servers to query.
+func plan(ctx context.Context, conf *runtime.Configuration) ([]check, map[string]*server) {
+	var checks []check
+	servers := make(map[string]*server)
+
+	for routerName, router := range conf.Routers {
+		if router.Status == runtime.StatusDisabled {
+			continue
+		}
+
+		serviceName := router.Service
+		if idx := strings.LastIndex(routerName, "@"); idx >= 0 && !strings.Contains(serviceName, "@") {
+			serviceName += routerName[idx:]
+		}
+
+		service, ok := conf.Services[serviceName]
+		if !ok || service.LoadBalancer == nil || service.LoadBalancer.GRPCReflection == nil {
+			continue
+		}
+
+		logger := log.FromContext(log.With(ctx, log.Str(log.RouterName, routerName)))
+
+		routes, err := ParseRoutes(router.Rule)
+		if err != nil {
+			logger.Debugf("Unable to parse the gRPC routes: %v", err)
+			continue
+		}
+
+		if len(routes) == 0 {
+			continue
+		}
+
+		c := check{name: routerName, router: router, routes: routes}
+		for _, lbServer := range service.LoadBalancer.Servers {
+			srv, ok := servers[lbServer.URL]
+			if !ok {
+				srv = &server{
+					services: make(map[string]struct{}),
+					timeout:  time.Duration(service.LoadBalancer.GRPCReflection.Timeout),
+				}
+				servers[lbServer.URL] = srv
+			}
+
+			for _, route := range routes {
+				srv.services[route.Service] = struct{}{}
+			}
+
+			c.servers = append(c.servers, lbServer.URL)
+		}
+
+		checks = append(checks, c)
+	}
+
+	return checks, servers
+}
+
+// FetchServices queries the gRPC reflection service of the given server,
+// for the gRPC services it exposes, and the methods of the given services.
+func FetchServices(ctx context.Context, serverURL string, services []string, timeout time.Duration) (Services, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return nil, err
+	}
+
+	dialOptions := []grpc.DialOption{grpc.WithBlock(), grpc.WithInsecure()}
+	if u.Scheme == "https" {
+		dialOptions = []grpc.DialOption{grpc.WithBlock(), grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{}))}
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	conn, err := grpc.DialContext(ctx, u.Host, dialOptions...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = stream.CloseSend() }()
+
+	resp, err := query(stream, &rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_ListServices{ListServices: "*"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.GetListServicesResponse() == nil {
+		return nil, errors.New("unexpected response to the list of the services")
+	}
+
+	result := make(Services)
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		result[service.GetName()] = nil
+	}
+
+	for _, service := range services {
+		if _, ok := result[service]; !ok {
+			continue
+		}
+
+		resp, err := query(stream, &rpb.ServerReflectionRequest{
+			MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: service},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		result[service], err = serviceMethods(resp.GetFileDescriptorResponse(), service)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func query(stream rpb.ServerReflection_ServerReflectionInfoClient, req *rpb.ServerReflectionRequest) (*rpb.ServerReflectionResponse, error) {
+	if err := stream.Send(req); err != nil {
+		return nil, err
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+
+	if errResp := resp.GetErrorResponse(); errResp != nil {
+		return nil, fmt.Errorf("error code %d: %s", errResp.GetErrorCode(), errResp.GetErrorMessage())
+	}
+
+	return resp, nil
+}
+
+// serviceMethods returns the methods of the given service, from the file descriptors declaring it.
+func serviceMethods(resp *rpb.FileDescriptorResponse, service string) (map[string]struct{}, error) {
+	if resp == nil {
+		return nil, fmt.Errorf("unexpected response to the descriptor of %s", service)
+	}
+
+	for _, raw := range resp.GetFileDescriptorProto() {
+		var file descriptor.FileDescriptorProto
+		if err := proto.Unmarshal(raw, &file); err != nil {
+			return nil, fmt.Errorf("unable to decode the descriptor of %s: %w", service, err)
+		}
+
+		for _, svc := range file.GetService() {
+			name := svc.GetName()
+			if file.GetPackage() != "" {
+				name = file.GetPackage() + "." + name
+			}
+
+			if name != service {
+				continue
+			}
+
+			methods := make(map[string]struct{}, len(svc.GetMethod()))
+			for _, method := range svc.GetMethod() {
+				methods[method.GetName()] = struct{}{}
+			}
+
+			return methods, nil
+		}
+	}
+
+	return nil, fmt.Errorf("descriptor of %s not found", service)
+}
+
+func keys(set map[string]struct{}) []string {
+	values := make([]string, 0, len(set))
+	for value := range set {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+
+	return values
+}
@@ -0,0 +1,128 @@
+package grpcreflection
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+func TestParseRoutes(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rule     string
+		expected []Route
+	}{
+		{
+			desc: "method",
+			rule: "Host(`foo.bar`) && Path(`/helloworld.Greeter/SayHello`)",
+			expected: []Route{
+				{Service: "helloworld.Greeter", Method: "SayHello"},
+			},
+		},
+		{
+			desc: "services",
+			rule: "PathPrefix(`/helloworld.Greeter/`, `/grpc.health.v1.Health`)",
+			expected: []Route{
+				{Service: "helloworld.Greeter"},
+				{Service: "grpc.health.v1.Health"},
+			},
+		},
+		{
+			desc: "paths which are not gRPC ones",
+			rule: "Path(`/foo/bar/baz`) || PathPrefix(`/`) || PathPrefix(`/foo/{id}`)",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			routes, err := ParseRoutes(test.rule)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, routes)
+		})
+	}
+}
+
+func TestServices_Mismatches(t *testing.T) {
+	services := Services{
+		"helloworld.Greeter": {"SayHello": {}},
+		"unknown.Methods":    nil,
+	}
+
+	routes := []Route{
+		{Service: "helloworld.Greeter"},
+		{Service: "helloworld.Greeter", Method: "SayHello"},
+		{Service: "helloworld.Greeter", Method: "SayGoodbye"},
+		{Service: "unknown.Methods", Method: "Foo"},
+		{Service: "helloworld.Farewell"},
+	}
+
+	expected := []Route{
+		{Service: "helloworld.Greeter", Method: "SayGoodbye"},
+		{Service: "helloworld.Farewell"},
+	}
+
+	assert.Equal(t, expected, services.Mismatches(routes))
+}
+
+func TestValidate(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	grpcServer := grpc.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, health.NewServer())
+	reflection.Register(grpcServer)
+
+	go func() { _ = grpcServer.Serve(listener) }()
+	defer grpcServer.Stop()
+
+	conf := &runtime.Configuration{
+		Routers: map[string]*runtime.RouterInfo{
+			"valid@file": {Router: &dynamic.Router{
+				Service: "grpc",
+				Rule:    "Path(`/grpc.health.v1.Health/Check`) || PathPrefix(`/grpc.health.v1.Health/`)",
+			}},
+			"invalid@file": {Router: &dynamic.Router{
+				Service: "grpc",
+				Rule:    "Path(`/grpc.health.v1.Health/Ping`) || PathPrefix(`/helloworld.Greeter/`)",
+			}},
+			"unvalidated@file": {Router: &dynamic.Router{
+				Service: "other",
+				Rule:    "PathPrefix(`/helloworld.Greeter/`)",
+			}},
+		},
+		Services: map[string]*runtime.ServiceInfo{
+			"grpc@file": {Service: &dynamic.Service{LoadBalancer: &dynamic.ServersLoadBalancer{
+				Servers:        []dynamic.Server{{URL: "h2c://" + listener.Addr().String()}},
+				GRPCReflection: &dynamic.GRPCReflection{Timeout: ptypes.Duration(5 * time.Second)},
+			}}},
+			"other@file": {Service: &dynamic.Service{LoadBalancer: &dynamic.ServersLoadBalancer{
+				Servers: []dynamic.Server{{URL: "h2c://" + listener.Addr().String()}},
+			}}},
+		},
+	}
+
+	Validate(context.Background(), conf)
+
+	assert.Empty(t, conf.Routers["valid@file"].GetGRPCMismatches())
+	assert.Empty(t, conf.Routers["unvalidated@file"].GetGRPCMismatches())
+
+	expected := []string{
+		"gRPC method grpc.health.v1.Health/Ping not found on h2c://" + listener.Addr().String(),
+		"gRPC service helloworld.Greeter not found on h2c://" + listener.Addr().String(),
+	}
+	assert.Equal(t, expected, conf.Routers["invalid@file"].GetGRPCMismatches())
+}
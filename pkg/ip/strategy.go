@@ -1,6 +1,7 @@
 package ip
 
 import (
+	"context"
 	"net"
 	"net/http"
 	"strings"
@@ -27,6 +28,27 @@ func (s *RemoteAddrStrategy) GetIP(req *http.Request) string {
 	return ip
 }
 
+// ClientIPStrategy a strategy that returns the client IP determined by the entry point,
+// according to its trusted forwarded headers, or the remote address if there is none.
+type ClientIPStrategy struct{}
+
+// GetIP returns the selected IP.
+func (s *ClientIPStrategy) GetIP(req *http.Request) string {
+	if clientIP, ok := req.Context().Value(clientIPKey{}).(string); ok {
+		return clientIP
+	}
+
+	strategy := RemoteAddrStrategy{}
+	return strategy.GetIP(req)
+}
+
+type clientIPKey struct{}
+
+// WithClientIP returns a copy of the context holding the client IP, as returned by the ClientIPStrategy.
+func WithClientIP(ctx context.Context, clientIP string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, clientIP)
+}
+
 // DepthStrategy a strategy based on the depth inside the X-Forwarded-For from right to left.
 type DepthStrategy struct {
 	Depth int
@@ -33,6 +33,40 @@ func TestRemoteAddrStrategy_GetIP(t *testing.T) {
 	}
 }
 
+func TestClientIPStrategy_GetIP(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		clientIP string
+		expected string
+	}{
+		{
+			desc:     "Use RemoteAddr",
+			expected: "192.0.2.1",
+		},
+		{
+			desc:     "Use client IP",
+			clientIP: "2001:db8::1",
+			expected: "2001:db8::1",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			strategy := ClientIPStrategy{}
+			req := httptest.NewRequest(http.MethodGet, "http://127.0.0.1", nil)
+			if test.clientIP != "" {
+				req = req.WithContext(WithClientIP(req.Context(), test.clientIP))
+			}
+
+			actual := strategy.GetIP(req)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
 func TestDepthStrategy_GetIP(t *testing.T) {
 	testCases := []struct {
 		desc          string
@@ -25,6 +25,10 @@ const (
 	ddLastConfigReloadSuccessName   = "config.reload.lastSuccessTimestamp"
 	ddLastConfigReloadFailureName   = "config.reload.lastFailureTimestamp"
 	ddTLSCertsNotAfterTimestampName = "tls.certs.notAfterTimestamp"
+	ddTLSCertsHandshakesTotalName   = "tls.certs.handshakes.total"
+	ddTLSOCSPStapleAgeName          = "tls.ocsp.stapleAge"
+	ddTLSOCSPFailuresTotalName      = "tls.ocsp.failures.total"
+	ddGCPauseDurationName           = "gc.pause.duration"
 
 	ddEntryPointReqsName        = "entrypoint.request.total"
 	ddEntryPointReqsTLSName     = "entrypoint.request.tls.total"
@@ -35,6 +39,8 @@ const (
 	ddMetricsRouterReqsTLSName      = "router.request.tls.total"
 	ddMetricsRouterReqsDurationName = "router.request.duration"
 	ddRouterOpenConnsName           = "router.connections.open"
+	ddRouterReqsBytesName           = "router.request.bytes.total"
+	ddRouterRespsBytesName          = "router.response.bytes.total"
 
 	ddMetricsServiceReqsName         = "service.request.total"
 	ddMetricsServiceReqsTLSName      = "service.request.tls.total"
@@ -42,6 +48,13 @@ const (
 	ddRetriesTotalName               = "service.retries.total"
 	ddOpenConnsName                  = "service.connections.open"
 	ddServerUpName                   = "service.server.up"
+	ddSpilloverTotalName             = "service.spillover.total"
+
+	ddTCPConnDurationName    = "tcp.connection.duration"
+	ddTCPBytesTotalName      = "tcp.bytes.total"
+	ddTCPConnErrorsTotalName = "tcp.connection.errors.total"
+
+	ddHTTPCacheReqsTotalName = "http.cache.request.total"
 )
 
 // RegisterDatadog registers the metrics pusher if this didn't happen yet and creates a datadog Registry instance.
@@ -56,8 +69,14 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		lastConfigReloadSuccessGauge:   datadogClient.NewGauge(ddLastConfigReloadSuccessName),
 		lastConfigReloadFailureGauge:   datadogClient.NewGauge(ddLastConfigReloadFailureName),
 		tlsCertsNotAfterTimestampGauge: datadogClient.NewGauge(ddTLSCertsNotAfterTimestampName),
+		tlsCertsHandshakesCounter:      datadogClient.NewCounter(ddTLSCertsHandshakesTotalName, 1.0),
+		tlsOCSPStapleAgeGauge:          datadogClient.NewGauge(ddTLSOCSPStapleAgeName),
+		tlsOCSPFailuresCounter:         datadogClient.NewCounter(ddTLSOCSPFailuresTotalName, 1.0),
 	}
 
+	registry.gcPauseDurationHistogram, _ = NewHistogramWithScale(datadogClient.NewHistogram(ddGCPauseDurationName, 1.0), time.Second)
+	registry.httpCacheReqsCounter = datadogClient.NewCounter(ddHTTPCacheReqsTotalName, 1.0)
+
 	if config.AddEntryPointsLabels {
 		registry.epEnabled = config.AddEntryPointsLabels
 		registry.entryPointReqsCounter = datadogClient.NewCounter(ddEntryPointReqsName, 1.0)
@@ -72,6 +91,8 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		registry.routerReqsTLSCounter = datadogClient.NewCounter(ddMetricsRouterReqsTLSName, 1.0)
 		registry.routerReqDurationHistogram, _ = NewHistogramWithScale(datadogClient.NewHistogram(ddMetricsRouterReqsDurationName, 1.0), time.Second)
 		registry.routerOpenConnsGauge = datadogClient.NewGauge(ddRouterOpenConnsName)
+		registry.routerReqsBytesCounter = datadogClient.NewCounter(ddRouterReqsBytesName, 1.0)
+		registry.routerRespsBytesCounter = datadogClient.NewCounter(ddRouterRespsBytesName, 1.0)
 	}
 
 	if config.AddServicesLabels {
@@ -82,6 +103,12 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		registry.serviceRetriesCounter = datadogClient.NewCounter(ddRetriesTotalName, 1.0)
 		registry.serviceOpenConnsGauge = datadogClient.NewGauge(ddOpenConnsName)
 		registry.serviceServerUpGauge = datadogClient.NewGauge(ddServerUpName)
+		registry.serviceSpilloverCounter = datadogClient.NewCounter(ddSpilloverTotalName, 1.0)
+
+		registry.tcpEnabled = config.AddServicesLabels
+		registry.tcpConnDurationHistogram, _ = NewHistogramWithScale(datadogClient.NewHistogram(ddTCPConnDurationName, 1.0), time.Second)
+		registry.tcpBytesCounter = datadogClient.NewCounter(ddTCPBytesTotalName, 1.0)
+		registry.tcpConnErrorsCounter = datadogClient.NewCounter(ddTCPConnErrorsTotalName, 1.0)
 	}
 
 	return registry
@@ -31,6 +31,11 @@ func TestDatadog(t *testing.T) {
 		"traefik.config.reload.lastFailureTimestamp:1.000000|g\n",
 
 		"traefik.tls.certs.notAfterTimestamp:1.000000|g|#key:value\n",
+		"traefik.tls.certs.handshakes.total:1.000000|c|#key:value\n",
+		"traefik.tls.ocsp.stapleAge:60.000000|g|#key:value\n",
+		"traefik.tls.ocsp.failures.total:1.000000|c|#key:value\n",
+
+		"traefik.gc.pause.duration:10000.000000|h\n",
 
 		"traefik.entrypoint.request.total:1.000000|c|#entrypoint:test\n",
 		"traefik.entrypoint.request.tls.total:1.000000|c|#entrypoint:test,tls_version:foo,tls_cipher:bar\n",
@@ -42,6 +47,8 @@ func TestDatadog(t *testing.T) {
 		"traefik.router.request.tls.total:1.000000|c|#router:demo,service:test,tls_version:foo,tls_cipher:bar\n",
 		"traefik.router.request.duration:10000.000000|h|#router:demo,service:test,code:200\n",
 		"traefik.router.connections.open:1.000000|g|#router:demo,service:test\n",
+		"traefik.router.request.bytes.total:100.000000|c|#router:demo,service:test,code:200,method:GET\n",
+		"traefik.router.response.bytes.total:200.000000|c|#router:demo,service:test,code:200,method:GET\n",
 
 		"traefik.service.request.total:1.000000|c|#service:test,code:404,method:GET\n",
 		"traefik.service.request.total:1.000000|c|#service:test,code:200,method:GET\n",
@@ -51,6 +58,13 @@ func TestDatadog(t *testing.T) {
 		"traefik.service.retries.total:2.000000|c|#service:test\n",
 		"traefik.service.request.duration:10000.000000|h|#service:test,code:200\n",
 		"traefik.service.server.up:1.000000|g|#service:test,url:http://127.0.0.1,one:two\n",
+		"traefik.service.spillover.total:1.000000|c|#service:test,pool:backup\n",
+
+		"traefik.tcp.connection.duration:10000.000000|h|#router:demo,service:test,server:127.0.0.1:80\n",
+		"traefik.tcp.bytes.total:10.000000|c|#router:demo,service:test,server:127.0.0.1:80,direction:in\n",
+		"traefik.tcp.connection.errors.total:1.000000|c|#router:demo,service:test,server:127.0.0.1:80,reason:timeout\n",
+
+		"traefik.http.cache.request.total:1.000000|c|#middleware:cache1,status:hit\n",
 	}
 
 	udp.ShouldReceiveAll(t, expected, func() {
@@ -60,6 +74,11 @@ func TestDatadog(t *testing.T) {
 		datadogRegistry.LastConfigReloadFailureGauge().Add(1)
 
 		datadogRegistry.TLSCertsNotAfterTimestampGauge().With("key", "value").Set(1)
+		datadogRegistry.TLSCertsHandshakesCounter().With("key", "value").Add(1)
+		datadogRegistry.TLSOCSPStapleAgeGauge().With("key", "value").Set(60)
+		datadogRegistry.TLSOCSPFailuresCounter().With("key", "value").Add(1)
+
+		datadogRegistry.GCPauseDurationHistogram().Observe(10000)
 
 		datadogRegistry.EntryPointReqsCounter().With("entrypoint", "test").Add(1)
 		datadogRegistry.EntryPointReqsTLSCounter().With("entrypoint", "test", "tls_version", "foo", "tls_cipher", "bar").Add(1)
@@ -71,6 +90,8 @@ func TestDatadog(t *testing.T) {
 		datadogRegistry.RouterReqsTLSCounter().With("router", "demo", "service", "test", "tls_version", "foo", "tls_cipher", "bar").Add(1)
 		datadogRegistry.RouterReqDurationHistogram().With("router", "demo", "service", "test", "code", strconv.Itoa(http.StatusOK)).Observe(10000)
 		datadogRegistry.RouterOpenConnsGauge().With("router", "demo", "service", "test").Set(1)
+		datadogRegistry.RouterReqsBytesCounter().With("router", "demo", "service", "test", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet).Add(100)
+		datadogRegistry.RouterRespsBytesCounter().With("router", "demo", "service", "test", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet).Add(200)
 
 		datadogRegistry.ServiceReqsCounter().With("service", "test", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet).Add(1)
 		datadogRegistry.ServiceReqsCounter().With("service", "test", "code", strconv.Itoa(http.StatusNotFound), "method", http.MethodGet).Add(1)
@@ -80,5 +101,12 @@ func TestDatadog(t *testing.T) {
 		datadogRegistry.ServiceRetriesCounter().With("service", "test").Add(1)
 		datadogRegistry.ServiceRetriesCounter().With("service", "test").Add(1)
 		datadogRegistry.ServiceServerUpGauge().With("service", "test", "url", "http://127.0.0.1", "one", "two").Set(1)
+		datadogRegistry.ServiceSpilloverCounter().With("service", "test", "pool", "backup").Add(1)
+
+		datadogRegistry.TCPConnDurationHistogram().With("router", "demo", "service", "test", "server", "127.0.0.1:80").Observe(10000)
+		datadogRegistry.TCPBytesCounter().With("router", "demo", "service", "test", "server", "127.0.0.1:80", "direction", "in").Add(10)
+		datadogRegistry.TCPConnErrorsCounter().With("router", "demo", "service", "test", "server", "127.0.0.1:80", "reason", "timeout").Add(1)
+
+		datadogRegistry.HTTPCacheReqsCounter().With("middleware", "cache1", "status", "hit").Add(1)
 	})
 }
@@ -32,6 +32,11 @@ const (
 	influxDBLastConfigReloadFailureName = "traefik.config.reload.lastFailureTimestamp"
 
 	influxDBTLSCertsNotAfterTimestampName = "traefik.tls.certs.notAfterTimestamp"
+	influxDBTLSCertsHandshakesTotalName   = "traefik.tls.certs.handshakes.total"
+	influxDBTLSOCSPStapleAgeName          = "traefik.tls.ocsp.stapleAge"
+	influxDBTLSOCSPFailuresTotalName      = "traefik.tls.ocsp.failures.total"
+
+	influxDBGCPauseDurationName = "traefik.gc.pause.duration"
 
 	influxDBEntryPointReqsName        = "traefik.entrypoint.requests.total"
 	influxDBEntryPointReqsTLSName     = "traefik.entrypoint.requests.tls.total"
@@ -42,6 +47,8 @@ const (
 	influxDBRouterReqsTLSName      = "traefik.router.requests.tls.total"
 	influxDBRouterReqsDurationName = "traefik.router.request.duration"
 	influxDBORouterOpenConnsName   = "traefik.router.connections.open"
+	influxDBRouterReqsBytesName    = "traefik.router.requests.bytes.total"
+	influxDBRouterRespsBytesName   = "traefik.router.responses.bytes.total"
 
 	influxDBServiceReqsName         = "traefik.service.requests.total"
 	influxDBServiceReqsTLSName      = "traefik.service.requests.tls.total"
@@ -49,6 +56,13 @@ const (
 	influxDBServiceRetriesTotalName = "traefik.service.retries.total"
 	influxDBServiceOpenConnsName    = "traefik.service.connections.open"
 	influxDBServiceServerUpName     = "traefik.service.server.up"
+	influxDBServiceSpilloverName    = "traefik.service.spillover.total"
+
+	influxDBTCPConnDurationName    = "traefik.tcp.connection.duration"
+	influxDBTCPBytesTotalName      = "traefik.tcp.bytes.total"
+	influxDBTCPConnErrorsTotalName = "traefik.tcp.connection.errors.total"
+
+	influxDBHTTPCacheReqsTotalName = "traefik.http.cache.request.total"
 )
 
 const (
@@ -71,8 +85,14 @@ func RegisterInfluxDB(ctx context.Context, config *types.InfluxDB) Registry {
 		lastConfigReloadSuccessGauge:   influxDBClient.NewGauge(influxDBLastConfigReloadSuccessName),
 		lastConfigReloadFailureGauge:   influxDBClient.NewGauge(influxDBLastConfigReloadFailureName),
 		tlsCertsNotAfterTimestampGauge: influxDBClient.NewGauge(influxDBTLSCertsNotAfterTimestampName),
+		tlsCertsHandshakesCounter:      influxDBClient.NewCounter(influxDBTLSCertsHandshakesTotalName),
+		tlsOCSPStapleAgeGauge:          influxDBClient.NewGauge(influxDBTLSOCSPStapleAgeName),
+		tlsOCSPFailuresCounter:         influxDBClient.NewCounter(influxDBTLSOCSPFailuresTotalName),
 	}
 
+	registry.gcPauseDurationHistogram, _ = NewHistogramWithScale(influxDBClient.NewHistogram(influxDBGCPauseDurationName), time.Second)
+	registry.httpCacheReqsCounter = influxDBClient.NewCounter(influxDBHTTPCacheReqsTotalName)
+
 	if config.AddEntryPointsLabels {
 		registry.epEnabled = config.AddEntryPointsLabels
 		registry.entryPointReqsCounter = influxDBClient.NewCounter(influxDBEntryPointReqsName)
@@ -87,6 +107,8 @@ func RegisterInfluxDB(ctx context.Context, config *types.InfluxDB) Registry {
 		registry.routerReqsTLSCounter = influxDBClient.NewCounter(influxDBRouterReqsTLSName)
 		registry.routerReqDurationHistogram, _ = NewHistogramWithScale(influxDBClient.NewHistogram(influxDBRouterReqsDurationName), time.Second)
 		registry.routerOpenConnsGauge = influxDBClient.NewGauge(influxDBORouterOpenConnsName)
+		registry.routerReqsBytesCounter = influxDBClient.NewCounter(influxDBRouterReqsBytesName)
+		registry.routerRespsBytesCounter = influxDBClient.NewCounter(influxDBRouterRespsBytesName)
 	}
 
 	if config.AddServicesLabels {
@@ -97,6 +119,12 @@ func RegisterInfluxDB(ctx context.Context, config *types.InfluxDB) Registry {
 		registry.serviceRetriesCounter = influxDBClient.NewCounter(influxDBServiceRetriesTotalName)
 		registry.serviceOpenConnsGauge = influxDBClient.NewGauge(influxDBServiceOpenConnsName)
 		registry.serviceServerUpGauge = influxDBClient.NewGauge(influxDBServiceServerUpName)
+		registry.serviceSpilloverCounter = influxDBClient.NewCounter(influxDBServiceSpilloverName)
+
+		registry.tcpEnabled = config.AddServicesLabels
+		registry.tcpConnDurationHistogram, _ = NewHistogramWithScale(influxDBClient.NewHistogram(influxDBTCPConnDurationName), time.Second)
+		registry.tcpBytesCounter = influxDBClient.NewCounter(influxDBTCPBytesTotalName)
+		registry.tcpConnErrorsCounter = influxDBClient.NewCounter(influxDBTCPConnErrorsTotalName)
 	}
 
 	return registry
@@ -16,6 +16,8 @@ type Registry interface {
 	IsRouterEnabled() bool
 	// IsSvcEnabled shows whether metrics instrumentation is enabled on services.
 	IsSvcEnabled() bool
+	// IsTCPEnabled shows whether metrics instrumentation is enabled on TCP routers.
+	IsTCPEnabled() bool
 
 	// server metrics
 	ConfigReloadsCounter() metrics.Counter
@@ -25,6 +27,12 @@ type Registry interface {
 
 	// TLS
 	TLSCertsNotAfterTimestampGauge() metrics.Gauge
+	TLSCertsHandshakesCounter() metrics.Counter
+	TLSOCSPStapleAgeGauge() metrics.Gauge
+	TLSOCSPFailuresCounter() metrics.Counter
+
+	// runtime metrics
+	GCPauseDurationHistogram() ScalableHistogram
 
 	// entry point metrics
 	EntryPointReqsCounter() metrics.Counter
@@ -37,6 +45,8 @@ type Registry interface {
 	RouterReqsTLSCounter() metrics.Counter
 	RouterReqDurationHistogram() ScalableHistogram
 	RouterOpenConnsGauge() metrics.Gauge
+	RouterReqsBytesCounter() metrics.Counter
+	RouterRespsBytesCounter() metrics.Counter
 
 	// service metrics
 	ServiceReqsCounter() metrics.Counter
@@ -45,6 +55,15 @@ type Registry interface {
 	ServiceOpenConnsGauge() metrics.Gauge
 	ServiceRetriesCounter() metrics.Counter
 	ServiceServerUpGauge() metrics.Gauge
+	ServiceSpilloverCounter() metrics.Counter
+
+	// TCP metrics
+	TCPConnDurationHistogram() ScalableHistogram
+	TCPBytesCounter() metrics.Counter
+	TCPConnErrorsCounter() metrics.Counter
+
+	// HTTP cache metrics
+	HTTPCacheReqsCounter() metrics.Counter
 }
 
 // NewVoidRegistry is a noop implementation of metrics.Registry.
@@ -62,6 +81,10 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var lastConfigReloadSuccessGauge []metrics.Gauge
 	var lastConfigReloadFailureGauge []metrics.Gauge
 	var tlsCertsNotAfterTimestampGauge []metrics.Gauge
+	var tlsCertsHandshakesCounter []metrics.Counter
+	var tlsOCSPStapleAgeGauge []metrics.Gauge
+	var tlsOCSPFailuresCounter []metrics.Counter
+	var gcPauseDurationHistogram []ScalableHistogram
 	var entryPointReqsCounter []metrics.Counter
 	var entryPointReqsTLSCounter []metrics.Counter
 	var entryPointReqDurationHistogram []ScalableHistogram
@@ -70,12 +93,19 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var routerReqsTLSCounter []metrics.Counter
 	var routerReqDurationHistogram []ScalableHistogram
 	var routerOpenConnsGauge []metrics.Gauge
+	var routerReqsBytesCounter []metrics.Counter
+	var routerRespsBytesCounter []metrics.Counter
 	var serviceReqsCounter []metrics.Counter
 	var serviceReqsTLSCounter []metrics.Counter
 	var serviceReqDurationHistogram []ScalableHistogram
 	var serviceOpenConnsGauge []metrics.Gauge
 	var serviceRetriesCounter []metrics.Counter
 	var serviceServerUpGauge []metrics.Gauge
+	var serviceSpilloverCounter []metrics.Counter
+	var tcpConnDurationHistogram []ScalableHistogram
+	var tcpBytesCounter []metrics.Counter
+	var tcpConnErrorsCounter []metrics.Counter
+	var httpCacheReqsCounter []metrics.Counter
 
 	for _, r := range registries {
 		if r.ConfigReloadsCounter() != nil {
@@ -93,6 +123,18 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.TLSCertsNotAfterTimestampGauge() != nil {
 			tlsCertsNotAfterTimestampGauge = append(tlsCertsNotAfterTimestampGauge, r.TLSCertsNotAfterTimestampGauge())
 		}
+		if r.TLSCertsHandshakesCounter() != nil {
+			tlsCertsHandshakesCounter = append(tlsCertsHandshakesCounter, r.TLSCertsHandshakesCounter())
+		}
+		if r.TLSOCSPStapleAgeGauge() != nil {
+			tlsOCSPStapleAgeGauge = append(tlsOCSPStapleAgeGauge, r.TLSOCSPStapleAgeGauge())
+		}
+		if r.TLSOCSPFailuresCounter() != nil {
+			tlsOCSPFailuresCounter = append(tlsOCSPFailuresCounter, r.TLSOCSPFailuresCounter())
+		}
+		if r.GCPauseDurationHistogram() != nil {
+			gcPauseDurationHistogram = append(gcPauseDurationHistogram, r.GCPauseDurationHistogram())
+		}
 		if r.EntryPointReqsCounter() != nil {
 			entryPointReqsCounter = append(entryPointReqsCounter, r.EntryPointReqsCounter())
 		}
@@ -117,6 +159,12 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.RouterOpenConnsGauge() != nil {
 			routerOpenConnsGauge = append(routerOpenConnsGauge, r.RouterOpenConnsGauge())
 		}
+		if r.RouterReqsBytesCounter() != nil {
+			routerReqsBytesCounter = append(routerReqsBytesCounter, r.RouterReqsBytesCounter())
+		}
+		if r.RouterRespsBytesCounter() != nil {
+			routerRespsBytesCounter = append(routerRespsBytesCounter, r.RouterRespsBytesCounter())
+		}
 		if r.ServiceReqsCounter() != nil {
 			serviceReqsCounter = append(serviceReqsCounter, r.ServiceReqsCounter())
 		}
@@ -135,17 +183,36 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.ServiceServerUpGauge() != nil {
 			serviceServerUpGauge = append(serviceServerUpGauge, r.ServiceServerUpGauge())
 		}
+		if r.ServiceSpilloverCounter() != nil {
+			serviceSpilloverCounter = append(serviceSpilloverCounter, r.ServiceSpilloverCounter())
+		}
+		if r.TCPConnDurationHistogram() != nil {
+			tcpConnDurationHistogram = append(tcpConnDurationHistogram, r.TCPConnDurationHistogram())
+		}
+		if r.TCPBytesCounter() != nil {
+			tcpBytesCounter = append(tcpBytesCounter, r.TCPBytesCounter())
+		}
+		if r.TCPConnErrorsCounter() != nil {
+			tcpConnErrorsCounter = append(tcpConnErrorsCounter, r.TCPConnErrorsCounter())
+		}
+		if r.HTTPCacheReqsCounter() != nil {
+			httpCacheReqsCounter = append(httpCacheReqsCounter, r.HTTPCacheReqsCounter())
+		}
 	}
 
 	return &standardRegistry{
 		epEnabled:                      len(entryPointReqsCounter) > 0 || len(entryPointReqDurationHistogram) > 0 || len(entryPointOpenConnsGauge) > 0,
 		svcEnabled:                     len(serviceReqsCounter) > 0 || len(serviceReqDurationHistogram) > 0 || len(serviceOpenConnsGauge) > 0 || len(serviceRetriesCounter) > 0 || len(serviceServerUpGauge) > 0,
-		routerEnabled:                  len(routerReqsCounter) > 0 || len(routerReqDurationHistogram) > 0 || len(routerOpenConnsGauge) > 0,
+		routerEnabled:                  len(routerReqsCounter) > 0 || len(routerReqDurationHistogram) > 0 || len(routerOpenConnsGauge) > 0 || len(routerReqsBytesCounter) > 0 || len(routerRespsBytesCounter) > 0,
 		configReloadsCounter:           multi.NewCounter(configReloadsCounter...),
 		configReloadsFailureCounter:    multi.NewCounter(configReloadsFailureCounter...),
 		lastConfigReloadSuccessGauge:   multi.NewGauge(lastConfigReloadSuccessGauge...),
 		lastConfigReloadFailureGauge:   multi.NewGauge(lastConfigReloadFailureGauge...),
 		tlsCertsNotAfterTimestampGauge: multi.NewGauge(tlsCertsNotAfterTimestampGauge...),
+		tlsCertsHandshakesCounter:      multi.NewCounter(tlsCertsHandshakesCounter...),
+		tlsOCSPStapleAgeGauge:          multi.NewGauge(tlsOCSPStapleAgeGauge...),
+		tlsOCSPFailuresCounter:         multi.NewCounter(tlsOCSPFailuresCounter...),
+		gcPauseDurationHistogram:       NewMultiHistogram(gcPauseDurationHistogram...),
 		entryPointReqsCounter:          multi.NewCounter(entryPointReqsCounter...),
 		entryPointReqsTLSCounter:       multi.NewCounter(entryPointReqsTLSCounter...),
 		entryPointReqDurationHistogram: NewMultiHistogram(entryPointReqDurationHistogram...),
@@ -154,12 +221,20 @@ func NewMultiRegistry(registries []Registry) Registry {
 		routerReqsTLSCounter:           multi.NewCounter(routerReqsTLSCounter...),
 		routerReqDurationHistogram:     NewMultiHistogram(routerReqDurationHistogram...),
 		routerOpenConnsGauge:           multi.NewGauge(routerOpenConnsGauge...),
+		routerReqsBytesCounter:         multi.NewCounter(routerReqsBytesCounter...),
+		routerRespsBytesCounter:        multi.NewCounter(routerRespsBytesCounter...),
 		serviceReqsCounter:             multi.NewCounter(serviceReqsCounter...),
 		serviceReqsTLSCounter:          multi.NewCounter(serviceReqsTLSCounter...),
 		serviceReqDurationHistogram:    NewMultiHistogram(serviceReqDurationHistogram...),
 		serviceOpenConnsGauge:          multi.NewGauge(serviceOpenConnsGauge...),
 		serviceRetriesCounter:          multi.NewCounter(serviceRetriesCounter...),
 		serviceServerUpGauge:           multi.NewGauge(serviceServerUpGauge...),
+		serviceSpilloverCounter:        multi.NewCounter(serviceSpilloverCounter...),
+		tcpEnabled:                     len(tcpConnDurationHistogram) > 0 || len(tcpBytesCounter) > 0 || len(tcpConnErrorsCounter) > 0,
+		tcpConnDurationHistogram:       NewMultiHistogram(tcpConnDurationHistogram...),
+		tcpBytesCounter:                multi.NewCounter(tcpBytesCounter...),
+		tcpConnErrorsCounter:           multi.NewCounter(tcpConnErrorsCounter...),
+		httpCacheReqsCounter:           multi.NewCounter(httpCacheReqsCounter...),
 	}
 }
 
@@ -172,6 +247,10 @@ type standardRegistry struct {
 	lastConfigReloadSuccessGauge   metrics.Gauge
 	lastConfigReloadFailureGauge   metrics.Gauge
 	tlsCertsNotAfterTimestampGauge metrics.Gauge
+	tlsCertsHandshakesCounter      metrics.Counter
+	tlsOCSPStapleAgeGauge          metrics.Gauge
+	tlsOCSPFailuresCounter         metrics.Counter
+	gcPauseDurationHistogram       ScalableHistogram
 	entryPointReqsCounter          metrics.Counter
 	entryPointReqsTLSCounter       metrics.Counter
 	entryPointReqDurationHistogram ScalableHistogram
@@ -180,12 +259,20 @@ type standardRegistry struct {
 	routerReqsTLSCounter           metrics.Counter
 	routerReqDurationHistogram     ScalableHistogram
 	routerOpenConnsGauge           metrics.Gauge
+	routerReqsBytesCounter         metrics.Counter
+	routerRespsBytesCounter        metrics.Counter
 	serviceReqsCounter             metrics.Counter
 	serviceReqsTLSCounter          metrics.Counter
 	serviceReqDurationHistogram    ScalableHistogram
 	serviceOpenConnsGauge          metrics.Gauge
 	serviceRetriesCounter          metrics.Counter
 	serviceServerUpGauge           metrics.Gauge
+	serviceSpilloverCounter        metrics.Counter
+	tcpEnabled                     bool
+	tcpConnDurationHistogram       ScalableHistogram
+	tcpBytesCounter                metrics.Counter
+	tcpConnErrorsCounter           metrics.Counter
+	httpCacheReqsCounter           metrics.Counter
 }
 
 func (r *standardRegistry) IsEpEnabled() bool {
@@ -200,6 +287,10 @@ func (r *standardRegistry) IsSvcEnabled() bool {
 	return r.svcEnabled
 }
 
+func (r *standardRegistry) IsTCPEnabled() bool {
+	return r.tcpEnabled
+}
+
 func (r *standardRegistry) ConfigReloadsCounter() metrics.Counter {
 	return r.configReloadsCounter
 }
@@ -220,6 +311,22 @@ func (r *standardRegistry) TLSCertsNotAfterTimestampGauge() metrics.Gauge {
 	return r.tlsCertsNotAfterTimestampGauge
 }
 
+func (r *standardRegistry) TLSCertsHandshakesCounter() metrics.Counter {
+	return r.tlsCertsHandshakesCounter
+}
+
+func (r *standardRegistry) TLSOCSPStapleAgeGauge() metrics.Gauge {
+	return r.tlsOCSPStapleAgeGauge
+}
+
+func (r *standardRegistry) TLSOCSPFailuresCounter() metrics.Counter {
+	return r.tlsOCSPFailuresCounter
+}
+
+func (r *standardRegistry) GCPauseDurationHistogram() ScalableHistogram {
+	return r.gcPauseDurationHistogram
+}
+
 func (r *standardRegistry) EntryPointReqsCounter() metrics.Counter {
 	return r.entryPointReqsCounter
 }
@@ -252,6 +359,14 @@ func (r *standardRegistry) RouterOpenConnsGauge() metrics.Gauge {
 	return r.routerOpenConnsGauge
 }
 
+func (r *standardRegistry) RouterReqsBytesCounter() metrics.Counter {
+	return r.routerReqsBytesCounter
+}
+
+func (r *standardRegistry) RouterRespsBytesCounter() metrics.Counter {
+	return r.routerRespsBytesCounter
+}
+
 func (r *standardRegistry) ServiceReqsCounter() metrics.Counter {
 	return r.serviceReqsCounter
 }
@@ -276,6 +391,26 @@ func (r *standardRegistry) ServiceServerUpGauge() metrics.Gauge {
 	return r.serviceServerUpGauge
 }
 
+func (r *standardRegistry) ServiceSpilloverCounter() metrics.Counter {
+	return r.serviceSpilloverCounter
+}
+
+func (r *standardRegistry) TCPConnDurationHistogram() ScalableHistogram {
+	return r.tcpConnDurationHistogram
+}
+
+func (r *standardRegistry) TCPBytesCounter() metrics.Counter {
+	return r.tcpBytesCounter
+}
+
+func (r *standardRegistry) TCPConnErrorsCounter() metrics.Counter {
+	return r.tcpConnErrorsCounter
+}
+
+func (r *standardRegistry) HTTPCacheReqsCounter() metrics.Counter {
+	return r.httpCacheReqsCounter
+}
+
 // ScalableHistogram is a Histogram with a predefined time unit,
 // used when producing observations without explicitly setting the observed value.
 type ScalableHistogram interface {
@@ -32,6 +32,9 @@ const (
 	// TLS.
 	metricsTLSPrefix          = MetricNamePrefix + "tls_"
 	tlsCertsNotAfterTimestamp = metricsTLSPrefix + "certs_not_after"
+	tlsCertsHandshakesTotal   = metricsTLSPrefix + "certs_handshakes_total"
+	tlsOCSPStapleAgeName      = metricsTLSPrefix + "ocsp_staple_age_seconds"
+	tlsOCSPFailuresTotalName  = metricsTLSPrefix + "ocsp_failures_total"
 
 	// entry point.
 	metricEntryPointPrefix     = MetricNamePrefix + "entrypoint_"
@@ -46,6 +49,8 @@ const (
 	routerReqsTLSTotalName = metricRouterPrefix + "requests_tls_total"
 	routerReqDurationName  = metricRouterPrefix + "request_duration_seconds"
 	routerOpenConnsName    = metricRouterPrefix + "open_connections"
+	routerReqsBytesName    = metricRouterPrefix + "requests_bytes_total"
+	routerRespsBytesName   = metricRouterPrefix + "responses_bytes_total"
 
 	// service level.
 	metricServicePrefix     = MetricNamePrefix + "service_"
@@ -55,6 +60,17 @@ const (
 	serviceOpenConnsName    = metricServicePrefix + "open_connections"
 	serviceRetriesTotalName = metricServicePrefix + "retries_total"
 	serviceServerUpName     = metricServicePrefix + "server_up"
+	serviceSpilloverName    = metricServicePrefix + "spillover_total"
+
+	// TCP.
+	metricTCPPrefix        = MetricNamePrefix + "tcp_"
+	tcpConnDurationName    = metricTCPPrefix + "connection_duration_seconds"
+	tcpBytesTotalName      = metricTCPPrefix + "bytes_total"
+	tcpConnErrorsTotalName = metricTCPPrefix + "connection_errors_total"
+
+	// HTTP cache.
+	metricHTTPCachePrefix  = MetricNamePrefix + "http_cache_"
+	httpCacheReqsTotalName = metricHTTPCachePrefix + "requests_total"
 )
 
 // promState holds all metric state internally and acts as the only Collector we register for Prometheus.
@@ -134,6 +150,22 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		Name: tlsCertsNotAfterTimestamp,
 		Help: "Certificate expiration timestamp",
 	}, []string{"cn", "serial", "sans"})
+	tlsCertsHandshakes := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: tlsCertsHandshakesTotal,
+		Help: "How many TLS handshakes were served with a certificate",
+	}, []string{"cn", "serial", "sans", "store"})
+	tlsOCSPStapleAge := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+		Name: tlsOCSPStapleAgeName,
+		Help: "Age of the OCSP response stapled to a certificate",
+	}, []string{"cn", "serial"})
+	tlsOCSPFailures := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: tlsOCSPFailuresTotalName,
+		Help: "How many times the OCSP response of a certificate failed to be fetched",
+	}, []string{"cn", "serial"})
+	httpCacheReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: httpCacheReqsTotalName,
+		Help: "How many requests were handled by an HTTP cache middleware, partitioned by middleware and cache status.",
+	}, []string{"middleware", "status"})
 
 	promState.describers = []func(chan<- *stdprometheus.Desc){
 		configReloads.cv.Describe,
@@ -141,6 +173,10 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		lastConfigReloadSuccess.gv.Describe,
 		lastConfigReloadFailure.gv.Describe,
 		tlsCertsNotAfterTimesptamp.gv.Describe,
+		tlsCertsHandshakes.cv.Describe,
+		tlsOCSPStapleAge.gv.Describe,
+		tlsOCSPFailures.cv.Describe,
+		httpCacheReqs.cv.Describe,
 	}
 
 	reg := &standardRegistry{
@@ -152,22 +188,26 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		lastConfigReloadSuccessGauge:   lastConfigReloadSuccess,
 		lastConfigReloadFailureGauge:   lastConfigReloadFailure,
 		tlsCertsNotAfterTimestampGauge: tlsCertsNotAfterTimesptamp,
+		tlsCertsHandshakesCounter:      tlsCertsHandshakes,
+		tlsOCSPStapleAgeGauge:          tlsOCSPStapleAge,
+		tlsOCSPFailuresCounter:         tlsOCSPFailures,
+		httpCacheReqsCounter:           httpCacheReqs,
 	}
 
 	if config.AddEntryPointsLabels {
 		entryPointReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
 			Name: entryPointReqsTotalName,
-			Help: "How many HTTP requests processed on an entrypoint, partitioned by status code, protocol, and method.",
-		}, []string{"code", "method", "protocol", "entrypoint"})
+			Help: "How many HTTP requests processed on an entrypoint, partitioned by status code, gRPC status code, protocol, and method.",
+		}, []string{"code", "grpc_code", "method", "protocol", "entrypoint"})
 		entryPointReqsTLS := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
 			Name: entryPointReqsTLSTotalName,
 			Help: "How many HTTP requests with TLS processed on an entrypoint, partitioned by TLS Version and TLS cipher Used.",
 		}, []string{"tls_version", "tls_cipher", "entrypoint"})
 		entryPointReqDurations := newHistogramFrom(promState.collectors, stdprometheus.HistogramOpts{
 			Name:    entryPointReqDurationName,
-			Help:    "How long it took to process the request on an entrypoint, partitioned by status code, protocol, and method.",
+			Help:    "How long it took to process the request on an entrypoint, partitioned by status code, gRPC status code, protocol, and method.",
 			Buckets: buckets,
-		}, []string{"code", "method", "protocol", "entrypoint"})
+		}, []string{"code", "grpc_code", "method", "protocol", "entrypoint"})
 		entryPointOpenConns := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
 			Name: entryPointOpenConnsName,
 			Help: "How many open connections exist on an entrypoint, partitioned by method and protocol.",
@@ -189,48 +229,60 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 	if config.AddRoutersLabels {
 		routerReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
 			Name: routerReqsTotalName,
-			Help: "How many HTTP requests are processed on a router, partitioned by service, status code, protocol, and method.",
-		}, []string{"code", "method", "protocol", "router", "service"})
+			Help: "How many HTTP requests are processed on a router, partitioned by service, status code, gRPC status code, protocol, and method.",
+		}, []string{"code", "grpc_code", "method", "protocol", "router", "service"})
 		routerReqsTLS := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
 			Name: routerReqsTLSTotalName,
 			Help: "How many HTTP requests with TLS are processed on a router, partitioned by service, TLS Version, and TLS cipher Used.",
 		}, []string{"tls_version", "tls_cipher", "router", "service"})
 		routerReqDurations := newHistogramFrom(promState.collectors, stdprometheus.HistogramOpts{
 			Name:    routerReqDurationName,
-			Help:    "How long it took to process the request on a router, partitioned by service, status code, protocol, and method.",
+			Help:    "How long it took to process the request on a router, partitioned by service, status code, gRPC status code, protocol, and method.",
 			Buckets: buckets,
-		}, []string{"code", "method", "protocol", "router", "service"})
+		}, []string{"code", "grpc_code", "method", "protocol", "router", "service"})
 		routerOpenConns := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
 			Name: routerOpenConnsName,
 			Help: "How many open connections exist on a router, partitioned by service, method, and protocol.",
 		}, []string{"method", "protocol", "router", "service"})
+		routerReqsBytes := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: routerReqsBytesName,
+			Help: "How many bytes of HTTP requests are received on a router, partitioned by service, status code, gRPC status code, protocol, and method.",
+		}, []string{"code", "grpc_code", "method", "protocol", "router", "service"})
+		routerRespsBytes := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: routerRespsBytesName,
+			Help: "How many bytes of HTTP responses are sent on a router, partitioned by service, status code, gRPC status code, protocol, and method.",
+		}, []string{"code", "grpc_code", "method", "protocol", "router", "service"})
 
 		promState.describers = append(promState.describers, []func(chan<- *stdprometheus.Desc){
 			routerReqs.cv.Describe,
 			routerReqsTLS.cv.Describe,
 			routerReqDurations.hv.Describe,
 			routerOpenConns.gv.Describe,
+			routerReqsBytes.cv.Describe,
+			routerRespsBytes.cv.Describe,
 		}...)
 		reg.routerReqsCounter = routerReqs
 		reg.routerReqsTLSCounter = routerReqsTLS
 		reg.routerReqDurationHistogram, _ = NewHistogramWithScale(routerReqDurations, time.Second)
 		reg.routerOpenConnsGauge = routerOpenConns
+		reg.routerReqsBytesCounter = routerReqsBytes
+		reg.routerRespsBytesCounter = routerRespsBytes
 	}
 
 	if config.AddServicesLabels {
 		serviceReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
 			Name: serviceReqsTotalName,
-			Help: "How many HTTP requests processed on a service, partitioned by status code, protocol, and method.",
-		}, []string{"code", "method", "protocol", "service"})
+			Help: "How many HTTP requests processed on a service, partitioned by status code, gRPC status code, protocol, and method.",
+		}, []string{"code", "grpc_code", "method", "protocol", "service"})
 		serviceReqsTLS := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
 			Name: serviceReqsTLSTotalName,
 			Help: "How many HTTP requests with TLS processed on a service, partitioned by TLS version and TLS cipher.",
 		}, []string{"tls_version", "tls_cipher", "service"})
 		serviceReqDurations := newHistogramFrom(promState.collectors, stdprometheus.HistogramOpts{
 			Name:    serviceReqDurationName,
-			Help:    "How long it took to process the request on a service, partitioned by status code, protocol, and method.",
+			Help:    "How long it took to process the request on a service, partitioned by status code, gRPC status code, protocol, and method.",
 			Buckets: buckets,
-		}, []string{"code", "method", "protocol", "service"})
+		}, []string{"code", "grpc_code", "method", "protocol", "service"})
 		serviceOpenConns := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
 			Name: serviceOpenConnsName,
 			Help: "How many open connections exist on a service, partitioned by method and protocol.",
@@ -243,6 +295,10 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			Name: serviceServerUpName,
 			Help: "service server is up, described by gauge value of 0 or 1.",
 		}, []string{"service", "url"})
+		serviceSpillover := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: serviceSpilloverName,
+			Help: "How many requests spilled over from the first service of a priority service, partitioned by the service handling them.",
+		}, []string{"service", "pool"})
 
 		promState.describers = append(promState.describers, []func(chan<- *stdprometheus.Desc){
 			serviceReqs.cv.Describe,
@@ -251,6 +307,7 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			serviceOpenConns.gv.Describe,
 			serviceRetries.cv.Describe,
 			serviceServerUp.gv.Describe,
+			serviceSpillover.cv.Describe,
 		}...)
 
 		reg.serviceReqsCounter = serviceReqs
@@ -259,6 +316,32 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		reg.serviceOpenConnsGauge = serviceOpenConns
 		reg.serviceRetriesCounter = serviceRetries
 		reg.serviceServerUpGauge = serviceServerUp
+		reg.serviceSpilloverCounter = serviceSpillover
+
+		tcpConnDurations := newHistogramFrom(promState.collectors, stdprometheus.HistogramOpts{
+			Name:    tcpConnDurationName,
+			Help:    "How long TCP connections lasted, partitioned by router, service, and server.",
+			Buckets: buckets,
+		}, []string{"router", "service", "server"})
+		tcpBytes := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: tcpBytesTotalName,
+			Help: "How many bytes were forwarded on TCP connections, partitioned by router, service, server, and direction.",
+		}, []string{"router", "service", "server", "direction"})
+		tcpConnErrors := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: tcpConnErrorsTotalName,
+			Help: "How many TCP connections failed, partitioned by router, service, server, and reason.",
+		}, []string{"router", "service", "server", "reason"})
+
+		promState.describers = append(promState.describers, []func(chan<- *stdprometheus.Desc){
+			tcpConnDurations.hv.Describe,
+			tcpBytes.cv.Describe,
+			tcpConnErrors.cv.Describe,
+		}...)
+
+		reg.tcpEnabled = config.AddServicesLabels
+		reg.tcpConnDurationHistogram, _ = NewHistogramWithScale(tcpConnDurations, time.Second)
+		reg.tcpBytesCounter = tcpBytes
+		reg.tcpConnErrorsCounter = tcpConnErrors
 	}
 
 	return reg
@@ -305,6 +388,18 @@ func OnConfigurationUpdate(conf dynamic.Configuration, entryPoints []string) {
 		}
 	}
 
+	if conf.TCP != nil {
+		for name := range conf.TCP.Routers {
+			dynamicConfig.routers[name] = true
+		}
+
+		for serviceName := range conf.TCP.Services {
+			if _, ok := dynamicConfig.services[serviceName]; !ok {
+				dynamicConfig.services[serviceName] = make(map[string]bool)
+			}
+		}
+	}
+
 	promState.SetDynamicConfig(dynamicConfig)
 }
 
@@ -461,6 +556,7 @@ func newCounterFrom(collectors chan<- *collector, opts stdprometheus.CounterOpts
 	c := &counter{
 		name:       opts.Name,
 		cv:         cv,
+		labelNames: labelNames,
 		collectors: collectors,
 	}
 	if len(labelNames) == 0 {
@@ -472,6 +568,7 @@ func newCounterFrom(collectors chan<- *collector, opts stdprometheus.CounterOpts
 type counter struct {
 	name             string
 	cv               *stdprometheus.CounterVec
+	labelNames       []string
 	labelNamesValues labelNamesValues
 	collectors       chan<- *collector
 }
@@ -480,13 +577,14 @@ func (c *counter) With(labelValues ...string) metrics.Counter {
 	return &counter{
 		name:             c.name,
 		cv:               c.cv,
+		labelNames:       c.labelNames,
 		labelNamesValues: c.labelNamesValues.With(labelValues...),
 		collectors:       c.collectors,
 	}
 }
 
 func (c *counter) Add(delta float64) {
-	labels := c.labelNamesValues.ToLabels()
+	labels := c.labelNamesValues.ToLabels(c.labelNames...)
 	collector := c.cv.With(labels)
 	collector.Add(delta)
 	c.collectors <- newCollector(c.name, labels, collector, func() {
@@ -554,6 +652,7 @@ func newHistogramFrom(collectors chan<- *collector, opts stdprometheus.Histogram
 	return &histogram{
 		name:       opts.Name,
 		hv:         hv,
+		labelNames: labelNames,
 		collectors: collectors,
 	}
 }
@@ -561,6 +660,7 @@ func newHistogramFrom(collectors chan<- *collector, opts stdprometheus.Histogram
 type histogram struct {
 	name             string
 	hv               *stdprometheus.HistogramVec
+	labelNames       []string
 	labelNamesValues labelNamesValues
 	collectors       chan<- *collector
 }
@@ -569,13 +669,14 @@ func (h *histogram) With(labelValues ...string) metrics.Histogram {
 	return &histogram{
 		name:             h.name,
 		hv:               h.hv,
+		labelNames:       h.labelNames,
 		labelNamesValues: h.labelNamesValues.With(labelValues...),
 		collectors:       h.collectors,
 	}
 }
 
 func (h *histogram) Observe(value float64) {
-	labels := h.labelNamesValues.ToLabels()
+	labels := h.labelNamesValues.ToLabels(h.labelNames...)
 	observer := h.hv.With(labels)
 	observer.Observe(value)
 	// Do a type assertion to be sure that prometheus will be able to call the Collect method.
@@ -605,8 +706,13 @@ func (lvs labelNamesValues) With(labelValues ...string) labelNamesValues {
 
 // ToLabels is a convenience method to convert a labelNamesValues
 // to the native prometheus.Labels.
-func (lvs labelNamesValues) ToLabels() stdprometheus.Labels {
+// The given label names which have no value are set to an empty value,
+// as the metrics with optional labels (e.g. grpc_code) still require all their labels.
+func (lvs labelNamesValues) ToLabels(labelNames ...string) stdprometheus.Labels {
 	labels := stdprometheus.Labels{}
+	for _, name := range labelNames {
+		labels[name] = ""
+	}
 	for i := 0; i < len(lvs); i += 2 {
 		labels[lvs[i]] = lvs[i+1]
 	}
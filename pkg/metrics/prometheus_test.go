@@ -120,6 +120,18 @@ func TestPrometheus(t *testing.T) {
 		TLSCertsNotAfterTimestampGauge().
 		With("cn", "value", "serial", "value", "sans", "value").
 		Set(float64(time.Now().Unix()))
+	prometheusRegistry.
+		TLSCertsHandshakesCounter().
+		With("cn", "value", "serial", "value", "sans", "value", "store", "value").
+		Add(1)
+	prometheusRegistry.
+		TLSOCSPStapleAgeGauge().
+		With("cn", "value", "serial", "value").
+		Set(60)
+	prometheusRegistry.
+		TLSOCSPFailuresCounter().
+		With("cn", "value", "serial", "value").
+		Add(1)
 
 	prometheusRegistry.
 		EntryPointReqsCounter().
@@ -150,6 +162,14 @@ func TestPrometheus(t *testing.T) {
 		RouterOpenConnsGauge().
 		With("router", "demo", "service", "service1", "method", http.MethodGet, "protocol", "http").
 		Set(1)
+	prometheusRegistry.
+		RouterReqsBytesCounter().
+		With("router", "demo", "service", "service1", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http").
+		Add(100)
+	prometheusRegistry.
+		RouterRespsBytesCounter().
+		With("router", "demo", "service", "service1", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http").
+		Add(200)
 
 	prometheusRegistry.
 		ServiceReqsCounter().
@@ -175,6 +195,26 @@ func TestPrometheus(t *testing.T) {
 		ServiceServerUpGauge().
 		With("service", "service1", "url", "http://127.0.0.10:80").
 		Set(1)
+	prometheusRegistry.
+		ServiceSpilloverCounter().
+		With("service", "service1", "pool", "service2").
+		Add(1)
+	prometheusRegistry.
+		TCPConnDurationHistogram().
+		With("router", "router1", "service", "service1", "server", "127.0.0.10:80").
+		Observe(1)
+	prometheusRegistry.
+		TCPBytesCounter().
+		With("router", "router1", "service", "service1", "server", "127.0.0.10:80", "direction", "in").
+		Add(10)
+	prometheusRegistry.
+		TCPConnErrorsCounter().
+		With("router", "router1", "service", "service1", "server", "127.0.0.10:80", "reason", "reset").
+		Add(1)
+	prometheusRegistry.
+		HTTPCacheReqsCounter().
+		With("middleware", "cache1", "status", "hit").
+		Add(1)
 
 	delayForTrackingCompletion()
 
@@ -210,10 +250,37 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildTimestampAssert(t, tlsCertsNotAfterTimestamp),
 		},
+		{
+			name: tlsCertsHandshakesTotal,
+			labels: map[string]string{
+				"cn":     "value",
+				"serial": "value",
+				"sans":   "value",
+				"store":  "value",
+			},
+			assert: buildCounterAssert(t, tlsCertsHandshakesTotal, 1),
+		},
+		{
+			name: tlsOCSPStapleAgeName,
+			labels: map[string]string{
+				"cn":     "value",
+				"serial": "value",
+			},
+			assert: buildGaugeAssert(t, tlsOCSPStapleAgeName, 60),
+		},
+		{
+			name: tlsOCSPFailuresTotalName,
+			labels: map[string]string{
+				"cn":     "value",
+				"serial": "value",
+			},
+			assert: buildCounterAssert(t, tlsOCSPFailuresTotalName, 1),
+		},
 		{
 			name: entryPointReqsTotalName,
 			labels: map[string]string{
 				"code":       "200",
+				"grpc_code":  "",
 				"method":     http.MethodGet,
 				"protocol":   "http",
 				"entrypoint": "http",
@@ -224,6 +291,7 @@ func TestPrometheus(t *testing.T) {
 			name: entryPointReqDurationName,
 			labels: map[string]string{
 				"code":       "200",
+				"grpc_code":  "",
 				"method":     http.MethodGet,
 				"protocol":   "http",
 				"entrypoint": "http",
@@ -242,11 +310,12 @@ func TestPrometheus(t *testing.T) {
 		{
 			name: routerReqsTotalName,
 			labels: map[string]string{
-				"code":     "200",
-				"method":   http.MethodGet,
-				"protocol": "http",
-				"service":  "service1",
-				"router":   "demo",
+				"code":      "200",
+				"grpc_code": "",
+				"method":    http.MethodGet,
+				"protocol":  "http",
+				"service":   "service1",
+				"router":    "demo",
 			},
 			assert: buildCounterAssert(t, routerReqsTotalName, 1),
 		},
@@ -263,11 +332,12 @@ func TestPrometheus(t *testing.T) {
 		{
 			name: routerReqDurationName,
 			labels: map[string]string{
-				"code":     "200",
-				"method":   http.MethodGet,
-				"protocol": "http",
-				"service":  "service1",
-				"router":   "demo",
+				"code":      "200",
+				"grpc_code": "",
+				"method":    http.MethodGet,
+				"protocol":  "http",
+				"service":   "service1",
+				"router":    "demo",
 			},
 			assert: buildHistogramAssert(t, routerReqDurationName, 1),
 		},
@@ -281,13 +351,38 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildGaugeAssert(t, routerOpenConnsName, 1),
 		},
+		{
+			name: routerReqsBytesName,
+			labels: map[string]string{
+				"code":      "200",
+				"grpc_code": "",
+				"method":    http.MethodGet,
+				"protocol":  "http",
+				"service":   "service1",
+				"router":    "demo",
+			},
+			assert: buildCounterAssert(t, routerReqsBytesName, 100),
+		},
+		{
+			name: routerRespsBytesName,
+			labels: map[string]string{
+				"code":      "200",
+				"grpc_code": "",
+				"method":    http.MethodGet,
+				"protocol":  "http",
+				"service":   "service1",
+				"router":    "demo",
+			},
+			assert: buildCounterAssert(t, routerRespsBytesName, 200),
+		},
 		{
 			name: serviceReqsTotalName,
 			labels: map[string]string{
-				"code":     "200",
-				"method":   http.MethodGet,
-				"protocol": "http",
-				"service":  "service1",
+				"code":      "200",
+				"grpc_code": "",
+				"method":    http.MethodGet,
+				"protocol":  "http",
+				"service":   "service1",
 			},
 			assert: buildCounterAssert(t, serviceReqsTotalName, 1),
 		},
@@ -303,10 +398,11 @@ func TestPrometheus(t *testing.T) {
 		{
 			name: serviceReqDurationName,
 			labels: map[string]string{
-				"code":     "200",
-				"method":   http.MethodGet,
-				"protocol": "http",
-				"service":  "service1",
+				"code":      "200",
+				"grpc_code": "",
+				"method":    http.MethodGet,
+				"protocol":  "http",
+				"service":   "service1",
 			},
 			assert: buildHistogramAssert(t, serviceReqDurationName, 1),
 		},
@@ -334,6 +430,51 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildGaugeAssert(t, serviceServerUpName, 1),
 		},
+		{
+			name: serviceSpilloverName,
+			labels: map[string]string{
+				"service": "service1",
+				"pool":    "service2",
+			},
+			assert: buildCounterAssert(t, serviceSpilloverName, 1),
+		},
+		{
+			name: tcpConnDurationName,
+			labels: map[string]string{
+				"router":  "router1",
+				"service": "service1",
+				"server":  "127.0.0.10:80",
+			},
+			assert: buildHistogramAssert(t, tcpConnDurationName, 1),
+		},
+		{
+			name: tcpBytesTotalName,
+			labels: map[string]string{
+				"router":    "router1",
+				"service":   "service1",
+				"server":    "127.0.0.10:80",
+				"direction": "in",
+			},
+			assert: buildCounterAssert(t, tcpBytesTotalName, 10),
+		},
+		{
+			name: tcpConnErrorsTotalName,
+			labels: map[string]string{
+				"router":  "router1",
+				"service": "service1",
+				"server":  "127.0.0.10:80",
+				"reason":  "reset",
+			},
+			assert: buildCounterAssert(t, tcpConnErrorsTotalName, 1),
+		},
+		{
+			name: httpCacheReqsTotalName,
+			labels: map[string]string{
+				"middleware": "cache1",
+				"status":     "hit",
+			},
+			assert: buildCounterAssert(t, httpCacheReqsTotalName, 1),
+		},
 	}
 
 	for _, test := range testCases {
@@ -23,6 +23,11 @@ const (
 	statsdLastConfigReloadFailureName = "config.reload.lastFailureTimestamp"
 
 	statsdTLSCertsNotAfterTimestampName = "tls.certs.notAfterTimestamp"
+	statsdTLSCertsHandshakesTotalName   = "tls.certs.handshakes.total"
+	statsdTLSOCSPStapleAgeName          = "tls.ocsp.stapleAge"
+	statsdTLSOCSPFailuresTotalName      = "tls.ocsp.failures.total"
+
+	statsdGCPauseDurationName = "gc.pause.duration"
 
 	statsdEntryPointReqsName        = "entrypoint.request.total"
 	statsdEntryPointReqsTLSName     = "entrypoint.request.tls.total"
@@ -33,6 +38,8 @@ const (
 	statsdRouterReqsTLSName      = "router.request.tls.total"
 	statsdRouterReqsDurationName = "router.request.duration"
 	statsdRouterOpenConnsName    = "router.connections.open"
+	statsdRouterReqsBytesName    = "router.request.bytes.total"
+	statsdRouterRespsBytesName   = "router.response.bytes.total"
 
 	statsdServiceReqsName         = "service.request.total"
 	statsdServiceReqsTLSName      = "service.request.tls.total"
@@ -40,6 +47,13 @@ const (
 	statsdServiceRetriesTotalName = "service.retries.total"
 	statsdServiceServerUpName     = "service.server.up"
 	statsdServiceOpenConnsName    = "service.connections.open"
+	statsdServiceSpilloverName    = "service.spillover.total"
+
+	statsdTCPConnDurationName    = "tcp.connection.duration"
+	statsdTCPBytesTotalName      = "tcp.bytes.total"
+	statsdTCPConnErrorsTotalName = "tcp.connection.errors.total"
+
+	statsdHTTPCacheReqsTotalName = "http.cache.request.total"
 )
 
 // RegisterStatsd registers the metrics pusher if this didn't happen yet and creates a statsd Registry instance.
@@ -64,8 +78,14 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		lastConfigReloadSuccessGauge:   statsdClient.NewGauge(statsdLastConfigReloadSuccessName),
 		lastConfigReloadFailureGauge:   statsdClient.NewGauge(statsdLastConfigReloadFailureName),
 		tlsCertsNotAfterTimestampGauge: statsdClient.NewGauge(statsdTLSCertsNotAfterTimestampName),
+		tlsCertsHandshakesCounter:      statsdClient.NewCounter(statsdTLSCertsHandshakesTotalName, 1.0),
+		tlsOCSPStapleAgeGauge:          statsdClient.NewGauge(statsdTLSOCSPStapleAgeName),
+		tlsOCSPFailuresCounter:         statsdClient.NewCounter(statsdTLSOCSPFailuresTotalName, 1.0),
 	}
 
+	registry.gcPauseDurationHistogram, _ = NewHistogramWithScale(statsdClient.NewTiming(statsdGCPauseDurationName, 1.0), time.Millisecond)
+	registry.httpCacheReqsCounter = statsdClient.NewCounter(statsdHTTPCacheReqsTotalName, 1.0)
+
 	if config.AddEntryPointsLabels {
 		registry.epEnabled = config.AddEntryPointsLabels
 		registry.entryPointReqsCounter = statsdClient.NewCounter(statsdEntryPointReqsName, 1.0)
@@ -80,6 +100,8 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		registry.routerReqsTLSCounter = statsdClient.NewCounter(statsdRouterReqsTLSName, 1.0)
 		registry.routerReqDurationHistogram, _ = NewHistogramWithScale(statsdClient.NewTiming(statsdRouterReqsDurationName, 1.0), time.Millisecond)
 		registry.routerOpenConnsGauge = statsdClient.NewGauge(statsdRouterOpenConnsName)
+		registry.routerReqsBytesCounter = statsdClient.NewCounter(statsdRouterReqsBytesName, 1.0)
+		registry.routerRespsBytesCounter = statsdClient.NewCounter(statsdRouterRespsBytesName, 1.0)
 	}
 
 	if config.AddServicesLabels {
@@ -90,6 +112,12 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		registry.serviceRetriesCounter = statsdClient.NewCounter(statsdServiceRetriesTotalName, 1.0)
 		registry.serviceOpenConnsGauge = statsdClient.NewGauge(statsdServiceOpenConnsName)
 		registry.serviceServerUpGauge = statsdClient.NewGauge(statsdServiceServerUpName)
+		registry.serviceSpilloverCounter = statsdClient.NewCounter(statsdServiceSpilloverName, 1.0)
+
+		registry.tcpEnabled = config.AddServicesLabels
+		registry.tcpConnDurationHistogram, _ = NewHistogramWithScale(statsdClient.NewTiming(statsdTCPConnDurationName, 1.0), time.Millisecond)
+		registry.tcpBytesCounter = statsdClient.NewCounter(statsdTCPBytesTotalName, 1.0)
+		registry.tcpConnErrorsCounter = statsdClient.NewCounter(statsdTCPConnErrorsTotalName, 1.0)
 	}
 
 	return registry
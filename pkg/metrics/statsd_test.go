@@ -58,6 +58,11 @@ func testRegistry(t *testing.T, metricsPrefix string, registry Registry) {
 		metricsPrefix + ".config.reload.lastFailureTimestamp:1.000000|g\n",
 
 		metricsPrefix + ".tls.certs.notAfterTimestamp:1.000000|g\n",
+		metricsPrefix + ".tls.certs.handshakes.total:1.000000|c\n",
+		metricsPrefix + ".tls.ocsp.stapleAge:60.000000|g\n",
+		metricsPrefix + ".tls.ocsp.failures.total:1.000000|c\n",
+
+		metricsPrefix + ".gc.pause.duration:10000.000000|ms",
 
 		metricsPrefix + ".entrypoint.request.total:1.000000|c\n",
 		metricsPrefix + ".entrypoint.request.tls.total:1.000000|c\n",
@@ -68,6 +73,8 @@ func testRegistry(t *testing.T, metricsPrefix string, registry Registry) {
 		metricsPrefix + ".router.request.tls.total:1.000000|c\n",
 		metricsPrefix + ".router.request.duration:10000.000000|ms",
 		metricsPrefix + ".router.connections.open:1.000000|g\n",
+		metricsPrefix + ".router.request.bytes.total:100.000000|c\n",
+		metricsPrefix + ".router.response.bytes.total:200.000000|c\n",
 
 		metricsPrefix + ".service.request.total:2.000000|c\n",
 		metricsPrefix + ".service.request.tls.total:1.000000|c\n",
@@ -84,6 +91,11 @@ func testRegistry(t *testing.T, metricsPrefix string, registry Registry) {
 		registry.LastConfigReloadFailureGauge().Set(1)
 
 		registry.TLSCertsNotAfterTimestampGauge().With("key", "value").Set(1)
+		registry.TLSCertsHandshakesCounter().With("key", "value").Add(1)
+		registry.TLSOCSPStapleAgeGauge().With("key", "value").Set(60)
+		registry.TLSOCSPFailuresCounter().With("key", "value").Add(1)
+
+		registry.GCPauseDurationHistogram().Observe(10000)
 
 		registry.EntryPointReqsCounter().With("entrypoint", "test", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet).Add(1)
 		registry.EntryPointReqsTLSCounter().With("entrypoint", "test", "tls_version", "foo", "tls_cipher", "bar").Add(1)
@@ -95,6 +107,8 @@ func testRegistry(t *testing.T, metricsPrefix string, registry Registry) {
 		registry.RouterReqsTLSCounter().With("router", "demo", "service", "test", "tls_version", "foo", "tls_cipher", "bar").Add(1)
 		registry.RouterReqDurationHistogram().With("router", "demo", "service", "test", "code", strconv.Itoa(http.StatusOK)).Observe(10000)
 		registry.RouterOpenConnsGauge().With("router", "demo", "service", "test").Set(1)
+		registry.RouterReqsBytesCounter().With("router", "demo", "service", "test", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet).Add(100)
+		registry.RouterRespsBytesCounter().With("router", "demo", "service", "test", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet).Add(200)
 
 		registry.ServiceReqsCounter().With("service", "test", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet).Add(1)
 		registry.ServiceReqsCounter().With("service", "test", "code", strconv.Itoa(http.StatusNotFound), "method", http.MethodGet).Add(1)
@@ -0,0 +1,161 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+)
+
+type usageKind int
+
+const (
+	usageRequests usageKind = iota
+	usageRequestBytes
+	usageResponseBytes
+)
+
+// RouterUsage is the traffic of a router during a usage report period.
+type RouterUsage struct {
+	Requests      int64 `json:"requests"`
+	RequestBytes  int64 `json:"requestBytes"`
+	ResponseBytes int64 `json:"responseBytes"`
+}
+
+// UsageReport is the traffic of the routers during a period.
+type UsageReport struct {
+	Start   time.Time              `json:"start"`
+	End     time.Time              `json:"end"`
+	Routers map[string]RouterUsage `json:"routers"`
+}
+
+func newUsageReport(start time.Time, period time.Duration) UsageReport {
+	return UsageReport{
+		Start:   start,
+		End:     start.Add(period),
+		Routers: make(map[string]RouterUsage),
+	}
+}
+
+func (u UsageReport) clone() UsageReport {
+	routers := make(map[string]RouterUsage, len(u.Routers))
+	for name, usage := range u.Routers {
+		routers[name] = usage
+	}
+
+	u.Routers = routers
+	return u
+}
+
+// UsageRegistry is a Registry accumulating the requests, and the bytes of the requests and responses, of each router.
+// The traffic is reported by periods, aligned on multiples of the period duration,
+// and the registry keeps the report of the current period and of the previous one.
+type UsageRegistry struct {
+	*standardRegistry
+
+	period time.Duration
+	now    func() time.Time
+
+	mu       sync.Mutex
+	current  UsageReport
+	previous *UsageReport
+}
+
+// RegisterUsage creates a UsageRegistry whose reports last the given period.
+func RegisterUsage(period time.Duration) *UsageRegistry {
+	ur := &UsageRegistry{
+		standardRegistry: &standardRegistry{routerEnabled: true},
+		period:           period,
+		now:              time.Now,
+	}
+
+	ur.current = newUsageReport(ur.now().Truncate(period), period)
+
+	ur.standardRegistry.routerReqsCounter = &usageCounter{registry: ur, kind: usageRequests}
+	ur.standardRegistry.routerReqsBytesCounter = &usageCounter{registry: ur, kind: usageRequestBytes}
+	ur.standardRegistry.routerRespsBytesCounter = &usageCounter{registry: ur, kind: usageResponseBytes}
+
+	return ur
+}
+
+// Reports returns the report of the current period, and the report of the previous period if any.
+func (ur *UsageRegistry) Reports() (UsageReport, *UsageReport) {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	ur.rotate()
+
+	current := ur.current.clone()
+	if ur.previous == nil {
+		return current, nil
+	}
+
+	previous := ur.previous.clone()
+	return current, &previous
+}
+
+func (ur *UsageRegistry) add(router string, kind usageKind, delta float64) {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	ur.rotate()
+
+	usage := ur.current.Routers[router]
+	switch kind {
+	case usageRequests:
+		usage.Requests += int64(delta)
+	case usageRequestBytes:
+		usage.RequestBytes += int64(delta)
+	case usageResponseBytes:
+		usage.ResponseBytes += int64(delta)
+	}
+	ur.current.Routers[router] = usage
+}
+
+// rotate closes the current period if it is over.
+// It must be called with the lock held.
+func (ur *UsageRegistry) rotate() {
+	now := ur.now()
+	if now.Before(ur.current.End) {
+		return
+	}
+
+	start := now.Truncate(ur.period)
+
+	previous := ur.current
+	if previous.End.Before(start) {
+		// No traffic was recorded during the period preceding the current one.
+		previous = newUsageReport(start.Add(-ur.period), ur.period)
+	}
+
+	ur.previous = &previous
+	ur.current = newUsageReport(start, ur.period)
+}
+
+// usageCounter is a metrics.Counter accumulating its values in the report of the router given by the router label.
+type usageCounter struct {
+	registry *UsageRegistry
+	kind     usageKind
+	router   string
+}
+
+// With implements metrics.Counter.
+func (c *usageCounter) With(labelValues ...string) metrics.Counter {
+	router := c.router
+	for i := 0; i+1 < len(labelValues); i += 2 {
+		if labelValues[i] == "router" {
+			router = labelValues[i+1]
+		}
+	}
+
+	return &usageCounter{registry: c.registry, kind: c.kind, router: router}
+}
+
+// Add implements metrics.Counter.
+func (c *usageCounter) Add(delta float64) {
+	if c.router == "" {
+		return
+	}
+
+	c.registry.add(c.router, c.kind, delta)
+}
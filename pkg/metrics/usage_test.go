@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageRegistry(t *testing.T) {
+	now := time.Date(2021, 6, 1, 10, 30, 0, 0, time.UTC)
+
+	registry := RegisterUsage(time.Hour)
+	registry.now = func() time.Time { return now }
+	registry.current = newUsageReport(now.Truncate(time.Hour), time.Hour)
+
+	assert.True(t, registry.IsRouterEnabled())
+	assert.False(t, registry.IsEpEnabled())
+	assert.False(t, registry.IsSvcEnabled())
+
+	record := func(router string, requestBytes, responseBytes float64) {
+		labels := []string{"router", router, "service", "service1", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet}
+		registry.RouterReqsCounter().With(labels...).Add(1)
+		registry.RouterReqsBytesCounter().With(labels...).Add(requestBytes)
+		registry.RouterRespsBytesCounter().With(labels...).Add(responseBytes)
+	}
+
+	record("foo", 100, 1000)
+	record("foo", 50, 500)
+	record("bar", 10, 20)
+
+	current, previous := registry.Reports()
+	assert.Nil(t, previous)
+	assert.Equal(t, UsageReport{
+		Start: time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC),
+		End:   time.Date(2021, 6, 1, 11, 0, 0, 0, time.UTC),
+		Routers: map[string]RouterUsage{
+			"foo": {Requests: 2, RequestBytes: 150, ResponseBytes: 1500},
+			"bar": {Requests: 1, RequestBytes: 10, ResponseBytes: 20},
+		},
+	}, current)
+
+	// The reports returned are not updated afterwards.
+	now = now.Add(time.Hour)
+	record("foo", 1, 2)
+	assert.Equal(t, RouterUsage{Requests: 2, RequestBytes: 150, ResponseBytes: 1500}, current.Routers["foo"])
+
+	current, previous = registry.Reports()
+	require.NotNil(t, previous)
+	assert.Equal(t, time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC), previous.Start)
+	assert.Equal(t, RouterUsage{Requests: 2, RequestBytes: 150, ResponseBytes: 1500}, previous.Routers["foo"])
+	assert.Equal(t, time.Date(2021, 6, 1, 11, 0, 0, 0, time.UTC), current.Start)
+	assert.Equal(t, map[string]RouterUsage{"foo": {Requests: 1, RequestBytes: 1, ResponseBytes: 2}}, current.Routers)
+
+	// Without traffic during a whole period, the previous report is empty.
+	now = now.Add(2 * time.Hour)
+
+	current, previous = registry.Reports()
+	require.NotNil(t, previous)
+	assert.Equal(t, time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC), previous.Start)
+	assert.Empty(t, previous.Routers)
+	assert.Equal(t, time.Date(2021, 6, 1, 13, 0, 0, 0, time.UTC), current.Start)
+	assert.Empty(t, current.Routers)
+}
+
+func TestUsageRegistry_withoutRouterLabel(t *testing.T) {
+	registry := RegisterUsage(time.Hour)
+
+	registry.RouterReqsBytesCounter().With("service", "service1").Add(10)
+	registry.RouterReqsBytesCounter().Add(10)
+
+	current, _ := registry.Reports()
+	assert.Empty(t, current.Routers)
+}
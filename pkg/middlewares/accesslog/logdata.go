@@ -59,6 +59,8 @@ const (
 	OriginStatus = "OriginStatus"
 	// DownstreamStatus is the map key used for the HTTP status code returned to the client.
 	DownstreamStatus = "DownstreamStatus"
+	// GRPCStatus is the map key used for the gRPC status code returned to the client, for gRPC requests.
+	GRPCStatus = "GRPCStatus"
 	// DownstreamContentSize is the map key used for the number of bytes in the response entity returned to the client.
 	// This is in addition to the "Content-Length" header, which may be present in the origin response.
 	DownstreamContentSize = "DownstreamContentSize"
@@ -70,6 +72,8 @@ const (
 	Overhead = "Overhead"
 	// RetryAttempts is the map key used for the amount of attempts the request was retried.
 	RetryAttempts = "RetryAttempts"
+	// RequestID is the map key used for the ID set on the request by the RequestID middleware.
+	RequestID = "RequestID"
 
 	// TLSVersion is the version of TLS used in the request.
 	TLSVersion = "TLSVersion"
@@ -99,6 +103,7 @@ var defaultCoreKeys = [...]string{
 	OriginStatus,
 	DownstreamStatus,
 	DownstreamContentSize,
+	GRPCStatus,
 	RequestCount,
 }
 
@@ -116,6 +121,7 @@ func init() {
 	allCoreKeys[StartLocal] = struct{}{}
 	allCoreKeys[Overhead] = struct{}{}
 	allCoreKeys[RetryAttempts] = struct{}{}
+	allCoreKeys[RequestID] = struct{}{}
 	allCoreKeys[TLSVersion] = struct{}{}
 	allCoreKeys[TLSCipher] = struct{}{}
 }
@@ -2,6 +2,7 @@ package accesslog
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -19,6 +20,7 @@ import (
 	"github.com/sirupsen/logrus"
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
 	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
 	"github.com/traefik/traefik/v2/pkg/types"
 )
@@ -34,6 +36,15 @@ const (
 
 	// JSONFormat is the JSON logging format.
 	JSONFormat string = "json"
+
+	// CombinedFormat is the Apache combined logging format.
+	CombinedFormat string = "combined"
+
+	// W3CFormat is the W3C extended logging format.
+	W3CFormat string = "w3c"
+
+	// TemplateFormat is the logging format defined by a custom template.
+	TemplateFormat string = "template"
 )
 
 type noopCloser struct {
@@ -75,6 +86,11 @@ func WrapHandler(handler *Handler) alice.Constructor {
 
 // NewHandler creates a new Handler.
 func NewHandler(config *types.AccessLog) (*Handler, error) {
+	formatter, err := newFormatter(config)
+	if err != nil {
+		return nil, err
+	}
+
 	var file io.WriteCloser = noopCloser{os.Stdout}
 	if len(config.FilePath) > 0 {
 		f, err := openAccessLogFile(config.FilePath)
@@ -85,18 +101,6 @@ func NewHandler(config *types.AccessLog) (*Handler, error) {
 	}
 	logHandlerChan := make(chan handlerParams, config.BufferingSize)
 
-	var formatter logrus.Formatter
-
-	switch config.Format {
-	case CommonFormat:
-		formatter = new(CommonLogFormatter)
-	case JSONFormat:
-		formatter = new(logrus.JSONFormatter)
-	default:
-		log.WithoutContext().Errorf("unsupported access log format: %q, defaulting to common format instead.", config.Format)
-		formatter = new(CommonLogFormatter)
-	}
-
 	logger := &logrus.Logger{
 		Out:       file,
 		Formatter: formatter,
@@ -143,6 +147,27 @@ func NewHandler(config *types.AccessLog) (*Handler, error) {
 	return logHandler, nil
 }
 
+func newFormatter(config *types.AccessLog) (logrus.Formatter, error) {
+	switch config.Format {
+	case CommonFormat:
+		return new(CommonLogFormatter), nil
+	case JSONFormat:
+		return new(logrus.JSONFormatter), nil
+	case CombinedFormat:
+		return NewTemplateLogFormatter(CombinedLogTemplate, "")
+	case W3CFormat:
+		return NewTemplateLogFormatter(W3CLogTemplate, W3CLogHeader)
+	case TemplateFormat:
+		if config.Template == "" {
+			return nil, errors.New("the template access log format requires a template")
+		}
+		return NewTemplateLogFormatter(config.Template, "")
+	default:
+		log.WithoutContext().Errorf("unsupported access log format: %q, defaulting to common format instead.", config.Format)
+		return new(CommonLogFormatter), nil
+	}
+}
+
 func openAccessLogFile(filePath string) (*os.File, error) {
 	dir := filepath.Dir(filePath)
 
@@ -239,6 +264,12 @@ func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request, next http
 		logDataTable.Request.size = crr.count
 	}
 
+	if middlewares.IsGRPCRequest(req) {
+		if grpcStatus := middlewares.GetGRPCStatus(logDataTable.DownstreamResponse.headers); grpcStatus != "" {
+			core[GRPCStatus] = grpcStatus
+		}
+	}
+
 	if h.config.BufferingSize > 0 {
 		h.logHandlerChan <- handlerParams{
 			logDataTable: logDataTable,
@@ -273,6 +304,11 @@ func (h *Handler) Rotate() error {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	h.logger.Out = h.file
+
+	if formatter, ok := h.logger.Formatter.(*TemplateLogFormatter); ok {
+		formatter.Reset()
+	}
+
 	return nil
 }
 
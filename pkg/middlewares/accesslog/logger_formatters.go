@@ -3,6 +3,10 @@ package accesslog
 import (
 	"bytes"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -52,6 +56,148 @@ func (f *CommonLogFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 	return b.Bytes(), err
 }
 
+// CombinedLogTemplate is the template of the Apache combined log format.
+const CombinedLogTemplate = `{{ .Get "ClientHost" }} - {{ .Get "ClientUsername" }} [{{ .Start "02/Jan/2006:15:04:05 -0700" }}] "{{ .Get "RequestMethod" }} {{ .Get "RequestPath" }} {{ .Get "RequestProtocol" }}" {{ .Get "DownstreamStatus" }} {{ .Size "DownstreamContentSize" }} {{ .Get "request_Referer" | quote }} {{ .Get "request_User-Agent" | quote }}`
+
+// W3CLogTemplate is the template of the W3C extended log format, with the fields of W3CLogHeader.
+const W3CLogTemplate = `{{ .StartUTC "2006-01-02" }} {{ .StartUTC "15:04:05" }} {{ .Get "ClientHost" | w3c }} {{ .Get "ClientUsername" | w3c }} {{ .Get "RequestMethod" | w3c }} {{ .Get "RequestPath" | uriStem | w3c }} {{ .Get "RequestPath" | uriQuery | w3c }} {{ .Get "DownstreamStatus" }} {{ .Get "DownstreamContentSize" }} {{ .Seconds "Duration" }} {{ .Get "RequestProtocol" | w3c }} {{ .Get "request_User-Agent" | w3c }} {{ .Get "request_Referer" | w3c }}`
+
+// W3CLogHeader is the header of the W3C extended log format, declaring the fields of W3CLogTemplate.
+const W3CLogHeader = `#Version: 1.0
+#Fields: date time c-ip cs-username cs-method cs-uri-stem cs-uri-query sc-status sc-bytes time-taken cs-version cs(User-Agent) cs(Referer)
+`
+
+// templateFuncs are the functions available in the access log templates.
+var templateFuncs = template.FuncMap{
+	"quote": func(s string) string {
+		return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+	},
+	"w3c": func(s string) string {
+		return strings.ReplaceAll(s, " ", "+")
+	},
+	"uriStem": func(s string) string {
+		return strings.SplitN(s, "?", 2)[0]
+	},
+	"uriQuery": func(s string) string {
+		parts := strings.SplitN(s, "?", 2)
+		if len(parts) < 2 || parts[1] == "" {
+			return defaultValue
+		}
+		return parts[1]
+	},
+}
+
+// TemplateLogFormatter provides formatting with a Go template, executed with a LogTemplateData.
+type TemplateLogFormatter struct {
+	tpl    *template.Template
+	header string
+
+	headerMu      sync.Mutex
+	headerWritten bool
+}
+
+// NewTemplateLogFormatter creates a new TemplateLogFormatter with the given template.
+// The given header, if any, is written before the first entry, and again after each call to Reset.
+func NewTemplateLogFormatter(text, header string) (*TemplateLogFormatter, error) {
+	tpl, err := template.New("accesslog").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid access log template: %w", err)
+	}
+
+	return &TemplateLogFormatter{tpl: tpl, header: header}, nil
+}
+
+// Format formats the log entry with the template.
+func (f *TemplateLogFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	b := &bytes.Buffer{}
+
+	f.headerMu.Lock()
+	if !f.headerWritten {
+		b.WriteString(f.header)
+		f.headerWritten = true
+	}
+	f.headerMu.Unlock()
+
+	if err := f.tpl.Execute(b, LogTemplateData(entry.Data)); err != nil {
+		return nil, err
+	}
+
+	b.WriteString("\n")
+
+	return b.Bytes(), nil
+}
+
+// Reset makes the header to be written again before the next entry, e.g. once the log file has been rotated.
+func (f *TemplateLogFormatter) Reset() {
+	f.headerMu.Lock()
+	f.headerWritten = false
+	f.headerMu.Unlock()
+}
+
+// LogTemplateData is the data given to the access log templates: the fields of the log entry.
+type LogTemplateData logrus.Fields
+
+// Get returns the value of the given field, or "-" if it is missing or empty.
+func (d LogTemplateData) Get(key string) string {
+	return fmt.Sprint(toLog(logrus.Fields(d), key, defaultValue, false))
+}
+
+// Size returns the value of the given size field, or "-" if it is missing or zero.
+func (d LogTemplateData) Size(key string) string {
+	value := d.Get(key)
+	if value == "0" {
+		return defaultValue
+	}
+
+	return value
+}
+
+// Start returns the time at which the request processing started, with the given layout.
+func (d LogTemplateData) Start(layout string) string {
+	if v, ok := d[StartUTC].(time.Time); ok {
+		return v.Format(layout)
+	}
+
+	if v, ok := d[StartLocal].(time.Time); ok {
+		return v.Local().Format(layout)
+	}
+
+	return defaultValue
+}
+
+// StartUTC returns the time at which the request processing started, in UTC, with the given layout.
+func (d LogTemplateData) StartUTC(layout string) string {
+	if v, ok := d[StartUTC].(time.Time); ok {
+		return v.UTC().Format(layout)
+	}
+
+	if v, ok := d[StartLocal].(time.Time); ok {
+		return v.UTC().Format(layout)
+	}
+
+	return defaultValue
+}
+
+// Millis returns the value of the given duration field in milliseconds, or "-" if it is missing.
+func (d LogTemplateData) Millis(key string) string {
+	v, ok := d[key].(time.Duration)
+	if !ok {
+		return defaultValue
+	}
+
+	return strconv.FormatInt(v.Milliseconds(), 10)
+}
+
+// Seconds returns the value of the given duration field in seconds, with a millisecond precision, or "-" if it is missing.
+func (d LogTemplateData) Seconds(key string) string {
+	v, ok := d[key].(time.Duration)
+	if !ok {
+		return defaultValue
+	}
+
+	return strconv.FormatFloat(v.Seconds(), 'f', 3, 64)
+}
+
 func toLog(fields logrus.Fields, key, defaultValue string, quoted bool) interface{} {
 	if v, ok := fields[key]; ok {
 		if v == nil {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCommonLogFormatter_Format(t *testing.T) {
@@ -161,3 +162,101 @@ func Test_toLog(t *testing.T) {
 		})
 	}
 }
+
+func TestTemplateLogFormatter_Format(t *testing.T) {
+	data := map[string]interface{}{
+		StartUTC:               time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC),
+		Duration:               1234 * time.Millisecond,
+		ClientHost:             "10.0.0.1",
+		ClientUsername:         "-",
+		RequestMethod:          http.MethodGet,
+		RequestPath:            "/foo?bar=baz",
+		RequestProtocol:        "HTTP/1.1",
+		DownstreamStatus:       200,
+		DownstreamContentSize:  int64(132),
+		RequestRefererHeader:   "http://example.com",
+		RequestUserAgentHeader: `curl "7.0"`,
+		RouterName:             "foo",
+	}
+
+	testCases := []struct {
+		desc        string
+		template    string
+		header      string
+		data        map[string]interface{}
+		expectedLog string
+	}{
+		{
+			desc:     "combined",
+			template: CombinedLogTemplate,
+			data:     data,
+			expectedLog: `10.0.0.1 - - [10/Nov/2009:23:00:00 +0000] "GET /foo?bar=baz HTTP/1.1" 200 132 "http://example.com" "curl \"7.0\""
+`,
+		},
+		{
+			desc:     "combined without data",
+			template: CombinedLogTemplate,
+			data:     map[string]interface{}{DownstreamContentSize: int64(0)},
+			expectedLog: `- - - [-] "- - -" - - "-" "-"
+`,
+		},
+		{
+			desc:     "w3c",
+			template: W3CLogTemplate,
+			header:   W3CLogHeader,
+			data:     data,
+			expectedLog: `#Version: 1.0
+#Fields: date time c-ip cs-username cs-method cs-uri-stem cs-uri-query sc-status sc-bytes time-taken cs-version cs(User-Agent) cs(Referer)
+2009-11-10 23:00:00 10.0.0.1 - GET /foo bar=baz 200 132 1.234 HTTP/1.1 curl+"7.0" http://example.com
+`,
+		},
+		{
+			desc:     "custom template",
+			template: `{{ .Get "RouterName" }} {{ .Get "ServiceName" }} {{ .Millis "Duration" }}ms {{ .Start "2006-01-02T15:04:05Z07:00" }}`,
+			data:     data,
+			expectedLog: `foo - 1234ms 2009-11-10T23:00:00Z
+`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			formatter, err := NewTemplateLogFormatter(test.template, test.header)
+			require.NoError(t, err)
+
+			raw, err := formatter.Format(&logrus.Entry{Data: test.data})
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedLog, string(raw))
+		})
+	}
+}
+
+func TestTemplateLogFormatter_header(t *testing.T) {
+	formatter, err := NewTemplateLogFormatter(`{{ .Get "ClientHost" }}`, "#header\n")
+	require.NoError(t, err)
+
+	entry := &logrus.Entry{Data: logrus.Fields{ClientHost: "10.0.0.1"}}
+
+	raw, err := formatter.Format(entry)
+	require.NoError(t, err)
+	assert.Equal(t, "#header\n10.0.0.1\n", string(raw))
+
+	raw, err = formatter.Format(entry)
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1\n", string(raw))
+
+	formatter.Reset()
+
+	raw, err = formatter.Format(entry)
+	require.NoError(t, err)
+	assert.Equal(t, "#header\n10.0.0.1\n", string(raw))
+}
+
+func TestNewTemplateLogFormatter_invalid(t *testing.T) {
+	_, err := NewTemplateLogFormatter(`{{ .Get "ClientHost" `, "")
+	require.Error(t, err)
+}
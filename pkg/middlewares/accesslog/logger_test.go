@@ -471,6 +471,34 @@ func TestLoggerJSON(t *testing.T) {
 	}
 }
 
+func TestLoggerGRPCStatus(t *testing.T) {
+	logFilePath := filepath.Join(t.TempDir(), logFileNameSuffix)
+
+	logger, err := NewHandler(&types.AccessLog{FilePath: logFilePath, Format: JSONFormat})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "http://foo.bar/grpc.Service/Method", nil)
+	req.Header.Set("Content-Type", "application/grpc")
+
+	logger.ServeHTTP(httptest.NewRecorder(), req, http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+		rw.Header().Set(http.TrailerPrefix+"Grpc-Status", "5")
+	}))
+
+	err = logger.Close()
+	require.NoError(t, err)
+
+	logData, err := os.ReadFile(logFilePath)
+	require.NoError(t, err)
+
+	jsonData := make(map[string]interface{})
+	err = json.Unmarshal(logData, &jsonData)
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(http.StatusOK), jsonData[DownstreamStatus])
+	assert.Equal(t, "5", jsonData[GRPCStatus])
+}
+
 func TestNewLogHandlerOutputStdout(t *testing.T) {
 	testCases := []struct {
 		desc        string
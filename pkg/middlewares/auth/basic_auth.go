@@ -23,26 +23,29 @@ const (
 type basicAuth struct {
 	next         http.Handler
 	auth         *goauth.BasicAuth
-	users        map[string]string
 	headerField  string
 	removeHeader bool
 	name         string
+	users        *reloadingFile
 }
 
 // NewBasic creates a basicAuth middleware.
 func NewBasic(ctx context.Context, next http.Handler, authConfig dynamic.BasicAuth, name string) (http.Handler, error) {
 	log.FromContext(middlewares.GetLoggerCtx(ctx, name, basicTypeName)).Debug("Creating middleware")
-	users, err := getUsers(authConfig.UsersFile, authConfig.Users, basicUserParser)
+
+	users, err := newReloadingFile(authConfig.UsersFile, "users file", func() (interface{}, error) {
+		return getUsers(authConfig.UsersFile, authConfig.Users, basicUserParser)
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	ba := &basicAuth{
 		next:         next,
-		users:        users,
 		headerField:  authConfig.HeaderField,
 		removeHeader: authConfig.RemoveHeader,
 		name:         name,
+		users:        users,
 	}
 
 	realm := defaultRealm
@@ -62,6 +65,8 @@ func (b *basicAuth) GetTracingInformation() (string, ext.SpanKindEnum) {
 func (b *basicAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), b.name, basicTypeName))
 
+	b.users.reload(logger)
+
 	user, password, ok := req.BasicAuth()
 	if ok {
 		secret := b.auth.Secrets(user, b.auth.Realm)
@@ -98,7 +103,7 @@ func (b *basicAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 }
 
 func (b *basicAuth) secretBasic(user, realm string) string {
-	if secret, ok := b.users[user]; ok {
+	if secret, ok := b.users.get().(map[string]string)[user]; ok {
 		return secret
 	}
 
@@ -7,7 +7,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -278,3 +280,52 @@ func TestBasicAuthUsersFromFile(t *testing.T) {
 		})
 	}
 }
+
+func TestBasicAuthUsersFileReload(t *testing.T) {
+	usersFile := filepath.Join(t.TempDir(), "auth-users")
+	err := os.WriteFile(usersFile, []byte("test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/\n"), 0o600)
+	require.NoError(t, err)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "traefik")
+	})
+
+	handler, err := NewBasic(context.Background(), next, dynamic.BasicAuth{UsersFile: usersFile}, "authName")
+	require.NoError(t, err)
+
+	authenticate := func(user, password string) int {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+		req.SetBasicAuth(user, password)
+
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+
+		return rw.Code
+	}
+
+	updateFile := func(content string, modTime time.Time) {
+		err = os.WriteFile(usersFile, []byte(content), 0o600)
+		require.NoError(t, err)
+		err = os.Chtimes(usersFile, modTime, modTime)
+		require.NoError(t, err)
+
+		// Forces the check of the file on the next request.
+		ba := handler.(*basicAuth)
+		ba.users.mu.Lock()
+		ba.users.nextCheck = time.Time{}
+		ba.users.mu.Unlock()
+	}
+
+	assert.Equal(t, http.StatusOK, authenticate("test", "test"))
+	assert.Equal(t, http.StatusUnauthorized, authenticate("test2", "test2"))
+
+	updateFile("test2:$apr1$d9hr9HBB$4HxwgUir3HP4EsggP/QNo0\n", time.Now().Add(time.Minute))
+
+	assert.Equal(t, http.StatusUnauthorized, authenticate("test", "test"))
+	assert.Equal(t, http.StatusOK, authenticate("test2", "test2"))
+
+	// An invalid file does not replace the previous users.
+	updateFile("invalid\n", time.Now().Add(2*time.Minute))
+
+	assert.Equal(t, http.StatusOK, authenticate("test2", "test2"))
+}
@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+var (
+	// ErrBypassNotFound is returned when removing a bypass which does not exist.
+	ErrBypassNotFound = errors.New("bypass not found")
+	// ErrBypassDuration is returned when adding a bypass with a duration out of bounds.
+	ErrBypassDuration = errors.New("invalid bypass duration")
+)
+
+// Bypass is an emergency bypass of an auth middleware for a router, until it expires.
+type Bypass struct {
+	Router     string    `json:"router"`
+	Middleware string    `json:"middleware"`
+	Reason     string    `json:"reason,omitempty"`
+	Requester  string    `json:"requester,omitempty"`
+	Since      time.Time `json:"since"`
+	Until      time.Time `json:"until"`
+}
+
+type bypassKey struct {
+	router     string
+	middleware string
+}
+
+type bypassEntry struct {
+	Bypass
+	timer *time.Timer
+}
+
+// Bypasses holds the emergency bypasses of the auth middlewares, which let the requests reach the next handler
+// without being authenticated, until they expire or are removed.
+// The creation, the removal, and the expiry of the bypasses are logged.
+type Bypasses struct {
+	maxDuration time.Duration
+
+	mu       sync.RWMutex
+	bypasses map[bypassKey]*bypassEntry
+}
+
+// NewBypasses creates a new Bypasses, whose bypasses cannot last longer than the given duration.
+func NewBypasses(maxDuration time.Duration) *Bypasses {
+	return &Bypasses{
+		maxDuration: maxDuration,
+		bypasses:    make(map[bypassKey]*bypassEntry),
+	}
+}
+
+// Add bypasses the given auth middleware for the given router during the given duration,
+// replacing the current bypass of the middleware for the router if any.
+func (b *Bypasses) Add(routerName, middlewareName string, duration time.Duration, reason, requester string) (Bypass, error) {
+	if duration <= 0 || duration > b.maxDuration {
+		return Bypass{}, fmt.Errorf("%w: %s is not between 0s and %s", ErrBypassDuration, duration, b.maxDuration)
+	}
+
+	now := time.Now()
+	key := bypassKey{router: routerName, middleware: middlewareName}
+	entry := &bypassEntry{
+		Bypass: Bypass{
+			Router:     routerName,
+			Middleware: middlewareName,
+			Reason:     reason,
+			Requester:  requester,
+			Since:      now,
+			Until:      now.Add(duration),
+		},
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if current, ok := b.bypasses[key]; ok {
+		current.timer.Stop()
+	}
+
+	entry.timer = time.AfterFunc(duration, func() { b.expire(key, entry) })
+	b.bypasses[key] = entry
+
+	log.WithoutContext().Warnf("Auth middleware %q bypassed for router %q by %q until %s: %s",
+		middlewareName, routerName, requester, entry.Until.Format(time.RFC3339), reason)
+
+	return entry.Bypass, nil
+}
+
+// Remove removes the bypass of the given auth middleware for the given router.
+func (b *Bypasses) Remove(routerName, middlewareName, requester string) error {
+	key := bypassKey{router: routerName, middleware: middlewareName}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	entry, ok := b.bypasses[key]
+	if !ok {
+		return ErrBypassNotFound
+	}
+
+	entry.timer.Stop()
+	delete(b.bypasses, key)
+
+	log.WithoutContext().Warnf("Auth middleware %q restored for router %q by %q", middlewareName, routerName, requester)
+
+	return nil
+}
+
+// List returns the current bypasses, sorted by router and middleware.
+func (b *Bypasses) List() []Bypass {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	bypasses := make([]Bypass, 0, len(b.bypasses))
+	for _, entry := range b.bypasses {
+		bypasses = append(bypasses, entry.Bypass)
+	}
+
+	sort.Slice(bypasses, func(i, j int) bool {
+		if bypasses[i].Router != bypasses[j].Router {
+			return bypasses[i].Router < bypasses[j].Router
+		}
+		return bypasses[i].Middleware < bypasses[j].Middleware
+	})
+
+	return bypasses
+}
+
+// Active reports whether the given auth middleware is bypassed for the given router.
+func (b *Bypasses) Active(routerName, middlewareName string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	entry, ok := b.bypasses[bypassKey{router: routerName, middleware: middlewareName}]
+
+	return ok && time.Now().Before(entry.Until)
+}
+
+// Wrap returns a handler calling the given auth middleware handler,
+// or directly the next handler while the middleware is bypassed for the given router.
+func (b *Bypasses) Wrap(routerName, middlewareName string, handler, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if b.Active(routerName, middlewareName) {
+			log.FromContext(req.Context()).Debugf("Auth middleware %q bypassed for router %q", middlewareName, routerName)
+			next.ServeHTTP(rw, req)
+			return
+		}
+
+		handler.ServeHTTP(rw, req)
+	})
+}
+
+// expire removes the given bypass entry, unless it has been replaced or removed in the meantime.
+func (b *Bypasses) expire(key bypassKey, entry *bypassEntry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.bypasses[key] != entry {
+		return
+	}
+
+	delete(b.bypasses, key)
+
+	log.WithoutContext().Warnf("Auth middleware %q bypass for router %q expired", key.middleware, key.router)
+}
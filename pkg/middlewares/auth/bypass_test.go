@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBypasses(t *testing.T) {
+	bypasses := NewBypasses(time.Hour)
+
+	authHandler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusUnauthorized)
+	})
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+	handler := bypasses.Wrap("router@file", "auth@file", authHandler, next)
+
+	serve := func() int {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, serve())
+
+	_, err := bypasses.Add("router@file", "auth@file", 2*time.Hour, "idp outage", "admin")
+	require.ErrorIs(t, err, ErrBypassDuration)
+
+	_, err = bypasses.Add("router@file", "auth@file", 0, "idp outage", "admin")
+	require.ErrorIs(t, err, ErrBypassDuration)
+
+	bypass, err := bypasses.Add("router@file", "auth@file", time.Minute, "idp outage", "admin")
+	require.NoError(t, err)
+	assert.Equal(t, "router@file", bypass.Router)
+	assert.Equal(t, "auth@file", bypass.Middleware)
+	assert.Equal(t, time.Minute, bypass.Until.Sub(bypass.Since))
+
+	assert.Equal(t, http.StatusOK, serve())
+	assert.Equal(t, []Bypass{bypass}, bypasses.List())
+
+	// Other routers are not bypassed.
+	assert.False(t, bypasses.Active("other@file", "auth@file"))
+
+	require.NoError(t, bypasses.Remove("router@file", "auth@file", "admin"))
+	require.ErrorIs(t, bypasses.Remove("router@file", "auth@file", "admin"), ErrBypassNotFound)
+
+	assert.Equal(t, http.StatusUnauthorized, serve())
+	assert.Empty(t, bypasses.List())
+}
+
+func TestBypasses_expiry(t *testing.T) {
+	bypasses := NewBypasses(time.Hour)
+
+	_, err := bypasses.Add("router@file", "auth@file", 50*time.Millisecond, "idp outage", "admin")
+	require.NoError(t, err)
+	assert.True(t, bypasses.Active("router@file", "auth@file"))
+
+	assert.Eventually(t, func() bool {
+		return !bypasses.Active("router@file", "auth@file") && len(bypasses.List()) == 0
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,262 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	hmacTypeName = "HMACAuth"
+
+	hexEncoding    = "hex"
+	base64Encoding = "base64"
+)
+
+// hmacAlgorithms are the supported hash functions, by name.
+var hmacAlgorithms = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+type hmacAuth struct {
+	next            http.Handler
+	name            string
+	header          string
+	prefix          string
+	newHash         func() hash.Hash
+	encoding        string
+	timestampHeader string
+	clockSkew       time.Duration
+	maxBodySize     int64
+	keys            *reloadingFile
+}
+
+// NewHMAC creates a HMAC signature authentication middleware.
+func NewHMAC(ctx context.Context, next http.Handler, config dynamic.HMACAuth, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, hmacTypeName)).Debug("Creating middleware")
+
+	if config.Header == "" {
+		return nil, errors.New("the signature header is missing")
+	}
+
+	newHash, ok := hmacAlgorithms[strings.ToLower(config.Algorithm)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported algorithm: %q", config.Algorithm)
+	}
+
+	encoding := strings.ToLower(config.Encoding)
+	if encoding != hexEncoding && encoding != base64Encoding {
+		return nil, fmt.Errorf("unsupported encoding: %q", config.Encoding)
+	}
+
+	if config.TimestampHeader != "" && config.ClockSkew <= 0 {
+		return nil, fmt.Errorf("invalid clock skew: %s", time.Duration(config.ClockSkew))
+	}
+
+	if config.MaxBodySize <= 0 {
+		return nil, fmt.Errorf("invalid max body size: %d", config.MaxBodySize)
+	}
+
+	keys, err := newReloadingFile(config.KeysFile, "keys file", func() (interface{}, error) {
+		return getKeys(config.KeysFile, config.Keys)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &hmacAuth{
+		next:            next,
+		name:            name,
+		header:          config.Header,
+		prefix:          config.Prefix,
+		newHash:         newHash,
+		encoding:        encoding,
+		timestampHeader: config.TimestampHeader,
+		clockSkew:       time.Duration(config.ClockSkew),
+		maxBodySize:     config.MaxBodySize,
+		keys:            keys,
+	}, nil
+}
+
+func (h *hmacAuth) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return h.name, tracing.SpanKindNoneEnum
+}
+
+func (h *hmacAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), h.name, hmacTypeName))
+
+	h.keys.reload(logger)
+
+	signature, err := h.signature(req)
+	if err != nil {
+		h.unauthorized(rw, req, logger, err)
+		return
+	}
+
+	var timestamp string
+	if h.timestampHeader != "" {
+		timestamp = req.Header.Get(h.timestampHeader)
+		if err = h.checkTimestamp(timestamp); err != nil {
+			h.unauthorized(rw, req, logger, err)
+			return
+		}
+	}
+
+	if req.ContentLength > h.maxBodySize {
+		logger.Debugf("Request body of %d bytes larger than %d bytes", req.ContentLength, h.maxBodySize)
+		http.Error(rw, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, h.maxBodySize+1))
+	if err != nil {
+		logger.Debugf("Error while reading the request body: %v", err)
+		http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	if int64(len(body)) > h.maxBodySize {
+		logger.Debugf("Request body larger than %d bytes", h.maxBodySize)
+		http.Error(rw, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if !h.verify(signature, timestamp, body) {
+		h.unauthorized(rw, req, logger, errors.New("invalid signature"))
+		return
+	}
+
+	logger.Debug("Authentication succeeded")
+
+	// The body has been consumed to compute the signature, so it is replaced by its copy.
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+
+	h.next.ServeHTTP(rw, req)
+}
+
+func (h *hmacAuth) unauthorized(rw http.ResponseWriter, req *http.Request, logger log.Logger, err error) {
+	logger.Debugf("Authentication failed: %v", err)
+	tracing.SetErrorWithEvent(req, "Authentication failed")
+
+	http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+}
+
+// signature returns the decoded signature of the request.
+func (h *hmacAuth) signature(req *http.Request) ([]byte, error) {
+	value := req.Header.Get(h.header)
+	if value == "" {
+		return nil, fmt.Errorf("missing %s header", h.header)
+	}
+
+	if !strings.HasPrefix(value, h.prefix) {
+		return nil, fmt.Errorf("the signature does not start with %q", h.prefix)
+	}
+	value = strings.TrimPrefix(value, h.prefix)
+
+	var signature []byte
+	var err error
+	if h.encoding == base64Encoding {
+		signature, err = base64.StdEncoding.DecodeString(value)
+	} else {
+		signature, err = hex.DecodeString(value)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("malformed signature: %w", err)
+	}
+
+	return signature, nil
+}
+
+// checkTimestamp checks that the timestamp, in seconds since the epoch, is within the allowed clock skew.
+func (h *hmacAuth) checkTimestamp(timestamp string) error {
+	if timestamp == "" {
+		return fmt.Errorf("missing %s header", h.timestampHeader)
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("malformed timestamp: %w", err)
+	}
+
+	skew := time.Since(time.Unix(seconds, 0))
+	if skew < 0 {
+		skew = -skew
+	}
+
+	if skew > h.clockSkew {
+		return fmt.Errorf("timestamp %d outside of the allowed clock skew", seconds)
+	}
+
+	return nil
+}
+
+// verify reports whether the signature is valid for any of the keys.
+// When the timestamp is checked, the signed content is the timestamp and the body, separated by a dot.
+func (h *hmacAuth) verify(signature []byte, timestamp string, body []byte) bool {
+	for _, key := range h.keys.get().([][]byte) {
+		mac := hmac.New(h.newHash, key)
+		if h.timestampHeader != "" {
+			mac.Write([]byte(timestamp + "."))
+		}
+		mac.Write(body)
+
+		if hmac.Equal(mac.Sum(nil), signature) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getKeys returns the keys of the file, one per line, followed by the inline keys.
+func getKeys(fileName string, inlineKeys []string) ([][]byte, error) {
+	var lines []string
+	if fileName != "" {
+		var err error
+		lines, err = getLinesFromFile(fileName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	lines = append(lines, inlineKeys...)
+	if len(lines) == 0 {
+		return nil, errors.New("no key")
+	}
+
+	keys := make([][]byte, 0, len(lines))
+	for _, line := range lines {
+		if line == "" {
+			return nil, errors.New("empty key")
+		}
+		keys = append(keys, []byte(line))
+	}
+
+	return keys, nil
+}
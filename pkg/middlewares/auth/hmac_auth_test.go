@@ -0,0 +1,369 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestNewHMAC(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      func(config *dynamic.HMACAuth)
+		expectedErr bool
+	}{
+		{
+			desc:   "default configuration",
+			config: func(config *dynamic.HMACAuth) {},
+		},
+		{
+			desc: "missing header",
+			config: func(config *dynamic.HMACAuth) {
+				config.Header = ""
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "unsupported algorithm",
+			config: func(config *dynamic.HMACAuth) {
+				config.Algorithm = "md5"
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "unsupported encoding",
+			config: func(config *dynamic.HMACAuth) {
+				config.Encoding = "base32"
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "invalid clock skew",
+			config: func(config *dynamic.HMACAuth) {
+				config.TimestampHeader = "X-Timestamp"
+				config.ClockSkew = 0
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "invalid max body size",
+			config: func(config *dynamic.HMACAuth) {
+				config.MaxBodySize = 0
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "no key",
+			config: func(config *dynamic.HMACAuth) {
+				config.Keys = nil
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "empty key",
+			config: func(config *dynamic.HMACAuth) {
+				config.Keys = []string{"secret", ""}
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "missing keys file",
+			config: func(config *dynamic.HMACAuth) {
+				config.KeysFile = filepath.Join(t.TempDir(), "missing")
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := dynamic.HMACAuth{Keys: []string{"secret"}}
+			config.SetDefaults()
+			test.config(&config)
+
+			next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+			_, err := NewHMAC(context.Background(), next, config, "hmacAuth")
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestHMACAuth(t *testing.T) {
+	sign := func(key, content string) []byte {
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write([]byte(content))
+		return mac.Sum(nil)
+	}
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	past := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+	testCases := []struct {
+		desc         string
+		config       func(config *dynamic.HMACAuth)
+		body         string
+		headers      map[string]string
+		expectedCode int
+	}{
+		{
+			desc:         "valid signature",
+			body:         "payload",
+			headers:      map[string]string{"X-Signature": hex.EncodeToString(sign("secret", "payload"))},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "valid signature with a previous key",
+			body:         "payload",
+			headers:      map[string]string{"X-Signature": hex.EncodeToString(sign("previous", "payload"))},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "signature with an unknown key",
+			body:         "payload",
+			headers:      map[string]string{"X-Signature": hex.EncodeToString(sign("unknown", "payload"))},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc:         "signature of another body",
+			body:         "payload",
+			headers:      map[string]string{"X-Signature": hex.EncodeToString(sign("secret", "other"))},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc:         "missing signature",
+			body:         "payload",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc:         "malformed signature",
+			body:         "payload",
+			headers:      map[string]string{"X-Signature": "not hex"},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc: "signature with a prefix",
+			config: func(config *dynamic.HMACAuth) {
+				config.Header = "X-Hub-Signature-256"
+				config.Prefix = "sha256="
+			},
+			body:         "payload",
+			headers:      map[string]string{"X-Hub-Signature-256": "sha256=" + hex.EncodeToString(sign("secret", "payload"))},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc: "signature without the prefix",
+			config: func(config *dynamic.HMACAuth) {
+				config.Prefix = "sha256="
+			},
+			body:         "payload",
+			headers:      map[string]string{"X-Signature": hex.EncodeToString(sign("secret", "payload"))},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc: "base64 signature",
+			config: func(config *dynamic.HMACAuth) {
+				config.Encoding = "base64"
+			},
+			body:         "payload",
+			headers:      map[string]string{"X-Signature": base64.StdEncoding.EncodeToString(sign("secret", "payload"))},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc: "sha512 signature",
+			config: func(config *dynamic.HMACAuth) {
+				config.Algorithm = "sha512"
+			},
+			body: "payload",
+			headers: map[string]string{"X-Signature": func() string {
+				mac := hmac.New(sha512.New, []byte("secret"))
+				mac.Write([]byte("payload"))
+				return hex.EncodeToString(mac.Sum(nil))
+			}()},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc: "signature with a timestamp",
+			config: func(config *dynamic.HMACAuth) {
+				config.TimestampHeader = "X-Timestamp"
+			},
+			body: "payload",
+			headers: map[string]string{
+				"X-Timestamp": now,
+				"X-Signature": hex.EncodeToString(sign("secret", now+".payload")),
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc: "signature without the timestamp",
+			config: func(config *dynamic.HMACAuth) {
+				config.TimestampHeader = "X-Timestamp"
+			},
+			body:         "payload",
+			headers:      map[string]string{"X-Signature": hex.EncodeToString(sign("secret", "payload"))},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc: "signature with an expired timestamp",
+			config: func(config *dynamic.HMACAuth) {
+				config.TimestampHeader = "X-Timestamp"
+			},
+			body: "payload",
+			headers: map[string]string{
+				"X-Timestamp": past,
+				"X-Signature": hex.EncodeToString(sign("secret", past+".payload")),
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc: "body too large",
+			config: func(config *dynamic.HMACAuth) {
+				config.MaxBodySize = 4
+			},
+			body:         "payload",
+			headers:      map[string]string{"X-Signature": hex.EncodeToString(sign("secret", "payload"))},
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := dynamic.HMACAuth{Keys: []string{"secret", "previous"}}
+			config.SetDefaults()
+			if test.config != nil {
+				test.config(&config)
+			}
+
+			var body string
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				b, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				body = string(b)
+			})
+
+			handler, err := NewHMAC(context.Background(), next, config, "hmacAuth")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost", strings.NewReader(test.body))
+			for name, value := range test.headers {
+				req.Header.Set(name, value)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedCode, recorder.Code)
+
+			if test.expectedCode == http.StatusOK {
+				// The backend receives the whole body.
+				assert.Equal(t, test.body, body)
+			}
+		})
+	}
+}
+
+func TestHMACAuth_chunkedBodyTooLarge(t *testing.T) {
+	config := dynamic.HMACAuth{Keys: []string{"secret"}}
+	config.SetDefaults()
+	config.MaxBodySize = 4
+
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+	handler, err := NewHMAC(context.Background(), next, config, "hmacAuth")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost", io.NopCloser(strings.NewReader("payload")))
+	req.ContentLength = -1
+	req.Header.Set("X-Signature", "00")
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+}
+
+func TestHMACAuth_keysFileReload(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys")
+	err := os.WriteFile(keysFile, []byte("# Current key\nsecret\n"), 0o600)
+	require.NoError(t, err)
+
+	config := dynamic.HMACAuth{KeysFile: keysFile}
+	config.SetDefaults()
+
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+	handler, err := NewHMAC(context.Background(), next, config, "hmacAuth")
+	require.NoError(t, err)
+
+	send := func(key string) int {
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write([]byte("payload"))
+
+		req := httptest.NewRequest(http.MethodPost, "http://localhost", strings.NewReader("payload"))
+		req.Header.Set("X-Signature", hex.EncodeToString(mac.Sum(nil)))
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+
+		return recorder.Code
+	}
+
+	updateFile := func(content string, modTime time.Time) {
+		err = os.WriteFile(keysFile, []byte(content), 0o600)
+		require.NoError(t, err)
+		err = os.Chtimes(keysFile, modTime, modTime)
+		require.NoError(t, err)
+
+		// Forces the check of the file on the next request.
+		h := handler.(*hmacAuth)
+		h.keys.mu.Lock()
+		h.keys.nextCheck = time.Time{}
+		h.keys.mu.Unlock()
+	}
+
+	assert.Equal(t, http.StatusOK, send("secret"))
+	assert.Equal(t, http.StatusUnauthorized, send("rotated"))
+
+	// The keys are rotated: the new key is added, while the previous one is still accepted.
+	updateFile("rotated\nsecret\n", time.Now().Add(time.Minute))
+
+	assert.Equal(t, http.StatusOK, send("secret"))
+	assert.Equal(t, http.StatusOK, send("rotated"))
+
+	updateFile("rotated\n", time.Now().Add(2*time.Minute))
+
+	assert.Equal(t, http.StatusUnauthorized, send("secret"))
+	assert.Equal(t, http.StatusOK, send("rotated"))
+
+	// A file without keys does not replace the previous keys.
+	updateFile("# No key\n", time.Now().Add(3*time.Minute))
+
+	assert.Equal(t, http.StatusOK, send("rotated"))
+}
@@ -0,0 +1,428 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	goauth "github.com/abbot/go-http-auth"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	ldapTypeName = "LDAPAuth"
+
+	// usernamePlaceholder is replaced by the escaped username in the search filter.
+	usernamePlaceholder = "{username}"
+)
+
+// errInvalidCredentials is returned when the credentials of a user are rejected.
+var errInvalidCredentials = errors.New("invalid credentials")
+
+// ldapConn is the part of an LDAP connection used by the middleware.
+type ldapConn interface {
+	Bind(username, password string) error
+	UnauthenticatedBind(username string) error
+	Search(searchRequest *ldap.SearchRequest) (*ldap.SearchResult, error)
+	IsClosing() bool
+	Close()
+}
+
+// cachedAuth is a successful authentication of a user.
+type cachedAuth struct {
+	mac    []byte
+	expiry time.Time
+}
+
+type ldapAuth struct {
+	next         http.Handler
+	name         string
+	auth         *goauth.BasicAuth
+	headerField  string
+	removeHeader bool
+
+	bindDN       string
+	bindPassword string
+	baseDN       string
+	searchFilter string
+	groupBaseDN  string
+	// groupsFilter matches the groups by their common name.
+	groupsFilter string
+
+	dial  func() (ldapConn, error)
+	conns chan ldapConn
+
+	cacheDuration time.Duration
+	cacheKey      []byte
+	cacheMu       sync.Mutex
+	cache         map[string]cachedAuth
+}
+
+// NewLDAP creates a LDAP authentication middleware.
+func NewLDAP(ctx context.Context, next http.Handler, config dynamic.LDAPAuth, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, ldapTypeName)).Debug("Creating middleware")
+
+	if config.BaseDN == "" {
+		return nil, errors.New("the base DN is missing")
+	}
+
+	if !strings.Contains(config.SearchFilter, usernamePlaceholder) {
+		return nil, fmt.Errorf("the search filter %q does not contain %s", config.SearchFilter, usernamePlaceholder)
+	}
+
+	if len(config.Groups) > 0 && config.GroupBaseDN == "" {
+		return nil, errors.New("the group base DN is missing")
+	}
+
+	if config.MaxIdleConns < 0 {
+		return nil, fmt.Errorf("invalid max idle connections: %d", config.MaxIdleConns)
+	}
+
+	dial, err := newLDAPDialer(config)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := make([]byte, 32)
+	if _, err = rand.Read(cacheKey); err != nil {
+		return nil, err
+	}
+
+	var groupsFilter string
+	for _, group := range config.Groups {
+		groupsFilter += "(cn=" + ldap.EscapeFilter(group) + ")"
+	}
+
+	realm := defaultRealm
+	if len(config.Realm) > 0 {
+		realm = config.Realm
+	}
+
+	return &ldapAuth{
+		next:          next,
+		name:          name,
+		auth:          &goauth.BasicAuth{Realm: realm},
+		headerField:   config.HeaderField,
+		removeHeader:  config.RemoveHeader,
+		bindDN:        config.BindDN,
+		bindPassword:  config.BindPassword,
+		baseDN:        config.BaseDN,
+		searchFilter:  config.SearchFilter,
+		groupBaseDN:   config.GroupBaseDN,
+		groupsFilter:  groupsFilter,
+		dial:          dial,
+		conns:         make(chan ldapConn, config.MaxIdleConns),
+		cacheDuration: time.Duration(config.CacheDuration),
+		cacheKey:      cacheKey,
+		cache:         make(map[string]cachedAuth),
+	}, nil
+}
+
+// newLDAPDialer returns a function opening a connection to the LDAP server,
+// over TLS for the ldaps scheme, or upgraded with StartTLS if enabled.
+func newLDAPDialer(config dynamic.LDAPAuth) (func() (ldapConn, error), error) {
+	serverURL, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %w", config.URL, err)
+	}
+
+	var defaultPort string
+	switch serverURL.Scheme {
+	case "ldap":
+		defaultPort = ldap.DefaultLdapPort
+	case "ldaps":
+		defaultPort = ldap.DefaultLdapsPort
+	default:
+		return nil, fmt.Errorf("unsupported scheme in URL %q, must be ldap or ldaps", config.URL)
+	}
+
+	if serverURL.Scheme == "ldaps" && config.StartTLS {
+		return nil, errors.New("startTLS cannot be used with the ldaps scheme")
+	}
+
+	address := serverURL.Host
+	if serverURL.Port() == "" {
+		address = net.JoinHostPort(serverURL.Hostname(), defaultPort)
+	}
+
+	tlsConfig, err := config.TLS.CreateTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = serverURL.Hostname()
+	}
+
+	timeout := time.Duration(config.Timeout)
+	dialer := &net.Dialer{Timeout: timeout}
+
+	return func() (ldapConn, error) {
+		var netConn net.Conn
+		var err error
+		if serverURL.Scheme == "ldaps" {
+			netConn, err = tls.DialWithDialer(dialer, "tcp", address, tlsConfig)
+		} else {
+			netConn, err = dialer.Dial("tcp", address)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		conn := ldap.NewConn(netConn, serverURL.Scheme == "ldaps")
+		conn.Start()
+		conn.SetTimeout(timeout)
+
+		if config.StartTLS {
+			if err := conn.StartTLS(tlsConfig); err != nil {
+				conn.Close()
+				return nil, fmt.Errorf("starting TLS: %w", err)
+			}
+		}
+
+		return conn, nil
+	}, nil
+}
+
+func (l *ldapAuth) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return l.name, ext.SpanKindRPCClientEnum
+}
+
+func (l *ldapAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), l.name, ldapTypeName))
+
+	user, password, ok := req.BasicAuth()
+
+	if logData := accesslog.GetLogData(req); logData != nil {
+		logData.Core[accesslog.ClientUsername] = user
+	}
+
+	if !ok || user == "" || password == "" {
+		logger.Debug("Authentication failed: missing credentials")
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+
+		l.auth.RequireAuth(rw, req)
+		return
+	}
+
+	if !l.cached(user, password) {
+		err := l.authenticate(user, password)
+		if errors.Is(err, errInvalidCredentials) {
+			logger.Debugf("Authentication failed: %v", err)
+			tracing.SetErrorWithEvent(req, "Authentication failed")
+
+			l.auth.RequireAuth(rw, req)
+			return
+		}
+
+		if err != nil {
+			logger.Errorf("Error while authenticating the user against the LDAP server: %v", err)
+			tracing.SetErrorWithEvent(req, "Error while authenticating the user against the LDAP server")
+
+			http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		l.store(user, password)
+	}
+
+	logger.Debug("Authentication succeeded")
+	req.URL.User = url.User(user)
+
+	if l.headerField != "" {
+		req.Header[l.headerField] = []string{user}
+	}
+
+	if l.removeHeader {
+		logger.Debug("Removing authorization header")
+		req.Header.Del(authorizationHeader)
+	}
+
+	l.next.ServeHTTP(rw, req)
+}
+
+// authenticate checks the credentials of the user against the LDAP server.
+// It returns errInvalidCredentials if they are rejected.
+func (l *ldapAuth) authenticate(user, password string) error {
+	conn, err := l.getConn()
+	if err != nil {
+		return err
+	}
+
+	err = l.authenticateWith(conn, user, password)
+	if err != nil && !errors.Is(err, errInvalidCredentials) {
+		// The state of the connection is unknown.
+		conn.Close()
+		return err
+	}
+
+	l.putConn(conn)
+
+	return err
+}
+
+func (l *ldapAuth) authenticateWith(conn ldapConn, user, password string) error {
+	// The connection may be bound to a previous user.
+	if l.bindDN != "" {
+		if err := conn.Bind(l.bindDN, l.bindPassword); err != nil {
+			return fmt.Errorf("binding with the bind DN: %w", err)
+		}
+	} else if err := conn.UnauthenticatedBind(""); err != nil {
+		return fmt.Errorf("binding anonymously: %w", err)
+	}
+
+	filter := strings.ReplaceAll(l.searchFilter, usernamePlaceholder, ldap.EscapeFilter(user))
+	result, err := conn.Search(ldap.NewSearchRequest(l.baseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, 0, false, filter, []string{"dn"}, nil))
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		return fmt.Errorf("%w: several entries found for the user %s", errInvalidCredentials, user)
+	}
+
+	if err != nil {
+		return fmt.Errorf("searching the user: %w", err)
+	}
+
+	if len(result.Entries) != 1 {
+		return fmt.Errorf("%w: %d entries found for the user %s", errInvalidCredentials, len(result.Entries), user)
+	}
+
+	userDN := result.Entries[0].DN
+
+	if l.groupsFilter != "" {
+		member, err := l.isMember(conn, userDN)
+		if err != nil {
+			return err
+		}
+
+		if !member {
+			return fmt.Errorf("%w: the user %s is not a member of the groups", errInvalidCredentials, user)
+		}
+	}
+
+	if err := conn.Bind(userDN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return fmt.Errorf("%w: %v", errInvalidCredentials, err)
+		}
+
+		return fmt.Errorf("binding with the user DN: %w", err)
+	}
+
+	return nil
+}
+
+// isMember reports whether the user is a member of any of the groups,
+// by their member or uniqueMember attributes.
+func (l *ldapAuth) isMember(conn ldapConn, userDN string) (bool, error) {
+	escapedDN := ldap.EscapeFilter(userDN)
+	filter := fmt.Sprintf("(&(|%s)(|(member=%s)(uniqueMember=%s)))", l.groupsFilter, escapedDN, escapedDN)
+
+	result, err := conn.Search(ldap.NewSearchRequest(l.groupBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 1, 0, false, filter, []string{"dn"}, nil))
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		// The user is a member of several groups.
+		return true, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("searching the groups: %w", err)
+	}
+
+	return len(result.Entries) > 0, nil
+}
+
+// getConn returns an idle connection, or a new one if there is none.
+func (l *ldapAuth) getConn() (ldapConn, error) {
+	for {
+		select {
+		case conn := <-l.conns:
+			if conn.IsClosing() {
+				continue
+			}
+			return conn, nil
+		default:
+			return l.dial()
+		}
+	}
+}
+
+// putConn keeps the connection for later use, unless there are already enough idle connections.
+func (l *ldapAuth) putConn(conn ldapConn) {
+	select {
+	case l.conns <- conn:
+	default:
+		conn.Close()
+	}
+}
+
+// cached reports whether the credentials of the user were successfully authenticated within the cache duration.
+func (l *ldapAuth) cached(user, password string) bool {
+	if l.cacheDuration <= 0 {
+		return false
+	}
+
+	l.cacheMu.Lock()
+	defer l.cacheMu.Unlock()
+
+	entry, ok := l.cache[user]
+	if !ok {
+		return false
+	}
+
+	if time.Now().After(entry.expiry) {
+		delete(l.cache, user)
+		return false
+	}
+
+	return hmac.Equal(entry.mac, l.credentialsMAC(user, password))
+}
+
+// store caches the successful authentication of the user.
+func (l *ldapAuth) store(user, password string) {
+	if l.cacheDuration <= 0 {
+		return
+	}
+
+	l.cacheMu.Lock()
+	defer l.cacheMu.Unlock()
+
+	// The expired authentications of the other users are removed along the way.
+	now := time.Now()
+	for cachedUser, entry := range l.cache {
+		if now.After(entry.expiry) {
+			delete(l.cache, cachedUser)
+		}
+	}
+
+	l.cache[user] = cachedAuth{
+		mac:    l.credentialsMAC(user, password),
+		expiry: now.Add(l.cacheDuration),
+	}
+}
+
+// credentialsMAC returns the MAC of the credentials, so that the passwords are not kept in memory.
+func (l *ldapAuth) credentialsMAC(user, password string) []byte {
+	mac := hmac.New(sha256.New, l.cacheKey)
+	mac.Write([]byte(user))
+	mac.Write([]byte{0})
+	mac.Write([]byte(password))
+
+	return mac.Sum(nil)
+}
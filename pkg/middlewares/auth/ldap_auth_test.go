@@ -0,0 +1,422 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+const (
+	testBaseDN       = "ou=people,dc=example,dc=org"
+	testGroupBaseDN  = "ou=groups,dc=example,dc=org"
+	testBindDN       = "cn=traefik,dc=example,dc=org"
+	testBindPassword = "traefik"
+)
+
+// ldapDirectoryMock is an LDAP directory holding users, by DN, and groups, by common name.
+type ldapDirectoryMock struct {
+	users  map[string]string
+	groups map[string][]string
+	err    error
+
+	mu    sync.Mutex
+	dials int
+	binds int
+}
+
+func (d *ldapDirectoryMock) dial() (ldapConn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.dials++
+
+	return &ldapConnMock{directory: d}, nil
+}
+
+type ldapConnMock struct {
+	directory *ldapDirectoryMock
+	closed    bool
+}
+
+func (c *ldapConnMock) Bind(username, password string) error {
+	c.directory.mu.Lock()
+	defer c.directory.mu.Unlock()
+
+	c.directory.binds++
+
+	if c.directory.err != nil {
+		return c.directory.err
+	}
+
+	if username == testBindDN && password == testBindPassword {
+		return nil
+	}
+
+	if expected, ok := c.directory.users[username]; ok && expected == password {
+		return nil
+	}
+
+	return ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("invalid credentials"))
+}
+
+func (c *ldapConnMock) UnauthenticatedBind(string) error {
+	return c.directory.err
+}
+
+func (c *ldapConnMock) Search(searchRequest *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	if c.directory.err != nil {
+		return nil, c.directory.err
+	}
+
+	result := &ldap.SearchResult{}
+
+	if searchRequest.BaseDN == testGroupBaseDN {
+		for group, members := range c.directory.groups {
+			if !strings.Contains(searchRequest.Filter, "(cn="+group+")") {
+				continue
+			}
+
+			for _, member := range members {
+				if strings.Contains(searchRequest.Filter, "(member="+ldap.EscapeFilter(member)+")") {
+					result.Entries = append(result.Entries, ldap.NewEntry("cn="+group+","+testGroupBaseDN, nil))
+				}
+			}
+		}
+
+		return result, nil
+	}
+
+	for dn := range c.directory.users {
+		uid := strings.TrimPrefix(strings.Split(dn, ",")[0], "uid=")
+		if searchRequest.Filter == "(uid="+uid+")" {
+			result.Entries = append(result.Entries, ldap.NewEntry(dn, nil))
+		}
+	}
+
+	return result, nil
+}
+
+func (c *ldapConnMock) IsClosing() bool {
+	return c.closed
+}
+
+func (c *ldapConnMock) Close() {
+	c.closed = true
+}
+
+func newTestLDAPAuth(t *testing.T, config dynamic.LDAPAuth, directory *ldapDirectoryMock) *ldapAuth {
+	t.Helper()
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte(req.Header.Get("X-User")))
+	})
+
+	handler, err := NewLDAP(context.Background(), next, config, "ldapAuth")
+	require.NoError(t, err)
+
+	l := handler.(*ldapAuth)
+	l.dial = directory.dial
+
+	return l
+}
+
+func newTestLDAPDirectory() *ldapDirectoryMock {
+	return &ldapDirectoryMock{
+		users: map[string]string{
+			"uid=john,ou=people,dc=example,dc=org": "secret",
+			"uid=jane,ou=people,dc=example,dc=org": "secret",
+		},
+		groups: map[string][]string{
+			"admins": {"uid=jane,ou=people,dc=example,dc=org"},
+		},
+	}
+}
+
+func TestNewLDAP(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      func(config *dynamic.LDAPAuth)
+		expectedErr bool
+	}{
+		{
+			desc:   "ldap URL",
+			config: func(config *dynamic.LDAPAuth) {},
+		},
+		{
+			desc: "ldaps URL",
+			config: func(config *dynamic.LDAPAuth) {
+				config.URL = "ldaps://ldap.example.org"
+			},
+		},
+		{
+			desc: "ldap URL with StartTLS",
+			config: func(config *dynamic.LDAPAuth) {
+				config.StartTLS = true
+			},
+		},
+		{
+			desc: "ldaps URL with StartTLS",
+			config: func(config *dynamic.LDAPAuth) {
+				config.URL = "ldaps://ldap.example.org"
+				config.StartTLS = true
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "unsupported scheme",
+			config: func(config *dynamic.LDAPAuth) {
+				config.URL = "http://ldap.example.org"
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "missing base DN",
+			config: func(config *dynamic.LDAPAuth) {
+				config.BaseDN = ""
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "search filter without username",
+			config: func(config *dynamic.LDAPAuth) {
+				config.SearchFilter = "(uid=john)"
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "groups without group base DN",
+			config: func(config *dynamic.LDAPAuth) {
+				config.Groups = []string{"admins"}
+			},
+			expectedErr: true,
+		},
+		{
+			desc: "invalid max idle connections",
+			config: func(config *dynamic.LDAPAuth) {
+				config.MaxIdleConns = -1
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := dynamic.LDAPAuth{URL: "ldap://ldap.example.org", BaseDN: testBaseDN}
+			config.SetDefaults()
+			test.config(&config)
+
+			next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+			_, err := NewLDAP(context.Background(), next, config, "ldapAuth")
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestLDAPAuth(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		groups       []string
+		user         string
+		password     string
+		err          error
+		expectedCode int
+		expectedUser string
+	}{
+		{
+			desc:         "valid credentials",
+			user:         "john",
+			password:     "secret",
+			expectedCode: http.StatusOK,
+			expectedUser: "john",
+		},
+		{
+			desc:         "invalid password",
+			user:         "john",
+			password:     "invalid",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc:         "empty password",
+			user:         "john",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc:         "unknown user",
+			user:         "unknown",
+			password:     "secret",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc:         "wildcard user",
+			user:         "*",
+			password:     "secret",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc:         "member of the groups",
+			groups:       []string{"missing", "admins"},
+			user:         "jane",
+			password:     "secret",
+			expectedCode: http.StatusOK,
+			expectedUser: "jane",
+		},
+		{
+			desc:         "not a member of the groups",
+			groups:       []string{"admins"},
+			user:         "john",
+			password:     "secret",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc:         "server error",
+			user:         "john",
+			password:     "secret",
+			err:          ldap.NewError(ldap.ErrorNetwork, errors.New("connection refused")),
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := dynamic.LDAPAuth{
+				URL:          "ldap://ldap.example.org",
+				BindDN:       testBindDN,
+				BindPassword: testBindPassword,
+				BaseDN:       testBaseDN,
+				GroupBaseDN:  testGroupBaseDN,
+				Groups:       test.groups,
+				HeaderField:  "X-User",
+			}
+			config.SetDefaults()
+
+			directory := newTestLDAPDirectory()
+			directory.err = test.err
+
+			handler := newTestLDAPAuth(t, config, directory)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			req.SetBasicAuth(test.user, test.password)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedCode, recorder.Code)
+
+			if test.expectedCode == http.StatusOK {
+				assert.Equal(t, test.expectedUser, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestLDAPAuth_cache(t *testing.T) {
+	config := dynamic.LDAPAuth{
+		URL:           "ldap://ldap.example.org",
+		BaseDN:        testBaseDN,
+		CacheDuration: ptypes.Duration(time.Minute),
+	}
+	config.SetDefaults()
+
+	directory := newTestLDAPDirectory()
+	handler := newTestLDAPAuth(t, config, directory)
+
+	authenticate := func(user, password string) int {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+		req.SetBasicAuth(user, password)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusOK, authenticate("john", "secret"))
+	assert.Equal(t, 1, directory.binds)
+
+	// The successful authentication is cached.
+	assert.Equal(t, http.StatusOK, authenticate("john", "secret"))
+	assert.Equal(t, 1, directory.binds)
+
+	// The cache only holds the authenticated credentials.
+	assert.Equal(t, http.StatusUnauthorized, authenticate("john", "invalid"))
+	assert.Equal(t, 2, directory.binds)
+
+	// The expired authentications are checked again.
+	handler.cacheMu.Lock()
+	entry := handler.cache["john"]
+	entry.expiry = time.Now().Add(-time.Second)
+	handler.cache["john"] = entry
+	handler.cacheMu.Unlock()
+
+	directory.users["uid=john,ou=people,dc=example,dc=org"] = "changed"
+
+	assert.Equal(t, http.StatusUnauthorized, authenticate("john", "secret"))
+	assert.Equal(t, 3, directory.binds)
+}
+
+func TestLDAPAuth_connectionPool(t *testing.T) {
+	config := dynamic.LDAPAuth{
+		URL:          "ldap://ldap.example.org",
+		BindDN:       testBindDN,
+		BindPassword: testBindPassword,
+		BaseDN:       testBaseDN,
+	}
+	config.SetDefaults()
+
+	directory := newTestLDAPDirectory()
+	handler := newTestLDAPAuth(t, config, directory)
+
+	authenticate := func(user, password string) int {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+		req.SetBasicAuth(user, password)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusOK, authenticate("john", "secret"))
+	assert.Equal(t, http.StatusUnauthorized, authenticate("jane", "invalid"))
+	assert.Equal(t, http.StatusOK, authenticate("jane", "secret"))
+
+	// The idle connection is reused.
+	assert.Equal(t, 1, directory.dials)
+
+	// A closed connection is not reused.
+	conn := <-handler.conns
+	conn.Close()
+	handler.conns <- conn
+
+	assert.Equal(t, http.StatusOK, authenticate("john", "secret"))
+	assert.Equal(t, 2, directory.dials)
+
+	// A connection in an unknown state is closed.
+	directory.err = ldap.NewError(ldap.ErrorNetwork, errors.New("connection reset"))
+
+	assert.Equal(t, http.StatusInternalServerError, authenticate("john", "secret"))
+	assert.Empty(t, handler.conns)
+}
@@ -0,0 +1,586 @@
+package auth
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+	"golang.org/x/net/http/httpguts"
+	"gopkg.in/square/go-jose.v2"
+)
+
+const (
+	oidcTypeName = "OIDCAuth"
+
+	// discoveryPath is the path of the OpenID Connect discovery document, relative to the issuer.
+	discoveryPath = "/.well-known/openid-configuration"
+
+	// stateCookieSuffix is the suffix of the name of the cookie holding the state of a pending authentication.
+	stateCookieSuffix = "_state"
+
+	// stateDuration is the maximum duration of an authentication on the provider.
+	stateDuration = 10 * time.Minute
+
+	// keysRefreshInterval is the minimum interval between two fetches of the keys of the provider,
+	// when an ID token is signed with an unknown key.
+	keysRefreshInterval = time.Minute
+)
+
+// oidcProvider holds the endpoints of an OpenID Connect provider, from its discovery document.
+type oidcProvider struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// oidcState is the state of a pending authentication, stored in a cookie during the authentication on the provider.
+type oidcState struct {
+	State       string `json:"state"`
+	Nonce       string `json:"nonce"`
+	RedirectURL string `json:"redirectURL"`
+	Expiry      int64  `json:"exp"`
+}
+
+// oidcSession is the session of an authenticated user, stored in a cookie.
+type oidcSession struct {
+	Subject string                 `json:"sub"`
+	Claims  map[string]interface{} `json:"claims,omitempty"`
+	Expiry  int64                  `json:"exp"`
+}
+
+type oidcAuth struct {
+	next         http.Handler
+	name         string
+	issuer       string
+	clientID     string
+	clientSecret string
+	scopes       []string
+	redirectPath string
+	cookieName   string
+	claimHeaders map[string]string
+	client       *http.Client
+	aead         cipher.AEAD
+
+	mu            sync.Mutex
+	provider      *oidcProvider
+	keys          *jose.JSONWebKeySet
+	keysFetchedAt time.Time
+}
+
+// NewOIDC creates an OpenID Connect authentication middleware.
+func NewOIDC(ctx context.Context, next http.Handler, config dynamic.OIDCAuth, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, oidcTypeName)).Debug("Creating middleware")
+
+	if config.Issuer == "" {
+		return nil, errors.New("the issuer is missing")
+	}
+
+	if config.ClientID == "" {
+		return nil, errors.New("the client ID is missing")
+	}
+
+	if config.SessionKey == "" {
+		return nil, errors.New("the session key is missing")
+	}
+
+	if !strings.HasPrefix(config.RedirectPath, "/") {
+		return nil, fmt.Errorf("the redirect path %q must start with a slash", config.RedirectPath)
+	}
+
+	// The session key of any length is turned into an AES-256 key.
+	key := sha256.Sum256([]byte(config.SessionKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := config.Scopes
+	if !containsString(scopes, "openid") {
+		scopes = append([]string{"openid"}, scopes...)
+	}
+
+	o := &oidcAuth{
+		next:         next,
+		name:         name,
+		issuer:       strings.TrimSuffix(config.Issuer, "/"),
+		clientID:     config.ClientID,
+		clientSecret: config.ClientSecret,
+		scopes:       scopes,
+		redirectPath: config.RedirectPath,
+		cookieName:   config.CookieName,
+		claimHeaders: config.ClaimHeaders,
+		client:       &http.Client{Timeout: 30 * time.Second},
+		aead:         aead,
+	}
+
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.CreateTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		tr := http.DefaultTransport.(*http.Transport).Clone()
+		tr.TLSClientConfig = tlsConfig
+		o.client.Transport = tr
+	}
+
+	return o, nil
+}
+
+func (o *oidcAuth) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return o.name, ext.SpanKindRPCClientEnum
+}
+
+func (o *oidcAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), o.name, oidcTypeName))
+
+	if req.URL.Path == o.redirectPath {
+		o.callback(rw, req)
+		return
+	}
+
+	// The headers with the claims must only come from the session.
+	for header := range o.claimHeaders {
+		req.Header.Del(header)
+	}
+
+	var session oidcSession
+	if cookie, err := req.Cookie(o.cookieName); err == nil && o.open(o.cookieName, cookie.Value, &session) == nil && time.Now().Unix() < session.Expiry {
+		for header, claim := range o.claimHeaders {
+			value, ok := session.Claims[claim]
+			if !ok {
+				continue
+			}
+
+			headerValue := claimValue(value)
+			if !httpguts.ValidHeaderFieldValue(headerValue) {
+				logger.Debugf("Claim %s ignored: its value is not a valid header value", claim)
+				continue
+			}
+
+			req.Header.Set(header, headerValue)
+		}
+
+		if logData := accesslog.GetLogData(req); logData != nil {
+			logData.Core[accesslog.ClientUsername] = session.Subject
+		}
+
+		o.next.ServeHTTP(rw, req)
+		return
+	}
+
+	// Only the navigations can be redirected to the provider.
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		logger.Debug("Authentication failed")
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	o.authenticate(rw, req)
+}
+
+// authenticate redirects the user to the authorization endpoint of the provider,
+// and stores the state of the authentication in a cookie.
+func (o *oidcAuth) authenticate(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), o.name, oidcTypeName))
+
+	provider, err := o.discover(req.Context())
+	if err != nil {
+		logMessage := fmt.Sprintf("Error discovering the provider %s: %v", o.issuer, err)
+		logger.Debug(logMessage)
+		tracing.SetErrorWithEvent(req, logMessage)
+
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	state := oidcState{
+		State:       randomString(),
+		Nonce:       randomString(),
+		RedirectURL: requestScheme(req) + "://" + req.Host + req.URL.RequestURI(),
+		Expiry:      time.Now().Add(stateDuration).Unix(),
+	}
+
+	stateCookieName := o.cookieName + stateCookieSuffix
+	value, err := o.seal(stateCookieName, state)
+	if err != nil {
+		logger.Debugf("Error sealing the state: %v", err)
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(rw, o.cookie(req, stateCookieName, value, int(stateDuration.Seconds())))
+
+	params := url.Values{
+		"response_type": {"code"},
+		"client_id":     {o.clientID},
+		"redirect_uri":  {o.redirectURI(req)},
+		"scope":         {strings.Join(o.scopes, " ")},
+		"state":         {state.State},
+		"nonce":         {state.Nonce},
+	}
+
+	separator := "?"
+	if strings.Contains(provider.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	http.Redirect(rw, req, provider.AuthorizationEndpoint+separator+params.Encode(), http.StatusFound)
+}
+
+// callback handles the redirection of the user by the provider, after the authentication:
+// it exchanges the authorization code for an ID token, stores the session in a cookie,
+// and redirects the user to the URL requested before the authentication.
+func (o *oidcAuth) callback(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), o.name, oidcTypeName))
+
+	stateCookieName := o.cookieName + stateCookieSuffix
+
+	var state oidcState
+	cookie, err := req.Cookie(stateCookieName)
+	if err != nil || o.open(stateCookieName, cookie.Value, &state) != nil || time.Now().Unix() >= state.Expiry {
+		logger.Debug("Authentication failed: missing or invalid state")
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	query := req.URL.Query()
+	if query.Get("state") != state.State {
+		logger.Debug("Authentication failed: unexpected state")
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if errCode := query.Get("error"); errCode != "" {
+		logger.Debugf("Authentication failed: %s %s", errCode, query.Get("error_description"))
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	token, err := o.exchange(req, query.Get("code"), state.Nonce)
+	if err != nil {
+		logMessage := fmt.Sprintf("Error getting the ID token from %s: %v", o.issuer, err)
+		logger.Debug(logMessage)
+		tracing.SetErrorWithEvent(req, logMessage)
+
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	session := oidcSession{
+		Subject: token.Subject,
+		Expiry:  token.Expiry.Unix(),
+	}
+
+	// Only the claims forwarded to the backend are kept, to keep the cookie small.
+	for _, claim := range o.claimHeaders {
+		if value, ok := token.Claims[claim]; ok {
+			if session.Claims == nil {
+				session.Claims = make(map[string]interface{})
+			}
+			session.Claims[claim] = value
+		}
+	}
+
+	value, err := o.seal(o.cookieName, session)
+	if err != nil {
+		logger.Debugf("Error sealing the session: %v", err)
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(rw, o.cookie(req, o.cookieName, value, int(time.Until(token.Expiry).Seconds())))
+	http.SetCookie(rw, o.cookie(req, stateCookieName, "", -1))
+
+	http.Redirect(rw, req, state.RedirectURL, http.StatusFound)
+}
+
+// exchange exchanges the given authorization code for an ID token at the token endpoint of the provider,
+// and returns the validated ID token.
+func (o *oidcAuth) exchange(req *http.Request, code, nonce string) (*idToken, error) {
+	provider, err := o.discover(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {o.redirectURI(req)},
+	}
+
+	tokenReq, err := http.NewRequestWithContext(req.Context(), http.MethodPost, provider.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	tokenReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	tokenReq.SetBasicAuth(url.QueryEscape(o.clientID), url.QueryEscape(o.clientSecret))
+
+	var tokenResp struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := o.getJSON(tokenReq, &tokenResp); err != nil {
+		return nil, err
+	}
+
+	if tokenResp.IDToken == "" {
+		return nil, errors.New("no ID token in the response")
+	}
+
+	keys, err := o.keySet(req.Context(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := parseIDToken(tokenResp.IDToken, keys, provider.Issuer, o.clientID, nonce, time.Now())
+	if !errors.Is(err, errUnknownKey) {
+		return token, err
+	}
+
+	// The provider may have rotated its keys.
+	keys, err = o.keySet(req.Context(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseIDToken(tokenResp.IDToken, keys, provider.Issuer, o.clientID, nonce, time.Now())
+}
+
+// discover returns the endpoints of the provider, from its discovery document fetched on the first call.
+// The document is fetched without holding the lock, so that a slow provider does not block the other requests.
+func (o *oidcAuth) discover(ctx context.Context) (*oidcProvider, error) {
+	o.mu.Lock()
+	provider := o.provider
+	o.mu.Unlock()
+
+	if provider != nil {
+		return provider, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.issuer+discoveryPath, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	provider = &oidcProvider{}
+	if err := o.getJSON(req, provider); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSuffix(provider.Issuer, "/") != o.issuer {
+		return nil, fmt.Errorf("unexpected issuer %q in the discovery document", provider.Issuer)
+	}
+
+	if provider.AuthorizationEndpoint == "" || provider.TokenEndpoint == "" || provider.JWKSURI == "" {
+		return nil, errors.New("incomplete discovery document")
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	// The document may have been fetched concurrently.
+	if o.provider == nil {
+		o.provider = provider
+	}
+
+	return o.provider, nil
+}
+
+// keySet returns the signing keys of the provider,
+// fetched on the first call, and again on refresh unless they have been fetched recently.
+// The keys are fetched without holding the lock, so that a slow provider does not block the other requests.
+func (o *oidcAuth) keySet(ctx context.Context, refresh bool) (*jose.JSONWebKeySet, error) {
+	provider, err := o.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	o.mu.Lock()
+	keys := o.keys
+	if keys != nil && (!refresh || time.Since(o.keysFetchedAt) < keysRefreshInterval) {
+		o.mu.Unlock()
+		return keys, nil
+	}
+
+	// The refresh is recorded before fetching the keys, so that the concurrent refreshes use the current keys.
+	o.keysFetchedAt = time.Now()
+	o.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, provider.JWKSURI, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var rawKeySet struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := o.getJSON(req, &rawKeySet); err != nil {
+		return nil, err
+	}
+
+	// The keys which are not supported are skipped, instead of rejecting the whole key set.
+	keys = &jose.JSONWebKeySet{}
+	for _, rawKey := range rawKeySet.Keys {
+		var key jose.JSONWebKey
+		if err := json.Unmarshal(rawKey, &key); err != nil {
+			log.FromContext(middlewares.GetLoggerCtx(ctx, o.name, oidcTypeName)).Debugf("Key of the provider %s ignored: %v", o.issuer, err)
+			continue
+		}
+
+		keys.Keys = append(keys.Keys, key)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.keys = keys
+
+	return o.keys, nil
+}
+
+func (o *oidcAuth) getJSON(req *http.Request, v interface{}) error {
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, req.URL)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("invalid response from %s: %w", req.URL, err)
+	}
+
+	return nil
+}
+
+func (o *oidcAuth) redirectURI(req *http.Request) string {
+	return requestScheme(req) + "://" + req.Host + o.redirectPath
+}
+
+func (o *oidcAuth) cookie(req *http.Request, name, value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   requestScheme(req) == "https",
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// seal encrypts the given value for the cookie with the given name.
+// The name of the cookie is authenticated, so that a cookie value cannot be used for another cookie.
+func (o *oidcAuth) seal(name string, v interface{}) (string, error) {
+	plaintext, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, o.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(o.aead.Seal(nonce, nonce, plaintext, []byte(name))), nil
+}
+
+// open decrypts the value of the cookie with the given name into v.
+func (o *oidcAuth) open(name, value string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return err
+	}
+
+	if len(data) < o.aead.NonceSize() {
+		return errors.New("value too short")
+	}
+
+	plaintext, err := o.aead.Open(nil, data[:o.aead.NonceSize()], data[o.aead.NonceSize():], []byte(name))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(plaintext, v)
+}
+
+func requestScheme(req *http.Request) string {
+	if proto := req.Header.Get("X-Forwarded-Proto"); proto != "" {
+		return proto
+	}
+
+	if req.TLS != nil {
+		return "https"
+	}
+
+	return "http"
+}
+
+func randomString() string {
+	data := make([]byte, 32)
+	_, _ = rand.Read(data)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// claimValue returns the header value of a claim value:
+// strings are used as is, and the other values as JSON.
+func claimValue(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(data)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"gopkg.in/square/go-jose.v2"
+)
+
+func TestOIDCAuth(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var nonce string
+	mux := http.NewServeMux()
+	provider := httptest.NewServer(mux)
+	t.Cleanup(provider.Close)
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(rw http.ResponseWriter, req *http.Request) {
+		_ = json.NewEncoder(rw).Encode(oidcProvider{
+			Issuer:                provider.URL,
+			AuthorizationEndpoint: provider.URL + "/authorize",
+			TokenEndpoint:         provider.URL + "/token",
+			JWKSURI:               provider.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(rw http.ResponseWriter, req *http.Request) {
+		// The keys which are not supported are skipped.
+		_ = json.NewEncoder(rw).Encode(map[string]interface{}{"keys": []interface{}{
+			map[string]string{"kty": "unknown", "kid": "unknown"},
+			jose.JSONWebKey{Key: &key.PublicKey, KeyID: "key", Use: "sig"},
+		}})
+	})
+	mux.HandleFunc("/token", func(rw http.ResponseWriter, req *http.Request) {
+		clientID, clientSecret, _ := req.BasicAuth()
+		if clientID != "client" || clientSecret != "secret" || req.FormValue("code") != "code" ||
+			req.FormValue("redirect_uri") != "http://app.example.com/oauth2/callback" {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		idToken := signToken(t, jose.RS256, key, "key", map[string]interface{}{
+			"iss":    provider.URL,
+			"sub":    "user",
+			"aud":    "client",
+			"exp":    time.Now().Add(time.Hour).Unix(),
+			"nonce":  nonce,
+			"email":  "user@example.com",
+			"groups": []string{"admin", "dev"},
+		})
+
+		_ = json.NewEncoder(rw).Encode(map[string]string{"id_token": idToken})
+	})
+
+	var forwarded http.Header
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		forwarded = req.Header.Clone()
+	})
+
+	config := dynamic.OIDCAuth{
+		Issuer:       provider.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		SessionKey:   "session key",
+		ClaimHeaders: map[string]string{"X-Auth-Email": "email", "X-Auth-Groups": "groups"},
+	}
+	config.SetDefaults()
+
+	handler, err := NewOIDC(context.Background(), next, config, "oidc")
+	require.NoError(t, err)
+
+	// An unauthenticated navigation is redirected to the provider.
+	req := httptest.NewRequest(http.MethodGet, "http://app.example.com/foo?bar=baz", nil)
+	req.Header.Set("X-Auth-Email", "forged@example.com")
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusFound, rw.Code)
+	assert.Nil(t, forwarded)
+
+	location, err := url.Parse(rw.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, provider.URL+"/authorize", location.Scheme+"://"+location.Host+location.Path)
+	assert.Equal(t, "code", location.Query().Get("response_type"))
+	assert.Equal(t, "client", location.Query().Get("client_id"))
+	assert.Equal(t, "openid", location.Query().Get("scope"))
+	assert.Equal(t, "http://app.example.com/oauth2/callback", location.Query().Get("redirect_uri"))
+
+	state := location.Query().Get("state")
+	nonce = location.Query().Get("nonce")
+	stateCookies := rw.Result().Cookies()
+	require.Len(t, stateCookies, 1)
+
+	// A callback with an unexpected state is rejected.
+	req = httptest.NewRequest(http.MethodGet, "http://app.example.com/oauth2/callback?code=code&state=other", nil)
+	req.AddCookie(stateCookies[0])
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+
+	// The callback creates the session, and redirects to the original URL.
+	req = httptest.NewRequest(http.MethodGet, "http://app.example.com/oauth2/callback?code=code&state="+url.QueryEscape(state), nil)
+	req.AddCookie(stateCookies[0])
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusFound, rw.Code)
+	assert.Equal(t, "http://app.example.com/foo?bar=baz", rw.Header().Get("Location"))
+
+	var sessionCookie *http.Cookie
+	for _, cookie := range rw.Result().Cookies() {
+		if cookie.Name == "_traefik_oidc" {
+			sessionCookie = cookie
+		}
+	}
+	require.NotNil(t, sessionCookie)
+
+	// An authenticated request is forwarded with the claims.
+	req = httptest.NewRequest(http.MethodPost, "http://app.example.com/foo", nil)
+	req.Header.Set("X-Auth-Email", "forged@example.com")
+	req.AddCookie(sessionCookie)
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	require.NotNil(t, forwarded)
+	assert.Equal(t, "user@example.com", forwarded.Get("X-Auth-Email"))
+	assert.Equal(t, `["admin","dev"]`, forwarded.Get("X-Auth-Groups"))
+
+	// A tampered session is rejected.
+	forwarded = nil
+	req = httptest.NewRequest(http.MethodPost, "http://app.example.com/foo", nil)
+	tampered := []byte(sessionCookie.Value)
+	if tampered[20] == 'A' {
+		tampered[20] = 'B'
+	} else {
+		tampered[20] = 'A'
+	}
+	req.AddCookie(&http.Cookie{Name: "_traefik_oidc", Value: string(tampered)})
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rw.Code)
+	assert.Nil(t, forwarded)
+
+	// A state cookie cannot be used as a session.
+	req = httptest.NewRequest(http.MethodPost, "http://app.example.com/foo", nil)
+	req.AddCookie(&http.Cookie{Name: "_traefik_oidc", Value: stateCookies[0].Value})
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rw.Code)
+	assert.Nil(t, forwarded)
+}
+
+func TestNewOIDC(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config dynamic.OIDCAuth
+	}{
+		{
+			desc:   "missing issuer",
+			config: dynamic.OIDCAuth{ClientID: "client", SessionKey: "key"},
+		},
+		{
+			desc:   "missing client ID",
+			config: dynamic.OIDCAuth{Issuer: "https://issuer.example.com", SessionKey: "key"},
+		},
+		{
+			desc:   "missing session key",
+			config: dynamic.OIDCAuth{Issuer: "https://issuer.example.com", ClientID: "client"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			test.config.RedirectPath = "/oauth2/callback"
+
+			_, err := NewOIDC(context.Background(), http.NotFoundHandler(), test.config, "oidc")
+			require.Error(t, err)
+		})
+	}
+}
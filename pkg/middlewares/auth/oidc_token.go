@@ -0,0 +1,158 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+)
+
+// errUnknownKey is returned when an ID token is signed with a key which is not in the key set of the provider.
+var errUnknownKey = errors.New("unknown signing key")
+
+// signingAlgorithms are the JSON Web Signature algorithms (RFC 7518) accepted for the ID tokens.
+// The symmetric algorithms are not, as the keys of the provider are public.
+var signingAlgorithms = map[jose.SignatureAlgorithm]bool{
+	jose.RS256: true,
+	jose.RS384: true,
+	jose.RS512: true,
+	jose.PS256: true,
+	jose.PS384: true,
+	jose.PS512: true,
+	jose.ES256: true,
+	jose.ES384: true,
+	jose.ES512: true,
+}
+
+// idTokenClaims are the claims of an ID token the middleware validates.
+type idTokenClaims struct {
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"`
+	Audience  audience `json:"aud"`
+	Expiry    int64    `json:"exp"`
+	NotBefore int64    `json:"nbf"`
+	Nonce     string   `json:"nonce"`
+}
+
+// audience is the audience of an ID token, which is either a string or a list of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+
+	*a = multiple
+	return nil
+}
+
+func (a audience) contains(clientID string) bool {
+	for _, aud := range a {
+		if aud == clientID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// idToken is a validated ID token.
+type idToken struct {
+	Subject string
+	Expiry  time.Time
+	Claims  map[string]interface{}
+}
+
+// parseIDToken verifies the signature of the given ID token with the given keys,
+// validates its issuer, audience, expiry, and nonce, and returns its claims.
+// It returns errUnknownKey when the token is signed with a key which is not in the given keys.
+func parseIDToken(token string, keys *jose.JSONWebKeySet, issuer, clientID, nonce string, now time.Time) (*idToken, error) {
+	jws, err := jose.ParseSigned(token)
+	if err != nil {
+		return nil, fmt.Errorf("malformed token: %w", err)
+	}
+
+	if len(jws.Signatures) != 1 {
+		return nil, errors.New("malformed token")
+	}
+
+	header := jws.Signatures[0].Header
+	if !signingAlgorithms[jose.SignatureAlgorithm(header.Algorithm)] {
+		return nil, fmt.Errorf("unsupported signing algorithm %q", header.Algorithm)
+	}
+
+	payload, err := verifySignature(jws, header.KeyID, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims idTokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("invalid claims: %w", err)
+	}
+
+	switch {
+	case claims.Issuer != issuer:
+		return nil, fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	case !claims.Audience.contains(clientID):
+		return nil, errors.New("token not issued for the client")
+	case claims.Expiry == 0 || now.Unix() >= claims.Expiry:
+		return nil, errors.New("token expired")
+	case claims.NotBefore != 0 && now.Unix() < claims.NotBefore:
+		return nil, errors.New("token not valid yet")
+	case claims.Nonce != nonce:
+		return nil, errors.New("unexpected nonce")
+	}
+
+	var allClaims map[string]interface{}
+	if err := json.Unmarshal(payload, &allClaims); err != nil {
+		return nil, fmt.Errorf("invalid claims: %w", err)
+	}
+
+	return &idToken{
+		Subject: claims.Subject,
+		Expiry:  time.Unix(claims.Expiry, 0),
+		Claims:  allClaims,
+	}, nil
+}
+
+// verifySignature verifies the signature with the key of the given ID, or with any key if there is no ID,
+// and returns the payload of the token.
+func verifySignature(jws *jose.JSONWebSignature, kid string, keys *jose.JSONWebKeySet) ([]byte, error) {
+	candidates := keys.Keys
+	if kid != "" {
+		candidates = keys.Key(kid)
+	}
+
+	var found bool
+	for _, key := range candidates {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+
+		if !key.Valid() || !key.IsPublic() {
+			continue
+		}
+
+		found = true
+
+		if payload, err := jws.Verify(key); err == nil {
+			return payload, nil
+		}
+	}
+
+	if !found {
+		return nil, errUnknownKey
+	}
+
+	return nil, errors.New("invalid signature")
+}
@@ -0,0 +1,172 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+)
+
+func Test_parseIDToken(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keys := &jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+		{Key: &rsaKey.PublicKey, KeyID: "rsa", Use: "sig"},
+		{Key: &ecKey.PublicKey, KeyID: "ec"},
+		{Key: []byte("secret"), KeyID: "hmac"},
+	}}
+
+	now := time.Unix(1600000000, 0)
+
+	validClaims := func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss":   "https://issuer.example.com",
+			"sub":   "user",
+			"aud":   "client",
+			"exp":   now.Add(time.Hour).Unix(),
+			"nonce": "nonce",
+			"email": "user@example.com",
+		}
+	}
+
+	testCases := []struct {
+		desc          string
+		token         func() string
+		expectedError error
+		expectedValid bool
+	}{
+		{
+			desc:          "RS256",
+			token:         func() string { return signToken(t, jose.RS256, rsaKey, "rsa", validClaims()) },
+			expectedValid: true,
+		},
+		{
+			desc:          "ES256",
+			token:         func() string { return signToken(t, jose.ES256, ecKey, "ec", validClaims()) },
+			expectedValid: true,
+		},
+		{
+			desc: "audience list",
+			token: func() string {
+				claims := validClaims()
+				claims["aud"] = []string{"other", "client"}
+				return signToken(t, jose.RS256, rsaKey, "rsa", claims)
+			},
+			expectedValid: true,
+		},
+		{
+			desc:          "unknown key",
+			token:         func() string { return signToken(t, jose.RS256, otherKey, "other", validClaims()) },
+			expectedError: errUnknownKey,
+		},
+		{
+			desc:  "invalid signature",
+			token: func() string { return signToken(t, jose.RS256, otherKey, "rsa", validClaims()) },
+		},
+		{
+			desc: "unexpected issuer",
+			token: func() string {
+				claims := validClaims()
+				claims["iss"] = "https://other.example.com"
+				return signToken(t, jose.RS256, rsaKey, "rsa", claims)
+			},
+		},
+		{
+			desc: "unexpected audience",
+			token: func() string {
+				claims := validClaims()
+				claims["aud"] = "other"
+				return signToken(t, jose.RS256, rsaKey, "rsa", claims)
+			},
+		},
+		{
+			desc: "expired",
+			token: func() string {
+				claims := validClaims()
+				claims["exp"] = now.Unix()
+				return signToken(t, jose.RS256, rsaKey, "rsa", claims)
+			},
+		},
+		{
+			desc: "unexpected nonce",
+			token: func() string {
+				claims := validClaims()
+				claims["nonce"] = "other"
+				return signToken(t, jose.RS256, rsaKey, "rsa", claims)
+			},
+		},
+		{
+			desc:  "symmetric key",
+			token: func() string { return signToken(t, jose.HS256, []byte("secret"), "hmac", validClaims()) },
+		},
+		{
+			desc: "unsigned",
+			token: func() string {
+				return encodeSegment(t, map[string]string{"alg": "none"}) + "." + encodeSegment(t, validClaims()) + "."
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			token, err := parseIDToken(test.token(), keys, "https://issuer.example.com", "client", "nonce", now)
+			if !test.expectedValid {
+				require.Error(t, err)
+				if test.expectedError != nil {
+					assert.ErrorIs(t, err, test.expectedError)
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "user", token.Subject)
+			assert.Equal(t, now.Add(time.Hour), token.Expiry)
+			assert.Equal(t, "user@example.com", token.Claims["email"])
+		})
+	}
+}
+
+func signToken(t *testing.T, alg jose.SignatureAlgorithm, key interface{}, kid string, claims map[string]interface{}) string {
+	t.Helper()
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: alg, Key: jose.JSONWebKey{Key: key, KeyID: kid}}, nil)
+	require.NoError(t, err)
+
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	jws, err := signer.Sign(payload)
+	require.NoError(t, err)
+
+	token, err := jws.CompactSerialize()
+	require.NoError(t, err)
+
+	return token
+}
+
+func encodeSegment(t *testing.T, v interface{}) string {
+	t.Helper()
+
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// fileCheckInterval is the minimum delay between two checks for changes of a file of credentials.
+const fileCheckInterval = time.Second
+
+// reloadingFile holds the content loaded from a file of credentials, such as the users or keys file of a middleware,
+// and loads it again once the file has been modified.
+// The file is checked at most once per fileCheckInterval, and the previous content is kept if the file cannot be loaded.
+type reloadingFile struct {
+	path string
+	// kind describes the file in the logs, e.g. "users file".
+	kind string
+	load func() (interface{}, error)
+
+	mu        sync.RWMutex
+	content   interface{}
+	modTime   time.Time
+	nextCheck time.Time
+}
+
+// newReloadingFile returns the content loaded with the given function, which reads the file at the given path.
+// When the path is empty, the content is loaded once and never reloaded.
+func newReloadingFile(path, kind string, load func() (interface{}, error)) (*reloadingFile, error) {
+	var modTime time.Time
+	if path != "" {
+		fileInfo, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		modTime = fileInfo.ModTime()
+	}
+
+	content, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	return &reloadingFile{
+		path:      path,
+		kind:      kind,
+		load:      load,
+		content:   content,
+		modTime:   modTime,
+		nextCheck: time.Now().Add(fileCheckInterval),
+	}, nil
+}
+
+// get returns the current content.
+func (f *reloadingFile) get() interface{} {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.content
+}
+
+// reload loads the file again if it has been modified since it was last loaded, and the check of the file is due.
+func (f *reloadingFile) reload(logger log.Logger) {
+	if f.path == "" {
+		return
+	}
+
+	now := time.Now()
+
+	f.mu.RLock()
+	skip := now.Before(f.nextCheck)
+	f.mu.RUnlock()
+
+	if skip {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if now.Before(f.nextCheck) {
+		// Another request has checked the file in the meantime.
+		return
+	}
+	f.nextCheck = now.Add(fileCheckInterval)
+
+	fileInfo, err := os.Stat(f.path)
+	if err != nil {
+		logger.Errorf("Unable to check the %s %s: %v", f.kind, f.path, err)
+		return
+	}
+
+	if fileInfo.ModTime().Equal(f.modTime) {
+		return
+	}
+
+	content, err := f.load()
+	if err != nil {
+		logger.Errorf("Unable to reload the %s %s, keeping the previous content: %v", f.kind, f.path, err)
+		return
+	}
+
+	logger.Debugf("The %s %s has been reloaded", f.kind, f.path)
+	f.content = content
+	f.modTime = fileInfo.ModTime()
+}
@@ -0,0 +1,149 @@
+package botblocker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "BotBlocker"
+)
+
+// The actions taken on the matching requests.
+const (
+	actionDeny   = "deny"
+	actionTarpit = "tarpit"
+)
+
+const defaultTarpitDelay = 10 * time.Second
+
+// maxTarpitted is the maximum number of requests tarpitted at the same time by a middleware.
+// The matching requests beyond it are denied right away, so that the tarpit cannot exhaust the resources of Traefik.
+const maxTarpitted = 1000
+
+// botBlocker is a middleware denying, or tarpitting, the requests matching its rules.
+type botBlocker struct {
+	next       http.Handler
+	name       string
+	rules      *rules
+	listFiles  []*listFile
+	tarpit     bool
+	statusCode int
+	delay      time.Duration
+
+	tarpitted int64
+}
+
+// New creates a new bot blocker middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.BotBlocker, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	r, err := newRules(config)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &botBlocker{
+		next:       next,
+		name:       name,
+		rules:      r,
+		statusCode: config.StatusCode,
+		delay:      time.Duration(config.TarpitDelay),
+	}
+
+	for _, path := range config.ListFiles {
+		lf, err := getListFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("loading the list file %s: %w", path, err)
+		}
+
+		b.listFiles = append(b.listFiles, lf)
+	}
+
+	switch config.Action {
+	case "", actionDeny:
+	case actionTarpit:
+		b.tarpit = true
+	default:
+		return nil, fmt.Errorf("unknown action %q", config.Action)
+	}
+
+	if b.statusCode == 0 {
+		b.statusCode = http.StatusForbidden
+	}
+
+	if b.statusCode < 100 || b.statusCode > 999 {
+		return nil, fmt.Errorf("invalid status code: %d", b.statusCode)
+	}
+
+	if b.delay <= 0 {
+		b.delay = defaultTarpitDelay
+	}
+
+	return b, nil
+}
+
+func (b *botBlocker) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return b.name, tracing.SpanKindNoneEnum
+}
+
+func (b *botBlocker) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	reason, ok := b.match(req)
+	if !ok {
+		b.next.ServeHTTP(rw, req)
+		return
+	}
+
+	logMessage := fmt.Sprintf("Blocking request: %s", reason)
+	log.FromContext(middlewares.GetLoggerCtx(req.Context(), b.name, typeName)).Debug(logMessage)
+	tracing.SetErrorWithEvent(req, "%s", logMessage)
+
+	if b.tarpit {
+		b.wait(req.Context())
+	}
+
+	rw.WriteHeader(b.statusCode)
+	_, _ = rw.Write([]byte(http.StatusText(b.statusCode)))
+}
+
+// match returns a description of the first rule matching the request, and whether a rule matches.
+func (b *botBlocker) match(req *http.Request) (string, bool) {
+	if reason, ok := b.rules.match(req); ok {
+		return reason, true
+	}
+
+	for _, lf := range b.listFiles {
+		if reason, ok := lf.getRules().match(req); ok {
+			return fmt.Sprintf("%s (%s)", reason, lf.path), true
+		}
+	}
+
+	return "", false
+}
+
+// wait holds the request for the tarpit delay, unless the request is canceled,
+// or too many requests are already tarpitted.
+func (b *botBlocker) wait(ctx context.Context) {
+	if atomic.AddInt64(&b.tarpitted, 1) > maxTarpitted {
+		atomic.AddInt64(&b.tarpitted, -1)
+		return
+	}
+	defer atomic.AddInt64(&b.tarpitted, -1)
+
+	timer := time.NewTimer(b.delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,253 @@
+package botblocker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      dynamic.BotBlocker
+		expectedErr bool
+	}{
+		{
+			desc:   "valid configuration",
+			config: dynamic.BotBlocker{UserAgents: []string{"(?i)curl"}, Action: "tarpit"},
+		},
+		{
+			desc:        "invalid regular expression",
+			config:      dynamic.BotBlocker{Paths: []string{"^/wp-admin("}},
+			expectedErr: true,
+		},
+		{
+			desc:        "header without name",
+			config:      dynamic.BotBlocker{Headers: []dynamic.BotBlockerHeader{{Regex: "foo"}}},
+			expectedErr: true,
+		},
+		{
+			desc:        "unknown action",
+			config:      dynamic.BotBlocker{Action: "drop"},
+			expectedErr: true,
+		},
+		{
+			desc:        "invalid status code",
+			config:      dynamic.BotBlocker{StatusCode: 42},
+			expectedErr: true,
+		},
+		{
+			desc:        "missing list file",
+			config:      dynamic.BotBlocker{ListFiles: []string{"missing.txt"}},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+			_, err := New(context.Background(), next, test.config, "botBlocker")
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestBotBlocker_ServeHTTP(t *testing.T) {
+	config := dynamic.BotBlocker{
+		UserAgents: []string{"(?i)sqlmap", "^$"},
+		Paths:      []string{`^/wp-admin`, `\.php$`},
+		Headers:    []dynamic.BotBlockerHeader{{Name: "x-scanner", Regex: ".+"}},
+		StatusCode: http.StatusTeapot,
+	}
+
+	testCases := []struct {
+		desc         string
+		path         string
+		header       http.Header
+		expectedCode int
+	}{
+		{
+			desc:         "no rule matching",
+			path:         "/",
+			header:       http.Header{"User-Agent": {"Mozilla/5.0"}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "user agent matching",
+			path:         "/",
+			header:       http.Header{"User-Agent": {"SQLMap/1.5"}},
+			expectedCode: http.StatusTeapot,
+		},
+		{
+			desc:         "empty user agent",
+			path:         "/",
+			expectedCode: http.StatusTeapot,
+		},
+		{
+			desc:         "path matching",
+			path:         "/wp-admin/login",
+			header:       http.Header{"User-Agent": {"Mozilla/5.0"}},
+			expectedCode: http.StatusTeapot,
+		},
+		{
+			desc:         "path matching the suffix",
+			path:         "/index.php",
+			header:       http.Header{"User-Agent": {"Mozilla/5.0"}},
+			expectedCode: http.StatusTeapot,
+		},
+		{
+			desc:         "header matching",
+			path:         "/",
+			header:       http.Header{"User-Agent": {"Mozilla/5.0"}, "X-Scanner": {"nikto"}},
+			expectedCode: http.StatusTeapot,
+		},
+	}
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	handler, err := New(context.Background(), next, config, "botBlocker")
+	require.NoError(t, err)
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost"+test.path, nil)
+			req.Header = test.header
+			if req.Header == nil {
+				req.Header = http.Header{}
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedCode, recorder.Code)
+		})
+	}
+}
+
+func TestBotBlocker_listFile(t *testing.T) {
+	listPath := filepath.Join(t.TempDir(), "bots.txt")
+	err := os.WriteFile(listPath, []byte("# Scanners\nuser-agent (?i)nikto\n\npath ^/\\.env\nheader X-Scanner .+\n"), 0o600)
+	require.NoError(t, err)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	handler, err := New(context.Background(), next, dynamic.BotBlocker{ListFiles: []string{listPath}}, "botBlocker")
+	require.NoError(t, err)
+
+	serve := func(path, userAgent string) int {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost"+path, nil)
+		req.Header.Set("User-Agent", userAgent)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, serve("/", "Nikto/2.1"))
+	assert.Equal(t, http.StatusForbidden, serve("/.env", "Mozilla/5.0"))
+	assert.Equal(t, http.StatusOK, serve("/", "curl/7.79"))
+
+	// The list file is reloaded once modified.
+	err = os.WriteFile(listPath, []byte("user-agent ^curl/\n"), 0o600)
+	require.NoError(t, err)
+	err = os.Chtimes(listPath, time.Now(), time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	lf, err := getListFile(listPath)
+	require.NoError(t, err)
+	defer lf.file.Release()
+
+	lf.file.Reload()
+
+	assert.Equal(t, http.StatusOK, serve("/", "Nikto/2.1"))
+	assert.Equal(t, http.StatusForbidden, serve("/", "curl/7.79"))
+
+	// The previous rules are kept if the list file is invalid.
+	err = os.WriteFile(listPath, []byte("agent ^curl/\n"), 0o600)
+	require.NoError(t, err)
+	err = os.Chtimes(listPath, time.Now(), time.Now().Add(2*time.Minute))
+	require.NoError(t, err)
+
+	lf.file.Reload()
+
+	assert.Equal(t, http.StatusForbidden, serve("/", "curl/7.79"))
+}
+
+func TestParseRules(t *testing.T) {
+	_, err := parseRules(strings.NewReader("user-agent foo\nreferer bar\n"))
+	assert.EqualError(t, err, `line 2: unknown rule kind "referer"`)
+
+	_, err = parseRules(strings.NewReader("header X-Foo\n"))
+	assert.EqualError(t, err, "line 1: empty regular expression")
+
+	r, err := parseRules(strings.NewReader("header X-Foo ^a b$\n"))
+	require.NoError(t, err)
+	require.Len(t, r.headers, 1)
+	assert.Equal(t, "X-Foo", r.headers[0].name)
+	assert.Equal(t, "^a b$", r.headers[0].regex.String())
+}
+
+func TestBotBlocker_tarpit(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	config := dynamic.BotBlocker{
+		UserAgents:  []string{"(?i)sqlmap"},
+		Action:      "tarpit",
+		TarpitDelay: ptypes.Duration(50 * time.Millisecond),
+	}
+
+	handler, err := New(context.Background(), next, config, "botBlocker")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+	req.Header.Set("User-Agent", "sqlmap/1.5")
+
+	start := time.Now()
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	// A canceled request is answered right away.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	config.TarpitDelay = ptypes.Duration(time.Hour)
+	handler, err = New(context.Background(), next, config, "botBlocker")
+	require.NoError(t, err)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req.WithContext(ctx))
+
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+}
@@ -0,0 +1,39 @@
+package botblocker
+
+import (
+	"io"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/middlewares/sharedfile"
+)
+
+// listFileCheckInterval is the minimum delay between two checks for changes of a list file.
+const listFileCheckInterval = 10 * time.Second
+
+// listFiles holds the list files, shared by all the middlewares,
+// as a list is usually used by many routers, and a middleware is built for every router using it.
+var listFiles = sharedfile.NewRegistry("bot blocker list file", listFileCheckInterval, func(r io.Reader) (interface{}, error) {
+	return parseRules(r)
+})
+
+// listFile is a file of rules, reloaded asynchronously when it is modified.
+// The previous rules are kept if the file cannot be reloaded.
+type listFile struct {
+	path string
+	file *sharedfile.Handle
+}
+
+// getListFile returns the list file, which is loaded if it is not already.
+func getListFile(path string) (*listFile, error) {
+	file, err := listFiles.Acquire(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &listFile{path: path, file: file}, nil
+}
+
+// getRules returns the current rules of the file.
+func (l *listFile) getRules() *rules {
+	return l.file.Get().(*rules)
+}
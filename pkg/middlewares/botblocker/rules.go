@@ -0,0 +1,179 @@
+package botblocker
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+// rules are the regular expressions matched against the requests.
+type rules struct {
+	userAgents []*regexp.Regexp
+	paths      []*regexp.Regexp
+	headers    []headerRule
+}
+
+type headerRule struct {
+	name  string
+	regex *regexp.Regexp
+}
+
+// newRules compiles the regular expressions of the configuration.
+func newRules(config dynamic.BotBlocker) (*rules, error) {
+	r := &rules{}
+
+	for _, expr := range config.UserAgents {
+		if err := r.addUserAgent(expr); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, expr := range config.Paths {
+		if err := r.addPath(expr); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, header := range config.Headers {
+		if err := r.addHeader(header.Name, header.Regex); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// parseRules reads the rules of a list file.
+// Each line holds a rule, made of its kind (user-agent, path, or header followed by the header name) and its regular expression,
+// e.g. "user-agent (?i)curl", "path ^/wp-admin", or "header X-Scanner .+".
+// The empty lines and the lines starting with # are ignored.
+func parseRules(reader io.Reader) (*rules, error) {
+	r := &rules{}
+
+	scanner := bufio.NewScanner(reader)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		kind, expr := cut(line)
+
+		var err error
+		switch kind {
+		case "user-agent":
+			err = r.addUserAgent(expr)
+		case "path":
+			err = r.addPath(expr)
+		case "header":
+			name, headerExpr := cut(expr)
+			err = r.addHeader(name, headerExpr)
+		default:
+			err = fmt.Errorf("unknown rule kind %q", kind)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *rules) addUserAgent(expr string) error {
+	regex, err := compile(expr)
+	if err != nil {
+		return err
+	}
+
+	r.userAgents = append(r.userAgents, regex)
+
+	return nil
+}
+
+func (r *rules) addPath(expr string) error {
+	regex, err := compile(expr)
+	if err != nil {
+		return err
+	}
+
+	r.paths = append(r.paths, regex)
+
+	return nil
+}
+
+func (r *rules) addHeader(name, expr string) error {
+	if name == "" {
+		return fmt.Errorf("header name missing for the regular expression %q", expr)
+	}
+
+	regex, err := compile(expr)
+	if err != nil {
+		return err
+	}
+
+	r.headers = append(r.headers, headerRule{name: http.CanonicalHeaderKey(name), regex: regex})
+
+	return nil
+}
+
+// match returns a description of the first rule matching the request, and whether a rule matches.
+// The user agent rules are matched even if the request has no User-Agent header,
+// whereas the header rules are only matched against the values of the headers.
+func (r *rules) match(req *http.Request) (string, bool) {
+	userAgent := req.UserAgent()
+	for _, regex := range r.userAgents {
+		if regex.MatchString(userAgent) {
+			return fmt.Sprintf("user agent %q matches %q", userAgent, regex), true
+		}
+	}
+
+	for _, regex := range r.paths {
+		if regex.MatchString(req.URL.Path) {
+			return fmt.Sprintf("path %q matches %q", req.URL.Path, regex), true
+		}
+	}
+
+	for _, rule := range r.headers {
+		for _, value := range req.Header.Values(rule.name) {
+			if rule.regex.MatchString(value) {
+				return fmt.Sprintf("header %s %q matches %q", rule.name, value, rule.regex), true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func compile(expr string) (*regexp.Regexp, error) {
+	if expr == "" {
+		return nil, errors.New("empty regular expression")
+	}
+
+	regex, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression %q: %w", expr, err)
+	}
+
+	return regex, nil
+}
+
+// cut splits the given string around its first run of spaces.
+func cut(s string) (string, string) {
+	i := strings.IndexAny(s, " \t")
+	if i < 0 {
+		return s, ""
+	}
+
+	return s[:i], strings.TrimSpace(s[i+1:])
+}
@@ -0,0 +1,152 @@
+package buffering
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestBuffering(t *testing.T) {
+	payload := make([]byte, 100)
+
+	testCases := []struct {
+		desc           string
+		config         dynamic.Buffering
+		body           []byte
+		chunked        bool
+		responseBody   []byte
+		expectedCode   int
+		expectedCalled bool
+	}{
+		{
+			desc:           "request body within the limits",
+			config:         dynamic.Buffering{MaxRequestBodyBytes: 200, MemRequestBodyBytes: 200},
+			body:           payload,
+			expectedCode:   http.StatusOK,
+			expectedCalled: true,
+		},
+		{
+			desc:           "request body buffered on disk",
+			config:         dynamic.Buffering{MaxRequestBodyBytes: 200, MemRequestBodyBytes: 10},
+			body:           payload,
+			expectedCode:   http.StatusOK,
+			expectedCalled: true,
+		},
+		{
+			desc:         "request body over the limit",
+			config:       dynamic.Buffering{MaxRequestBodyBytes: 10, MemRequestBodyBytes: 10},
+			body:         payload,
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			desc:         "chunked request body over the limit",
+			config:       dynamic.Buffering{MaxRequestBodyBytes: 10, MemRequestBodyBytes: 10},
+			body:         payload,
+			chunked:      true,
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			desc:           "response body within the limits",
+			config:         dynamic.Buffering{MaxRequestBodyBytes: 10, MemRequestBodyBytes: 10, MaxResponseBodyBytes: 200, MemResponseBodyBytes: 10},
+			responseBody:   payload,
+			expectedCode:   http.StatusOK,
+			expectedCalled: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var called bool
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				called = true
+
+				body, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				assert.Equal(t, len(test.body), len(body))
+				assert.Equal(t, int64(len(test.body)), req.ContentLength)
+
+				rw.WriteHeader(http.StatusOK)
+				_, err = rw.Write(test.responseBody)
+				require.NoError(t, err)
+			})
+
+			handler, err := New(context.Background(), next, test.config, "buffering")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost", bytes.NewReader(test.body))
+			if test.chunked {
+				// The size of a chunked request is only known once it is read.
+				req.Body = io.NopCloser(bytes.NewReader(test.body))
+				req.ContentLength = -1
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			assert.Equal(t, test.expectedCalled, called)
+
+			if test.expectedCalled {
+				assert.Equal(t, test.responseBody, recorder.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestBuffering_retry(t *testing.T) {
+	var attempts int
+	var bodies []string
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		attempts++
+
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, string(body))
+
+		// The buffered responses must have a body to be read back.
+		if attempts < 3 {
+			http.Error(rw, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+			return
+		}
+
+		// The status code is not set implicitly by the buffered response writer.
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write([]byte("bar"))
+	})
+
+	config := dynamic.Buffering{
+		MaxRequestBodyBytes: 10,
+		MemRequestBodyBytes: 10,
+		RetryExpression:     "IsNetworkError() && Attempts() < 3",
+	}
+	handler, err := New(context.Background(), next, config, "buffering")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost", bytes.NewReader([]byte("foo")))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "bar", recorder.Body.String())
+
+	// The buffered request body is replayed on each attempt.
+	assert.Equal(t, []string{"foo", "foo", "foo"}, bodies)
+}
+
+func TestBuffering_invalidRetryExpression(t *testing.T) {
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+	_, err := New(context.Background(), next, dynamic.Buffering{RetryExpression: "IsNetworkError("}, "buffering")
+	assert.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package chain
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/alice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+// chainBuilderMock builds chains of middlewares which append their name to the X-Chain request header.
+type chainBuilderMock struct{}
+
+func (chainBuilderMock) BuildChain(_ context.Context, middlewares []string) *alice.Chain {
+	chain := alice.New()
+	for _, name := range middlewares {
+		name := name
+		chain = chain.Append(func(next http.Handler) (http.Handler, error) {
+			return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				req.Header.Add("X-Chain", name)
+				next.ServeHTTP(rw, req)
+			}), nil
+		})
+	}
+
+	return &chain
+}
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		middlewares []string
+		expected    []string
+	}{
+		{
+			desc:     "no middleware",
+			expected: nil,
+		},
+		{
+			desc:        "one middleware",
+			middlewares: []string{"secure-headers"},
+			expected:    []string{"secure-headers"},
+		},
+		{
+			desc:        "middlewares applied in order",
+			middlewares: []string{"secure-headers", "auth", "ratelimit"},
+			expected:    []string{"secure-headers", "auth", "ratelimit"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var chained []string
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				chained = req.Header.Values("X-Chain")
+			})
+
+			handler, err := New(context.Background(), next, dynamic.Chain{Middlewares: test.middlewares}, chainBuilderMock{}, "chain")
+			require.NoError(t, err)
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, test.expected, chained)
+		})
+	}
+}
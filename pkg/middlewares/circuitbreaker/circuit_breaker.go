@@ -2,7 +2,9 @@ package circuitbreaker
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/opentracing/opentracing-go/ext"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
@@ -27,9 +29,34 @@ func New(ctx context.Context, next http.Handler, confCircuitBreaker dynamic.Circ
 
 	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
 	logger.Debug("Creating middleware")
-	logger.Debug("Setting up with expression: %s", expression)
+	logger.Debugf("Setting up with expression: %s", expression)
 
-	oxyCircuitBreaker, err := cbreaker.New(next, expression, createCircuitBreakerOptions(expression))
+	responseCode := confCircuitBreaker.ResponseCode
+	if responseCode == 0 {
+		responseCode = http.StatusServiceUnavailable
+	}
+
+	if responseCode < 100 || responseCode > 999 {
+		return nil, fmt.Errorf("invalid response code: %d", responseCode)
+	}
+
+	cbOpts := []cbreaker.CircuitBreakerOption{
+		createCircuitBreakerOptions(expression, responseCode),
+	}
+
+	if confCircuitBreaker.CheckPeriod > 0 {
+		cbOpts = append(cbOpts, cbreaker.CheckPeriod(time.Duration(confCircuitBreaker.CheckPeriod)))
+	}
+
+	if confCircuitBreaker.FallbackDuration > 0 {
+		cbOpts = append(cbOpts, cbreaker.FallbackDuration(time.Duration(confCircuitBreaker.FallbackDuration)))
+	}
+
+	if confCircuitBreaker.RecoveryDuration > 0 {
+		cbOpts = append(cbOpts, cbreaker.RecoveryDuration(time.Duration(confCircuitBreaker.RecoveryDuration)))
+	}
+
+	oxyCircuitBreaker, err := cbreaker.New(next, expression, cbOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -39,13 +66,13 @@ func New(ctx context.Context, next http.Handler, confCircuitBreaker dynamic.Circ
 	}, nil
 }
 
-// NewCircuitBreakerOptions returns a new CircuitBreakerOption.
-func createCircuitBreakerOptions(expression string) cbreaker.CircuitBreakerOption {
+// createCircuitBreakerOptions returns the fallback option, responding with the given status code.
+func createCircuitBreakerOptions(expression string, responseCode int) cbreaker.CircuitBreakerOption {
 	return cbreaker.Fallback(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		tracing.SetErrorWithEvent(req, "blocked by circuit-breaker (%q)", expression)
-		rw.WriteHeader(http.StatusServiceUnavailable)
+		rw.WriteHeader(responseCode)
 
-		if _, err := rw.Write([]byte(http.StatusText(http.StatusServiceUnavailable))); err != nil {
+		if _, err := rw.Write([]byte(http.StatusText(responseCode))); err != nil {
 			log.FromContext(req.Context()).Error(err)
 		}
 	}))
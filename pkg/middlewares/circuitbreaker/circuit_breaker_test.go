@@ -0,0 +1,120 @@
+package circuitbreaker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      dynamic.CircuitBreaker
+		expectedErr bool
+	}{
+		{
+			desc:   "valid expression",
+			config: dynamic.CircuitBreaker{Expression: "NetworkErrorRatio() > 0.3 || LatencyAtQuantileMS(50.0) > 100"},
+		},
+		{
+			desc:        "invalid expression",
+			config:      dynamic.CircuitBreaker{Expression: "NetworkErrorRatio( > 0.3"},
+			expectedErr: true,
+		},
+		{
+			desc:        "invalid response code",
+			config:      dynamic.CircuitBreaker{Expression: "NetworkErrorRatio() > 0.3", ResponseCode: 42},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+			_, err := New(context.Background(), next, test.config, "circuitBreaker")
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCircuitBreaker_fallback(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		responseCode int
+		expectedCode int
+	}{
+		{
+			desc:         "default response code",
+			expectedCode: http.StatusServiceUnavailable,
+		},
+		{
+			desc:         "custom response code",
+			responseCode: http.StatusTeapot,
+			expectedCode: http.StatusTeapot,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				calls++
+				rw.WriteHeader(http.StatusBadGateway)
+			})
+
+			config := dynamic.CircuitBreaker{
+				Expression:       "NetworkErrorRatio() > 0.5",
+				CheckPeriod:      ptypes.Duration(time.Millisecond),
+				FallbackDuration: ptypes.Duration(time.Minute),
+				ResponseCode:     test.responseCode,
+			}
+
+			handler, err := New(context.Background(), next, config, "circuitBreaker")
+			require.NoError(t, err)
+
+			// The circuit breaker opens once its condition is checked, at the latest after the check period.
+			var recorder *httptest.ResponseRecorder
+			for i := 0; i < 100; i++ {
+				recorder = httptest.NewRecorder()
+				handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+				if recorder.Code != http.StatusBadGateway {
+					break
+				}
+
+				time.Sleep(2 * time.Millisecond)
+			}
+
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			assert.Equal(t, http.StatusText(test.expectedCode), recorder.Body.String())
+
+			// The requests are not forwarded while the circuit breaker is open.
+			forwarded := calls
+
+			recorder = httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			assert.Equal(t, forwarded, calls)
+		})
+	}
+}
@@ -3,8 +3,11 @@ package compress
 import (
 	"compress/gzip"
 	"context"
+	"fmt"
 	"mime"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/klauspost/compress/gzhttp"
 	"github.com/opentracing/opentracing-go/ext"
@@ -18,11 +21,25 @@ const (
 	typeName = "Compress"
 )
 
+// The supported encodings.
+const (
+	gzipName   = "gzip"
+	brotliName = "br"
+	zstdName   = "zstd"
+)
+
+// defaultEncodings are the supported encodings, by order of preference.
+var defaultEncodings = []string{zstdName, brotliName, gzipName}
+
 // Compress is a middleware that allows to compress the response.
 type compress struct {
-	next     http.Handler
-	name     string
-	excludes []string
+	next      http.Handler
+	name      string
+	excludes  []string
+	minSize   int
+	encodings []string
+
+	gzipHandler http.Handler
 }
 
 // New creates a new compress middleware.
@@ -39,7 +56,37 @@ func New(ctx context.Context, next http.Handler, conf dynamic.Compress, name str
 		excludes = append(excludes, mediaType)
 	}
 
-	return &compress{next: next, name: name, excludes: excludes}, nil
+	minSize := gzhttp.DefaultMinSize
+	if conf.MinResponseBodyBytes > 0 {
+		minSize = conf.MinResponseBodyBytes
+	}
+
+	encodings := defaultEncodings
+	if len(conf.Encodings) > 0 {
+		encodings = make([]string, 0, len(conf.Encodings))
+		for _, encoding := range conf.Encodings {
+			encoding = strings.ToLower(strings.TrimSpace(encoding))
+			switch encoding {
+			case gzipName, brotliName, zstdName:
+				encodings = append(encodings, encoding)
+			default:
+				return nil, fmt.Errorf("unsupported encoding %q", encoding)
+			}
+		}
+	}
+
+	c := &compress{next: next, name: name, excludes: excludes, minSize: minSize, encodings: encodings}
+
+	gzipWrapper, err := gzhttp.NewWrapper(
+		gzhttp.ExceptContentTypes(c.excludes),
+		gzhttp.CompressionLevel(gzip.DefaultCompression),
+		gzhttp.MinSize(minSize))
+	if err != nil {
+		return nil, err
+	}
+	c.gzipHandler = gzipWrapper(next)
+
+	return c, nil
 }
 
 func (c *compress) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
@@ -50,9 +97,26 @@ func (c *compress) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 
 	if contains(c.excludes, mediaType) {
 		c.next.ServeHTTP(rw, req)
-	} else {
-		ctx := middlewares.GetLoggerCtx(req.Context(), c.name, typeName)
-		c.gzipHandler(ctx).ServeHTTP(rw, req)
+		return
+	}
+
+	switch encoding := c.negotiate(req.Header.Values("Accept-Encoding")); encoding {
+	case gzipName:
+		c.gzipHandler.ServeHTTP(rw, req)
+
+	case brotliName, zstdName:
+		crw := newResponseWriter(rw, encoding, c.minSize, c.excludes)
+		defer func() {
+			if err := crw.close(); err != nil {
+				ctx := middlewares.GetLoggerCtx(req.Context(), c.name, typeName)
+				log.FromContext(ctx).Errorf("Error while closing the %s encoder: %v", encoding, err)
+			}
+		}()
+
+		c.next.ServeHTTP(crw, req)
+
+	default:
+		c.next.ServeHTTP(rw, req)
 	}
 }
 
@@ -60,16 +124,60 @@ func (c *compress) GetTracingInformation() (string, ext.SpanKindEnum) {
 	return c.name, tracing.SpanKindNoneEnum
 }
 
-func (c *compress) gzipHandler(ctx context.Context) http.Handler {
-	wrapper, err := gzhttp.NewWrapper(
-		gzhttp.ExceptContentTypes(c.excludes),
-		gzhttp.CompressionLevel(gzip.DefaultCompression),
-		gzhttp.MinSize(gzhttp.DefaultMinSize))
-	if err != nil {
-		log.FromContext(ctx).Error(err)
+// negotiate returns the encoding of the response, i.e. the supported encoding with the highest quality value
+// in the Accept-Encoding header values, the order of preference breaking the ties,
+// or an empty string if none is acceptable.
+func (c *compress) negotiate(acceptEncoding []string) string {
+	qualities := make(map[string]float64)
+	wildcard := -1.0
+
+	for _, value := range acceptEncoding {
+		for _, item := range strings.Split(value, ",") {
+			parts := strings.Split(item, ";")
+
+			encoding := strings.ToLower(strings.TrimSpace(parts[0]))
+			if encoding == "" {
+				continue
+			}
+
+			quality := 1.0
+			for _, param := range parts[1:] {
+				param = strings.TrimSpace(param)
+				if !strings.HasPrefix(param, "q=") {
+					continue
+				}
+
+				q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+				if err != nil {
+					q = 0
+				}
+				quality = q
+			}
+
+			if encoding == "*" {
+				wildcard = quality
+				continue
+			}
+
+			qualities[encoding] = quality
+		}
+	}
+
+	var best string
+	var bestQuality float64
+	for _, encoding := range c.encodings {
+		quality, ok := qualities[encoding]
+		if !ok {
+			quality = wildcard
+		}
+
+		if quality > bestQuality {
+			best = encoding
+			bestQuality = quality
+		}
 	}
 
-	return wrapper(c.next)
+	return best
 }
 
 func contains(values []string, val string) bool {
@@ -1,13 +1,16 @@
 package compress
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/andybalholm/brotli"
 	"github.com/klauspost/compress/gzhttp"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
@@ -305,6 +308,236 @@ func TestIntegrationShouldCompress(t *testing.T) {
 	}
 }
 
+func TestNew_encodings(t *testing.T) {
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+	_, err := New(context.Background(), next, dynamic.Compress{Encodings: []string{"GZIP", " br "}}, "testing")
+	assert.NoError(t, err)
+
+	_, err = New(context.Background(), next, dynamic.Compress{Encodings: []string{"deflate"}}, "testing")
+	assert.Error(t, err)
+}
+
+func TestCompress_negotiate(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		encodings      []string
+		acceptEncoding []string
+		expected       string
+	}{
+		{
+			desc: "no Accept-Encoding",
+		},
+		{
+			desc:           "single encoding",
+			acceptEncoding: []string{"gzip"},
+			expected:       gzipName,
+		},
+		{
+			desc:           "order of preference",
+			acceptEncoding: []string{"gzip, deflate, br, zstd"},
+			expected:       zstdName,
+		},
+		{
+			desc:           "configured order of preference",
+			encodings:      []string{"br", "gzip"},
+			acceptEncoding: []string{"gzip, zstd, br"},
+			expected:       brotliName,
+		},
+		{
+			desc:           "quality values",
+			acceptEncoding: []string{"zstd;q=0.5, br;q=0.8", "gzip;q=0.9"},
+			expected:       gzipName,
+		},
+		{
+			desc:           "refused encodings",
+			acceptEncoding: []string{"zstd;q=0, br;q=0, gzip"},
+			expected:       gzipName,
+		},
+		{
+			desc:           "wildcard",
+			acceptEncoding: []string{"zstd;q=0, *"},
+			expected:       brotliName,
+		},
+		{
+			desc:           "unsupported encodings",
+			acceptEncoding: []string{"deflate, identity"},
+		},
+		{
+			desc:           "all refused",
+			acceptEncoding: []string{"*;q=0"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+			handler, err := New(context.Background(), next, dynamic.Compress{Encodings: test.encodings}, "testing")
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, handler.(*compress).negotiate(test.acceptEncoding))
+		})
+	}
+}
+
+func TestCompress_encodings(t *testing.T) {
+	baseBody := generateBytes(gzhttp.DefaultMinSize)
+
+	testCases := []struct {
+		desc             string
+		conf             dynamic.Compress
+		encoding         string
+		header           http.Header
+		body             []byte
+		expectedEncoding string
+	}{
+		{
+			desc:             "brotli",
+			encoding:         brotliName,
+			body:             baseBody,
+			expectedEncoding: brotliName,
+		},
+		{
+			desc:             "zstd",
+			encoding:         zstdName,
+			body:             baseBody,
+			expectedEncoding: zstdName,
+		},
+		{
+			desc:     "body smaller than the minimum size",
+			encoding: zstdName,
+			body:     baseBody[:100],
+		},
+		{
+			desc:             "configured minimum size",
+			conf:             dynamic.Compress{MinResponseBodyBytes: 100},
+			encoding:         zstdName,
+			body:             baseBody[:100],
+			expectedEncoding: zstdName,
+		},
+		{
+			desc:     "Content-Length smaller than the minimum size",
+			conf:     dynamic.Compress{MinResponseBodyBytes: 100},
+			encoding: brotliName,
+			header:   http.Header{"Content-Length": {"10"}},
+			body:     baseBody[:10],
+		},
+		{
+			desc:     "excluded content type",
+			conf:     dynamic.Compress{ExcludedContentTypes: []string{"text/event-stream"}},
+			encoding: brotliName,
+			header:   http.Header{"Content-Type": {"text/event-stream; charset=utf-8"}},
+			body:     baseBody,
+		},
+		{
+			desc:             "already encoded",
+			encoding:         brotliName,
+			header:           http.Header{"Content-Encoding": {"foo"}},
+			body:             baseBody,
+			expectedEncoding: "foo",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				for name, values := range test.header {
+					rw.Header()[name] = values
+				}
+
+				rw.WriteHeader(http.StatusCreated)
+
+				// The body is written in two parts.
+				_, err := rw.Write(test.body[:len(test.body)/2])
+				require.NoError(t, err)
+				_, err = rw.Write(test.body[len(test.body)/2:])
+				require.NoError(t, err)
+			})
+
+			handler, err := New(context.Background(), next, test.conf, "testing")
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+			req.Header.Set(acceptEncodingHeader, test.encoding)
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, http.StatusCreated, rw.Code)
+			assert.Equal(t, test.expectedEncoding, rw.Header().Get(contentEncodingHeader))
+
+			body := rw.Body.Bytes()
+			switch test.expectedEncoding {
+			case brotliName:
+				assert.Equal(t, acceptEncodingHeader, rw.Header().Get(varyHeader))
+				assert.Equal(t, http.DetectContentType(test.body), rw.Header().Get(contentTypeHeader))
+
+				body, err = io.ReadAll(brotli.NewReader(bytes.NewReader(body)))
+				require.NoError(t, err)
+
+			case zstdName:
+				assert.Equal(t, acceptEncodingHeader, rw.Header().Get(varyHeader))
+				assert.Equal(t, http.DetectContentType(test.body), rw.Header().Get(contentTypeHeader))
+
+				decoder, err := zstd.NewReader(bytes.NewReader(body))
+				require.NoError(t, err)
+				defer decoder.Close()
+
+				body, err = io.ReadAll(decoder)
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, test.body, body)
+		})
+	}
+}
+
+func TestCompress_flush(t *testing.T) {
+	written := make(chan struct{})
+	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set(contentTypeHeader, "text/event-stream")
+
+		_, err := rw.Write([]byte("data: foo\n\n"))
+		require.NoError(t, err)
+		rw.(http.Flusher).Flush()
+
+		<-written
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Compress{}, "testing")
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	req := testhelpers.MustNewRequest(http.MethodGet, ts.URL, nil)
+	req.Header.Set(acceptEncodingHeader, zstdName)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, zstdName, resp.Header.Get(contentEncodingHeader))
+
+	decoder, err := zstd.NewReader(resp.Body)
+	require.NoError(t, err)
+	defer decoder.Close()
+
+	// The flushed data is received while the response is still in progress.
+	data := make([]byte, len("data: foo\n\n"))
+	_, err = io.ReadFull(decoder, data)
+	require.NoError(t, err)
+	assert.Equal(t, "data: foo\n\n", string(data))
+
+	close(written)
+}
+
 func BenchmarkCompress(b *testing.B) {
 	testCases := []struct {
 		name     string
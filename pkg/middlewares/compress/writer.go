@@ -0,0 +1,231 @@
+package compress
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// encoder compresses a response body.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// The encoders are pooled, as they allocate large buffers.
+var encoderPools = map[string]*sync.Pool{
+	brotliName: {
+		New: func() interface{} {
+			return brotli.NewWriterLevel(nil, brotli.DefaultCompression)
+		},
+	},
+	zstdName: {
+		New: func() interface{} {
+			// The options are valid, so no error can happen.
+			enc, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1), zstd.WithEncoderLevel(zstd.SpeedDefault))
+			return enc
+		},
+	},
+}
+
+// responseWriter compresses the response body with the encoding,
+// unless the response is already encoded, its content type is excluded, or its body is smaller than the minimum size.
+// The body is buffered until it reaches the minimum size, unless it is flushed.
+type responseWriter struct {
+	rw       http.ResponseWriter
+	encoding string
+	minSize  int
+	excludes []string
+
+	statusCode int
+	buf        []byte
+	// passthrough is true once the response is sent without being compressed.
+	passthrough bool
+	enc         encoder
+}
+
+func newResponseWriter(rw http.ResponseWriter, encoding string, minSize int, excludes []string) *responseWriter {
+	return &responseWriter{
+		rw:       rw,
+		encoding: encoding,
+		minSize:  minSize,
+		excludes: excludes,
+	}
+}
+
+func (r *responseWriter) Header() http.Header {
+	return r.rw.Header()
+}
+
+func (r *responseWriter) WriteHeader(statusCode int) {
+	if r.statusCode != 0 {
+		return
+	}
+
+	r.statusCode = statusCode
+}
+
+func (r *responseWriter) Write(p []byte) (int, error) {
+	if r.statusCode == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if !r.passthrough && r.enc == nil && len(r.buf) == 0 && !r.canCompress() {
+		r.passthrough = true
+		r.rw.WriteHeader(r.statusCode)
+	}
+
+	if r.passthrough {
+		return r.rw.Write(p)
+	}
+
+	if r.enc != nil {
+		return r.enc.Write(p)
+	}
+
+	r.buf = append(r.buf, p...)
+	if len(r.buf) < r.minSize {
+		return len(p), nil
+	}
+
+	if err := r.writeBuffer(true); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Flush sends the buffered data, compressing it whatever its size, and flushes the encoder.
+func (r *responseWriter) Flush() {
+	if r.statusCode == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if !r.passthrough && r.enc == nil {
+		if err := r.writeBuffer(r.canCompress()); err != nil {
+			return
+		}
+	}
+
+	if r.enc != nil {
+		if err := r.enc.Flush(); err != nil {
+			return
+		}
+	}
+
+	if flusher, ok := r.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.rw.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", r.rw)
+	}
+
+	r.passthrough = true
+
+	return hijacker.Hijack()
+}
+
+// close sends the buffered data, if any, and closes the encoder.
+func (r *responseWriter) close() error {
+	if r.passthrough {
+		return nil
+	}
+
+	if r.enc != nil {
+		err := r.enc.Close()
+
+		r.enc.Reset(nil)
+		encoderPools[r.encoding].Put(r.enc)
+		r.enc = nil
+
+		return err
+	}
+
+	// Nothing was written, the response is left to the server.
+	if r.statusCode == 0 {
+		return nil
+	}
+
+	return r.writeBuffer(false)
+}
+
+// canCompress reports whether the response can be compressed, according to its headers.
+func (r *responseWriter) canCompress() bool {
+	header := r.rw.Header()
+
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+
+	if contentLength := header.Get("Content-Length"); contentLength != "" {
+		length, err := strconv.Atoi(contentLength)
+		if err == nil && length < r.minSize {
+			return false
+		}
+	}
+
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err != nil || !contains(r.excludes, mediaType)
+}
+
+// writeBuffer sends the headers and the buffered data, compressed if asked and if its content type is not excluded.
+func (r *responseWriter) writeBuffer(compress bool) error {
+	header := r.rw.Header()
+
+	if compress {
+		// The content type is detected before the compression, as it could not be once the body is compressed.
+		if header.Get("Content-Type") == "" {
+			header.Set("Content-Type", http.DetectContentType(r.buf))
+		}
+
+		compress = r.canCompress()
+	}
+
+	buf := r.buf
+	r.buf = nil
+
+	if !compress {
+		r.passthrough = true
+		r.rw.WriteHeader(r.statusCode)
+
+		if len(buf) == 0 {
+			return nil
+		}
+
+		_, err := r.rw.Write(buf)
+		return err
+	}
+
+	header.Set("Content-Encoding", r.encoding)
+	if !contains(header.Values("Vary"), "Accept-Encoding") {
+		header.Add("Vary", "Accept-Encoding")
+	}
+	header.Del("Content-Length")
+	header.Del("Accept-Ranges")
+
+	r.rw.WriteHeader(r.statusCode)
+
+	r.enc = encoderPools[r.encoding].Get().(encoder)
+	r.enc.Reset(r.rw)
+
+	_, err := r.enc.Write(buf)
+	return err
+}
@@ -102,6 +102,7 @@ func (c *customErrors) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		if len(c.backendQuery) > 0 {
 			query = "/" + strings.TrimPrefix(c.backendQuery, "/")
 			query = strings.ReplaceAll(query, "{status}", strconv.Itoa(code))
+			query = strings.ReplaceAll(query, "{url}", url.QueryEscape(req.URL.String()))
 		}
 
 		pageReq, err := newRequest(backendURL + query)
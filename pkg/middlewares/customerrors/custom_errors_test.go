@@ -106,6 +106,23 @@ func TestHandler(t *testing.T) {
 				assert.NotContains(t, recorder.Body.String(), "oops", "Should not return the oops page")
 			},
 		},
+		{
+			desc:        "query replacement with the original URL",
+			errorPage:   &dynamic.ErrorPage{Service: "error", Query: "/{status}?url={url}", Status: []string{"503"}},
+			backendCode: http.StatusServiceUnavailable,
+			backendErrorHandler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/503" && r.URL.Query().Get("url") == "http://localhost/test" {
+					fmt.Fprintln(w, "My 503 page.")
+				} else {
+					fmt.Fprintln(w, "Failed")
+				}
+			}),
+			validate: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				t.Helper()
+				assert.Equal(t, http.StatusServiceUnavailable, recorder.Code, "HTTP status")
+				assert.Contains(t, recorder.Body.String(), "My 503 page.")
+			},
+		},
 		{
 			desc:        "Single code",
 			errorPage:   &dynamic.ErrorPage{Service: "error", Query: "/{status}", Status: []string{"503"}},
@@ -0,0 +1,184 @@
+package decisionlog
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/containous/alice"
+	"github.com/sirupsen/logrus"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/rules"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+// The reasons for which the routing decision of a request is logged.
+const (
+	reasonSampled = "sampled"
+	reasonToken   = "token"
+)
+
+type noopCloser struct {
+	*os.File
+}
+
+func (n noopCloser) Close() error {
+	return nil
+}
+
+// Handler records the routing decision of the sampled requests, and of the requests carrying the debug token,
+// i.e. the HTTP routers evaluated in order, with their rule, their priority, and whether they matched,
+// and writes it to the decision log.
+type Handler struct {
+	config *types.DecisionLog
+	logger *logrus.Logger
+	file   io.WriteCloser
+	mu     sync.Mutex
+
+	// random returns a pseudo-random number in [0.0,1.0), it is replaced in tests.
+	random func() float64
+}
+
+// WrapHandler wraps the decision log handler into an Alice Constructor.
+func WrapHandler(handler *Handler, entryPointName string) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			handler.ServeHTTP(rw, req, entryPointName, next)
+		}), nil
+	}
+}
+
+// NewHandler creates a new Handler.
+func NewHandler(config *types.DecisionLog) (*Handler, error) {
+	if config.SampleRate < 0 || config.SampleRate > 1 {
+		return nil, fmt.Errorf("invalid sample rate %v, it must be between 0 and 1", config.SampleRate)
+	}
+
+	if config.Token != "" && config.TokenHeader == "" {
+		return nil, errors.New("the token header is missing")
+	}
+
+	var file io.WriteCloser = noopCloser{os.Stdout}
+	if config.FilePath != "" {
+		f, err := openFile(config.FilePath)
+		if err != nil {
+			return nil, fmt.Errorf("error opening decision log file: %w", err)
+		}
+		file = f
+	}
+
+	return &Handler{
+		config: config,
+		logger: &logrus.Logger{
+			Out:       file,
+			Formatter: new(logrus.JSONFormatter),
+			Hooks:     make(logrus.LevelHooks),
+			Level:     logrus.InfoLevel,
+		},
+		file:   file,
+		random: rand.Float64,
+	}, nil
+}
+
+func openFile(filePath string) (*os.File, error) {
+	dir := filepath.Dir(filePath)
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log path %s: %w", dir, err)
+	}
+
+	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o664)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file %s: %w", filePath, err)
+	}
+
+	return file, nil
+}
+
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request, entryPointName string, next http.Handler) {
+	reason := h.reason(req)
+
+	// The debug token is not forwarded to the services.
+	if h.config.Token != "" {
+		req.Header.Del(h.config.TokenHeader)
+	}
+
+	if reason == "" {
+		next.ServeHTTP(rw, req)
+		return
+	}
+
+	decision := &rules.Decision{}
+	next.ServeHTTP(rw, rules.WithDecision(req, decision))
+
+	fields := logrus.Fields{
+		"entryPointName": entryPointName,
+		"RequestMethod":  req.Method,
+		"RequestHost":    req.Host,
+		"RequestPath":    req.URL.RequestURI(),
+		"reason":         reason,
+		"routers":        decision.Routes,
+	}
+
+	if name, ok := decision.Matched(); ok {
+		fields["matchedRouter"] = name
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.logger.WithFields(fields).Info("Routing decision")
+}
+
+// reason returns why the routing decision of the request is logged, or an empty string if it is not.
+func (h *Handler) reason(req *http.Request) string {
+	if h.config.Token != "" {
+		token := req.Header.Get(h.config.TokenHeader)
+		if token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(h.config.Token)) == 1 {
+			return reasonToken
+		}
+	}
+
+	if h.config.SampleRate > 0 && h.random() < h.config.SampleRate {
+		return reasonSampled
+	}
+
+	return ""
+}
+
+// Close closes the decision log file.
+func (h *Handler) Close() error {
+	return h.file.Close()
+}
+
+// Rotate closes and reopens the log file to allow for rotation by an external source.
+func (h *Handler) Rotate() error {
+	if h.config.FilePath == "" {
+		return nil
+	}
+
+	file, err := os.OpenFile(h.config.FilePath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o664)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.file != nil {
+		if err := h.file.Close(); err != nil {
+			log.WithoutContext().Errorf("Error closing the decision log file: %v", err)
+		}
+	}
+
+	h.file = file
+	h.logger.Out = file
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package decisionlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/rules"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestNewHandler(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      types.DecisionLog
+		expectedErr bool
+	}{
+		{
+			desc:   "valid configuration",
+			config: types.DecisionLog{SampleRate: 0.5, TokenHeader: "X-Debug", Token: "secret"},
+		},
+		{
+			desc:        "negative sample rate",
+			config:      types.DecisionLog{SampleRate: -1},
+			expectedErr: true,
+		},
+		{
+			desc:        "sample rate greater than 1",
+			config:      types.DecisionLog{SampleRate: 1.5},
+			expectedErr: true,
+		},
+		{
+			desc:        "token without header",
+			config:      types.DecisionLog{Token: "secret"},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewHandler(&test.config)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		sampleRate     float64
+		random         float64
+		token          string
+		expectedReason string
+	}{
+		{
+			desc:           "sampled request",
+			sampleRate:     0.1,
+			random:         0.05,
+			expectedReason: "sampled",
+		},
+		{
+			desc:       "request not sampled",
+			sampleRate: 0.1,
+			random:     0.5,
+		},
+		{
+			desc:           "request with the debug token",
+			random:         0.5,
+			token:          "secret",
+			expectedReason: "token",
+		},
+		{
+			desc:   "request with an invalid debug token",
+			random: 0.5,
+			token:  "foobar",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			logFilePath := filepath.Join(t.TempDir(), "decision.log")
+
+			config := &types.DecisionLog{FilePath: logFilePath, SampleRate: test.sampleRate, Token: "secret"}
+			config.SetDefaults()
+
+			handler, err := NewHandler(config)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = handler.Close() })
+
+			handler.random = func() float64 { return test.random }
+
+			router, err := rules.NewRouter()
+			require.NoError(t, err)
+
+			var forwardedToken string
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				forwardedToken = req.Header.Get("X-Traefik-Debug")
+			})
+
+			require.NoError(t, router.AddNamedRoute("foo@file", "PathPrefix(`/foo`)", 0, next))
+			require.NoError(t, router.AddNamedRoute("bar@file", "PathPrefix(`/`)", 0, next))
+			router.SortRoutes()
+
+			chain, err := WrapHandler(handler, "web")(router)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost/bar", nil)
+			if test.token != "" {
+				req.Header.Set("X-Traefik-Debug", test.token)
+			}
+
+			chain.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Empty(t, forwardedToken)
+
+			file, err := os.Open(logFilePath)
+			require.NoError(t, err)
+			defer func() { _ = file.Close() }()
+
+			var entries []map[string]interface{}
+			scanner := bufio.NewScanner(file)
+			for scanner.Scan() {
+				var entry map[string]interface{}
+				require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+				entries = append(entries, entry)
+			}
+			require.NoError(t, scanner.Err())
+
+			if test.expectedReason == "" {
+				assert.Empty(t, entries)
+				return
+			}
+
+			require.Len(t, entries, 1)
+			assert.Equal(t, test.expectedReason, entries[0]["reason"])
+			assert.Equal(t, "web", entries[0]["entryPointName"])
+			assert.Equal(t, "/bar", entries[0]["RequestPath"])
+			assert.Equal(t, "bar@file", entries[0]["matchedRouter"])
+			assert.Equal(t, []interface{}{
+				map[string]interface{}{"name": "foo@file", "rule": "PathPrefix(`/foo`)", "priority": float64(18), "matched": false},
+				map[string]interface{}{"name": "bar@file", "rule": "PathPrefix(`/`)", "priority": float64(15), "matched": true},
+			}, entries[0]["routers"])
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package fairqueuing
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+var errQueueFull = errors.New("fair queue is full")
+
+// FairQueuing limits the number of requests handled concurrently,
+// sharing the slots fairly across the source IPs:
+// when all the slots are taken, the requests wait in a queue per source IP,
+// and each freed slot is given to the waiting source IP with the fewest requests in progress.
+type FairQueuing struct {
+	next http.Handler
+
+	maxConcurrent int64
+	maxQueued     int64
+
+	mu sync.Mutex
+	// active is the number of requests in progress.
+	active int64
+	// activeBySource is the number of requests in progress by source IP.
+	activeBySource map[string]int64
+	// queued is the number of waiting requests.
+	queued int64
+	// waiting holds the waiting requests of each source IP, in arrival order.
+	waiting map[string][]chan struct{}
+	// sources holds the source IPs with waiting requests, in the order they are served.
+	sources []string
+}
+
+// New creates a FairQueuing handler.
+func New(next http.Handler, config static.FairQueuing) *FairQueuing {
+	maxConcurrent := config.MaxConcurrentRequests
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+
+	return &FairQueuing{
+		next:           next,
+		maxConcurrent:  maxConcurrent,
+		maxQueued:      config.MaxQueuedRequests,
+		activeBySource: make(map[string]int64),
+		waiting:        make(map[string][]chan struct{}),
+	}
+}
+
+func (f *FairQueuing) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	source, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		source = req.RemoteAddr
+	}
+
+	if err := f.acquire(req.Context(), source); err != nil {
+		if errors.Is(err, errQueueFull) {
+			log.FromContext(req.Context()).Debugf("Rejecting request from %s: %v", source, err)
+			http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		}
+		return
+	}
+	defer f.release(source)
+
+	f.next.ServeHTTP(rw, req)
+}
+
+// acquire waits for a slot for the given source, until the context is done.
+func (f *FairQueuing) acquire(ctx context.Context, source string) error {
+	f.mu.Lock()
+
+	if f.active < f.maxConcurrent && f.queued == 0 {
+		f.active++
+		f.activeBySource[source]++
+		f.mu.Unlock()
+		return nil
+	}
+
+	if f.maxQueued > 0 && f.queued >= f.maxQueued {
+		f.mu.Unlock()
+		return errQueueFull
+	}
+
+	ready := make(chan struct{})
+	if len(f.waiting[source]) == 0 {
+		f.sources = append(f.sources, source)
+	}
+	f.waiting[source] = append(f.waiting[source], ready)
+	f.queued++
+
+	f.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		if !f.removeWaiting(source, ready) {
+			// The slot has been given in the meantime.
+			f.releaseLocked(source)
+		}
+
+		return ctx.Err()
+	}
+}
+
+func (f *FairQueuing) release(source string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.releaseLocked(source)
+}
+
+func (f *FairQueuing) releaseLocked(source string) {
+	f.active--
+	f.activeBySource[source]--
+	if f.activeBySource[source] <= 0 {
+		delete(f.activeBySource, source)
+	}
+
+	for f.active < f.maxConcurrent && f.queued > 0 {
+		f.dispatch()
+	}
+}
+
+// dispatch gives a slot to the first request of the waiting source IP with the fewest requests in progress.
+func (f *FairQueuing) dispatch() {
+	index := 0
+	for i, source := range f.sources {
+		if f.activeBySource[source] < f.activeBySource[f.sources[index]] {
+			index = i
+		}
+	}
+
+	source := f.sources[index]
+	ready := f.waiting[source][0]
+
+	f.waiting[source] = f.waiting[source][1:]
+	f.sources = append(f.sources[:index], f.sources[index+1:]...)
+	if len(f.waiting[source]) == 0 {
+		delete(f.waiting, source)
+	} else {
+		// The source goes back to the end of the line, for the next slots.
+		f.sources = append(f.sources, source)
+	}
+
+	f.queued--
+	f.active++
+	f.activeBySource[source]++
+
+	close(ready)
+}
+
+// removeWaiting removes the given waiting request, and reports whether it was still waiting.
+func (f *FairQueuing) removeWaiting(source string, ready chan struct{}) bool {
+	waiting := f.waiting[source]
+	for i, w := range waiting {
+		if w != ready {
+			continue
+		}
+
+		f.waiting[source] = append(waiting[:i], waiting[i+1:]...)
+		f.queued--
+
+		if len(f.waiting[source]) == 0 {
+			delete(f.waiting, source)
+			for j, s := range f.sources {
+				if s == source {
+					f.sources = append(f.sources[:j], f.sources[j+1:]...)
+					break
+				}
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
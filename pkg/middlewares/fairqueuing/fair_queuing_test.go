@@ -0,0 +1,115 @@
+package fairqueuing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+func TestFairQueuing_sharesSlotsAcrossSources(t *testing.T) {
+	fq := New(http.NotFoundHandler(), static.FairQueuing{MaxConcurrentRequests: 1})
+
+	err := fq.acquire(context.Background(), "10.0.0.1")
+	require.NoError(t, err)
+
+	served := make(chan string, 4)
+	wait := func(source string) {
+		go func() {
+			assert.NoError(t, fq.acquire(context.Background(), source))
+			served <- source
+		}()
+	}
+
+	// The aggressive source queues its requests first.
+	for i := 0; i < 3; i++ {
+		wait("10.0.0.1")
+		waitQueued(t, fq, int64(i+1))
+	}
+	wait("10.0.0.2")
+	waitQueued(t, fq, 4)
+
+	var order []string
+	current := "10.0.0.1"
+	for i := 0; i < 4; i++ {
+		fq.release(current)
+		current = <-served
+		order = append(order, current)
+	}
+
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2", "10.0.0.1", "10.0.0.1"}, order)
+}
+
+func TestFairQueuing_queueFull(t *testing.T) {
+	release := make(chan struct{})
+	next := http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		<-release
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	fq := New(next, static.FairQueuing{MaxConcurrentRequests: 1, MaxQueuedRequests: 1})
+
+	codes := make(chan int, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			rw := httptest.NewRecorder()
+			fq.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://foo.bar/", nil))
+			codes <- rw.Code
+		}()
+	}
+	waitQueued(t, fq, 1)
+
+	rw := httptest.NewRecorder()
+	fq.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://foo.bar/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rw.Code)
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-codes)
+	assert.Equal(t, http.StatusOK, <-codes)
+}
+
+func TestFairQueuing_canceledRequest(t *testing.T) {
+	fq := New(http.NotFoundHandler(), static.FairQueuing{MaxConcurrentRequests: 1})
+
+	err := fq.acquire(context.Background(), "10.0.0.1")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error)
+	go func() {
+		errCh <- fq.acquire(ctx, "10.0.0.2")
+	}()
+	waitQueued(t, fq, 1)
+
+	cancel()
+	assert.ErrorIs(t, <-errCh, context.Canceled)
+
+	fq.mu.Lock()
+	assert.Equal(t, int64(0), fq.queued)
+	assert.Empty(t, fq.waiting)
+	assert.Empty(t, fq.sources)
+	fq.mu.Unlock()
+
+	fq.release("10.0.0.1")
+
+	fq.mu.Lock()
+	assert.Equal(t, int64(0), fq.active)
+	assert.Empty(t, fq.activeBySource)
+	fq.mu.Unlock()
+}
+
+func waitQueued(t *testing.T, fq *FairQueuing, expected int64) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		fq.mu.Lock()
+		defer fq.mu.Unlock()
+
+		return fq.queued == expected
+	}, time.Second, time.Millisecond)
+}
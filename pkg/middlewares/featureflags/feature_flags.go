@@ -0,0 +1,245 @@
+package featureflags
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+	"golang.org/x/net/http/httpguts"
+)
+
+const (
+	typeName = "FeatureFlags"
+
+	// evaluationPath is the path of the bulk evaluation endpoint of the OpenFeature Remote Evaluation Protocol.
+	evaluationPath = "/ofrep/v1/evaluate/flags"
+)
+
+// evaluationRequest is the body of an OFREP bulk evaluation request.
+type evaluationRequest struct {
+	Context map[string]string `json:"context"`
+}
+
+// evaluationResponse is the body of an OFREP bulk evaluation response.
+type evaluationResponse struct {
+	Flags []flagEvaluation `json:"flags"`
+}
+
+type flagEvaluation struct {
+	Key       string          `json:"key"`
+	Value     json.RawMessage `json:"value"`
+	ErrorCode string          `json:"errorCode"`
+}
+
+type featureFlags struct {
+	next               http.Handler
+	name               string
+	endpoint           string
+	flags              map[string]struct{}
+	headerPrefix       string
+	targetingKeyHeader string
+	contextHeaders     []string
+	headers            map[string]string
+	timeout            time.Duration
+	client             *http.Client
+}
+
+// New creates a feature flags middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.FeatureFlags, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if config.Endpoint == "" {
+		return nil, errors.New("the endpoint of the feature flags provider is missing")
+	}
+
+	if config.HeaderPrefix == "" {
+		return nil, errors.New("the header prefix is missing")
+	}
+
+	f := &featureFlags{
+		next:               next,
+		name:               name,
+		endpoint:           strings.TrimSuffix(config.Endpoint, "/") + evaluationPath,
+		headerPrefix:       http.CanonicalHeaderKey(config.HeaderPrefix),
+		targetingKeyHeader: config.TargetingKeyHeader,
+		contextHeaders:     config.ContextHeaders,
+		headers:            config.Headers,
+		timeout:            time.Duration(config.Timeout),
+		client:             &http.Client{},
+	}
+
+	if len(config.Flags) > 0 {
+		f.flags = make(map[string]struct{})
+		for _, flag := range config.Flags {
+			f.flags[flag] = struct{}{}
+		}
+	}
+
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.CreateTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		tr := http.DefaultTransport.(*http.Transport).Clone()
+		tr.TLSClientConfig = tlsConfig
+		f.client.Transport = tr
+	}
+
+	return f, nil
+}
+
+func (f *featureFlags) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return f.name, ext.SpanKindRPCClientEnum
+}
+
+func (f *featureFlags) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), f.name, typeName))
+
+	// The headers with the results must only come from the evaluation.
+	for name := range req.Header {
+		if strings.HasPrefix(name, f.headerPrefix) {
+			req.Header.Del(name)
+		}
+	}
+
+	flags, err := f.evaluate(req)
+	if err != nil {
+		// The request is forwarded without the results,
+		// so that the backends fall back to their default behavior when the provider is unavailable.
+		logMessage := fmt.Sprintf("Error evaluating feature flags with %s: %v", f.endpoint, err)
+		logger.Debug(logMessage)
+		tracing.SetErrorWithEvent(req, logMessage)
+
+		f.next.ServeHTTP(rw, req)
+		return
+	}
+
+	for _, flag := range flags {
+		if f.flags != nil {
+			if _, ok := f.flags[flag.Key]; !ok {
+				continue
+			}
+		}
+
+		if flag.ErrorCode != "" {
+			logger.Debugf("Error evaluating feature flag %s: %s", flag.Key, flag.ErrorCode)
+			continue
+		}
+
+		header := f.headerPrefix + flag.Key
+		if !httpguts.ValidHeaderFieldName(header) {
+			logger.Debugf("Feature flag %s ignored: %s is not a valid header name", flag.Key, header)
+			continue
+		}
+
+		value := headerValue(flag.Value)
+		if !httpguts.ValidHeaderFieldValue(value) {
+			logger.Debugf("Feature flag %s ignored: its value is not a valid header value", flag.Key)
+			continue
+		}
+
+		req.Header.Set(header, value)
+	}
+
+	f.next.ServeHTTP(rw, req)
+}
+
+// evaluate evaluates the feature flags with the attributes of the request.
+func (f *featureFlags) evaluate(req *http.Request) ([]flagEvaluation, error) {
+	body, err := json.Marshal(evaluationRequest{Context: f.evaluationContext(req)})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := req.Context()
+	if f.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, f.timeout)
+		defer cancel()
+	}
+
+	evalReq, err := http.NewRequestWithContext(ctx, http.MethodPost, f.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	evalReq.Header.Set("Content-Type", "application/json")
+	for name, value := range f.headers {
+		evalReq.Header.Set(name, value)
+	}
+
+	resp, err := f.client.Do(evalReq)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var evaluation evaluationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&evaluation); err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+
+	return evaluation.Flags, nil
+}
+
+// evaluationContext returns the attributes of the request the feature flags are evaluated with.
+func (f *featureFlags) evaluationContext(req *http.Request) map[string]string {
+	clientIP, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		clientIP = req.RemoteAddr
+	}
+
+	evalCtx := map[string]string{
+		"targetingKey": clientIP,
+		"host":         req.Host,
+		"method":       req.Method,
+		"path":         req.URL.Path,
+		"clientIP":     clientIP,
+	}
+
+	if f.targetingKeyHeader != "" {
+		if key := req.Header.Get(f.targetingKeyHeader); key != "" {
+			evalCtx["targetingKey"] = key
+		}
+	}
+
+	for _, name := range f.contextHeaders {
+		if value := req.Header.Get(name); value != "" {
+			evalCtx[name] = value
+		}
+	}
+
+	return evalCtx
+}
+
+// headerValue returns the header value of a flag value:
+// strings are used as is, and the other values as compact JSON.
+func headerValue(value json.RawMessage) string {
+	var str string
+	if err := json.Unmarshal(value, &str); err == nil {
+		return str
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, value); err != nil {
+		return string(value)
+	}
+
+	return buf.String()
+}
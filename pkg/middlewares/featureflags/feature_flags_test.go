@@ -0,0 +1,154 @@
+package featureflags
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestFeatureFlags(t *testing.T) {
+	var evalReq evaluationRequest
+	var authorization string
+	provider := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost || req.URL.Path != "/ofrep/v1/evaluate/flags" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		authorization = req.Header.Get("Authorization")
+
+		if err := json.NewDecoder(req.Body).Decode(&evalReq); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		_, _ = rw.Write([]byte(`{"flags": [
+			{"key": "new-checkout", "value": true, "reason": "TARGETING_MATCH", "variant": "on"},
+			{"key": "theme", "value": "dark", "reason": "STATIC"},
+			{"key": "limits", "value": {"max": 10}, "reason": "STATIC"},
+			{"key": "broken", "errorCode": "PARSE_ERROR"},
+			{"key": "unlisted", "value": 1}
+		]}`))
+	}))
+	t.Cleanup(provider.Close)
+
+	config := dynamic.FeatureFlags{
+		Endpoint:           provider.URL + "/",
+		Flags:              []string{"new-checkout", "theme", "limits", "broken"},
+		TargetingKeyHeader: "X-User-Id",
+		ContextHeaders:     []string{"X-Country"},
+		Headers:            map[string]string{"Authorization": "Bearer token"},
+	}
+	config.SetDefaults()
+
+	var forwarded http.Header
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		forwarded = req.Header.Clone()
+	})
+
+	handler, err := New(context.Background(), next, config, "test")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/cart", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-User-Id", "user-1")
+	req.Header.Set("X-Country", "FR")
+	req.Header.Set("X-Feature-Spoofed", "true")
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "Bearer token", authorization)
+	assert.Equal(t, map[string]string{
+		"targetingKey": "user-1",
+		"host":         "example.com",
+		"method":       http.MethodGet,
+		"path":         "/cart",
+		"clientIP":     "10.0.0.1",
+		"X-Country":    "FR",
+	}, evalReq.Context)
+
+	assert.Equal(t, "true", forwarded.Get("X-Feature-New-Checkout"))
+	assert.Equal(t, "dark", forwarded.Get("X-Feature-Theme"))
+	assert.Equal(t, `{"max":10}`, forwarded.Get("X-Feature-Limits"))
+	assert.Empty(t, forwarded.Values("X-Feature-Broken"))
+	assert.Empty(t, forwarded.Values("X-Feature-Unlisted"))
+	assert.Empty(t, forwarded.Values("X-Feature-Spoofed"))
+}
+
+func TestFeatureFlags_providerUnavailable(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		handler http.HandlerFunc
+	}{
+		{
+			desc: "error status",
+			handler: func(rw http.ResponseWriter, _ *http.Request) {
+				rw.WriteHeader(http.StatusInternalServerError)
+			},
+		},
+		{
+			desc: "invalid response",
+			handler: func(rw http.ResponseWriter, _ *http.Request) {
+				_, _ = rw.Write([]byte(`{"flags":`))
+			},
+		},
+		{
+			desc: "timeout",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				select {
+				case <-req.Context().Done():
+				case <-time.After(time.Second):
+				}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			provider := httptest.NewServer(test.handler)
+			t.Cleanup(provider.Close)
+
+			config := dynamic.FeatureFlags{Endpoint: provider.URL}
+			config.SetDefaults()
+			config.Timeout = ptypes.Duration(50 * time.Millisecond)
+
+			var forwarded http.Header
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				forwarded = req.Header.Clone()
+				rw.WriteHeader(http.StatusOK)
+			})
+
+			handler, err := New(context.Background(), next, config, "test")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+			req.Header.Set("X-Feature-Spoofed", "true")
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			require.NotNil(t, forwarded)
+			assert.Empty(t, forwarded.Values("X-Feature-Spoofed"))
+		})
+	}
+}
+
+func TestNew_missingEndpoint(t *testing.T) {
+	config := dynamic.FeatureFlags{}
+	config.SetDefaults()
+
+	_, err := New(context.Background(), http.NotFoundHandler(), config, "test")
+	assert.Error(t, err)
+}
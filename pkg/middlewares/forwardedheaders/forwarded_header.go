@@ -178,11 +178,43 @@ func (x *XForwarded) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	r = r.WithContext(ip.WithClientIP(r.Context(), x.clientIP(r)))
+
 	x.rewrite(r)
 
 	x.next.ServeHTTP(w, r)
 }
 
+// clientIP returns the IP of the client which sent the request, through the trusted proxies if any:
+// the X-Forwarded-For IPs, from right to left, are skipped as long as they are trusted,
+// and the remote address is returned when the forwarded headers are not trusted.
+func (x *XForwarded) clientIP(req *http.Request) string {
+	clientIP, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		clientIP = req.RemoteAddr
+	}
+	clientIP = removeIPv6Zone(clientIP)
+
+	if !x.insecure && !x.isTrustedIP(req.RemoteAddr) {
+		return clientIP
+	}
+
+	xffs := strings.Split(strings.Join(req.Header.Values(xForwardedFor), ","), ",")
+	for i := len(xffs) - 1; i >= 0; i-- {
+		xff := strings.TrimSpace(xffs[i])
+		if xff == "" {
+			continue
+		}
+
+		clientIP = removeIPv6Zone(xff)
+		if !x.insecure && !x.isTrustedIP(clientIP) {
+			break
+		}
+	}
+
+	return clientIP
+}
+
 // unsafeHeader allows to manage Header values.
 // Must be used only when the header name is already a canonical key.
 type unsafeHeader map[string][]string
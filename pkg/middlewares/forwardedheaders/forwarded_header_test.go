@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/ip"
 )
 
 func TestServeHTTP(t *testing.T) {
@@ -301,6 +302,89 @@ func TestServeHTTP(t *testing.T) {
 	}
 }
 
+func TestServeHTTP_clientIP(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		insecure      bool
+		trustedIps    []string
+		remoteAddr    string
+		xForwardedFor []string
+		expected      string
+	}{
+		{
+			desc:          "untrusted remote address",
+			trustedIps:    []string{"10.0.1.100"},
+			remoteAddr:    "10.0.1.101:80",
+			xForwardedFor: []string{"10.0.1.0"},
+			expected:      "10.0.1.101",
+		},
+		{
+			desc:       "trusted remote address without X-Forwarded-For",
+			trustedIps: []string{"10.0.1.100"},
+			remoteAddr: "10.0.1.100:80",
+			expected:   "10.0.1.100",
+		},
+		{
+			desc:          "trusted remote address",
+			trustedIps:    []string{"10.0.1.100"},
+			remoteAddr:    "10.0.1.100:80",
+			xForwardedFor: []string{"10.0.1.0, 10.0.1.12"},
+			expected:      "10.0.1.12",
+		},
+		{
+			desc:          "trusted proxies chain",
+			trustedIps:    []string{"10.0.1.0/24", "2001:db8::/32"},
+			remoteAddr:    "10.0.1.100:80",
+			xForwardedFor: []string{"192.0.2.1, 203.0.113.1", "2001:db8::1"},
+			expected:      "203.0.113.1",
+		},
+		{
+			desc:          "only trusted proxies",
+			trustedIps:    []string{"10.0.1.0/24"},
+			remoteAddr:    "10.0.1.100:80",
+			xForwardedFor: []string{"10.0.1.1, 10.0.1.2"},
+			expected:      "10.0.1.1",
+		},
+		{
+			desc:          "insecure",
+			insecure:      true,
+			remoteAddr:    "10.0.1.100:80",
+			xForwardedFor: []string{"2001:db8::1, 10.0.1.12"},
+			expected:      "2001:db8::1",
+		},
+		{
+			desc:       "IPv6 remote address with zone",
+			remoteAddr: "[fe80::1%eth0]:80",
+			expected:   "fe80::1",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = test.remoteAddr
+			for _, xff := range test.xForwardedFor {
+				req.Header.Add(xForwardedFor, xff)
+			}
+
+			var clientIP string
+			m, err := NewXForwarded(test.insecure, test.trustedIps,
+				http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+					strategy := ip.ClientIPStrategy{}
+					clientIP = strategy.GetIP(req)
+				}))
+			require.NoError(t, err)
+
+			m.ServeHTTP(nil, req)
+
+			assert.Equal(t, test.expected, clientIP)
+		})
+	}
+}
+
 func Test_isWebsocketRequest(t *testing.T) {
 	testCases := []struct {
 		desc             string
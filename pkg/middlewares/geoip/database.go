@@ -0,0 +1,44 @@
+package geoip
+
+import (
+	"io"
+	"net"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/middlewares/sharedfile"
+)
+
+// databaseCheckInterval is the minimum delay between two checks for changes of a database file.
+const databaseCheckInterval = 10 * time.Second
+
+// databases holds the MaxMind DB files, shared by all the middlewares, as a database file can be large.
+var databases = sharedfile.NewRegistry("GeoIP database", databaseCheckInterval, func(r io.Reader) (interface{}, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return newMMDBReader(content)
+})
+
+// database is a MaxMind DB file, reloaded asynchronously when it is modified.
+// The previous content is kept if the file cannot be reloaded.
+type database struct {
+	path string
+	file *sharedfile.Handle
+}
+
+// getDatabase returns the database of the file, which is loaded if it is not already.
+func getDatabase(path string) (*database, error) {
+	file, err := databases.Acquire(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &database{path: path, file: file}, nil
+}
+
+// lookup returns the data recorded for the network of the IP address, or nil if there is none.
+func (d *database) lookup(ip net.IP) (interface{}, error) {
+	return d.file.Get().(*mmdbReader).lookup(ip)
+}
@@ -0,0 +1,249 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/ip"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const typeName = "GeoIP"
+
+// geoRecord is the geolocation of a client IP, merged from the records of all the databases.
+type geoRecord struct {
+	country        string
+	city           string
+	asn            int
+	asOrganization string
+}
+
+type geoIP struct {
+	next             http.Handler
+	name             string
+	databases        []*database
+	strategy         ip.Strategy
+	allowedCountries map[string]struct{}
+	deniedCountries  map[string]struct{}
+	allowedASNs      map[int]struct{}
+	deniedASNs       map[int]struct{}
+	headers          dynamic.GeoIPHeaders
+}
+
+// New creates a GeoIP middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.GeoIP, name string) (http.Handler, error) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
+	logger.Debug("Creating middleware")
+
+	if len(config.DatabaseFiles) == 0 {
+		return nil, errors.New("databaseFiles is empty, GeoIP not created")
+	}
+
+	strategy, err := config.IPStrategy.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	g := &geoIP{
+		next:             next,
+		name:             name,
+		strategy:         strategy,
+		allowedCountries: toCountrySet(config.AllowedCountries),
+		deniedCountries:  toCountrySet(config.DeniedCountries),
+		allowedASNs:      toASNSet(config.AllowedASNs),
+		deniedASNs:       toASNSet(config.DeniedASNs),
+	}
+
+	if config.Headers != nil {
+		g.headers = *config.Headers
+	}
+
+	for _, path := range config.DatabaseFiles {
+		db, err := getDatabase(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load the GeoIP database %s: %w", path, err)
+		}
+
+		g.databases = append(g.databases, db)
+	}
+
+	return g, nil
+}
+
+func (g *geoIP) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return g.name, tracing.SpanKindNoneEnum
+}
+
+func (g *geoIP) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	ctx := middlewares.GetLoggerCtx(req.Context(), g.name, typeName)
+	logger := log.FromContext(ctx)
+
+	clientIP := g.strategy.GetIP(req)
+	record := g.lookup(logger, clientIP)
+
+	if !g.isAllowed(record) {
+		logMessage := fmt.Sprintf("rejecting request from %s (country: %q, ASN: %d)", clientIP, record.country, record.asn)
+		logger.Debug(logMessage)
+		tracing.SetErrorWithEvent(req, logMessage)
+		reject(ctx, rw)
+		return
+	}
+
+	// The headers sent by the client are removed, so they cannot be spoofed.
+	setHeader(req, g.headers.Country, record.country)
+	setHeader(req, g.headers.City, record.city)
+	setHeader(req, g.headers.ASOrganization, record.asOrganization)
+
+	var asn string
+	if record.asn != 0 {
+		asn = strconv.Itoa(record.asn)
+	}
+	setHeader(req, g.headers.ASN, asn)
+
+	g.next.ServeHTTP(rw, req)
+}
+
+// lookup returns the geolocation of the client IP.
+// The unknown fields are left empty, and all of them if the client IP cannot be looked up.
+func (g *geoIP) lookup(logger log.Logger, clientIP string) geoRecord {
+	var record geoRecord
+
+	parsedIP := net.ParseIP(clientIP)
+	if parsedIP == nil {
+		logger.Debugf("Unable to parse the client IP %q", clientIP)
+		return record
+	}
+
+	for _, db := range g.databases {
+		value, err := db.lookup(parsedIP)
+		if err != nil {
+			logger.Errorf("Unable to look up %s in the GeoIP database %s: %v", clientIP, db.path, err)
+			continue
+		}
+
+		record.merge(value)
+	}
+
+	return record
+}
+
+func (g *geoIP) isAllowed(record geoRecord) bool {
+	if _, ok := g.deniedCountries[record.country]; ok && record.country != "" {
+		return false
+	}
+
+	if _, ok := g.deniedASNs[record.asn]; ok && record.asn != 0 {
+		return false
+	}
+
+	if len(g.allowedCountries) > 0 {
+		if _, ok := g.allowedCountries[record.country]; !ok {
+			return false
+		}
+	}
+
+	if len(g.allowedASNs) > 0 {
+		if _, ok := g.allowedASNs[record.asn]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// merge fills the unknown fields of the record with the data recorded in a database,
+// which follows the structure of the MaxMind GeoIP2 and GeoLite2 databases.
+func (r *geoRecord) merge(value interface{}) {
+	data, ok := value.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if r.country == "" {
+		r.country = getString(data, "country", "iso_code")
+	}
+
+	if r.country == "" {
+		r.country = getString(data, "registered_country", "iso_code")
+	}
+
+	if r.city == "" {
+		r.city = getString(data, "city", "names", "en")
+	}
+
+	if r.asn == 0 {
+		if asn, ok := data["autonomous_system_number"].(uint64); ok {
+			r.asn = int(asn)
+		}
+	}
+
+	if r.asOrganization == "" {
+		r.asOrganization = getString(data, "autonomous_system_organization")
+	}
+}
+
+// getString returns the string at the path of keys in the nested maps, or an empty string.
+func getString(data map[string]interface{}, path ...string) string {
+	for i, key := range path {
+		if i == len(path)-1 {
+			value, _ := data[key].(string)
+			return value
+		}
+
+		var ok bool
+		data, ok = data[key].(map[string]interface{})
+		if !ok {
+			return ""
+		}
+	}
+
+	return ""
+}
+
+func setHeader(req *http.Request, name, value string) {
+	if name == "" {
+		return
+	}
+
+	req.Header.Del(name)
+	if value != "" {
+		req.Header.Set(name, value)
+	}
+}
+
+func toCountrySet(countries []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(countries))
+	for _, country := range countries {
+		set[strings.ToUpper(strings.TrimSpace(country))] = struct{}{}
+	}
+
+	return set
+}
+
+func toASNSet(asns []int) map[int]struct{} {
+	set := make(map[int]struct{}, len(asns))
+	for _, asn := range asns {
+		set[asn] = struct{}{}
+	}
+
+	return set
+}
+
+func reject(ctx context.Context, rw http.ResponseWriter) {
+	statusCode := http.StatusForbidden
+
+	rw.WriteHeader(statusCode)
+	_, err := rw.Write([]byte(http.StatusText(statusCode)))
+	if err != nil {
+		log.FromContext(ctx).Error(err)
+	}
+}
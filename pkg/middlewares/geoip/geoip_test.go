@@ -0,0 +1,272 @@
+package geoip
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func writeTestDatabase(t *testing.T, networks map[string]interface{}) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "test.mmdb")
+	err := os.WriteFile(path, buildTestDatabase(t, networks), 0o600)
+	require.NoError(t, err)
+
+	return path
+}
+
+func TestNewGeoIP(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		databaseFiles []string
+		expectedErr   bool
+	}{
+		{
+			desc:        "no database",
+			expectedErr: true,
+		},
+		{
+			desc:          "missing database",
+			databaseFiles: []string{filepath.Join(t.TempDir(), "missing.mmdb")},
+			expectedErr:   true,
+		},
+		{
+			desc:          "valid database",
+			databaseFiles: []string{writeTestDatabase(t, map[string]interface{}{"1.2.3.0/24": "foo"})},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+			_, err := New(context.Background(), next, dynamic.GeoIP{DatabaseFiles: test.databaseFiles}, "test")
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestGeoIP_ServeHTTP(t *testing.T) {
+	countryDB := writeTestDatabase(t, map[string]interface{}{
+		"1.2.3.0/24": map[string]interface{}{
+			"city":    map[string]interface{}{"names": map[string]interface{}{"en": "Lyon"}},
+			"country": map[string]interface{}{"iso_code": "FR"},
+		},
+		"5.6.7.0/24": map[string]interface{}{
+			"registered_country": map[string]interface{}{"iso_code": "DE"},
+		},
+		"2001:db8::/32": map[string]interface{}{
+			"country": map[string]interface{}{"iso_code": "US"},
+		},
+	})
+
+	asnDB := writeTestDatabase(t, map[string]interface{}{
+		"1.2.0.0/16": map[string]interface{}{
+			"autonomous_system_number":       uint32(64512),
+			"autonomous_system_organization": "Example",
+		},
+	})
+
+	testCases := []struct {
+		desc            string
+		config          dynamic.GeoIP
+		remoteAddr      string
+		reqHeaders      map[string]string
+		expectedStatus  int
+		expectedHeaders map[string]string
+	}{
+		{
+			desc:           "allowed country",
+			config:         dynamic.GeoIP{AllowedCountries: []string{"fr", "DE"}},
+			remoteAddr:     "1.2.3.4:1234",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "allowed registered country",
+			config:         dynamic.GeoIP{AllowedCountries: []string{"DE"}},
+			remoteAddr:     "5.6.7.8:1234",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "country not allowed",
+			config:         dynamic.GeoIP{AllowedCountries: []string{"DE"}},
+			remoteAddr:     "1.2.3.4:1234",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "unknown country not allowed",
+			config:         dynamic.GeoIP{AllowedCountries: []string{"FR"}},
+			remoteAddr:     "9.9.9.9:1234",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "denied country",
+			config:         dynamic.GeoIP{DeniedCountries: []string{"US"}},
+			remoteAddr:     "[2001:db8::1]:1234",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "unknown country not denied",
+			config:         dynamic.GeoIP{DeniedCountries: []string{"US"}},
+			remoteAddr:     "9.9.9.9:1234",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "allowed ASN",
+			config:         dynamic.GeoIP{AllowedASNs: []int{64512}},
+			remoteAddr:     "1.2.3.4:1234",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "ASN not allowed",
+			config:         dynamic.GeoIP{AllowedASNs: []int{64512}},
+			remoteAddr:     "5.6.7.8:1234",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "denied ASN of an allowed country",
+			config:         dynamic.GeoIP{AllowedCountries: []string{"FR"}, DeniedASNs: []int{64512}},
+			remoteAddr:     "1.2.3.4:1234",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc: "client IP from the X-Forwarded-For header",
+			config: dynamic.GeoIP{
+				AllowedCountries: []string{"DE"},
+				IPStrategy:       &dynamic.IPStrategy{Depth: 1},
+			},
+			remoteAddr:     "1.2.3.4:1234",
+			reqHeaders:     map[string]string{"X-Forwarded-For": "5.6.7.8"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc: "geolocation headers",
+			config: dynamic.GeoIP{
+				Headers: &dynamic.GeoIPHeaders{
+					Country:        "X-Country",
+					City:           "X-City",
+					ASN:            "X-Asn",
+					ASOrganization: "X-As-Org",
+				},
+			},
+			remoteAddr:     "1.2.3.4:1234",
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"X-Country": "FR",
+				"X-City":    "Lyon",
+				"X-Asn":     "64512",
+				"X-As-Org":  "Example",
+			},
+		},
+		{
+			desc: "spoofed geolocation headers",
+			config: dynamic.GeoIP{
+				Headers: &dynamic.GeoIPHeaders{
+					Country: "X-Country",
+					City:    "X-City",
+				},
+			},
+			remoteAddr:     "5.6.7.8:1234",
+			reqHeaders:     map[string]string{"X-Country": "FR", "X-City": "Lyon"},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"X-Country": "DE",
+				"X-City":    "",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var reqHeaders http.Header
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				reqHeaders = req.Header
+			})
+
+			test.config.DatabaseFiles = []string{countryDB, asnDB}
+
+			handler, err := New(context.Background(), next, test.config, "test")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			req.RemoteAddr = test.remoteAddr
+			for name, value := range test.reqHeaders {
+				req.Header.Set(name, value)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+
+			for name, value := range test.expectedHeaders {
+				assert.Equal(t, value, reqHeaders.Get(name), name)
+			}
+		})
+	}
+}
+
+func TestGeoIP_databaseReload(t *testing.T) {
+	path := writeTestDatabase(t, map[string]interface{}{
+		"1.2.3.0/24": map[string]interface{}{"country": map[string]interface{}{"iso_code": "FR"}},
+	})
+
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+	handler, err := New(context.Background(), next, dynamic.GeoIP{DatabaseFiles: []string{path}, AllowedCountries: []string{"FR"}}, "test")
+	require.NoError(t, err)
+
+	serve := func() int {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+		req.RemoteAddr = "1.2.3.4:1234"
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+
+		return recorder.Code
+	}
+
+	require.Equal(t, http.StatusOK, serve())
+
+	db, err := getDatabase(path)
+	require.NoError(t, err)
+	defer db.file.Release()
+
+	// An invalid database is not loaded.
+	err = os.WriteFile(path, []byte("invalid"), 0o600)
+	require.NoError(t, err)
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+
+	db.file.Reload()
+	assert.Equal(t, http.StatusOK, serve())
+
+	content := buildTestDatabase(t, map[string]interface{}{
+		"1.2.3.0/24": map[string]interface{}{"country": map[string]interface{}{"iso_code": "DE"}},
+	})
+	err = os.WriteFile(path, content, 0o600)
+	require.NoError(t, err)
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(2*time.Minute)))
+
+	db.file.Reload()
+	assert.Equal(t, http.StatusForbidden, serve())
+}
@@ -0,0 +1,376 @@
+package geoip
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+)
+
+// metadataStartMarker precedes the metadata at the end of a MaxMind DB file.
+var metadataStartMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+const (
+	// dataSectionSeparatorSize is the size of the separator between the search tree and the data section.
+	dataSectionSeparatorSize = 16
+
+	// maxDecodeDepth bounds the nesting of the decoded values, which protects against the pointer loops.
+	maxDecodeDepth = 64
+)
+
+// The data types of the MaxMind DB format.
+const (
+	typeExtended = 0
+	typePointer  = 1
+	typeString   = 2
+	typeDouble   = 3
+	typeBytes    = 4
+	typeUint16   = 5
+	typeUint32   = 6
+	typeMap      = 7
+	typeInt32    = 8
+	typeUint64   = 9
+	typeUint128  = 10
+	typeArray    = 11
+	typeBool     = 14
+	typeFloat    = 15
+)
+
+// unsignedMaxSizes are the maximum sizes, in bytes, of the unsigned integer types.
+var unsignedMaxSizes = map[uint]uint{typeUint16: 2, typeUint32: 4, typeUint64: 8}
+
+// mmdbReader looks up IP addresses in the content of a MaxMind DB (mmdb) file.
+// See https://maxmind.github.io/MaxMind-DB/ for the specification of the format.
+type mmdbReader struct {
+	tree       []byte
+	data       decoder
+	nodeCount  uint
+	recordSize uint
+	ipVersion  uint
+	ipv4Start  uint
+}
+
+func newMMDBReader(buffer []byte) (*mmdbReader, error) {
+	metadataStart := bytes.LastIndex(buffer, metadataStartMarker)
+	if metadataStart == -1 {
+		return nil, errors.New("invalid MaxMind DB file: metadata not found")
+	}
+
+	value, _, err := decoder{buffer: buffer[metadataStart+len(metadataStartMarker):]}.decode(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MaxMind DB metadata: %w", err)
+	}
+
+	metadata, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid MaxMind DB metadata: not a map")
+	}
+
+	if version, _ := metadata["binary_format_major_version"].(uint64); version != 2 {
+		return nil, fmt.Errorf("unsupported MaxMind DB format version: %v", metadata["binary_format_major_version"])
+	}
+
+	nodeCount, _ := metadata["node_count"].(uint64)
+	recordSize, _ := metadata["record_size"].(uint64)
+	ipVersion, _ := metadata["ip_version"].(uint64)
+
+	if recordSize != 24 && recordSize != 28 && recordSize != 32 {
+		return nil, fmt.Errorf("unsupported MaxMind DB record size: %d", recordSize)
+	}
+
+	if ipVersion != 4 && ipVersion != 6 {
+		return nil, fmt.Errorf("unsupported MaxMind DB IP version: %d", ipVersion)
+	}
+
+	// The node count is checked before computing the size of the search tree, which could overflow otherwise.
+	if metadataStart < dataSectionSeparatorSize || nodeCount > uint64(metadataStart-dataSectionSeparatorSize)*4/recordSize {
+		return nil, errors.New("invalid MaxMind DB file: search tree larger than the file")
+	}
+
+	treeSize := nodeCount * recordSize / 4
+
+	r := &mmdbReader{
+		tree:       buffer[:treeSize],
+		data:       decoder{buffer: buffer[treeSize+dataSectionSeparatorSize : metadataStart]},
+		nodeCount:  uint(nodeCount),
+		recordSize: uint(recordSize),
+		ipVersion:  uint(ipVersion),
+	}
+
+	// In an IPv6 tree, the IPv4 addresses are looked up under ::/96.
+	if r.ipVersion == 6 {
+		for i := 0; i < 96 && r.ipv4Start < r.nodeCount; i++ {
+			r.ipv4Start = r.readRecord(r.ipv4Start, 0)
+		}
+	}
+
+	return r, nil
+}
+
+// lookup returns the data recorded for the network of the IP address, or nil if there is none.
+func (r *mmdbReader) lookup(ip net.IP) (interface{}, error) {
+	var node uint
+
+	address := ip.To4()
+	if address != nil {
+		node = r.ipv4Start
+	} else {
+		if r.ipVersion == 4 {
+			return nil, nil
+		}
+
+		address = ip.To16()
+		if address == nil {
+			return nil, fmt.Errorf("invalid IP address: %s", ip)
+		}
+	}
+
+	for i := 0; i < len(address)*8 && node < r.nodeCount; i++ {
+		bit := (address[i>>3] >> (7 - uint(i&7))) & 1
+		node = r.readRecord(node, bit)
+	}
+
+	switch {
+	case node == r.nodeCount:
+		return nil, nil
+	case node < r.nodeCount:
+		return nil, errors.New("invalid MaxMind DB search tree")
+	}
+
+	value, _, err := r.data.decode(node-r.nodeCount-dataSectionSeparatorSize, 0)
+	return value, err
+}
+
+// readRecord returns the left (bit 0) or right (bit 1) record of the node.
+func (r *mmdbReader) readRecord(node uint, bit byte) uint {
+	b := r.tree
+
+	switch r.recordSize {
+	case 24:
+		offset := node*6 + uint(bit)*3
+		return uint(b[offset])<<16 | uint(b[offset+1])<<8 | uint(b[offset+2])
+
+	case 28:
+		offset := node * 7
+		if bit == 0 {
+			return uint(b[offset+3]&0xF0)<<20 | uint(b[offset])<<16 | uint(b[offset+1])<<8 | uint(b[offset+2])
+		}
+		return uint(b[offset+3]&0x0F)<<24 | uint(b[offset+4])<<16 | uint(b[offset+5])<<8 | uint(b[offset+6])
+
+	default:
+		offset := node*8 + uint(bit)*4
+		return uint(b[offset])<<24 | uint(b[offset+1])<<16 | uint(b[offset+2])<<8 | uint(b[offset+3])
+	}
+}
+
+// decoder decodes the values of a MaxMind DB data section.
+// The maps are decoded as map[string]interface{}, the arrays as []interface{},
+// and the unsigned integers, except the 128 bits ones, as uint64.
+type decoder struct {
+	buffer []byte
+}
+
+// decode returns the value at the offset, and the offset following it.
+func (d decoder) decode(offset uint, depth int) (interface{}, uint, error) {
+	if depth > maxDecodeDepth {
+		return nil, 0, errors.New("maximum data structure depth exceeded")
+	}
+
+	if offset >= uint(len(d.buffer)) {
+		return nil, 0, errors.New("unexpected end of data")
+	}
+
+	ctrl := d.buffer[offset]
+	offset++
+
+	dataType := uint(ctrl >> 5)
+
+	if dataType == typePointer {
+		pointer, next, err := d.decodePointer(ctrl, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		value, _, err := d.decode(pointer, depth+1)
+		return value, next, err
+	}
+
+	if dataType == typeExtended {
+		if offset >= uint(len(d.buffer)) {
+			return nil, 0, errors.New("unexpected end of data")
+		}
+
+		dataType = 7 + uint(d.buffer[offset])
+		offset++
+	}
+
+	size, offset, err := d.decodeSize(ctrl, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch dataType {
+	case typeMap:
+		return d.decodeMap(size, offset, depth)
+	case typeArray:
+		return d.decodeArray(size, offset, depth)
+	case typeBool:
+		if size > 1 {
+			return nil, 0, fmt.Errorf("invalid boolean size: %d", size)
+		}
+		return size == 1, offset, nil
+	}
+
+	if offset+size > uint(len(d.buffer)) {
+		return nil, 0, errors.New("unexpected end of data")
+	}
+
+	value := d.buffer[offset : offset+size]
+	next := offset + size
+
+	switch dataType {
+	case typeString:
+		return string(value), next, nil
+
+	case typeBytes:
+		return append([]byte(nil), value...), next, nil
+
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("invalid double size: %d", size)
+		}
+		return math.Float64frombits(readUint(0, value)), next, nil
+
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("invalid float size: %d", size)
+		}
+		return math.Float32frombits(uint32(readUint(0, value))), next, nil
+
+	case typeUint16, typeUint32, typeUint64:
+		if size > unsignedMaxSizes[dataType] {
+			return nil, 0, fmt.Errorf("invalid unsigned integer size: %d", size)
+		}
+		return readUint(0, value), next, nil
+
+	case typeInt32:
+		if size > 4 {
+			return nil, 0, fmt.Errorf("invalid integer size: %d", size)
+		}
+		return int32(uint32(readUint(0, value))), next, nil
+
+	case typeUint128:
+		if size > 16 {
+			return nil, 0, fmt.Errorf("invalid unsigned integer size: %d", size)
+		}
+		return new(big.Int).SetBytes(value), next, nil
+
+	default:
+		return nil, 0, fmt.Errorf("unexpected data type: %d", dataType)
+	}
+}
+
+func (d decoder) decodeMap(size, offset uint, depth int) (interface{}, uint, error) {
+	// Each entry takes at least one byte, so the remaining data bounds the number of entries to allocate.
+	capacity := uint(len(d.buffer)) - offset
+	if size < capacity {
+		capacity = size
+	}
+
+	m := make(map[string]interface{}, capacity)
+
+	for i := uint(0); i < size; i++ {
+		key, next, err := d.decode(offset, depth+1)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		name, ok := key.(string)
+		if !ok {
+			return nil, 0, fmt.Errorf("invalid map key: %v", key)
+		}
+
+		m[name], offset, err = d.decode(next, depth+1)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return m, offset, nil
+}
+
+func (d decoder) decodeArray(size, offset uint, depth int) (interface{}, uint, error) {
+	var array []interface{}
+
+	for i := uint(0); i < size; i++ {
+		value, next, err := d.decode(offset, depth+1)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		array = append(array, value)
+		offset = next
+	}
+
+	return array, offset, nil
+}
+
+func (d decoder) decodeSize(ctrl byte, offset uint) (uint, uint, error) {
+	size := uint(ctrl & 0x1f)
+	if size < 29 {
+		return size, offset, nil
+	}
+
+	length := size - 28
+	if offset+length > uint(len(d.buffer)) {
+		return 0, 0, errors.New("unexpected end of data")
+	}
+
+	value := uint(readUint(0, d.buffer[offset:offset+length]))
+
+	switch size {
+	case 29:
+		size = 29 + value
+	case 30:
+		size = 285 + value
+	default:
+		size = 65821 + value
+	}
+
+	return size, offset + length, nil
+}
+
+func (d decoder) decodePointer(ctrl byte, offset uint) (uint, uint, error) {
+	length := uint((ctrl>>3)&0x3) + 1
+	if offset+length > uint(len(d.buffer)) {
+		return 0, 0, errors.New("unexpected end of data")
+	}
+
+	var prefix uint64
+	if length != 4 {
+		prefix = uint64(ctrl & 0x7)
+	}
+
+	pointer := uint(readUint(prefix, d.buffer[offset:offset+length]))
+
+	switch length {
+	case 2:
+		pointer += 2048
+	case 3:
+		pointer += 526336
+	}
+
+	return pointer, offset + length, nil
+}
+
+// readUint reads a big-endian unsigned integer, prefixed with the given high bits.
+func readUint(prefix uint64, b []byte) uint64 {
+	value := prefix
+	for _, c := range b {
+		value = value<<8 | uint64(c)
+	}
+
+	return value
+}
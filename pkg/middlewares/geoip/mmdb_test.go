@@ -0,0 +1,314 @@
+package geoip
+
+import (
+	"bytes"
+	"math/big"
+	"net"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testNode is a node of the search tree of a test database.
+type testNode struct {
+	children [2]*testNode
+	data     [2]interface{}
+}
+
+// buildTestDatabase builds an IPv6 MaxMind DB with 24 bits records, holding the data of the networks.
+// The IPv4 networks are inserted under ::/96.
+func buildTestDatabase(t *testing.T, networks map[string]interface{}) []byte {
+	t.Helper()
+
+	root := &testNode{}
+	for cidr, data := range networks {
+		_, network, err := net.ParseCIDR(cidr)
+		require.NoError(t, err)
+
+		ones, _ := network.Mask.Size()
+		address := network.IP.To16()
+		if network.IP.To4() != nil {
+			address = append(make(net.IP, 12), network.IP.To4()...)
+			ones += 96
+		}
+
+		node := root
+		for i := 0; i < ones; i++ {
+			bit := (address[i>>3] >> (7 - uint(i&7))) & 1
+			if i == ones-1 {
+				node.data[bit] = data
+				break
+			}
+
+			if node.children[bit] == nil {
+				node.children[bit] = &testNode{}
+			}
+			node = node.children[bit]
+		}
+	}
+
+	var nodes []*testNode
+	ids := make(map[*testNode]int)
+
+	var walk func(node *testNode)
+	walk = func(node *testNode) {
+		ids[node] = len(nodes)
+		nodes = append(nodes, node)
+		for _, child := range node.children {
+			if child != nil {
+				walk(child)
+			}
+		}
+	}
+	walk(root)
+
+	var data []byte
+	var tree []byte
+	for _, node := range nodes {
+		for bit := 0; bit < 2; bit++ {
+			record := len(nodes)
+
+			switch {
+			case node.children[bit] != nil:
+				record = ids[node.children[bit]]
+			case node.data[bit] != nil:
+				record = len(nodes) + dataSectionSeparatorSize + len(data)
+				data = append(data, encodeTestValue(node.data[bit])...)
+			}
+
+			tree = append(tree, byte(record>>16), byte(record>>8), byte(record))
+		}
+	}
+
+	metadata := encodeTestValue(map[string]interface{}{
+		"binary_format_major_version": uint16(2),
+		"database_type":               "Test",
+		"ip_version":                  uint16(6),
+		"node_count":                  uint32(len(nodes)),
+		"record_size":                 uint16(24),
+	})
+
+	var db bytes.Buffer
+	db.Write(tree)
+	db.Write(make([]byte, dataSectionSeparatorSize))
+	db.Write(data)
+	db.Write(metadataStartMarker)
+	db.Write(metadata)
+
+	return db.Bytes()
+}
+
+// encodeTestValue encodes a value in the MaxMind DB format.
+func encodeTestValue(value interface{}) []byte {
+	switch v := value.(type) {
+	case string:
+		return append(encodeTestCtrl(typeString, len(v)), v...)
+
+	case uint16:
+		return append(encodeTestCtrl(typeUint16, 2), byte(v>>8), byte(v))
+
+	case uint32:
+		return append(encodeTestCtrl(typeUint32, 4), byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+
+	case uint64:
+		return append(encodeTestCtrl(typeUint64, 8), byte(v>>56), byte(v>>48), byte(v>>40), byte(v>>32), byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+
+	case map[string]interface{}:
+		var keys []string
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		encoded := encodeTestCtrl(typeMap, len(v))
+		for _, key := range keys {
+			encoded = append(encoded, encodeTestValue(key)...)
+			encoded = append(encoded, encodeTestValue(v[key])...)
+		}
+		return encoded
+
+	default:
+		panic("unsupported test value")
+	}
+}
+
+func encodeTestCtrl(dataType, size int) []byte {
+	if size >= 285 {
+		panic("unsupported test value size")
+	}
+
+	ctrl := []byte{byte(dataType<<5 | size)}
+	if size >= 29 {
+		ctrl = []byte{byte(dataType<<5 | 29), byte(size - 29)}
+	}
+
+	if dataType > 7 {
+		ctrl = append([]byte{ctrl[0] & 0x1f, byte(dataType - 7)}, ctrl[1:]...)
+	}
+
+	return ctrl
+}
+
+func TestMMDBReader_lookup(t *testing.T) {
+	content := buildTestDatabase(t, map[string]interface{}{
+		"1.2.3.0/24":    map[string]interface{}{"country": map[string]interface{}{"iso_code": "FR"}},
+		"10.0.0.0/8":    map[string]interface{}{"autonomous_system_number": uint32(64512)},
+		"2001:db8::/32": "ipv6",
+	})
+
+	reader, err := newMMDBReader(content)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		ip       string
+		expected interface{}
+	}{
+		{
+			desc:     "IPv4 address in a network",
+			ip:       "1.2.3.4",
+			expected: map[string]interface{}{"country": map[string]interface{}{"iso_code": "FR"}},
+		},
+		{
+			desc:     "IPv4 address in another network",
+			ip:       "10.20.30.40",
+			expected: map[string]interface{}{"autonomous_system_number": uint64(64512)},
+		},
+		{
+			desc: "IPv4 address without network",
+			ip:   "1.2.4.1",
+		},
+		{
+			desc:     "IPv6 address in a network",
+			ip:       "2001:db8::1",
+			expected: "ipv6",
+		},
+		{
+			desc: "IPv6 address without network",
+			ip:   "2001:db9::1",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			value, err := reader.lookup(net.ParseIP(test.ip))
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, value)
+		})
+	}
+}
+
+func TestNewMMDBReader_invalid(t *testing.T) {
+	_, err := newMMDBReader([]byte("foobar"))
+	assert.Error(t, err)
+
+	content := buildTestDatabase(t, map[string]interface{}{"1.2.3.0/24": "foo"})
+	_, err = newMMDBReader(content[:len(content)/2])
+	assert.Error(t, err)
+
+	// The size of the search tree overflows to zero with this node count.
+	var crafted []byte
+	crafted = append(crafted, make([]byte, dataSectionSeparatorSize)...)
+	crafted = append(crafted, metadataStartMarker...)
+	crafted = append(crafted, encodeTestValue(map[string]interface{}{
+		"binary_format_major_version": uint16(2),
+		"ip_version":                  uint16(6),
+		"node_count":                  uint64(1 << 61),
+		"record_size":                 uint16(32),
+	})...)
+
+	_, err = newMMDBReader(crafted)
+	assert.Error(t, err)
+}
+
+func TestDecoder_decode(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		buffer      []byte
+		expected    interface{}
+		expectedErr bool
+	}{
+		{
+			desc:     "string",
+			buffer:   []byte{0x43, 'f', 'o', 'o'},
+			expected: "foo",
+		},
+		{
+			desc:     "string with an extended size",
+			buffer:   append([]byte{0x5d, 0x01}, bytes.Repeat([]byte{'a'}, 30)...),
+			expected: string(bytes.Repeat([]byte{'a'}, 30)),
+		},
+		{
+			desc:     "uint16",
+			buffer:   []byte{0xa2, 0x01, 0xf4},
+			expected: uint64(500),
+		},
+		{
+			desc:     "uint64",
+			buffer:   []byte{0x02, 0x02, 0x01, 0x00},
+			expected: uint64(256),
+		},
+		{
+			desc:     "uint128",
+			buffer:   []byte{0x01, 0x03, 0x01},
+			expected: big.NewInt(1),
+		},
+		{
+			desc:     "int32",
+			buffer:   []byte{0x04, 0x01, 0xff, 0xff, 0xff, 0xff},
+			expected: int32(-1),
+		},
+		{
+			desc:     "boolean",
+			buffer:   []byte{0x01, 0x07},
+			expected: true,
+		},
+		{
+			desc:     "array",
+			buffer:   []byte{0x02, 0x04, 0x41, 'a', 0x41, 'b'},
+			expected: []interface{}{"a", "b"},
+		},
+		{
+			desc:     "map with a pointer",
+			buffer:   []byte{0xe1, 0x20, 0x05, 0x41, 'a', 0x41, 'b'},
+			expected: map[string]interface{}{"b": "a"},
+		},
+		{
+			desc:        "pointer loop",
+			buffer:      []byte{0xe1, 0x41, 'a', 0x20, 0x00},
+			expectedErr: true,
+		},
+		{
+			desc:        "map larger than the data",
+			buffer:      []byte{0xff, 0xff, 0xff, 0xff},
+			expectedErr: true,
+		},
+		{
+			desc:        "truncated string",
+			buffer:      []byte{0x43, 'f'},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			value, _, err := decoder{buffer: test.buffer}.decode(0, 0)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, value)
+		})
+	}
+}
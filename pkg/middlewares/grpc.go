@@ -0,0 +1,24 @@
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+)
+
+const grpcStatusHeader = "Grpc-Status"
+
+// IsGRPCRequest reports whether the request is a gRPC request.
+func IsGRPCRequest(req *http.Request) bool {
+	return strings.HasPrefix(req.Header.Get("Content-Type"), "application/grpc")
+}
+
+// GetGRPCStatus returns the gRPC status code of a response, from its headers once it has been written.
+// The status is either sent as a header for Trailers-Only responses, or as a trailer.
+// It returns an empty string if there is no status.
+func GetGRPCStatus(header http.Header) string {
+	if status := header.Get(grpcStatusHeader); status != "" {
+		return status
+	}
+
+	return header.Get(http.TrailerPrefix + grpcStatusHeader)
+}
@@ -0,0 +1,45 @@
+package middlewares
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetGRPCStatus(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		header   http.Header
+		expected string
+	}{
+		{
+			desc:   "no status",
+			header: http.Header{},
+		},
+		{
+			desc:     "trailers-only response",
+			header:   http.Header{"Grpc-Status": []string{"5"}},
+			expected: "5",
+		},
+		{
+			desc:     "announced trailer",
+			header:   http.Header{"Trailer": []string{"Grpc-Status"}, "Grpc-Status": []string{"14"}},
+			expected: "14",
+		},
+		{
+			desc:     "unannounced trailer",
+			header:   http.Header{http.TrailerPrefix + "Grpc-Status": []string{"0"}},
+			expected: "0",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, GetGRPCStatus(test.header))
+		})
+	}
+}
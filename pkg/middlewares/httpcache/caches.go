@@ -0,0 +1,114 @@
+package httpcache
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+)
+
+// ErrCacheNotFound is returned when purging the cache of an unknown middleware.
+var ErrCacheNotFound = errors.New("cache not found")
+
+// CacheInfo holds the statistics of the cache of an HTTP cache middleware.
+type CacheInfo struct {
+	Middleware string `json:"middleware"`
+	Entries    int    `json:"entries"`
+	Size       int64  `json:"size"`
+	MaxSize    int64  `json:"maxSize"`
+	Hits       uint64 `json:"hits"`
+	Misses     uint64 `json:"misses"`
+}
+
+// Caches holds the stores of the HTTP cache middlewares, by middleware name.
+// The stores are kept across the configuration reloads, as long as their middleware is used.
+type Caches struct {
+	reqsCounter gokitmetrics.Counter
+
+	mu     sync.Mutex
+	stores map[string]*cachedStore
+}
+
+type cachedStore struct {
+	store *Store
+	used  bool
+}
+
+// NewCaches creates new Caches, reporting the requests handled by the caches to the given metrics registry.
+func NewCaches(metricsRegistry metrics.Registry) *Caches {
+	caches := &Caches{stores: make(map[string]*cachedStore)}
+
+	if metricsRegistry != nil {
+		caches.reqsCounter = metricsRegistry.HTTPCacheReqsCounter()
+	}
+
+	return caches
+}
+
+// get returns the store of the given middleware, created if needed, and resized to the given maximum size.
+func (c *Caches) get(name string, maxSize int64) *Store {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, ok := c.stores[name]
+	if !ok {
+		cached = &cachedStore{store: NewStore(maxSize)}
+		c.stores[name] = cached
+	}
+
+	cached.used = true
+	cached.store.setMaxSize(maxSize)
+
+	return cached.store
+}
+
+// Sweep drops the stores which have not been used since the previous sweep.
+// It is meant to be called each time the routers of a new configuration have been built,
+// so that only the stores of the current middlewares are kept.
+func (c *Caches) Sweep() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name, cached := range c.stores {
+		if !cached.used {
+			delete(c.stores, name)
+			continue
+		}
+
+		cached.used = false
+	}
+}
+
+// List returns the statistics of the caches, sorted by middleware name.
+func (c *Caches) List() []CacheInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	infos := make([]CacheInfo, 0, len(c.stores))
+	for name, cached := range c.stores {
+		info := cached.store.info()
+		info.Middleware = name
+
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Middleware < infos[j].Middleware })
+
+	return infos
+}
+
+// Purge removes the responses of the cache of the given middleware whose URI starts with the given prefix,
+// or all its responses if the prefix is empty, and returns the number of removed responses.
+func (c *Caches) Purge(name, prefix string) (int, error) {
+	c.mu.Lock()
+	cached, ok := c.stores[name]
+	c.mu.Unlock()
+
+	if !ok {
+		return 0, ErrCacheNotFound
+	}
+
+	return cached.store.Purge(prefix), nil
+}
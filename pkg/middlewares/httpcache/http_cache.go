@@ -0,0 +1,343 @@
+package httpcache
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "HTTPCache"
+)
+
+const (
+	defaultMaxSize              = 64 * 1024 * 1024
+	defaultMaxResponseBodyBytes = 1024 * 1024
+)
+
+// cacheStatusHeader is the response header telling whether the response was served from the cache.
+const cacheStatusHeader = "X-Cache-Status"
+
+// The cache statuses, reported in the cacheStatusHeader header and in the metrics.
+const (
+	statusHit    = "hit"
+	statusMiss   = "miss"
+	statusBypass = "bypass"
+)
+
+// cacheableByDefault are the status codes of the responses which can be cached without explicit freshness information.
+var cacheableByDefault = map[int]bool{
+	http.StatusOK:                   true,
+	http.StatusNonAuthoritativeInfo: true,
+	http.StatusNoContent:            true,
+	http.StatusMultipleChoices:      true,
+	http.StatusMovedPermanently:     true,
+	http.StatusNotFound:             true,
+	http.StatusMethodNotAllowed:     true,
+	http.StatusGone:                 true,
+	http.StatusRequestURITooLong:    true,
+	http.StatusNotImplemented:       true,
+}
+
+// httpCache is a middleware that caches the responses, following RFC 7234.
+type httpCache struct {
+	next                 http.Handler
+	name                 string
+	store                *Store
+	caches               *Caches
+	keyHeaders           []string
+	ignoreQuery          bool
+	maxResponseBodyBytes int64
+	defaultTTL           time.Duration
+	maxTTL               time.Duration
+}
+
+// New creates a new HTTP cache middleware.
+// The middleware uses the store of the given caches for its name, or its own store if caches is nil.
+func New(ctx context.Context, next http.Handler, config dynamic.HTTPCache, name string, caches *Caches) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	maxSize := int64(defaultMaxSize)
+	if config.MaxSize > 0 {
+		maxSize = config.MaxSize
+	}
+
+	maxResponseBodyBytes := int64(defaultMaxResponseBodyBytes)
+	if config.MaxResponseBodyBytes > 0 {
+		maxResponseBodyBytes = config.MaxResponseBodyBytes
+	}
+
+	keyHeaders := make([]string, 0, len(config.KeyHeaders))
+	for _, header := range config.KeyHeaders {
+		keyHeaders = append(keyHeaders, http.CanonicalHeaderKey(header))
+	}
+
+	var store *Store
+	if caches != nil {
+		store = caches.get(name, maxSize)
+	} else {
+		store = NewStore(maxSize)
+	}
+
+	return &httpCache{
+		next:                 next,
+		name:                 name,
+		store:                store,
+		caches:               caches,
+		keyHeaders:           keyHeaders,
+		ignoreQuery:          config.IgnoreQuery,
+		maxResponseBodyBytes: maxResponseBodyBytes,
+		defaultTTL:           time.Duration(config.DefaultTTL),
+		maxTTL:               time.Duration(config.MaxTTL),
+	}, nil
+}
+
+func (c *httpCache) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return c.name, tracing.SpanKindNoneEnum
+}
+
+func (c *httpCache) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodHead, http.MethodOptions, http.MethodTrace:
+		c.next.ServeHTTP(rw, req)
+		return
+	default:
+		// The unsafe requests invalidate the responses cached for their URI.
+		recorder := &responseRecorder{rw: rw}
+		c.next.ServeHTTP(recorder, req)
+
+		if recorder.code < http.StatusBadRequest {
+			c.store.invalidate(req.Host, req.URL.RequestURI())
+		}
+		return
+	}
+
+	reqCC := parseCacheControl(req.Header)
+	if reqCC.has("no-store") {
+		c.report(statusBypass)
+		rw.Header().Set(cacheStatusHeader, statusBypass)
+		c.next.ServeHTTP(rw, req)
+		return
+	}
+
+	primaryKey := c.key(req)
+	now := time.Now()
+
+	if !reqCC.has("no-cache") && !(len(reqCC) == 0 && req.Header.Get("Pragma") == "no-cache") {
+		if e := c.store.get(primaryKey, req); e != nil {
+			maxAge, hasMaxAge := reqCC.duration("max-age")
+
+			if e.fresh(now) && (!hasMaxAge || e.age(now) <= maxAge) {
+				atomic.AddUint64(&c.store.hits, 1)
+				c.report(statusHit)
+				c.serve(rw, req, e, now)
+				return
+			}
+
+			if !e.fresh(now) {
+				c.store.delete(e)
+			}
+		}
+	}
+
+	atomic.AddUint64(&c.store.misses, 1)
+	c.report(statusMiss)
+	rw.Header().Set(cacheStatusHeader, statusMiss)
+
+	recorder := &responseRecorder{rw: rw, maxSize: c.maxResponseBodyBytes, record: true}
+	c.next.ServeHTTP(recorder, req)
+
+	if recorder.failed || recorder.code == 0 || req.Context().Err() != nil {
+		return
+	}
+
+	c.storeResponse(req, primaryKey, recorder, now)
+}
+
+// key returns the key identifying the request.
+func (c *httpCache) key(req *http.Request) string {
+	var key strings.Builder
+	if req.TLS != nil {
+		key.WriteString("https")
+	} else {
+		key.WriteString("http")
+	}
+
+	key.WriteByte('\n')
+	key.WriteString(req.Host)
+	key.WriteByte('\n')
+
+	if c.ignoreQuery {
+		key.WriteString(req.URL.EscapedPath())
+	} else {
+		key.WriteString(req.URL.RequestURI())
+	}
+
+	for _, header := range c.keyHeaders {
+		key.WriteByte('\n')
+		key.WriteString(strings.Join(req.Header.Values(header), ","))
+	}
+
+	return key.String()
+}
+
+// serve sends the cached response.
+func (c *httpCache) serve(rw http.ResponseWriter, req *http.Request, e *entry, now time.Time) {
+	for k, v := range e.header {
+		rw.Header()[k] = append([]string(nil), v...)
+	}
+
+	rw.Header().Set("Age", strconv.FormatInt(int64(e.age(now)/time.Second), 10))
+	rw.Header().Set(cacheStatusHeader, statusHit)
+
+	if etag := e.header.Get("Etag"); etag != "" && etagMatch(req.Header.Get("If-None-Match"), etag) {
+		rw.Header().Del("Content-Length")
+		rw.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	rw.WriteHeader(e.code)
+
+	if _, err := rw.Write(e.body); err != nil {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), c.name, typeName)).Debugf("Error while writing the cached response: %v", err)
+	}
+}
+
+// storeResponse stores the recorded response, if it can be cached.
+func (c *httpCache) storeResponse(req *http.Request, primaryKey string, recorder *responseRecorder, now time.Time) {
+	// The partial responses, and the responses to conditional requests, do not hold the full representation.
+	if recorder.code == http.StatusPartialContent || recorder.code == http.StatusNotModified {
+		return
+	}
+
+	header := recorder.header
+	respCC := parseCacheControl(header)
+
+	if respCC.has("no-store") || respCC.has("no-cache") || respCC.has("private") {
+		return
+	}
+
+	// The responses to authenticated requests are only cached when they are explicitly allowed to be shared.
+	if req.Header.Get("Authorization") != "" && !respCC.has("public") && !respCC.has("s-maxage") && !respCC.has("must-revalidate") {
+		return
+	}
+
+	if header.Get("Set-Cookie") != "" {
+		return
+	}
+
+	var vary []string
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "*" {
+				return
+			}
+
+			if name != "" {
+				vary = append(vary, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+
+	lifetime, ok := c.lifetime(recorder.code, header, respCC)
+	if !ok || lifetime <= 0 {
+		return
+	}
+
+	var initialAge time.Duration
+	if seconds, err := strconv.ParseInt(header.Get("Age"), 10, 64); err == nil && seconds > 0 {
+		initialAge = time.Duration(seconds) * time.Second
+	}
+
+	header.Del(cacheStatusHeader)
+	header.Del("Age")
+
+	c.store.set(req, &entry{
+		primaryKey: primaryKey,
+		host:       req.Host,
+		uri:        req.URL.RequestURI(),
+		code:       recorder.code,
+		header:     header,
+		body:       recorder.body.Bytes(),
+		storedAt:   now,
+		initialAge: initialAge,
+		lifetime:   lifetime,
+	}, vary)
+}
+
+// lifetime returns the freshness lifetime of the response, and whether the response can be cached.
+func (c *httpCache) lifetime(code int, header http.Header, respCC cacheControl) (time.Duration, bool) {
+	lifetime, explicit := respCC.duration("s-maxage")
+	if !explicit {
+		lifetime, explicit = respCC.duration("max-age")
+	}
+
+	if !explicit && header.Get("Expires") != "" {
+		explicit = true
+
+		// An invalid Expires header means that the response is already expired.
+		expires, err := http.ParseTime(header.Get("Expires"))
+		if err == nil {
+			date, errDate := http.ParseTime(header.Get("Date"))
+			if errDate != nil {
+				date = time.Now()
+			}
+
+			lifetime = expires.Sub(date)
+		}
+	}
+
+	if !explicit {
+		if !cacheableByDefault[code] {
+			return 0, false
+		}
+
+		lifetime = c.defaultTTL
+	}
+
+	if c.maxTTL > 0 && lifetime > c.maxTTL {
+		lifetime = c.maxTTL
+	}
+
+	return lifetime, true
+}
+
+func (c *httpCache) report(status string) {
+	if c.caches == nil || c.caches.reqsCounter == nil {
+		return
+	}
+
+	c.caches.reqsCounter.With("middleware", c.name, "status", status).Add(1)
+}
+
+// etagMatch reports whether the given If-None-Match header value matches the given entity tag,
+// with the weak comparison.
+func etagMatch(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	if strings.TrimSpace(ifNoneMatch) == "*" {
+		return true
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,372 @@
+package httpcache
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestHTTPCache(t *testing.T) {
+	type request struct {
+		method         string
+		path           string
+		header         http.Header
+		expectedStatus string
+		expectedCode   int
+	}
+
+	testCases := []struct {
+		desc           string
+		config         dynamic.HTTPCache
+		responseHeader http.Header
+		responseCode   int
+		requests       []request
+		expectedCalls  int
+	}{
+		{
+			desc:           "fresh response served from the cache",
+			responseHeader: http.Header{"Cache-Control": {"max-age=60"}},
+			requests: []request{
+				{path: "/foo", expectedStatus: statusMiss},
+				{path: "/foo", expectedStatus: statusHit},
+				{path: "/bar", expectedStatus: statusMiss},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc:           "response with an Expires header",
+			responseHeader: http.Header{"Expires": {time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)}},
+			requests: []request{
+				{path: "/foo", expectedStatus: statusMiss},
+				{path: "/foo", expectedStatus: statusHit},
+			},
+			expectedCalls: 1,
+		},
+		{
+			desc: "response without freshness information",
+			requests: []request{
+				{path: "/foo", expectedStatus: statusMiss},
+				{path: "/foo", expectedStatus: statusMiss},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc:   "response without freshness information and a default TTL",
+			config: dynamic.HTTPCache{DefaultTTL: ptypes.Duration(time.Minute)},
+			requests: []request{
+				{path: "/foo", expectedStatus: statusMiss},
+				{path: "/foo", expectedStatus: statusHit},
+			},
+			expectedCalls: 1,
+		},
+		{
+			desc:         "error response without freshness information and a default TTL",
+			config:       dynamic.HTTPCache{DefaultTTL: ptypes.Duration(time.Minute)},
+			responseCode: http.StatusInternalServerError,
+			requests: []request{
+				{path: "/foo", expectedStatus: statusMiss, expectedCode: http.StatusInternalServerError},
+				{path: "/foo", expectedStatus: statusMiss, expectedCode: http.StatusInternalServerError},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc:           "response not to be stored",
+			responseHeader: http.Header{"Cache-Control": {"max-age=60, no-store"}},
+			requests: []request{
+				{path: "/foo", expectedStatus: statusMiss},
+				{path: "/foo", expectedStatus: statusMiss},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc:           "private response",
+			responseHeader: http.Header{"Cache-Control": {"private, max-age=60"}},
+			requests: []request{
+				{path: "/foo", expectedStatus: statusMiss},
+				{path: "/foo", expectedStatus: statusMiss},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc:           "response setting a cookie",
+			responseHeader: http.Header{"Cache-Control": {"max-age=60"}, "Set-Cookie": {"session=foo"}},
+			requests: []request{
+				{path: "/foo", expectedStatus: statusMiss},
+				{path: "/foo", expectedStatus: statusMiss},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc:           "request bypassing the cache",
+			responseHeader: http.Header{"Cache-Control": {"max-age=60"}},
+			requests: []request{
+				{path: "/foo", header: http.Header{"Cache-Control": {"no-store"}}, expectedStatus: statusBypass},
+				{path: "/foo", expectedStatus: statusMiss},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc:           "request revalidating the cached response",
+			responseHeader: http.Header{"Cache-Control": {"max-age=60"}},
+			requests: []request{
+				{path: "/foo", expectedStatus: statusMiss},
+				{path: "/foo", header: http.Header{"Cache-Control": {"no-cache"}}, expectedStatus: statusMiss},
+				{path: "/foo", expectedStatus: statusHit},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc:           "request with a max age lower than the age of the cached response",
+			responseHeader: http.Header{"Cache-Control": {"max-age=60"}, "Age": {"10"}},
+			requests: []request{
+				{path: "/foo", expectedStatus: statusMiss},
+				{path: "/foo", header: http.Header{"Cache-Control": {"max-age=5"}}, expectedStatus: statusMiss},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc:           "query ignored",
+			config:         dynamic.HTTPCache{IgnoreQuery: true},
+			responseHeader: http.Header{"Cache-Control": {"max-age=60"}},
+			requests: []request{
+				{path: "/foo?bar=1", expectedStatus: statusMiss},
+				{path: "/foo?bar=2", expectedStatus: statusHit},
+			},
+			expectedCalls: 1,
+		},
+		{
+			desc:           "key headers",
+			config:         dynamic.HTTPCache{KeyHeaders: []string{"x-tenant"}},
+			responseHeader: http.Header{"Cache-Control": {"max-age=60"}},
+			requests: []request{
+				{path: "/foo", header: http.Header{"X-Tenant": {"a"}}, expectedStatus: statusMiss},
+				{path: "/foo", header: http.Header{"X-Tenant": {"b"}}, expectedStatus: statusMiss},
+				{path: "/foo", header: http.Header{"X-Tenant": {"a"}}, expectedStatus: statusHit},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc:           "response varying on a request header",
+			responseHeader: http.Header{"Cache-Control": {"max-age=60"}, "Vary": {"Accept-Language"}},
+			requests: []request{
+				{path: "/foo", header: http.Header{"Accept-Language": {"en"}}, expectedStatus: statusMiss},
+				{path: "/foo", header: http.Header{"Accept-Language": {"fr"}}, expectedStatus: statusMiss},
+				{path: "/foo", header: http.Header{"Accept-Language": {"en"}}, expectedStatus: statusHit},
+				{path: "/foo", header: http.Header{"Accept-Language": {"fr"}}, expectedStatus: statusHit},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc:           "response varying on all the request headers",
+			responseHeader: http.Header{"Cache-Control": {"max-age=60"}, "Vary": {"*"}},
+			requests: []request{
+				{path: "/foo", expectedStatus: statusMiss},
+				{path: "/foo", expectedStatus: statusMiss},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc:           "authenticated request",
+			responseHeader: http.Header{"Cache-Control": {"max-age=60"}},
+			requests: []request{
+				{path: "/foo", header: http.Header{"Authorization": {"Bearer foo"}}, expectedStatus: statusMiss},
+				{path: "/foo", header: http.Header{"Authorization": {"Bearer foo"}}, expectedStatus: statusMiss},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc:           "authenticated request with a public response",
+			responseHeader: http.Header{"Cache-Control": {"public, max-age=60"}},
+			requests: []request{
+				{path: "/foo", header: http.Header{"Authorization": {"Bearer foo"}}, expectedStatus: statusMiss},
+				{path: "/foo", header: http.Header{"Authorization": {"Bearer foo"}}, expectedStatus: statusHit},
+			},
+			expectedCalls: 1,
+		},
+		{
+			desc:           "unsafe request invalidating the cached response",
+			responseHeader: http.Header{"Cache-Control": {"max-age=60"}},
+			requests: []request{
+				{path: "/foo", expectedStatus: statusMiss},
+				{method: http.MethodPost, path: "/foo"},
+				{path: "/foo", expectedStatus: statusMiss},
+			},
+			expectedCalls: 3,
+		},
+		{
+			desc:           "response too large",
+			config:         dynamic.HTTPCache{MaxResponseBodyBytes: 2},
+			responseHeader: http.Header{"Cache-Control": {"max-age=60"}},
+			requests: []request{
+				{path: "/foo", expectedStatus: statusMiss},
+				{path: "/foo", expectedStatus: statusMiss},
+			},
+			expectedCalls: 2,
+		},
+		{
+			desc:           "conditional request matching the cached response",
+			responseHeader: http.Header{"Cache-Control": {"max-age=60"}, "Etag": {`"v1"`}},
+			requests: []request{
+				{path: "/foo", expectedStatus: statusMiss},
+				{path: "/foo", header: http.Header{"If-None-Match": {`W/"v1"`}}, expectedStatus: statusHit, expectedCode: http.StatusNotModified},
+			},
+			expectedCalls: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			responseCode := test.responseCode
+			if responseCode == 0 {
+				responseCode = http.StatusOK
+			}
+
+			var calls int
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				calls++
+
+				for k, v := range test.responseHeader {
+					rw.Header()[k] = v
+				}
+
+				rw.WriteHeader(responseCode)
+				_, _ = rw.Write([]byte("foo"))
+			})
+
+			handler, err := New(context.Background(), next, test.config, "cache", nil)
+			require.NoError(t, err)
+
+			for i, r := range test.requests {
+				method := r.method
+				if method == "" {
+					method = http.MethodGet
+				}
+
+				req := httptest.NewRequest(method, "http://localhost"+r.path, nil)
+				for k, v := range r.header {
+					req.Header[k] = v
+				}
+
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, req)
+
+				assert.Equal(t, r.expectedStatus, recorder.Header().Get(cacheStatusHeader), "request %d", i)
+
+				expectedCode := r.expectedCode
+				if expectedCode == 0 {
+					expectedCode = responseCode
+				}
+				assert.Equal(t, expectedCode, recorder.Code, "request %d", i)
+
+				if expectedCode != http.StatusNotModified {
+					assert.Equal(t, "foo", recorder.Body.String(), "request %d", i)
+				}
+			}
+
+			assert.Equal(t, test.expectedCalls, calls)
+		})
+	}
+}
+
+func TestHTTPCache_age(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Cache-Control", "max-age=60")
+		rw.Header().Set("Age", "10")
+		_, _ = rw.Write([]byte("foo"))
+	})
+
+	handler, err := New(context.Background(), next, dynamic.HTTPCache{}, "cache", nil)
+	require.NoError(t, err)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil))
+
+	assert.Equal(t, statusHit, recorder.Header().Get(cacheStatusHeader))
+	assert.Equal(t, "10", recorder.Header().Get("Age"))
+}
+
+func TestStore_eviction(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Cache-Control", "max-age=60")
+		_, _ = rw.Write(make([]byte, 100))
+	})
+
+	handler, err := New(context.Background(), next, dynamic.HTTPCache{MaxSize: 400}, "cache", nil)
+	require.NoError(t, err)
+
+	store := handler.(*httpCache).store
+
+	for _, path := range []string{"/foo", "/bar", "/foo", "/baz"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost"+path, nil))
+	}
+
+	// The least recently used response, /bar, is evicted.
+	info := store.info()
+	assert.Equal(t, 2, info.Entries)
+	assert.LessOrEqual(t, info.Size, int64(400))
+	assert.Equal(t, uint64(1), info.Hits)
+	assert.Equal(t, uint64(3), info.Misses)
+
+	assert.Equal(t, 1, store.Purge("/f"))
+	assert.Equal(t, 1, store.Purge(""))
+	assert.Equal(t, 0, store.info().Entries)
+}
+
+func TestCaches(t *testing.T) {
+	caches := NewCaches(nil)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Cache-Control", "max-age=60")
+		_, _ = rw.Write([]byte("foo"))
+	})
+
+	foo, err := New(context.Background(), next, dynamic.HTTPCache{}, "foo@file", caches)
+	require.NoError(t, err)
+
+	_, err = New(context.Background(), next, dynamic.HTTPCache{}, "bar@file", caches)
+	require.NoError(t, err)
+
+	caches.Sweep()
+
+	foo.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil))
+
+	infos := caches.List()
+	require.Len(t, infos, 2)
+	assert.Equal(t, "bar@file", infos[0].Middleware)
+	assert.Equal(t, "foo@file", infos[1].Middleware)
+	assert.Equal(t, 1, infos[1].Entries)
+
+	// The store is kept when the middleware is built again for a new configuration.
+	foo, err = New(context.Background(), next, dynamic.HTTPCache{}, "foo@file", caches)
+	require.NoError(t, err)
+
+	caches.Sweep()
+
+	infos = caches.List()
+	require.Len(t, infos, 1)
+	assert.Equal(t, "foo@file", infos[0].Middleware)
+	assert.Equal(t, 1, infos[0].Entries)
+
+	recorder := httptest.NewRecorder()
+	foo.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil))
+	assert.Equal(t, statusHit, recorder.Header().Get(cacheStatusHeader))
+
+	purged, err := caches.Purge("foo@file", "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	_, err = caches.Purge("bar@file", "")
+	assert.ErrorIs(t, err, ErrCacheNotFound)
+}
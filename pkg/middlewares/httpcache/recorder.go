@@ -0,0 +1,88 @@
+package httpcache
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// responseRecorder sends the response to the client,
+// while recording its status code, its headers, and, if record is true, its body up to the maximum size.
+type responseRecorder struct {
+	rw      http.ResponseWriter
+	maxSize int64
+	record  bool
+
+	code   int
+	header http.Header
+	body   bytes.Buffer
+	// failed is true when the response cannot be stored,
+	// because its body exceeds the maximum size, it could not be sent, or the connection was hijacked.
+	failed bool
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.rw.Header()
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	// The informational responses are not recorded.
+	if r.code == 0 && code >= http.StatusOK {
+		r.code = code
+		if r.record {
+			r.header = r.rw.Header().Clone()
+		}
+	}
+
+	r.rw.WriteHeader(code)
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	if r.code == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	n, err := r.rw.Write(p)
+	if err != nil {
+		r.failed = true
+	}
+
+	if !r.record || r.failed {
+		return n, err
+	}
+
+	if int64(r.body.Len()+n) > r.maxSize {
+		r.failed = true
+		r.body = bytes.Buffer{}
+		return n, err
+	}
+
+	r.body.Write(p[:n])
+
+	return n, err
+}
+
+// Flush sends any buffered data to the client.
+func (r *responseRecorder) Flush() {
+	if r.code == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if flusher, ok := r.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hijacks the connection.
+func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.rw.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("not a hijacker: %T", r.rw)
+	}
+
+	r.failed = true
+
+	return hijacker.Hijack()
+}
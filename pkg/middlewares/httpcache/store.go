@@ -0,0 +1,245 @@
+package httpcache
+
+import (
+	"container/list"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// entry is a cached response.
+type entry struct {
+	// key identifies the response among the variants of the request.
+	key string
+	// primaryKey identifies the request.
+	primaryKey string
+	host       string
+	uri        string
+
+	code   int
+	header http.Header
+	body   []byte
+
+	// storedAt is the time at which the response was received.
+	storedAt time.Time
+	// initialAge is the age of the response when it was received, according to its Age header.
+	initialAge time.Duration
+	// lifetime is the freshness lifetime of the response.
+	lifetime time.Duration
+}
+
+func (e *entry) age(now time.Time) time.Duration {
+	return e.initialAge + now.Sub(e.storedAt)
+}
+
+func (e *entry) fresh(now time.Time) bool {
+	return e.age(now) < e.lifetime
+}
+
+// size returns the approximate memory size of the entry.
+func (e *entry) size() int64 {
+	size := len(e.key) + len(e.primaryKey) + len(e.host) + len(e.uri) + len(e.body)
+	for k, values := range e.header {
+		size += len(k)
+		for _, v := range values {
+			size += len(v)
+		}
+	}
+
+	return int64(size)
+}
+
+// variants holds the header names the responses to a request vary on.
+type variants struct {
+	headers []string
+	count   int
+}
+
+// Store is an in-memory store of responses,
+// which evicts the least recently used responses when it exceeds its maximum size.
+type Store struct {
+	mu       sync.Mutex
+	maxSize  int64
+	size     int64
+	entries  map[string]*list.Element
+	lru      *list.List
+	variants map[string]*variants
+
+	hits   uint64
+	misses uint64
+}
+
+// NewStore creates a new Store.
+func NewStore(maxSize int64) *Store {
+	return &Store{
+		maxSize:  maxSize,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+		variants: make(map[string]*variants),
+	}
+}
+
+// get returns the response stored for the given request, or nil if there is none.
+func (s *Store) get(primaryKey string, req *http.Request) *entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.variants[primaryKey]
+	if !ok {
+		return nil
+	}
+
+	elem, ok := s.entries[variantKey(primaryKey, v.headers, req)]
+	if !ok {
+		return nil
+	}
+
+	s.lru.MoveToFront(elem)
+
+	return elem.Value.(*entry)
+}
+
+// set stores the response to the given request, which varies on the given header names.
+// The responses to the request varying on other headers are removed.
+func (s *Store) set(req *http.Request, e *entry, vary []string) {
+	e.key = variantKey(e.primaryKey, vary, req)
+
+	size := e.size()
+	if size > s.maxSize {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if v, ok := s.variants[e.primaryKey]; ok && !equal(v.headers, vary) {
+		s.removeIf(func(other *entry) bool { return other.primaryKey == e.primaryKey })
+	}
+
+	if elem, ok := s.entries[e.key]; ok {
+		s.remove(elem)
+	}
+
+	v, ok := s.variants[e.primaryKey]
+	if !ok {
+		v = &variants{headers: vary}
+		s.variants[e.primaryKey] = v
+	}
+	v.count++
+
+	s.entries[e.key] = s.lru.PushFront(e)
+	s.size += size
+
+	s.evict()
+}
+
+// delete removes the given response, if it is still stored.
+func (s *Store) delete(e *entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.entries[e.key]; ok && elem.Value.(*entry) == e {
+		s.remove(elem)
+	}
+}
+
+// invalidate removes the responses to the requests for the given host and URI.
+func (s *Store) invalidate(host, uri string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.removeIf(func(e *entry) bool { return e.host == host && e.uri == uri })
+}
+
+// Purge removes the responses whose URI starts with the given prefix, or all the responses if the prefix is empty,
+// and returns the number of removed responses.
+func (s *Store) Purge(prefix string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.removeIf(func(e *entry) bool { return strings.HasPrefix(e.uri, prefix) })
+}
+
+// setMaxSize sets the maximum size of the store, evicting responses if needed.
+func (s *Store) setMaxSize(maxSize int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.maxSize = maxSize
+	s.evict()
+}
+
+// info returns the statistics of the store.
+func (s *Store) info() CacheInfo {
+	s.mu.Lock()
+	info := CacheInfo{Entries: s.lru.Len(), Size: s.size, MaxSize: s.maxSize}
+	s.mu.Unlock()
+
+	info.Hits = atomic.LoadUint64(&s.hits)
+	info.Misses = atomic.LoadUint64(&s.misses)
+
+	return info
+}
+
+func (s *Store) evict() {
+	for s.size > s.maxSize {
+		s.remove(s.lru.Back())
+	}
+}
+
+func (s *Store) removeIf(match func(*entry) bool) int {
+	var count int
+	for elem := s.lru.Front(); elem != nil; {
+		next := elem.Next()
+		if match(elem.Value.(*entry)) {
+			s.remove(elem)
+			count++
+		}
+		elem = next
+	}
+
+	return count
+}
+
+func (s *Store) remove(elem *list.Element) {
+	e := s.lru.Remove(elem).(*entry)
+	delete(s.entries, e.key)
+	s.size -= e.size()
+
+	if v, ok := s.variants[e.primaryKey]; ok {
+		v.count--
+		if v.count == 0 {
+			delete(s.variants, e.primaryKey)
+		}
+	}
+}
+
+// variantKey returns the key identifying the response to the request among its variants,
+// from the values of the request headers the response varies on.
+func variantKey(primaryKey string, vary []string, req *http.Request) string {
+	var key strings.Builder
+	key.WriteString(primaryKey)
+
+	for _, header := range vary {
+		key.WriteByte('\n')
+		key.WriteString(strings.Join(req.Header.Values(header), ","))
+	}
+
+	return key.String()
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -2,6 +2,7 @@ package inflightreq
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -11,10 +12,14 @@ import (
 	"github.com/traefik/traefik/v2/pkg/middlewares"
 	"github.com/traefik/traefik/v2/pkg/tracing"
 	"github.com/vulcand/oxy/connlimit"
+	"github.com/vulcand/oxy/utils"
 )
 
 const (
 	typeName = "InFlightReq"
+
+	// globalSource is the source of all the requests when the requests are limited globally.
+	globalSource = "global"
 )
 
 type inFlightReq struct {
@@ -28,15 +33,7 @@ func New(ctx context.Context, next http.Handler, config dynamic.InFlightReq, nam
 	ctxLog := log.With(ctx, log.Str(log.MiddlewareName, name), log.Str(log.MiddlewareType, typeName))
 	log.FromContext(ctxLog).Debug("Creating middleware")
 
-	if config.SourceCriterion == nil ||
-		config.SourceCriterion.IPStrategy == nil &&
-			config.SourceCriterion.RequestHeaderName == "" && !config.SourceCriterion.RequestHost {
-		config.SourceCriterion = &dynamic.SourceCriterion{
-			RequestHost: true,
-		}
-	}
-
-	sourceMatcher, err := middlewares.GetSourceExtractor(ctxLog, config.SourceCriterion)
+	sourceMatcher, err := getSourceExtractor(ctxLog, config)
 	if err != nil {
 		return nil, fmt.Errorf("error creating requests limiter: %w", err)
 	}
@@ -49,6 +46,28 @@ func New(ctx context.Context, next http.Handler, config dynamic.InFlightReq, nam
 	return &inFlightReq{handler: handler, name: name}, nil
 }
 
+func getSourceExtractor(ctx context.Context, config dynamic.InFlightReq) (utils.SourceExtractor, error) {
+	if config.Global {
+		if config.SourceCriterion != nil {
+			return nil, errors.New("global and sourceCriterion are mutually exclusive")
+		}
+
+		return utils.ExtractorFunc(func(*http.Request) (string, int64, error) {
+			return globalSource, 1, nil
+		}), nil
+	}
+
+	if config.SourceCriterion == nil ||
+		config.SourceCriterion.IPStrategy == nil &&
+			config.SourceCriterion.RequestHeaderName == "" && !config.SourceCriterion.RequestHost {
+		config.SourceCriterion = &dynamic.SourceCriterion{
+			RequestHost: true,
+		}
+	}
+
+	return middlewares.GetSourceExtractor(ctx, config.SourceCriterion)
+}
+
 func (i *inFlightReq) GetTracingInformation() (string, ext.SpanKindEnum) {
 	return i.name, tracing.SpanKindNoneEnum
 }
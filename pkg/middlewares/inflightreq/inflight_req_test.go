@@ -0,0 +1,127 @@
+package inflightreq
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/testhelpers"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.InFlightReq
+		expectedError bool
+	}{
+		{
+			desc:   "default source criterion",
+			config: dynamic.InFlightReq{Amount: 1},
+		},
+		{
+			desc:   "global",
+			config: dynamic.InFlightReq{Amount: 1, Global: true},
+		},
+		{
+			desc: "global and source criterion",
+			config: dynamic.InFlightReq{
+				Amount:          1,
+				Global:          true,
+				SourceCriterion: &dynamic.SourceCriterion{RequestHost: true},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+			_, err := New(context.Background(), next, test.config, "inflightreq")
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestInFlightReq_ServeHTTP(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		config       dynamic.InFlightReq
+		host         string
+		expectedCode int
+	}{
+		{
+			desc:         "other source",
+			config:       dynamic.InFlightReq{Amount: 1},
+			host:         "other.localhost",
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "same source",
+			config:       dynamic.InFlightReq{Amount: 1},
+			host:         "localhost",
+			expectedCode: http.StatusTooManyRequests,
+		},
+		{
+			desc:         "global",
+			config:       dynamic.InFlightReq{Amount: 1, Global: true},
+			host:         "other.localhost",
+			expectedCode: http.StatusTooManyRequests,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			started := make(chan struct{})
+			release := make(chan struct{})
+
+			// The first request is in flight until released, the others are served at once.
+			var once sync.Once
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				first := false
+				once.Do(func() { first = true })
+
+				if first {
+					close(started)
+					<-release
+				}
+			})
+
+			handler, err := New(context.Background(), next, test.config, "inflightreq")
+			require.NoError(t, err)
+
+			done := make(chan int)
+			go func() {
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil))
+				done <- recorder.Code
+			}()
+
+			<-started
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, testhelpers.MustNewRequest(http.MethodGet, "http://"+test.host, nil))
+
+			close(release)
+
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			assert.Equal(t, http.StatusOK, <-done)
+		})
+	}
+}
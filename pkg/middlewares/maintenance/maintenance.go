@@ -0,0 +1,146 @@
+package maintenance
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/ip"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "Maintenance"
+)
+
+// maintenance is a middleware answering the requests with a static response while the maintenance is enabled.
+type maintenance struct {
+	next     http.Handler
+	name     string
+	enabled  bool
+	switches *Switches
+
+	statusCode int
+	headers    map[string]string
+	body       []byte
+
+	bypassChecker *ip.Checker
+	strategy      ip.Strategy
+	bypassHeaders map[string]string
+}
+
+// New creates a new maintenance middleware.
+// The maintenance state of the middleware can be switched at runtime with the given switches, if not nil.
+func New(ctx context.Context, next http.Handler, config dynamic.Maintenance, name string, switches *Switches) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	statusCode := config.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	if statusCode < 100 || statusCode > 999 {
+		return nil, fmt.Errorf("invalid status code: %d", statusCode)
+	}
+
+	if config.Body != "" && config.BodyFile != "" {
+		return nil, errors.New("body and bodyFile are mutually exclusive")
+	}
+
+	body := []byte(config.Body)
+	if config.BodyFile != "" {
+		var err error
+		body, err = os.ReadFile(config.BodyFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading the body file: %w", err)
+		}
+	}
+
+	m := &maintenance{
+		next:          next,
+		name:          name,
+		enabled:       config.Enabled,
+		switches:      switches,
+		statusCode:    statusCode,
+		headers:       config.Headers,
+		body:          body,
+		bypassHeaders: make(map[string]string, len(config.BypassHeaders)),
+	}
+
+	for header, value := range config.BypassHeaders {
+		m.bypassHeaders[http.CanonicalHeaderKey(header)] = value
+	}
+
+	if len(config.BypassSourceRange) > 0 {
+		checker, err := ip.NewChecker(config.BypassSourceRange)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse CIDR bypass source range %s: %w", config.BypassSourceRange, err)
+		}
+
+		strategy, err := config.IPStrategy.Get()
+		if err != nil {
+			return nil, err
+		}
+
+		m.bypassChecker = checker
+		m.strategy = strategy
+	}
+
+	return m, nil
+}
+
+func (m *maintenance) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return m.name, tracing.SpanKindNoneEnum
+}
+
+func (m *maintenance) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if !m.isEnabled() || m.bypass(req) {
+		m.next.ServeHTTP(rw, req)
+		return
+	}
+
+	for header, value := range m.headers {
+		rw.Header().Set(header, value)
+	}
+
+	rw.WriteHeader(m.statusCode)
+
+	if req.Method == http.MethodHead {
+		return
+	}
+
+	if _, err := rw.Write(m.body); err != nil {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), m.name, typeName)).Debugf("Error while writing the maintenance response: %v", err)
+	}
+}
+
+// isEnabled reports whether the maintenance is enabled, by its switch if any, or else by its configuration.
+func (m *maintenance) isEnabled() bool {
+	if m.switches != nil {
+		if enabled, ok := m.switches.enabled(m.name); ok {
+			return enabled
+		}
+	}
+
+	return m.enabled
+}
+
+// bypass reports whether the request is not affected by the maintenance.
+func (m *maintenance) bypass(req *http.Request) bool {
+	for header, value := range m.bypassHeaders {
+		// The header values are compared in constant time, as they are usually secrets.
+		actual := req.Header.Get(header)
+		if actual != "" && subtle.ConstantTimeCompare([]byte(actual), []byte(value)) == 1 {
+			return true
+		}
+	}
+
+	return m.bypassChecker != nil && m.bypassChecker.IsAuthorized(m.strategy.GetIP(req)) == nil
+}
@@ -0,0 +1,224 @@
+package maintenance
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      dynamic.Maintenance
+		expectedErr bool
+	}{
+		{
+			desc:   "default configuration",
+			config: dynamic.Maintenance{},
+		},
+		{
+			desc:        "invalid status code",
+			config:      dynamic.Maintenance{StatusCode: 42},
+			expectedErr: true,
+		},
+		{
+			desc:        "body and body file",
+			config:      dynamic.Maintenance{Body: "foo", BodyFile: "foo.html"},
+			expectedErr: true,
+		},
+		{
+			desc:        "missing body file",
+			config:      dynamic.Maintenance{BodyFile: "missing.html"},
+			expectedErr: true,
+		},
+		{
+			desc:        "invalid bypass source range",
+			config:      dynamic.Maintenance{BypassSourceRange: []string{"foo"}},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+			_, err := New(context.Background(), next, test.config, "maintenance", nil)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestMaintenance_ServeHTTP(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		config         dynamic.Maintenance
+		remoteAddr     string
+		header         http.Header
+		expectedCode   int
+		expectedBody   string
+		expectedHeader http.Header
+	}{
+		{
+			desc:         "maintenance disabled",
+			config:       dynamic.Maintenance{Body: "maintenance"},
+			expectedCode: http.StatusOK,
+			expectedBody: "next",
+		},
+		{
+			desc:         "maintenance enabled",
+			config:       dynamic.Maintenance{Enabled: true, Body: "maintenance"},
+			expectedCode: http.StatusServiceUnavailable,
+			expectedBody: "maintenance",
+		},
+		{
+			desc: "maintenance enabled with custom status code and headers",
+			config: dynamic.Maintenance{
+				Enabled:    true,
+				StatusCode: http.StatusTeapot,
+				Headers:    map[string]string{"Retry-After": "3600"},
+			},
+			expectedCode:   http.StatusTeapot,
+			expectedHeader: http.Header{"Retry-After": {"3600"}},
+		},
+		{
+			desc:         "client IP in the bypass source range",
+			config:       dynamic.Maintenance{Enabled: true, BypassSourceRange: []string{"10.0.0.0/8"}},
+			remoteAddr:   "10.0.0.1:1234",
+			expectedCode: http.StatusOK,
+			expectedBody: "next",
+		},
+		{
+			desc:         "client IP out of the bypass source range",
+			config:       dynamic.Maintenance{Enabled: true, BypassSourceRange: []string{"10.0.0.0/8"}},
+			remoteAddr:   "192.168.0.1:1234",
+			expectedCode: http.StatusServiceUnavailable,
+		},
+		{
+			desc: "client IP in the bypass source range according to the IP strategy",
+			config: dynamic.Maintenance{
+				Enabled:           true,
+				BypassSourceRange: []string{"10.0.0.0/8"},
+				IPStrategy:        &dynamic.IPStrategy{Depth: 1},
+			},
+			remoteAddr:   "192.168.0.1:1234",
+			header:       http.Header{"X-Forwarded-For": {"10.0.0.1"}},
+			expectedCode: http.StatusOK,
+			expectedBody: "next",
+		},
+		{
+			desc:         "bypass header",
+			config:       dynamic.Maintenance{Enabled: true, BypassHeaders: map[string]string{"x-maintenance-bypass": "secret"}},
+			header:       http.Header{"X-Maintenance-Bypass": {"secret"}},
+			expectedCode: http.StatusOK,
+			expectedBody: "next",
+		},
+		{
+			desc:         "wrong bypass header",
+			config:       dynamic.Maintenance{Enabled: true, BypassHeaders: map[string]string{"X-Maintenance-Bypass": "secret"}},
+			header:       http.Header{"X-Maintenance-Bypass": {"guess"}},
+			expectedCode: http.StatusServiceUnavailable,
+		},
+		{
+			desc:         "missing bypass header with an empty value",
+			config:       dynamic.Maintenance{Enabled: true, BypassHeaders: map[string]string{"X-Maintenance-Bypass": ""}},
+			expectedCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				_, _ = rw.Write([]byte("next"))
+			})
+
+			handler, err := New(context.Background(), next, test.config, "maintenance", nil)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			if test.remoteAddr != "" {
+				req.RemoteAddr = test.remoteAddr
+			}
+			for k, v := range test.header {
+				req.Header[k] = v
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+
+			for k, v := range test.expectedHeader {
+				assert.Equal(t, v, recorder.Header()[k])
+			}
+		})
+	}
+}
+
+func TestMaintenance_bodyFile(t *testing.T) {
+	bodyFile := filepath.Join(t.TempDir(), "maintenance.html")
+	err := os.WriteFile(bodyFile, []byte("<h1>Back soon</h1>"), 0o600)
+	require.NoError(t, err)
+
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+	handler, err := New(context.Background(), next, dynamic.Maintenance{Enabled: true, BodyFile: bodyFile}, "maintenance", nil)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, "<h1>Back soon</h1>", recorder.Body.String())
+}
+
+func TestMaintenance_switches(t *testing.T) {
+	switches := NewSwitches()
+
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+	handler, err := New(context.Background(), next, dynamic.Maintenance{}, "maintenance@file", switches)
+	require.NoError(t, err)
+
+	serve := func() int {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve())
+
+	sw := switches.Set("maintenance@file", true, "127.0.0.1")
+	assert.True(t, sw.Enabled)
+	assert.Equal(t, http.StatusServiceUnavailable, serve())
+
+	switches.Set("maintenance@file", false, "127.0.0.1")
+	assert.Equal(t, http.StatusOK, serve())
+
+	list := switches.List()
+	require.Len(t, list, 1)
+	assert.Equal(t, "maintenance@file", list[0].Middleware)
+	assert.False(t, list[0].Enabled)
+
+	require.NoError(t, switches.Reset("maintenance@file", "127.0.0.1"))
+	assert.Empty(t, switches.List())
+	assert.ErrorIs(t, switches.Reset("maintenance@file", "127.0.0.1"), ErrSwitchNotFound)
+}
@@ -0,0 +1,97 @@
+package maintenance
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// ErrSwitchNotFound is returned when resetting a maintenance which has not been switched.
+var ErrSwitchNotFound = errors.New("maintenance switch not found")
+
+// Switch is the maintenance state of a maintenance middleware, switched at runtime.
+type Switch struct {
+	Middleware string    `json:"middleware"`
+	Enabled    bool      `json:"enabled"`
+	Requester  string    `json:"requester,omitempty"`
+	Since      time.Time `json:"since"`
+}
+
+// Switches holds the maintenance states of the maintenance middlewares switched at runtime,
+// which override their configured state until they are reset.
+// The switches are kept across the configuration reloads, and their changes are logged.
+type Switches struct {
+	mu       sync.RWMutex
+	switches map[string]Switch
+}
+
+// NewSwitches creates a new Switches.
+func NewSwitches() *Switches {
+	return &Switches{switches: make(map[string]Switch)}
+}
+
+// Set enables or disables the maintenance of the given middleware, whatever its configuration.
+func (s *Switches) Set(middlewareName string, enabled bool, requester string) Switch {
+	sw := Switch{
+		Middleware: middlewareName,
+		Enabled:    enabled,
+		Requester:  requester,
+		Since:      time.Now(),
+	}
+
+	s.mu.Lock()
+	s.switches[middlewareName] = sw
+	s.mu.Unlock()
+
+	if enabled {
+		log.WithoutContext().Warnf("Maintenance of middleware %q enabled by %q", middlewareName, requester)
+	} else {
+		log.WithoutContext().Warnf("Maintenance of middleware %q disabled by %q", middlewareName, requester)
+	}
+
+	return sw
+}
+
+// Reset restores the configured maintenance state of the given middleware.
+func (s *Switches) Reset(middlewareName, requester string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.switches[middlewareName]; !ok {
+		return ErrSwitchNotFound
+	}
+
+	delete(s.switches, middlewareName)
+
+	log.WithoutContext().Warnf("Maintenance of middleware %q reset to its configuration by %q", middlewareName, requester)
+
+	return nil
+}
+
+// List returns the current switches, sorted by middleware.
+func (s *Switches) List() []Switch {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	switches := make([]Switch, 0, len(s.switches))
+	for _, sw := range s.switches {
+		switches = append(switches, sw)
+	}
+
+	sort.Slice(switches, func(i, j int) bool { return switches[i].Middleware < switches[j].Middleware })
+
+	return switches
+}
+
+// enabled returns the maintenance state of the given middleware, and whether it has been switched.
+func (s *Switches) enabled(middlewareName string) (bool, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sw, ok := s.switches[middlewareName]
+
+	return sw.Enabled, ok
+}
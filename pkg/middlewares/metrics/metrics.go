@@ -2,9 +2,11 @@ package metrics
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 	"unicode/utf8"
 
@@ -32,6 +34,8 @@ type metricsMiddleware struct {
 	reqsTLSCounter       gokitmetrics.Counter
 	reqDurationHistogram metrics.ScalableHistogram
 	openConnsGauge       gokitmetrics.Gauge
+	reqsBytesCounter     gokitmetrics.Counter
+	respsBytesCounter    gokitmetrics.Counter
 	baseLabels           []string
 }
 
@@ -59,6 +63,8 @@ func NewRouterMiddleware(ctx context.Context, next http.Handler, registry metric
 		reqsTLSCounter:       registry.RouterReqsTLSCounter(),
 		reqDurationHistogram: registry.RouterReqDurationHistogram(),
 		openConnsGauge:       registry.RouterOpenConnsGauge(),
+		reqsBytesCounter:     registry.RouterReqsBytesCounter(),
+		respsBytesCounter:    registry.RouterRespsBytesCounter(),
 		baseLabels:           []string{"router", routerName, "service", serviceName},
 	}
 }
@@ -115,6 +121,12 @@ func (m *metricsMiddleware) ServeHTTP(rw http.ResponseWriter, req *http.Request)
 		m.reqsTLSCounter.With(tlsLabels...).Add(1)
 	}
 
+	var body *countingReader
+	if m.reqsBytesCounter != nil && req.Body != nil && req.Body != http.NoBody {
+		body = &countingReader{ReadCloser: req.Body}
+		req.Body = body
+	}
+
 	recorder := newResponseRecorder(rw)
 	start := time.Now()
 
@@ -122,10 +134,75 @@ func (m *metricsMiddleware) ServeHTTP(rw http.ResponseWriter, req *http.Request)
 
 	labels = append(labels, "code", strconv.Itoa(recorder.getCode()))
 
+	// gRPC errors are sent with a 200 status code, the actual status being in the grpc-status trailer.
+	if middlewares.IsGRPCRequest(req) {
+		if grpcCode := middlewares.GetGRPCStatus(recorder.Header()); grpcCode != "" {
+			labels = append(labels, "grpc_code", grpcCode)
+		}
+	}
+
 	histograms := m.reqDurationHistogram.With(labels...)
 	histograms.ObserveFromStart(start)
 
 	m.reqsCounter.With(labels...).Add(1)
+
+	if m.reqsBytesCounter != nil {
+		m.reqsBytesCounter.With(labels...).Add(float64(requestHeaderSize(req) + body.size()))
+	}
+
+	if m.respsBytesCounter != nil {
+		m.respsBytesCounter.With(labels...).Add(float64(responseHeaderSize(recorder.getCode(), recorder.Header()) + recorder.getSize()))
+	}
+}
+
+// countingReader counts the bytes read from a request body.
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	atomic.AddInt64(&r.n, int64(n))
+	return n, err
+}
+
+func (r *countingReader) size() int64 {
+	if r == nil {
+		return 0
+	}
+	return atomic.LoadInt64(&r.n)
+}
+
+// requestHeaderSize returns the size of the request line and of the headers of the request, as sent in HTTP/1.1.
+func requestHeaderSize(req *http.Request) int64 {
+	// Request line, Host header, and final empty line.
+	size := len(req.Method) + len(req.RequestURI) + len("  HTTP/1.1\r\n") + len("Host: \r\n") + len(req.Host) + len("\r\n")
+
+	return int64(size + headerSize(req.Header))
+}
+
+// responseHeaderSize returns the size of the status line and of the headers of a response, as sent in HTTP/1.1.
+func responseHeaderSize(code int, header http.Header) int64 {
+	// Status line, and final empty line.
+	size := len("HTTP/1.1 000 \r\n") + len(http.StatusText(code)) + len("\r\n")
+
+	return int64(size + headerSize(header))
+}
+
+func headerSize(header http.Header) int {
+	var size int
+	for name, values := range header {
+		if strings.HasPrefix(name, http.TrailerPrefix) {
+			continue
+		}
+
+		for _, value := range values {
+			size += len(name) + len(": \r\n") + len(value)
+		}
+	}
+
+	return size
 }
 
 func getRequestProtocol(req *http.Request) string {
@@ -1,13 +1,19 @@
 package metrics
 
 import (
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	traefikmetrics "github.com/traefik/traefik/v2/pkg/metrics"
 )
 
 // CollectingCounter is a metrics.Counter implementation that enables access to the CounterValue and LastLabelValues.
@@ -98,3 +104,100 @@ func TestCloseNotifier(t *testing.T) {
 		})
 	}
 }
+
+func TestMetricsMiddleware_gRPCStatus(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		contentType    string
+		grpcStatus     string
+		expectedLabels []string
+	}{
+		{
+			desc:           "HTTP request",
+			expectedLabels: []string{"service", "foo", "method", http.MethodPost, "protocol", "http", "code", "200"},
+		},
+		{
+			desc:           "gRPC request",
+			contentType:    "application/grpc",
+			grpcStatus:     "5",
+			expectedLabels: []string{"service", "foo", "method", http.MethodPost, "protocol", "http", "code", "200", "grpc_code", "5"},
+		},
+		{
+			desc:           "gRPC request without status",
+			contentType:    "application/grpc+proto",
+			expectedLabels: []string{"service", "foo", "method", http.MethodPost, "protocol", "http", "code", "200"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			histogram, err := traefikmetrics.NewHistogramWithScale(discard.NewHistogram(), time.Second)
+			require.NoError(t, err)
+
+			reqsCounter := &CollectingCounter{}
+			handler := &metricsMiddleware{
+				next: http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+					rw.WriteHeader(http.StatusOK)
+					if test.grpcStatus != "" {
+						rw.Header().Set(http.TrailerPrefix+"Grpc-Status", test.grpcStatus)
+					}
+				}),
+				reqsCounter:          reqsCounter,
+				reqsTLSCounter:       discard.NewCounter(),
+				reqDurationHistogram: histogram,
+				openConnsGauge:       discard.NewGauge(),
+				baseLabels:           []string{"service", "foo"},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "http://foo.bar/", nil)
+			if test.contentType != "" {
+				req.Header.Set("Content-Type", test.contentType)
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, test.expectedLabels, reqsCounter.LastLabelValues)
+			assert.Equal(t, float64(1), reqsCounter.CounterValue)
+		})
+	}
+}
+
+func TestMetricsMiddleware_bytes(t *testing.T) {
+	histogram, err := traefikmetrics.NewHistogramWithScale(discard.NewHistogram(), time.Second)
+	require.NoError(t, err)
+
+	reqsBytesCounter := &CollectingCounter{}
+	respsBytesCounter := &CollectingCounter{}
+	handler := &metricsMiddleware{
+		next: http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			_, _ = io.Copy(io.Discard, req.Body)
+
+			rw.Header().Set("Content-Type", "text/plain")
+			rw.WriteHeader(http.StatusCreated)
+			_, _ = rw.Write([]byte("created"))
+		}),
+		reqsCounter:          discard.NewCounter(),
+		reqsTLSCounter:       discard.NewCounter(),
+		reqDurationHistogram: histogram,
+		openConnsGauge:       discard.NewGauge(),
+		reqsBytesCounter:     reqsBytesCounter,
+		respsBytesCounter:    respsBytesCounter,
+		baseLabels:           []string{"router", "foo", "service", "bar"},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "http://foo.bar/baz", strings.NewReader("hello"))
+	req.RequestURI = "/baz"
+	req.Header.Set("Content-Type", "text/plain")
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	expectedRequest := "POST /baz HTTP/1.1\r\nHost: foo.bar\r\nContent-Type: text/plain\r\n\r\nhello"
+	expectedResponse := "HTTP/1.1 201 Created\r\nContent-Type: text/plain\r\n\r\ncreated"
+
+	assert.Equal(t, []string{"router", "foo", "service", "bar", "method", http.MethodPost, "protocol", "http", "code", "201"}, reqsBytesCounter.LastLabelValues)
+	assert.Equal(t, float64(len(expectedRequest)), reqsBytesCounter.CounterValue)
+	assert.Equal(t, float64(len(expectedResponse)), respsBytesCounter.CounterValue)
+}
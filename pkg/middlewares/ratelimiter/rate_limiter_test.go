@@ -329,3 +329,45 @@ func computeMinCount(wantCount int) int {
 
 	return wantCount * 95 / 100
 }
+
+func TestRateLimiter_serveDelayError(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		delay              time.Duration
+		expectedRetryAfter string
+	}{
+		{
+			desc:               "less than a second",
+			delay:              200 * time.Millisecond,
+			expectedRetryAfter: "1",
+		},
+		{
+			desc:               "whole seconds",
+			delay:              2 * time.Second,
+			expectedRetryAfter: "2",
+		},
+		{
+			desc:               "more than a second",
+			delay:              2100 * time.Millisecond,
+			expectedRetryAfter: "3",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rl := &rateLimiter{}
+
+			recorder := httptest.NewRecorder()
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+
+			rl.serveDelayError(context.Background(), recorder, req, test.delay)
+
+			assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+			assert.Equal(t, test.expectedRetryAfter, recorder.Header().Get("Retry-After"))
+			assert.Equal(t, test.delay.String(), recorder.Header().Get("X-Retry-In"))
+		})
+	}
+}
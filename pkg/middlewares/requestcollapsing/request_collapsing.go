@@ -0,0 +1,261 @@
+package requestcollapsing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "RequestCollapsing"
+)
+
+// call is an in-flight request to the backend, whose response is shared with the identical requests.
+type call struct {
+	done chan struct{}
+
+	// The fields below are written before done is closed, and only read after.
+	shared bool
+	code   int
+	header http.Header
+	body   []byte
+}
+
+type requestCollapsing struct {
+	next                 http.Handler
+	name                 string
+	keyHeaders           []string
+	ignoreQuery          bool
+	maxResponseBodyBytes int64
+
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+// New creates a request collapsing middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.RequestCollapsing, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if config.MaxResponseBodyBytes <= 0 {
+		return nil, errors.New("the maximum size of the response body must be greater than zero")
+	}
+
+	keyHeaders := make([]string, 0, len(config.KeyHeaders))
+	for _, header := range config.KeyHeaders {
+		keyHeaders = append(keyHeaders, http.CanonicalHeaderKey(header))
+	}
+
+	return &requestCollapsing{
+		next:                 next,
+		name:                 name,
+		keyHeaders:           keyHeaders,
+		ignoreQuery:          config.IgnoreQuery,
+		maxResponseBodyBytes: config.MaxResponseBodyBytes,
+		calls:                make(map[string]*call),
+	}, nil
+}
+
+func (r *requestCollapsing) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return r.name, tracing.SpanKindNoneEnum
+}
+
+func (r *requestCollapsing) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	key, ok := r.key(req)
+	if !ok {
+		r.next.ServeHTTP(rw, req)
+		return
+	}
+
+	r.mu.Lock()
+	if c, exists := r.calls[key]; exists {
+		r.mu.Unlock()
+		r.wait(rw, req, c)
+		return
+	}
+
+	c := &call{done: make(chan struct{})}
+	r.calls[key] = c
+	r.mu.Unlock()
+
+	r.do(rw, req, key, c)
+}
+
+// key returns the key identifying the identical requests,
+// and whether the request can be collapsed with them.
+func (r *requestCollapsing) key(req *http.Request) (string, bool) {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return "", false
+	}
+
+	// Requests carrying credentials are only collapsed with requests carrying the same credentials.
+	for _, header := range []string{"Authorization", "Cookie"} {
+		if req.Header.Get(header) != "" && !r.isKeyHeader(header) {
+			return "", false
+		}
+	}
+
+	var key strings.Builder
+	key.WriteString(req.Host)
+	key.WriteByte('\n')
+
+	if r.ignoreQuery {
+		key.WriteString(req.URL.EscapedPath())
+	} else {
+		key.WriteString(req.URL.RequestURI())
+	}
+
+	for _, header := range r.keyHeaders {
+		key.WriteByte('\n')
+		key.WriteString(strings.Join(req.Header.Values(header), ","))
+	}
+
+	return key.String(), true
+}
+
+func (r *requestCollapsing) isKeyHeader(header string) bool {
+	for _, keyHeader := range r.keyHeaders {
+		if keyHeader == header {
+			return true
+		}
+	}
+
+	return false
+}
+
+// do forwards the request to the backend, and shares its response with the identical requests received in the meantime.
+// The response is not shared when its body is too large to be buffered, when the next handler panics,
+// or when the client has gone away, in which case the identical requests are forwarded on their own.
+func (r *requestCollapsing) do(rw http.ResponseWriter, req *http.Request, key string, c *call) {
+	recorder := &responseRecorder{
+		rw:      rw,
+		header:  make(http.Header),
+		maxSize: r.maxResponseBodyBytes,
+	}
+
+	var completed bool
+	defer func() {
+		r.mu.Lock()
+		delete(r.calls, key)
+		r.mu.Unlock()
+
+		c.shared = completed && !recorder.overflowed && req.Context().Err() == nil
+		if c.shared {
+			c.code = recorder.statusCode()
+			c.header = recorder.header
+			c.body = recorder.body.Bytes()
+		}
+
+		close(c.done)
+	}()
+
+	r.next.ServeHTTP(recorder, req)
+	completed = true
+
+	if recorder.overflowed {
+		return
+	}
+
+	writeResponse(rw, recorder.statusCode(), recorder.header, recorder.body.Bytes())
+}
+
+// wait waits for the response of the given in-flight request, and sends it to the client.
+func (r *requestCollapsing) wait(rw http.ResponseWriter, req *http.Request, c *call) {
+	select {
+	case <-c.done:
+	case <-req.Context().Done():
+		return
+	}
+
+	if !c.shared {
+		r.next.ServeHTTP(rw, req)
+		return
+	}
+
+	log.FromContext(req.Context()).Debugf("Sending the response of an identical in-flight request for %s", req.URL.Path)
+
+	writeResponse(rw, c.code, c.header, c.body)
+}
+
+func writeResponse(rw http.ResponseWriter, code int, header http.Header, body []byte) {
+	for k, v := range header {
+		rw.Header()[k] = append([]string(nil), v...)
+	}
+
+	rw.WriteHeader(code)
+
+	if _, err := rw.Write(body); err != nil {
+		log.WithoutContext().Debugf("Error while writing the collapsed response: %v", err)
+	}
+}
+
+// responseRecorder buffers the response in memory,
+// until its body exceeds the maximum size, in which case the response is streamed to the client.
+type responseRecorder struct {
+	rw      http.ResponseWriter
+	header  http.Header
+	code    int
+	body    bytes.Buffer
+	maxSize int64
+
+	overflowed bool
+}
+
+func (r *responseRecorder) Header() http.Header {
+	if r.overflowed {
+		return r.rw.Header()
+	}
+
+	return r.header
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	if r.overflowed {
+		r.rw.WriteHeader(code)
+		return
+	}
+
+	if r.code == 0 {
+		r.code = code
+	}
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	if r.overflowed {
+		return r.rw.Write(p)
+	}
+
+	if int64(r.body.Len()+len(p)) <= r.maxSize {
+		return r.body.Write(p)
+	}
+
+	r.overflowed = true
+
+	for k, v := range r.header {
+		r.rw.Header()[k] = v
+	}
+
+	r.rw.WriteHeader(r.statusCode())
+
+	if _, err := r.rw.Write(r.body.Bytes()); err != nil {
+		return 0, err
+	}
+
+	return r.rw.Write(p)
+}
+
+func (r *responseRecorder) statusCode() int {
+	if r.code == 0 {
+		return http.StatusOK
+	}
+
+	return r.code
+}
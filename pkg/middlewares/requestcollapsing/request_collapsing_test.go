@@ -0,0 +1,176 @@
+package requestcollapsing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	_, err := New(context.Background(), http.NotFoundHandler(), dynamic.RequestCollapsing{}, "collapsing")
+	require.Error(t, err)
+}
+
+func TestRequestCollapsing_key(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      dynamic.RequestCollapsing
+		method      string
+		url         string
+		headers     map[string]string
+		expectedKey string
+		expectedOk  bool
+	}{
+		{
+			desc:        "GET request",
+			method:      http.MethodGet,
+			url:         "http://foo.com/bar?baz=1",
+			expectedKey: "foo.com\n/bar?baz=1",
+			expectedOk:  true,
+		},
+		{
+			desc:        "query ignored",
+			config:      dynamic.RequestCollapsing{IgnoreQuery: true},
+			method:      http.MethodGet,
+			url:         "http://foo.com/bar?baz=1",
+			expectedKey: "foo.com\n/bar",
+			expectedOk:  true,
+		},
+		{
+			desc:        "key headers",
+			config:      dynamic.RequestCollapsing{KeyHeaders: []string{"accept-encoding", "X-Tenant"}},
+			method:      http.MethodGet,
+			url:         "http://foo.com/bar",
+			headers:     map[string]string{"Accept-Encoding": "gzip"},
+			expectedKey: "foo.com\n/bar\ngzip\n",
+			expectedOk:  true,
+		},
+		{
+			desc:   "POST request",
+			method: http.MethodPost,
+			url:    "http://foo.com/bar",
+		},
+		{
+			desc:    "range request",
+			method:  http.MethodGet,
+			url:     "http://foo.com/bar",
+			headers: map[string]string{"Range": "bytes=0-42"},
+		},
+		{
+			desc:    "request with credentials",
+			method:  http.MethodGet,
+			url:     "http://foo.com/bar",
+			headers: map[string]string{"Authorization": "Bearer secret"},
+		},
+		{
+			desc:        "request with credentials in the key",
+			config:      dynamic.RequestCollapsing{KeyHeaders: []string{"Authorization"}},
+			method:      http.MethodGet,
+			url:         "http://foo.com/bar",
+			headers:     map[string]string{"Authorization": "Bearer secret"},
+			expectedKey: "foo.com\n/bar\nBearer secret",
+			expectedOk:  true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			test.config.MaxResponseBodyBytes = 1024
+
+			handler, err := New(context.Background(), http.NotFoundHandler(), test.config, "collapsing")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(test.method, test.url, nil)
+			for k, v := range test.headers {
+				req.Header.Set(k, v)
+			}
+
+			key, ok := handler.(*requestCollapsing).key(req)
+			assert.Equal(t, test.expectedOk, ok)
+			assert.Equal(t, test.expectedKey, key)
+		})
+	}
+}
+
+func TestRequestCollapsing_ServeHTTP(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		body          string
+		expectedCalls int32
+	}{
+		{
+			desc:          "response shared",
+			body:          "foo",
+			expectedCalls: 1,
+		},
+		{
+			desc:          "response too large to be shared",
+			body:          strings.Repeat("foo", 10),
+			expectedCalls: 3,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int32
+			release := make(chan struct{})
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if atomic.AddInt32(&calls, 1) == 1 {
+					<-release
+				}
+
+				rw.Header().Set("X-Foo", "bar")
+				rw.WriteHeader(http.StatusAccepted)
+				_, _ = rw.Write([]byte(test.body))
+			})
+
+			handler, err := New(context.Background(), next, dynamic.RequestCollapsing{MaxResponseBodyBytes: 10}, "collapsing")
+			require.NoError(t, err)
+
+			const clients = 3
+
+			var wg sync.WaitGroup
+			recorders := make([]*httptest.ResponseRecorder, clients)
+			for i := range recorders {
+				recorders[i] = httptest.NewRecorder()
+
+				wg.Add(1)
+				go func(recorder *httptest.ResponseRecorder) {
+					defer wg.Done()
+					handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo.com/bar", nil))
+				}(recorders[i])
+			}
+
+			// Waits for the identical requests to wait for the in-flight one.
+			assert.Eventually(t, func() bool {
+				return atomic.LoadInt32(&calls) == 1
+			}, time.Second, time.Millisecond)
+			time.Sleep(50 * time.Millisecond)
+
+			close(release)
+			wg.Wait()
+
+			assert.Equal(t, test.expectedCalls, atomic.LoadInt32(&calls))
+			for _, recorder := range recorders {
+				assert.Equal(t, http.StatusAccepted, recorder.Code)
+				assert.Equal(t, "bar", recorder.Header().Get("X-Foo"))
+				assert.Equal(t, test.body, recorder.Body.String())
+			}
+		})
+	}
+}
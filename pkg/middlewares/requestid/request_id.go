@@ -0,0 +1,87 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName          = "RequestID"
+	defaultHeaderName = "X-Request-Id"
+)
+
+// requestID is a middleware that sets a unique ID on each request.
+type requestID struct {
+	next       http.Handler
+	name       string
+	headerName string
+	overwrite  bool
+}
+
+// New creates a new request ID middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.RequestID, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	headerName := defaultHeaderName
+	if config.HeaderName != "" {
+		headerName = http.CanonicalHeaderKey(config.HeaderName)
+	}
+
+	return &requestID{
+		next:       next,
+		name:       name,
+		headerName: headerName,
+		overwrite:  config.Overwrite,
+	}, nil
+}
+
+func (r *requestID) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return r.name, tracing.SpanKindNoneEnum
+}
+
+func (r *requestID) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	id := req.Header.Get(r.headerName)
+	if id == "" || r.overwrite {
+		var err error
+		id, err = newID()
+		if err != nil {
+			log.FromContext(middlewares.GetLoggerCtx(req.Context(), r.name, typeName)).Errorf("Error while generating the request ID: %v", err)
+			r.next.ServeHTTP(rw, req)
+			return
+		}
+
+		req.Header.Set(r.headerName, id)
+	}
+
+	if logData := accesslog.GetLogData(req); logData != nil {
+		logData.Core[accesslog.RequestID] = id
+	}
+
+	if span := tracing.GetSpan(req); span != nil {
+		span.SetTag("http.request_id", id)
+	}
+
+	r.next.ServeHTTP(newResponseWriter(rw, r.headerName, id), req)
+}
+
+// newID returns a random (version 4) UUID.
+func newID() (string, error) {
+	var uuid [16]byte
+	if _, err := rand.Read(uuid[:]); err != nil {
+		return "", err
+	}
+
+	uuid[6] = uuid[6]&0x0f | 0x40
+	uuid[8] = uuid[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:]), nil
+}
@@ -0,0 +1,113 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+)
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestRequestID(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		config             dynamic.RequestID
+		incomingHeaders    map[string]string
+		backendHeaders     map[string]string
+		expectedHeaderName string
+		expectedID         string
+	}{
+		{
+			desc:               "generated ID",
+			expectedHeaderName: "X-Request-Id",
+		},
+		{
+			desc:               "incoming ID kept",
+			incomingHeaders:    map[string]string{"X-Request-Id": "foo"},
+			expectedHeaderName: "X-Request-Id",
+			expectedID:         "foo",
+		},
+		{
+			desc:               "incoming ID overwritten",
+			config:             dynamic.RequestID{Overwrite: true},
+			incomingHeaders:    map[string]string{"X-Request-Id": "foo"},
+			expectedHeaderName: "X-Request-Id",
+		},
+		{
+			desc:               "custom header name",
+			config:             dynamic.RequestID{HeaderName: "x-correlation-id"},
+			incomingHeaders:    map[string]string{"X-Request-Id": "foo"},
+			expectedHeaderName: "X-Correlation-Id",
+		},
+		{
+			desc:               "backend ID replaced in the response",
+			incomingHeaders:    map[string]string{"X-Request-Id": "foo"},
+			backendHeaders:     map[string]string{"X-Request-Id": "bar"},
+			expectedHeaderName: "X-Request-Id",
+			expectedID:         "foo",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var forwardedID string
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				forwardedID = req.Header.Get(test.expectedHeaderName)
+
+				for k, v := range test.backendHeaders {
+					rw.Header().Set(k, v)
+				}
+				_, _ = rw.Write([]byte("ok"))
+			})
+
+			handler, err := New(context.Background(), next, test.config, "requestID")
+			require.NoError(t, err)
+
+			logData := &accesslog.LogData{Core: accesslog.CoreLogData{}}
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			req = req.WithContext(context.WithValue(req.Context(), accesslog.DataTableKey, logData))
+			for k, v := range test.incomingHeaders {
+				req.Header.Set(k, v)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			if test.expectedID != "" {
+				assert.Equal(t, test.expectedID, forwardedID)
+			} else {
+				assert.Regexp(t, uuidRegexp, forwardedID)
+			}
+
+			assert.Equal(t, []string{forwardedID}, recorder.Header().Values(test.expectedHeaderName))
+			assert.Equal(t, forwardedID, logData.Core[accesslog.RequestID])
+		})
+	}
+}
+
+func TestRequestID_unique(t *testing.T) {
+	ids := make(map[string]struct{})
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		ids[req.Header.Get("X-Request-Id")] = struct{}{}
+	})
+
+	handler, err := New(context.Background(), next, dynamic.RequestID{}, "requestID")
+	require.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+	}
+
+	assert.Len(t, ids, 100)
+}
@@ -0,0 +1,71 @@
+package requestid
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// responseWriter sets the request ID header on the response, overriding the one sent by the backend, if any.
+type responseWriter struct {
+	rw         http.ResponseWriter
+	headerName string
+	id         string
+
+	headersSent bool
+}
+
+func newResponseWriter(rw http.ResponseWriter, headerName, id string) *responseWriter {
+	return &responseWriter{
+		rw:         rw,
+		headerName: headerName,
+		id:         id,
+	}
+}
+
+func (r *responseWriter) Header() http.Header {
+	return r.rw.Header()
+}
+
+func (r *responseWriter) WriteHeader(code int) {
+	if !r.headersSent {
+		r.rw.Header().Set(r.headerName, r.id)
+		r.headersSent = true
+	}
+
+	r.rw.WriteHeader(code)
+}
+
+func (r *responseWriter) Write(b []byte) (int, error) {
+	if !r.headersSent {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	return r.rw.Write(b)
+}
+
+// Hijack hijacks the connection.
+func (r *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := r.rw.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+
+	return nil, nil, fmt.Errorf("not a hijacker: %T", r.rw)
+}
+
+// Flush sends any buffered data to the client.
+func (r *responseWriter) Flush() {
+	if !r.headersSent {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if flusher, ok := r.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// CloseNotify implements http.CloseNotifier.
+func (r *responseWriter) CloseNotify() <-chan bool {
+	return r.rw.(http.CloseNotifier).CloseNotify()
+}
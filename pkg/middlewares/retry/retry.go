@@ -17,6 +17,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/middlewares"
 	"github.com/traefik/traefik/v2/pkg/tracing"
+	"github.com/traefik/traefik/v2/pkg/types"
 )
 
 // Compile time validation that the response writer implements http interfaces correctly.
@@ -46,6 +47,8 @@ type nexter interface {
 type retry struct {
 	attempts        int
 	initialInterval time.Duration
+	status          types.HTTPCodeRanges
+	idempotentOnly  bool
 	next            http.Handler
 	listener        Listener
 	name            string
@@ -59,9 +62,16 @@ func New(ctx context.Context, next http.Handler, config dynamic.Retry, listener
 		return nil, fmt.Errorf("incorrect (or empty) value for attempt (%d)", config.Attempts)
 	}
 
+	status, err := types.NewHTTPCodeRanges(config.Status)
+	if err != nil {
+		return nil, fmt.Errorf("invalid status code ranges: %w", err)
+	}
+
 	return &retry{
 		attempts:        config.Attempts,
 		initialInterval: time.Duration(config.InitialInterval),
+		status:          status,
+		idempotentOnly:  config.IdempotentOnly,
 		next:            next,
 		listener:        listener,
 		name:            name,
@@ -81,6 +91,15 @@ func (r *retry) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		req.Body = io.NopCloser(body)
 	}
 
+	canRetry := !r.idempotentOnly || isIdempotent(req.Method)
+
+	// The requests with a body are not retried once they reached the backend,
+	// as their body cannot be sent again.
+	var retryStatus types.HTTPCodeRanges
+	if req.ContentLength == 0 {
+		retryStatus = r.status
+	}
+
 	attempts := 1
 	backOff := r.newBackOff()
 	currentInterval := 0 * time.Millisecond
@@ -88,16 +107,17 @@ func (r *retry) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		select {
 		case <-time.After(currentInterval):
 
-			shouldRetry := attempts < r.attempts
-			retryResponseWriter := newResponseWriter(rw, shouldRetry)
+			shouldRetry := canRetry && attempts < r.attempts
+			retryResponseWriter := newResponseWriter(rw, shouldRetry, retryStatus)
 
-			// Disable retries when the backend already received request data
+			// Disable retries when the backend already received request data,
+			// unless the response status code is one to retry.
 			trace := &httptrace.ClientTrace{
 				WroteHeaders: func() {
-					retryResponseWriter.DisableRetries()
+					retryResponseWriter.RequestSent()
 				},
 				WroteRequest: func(httptrace.WroteRequestInfo) {
-					retryResponseWriter.DisableRetries()
+					retryResponseWriter.RequestSent()
 				},
 			}
 			newCtx := httptrace.WithClientTrace(req.Context(), trace)
@@ -141,6 +161,16 @@ func (r *retry) newBackOff() nexter {
 	return b
 }
 
+// isIdempotent reports whether the method is idempotent, as defined in RFC 7231.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
 // Retried exists to implement the Listener interface. It calls Retried on each of its slice entries.
 func (l Listeners) Retried(req *http.Request, attempt int) {
 	for _, listener := range l {
@@ -153,13 +183,15 @@ type responseWriter interface {
 	http.Flusher
 	ShouldRetry() bool
 	DisableRetries()
+	RequestSent()
 }
 
-func newResponseWriter(rw http.ResponseWriter, shouldRetry bool) responseWriter {
+func newResponseWriter(rw http.ResponseWriter, shouldRetry bool, retryStatus types.HTTPCodeRanges) responseWriter {
 	responseWriter := &responseWriterWithoutCloseNotify{
 		responseWriter: rw,
 		headers:        make(http.Header),
 		shouldRetry:    shouldRetry,
+		retryStatus:    retryStatus,
 	}
 	if _, ok := rw.(http.CloseNotifier); ok {
 		return &responseWriterWithCloseNotify{
@@ -173,6 +205,8 @@ type responseWriterWithoutCloseNotify struct {
 	responseWriter http.ResponseWriter
 	headers        http.Header
 	shouldRetry    bool
+	retryStatus    types.HTTPCodeRanges
+	requestSent    bool
 	written        bool
 }
 
@@ -184,6 +218,16 @@ func (r *responseWriterWithoutCloseNotify) DisableRetries() {
 	r.shouldRetry = false
 }
 
+// RequestSent records that the backend received request data.
+// The retries are then disabled, unless there are status codes to retry.
+func (r *responseWriterWithoutCloseNotify) RequestSent() {
+	r.requestSent = true
+
+	if len(r.retryStatus) == 0 {
+		r.DisableRetries()
+	}
+}
+
 func (r *responseWriterWithoutCloseNotify) Header() http.Header {
 	if r.written {
 		return r.responseWriter.Header()
@@ -199,13 +243,22 @@ func (r *responseWriterWithoutCloseNotify) Write(buf []byte) (int, error) {
 }
 
 func (r *responseWriterWithoutCloseNotify) WriteHeader(code int) {
-	if r.ShouldRetry() && code == http.StatusServiceUnavailable {
-		// We get a 503 HTTP Status Code when there is no backend server in the pool
-		// to which the request could be sent.  Also, note that r.ShouldRetry()
-		// will never return true in case there was a connection established to
-		// the backend server and so we can be sure that the 503 was produced
-		// inside Traefik already and we don't have to retry in this cases.
-		r.DisableRetries()
+	if r.ShouldRetry() {
+		switch {
+		case r.requestSent:
+			// The backend responded, the request is only retried on the configured status codes.
+			if !r.retryStatus.Contains(code) {
+				r.DisableRetries()
+			}
+		case code == http.StatusServiceUnavailable:
+			// We get a 503 HTTP Status Code when there is no backend server in the pool
+			// to which the request could be sent.  Also, note that r.ShouldRetry()
+			// will never return true in case there was a connection established to
+			// the backend server (unless there are status codes to retry),
+			// and so we can be sure that the 503 was produced
+			// inside Traefik already and we don't have to retry in this cases.
+			r.DisableRetries()
+		}
 	}
 
 	if r.ShouldRetry() {
@@ -134,6 +134,137 @@ func TestRetry(t *testing.T) {
 	}
 }
 
+func TestRetry_conditions(t *testing.T) {
+	testCases := []struct {
+		desc                  string
+		config                dynamic.Retry
+		method                string
+		body                  string
+		faultyStatus          int
+		wantRetryAttempts     int
+		wantResponseStatus    int
+		amountFaultyEndpoints int
+	}{
+		{
+			desc:                  "retry on a status code",
+			config:                dynamic.Retry{Attempts: 3, Status: []string{"500-599"}},
+			method:                http.MethodGet,
+			faultyStatus:          http.StatusInternalServerError,
+			wantRetryAttempts:     1,
+			wantResponseStatus:    http.StatusOK,
+			amountFaultyEndpoints: 1,
+		},
+		{
+			desc:                  "max attempts exhausted delivers the response with the status code",
+			config:                dynamic.Retry{Attempts: 3, Status: []string{"500-599"}},
+			method:                http.MethodGet,
+			faultyStatus:          http.StatusInternalServerError,
+			wantRetryAttempts:     2,
+			wantResponseStatus:    http.StatusInternalServerError,
+			amountFaultyEndpoints: 3,
+		},
+		{
+			desc:                  "no retry on another status code",
+			config:                dynamic.Retry{Attempts: 3, Status: []string{"503"}},
+			method:                http.MethodGet,
+			faultyStatus:          http.StatusInternalServerError,
+			wantRetryAttempts:     0,
+			wantResponseStatus:    http.StatusInternalServerError,
+			amountFaultyEndpoints: 1,
+		},
+		{
+			desc:                  "no retry on a status code without status codes to retry",
+			config:                dynamic.Retry{Attempts: 3},
+			method:                http.MethodGet,
+			faultyStatus:          http.StatusInternalServerError,
+			wantRetryAttempts:     0,
+			wantResponseStatus:    http.StatusInternalServerError,
+			amountFaultyEndpoints: 1,
+		},
+		{
+			desc:                  "no retry on a status code for a request with a body",
+			config:                dynamic.Retry{Attempts: 3, Status: []string{"500-599"}},
+			method:                http.MethodPost,
+			body:                  "foo",
+			faultyStatus:          http.StatusInternalServerError,
+			wantRetryAttempts:     0,
+			wantResponseStatus:    http.StatusInternalServerError,
+			amountFaultyEndpoints: 1,
+		},
+		{
+			desc:                  "retry of an idempotent request",
+			config:                dynamic.Retry{Attempts: 3, IdempotentOnly: true},
+			method:                http.MethodPut,
+			wantRetryAttempts:     1,
+			wantResponseStatus:    http.StatusOK,
+			amountFaultyEndpoints: 1,
+		},
+		{
+			desc:                  "no retry of a non idempotent request",
+			config:                dynamic.Retry{Attempts: 3, IdempotentOnly: true},
+			method:                http.MethodPost,
+			wantRetryAttempts:     0,
+			wantResponseStatus:    http.StatusBadGateway,
+			amountFaultyEndpoints: 1,
+		},
+		{
+			desc:                  "retry of a non idempotent request",
+			config:                dynamic.Retry{Attempts: 3},
+			method:                http.MethodPost,
+			wantRetryAttempts:     1,
+			wantResponseStatus:    http.StatusOK,
+			amountFaultyEndpoints: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			retryAttemps := 0
+			next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				retryAttemps++
+
+				if retryAttemps <= test.amountFaultyEndpoints && test.faultyStatus == 0 {
+					// The request did not reach the backend.
+					rw.WriteHeader(http.StatusBadGateway)
+					return
+				}
+
+				// calls WroteHeaders on httptrace.
+				_ = r.Write(io.Discard)
+
+				if retryAttemps <= test.amountFaultyEndpoints {
+					rw.WriteHeader(test.faultyStatus)
+					return
+				}
+
+				rw.WriteHeader(http.StatusOK)
+			})
+
+			retryListener := &countingRetryListener{}
+			retry, err := New(context.Background(), next, test.config, retryListener, "traefikTest")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(test.method, "http://localhost:3000/ok", strings.NewReader(test.body))
+
+			retry.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.wantResponseStatus, recorder.Code)
+			assert.Equal(t, test.wantRetryAttempts, retryListener.timesCalled)
+		})
+	}
+}
+
+func TestNew_invalidStatus(t *testing.T) {
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+	_, err := New(context.Background(), next, dynamic.Retry{Attempts: 3, Status: []string{"foo"}}, &countingRetryListener{}, "traefikTest")
+	assert.Error(t, err)
+}
+
 func TestRetryEmptyServerList(t *testing.T) {
 	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 		rw.WriteHeader(http.StatusServiceUnavailable)
@@ -0,0 +1,139 @@
+package rewritebody
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// responseWriter buffers the response body to rewrite it once complete,
+// unless the response cannot be rewritten, or its body exceeds the maximum size,
+// in which case the response is streamed to the client unchanged.
+type responseWriter struct {
+	rw       http.ResponseWriter
+	rewriter *rewriteBody
+
+	code int
+	buf  bytes.Buffer
+	// passthrough is true once the response is sent unchanged.
+	passthrough bool
+}
+
+func newResponseWriter(rw http.ResponseWriter, rewriter *rewriteBody) *responseWriter {
+	return &responseWriter{
+		rw:       rw,
+		rewriter: rewriter,
+	}
+}
+
+func (r *responseWriter) Header() http.Header {
+	return r.rw.Header()
+}
+
+func (r *responseWriter) WriteHeader(code int) {
+	if r.passthrough {
+		r.rw.WriteHeader(code)
+		return
+	}
+
+	if r.code != 0 {
+		return
+	}
+
+	// The informational responses are sent as is.
+	if code < http.StatusOK {
+		r.rw.WriteHeader(code)
+		return
+	}
+
+	r.code = code
+
+	if !r.rewriter.canRewrite(code, r.rw.Header()) {
+		r.passthrough = true
+		r.rw.WriteHeader(code)
+	}
+}
+
+func (r *responseWriter) Write(p []byte) (int, error) {
+	if r.code == 0 && !r.passthrough {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if r.passthrough {
+		return r.rw.Write(p)
+	}
+
+	if int64(r.buf.Len()+len(p)) <= r.rewriter.maxResponseBodyBytes {
+		return r.buf.Write(p)
+	}
+
+	if err := r.writeUnchanged(); err != nil {
+		return 0, err
+	}
+
+	return r.rw.Write(p)
+}
+
+// Flush flushes the response, unless it is buffered to be rewritten.
+func (r *responseWriter) Flush() {
+	if !r.passthrough {
+		return
+	}
+
+	if flusher, ok := r.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hijacks the connection.
+func (r *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.rw.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("not a hijacker: %T", r.rw)
+	}
+
+	r.passthrough = true
+
+	return hijacker.Hijack()
+}
+
+// close rewrites the buffered body, and sends the response.
+// The response is sent unchanged if its body cannot be decoded.
+func (r *responseWriter) close() error {
+	// Nothing was written, the response is left to the server.
+	if r.passthrough || r.code == 0 {
+		return nil
+	}
+
+	body, err := r.rewriter.rewrite(r.buf.Bytes(), r.rw.Header().Get("Content-Encoding"))
+	if err != nil {
+		if errW := r.writeUnchanged(); errW != nil {
+			return errW
+		}
+
+		return fmt.Errorf("unable to rewrite the response body: %w", err)
+	}
+
+	r.passthrough = true
+	r.rw.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	r.rw.WriteHeader(r.code)
+
+	_, err = r.rw.Write(body)
+	return err
+}
+
+// writeUnchanged sends the headers and the buffered body as is.
+func (r *responseWriter) writeUnchanged() error {
+	r.passthrough = true
+	r.rw.WriteHeader(r.code)
+
+	if r.buf.Len() == 0 {
+		return nil
+	}
+
+	_, err := r.rw.Write(r.buf.Bytes())
+	return err
+}
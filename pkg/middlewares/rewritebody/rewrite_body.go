@@ -0,0 +1,187 @@
+package rewritebody
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "RewriteBody"
+)
+
+const defaultMaxResponseBodyBytes = 1024 * 1024
+
+var defaultContentTypes = []string{"text/html"}
+
+type rewrite struct {
+	regexp      *regexp.Regexp
+	replacement []byte
+}
+
+// rewriteBody is a middleware that rewrites the response body with regular expressions.
+type rewriteBody struct {
+	next                 http.Handler
+	name                 string
+	rewrites             []rewrite
+	contentTypes         []string
+	maxResponseBodyBytes int64
+}
+
+// New creates a new rewrite body middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.RewriteBody, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if len(config.Rewrites) == 0 {
+		return nil, errors.New("at least one rewrite must be defined")
+	}
+
+	rewrites := make([]rewrite, 0, len(config.Rewrites))
+	for _, rule := range config.Rewrites {
+		exp, err := regexp.Compile(rule.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("error compiling regular expression %s: %w", rule.Regex, err)
+		}
+
+		rewrites = append(rewrites, rewrite{regexp: exp, replacement: []byte(rule.Replacement)})
+	}
+
+	contentTypes := defaultContentTypes
+	if len(config.ContentTypes) > 0 {
+		contentTypes = make([]string, 0, len(config.ContentTypes))
+		for _, v := range config.ContentTypes {
+			mediaType, _, err := mime.ParseMediaType(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid content type %q: %w", v, err)
+			}
+
+			contentTypes = append(contentTypes, mediaType)
+		}
+	}
+
+	maxResponseBodyBytes := int64(defaultMaxResponseBodyBytes)
+	if config.MaxResponseBodyBytes > 0 {
+		maxResponseBodyBytes = config.MaxResponseBodyBytes
+	}
+
+	return &rewriteBody{
+		next:                 next,
+		name:                 name,
+		rewrites:             rewrites,
+		contentTypes:         contentTypes,
+		maxResponseBodyBytes: maxResponseBodyBytes,
+	}, nil
+}
+
+func (r *rewriteBody) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return r.name, tracing.SpanKindNoneEnum
+}
+
+func (r *rewriteBody) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodHead {
+		r.next.ServeHTTP(rw, req)
+		return
+	}
+
+	rrw := newResponseWriter(rw, r)
+	defer func() {
+		if err := rrw.close(); err != nil {
+			log.FromContext(middlewares.GetLoggerCtx(req.Context(), r.name, typeName)).Debugf("Error while writing the rewritten response: %v", err)
+		}
+	}()
+
+	r.next.ServeHTTP(rrw, req)
+}
+
+// canRewrite reports whether the response can be rewritten, according to its status code and headers.
+func (r *rewriteBody) canRewrite(code int, header http.Header) bool {
+	switch code {
+	case http.StatusNoContent, http.StatusPartialContent, http.StatusNotModified:
+		return false
+	}
+
+	switch header.Get("Content-Encoding") {
+	case "", "identity", "gzip":
+	default:
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil || !contains(r.contentTypes, mediaType) {
+		return false
+	}
+
+	// The response is not buffered when it is known to be too large.
+	if contentLength := header.Get("Content-Length"); contentLength != "" {
+		length, err := strconv.ParseInt(contentLength, 10, 64)
+		if err == nil && length > r.maxResponseBodyBytes {
+			return false
+		}
+	}
+
+	return true
+}
+
+// rewrite applies the rewrites to the body, decoded then encoded back with the given content encoding.
+func (r *rewriteBody) rewrite(body []byte, encoding string) ([]byte, error) {
+	if encoding == "gzip" {
+		reader, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+
+		// The decoded body is also limited, as it can be much larger than the encoded one.
+		body, err = io.ReadAll(io.LimitReader(reader, r.maxResponseBodyBytes+1))
+		if err != nil {
+			return nil, err
+		}
+
+		if int64(len(body)) > r.maxResponseBodyBytes {
+			return nil, errors.New("decoded response body too large")
+		}
+	}
+
+	for _, rule := range r.rewrites {
+		body = rule.regexp.ReplaceAll(body, rule.replacement)
+	}
+
+	if encoding != "gzip" {
+		return body, nil
+	}
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(body); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,241 @@
+package rewritebody
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      dynamic.RewriteBody
+		expectedErr bool
+	}{
+		{
+			desc:   "valid configuration",
+			config: dynamic.RewriteBody{Rewrites: []dynamic.RewriteBodyRule{{Regex: "foo", Replacement: "bar"}}},
+		},
+		{
+			desc:        "no rewrite",
+			config:      dynamic.RewriteBody{},
+			expectedErr: true,
+		},
+		{
+			desc:        "invalid regex",
+			config:      dynamic.RewriteBody{Rewrites: []dynamic.RewriteBodyRule{{Regex: "foo(", Replacement: "bar"}}},
+			expectedErr: true,
+		},
+		{
+			desc: "invalid content type",
+			config: dynamic.RewriteBody{
+				Rewrites:     []dynamic.RewriteBodyRule{{Regex: "foo", Replacement: "bar"}},
+				ContentTypes: []string{"text/html; charset"},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+			_, err := New(context.Background(), next, test.config, "rewriteBody")
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRewriteBody(t *testing.T) {
+	rewrites := []dynamic.RewriteBodyRule{
+		{Regex: `http://internal\.local(:\d+)?/`, Replacement: "https://example.com/"},
+		{Regex: `src="/(\w+)`, Replacement: `src="/app/$1`},
+	}
+
+	testCases := []struct {
+		desc            string
+		config          dynamic.RewriteBody
+		method          string
+		code            int
+		contentType     string
+		contentEncoding string
+		body            string
+		expectedBody    string
+	}{
+		{
+			desc:         "rewritten",
+			config:       dynamic.RewriteBody{Rewrites: rewrites},
+			contentType:  "text/html; charset=utf-8",
+			body:         `<a href="http://internal.local:8080/foo"><img src="/img/bar.png">`,
+			expectedBody: `<a href="https://example.com/foo"><img src="/app/img/bar.png">`,
+		},
+		{
+			desc:         "content type not selected",
+			config:       dynamic.RewriteBody{Rewrites: rewrites},
+			contentType:  "application/json",
+			body:         `{"url": "http://internal.local/foo"}`,
+			expectedBody: `{"url": "http://internal.local/foo"}`,
+		},
+		{
+			desc:         "selected content type",
+			config:       dynamic.RewriteBody{Rewrites: rewrites, ContentTypes: []string{"application/json"}},
+			contentType:  "application/json",
+			body:         `{"url": "http://internal.local/foo"}`,
+			expectedBody: `{"url": "https://example.com/foo"}`,
+		},
+		{
+			desc:         "body over the maximum size",
+			config:       dynamic.RewriteBody{Rewrites: rewrites, MaxResponseBodyBytes: 10},
+			contentType:  "text/html",
+			body:         `<a href="http://internal.local/foo">`,
+			expectedBody: `<a href="http://internal.local/foo">`,
+		},
+		{
+			desc:         "partial content",
+			config:       dynamic.RewriteBody{Rewrites: rewrites},
+			code:         http.StatusPartialContent,
+			contentType:  "text/html",
+			body:         `<a href="http://internal.local/foo">`,
+			expectedBody: `<a href="http://internal.local/foo">`,
+		},
+		{
+			desc:            "unsupported encoding",
+			config:          dynamic.RewriteBody{Rewrites: rewrites},
+			contentType:     "text/html",
+			contentEncoding: "br",
+			body:            `<a href="http://internal.local/foo">`,
+			expectedBody:    `<a href="http://internal.local/foo">`,
+		},
+		{
+			desc:         "HEAD request",
+			config:       dynamic.RewriteBody{Rewrites: rewrites},
+			method:       http.MethodHead,
+			contentType:  "text/html",
+			body:         `<a href="http://internal.local/foo">`,
+			expectedBody: `<a href="http://internal.local/foo">`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Content-Type", test.contentType)
+				rw.Header().Set("Content-Length", strconv.Itoa(len(test.body)))
+				if test.contentEncoding != "" {
+					rw.Header().Set("Content-Encoding", test.contentEncoding)
+				}
+
+				if test.code != 0 {
+					rw.WriteHeader(test.code)
+				}
+
+				// The body is written in several parts, as a backend would stream it.
+				for i := 0; i < len(test.body); i += 8 {
+					end := i + 8
+					if end > len(test.body) {
+						end = len(test.body)
+					}
+
+					_, err := rw.Write([]byte(test.body[i:end]))
+					require.NoError(t, err)
+				}
+			})
+
+			handler, err := New(context.Background(), next, test.config, "rewriteBody")
+			require.NoError(t, err)
+
+			method := http.MethodGet
+			if test.method != "" {
+				method = test.method
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(method, "http://localhost", nil))
+
+			expectedCode := http.StatusOK
+			if test.code != 0 {
+				expectedCode = test.code
+			}
+
+			assert.Equal(t, expectedCode, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+			assert.Equal(t, strconv.Itoa(len(test.expectedBody)), recorder.Header().Get("Content-Length"))
+		})
+	}
+}
+
+func TestRewriteBody_gzip(t *testing.T) {
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, err := writer.Write([]byte(`<a href="http://internal.local/foo">`))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "text/html")
+		rw.Header().Set("Content-Encoding", "gzip")
+		rw.Header().Set("Content-Length", strconv.Itoa(compressed.Len()))
+
+		_, err := rw.Write(compressed.Bytes())
+		require.NoError(t, err)
+	})
+
+	config := dynamic.RewriteBody{Rewrites: []dynamic.RewriteBodyRule{{Regex: `http://internal\.local/`, Replacement: "https://example.com/"}}}
+	handler, err := New(context.Background(), next, config, "rewriteBody")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	assert.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+	assert.Equal(t, strconv.Itoa(recorder.Body.Len()), recorder.Header().Get("Content-Length"))
+
+	reader, err := gzip.NewReader(recorder.Body)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	assert.Equal(t, `<a href="https://example.com/foo">`, string(body))
+}
+
+func TestRewriteBody_invalidGzip(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "text/html")
+		rw.Header().Set("Content-Encoding", "gzip")
+
+		_, err := rw.Write([]byte("http://internal.local/"))
+		require.NoError(t, err)
+	})
+
+	config := dynamic.RewriteBody{Rewrites: []dynamic.RewriteBodyRule{{Regex: `http://internal\.local/`, Replacement: "https://example.com/"}}}
+	handler, err := New(context.Background(), next, config, "rewriteBody")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+	// The body which cannot be decoded is sent unchanged.
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "http://internal.local/", recorder.Body.String())
+}
@@ -0,0 +1,190 @@
+// Package sharedfile shares the content of the files loaded by the middlewares, such as databases or lists,
+// between all the middlewares using them, and reloads it asynchronously when the files are modified.
+package sharedfile
+
+import (
+	"io"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/safe"
+)
+
+// LoadFunc parses the content of a file.
+type LoadFunc func(r io.Reader) (interface{}, error)
+
+// Registry holds the files of a kind, shared by all the middlewares using them,
+// as a middleware is built for every router using it, and again on every configuration update.
+// A file is dropped once it is not used by any middleware anymore.
+type Registry struct {
+	kind          string
+	checkInterval time.Duration
+	load          LoadFunc
+
+	mu    sync.Mutex
+	files map[string]*file
+}
+
+// NewRegistry creates a new Registry of the files of the kind, e.g. "GeoIP database",
+// checked for changes at most once per check interval.
+func NewRegistry(kind string, checkInterval time.Duration, load LoadFunc) *Registry {
+	return &Registry{
+		kind:          kind,
+		checkInterval: checkInterval,
+		load:          load,
+		files:         make(map[string]*file),
+	}
+}
+
+// Acquire returns a handle on the file, which is loaded if it is not already used by another middleware.
+// The handle must be released once the middleware is not used anymore.
+// As the middlewares are not closed on configuration updates, it is also released when it is garbage collected.
+func (r *Registry) Acquire(path string) (*Handle, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f, ok := r.files[path]
+	if !ok {
+		f = &file{registry: r, path: path}
+
+		content, modTime, err := f.read()
+		if err != nil {
+			return nil, err
+		}
+
+		f.content = content
+		f.modTime = modTime
+		f.nextCheck = time.Now().Add(r.checkInterval)
+
+		r.files[path] = f
+	}
+
+	f.refs++
+
+	h := &Handle{file: f}
+	runtime.SetFinalizer(h, (*Handle).Release)
+
+	return h, nil
+}
+
+func (r *Registry) release(f *file) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f.refs--
+	if f.refs == 0 && r.files[f.path] == f {
+		delete(r.files, f.path)
+	}
+}
+
+// Handle is a reference to a shared file.
+type Handle struct {
+	file     *file
+	released int32
+}
+
+// Get returns the current content of the file, and triggers the check for changes of the file if it is due.
+func (h *Handle) Get() interface{} {
+	f := h.file
+
+	f.mu.RLock()
+	content, nextCheck := f.content, f.nextCheck
+	f.mu.RUnlock()
+
+	if time.Now().After(nextCheck) && atomic.CompareAndSwapInt32(&f.checking, 0, 1) {
+		safe.Go(func() {
+			defer atomic.StoreInt32(&f.checking, 0)
+			f.reload()
+		})
+	}
+
+	return content
+}
+
+// Reload loads the file again if it has been modified since it was last loaded.
+// The previous content is kept if the file cannot be loaded.
+func (h *Handle) Reload() {
+	h.file.reload()
+}
+
+// Release releases the reference on the file.
+func (h *Handle) Release() {
+	if !atomic.CompareAndSwapInt32(&h.released, 0, 1) {
+		return
+	}
+
+	runtime.SetFinalizer(h, nil)
+	h.file.registry.release(h.file)
+}
+
+// file is a file shared by the middlewares.
+type file struct {
+	registry *Registry
+	path     string
+
+	// refs is the number of handles on the file, guarded by the mutex of the registry.
+	refs int
+
+	checking int32
+
+	mu        sync.RWMutex
+	content   interface{}
+	modTime   time.Time
+	nextCheck time.Time
+}
+
+func (f *file) reload() {
+	logger := log.WithoutContext()
+
+	f.mu.Lock()
+	modTime := f.modTime
+	f.nextCheck = time.Now().Add(f.registry.checkInterval)
+	f.mu.Unlock()
+
+	fileInfo, err := os.Stat(f.path)
+	if err != nil {
+		logger.Errorf("Unable to check the %s %s: %v", f.registry.kind, f.path, err)
+		return
+	}
+
+	if fileInfo.ModTime().Equal(modTime) {
+		return
+	}
+
+	content, modTime, err := f.read()
+	if err != nil {
+		logger.Errorf("Unable to reload the %s %s, keeping the previous content: %v", f.registry.kind, f.path, err)
+		return
+	}
+
+	logger.Debugf("The %s %s has been reloaded", f.registry.kind, f.path)
+
+	f.mu.Lock()
+	f.content = content
+	f.modTime = modTime
+	f.mu.Unlock()
+}
+
+func (f *file) read() (interface{}, time.Time, error) {
+	osFile, err := os.Open(f.path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer func() { _ = osFile.Close() }()
+
+	fileInfo, err := osFile.Stat()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	content, err := f.registry.load(osFile)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return content, fileInfo.ModTime(), nil
+}
@@ -0,0 +1,127 @@
+package sharedfile
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRegistry(checkInterval time.Duration) (*Registry, *int) {
+	var loads int
+
+	return NewRegistry("test file", checkInterval, func(r io.Reader) (interface{}, error) {
+		content, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+
+		if string(content) == "invalid" {
+			return nil, errors.New("invalid content")
+		}
+
+		loads++
+
+		return string(content), nil
+	}), &loads
+}
+
+func writeFile(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+
+	err := os.WriteFile(path, []byte(content), 0o600)
+	require.NoError(t, err)
+	err = os.Chtimes(path, modTime, modTime)
+	require.NoError(t, err)
+}
+
+func TestRegistry_Acquire(t *testing.T) {
+	registry, loads := newTestRegistry(time.Hour)
+
+	path := filepath.Join(t.TempDir(), "file")
+	writeFile(t, path, "foo", time.Now())
+
+	foo, err := registry.Acquire(path)
+	require.NoError(t, err)
+
+	bar, err := registry.Acquire(path)
+	require.NoError(t, err)
+
+	// The file is loaded once, and shared by the handles.
+	assert.Equal(t, 1, *loads)
+	assert.Equal(t, "foo", foo.Get())
+	assert.Equal(t, "foo", bar.Get())
+
+	// The file is kept as long as a handle is not released.
+	foo.Release()
+	foo.Release()
+	assert.Len(t, registry.files, 1)
+
+	bar.Release()
+	assert.Empty(t, registry.files)
+
+	// The file is loaded again once it has been dropped.
+	baz, err := registry.Acquire(path)
+	require.NoError(t, err)
+	defer baz.Release()
+
+	assert.Equal(t, 2, *loads)
+
+	_, err = registry.Acquire(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+	assert.Len(t, registry.files, 1)
+}
+
+func TestHandle_Reload(t *testing.T) {
+	registry, loads := newTestRegistry(time.Hour)
+
+	path := filepath.Join(t.TempDir(), "file")
+	writeFile(t, path, "foo", time.Now())
+
+	handle, err := registry.Acquire(path)
+	require.NoError(t, err)
+	defer handle.Release()
+
+	// The file is not loaded again if it has not been modified.
+	handle.Reload()
+	assert.Equal(t, 1, *loads)
+
+	writeFile(t, path, "bar", time.Now().Add(time.Minute))
+
+	handle.Reload()
+	assert.Equal(t, "bar", handle.Get())
+
+	// The previous content is kept if the file is invalid.
+	writeFile(t, path, "invalid", time.Now().Add(2*time.Minute))
+
+	handle.Reload()
+	assert.Equal(t, "bar", handle.Get())
+
+	require.NoError(t, os.Remove(path))
+
+	handle.Reload()
+	assert.Equal(t, "bar", handle.Get())
+}
+
+func TestHandle_Get_check(t *testing.T) {
+	registry, _ := newTestRegistry(10 * time.Millisecond)
+
+	path := filepath.Join(t.TempDir(), "file")
+	writeFile(t, path, "foo", time.Now())
+
+	handle, err := registry.Acquire(path)
+	require.NoError(t, err)
+	defer handle.Release()
+
+	writeFile(t, path, "bar", time.Now().Add(time.Minute))
+
+	// The file is checked asynchronously once the check interval has elapsed.
+	assert.Eventually(t, func() bool {
+		return handle.Get() == "bar"
+	}, time.Second, 5*time.Millisecond)
+}
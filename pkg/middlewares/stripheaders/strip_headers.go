@@ -0,0 +1,119 @@
+package stripheaders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/ip"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "StripHeaders"
+)
+
+// stripHeaders is a middleware removing request headers which can be spoofed by the untrusted clients.
+type stripHeaders struct {
+	next http.Handler
+	name string
+
+	// names are the lower-case names of the headers to remove.
+	names map[string]struct{}
+	// prefixes are the lower-case prefixes of the names of the headers to remove.
+	prefixes []string
+
+	trustedChecker *ip.Checker
+	strategy       ip.Strategy
+}
+
+// New creates a new strip headers middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.StripHeaders, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if len(config.Headers) == 0 {
+		return nil, errors.New("headers is empty")
+	}
+
+	s := &stripHeaders{
+		next:  next,
+		name:  name,
+		names: make(map[string]struct{}),
+	}
+
+	for _, header := range config.Headers {
+		header = strings.ToLower(strings.TrimSpace(header))
+		if header == "" || header == "*" {
+			return nil, fmt.Errorf("invalid header name: %q", header)
+		}
+
+		if strings.HasSuffix(header, "*") {
+			s.prefixes = append(s.prefixes, strings.TrimSuffix(header, "*"))
+			continue
+		}
+
+		s.names[header] = struct{}{}
+	}
+
+	if len(config.TrustedIPs) > 0 {
+		checker, err := ip.NewChecker(config.TrustedIPs)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse CIDR trusted IPs %s: %w", config.TrustedIPs, err)
+		}
+
+		strategy, err := config.IPStrategy.Get()
+		if err != nil {
+			return nil, err
+		}
+
+		s.trustedChecker = checker
+		s.strategy = strategy
+	}
+
+	return s, nil
+}
+
+func (s *stripHeaders) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return s.name, tracing.SpanKindNoneEnum
+}
+
+func (s *stripHeaders) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if s.trustedChecker != nil && s.trustedChecker.IsAuthorized(s.strategy.GetIP(req)) == nil {
+		s.next.ServeHTTP(rw, req)
+		return
+	}
+
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), s.name, typeName))
+
+	// The header map is walked, rather than its canonical keys looked up,
+	// so that the headers with non-canonical names are removed too.
+	for header := range req.Header {
+		if s.matches(strings.ToLower(header)) {
+			logger.Debugf("Removing the header %s of the untrusted request", header)
+			delete(req.Header, header)
+		}
+	}
+
+	s.next.ServeHTTP(rw, req)
+}
+
+// matches reports whether the header, by its lower-case name, must be removed.
+func (s *stripHeaders) matches(header string) bool {
+	if _, ok := s.names[header]; ok {
+		return true
+	}
+
+	for _, prefix := range s.prefixes {
+		if strings.HasPrefix(header, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,188 @@
+package stripheaders
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      dynamic.StripHeaders
+		expectedErr bool
+	}{
+		{
+			desc:   "headers",
+			config: dynamic.StripHeaders{Headers: []string{"X-Forwarded-*", "X-Real-Ip"}},
+		},
+		{
+			desc:   "headers and trusted IPs",
+			config: dynamic.StripHeaders{Headers: []string{"X-Real-Ip"}, TrustedIPs: []string{"10.0.0.0/8"}},
+		},
+		{
+			desc:        "no header",
+			config:      dynamic.StripHeaders{TrustedIPs: []string{"10.0.0.0/8"}},
+			expectedErr: true,
+		},
+		{
+			desc:        "wildcard header",
+			config:      dynamic.StripHeaders{Headers: []string{"*"}},
+			expectedErr: true,
+		},
+		{
+			desc:        "invalid trusted IPs",
+			config:      dynamic.StripHeaders{Headers: []string{"X-Real-Ip"}, TrustedIPs: []string{"foo"}},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+			_, err := New(context.Background(), next, test.config, "stripHeaders")
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestStripHeaders(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		config          dynamic.StripHeaders
+		remoteAddr      string
+		xForwardedFor   string
+		expectedHeaders map[string]string
+	}{
+		{
+			desc:       "untrusted client",
+			config:     dynamic.StripHeaders{Headers: []string{"X-Forwarded-User", "x-real-ip"}, TrustedIPs: []string{"10.0.0.0/8"}},
+			remoteAddr: "192.168.1.1:1234",
+			expectedHeaders: map[string]string{
+				"X-Forwarded-User":  "",
+				"X-Real-Ip":         "",
+				"X-Forwarded-Proto": "https",
+				"X-Other":           "foo",
+			},
+		},
+		{
+			desc:       "trusted client",
+			config:     dynamic.StripHeaders{Headers: []string{"X-Forwarded-User", "x-real-ip"}, TrustedIPs: []string{"10.0.0.0/8"}},
+			remoteAddr: "10.0.0.1:1234",
+			expectedHeaders: map[string]string{
+				"X-Forwarded-User":  "admin",
+				"X-Real-Ip":         "1.2.3.4",
+				"X-Forwarded-Proto": "https",
+				"X-Other":           "foo",
+			},
+		},
+		{
+			desc:       "no trusted IPs",
+			config:     dynamic.StripHeaders{Headers: []string{"X-Forwarded-User"}},
+			remoteAddr: "10.0.0.1:1234",
+			expectedHeaders: map[string]string{
+				"X-Forwarded-User": "",
+				"X-Real-Ip":        "1.2.3.4",
+			},
+		},
+		{
+			desc:       "prefix",
+			config:     dynamic.StripHeaders{Headers: []string{"x-forwarded-*"}},
+			remoteAddr: "10.0.0.1:1234",
+			expectedHeaders: map[string]string{
+				"X-Forwarded-User":  "",
+				"X-Forwarded-Proto": "",
+				"X-Real-Ip":         "1.2.3.4",
+				"X-Other":           "foo",
+			},
+		},
+		{
+			desc: "trusted client with a depth strategy",
+			config: dynamic.StripHeaders{
+				Headers:    []string{"X-Forwarded-User"},
+				TrustedIPs: []string{"10.0.0.0/8"},
+				IPStrategy: &dynamic.IPStrategy{Depth: 1},
+			},
+			remoteAddr:    "192.168.1.1:1234",
+			xForwardedFor: "10.0.0.1",
+			expectedHeaders: map[string]string{
+				"X-Forwarded-User": "admin",
+			},
+		},
+		{
+			desc: "untrusted client with a depth strategy",
+			config: dynamic.StripHeaders{
+				Headers:    []string{"X-Forwarded-User"},
+				TrustedIPs: []string{"10.0.0.0/8"},
+				IPStrategy: &dynamic.IPStrategy{Depth: 1},
+			},
+			remoteAddr:    "10.0.0.1:1234",
+			xForwardedFor: "192.168.1.1",
+			expectedHeaders: map[string]string{
+				"X-Forwarded-User": "",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var headers http.Header
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				headers = req.Header
+			})
+
+			handler, err := New(context.Background(), next, test.config, "stripHeaders")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			req.RemoteAddr = test.remoteAddr
+			req.Header.Set("X-Forwarded-User", "admin")
+			req.Header.Set("X-Forwarded-Proto", "https")
+			req.Header.Set("X-Real-Ip", "1.2.3.4")
+			req.Header.Set("X-Other", "foo")
+			if test.xForwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", test.xForwardedFor)
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			for name, value := range test.expectedHeaders {
+				assert.Equal(t, value, headers.Get(name), name)
+			}
+		})
+	}
+}
+
+func TestStripHeaders_nonCanonicalName(t *testing.T) {
+	var headers http.Header
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		headers = req.Header
+	})
+
+	handler, err := New(context.Background(), next, dynamic.StripHeaders{Headers: []string{"X-Forwarded-User"}}, "stripHeaders")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+	req.Header["x-forwarded-user"] = []string{"admin"}
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Empty(t, headers)
+}
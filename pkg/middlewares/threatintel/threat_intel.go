@@ -0,0 +1,208 @@
+package threatintel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/patrickmn/go-cache"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/ip"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const typeName = "ThreatIntel"
+
+// The verdicts of the enrichment service.
+const (
+	verdictAllow = "allow"
+	verdictBlock = "block"
+)
+
+// enrichmentRequest is the body of a request to the enrichment service.
+type enrichmentRequest struct {
+	ClientIP  string `json:"clientIP"`
+	JA3       string `json:"ja3,omitempty"`
+	UserAgent string `json:"userAgent,omitempty"`
+}
+
+// enrichmentResponse is the body of a response of the enrichment service.
+type enrichmentResponse struct {
+	Verdict string `json:"verdict"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+type threatIntel struct {
+	next      http.Handler
+	name      string
+	endpoint  string
+	ja3Header string
+	headers   map[string]string
+	strategy  ip.Strategy
+	timeout   time.Duration
+	failOpen  bool
+	client    *http.Client
+	verdicts  *cache.Cache
+}
+
+// New creates a threat intelligence middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.ThreatIntel, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if config.Endpoint == "" {
+		return nil, errors.New("the endpoint of the enrichment service is missing")
+	}
+
+	strategy, err := config.IPStrategy.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &threatIntel{
+		next:      next,
+		name:      name,
+		endpoint:  config.Endpoint,
+		ja3Header: config.JA3Header,
+		headers:   config.Headers,
+		strategy:  strategy,
+		timeout:   time.Duration(config.Timeout),
+		failOpen:  config.FailOpen,
+		client:    &http.Client{},
+	}
+
+	// The verdicts are cached for the cache duration, and the expired ones are purged periodically.
+	if cacheDuration := time.Duration(config.CacheDuration); cacheDuration > 0 {
+		t.verdicts = cache.New(cacheDuration, 2*cacheDuration)
+	}
+
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.CreateTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		tr := http.DefaultTransport.(*http.Transport).Clone()
+		tr.TLSClientConfig = tlsConfig
+		t.client.Transport = tr
+	}
+
+	return t, nil
+}
+
+func (t *threatIntel) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return t.name, ext.SpanKindRPCClientEnum
+}
+
+func (t *threatIntel) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), t.name, typeName))
+
+	enrichReq := enrichmentRequest{
+		ClientIP:  t.strategy.GetIP(req),
+		UserAgent: req.UserAgent(),
+	}
+
+	if t.ja3Header != "" {
+		enrichReq.JA3 = req.Header.Get(t.ja3Header)
+	}
+
+	verdict, err := t.verdict(req.Context(), enrichReq)
+	if err != nil {
+		logMessage := fmt.Sprintf("Error querying the enrichment service %s: %v", t.endpoint, err)
+		logger.Debug(logMessage)
+		tracing.SetErrorWithEvent(req, logMessage)
+
+		if t.failOpen {
+			t.next.ServeHTTP(rw, req)
+			return
+		}
+
+		rw.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	if verdict.Verdict == verdictBlock {
+		logger.Debugf("Request from %s blocked by the enrichment service: %s", enrichReq.ClientIP, verdict.Reason)
+
+		rw.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	t.next.ServeHTTP(rw, req)
+}
+
+// verdict returns the verdict of the enrichment service for the given request attributes,
+// from the cache if it has been queried for the same attributes recently.
+func (t *threatIntel) verdict(ctx context.Context, enrichReq enrichmentRequest) (enrichmentResponse, error) {
+	key := strings.Join([]string{enrichReq.ClientIP, enrichReq.JA3, enrichReq.UserAgent}, "\x00")
+
+	if t.verdicts != nil {
+		if verdict, found := t.verdicts.Get(key); found {
+			return verdict.(enrichmentResponse), nil
+		}
+	}
+
+	verdict, err := t.query(ctx, enrichReq)
+	if err != nil {
+		return enrichmentResponse{}, err
+	}
+
+	if t.verdicts != nil {
+		t.verdicts.Set(key, verdict, cache.DefaultExpiration)
+	}
+
+	return verdict, nil
+}
+
+// query queries the enrichment service for the verdict of the given request attributes.
+func (t *threatIntel) query(ctx context.Context, enrichReq enrichmentRequest) (enrichmentResponse, error) {
+	body, err := json.Marshal(enrichReq)
+	if err != nil {
+		return enrichmentResponse{}, err
+	}
+
+	if t.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return enrichmentResponse{}, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range t.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return enrichmentResponse{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return enrichmentResponse{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var verdict enrichmentResponse
+	if err := json.NewDecoder(resp.Body).Decode(&verdict); err != nil {
+		return enrichmentResponse{}, fmt.Errorf("invalid response: %w", err)
+	}
+
+	verdict.Verdict = strings.ToLower(verdict.Verdict)
+	if verdict.Verdict != verdictAllow && verdict.Verdict != verdictBlock {
+		return enrichmentResponse{}, fmt.Errorf("unknown verdict %q", verdict.Verdict)
+	}
+
+	return verdict, nil
+}
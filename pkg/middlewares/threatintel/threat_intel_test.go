@@ -0,0 +1,180 @@
+package threatintel
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestThreatIntel(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		response       string
+		statusCode     int
+		failOpen       bool
+		expectedStatus int
+	}{
+		{
+			desc:           "allow verdict",
+			response:       `{"verdict": "allow"}`,
+			statusCode:     http.StatusOK,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "block verdict",
+			response:       `{"verdict": "BLOCK", "reason": "known scanner"}`,
+			statusCode:     http.StatusOK,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "unknown verdict, fail closed",
+			response:       `{"verdict": "maybe"}`,
+			statusCode:     http.StatusOK,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			desc:           "service error, fail closed",
+			statusCode:     http.StatusInternalServerError,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			desc:           "service error, fail open",
+			statusCode:     http.StatusInternalServerError,
+			failOpen:       true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "invalid response, fail open",
+			response:       `not json`,
+			statusCode:     http.StatusOK,
+			failOpen:       true,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			service := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(test.statusCode)
+				_, _ = rw.Write([]byte(test.response))
+			}))
+			t.Cleanup(service.Close)
+
+			config := dynamic.ThreatIntel{Endpoint: service.URL, FailOpen: test.failOpen}
+			config.SetDefaults()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+			handler, err := New(context.Background(), next, config, "threatIntel")
+			require.NoError(t, err)
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "http://foo.com", nil))
+
+			assert.Equal(t, test.expectedStatus, rw.Code)
+		})
+	}
+}
+
+func TestThreatIntel_enrichmentRequest(t *testing.T) {
+	var enrichReq enrichmentRequest
+	var authorization string
+	service := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		authorization = req.Header.Get("Authorization")
+
+		if err := json.NewDecoder(req.Body).Decode(&enrichReq); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		_, _ = rw.Write([]byte(`{"verdict": "allow"}`))
+	}))
+	t.Cleanup(service.Close)
+
+	config := dynamic.ThreatIntel{
+		Endpoint:   service.URL,
+		JA3Header:  "X-JA3",
+		IPStrategy: &dynamic.IPStrategy{Depth: 1},
+		Headers:    map[string]string{"Authorization": "Bearer token"},
+	}
+	config.SetDefaults()
+
+	handler, err := New(context.Background(), http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}), config, "threatIntel")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "http://foo.com", nil)
+	req.Header.Set("X-Forwarded-For", "10.0.0.1, 10.0.0.2")
+	req.Header.Set("X-JA3", "771,4865-4866,0-23,29-23,0")
+	req.Header.Set("User-Agent", "curl/7.79.1")
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "Bearer token", authorization)
+	assert.Equal(t, enrichmentRequest{
+		ClientIP:  "10.0.0.2",
+		JA3:       "771,4865-4866,0-23,29-23,0",
+		UserAgent: "curl/7.79.1",
+	}, enrichReq)
+}
+
+func TestThreatIntel_cache(t *testing.T) {
+	var queries int32
+	service := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&queries, 1)
+		_, _ = rw.Write([]byte(`{"verdict": "block"}`))
+	}))
+	t.Cleanup(service.Close)
+
+	testCases := []struct {
+		desc            string
+		cacheDuration   ptypes.Duration
+		expectedQueries int32
+	}{
+		{
+			desc:            "cached verdicts",
+			cacheDuration:   ptypes.Duration(time.Minute),
+			expectedQueries: 2,
+		},
+		{
+			desc:            "cache disabled",
+			expectedQueries: 3,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			atomic.StoreInt32(&queries, 0)
+
+			config := dynamic.ThreatIntel{Endpoint: service.URL, CacheDuration: test.cacheDuration}
+
+			handler, err := New(context.Background(), http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}), config, "threatIntel")
+			require.NoError(t, err)
+
+			for _, remoteAddr := range []string{"10.0.0.1:1234", "10.0.0.1:5678", "10.0.0.2:1234"} {
+				req := httptest.NewRequest(http.MethodGet, "http://foo.com", nil)
+				req.RemoteAddr = remoteAddr
+
+				rw := httptest.NewRecorder()
+				handler.ServeHTTP(rw, req)
+
+				assert.Equal(t, http.StatusForbidden, rw.Code)
+			}
+
+			assert.Equal(t, test.expectedQueries, atomic.LoadInt32(&queries))
+		})
+	}
+}
@@ -3,6 +3,8 @@ package acme
 import (
 	"context"
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -87,3 +89,18 @@ func GetKeyType(ctx context.Context, value string) certcrypto.KeyType {
 		return certcrypto.RSA4096
 	}
 }
+
+// matchesKeyType reports whether the public key of the certificate is of the given key type.
+func matchesKeyType(crt *x509.Certificate, keyType certcrypto.KeyType) bool {
+	switch publicKey := crt.PublicKey.(type) {
+	case *ecdsa.PublicKey:
+		return (keyType == certcrypto.EC256 && publicKey.Curve == elliptic.P256()) ||
+			(keyType == certcrypto.EC384 && publicKey.Curve == elliptic.P384())
+	case *rsa.PublicKey:
+		return (keyType == certcrypto.RSA2048 && publicKey.N.BitLen() == 2048) ||
+			(keyType == certcrypto.RSA4096 && publicKey.N.BitLen() == 4096) ||
+			(keyType == certcrypto.RSA8192 && publicKey.N.BitLen() == 8192)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,98 @@
+package acmetest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInjectedFault is the error of the requests failed by a FaultyTransport.
+var ErrInjectedFault = errors.New("injected network fault")
+
+// FaultyTransport is an http.RoundTripper injecting network faults,
+// to test how the ACME provider recovers from an unreliable CA server.
+type FaultyTransport struct {
+	// Transport sends the requests which are not failed. Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+	// Latency is the delay added before sending each request.
+	Latency time.Duration
+	// FailFirst is the number of first requests to fail.
+	FailFirst int
+	// FailPaths are the URL path prefixes of the requests to fail (e.g. /finalize-order/ with Pebble).
+	FailPaths []string
+
+	mu       sync.Mutex
+	requests int
+	faults   int
+}
+
+// RoundTrip implements http.RoundTripper.
+func (f *FaultyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if f.Latency > 0 {
+		timer := time.NewTimer(f.Latency)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			closeBody(req)
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+
+	if f.fail(req) {
+		closeBody(req)
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, ErrInjectedFault)
+	}
+
+	transport := f.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return transport.RoundTrip(req)
+}
+
+// Requests returns the number of requests received by the transport, including the failed ones.
+func (f *FaultyTransport) Requests() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.requests
+}
+
+// Faults returns the number of requests failed by the transport.
+func (f *FaultyTransport) Faults() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.faults
+}
+
+func (f *FaultyTransport) fail(req *http.Request) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests++
+
+	fail := f.requests <= f.FailFirst
+	for _, prefix := range f.FailPaths {
+		if strings.HasPrefix(req.URL.Path, prefix) {
+			fail = true
+		}
+	}
+
+	if fail {
+		f.faults++
+	}
+
+	return fail
+}
+
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+}
@@ -0,0 +1,69 @@
+package acmetest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultyTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	transport := &FaultyTransport{
+		FailFirst: 2,
+		FailPaths: []string{"/finalize-order/"},
+	}
+	client := &http.Client{Transport: transport}
+
+	testCases := []struct {
+		path     string
+		expected error
+	}{
+		{path: "/dir", expected: ErrInjectedFault},
+		{path: "/dir", expected: ErrInjectedFault},
+		{path: "/dir"},
+		{path: "/finalize-order/1", expected: ErrInjectedFault},
+		{path: "/order/1"},
+	}
+
+	for _, test := range testCases {
+		resp, err := client.Get(server.URL + test.path)
+		if test.expected != nil {
+			assert.ErrorIs(t, err, test.expected)
+			continue
+		}
+
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	assert.Equal(t, 5, transport.Requests())
+	assert.Equal(t, 3, transport.Faults())
+}
+
+func TestFaultyTransport_latency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	client := &http.Client{Transport: &FaultyTransport{Latency: time.Hour}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	_, err = client.Do(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
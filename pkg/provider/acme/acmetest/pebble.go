@@ -0,0 +1,101 @@
+// Package acmetest provides helpers to run the ACME provider against a local Pebble server
+// (https://github.com/letsencrypt/pebble), with network fault injection,
+// in order to test the certificate issuance without reaching a real CA.
+package acmetest
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/provider/acme"
+)
+
+// Pebble describes a local Pebble server.
+type Pebble struct {
+	// DirectoryURL is the URL of the ACME directory (e.g. https://127.0.0.1:14000/dir).
+	DirectoryURL string
+	// RootCA is the path to the PEM file of the CA signing the certificate of the Pebble server.
+	RootCA string
+	// ServerName is the name in the certificate of the Pebble server (e.g. pebble).
+	ServerName string
+}
+
+// Transport returns a transport trusting the certificate of the Pebble server.
+func (p Pebble) Transport() (*http.Transport, error) {
+	customCAs, err := os.ReadFile(p.RootCA)
+	if err != nil {
+		return nil, err
+	}
+
+	certPool := x509.NewCertPool()
+	if ok := certPool.AppendCertsFromPEM(customCAs); !ok {
+		return nil, fmt.Errorf("error creating x509 cert pool from %q", p.RootCA)
+	}
+
+	return &http.Transport{
+		TLSClientConfig: &tls.Config{
+			ServerName: p.ServerName,
+			RootCAs:    certPool,
+		},
+	}, nil
+}
+
+// WaitReady waits until the ACME directory of the Pebble server is served, or the context is done.
+func (p Pebble) WaitReady(ctx context.Context, transport http.RoundTripper) error {
+	client := &http.Client{Transport: transport, Timeout: time.Second}
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		err := p.getDirectory(ctx, client)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("pebble is not ready: %w", err)
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p Pebble) getDirectory(ctx context.Context, client *http.Client) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.DirectoryURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Provider returns an ACME provider, using the given configuration,
+// which requests its certificates to the Pebble server through the given transport.
+// The transport is typically a FaultyTransport wrapping the one returned by Transport.
+// The provider still has to be initialized.
+func (p Pebble) Provider(resolverName string, config acme.Configuration, store acme.Store, transport http.RoundTripper) *acme.Provider {
+	config.CAServer = p.DirectoryURL
+
+	return &acme.Provider{
+		Configuration: &config,
+		ResolverName:  resolverName,
+		Store:         store,
+		HTTPClient:    &http.Client{Transport: transport, Timeout: 30 * time.Second},
+	}
+}
@@ -0,0 +1,61 @@
+package acmetest
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPebble_WaitReady(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/dir" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		rw.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	rootCA := filepath.Join(t.TempDir(), "pebble.minica.pem")
+	err := os.WriteFile(rootCA, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600)
+	require.NoError(t, err)
+
+	pebble := Pebble{
+		DirectoryURL: server.URL + "/dir",
+		RootCA:       rootCA,
+		// Name in the certificate of the httptest servers.
+		ServerName: "example.com",
+	}
+
+	transport, err := pebble.Transport()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	faulty := &FaultyTransport{Transport: transport, FailFirst: 2}
+
+	err = pebble.WaitReady(ctx, faulty)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, faulty.Requests())
+}
+
+func TestPebble_WaitReady_notReady(t *testing.T) {
+	pebble := Pebble{DirectoryURL: "http://127.0.0.1:14000/dir"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	err := pebble.WaitReady(ctx, &FaultyTransport{FailPaths: []string{"/"}})
+	assert.ErrorIs(t, err, ErrInjectedFault)
+}
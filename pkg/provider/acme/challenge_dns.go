@@ -0,0 +1,29 @@
+package acme
+
+import (
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+)
+
+// sequentialDNSProvider wraps a DNS challenge provider so that lego solves the DNS challenges of a certificate one after the other,
+// waiting for the interval between them, e.g. for the DNS providers which cannot hold several challenge records at once.
+type sequentialDNSProvider struct {
+	challenge.Provider
+	interval time.Duration
+}
+
+// Sequential returns the interval between two DNS challenges.
+func (p sequentialDNSProvider) Sequential() time.Duration {
+	return p.interval
+}
+
+// Timeout returns the timeout and the interval of the propagation checks of the wrapped provider.
+func (p sequentialDNSProvider) Timeout() (timeout, interval time.Duration) {
+	if provider, ok := p.Provider.(challenge.ProviderTimeout); ok {
+		return provider.Timeout()
+	}
+
+	return dns01.DefaultPropagationTimeout, dns01.DefaultPollingInterval
+}
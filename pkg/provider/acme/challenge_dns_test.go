@@ -0,0 +1,40 @@
+package acme
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/stretchr/testify/assert"
+)
+
+type dnsProviderMock struct{}
+
+func (dnsProviderMock) Present(_, _, _ string) error { return nil }
+
+func (dnsProviderMock) CleanUp(_, _, _ string) error { return nil }
+
+type dnsProviderTimeoutMock struct {
+	dnsProviderMock
+}
+
+func (dnsProviderTimeoutMock) Timeout() (timeout, interval time.Duration) {
+	return 10 * time.Minute, 30 * time.Second
+}
+
+func TestSequentialDNSProvider(t *testing.T) {
+	provider := sequentialDNSProvider{Provider: dnsProviderMock{}, interval: time.Minute}
+
+	assert.Equal(t, time.Minute, provider.Sequential())
+
+	timeout, interval := provider.Timeout()
+	assert.Equal(t, dns01.DefaultPropagationTimeout, timeout)
+	assert.Equal(t, dns01.DefaultPollingInterval, interval)
+
+	// The propagation timeout of the wrapped provider is kept.
+	provider = sequentialDNSProvider{Provider: dnsProviderTimeoutMock{}}
+
+	timeout, interval = provider.Timeout()
+	assert.Equal(t, 10*time.Minute, timeout)
+	assert.Equal(t, 30*time.Second, interval)
+}
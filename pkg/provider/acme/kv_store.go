@@ -0,0 +1,370 @@
+package acme
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/abronan/valkeyrie/store"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider/kv"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+const (
+	// kvLockTTL is the TTL of the lock of a resolver, renewed as long as the lock is held.
+	kvLockTTL = 20 * time.Second
+	// kvSaveAttempts is the number of attempts to save the certificates modified concurrently by other instances.
+	kvSaveAttempts = 10
+)
+
+// KVStorage holds the configuration of the KV store keeping the ACME data of a cluster of Traefik instances.
+type KVStorage struct {
+	Backend   string           `description:"KV store backend: consul, etcd, zookeeper or redis." json:"backend,omitempty" toml:"backend,omitempty" yaml:"backend,omitempty" export:"true"`
+	Endpoints []string         `description:"KV store endpoints." json:"endpoints,omitempty" toml:"endpoints,omitempty" yaml:"endpoints,omitempty"`
+	Username  string           `description:"KV store username." json:"username,omitempty" toml:"username,omitempty" yaml:"username,omitempty"`
+	Password  string           `description:"KV store password." json:"password,omitempty" toml:"password,omitempty" yaml:"password,omitempty"`
+	TLS       *types.ClientTLS `description:"Enable TLS support." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	RootKey   string           `description:"Root key of the ACME data." json:"rootKey,omitempty" toml:"rootKey,omitempty" yaml:"rootKey,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (s *KVStorage) SetDefaults() {
+	s.Backend = "consul"
+	s.RootKey = "traefik/acme"
+}
+
+var _ SharedStore = (*KVStore)(nil)
+
+// KVStore Stores implementation for a KV store shared by several Traefik instances.
+type KVStore struct {
+	client  store.Store
+	rootKey string
+}
+
+// NewKVStore initializes a new KVStore connected to the KV store.
+func NewKVStore(ctx context.Context, config *KVStorage) (*KVStore, error) {
+	var backend store.Backend
+	switch config.Backend {
+	case "consul":
+		backend = store.CONSUL
+	case "etcd":
+		backend = store.ETCDV3
+	case "zookeeper":
+		backend = store.ZK
+	case "redis":
+		backend = store.REDIS
+	default:
+		return nil, fmt.Errorf("unsupported KV store backend: %q", config.Backend)
+	}
+
+	if len(config.Endpoints) == 0 {
+		return nil, errors.New("no KV store endpoint")
+	}
+
+	client, err := kv.NewClient(ctx, backend, config.Endpoints, config.Username, config.Password, config.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the KV store: %w", err)
+	}
+
+	return &KVStore{client: client, rootKey: config.RootKey}, nil
+}
+
+func (s *KVStore) key(resolverName, name string) string {
+	return path.Join(s.rootKey, resolverName, name)
+}
+
+// GetAccount returns ACME Account.
+func (s *KVStore) GetAccount(resolverName string) (*Account, error) {
+	pair, err := s.client.Get(s.key(resolverName, "account"), &store.ReadOptions{Consistent: true})
+	if errors.Is(err, store.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var account *Account
+	if err := json.Unmarshal(pair.Value, &account); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// SaveAccount stores ACME Account.
+func (s *KVStore) SaveAccount(resolverName string, account *Account) error {
+	data, err := json.Marshal(account)
+	if err != nil {
+		return err
+	}
+
+	return s.client.Put(s.key(resolverName, "account"), data, nil)
+}
+
+// GetCertificates returns ACME Certificates list.
+func (s *KVStore) GetCertificates(resolverName string) ([]*CertAndStore, error) {
+	certificates, _, err := s.getCertificates(resolverName)
+	return certificates, err
+}
+
+func (s *KVStore) getCertificates(resolverName string) ([]*CertAndStore, *store.KVPair, error) {
+	pair, err := s.client.Get(s.key(resolverName, "certificates"), &store.ReadOptions{Consistent: true})
+	if errors.Is(err, store.ErrKeyNotFound) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certificates, err := decodeKVCertificates(pair.Value)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return certificates, pair, nil
+}
+
+// SaveCertificates stores ACME Certificates list.
+// The certificates are merged with the ones saved by the other instances,
+// and the certificate expiring last is kept when several instances saved one for the same domains.
+func (s *KVStore) SaveCertificates(resolverName string, certificates []*CertAndStore) error {
+	key := s.key(resolverName, "certificates")
+
+	for i := 0; i < kvSaveAttempts; i++ {
+		stored, previous, err := s.getCertificates(resolverName)
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(mergeStoredCertificates(stored, certificates))
+		if err != nil {
+			return err
+		}
+
+		_, _, err = s.client.AtomicPut(key, data, previous, nil)
+		if errors.Is(err, store.ErrKeyModified) || errors.Is(err, store.ErrKeyExists) {
+			continue
+		}
+
+		return err
+	}
+
+	return fmt.Errorf("unable to save the certificates of the resolver %s: modified concurrently", resolverName)
+}
+
+// GetOrderAttempts returns the certificate orders attempted, by registered domain.
+func (s *KVStore) GetOrderAttempts(resolverName string) (map[string][]time.Time, error) {
+	attempts, _, err := s.getOrderAttempts(resolverName)
+	return attempts, err
+}
+
+func (s *KVStore) getOrderAttempts(resolverName string) (map[string][]time.Time, *store.KVPair, error) {
+	pair, err := s.client.Get(s.key(resolverName, "orderAttempts"), &store.ReadOptions{Consistent: true})
+	if errors.Is(err, store.ErrKeyNotFound) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var attempts map[string][]time.Time
+	if err := json.Unmarshal(pair.Value, &attempts); err != nil {
+		return nil, nil, err
+	}
+
+	return attempts, pair, nil
+}
+
+// SaveOrderAttempts stores the certificate orders attempted, by registered domain.
+// The attempts are merged with the ones saved by the other instances,
+// as the rate limits of the CA apply to all of them.
+func (s *KVStore) SaveOrderAttempts(resolverName string, attempts map[string][]time.Time) error {
+	key := s.key(resolverName, "orderAttempts")
+
+	for i := 0; i < kvSaveAttempts; i++ {
+		stored, previous, err := s.getOrderAttempts(resolverName)
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(mergeOrderAttempts(stored, attempts, time.Now().Add(-registeredDomainWindow)))
+		if err != nil {
+			return err
+		}
+
+		_, _, err = s.client.AtomicPut(key, data, previous, nil)
+		if errors.Is(err, store.ErrKeyModified) || errors.Is(err, store.ErrKeyExists) {
+			continue
+		}
+
+		return err
+	}
+
+	return fmt.Errorf("unable to save the order attempts of the resolver %s: modified concurrently", resolverName)
+}
+
+// Lock acquires the lock of the resolver, and returns the function releasing it.
+func (s *KVStore) Lock(ctx context.Context, resolverName string) (func(), error) {
+	locker, err := s.client.NewLock(s.key(resolverName, "lock"), &store.LockOptions{TTL: kvLockTTL})
+	if err != nil {
+		return nil, err
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			close(stop)
+		case <-done:
+		}
+	}()
+
+	_, err = locker.Lock(stop)
+	close(done)
+	if err != nil {
+		return nil, fmt.Errorf("unable to acquire the lock of the resolver %s: %w", resolverName, err)
+	}
+
+	return func() {
+		if err := locker.Unlock(); err != nil {
+			log.FromContext(ctx).Errorf("Unable to release the lock of the resolver %s: %v", resolverName, err)
+		}
+	}, nil
+}
+
+// WatchCertificates sends the certificates of the resolver each time they are saved, by any instance.
+func (s *KVStore) WatchCertificates(ctx context.Context, resolverName string) (<-chan []*CertAndStore, error) {
+	pairs, err := s.client.Watch(s.key(resolverName, "certificates"), ctx.Done(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	certificatesChan := make(chan []*CertAndStore)
+
+	go func() {
+		defer close(certificatesChan)
+
+		for pair := range pairs {
+			if pair == nil {
+				continue
+			}
+
+			certificates, err := decodeKVCertificates(pair.Value)
+			if err != nil {
+				log.FromContext(ctx).Errorf("Unable to decode the certificates of the resolver %s: %v", resolverName, err)
+				continue
+			}
+
+			select {
+			case certificatesChan <- certificates:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return certificatesChan, nil
+}
+
+// decodeKVCertificates decodes the certificates saved in the KV store, and deletes the certificates with no value.
+func decodeKVCertificates(data []byte) ([]*CertAndStore, error) {
+	var stored []*CertAndStore
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, err
+	}
+
+	var certificates []*CertAndStore
+	for _, certificate := range stored {
+		if len(certificate.Certificate.Certificate) == 0 || len(certificate.Key) == 0 {
+			continue
+		}
+		certificates = append(certificates, certificate)
+	}
+
+	return certificates, nil
+}
+
+// mergeStoredCertificates merges the certificates into the stored ones,
+// keeping the certificate expiring last for each domain and TLS store.
+func mergeStoredCertificates(stored, certificates []*CertAndStore) []*CertAndStore {
+	merged := append([]*CertAndStore{}, stored...)
+
+	for _, certificate := range certificates {
+		found := false
+		for i, current := range merged {
+			if current.Store != certificate.Store || !reflect.DeepEqual(current.Domain, certificate.Domain) {
+				continue
+			}
+
+			found = true
+			if expiresAfter(certificate, current) {
+				merged[i] = certificate
+			}
+			break
+		}
+
+		if !found {
+			merged = append(merged, certificate)
+		}
+	}
+
+	return merged
+}
+
+// expiresAfter reports whether the certificate a expires after the certificate b.
+// A certificate which cannot be parsed is considered as expired.
+func expiresAfter(a, b *CertAndStore) bool {
+	ctx := log.With(context.Background(), log.Str(log.ProviderName, "acme"))
+
+	crtA, err := getX509Certificate(ctx, &a.Certificate)
+	if err != nil || crtA == nil {
+		return false
+	}
+
+	crtB, err := getX509Certificate(ctx, &b.Certificate)
+	if err != nil || crtB == nil {
+		return true
+	}
+
+	return crtA.NotAfter.After(crtB.NotAfter)
+}
+
+// mergeOrderAttempts merges the attempts into the stored ones, dropping the attempts older than since.
+func mergeOrderAttempts(stored, attempts map[string][]time.Time, since time.Time) map[string][]time.Time {
+	merged := make(map[string][]time.Time)
+
+	for _, source := range []map[string][]time.Time{stored, attempts} {
+		for registeredDomain, domainAttempts := range source {
+			for _, attempt := range domainAttempts {
+				if !attempt.After(since) || containsTime(merged[registeredDomain], attempt) {
+					continue
+				}
+
+				merged[registeredDomain] = append(merged[registeredDomain], attempt)
+			}
+		}
+	}
+
+	for _, domainAttempts := range merged {
+		sort.Slice(domainAttempts, func(i, j int) bool { return domainAttempts[i].Before(domainAttempts[j]) })
+	}
+
+	return merged
+}
+
+func containsTime(times []time.Time, t time.Time) bool {
+	for _, v := range times {
+		if v.Equal(t) {
+			return true
+		}
+	}
+
+	return false
+}
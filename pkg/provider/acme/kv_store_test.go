@@ -0,0 +1,208 @@
+package acme
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abronan/valkeyrie/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// kvClientMock is an in-memory KV store supporting the atomic operations.
+type kvClientMock struct {
+	store.Store
+
+	mu    sync.Mutex
+	pairs map[string]*store.KVPair
+	index uint64
+
+	// beforeAtomicPut is called before each atomic put, e.g. to simulate the concurrent writes of other instances.
+	beforeAtomicPut func()
+}
+
+func newKVClientMock() *kvClientMock {
+	return &kvClientMock{pairs: make(map[string]*store.KVPair)}
+}
+
+func (m *kvClientMock) Get(key string, _ *store.ReadOptions) (*store.KVPair, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pair, ok := m.pairs[key]
+	if !ok {
+		return nil, store.ErrKeyNotFound
+	}
+
+	return pair, nil
+}
+
+func (m *kvClientMock) Put(key string, value []byte, _ *store.WriteOptions) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.index++
+	m.pairs[key] = &store.KVPair{Key: key, Value: value, LastIndex: m.index}
+
+	return nil
+}
+
+func (m *kvClientMock) AtomicPut(key string, value []byte, previous *store.KVPair, _ *store.WriteOptions) (bool, *store.KVPair, error) {
+	if m.beforeAtomicPut != nil {
+		m.beforeAtomicPut()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.pairs[key]
+	if previous == nil && ok {
+		return false, nil, store.ErrKeyExists
+	}
+	if previous != nil && (!ok || current.LastIndex != previous.LastIndex) {
+		return false, nil, store.ErrKeyModified
+	}
+
+	m.index++
+	m.pairs[key] = &store.KVPair{Key: key, Value: value, LastIndex: m.index}
+
+	return true, m.pairs[key], nil
+}
+
+func TestKVStore_account(t *testing.T) {
+	s := &KVStore{client: newKVClientMock(), rootKey: "traefik/acme"}
+
+	account, err := s.GetAccount("resolver")
+	require.NoError(t, err)
+	assert.Nil(t, account)
+
+	expected := newTestAccount(t, "foo@example.com")
+	err = s.SaveAccount("resolver", expected)
+	require.NoError(t, err)
+
+	account, err = s.GetAccount("resolver")
+	require.NoError(t, err)
+	assert.Equal(t, expected, account)
+
+	account, err = s.GetAccount("other")
+	require.NoError(t, err)
+	assert.Nil(t, account)
+}
+
+func TestKVStore_SaveCertificates(t *testing.T) {
+	client := newKVClientMock()
+	s := &KVStore{client: client, rootKey: "traefik/acme"}
+
+	soon := time.Now().Add(24 * time.Hour)
+	later := time.Now().Add(90 * 24 * time.Hour)
+
+	err := s.SaveCertificates("resolver", []*CertAndStore{
+		newCertAndStore(t, "foo.example.com", soon),
+		newCertAndStore(t, "bar.example.com", later),
+	})
+	require.NoError(t, err)
+
+	// Another instance saves its certificates, with a renewed certificate and an outdated one.
+	renewed := newCertAndStore(t, "foo.example.com", later)
+	err = s.SaveCertificates("resolver", []*CertAndStore{
+		renewed,
+		newCertAndStore(t, "bar.example.com", soon),
+		newCertAndStore(t, "baz.example.com", later),
+	})
+	require.NoError(t, err)
+
+	certificates, err := s.GetCertificates("resolver")
+	require.NoError(t, err)
+
+	notAfters := make(map[string]time.Time)
+	for _, cert := range certificates {
+		crt, err := getX509Certificate(context.Background(), &cert.Certificate)
+		require.NoError(t, err)
+		notAfters[cert.Domain.Main] = crt.NotAfter
+	}
+
+	require.Len(t, notAfters, 3)
+	assert.WithinDuration(t, later, notAfters["foo.example.com"], time.Second)
+	assert.WithinDuration(t, later, notAfters["bar.example.com"], time.Second)
+	assert.WithinDuration(t, later, notAfters["baz.example.com"], time.Second)
+}
+
+func TestKVStore_SaveCertificates_concurrentWrite(t *testing.T) {
+	client := newKVClientMock()
+	s := &KVStore{client: client, rootKey: "traefik/acme"}
+
+	later := time.Now().Add(90 * 24 * time.Hour)
+
+	// Another instance saves a certificate between the read and the write of the first attempt.
+	concurrent := newCertAndStore(t, "bar.example.com", later)
+	var once sync.Once
+	client.beforeAtomicPut = func() {
+		once.Do(func() {
+			data, err := json.Marshal([]*CertAndStore{concurrent})
+			require.NoError(t, err)
+			require.NoError(t, client.Put("traefik/acme/resolver/certificates", data, nil))
+		})
+	}
+
+	err := s.SaveCertificates("resolver", []*CertAndStore{newCertAndStore(t, "foo.example.com", later)})
+	require.NoError(t, err)
+
+	certificates, err := s.GetCertificates("resolver")
+	require.NoError(t, err)
+
+	var domains []string
+	for _, cert := range certificates {
+		domains = append(domains, cert.Domain.Main)
+	}
+
+	assert.ElementsMatch(t, []string{"foo.example.com", "bar.example.com"}, domains)
+}
+
+func TestKVStore_SaveOrderAttempts(t *testing.T) {
+	s := &KVStore{client: newKVClientMock(), rootKey: "traefik/acme"}
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	err := s.SaveOrderAttempts("resolver", map[string][]time.Time{
+		"example.com": {now.Add(-registeredDomainWindow - time.Hour), now.Add(-time.Hour)},
+	})
+	require.NoError(t, err)
+
+	// Another instance saves its attempts.
+	err = s.SaveOrderAttempts("resolver", map[string][]time.Time{
+		"example.com": {now},
+		"example.org": {now},
+	})
+	require.NoError(t, err)
+
+	attempts, err := s.GetOrderAttempts("resolver")
+	require.NoError(t, err)
+
+	expected := map[string][]time.Time{
+		"example.com": {now.Add(-time.Hour), now},
+		"example.org": {now},
+	}
+	assert.Equal(t, expected, attempts)
+}
+
+func TestKVStore_GetCertificates_emptyCertificates(t *testing.T) {
+	client := newKVClientMock()
+	s := &KVStore{client: client, rootKey: "traefik/acme"}
+
+	valid := newCertAndStore(t, "foo.example.com", time.Now().Add(90*24*time.Hour))
+	empty := newCertAndStore(t, "bar.example.com", time.Now().Add(90*24*time.Hour))
+	empty.Key = nil
+
+	data, err := json.Marshal([]*CertAndStore{valid, empty})
+	require.NoError(t, err)
+	require.NoError(t, client.Put("traefik/acme/resolver/certificates", data, nil))
+
+	certificates, err := s.GetCertificates("resolver")
+	require.NoError(t, err)
+
+	require.Len(t, certificates, 1)
+	assert.Equal(t, "foo.example.com", certificates[0].Domain.Main)
+}
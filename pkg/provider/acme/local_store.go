@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/safe"
@@ -170,3 +171,26 @@ func (s *LocalStore) SaveCertificates(resolverName string, certificates []*CertA
 
 	return nil
 }
+
+// GetOrderAttempts returns the certificate orders attempted, by registered domain.
+func (s *LocalStore) GetOrderAttempts(resolverName string) (map[string][]time.Time, error) {
+	storedData, err := s.get(resolverName)
+	if err != nil {
+		return nil, err
+	}
+
+	return storedData.OrderAttempts, nil
+}
+
+// SaveOrderAttempts stores the certificate orders attempted, by registered domain.
+func (s *LocalStore) SaveOrderAttempts(resolverName string, attempts map[string][]time.Time) error {
+	storedData, err := s.get(resolverName)
+	if err != nil {
+		return err
+	}
+
+	storedData.OrderAttempts = attempts
+	s.save(resolverName, storedData)
+
+	return nil
+}
@@ -0,0 +1,228 @@
+package acme
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// MigrationOptions holds the options of an ACME storage migration.
+type MigrationOptions struct {
+	// FromResolver is the name of the resolver to migrate, all the resolvers are migrated if empty.
+	FromResolver string
+	// ToResolver is the name of the resolver in the destination storage, defaults to FromResolver.
+	ToResolver string
+}
+
+// MigrateStorage copies the ACME accounts and certificates from the storage file src to the storage file dst,
+// so that the resolvers using dst do not have to request again the certificates already issued.
+// All the data is checked before anything is written: the account private keys must be valid,
+// and each certificate must match its private key.
+// The destination data of the other resolvers is kept.
+// When the destination resolver already has an account, it is kept and only the certificates are copied,
+// since the issued certificates are not bound to the account which requested them.
+// When both storages have a certificate for the same domains, the one expiring last is kept.
+// It returns the number of certificates copied.
+func MigrateStorage(src, dst string, opts MigrationOptions) (int, error) {
+	if opts.ToResolver != "" && opts.FromResolver == "" {
+		return 0, errors.New("the resolver to migrate from must be set when the resolver to migrate to is set")
+	}
+
+	if _, err := os.Stat(src); err != nil {
+		return 0, err
+	}
+
+	srcData, err := readStorage(src)
+	if err != nil {
+		return 0, fmt.Errorf("unable to read the storage %s: %w", src, err)
+	}
+
+	dstData, err := readStorage(dst)
+	if err != nil {
+		return 0, fmt.Errorf("unable to read the storage %s: %w", dst, err)
+	}
+
+	resolvers := make(map[string]string)
+	if opts.FromResolver != "" {
+		if srcData[opts.FromResolver] == nil {
+			return 0, fmt.Errorf("no data for resolver %s in the storage %s", opts.FromResolver, src)
+		}
+
+		resolvers[opts.FromResolver] = opts.ToResolver
+		if opts.ToResolver == "" {
+			resolvers[opts.FromResolver] = opts.FromResolver
+		}
+	} else {
+		for name := range srcData {
+			resolvers[name] = name
+		}
+	}
+
+	var names []string
+	for name := range resolvers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	logger := log.WithoutContext().WithField(log.ProviderName, "acme")
+
+	var count int
+	for _, name := range names {
+		data := srcData[name]
+		if data == nil {
+			continue
+		}
+
+		if err := validateStoredData(data); err != nil {
+			return 0, fmt.Errorf("invalid data for resolver %s in the storage %s: %w", name, src, err)
+		}
+
+		target := dstData[resolvers[name]]
+		if target == nil {
+			target = &StoredData{}
+			dstData[resolvers[name]] = target
+		}
+
+		if target.Account == nil {
+			target.Account = data.Account
+		} else if data.Account != nil {
+			logger.Infof("Keeping the account %s of resolver %s in the storage %s", target.Account.Email, resolvers[name], dst)
+		}
+
+		var copied int
+		target.Certificates, copied = mergeCertificates(target.Certificates, data.Certificates)
+		count += copied
+
+		logger.Infof("%d certificate(s) of resolver %s migrated to resolver %s", copied, name, resolvers[name])
+	}
+
+	if err := writeStorage(dst, dstData); err != nil {
+		return 0, fmt.Errorf("unable to write the storage %s: %w", dst, err)
+	}
+
+	return count, nil
+}
+
+// readStorage reads the data of a storage file, which may not exist yet.
+func readStorage(filename string) (map[string]*StoredData, error) {
+	data := make(map[string]*StoredData)
+
+	hasData, err := CheckFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasData {
+		return data, nil
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(content, &data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// writeStorage writes the data of a storage file through a temporary file,
+// so that the storage is never left partially written.
+func writeStorage(filename string, data map[string]*StoredData) error {
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if err := tmp.Chmod(0o600); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filename)
+}
+
+func validateStoredData(data *StoredData) error {
+	if data.Account != nil && len(data.Account.PrivateKey) > 0 {
+		if _, err := x509.ParsePKCS1PrivateKey(data.Account.PrivateKey); err != nil {
+			return fmt.Errorf("invalid private key for account %s: %w", data.Account.Email, err)
+		}
+	}
+
+	for _, cert := range data.Certificates {
+		if _, err := tls.X509KeyPair(cert.Certificate.Certificate, cert.Key); err != nil {
+			return fmt.Errorf("invalid certificate for domains %q: %w", strings.Join(cert.Domain.ToStrArray(), ","), err)
+		}
+	}
+
+	return nil
+}
+
+// mergeCertificates adds the certificates to the existing ones,
+// keeping the certificate expiring last for the same domains and TLS store.
+// It returns the merged certificates and the number of certificates added or replaced.
+func mergeCertificates(existing, certificates []*CertAndStore) ([]*CertAndStore, int) {
+	var count int
+	for _, cert := range certificates {
+		index := -1
+		for i, c := range existing {
+			if c.Store == cert.Store && strings.Join(c.Domain.ToStrArray(), ",") == strings.Join(cert.Domain.ToStrArray(), ",") {
+				index = i
+				break
+			}
+		}
+
+		if index < 0 {
+			existing = append(existing, cert)
+			count++
+			continue
+		}
+
+		if notAfter(cert).After(notAfter(existing[index])) {
+			existing[index] = cert
+			count++
+		}
+	}
+
+	return existing, count
+}
+
+func notAfter(cert *CertAndStore) (t time.Time) {
+	keyPair, err := tls.X509KeyPair(cert.Certificate.Certificate, cert.Key)
+	if err != nil {
+		return t
+	}
+
+	leaf, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return t
+	}
+
+	return leaf.NotAfter
+}
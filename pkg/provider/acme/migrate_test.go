@@ -0,0 +1,178 @@
+package acme
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestMigrateStorage(t *testing.T) {
+	dir := t.TempDir()
+
+	oldCert := newCertAndStore(t, "example.com", time.Now().Add(24*time.Hour))
+	newCert := newCertAndStore(t, "example.com", time.Now().Add(48*time.Hour))
+	otherCert := newCertAndStore(t, "example.org", time.Now().Add(24*time.Hour))
+
+	src := filepath.Join(dir, "src.json")
+	writeStorageFile(t, src, map[string]*StoredData{
+		"le":    {Account: newTestAccount(t, "src@example.com"), Certificates: []*CertAndStore{newCert, otherCert}},
+		"other": {Account: newTestAccount(t, "other@example.com")},
+	})
+
+	dst := filepath.Join(dir, "dst.json")
+	writeStorageFile(t, dst, map[string]*StoredData{
+		"myresolver": {Account: newTestAccount(t, "dst@example.com"), Certificates: []*CertAndStore{oldCert}},
+		"untouched":  {Account: newTestAccount(t, "untouched@example.com")},
+	})
+
+	count, err := MigrateStorage(src, dst, MigrationOptions{FromResolver: "le", ToResolver: "myresolver"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	data := readStorageFile(t, dst)
+	require.Len(t, data, 2)
+
+	assert.Equal(t, "dst@example.com", data["myresolver"].Account.Email)
+	assert.Equal(t, []*CertAndStore{newCert, otherCert}, data["myresolver"].Certificates)
+	assert.Equal(t, "untouched@example.com", data["untouched"].Account.Email)
+
+	fi, err := os.Stat(dst)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+}
+
+func TestMigrateStorage_allResolvers(t *testing.T) {
+	dir := t.TempDir()
+
+	cert := newCertAndStore(t, "example.com", time.Now().Add(24*time.Hour))
+
+	src := filepath.Join(dir, "src.json")
+	writeStorageFile(t, src, map[string]*StoredData{
+		"le":    {Account: newTestAccount(t, "le@example.com"), Certificates: []*CertAndStore{cert}},
+		"other": {Account: newTestAccount(t, "other@example.com")},
+	})
+
+	dst := filepath.Join(dir, "dst.json")
+
+	count, err := MigrateStorage(src, dst, MigrationOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	data := readStorageFile(t, dst)
+	require.Len(t, data, 2)
+
+	assert.Equal(t, "le@example.com", data["le"].Account.Email)
+	assert.Equal(t, []*CertAndStore{cert}, data["le"].Certificates)
+	assert.Equal(t, "other@example.com", data["other"].Account.Email)
+}
+
+func TestMigrateStorage_invalid(t *testing.T) {
+	dir := t.TempDir()
+
+	cert := newCertAndStore(t, "example.com", time.Now().Add(24*time.Hour))
+	cert.Key = newCertAndStore(t, "example.com", time.Now().Add(24*time.Hour)).Key
+
+	src := filepath.Join(dir, "src.json")
+	writeStorageFile(t, src, map[string]*StoredData{
+		"le": {Certificates: []*CertAndStore{cert}},
+	})
+
+	dst := filepath.Join(dir, "dst.json")
+	writeStorageFile(t, dst, map[string]*StoredData{
+		"le": {Account: newTestAccount(t, "dst@example.com")},
+	})
+
+	_, err := MigrateStorage(src, dst, MigrationOptions{})
+	require.Error(t, err)
+
+	data := readStorageFile(t, dst)
+	assert.Empty(t, data["le"].Certificates)
+}
+
+func TestMigrateStorage_unknownResolver(t *testing.T) {
+	dir := t.TempDir()
+
+	src := filepath.Join(dir, "src.json")
+	writeStorageFile(t, src, map[string]*StoredData{
+		"le": {Account: newTestAccount(t, "le@example.com")},
+	})
+
+	_, err := MigrateStorage(src, filepath.Join(dir, "dst.json"), MigrationOptions{FromResolver: "unknown"})
+	assert.Error(t, err)
+}
+
+func newTestAccount(t *testing.T, email string) *Account {
+	t.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	return &Account{Email: email, PrivateKey: x509.MarshalPKCS1PrivateKey(privateKey)}
+}
+
+func newCertAndStore(t *testing.T, domain string, notAfter time.Time) *CertAndStore {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: domain},
+		DNSNames:     []string{domain},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	require.NoError(t, err)
+
+	key, err := x509.MarshalECPrivateKey(privateKey)
+	require.NoError(t, err)
+
+	return &CertAndStore{
+		Certificate: Certificate{
+			Domain:      types.Domain{Main: domain},
+			Certificate: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			Key:         pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: key}),
+		},
+		Store: "default",
+	}
+}
+
+func writeStorageFile(t *testing.T, filename string, data map[string]*StoredData) {
+	t.Helper()
+
+	content, err := json.Marshal(data)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filename, content, 0o600)
+	require.NoError(t, err)
+}
+
+func readStorageFile(t *testing.T, filename string) map[string]*StoredData {
+	t.Helper()
+
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+
+	var data map[string]*StoredData
+	err = json.Unmarshal(content, &data)
+	require.NoError(t, err)
+
+	return data
+}
@@ -6,12 +6,14 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"reflect"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	"github.com/go-acme/lego/v4/certificate"
 	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/challenge/dns01"
@@ -20,6 +22,7 @@ import (
 	"github.com/go-acme/lego/v4/registration"
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/job"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/rules"
 	"github.com/traefik/traefik/v2/pkg/safe"
@@ -31,6 +34,9 @@ import (
 // oscpMustStaple enables OSCP stapling as from https://github.com/go-acme/lego/issues/270.
 var oscpMustStaple = false
 
+// renewBefore is the remaining validity below which the certificates are renewed.
+const renewBefore = 30 * 24 * time.Hour
+
 // Configuration holds ACME configuration provided by users.
 type Configuration struct {
 	Email          string `description:"Email address used for registration." json:"email,omitempty" toml:"email,omitempty" yaml:"email,omitempty"`
@@ -40,6 +46,11 @@ type Configuration struct {
 	KeyType        string `description:"KeyType used for generating certificate private key. Allow value 'EC256', 'EC384', 'RSA2048', 'RSA4096', 'RSA8192'." json:"keyType,omitempty" toml:"keyType,omitempty" yaml:"keyType,omitempty" export:"true"`
 	EAB            *EAB   `description:"External Account Binding to use." json:"eab,omitempty" toml:"eab,omitempty" yaml:"eab,omitempty"`
 
+	IssuanceQuota   *IssuanceQuota   `description:"Limits the number of certificates requested by the resolver." json:"issuanceQuota,omitempty" toml:"issuanceQuota,omitempty" yaml:"issuanceQuota,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	OrderScheduling *OrderScheduling `description:"Spreads the certificate orders of the resolver, and backs off the orders failing." json:"orderScheduling,omitempty" toml:"orderScheduling,omitempty" yaml:"orderScheduling,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+
+	KVStorage *KVStorage `description:"KV store shared by the Traefik instances, used rather than the storage file." json:"kvStorage,omitempty" toml:"kvStorage,omitempty" yaml:"kvStorage,omitempty" export:"true"`
+
 	DNSChallenge  *DNSChallenge  `description:"Activate DNS-01 Challenge." json:"dnsChallenge,omitempty" toml:"dnsChallenge,omitempty" yaml:"dnsChallenge,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	HTTPChallenge *HTTPChallenge `description:"Activate HTTP-01 Challenge." json:"httpChallenge,omitempty" toml:"httpChallenge,omitempty" yaml:"httpChallenge,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	TLSChallenge  *TLSChallenge  `description:"Activate TLS-ALPN-01 Challenge." json:"tlsChallenge,omitempty" toml:"tlsChallenge,omitempty" yaml:"tlsChallenge,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
@@ -50,6 +61,9 @@ func (a *Configuration) SetDefaults() {
 	a.CAServer = lego.LEDirectoryProduction
 	a.Storage = "acme.json"
 	a.KeyType = "RSA4096"
+
+	a.OrderScheduling = &OrderScheduling{}
+	a.OrderScheduling.SetDefaults()
 }
 
 // CertAndStore allows mapping a TLS certificate to a TLS store.
@@ -71,12 +85,43 @@ type EAB struct {
 	HmacEncoded string `description:"Base64 encoded HMAC key from External CA." json:"hmacEncoded,omitempty" toml:"hmacEncoded,omitempty" yaml:"hmacEncoded,omitempty"`
 }
 
+// IssuanceQuota limits the number of certificates requested by a resolver during a sliding period,
+// so that the failures or the rate limits of a resolver do not exhaust the rate limits shared with the other resolvers.
+type IssuanceQuota struct {
+	Certificates int             `description:"Maximum number of certificates requested during the period." json:"certificates,omitempty" toml:"certificates,omitempty" yaml:"certificates,omitempty" export:"true"`
+	Period       ptypes.Duration `description:"Period of the quota." json:"period,omitempty" toml:"period,omitempty" yaml:"period,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (q *IssuanceQuota) SetDefaults() {
+	q.Certificates = 50
+	q.Period = ptypes.Duration(7 * 24 * time.Hour)
+}
+
+// OrderScheduling spreads the certificate orders of a resolver, and backs off the orders failing,
+// so that a misconfigured router does not exhaust the rate limits of the CA.
+// The renewals are not scheduled.
+type OrderScheduling struct {
+	Interval              ptypes.Duration `description:"Minimum interval between two certificate orders." json:"interval,omitempty" toml:"interval,omitempty" yaml:"interval,omitempty" export:"true"`
+	RegisteredDomainLimit int             `description:"Maximum number of certificate orders per registered domain during a week." json:"registeredDomainLimit,omitempty" toml:"registeredDomainLimit,omitempty" yaml:"registeredDomainLimit,omitempty" export:"true"`
+	MaxBackoff            ptypes.Duration `description:"Maximum delay before ordering again a certificate which failed." json:"maxBackoff,omitempty" toml:"maxBackoff,omitempty" yaml:"maxBackoff,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (o *OrderScheduling) SetDefaults() {
+	o.Interval = ptypes.Duration(10 * time.Second)
+	o.RegisteredDomainLimit = 50
+	o.MaxBackoff = ptypes.Duration(24 * time.Hour)
+}
+
 // DNSChallenge contains DNS challenge configuration.
 type DNSChallenge struct {
 	Provider                string          `description:"Use a DNS-01 based challenge provider rather than HTTPS." json:"provider,omitempty" toml:"provider,omitempty" yaml:"provider,omitempty" export:"true"`
 	DelayBeforeCheck        ptypes.Duration `description:"Assume DNS propagates after a delay in seconds rather than finding and querying nameservers." json:"delayBeforeCheck,omitempty" toml:"delayBeforeCheck,omitempty" yaml:"delayBeforeCheck,omitempty" export:"true"`
 	Resolvers               []string        `description:"Use following DNS servers to resolve the FQDN authority." json:"resolvers,omitempty" toml:"resolvers,omitempty" yaml:"resolvers,omitempty"`
 	DisablePropagationCheck bool            `description:"Disable the DNS propagation checks before notifying ACME that the DNS challenge is ready. [not recommended]" json:"disablePropagationCheck,omitempty" toml:"disablePropagationCheck,omitempty" yaml:"disablePropagationCheck,omitempty" export:"true"`
+	Sequential              bool            `description:"Solve the DNS challenges of a certificate one after the other, rather than all at once." json:"sequential,omitempty" toml:"sequential,omitempty" yaml:"sequential,omitempty" export:"true"`
+	SequentialInterval      ptypes.Duration `description:"Delay between two DNS challenges solved sequentially." json:"sequentialInterval,omitempty" toml:"sequentialInterval,omitempty" yaml:"sequentialInterval,omitempty" export:"true"`
 }
 
 // HTTPChallenge contains HTTP challenge configuration.
@@ -96,10 +141,14 @@ type Provider struct {
 	TLSChallengeProvider  challenge.Provider
 	HTTPChallengeProvider challenge.Provider
 
+	// HTTPClient, if set, is the client used to reach the CA server (e.g. to inject faults in tests).
+	HTTPClient *http.Client
+
 	certificates           []*CertAndStore
 	account                *Account
 	client                 *lego.Client
 	certsChan              chan *CertAndStore
+	sharedCertsChan        chan []*CertAndStore
 	configurationChan      chan<- dynamic.Message
 	tlsManager             *traefiktls.Manager
 	clientMutex            sync.Mutex
@@ -107,6 +156,9 @@ type Provider struct {
 	pool                   *safe.Pool
 	resolvingDomains       map[string]struct{}
 	resolvingDomainsMutex  sync.RWMutex
+	issuances              []time.Time
+	issuancesMutex         sync.Mutex
+	scheduler              *orderScheduler
 }
 
 // SetTLSManager sets the tls manager to use.
@@ -133,6 +185,14 @@ func (p *Provider) Init() error {
 		return errors.New("unable to initialize ACME provider with no storage location for the certificates")
 	}
 
+	if p.OrderScheduling != nil && (p.OrderScheduling.Interval < 0 || p.OrderScheduling.RegisteredDomainLimit < 0 || p.OrderScheduling.MaxBackoff < 0) {
+		return errors.New("unable to initialize ACME provider with a negative order scheduling option")
+	}
+
+	if p.DNSChallenge != nil && (p.DNSChallenge.DelayBeforeCheck < 0 || p.DNSChallenge.SequentialInterval < 0) {
+		return errors.New("unable to initialize ACME provider with a negative DNS challenge delay")
+	}
+
 	var err error
 	p.account, err = p.Store.GetAccount(p.ResolverName)
 	if err != nil {
@@ -153,9 +213,22 @@ func (p *Provider) Init() error {
 	// Init the currently resolved domain map
 	p.resolvingDomains = make(map[string]struct{})
 
+	p.scheduler, err = newOrderScheduler(p.OrderScheduling, p.Store, p.ResolverName)
+	if err != nil {
+		return fmt.Errorf("unable to get ACME order attempts: %w", err)
+	}
+
 	return nil
 }
 
+// OrdersState returns the state of the certificate orders of the resolver.
+func (p *Provider) OrdersState() OrdersState {
+	state := p.scheduler.state(time.Now())
+	state.Resolver = p.ResolverName
+
+	return state
+}
+
 func isAccountMatchingCaServer(ctx context.Context, accountURI, serverURI string) bool {
 	logger := log.FromContext(ctx)
 
@@ -187,6 +260,10 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 	p.configurationChan = configurationChan
 	p.refreshCertificates()
 
+	if sharedStore, ok := p.Store.(SharedStore); ok {
+		p.watchSharedCertificates(ctx, sharedStore)
+	}
+
 	p.renewCertificates(ctx)
 
 	ticker := time.NewTicker(24 * time.Hour)
@@ -233,6 +310,9 @@ func (p *Provider) getClient() (*lego.Client, error) {
 	config.CADirURL = caServer
 	config.Certificate.KeyType = GetKeyType(ctx, p.KeyType)
 	config.UserAgent = fmt.Sprintf("containous-traefik/%s", version.Version)
+	if p.HTTPClient != nil {
+		config.HTTPClient = p.HTTPClient
+	}
 
 	client, err := lego.NewClient(config)
 	if err != nil {
@@ -271,11 +351,15 @@ func (p *Provider) getClient() (*lego.Client, error) {
 			return nil, err
 		}
 
+		if p.DNSChallenge.Sequential {
+			provider = sequentialDNSProvider{Provider: provider, interval: time.Duration(p.DNSChallenge.SequentialInterval)}
+		}
+
 		err = client.Challenge.SetDNS01Provider(provider,
 			dns01.CondOption(len(p.DNSChallenge.Resolvers) > 0, dns01.AddRecursiveNameservers(p.DNSChallenge.Resolvers)),
 			dns01.WrapPreCheck(func(domain, fqdn, value string, check dns01.PreCheckFunc) (bool, error) {
 				if p.DNSChallenge.DelayBeforeCheck > 0 {
-					logger.Debugf("Delaying %d rather than validating DNS propagation now.", p.DNSChallenge.DelayBeforeCheck)
+					logger.Debugf("Delaying %s rather than validating DNS propagation now.", time.Duration(p.DNSChallenge.DelayBeforeCheck))
 					time.Sleep(time.Duration(p.DNSChallenge.DelayBeforeCheck))
 				}
 
@@ -383,6 +467,11 @@ func (p *Provider) watchNewDomains(ctx context.Context) {
 						ctxRouter := log.With(ctx, log.Str(log.RouterName, routerName), log.Str(log.Rule, route.Rule))
 						logger := log.FromContext(ctxRouter)
 
+						if route.TLS.Passthrough {
+							logger.Warn("No ACME certificate is requested for a router passing the TLS connections through to the services")
+							continue
+						}
+
 						if len(route.TLS.Domains) > 0 {
 							for _, domain := range route.TLS.Domains {
 								if domain.Main != dns01.UnFqdn(domain.Main) {
@@ -406,7 +495,7 @@ func (p *Provider) watchNewDomains(ctx context.Context) {
 								})
 							}
 						} else {
-							domains, err := rules.ParseHostSNI(route.Rule)
+							domains, err := parseHostSNI(route.Rule)
 							if err != nil {
 								logger.Errorf("Error parsing domains in provider ACME: %v", err)
 								continue
@@ -464,7 +553,43 @@ func (p *Provider) resolveCertificate(ctx context.Context, domain types.Domain,
 
 	defer p.removeResolvingDomains(uncheckedDomains)
 
+	if len(uncheckedDomains) > 1 {
+		domain = types.Domain{Main: uncheckedDomains[0], SANs: uncheckedDomains[1:]}
+	} else {
+		domain = types.Domain{Main: uncheckedDomains[0]}
+	}
+
 	logger := log.FromContext(ctx)
+
+	slot, err := p.scheduler.schedule(uncheckedDomains, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if delay := time.Until(slot); delay > 0 {
+		logger.Debugf("Ordering the certificate for domains %+v in %s...", uncheckedDomains, delay.Round(time.Second))
+	}
+
+	if err := p.scheduler.wait(ctx, slot); err != nil {
+		return nil, err
+	}
+
+	unlock, err := p.lockStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if sharedCert := p.getSharedCertificate(ctx, domain, tlsStore); sharedCert != nil {
+		logger.Debugf("Using the certificate obtained by another instance for domains %+v", uncheckedDomains)
+		p.certsChan <- sharedCert
+		return nil, nil
+	}
+
+	if err := p.reserveIssuance(time.Now()); err != nil {
+		return nil, err
+	}
+
 	logger.Debugf("Loading ACME certificates %+v...", uncheckedDomains)
 
 	client, err := p.getClient()
@@ -481,8 +606,11 @@ func (p *Provider) resolveCertificate(ctx context.Context, domain types.Domain,
 
 	cert, err := client.Certificate.Obtain(request)
 	if err != nil {
+		p.scheduler.failed(uncheckedDomains, err, time.Now())
 		return nil, fmt.Errorf("unable to generate a certificate for the domains %v: %w", uncheckedDomains, err)
 	}
+
+	p.scheduler.succeeded(uncheckedDomains)
 	if cert == nil {
 		return nil, fmt.Errorf("domains %v do not generate a certificate", uncheckedDomains)
 	}
@@ -492,16 +620,40 @@ func (p *Provider) resolveCertificate(ctx context.Context, domain types.Domain,
 
 	logger.Debugf("Certificates obtained for domains %+v", uncheckedDomains)
 
-	if len(uncheckedDomains) > 1 {
-		domain = types.Domain{Main: uncheckedDomains[0], SANs: uncheckedDomains[1:]}
-	} else {
-		domain = types.Domain{Main: uncheckedDomains[0]}
-	}
-	p.addCertificateForDomain(domain, cert.Certificate, cert.PrivateKey, tlsStore)
+	p.addCertificateForDomain(ctx, domain, cert.Certificate, cert.PrivateKey, tlsStore)
 
 	return cert, nil
 }
 
+// reserveIssuance reserves a certificate request in the issuance quota of the resolver, if any.
+func (p *Provider) reserveIssuance(now time.Time) error {
+	if p.IssuanceQuota == nil || p.IssuanceQuota.Certificates <= 0 {
+		return nil
+	}
+
+	p.issuancesMutex.Lock()
+	defer p.issuancesMutex.Unlock()
+
+	since := now.Add(-time.Duration(p.IssuanceQuota.Period))
+
+	var issuances []time.Time
+	for _, issuance := range p.issuances {
+		if issuance.After(since) {
+			issuances = append(issuances, issuance)
+		}
+	}
+
+	if len(issuances) >= p.IssuanceQuota.Certificates {
+		p.issuances = issuances
+		return fmt.Errorf("issuance quota of the resolver %s exceeded: %d certificates requested in the last %s",
+			p.ResolverName, len(issuances), time.Duration(p.IssuanceQuota.Period))
+	}
+
+	p.issuances = append(issuances, now)
+
+	return nil
+}
+
 func (p *Provider) removeResolvingDomains(resolvingDomains []string) {
 	p.resolvingDomainsMutex.Lock()
 	defer p.resolvingDomainsMutex.Unlock()
@@ -511,8 +663,74 @@ func (p *Provider) removeResolvingDomains(resolvingDomains []string) {
 	}
 }
 
-func (p *Provider) addCertificateForDomain(domain types.Domain, certificate, key []byte, tlsStore string) {
-	p.certsChan <- &CertAndStore{Certificate: Certificate{Certificate: certificate, Key: key, Domain: domain}, Store: tlsStore}
+func (p *Provider) addCertificateForDomain(ctx context.Context, domain types.Domain, certificate, key []byte, tlsStore string) {
+	cert := &CertAndStore{Certificate: Certificate{Certificate: certificate, Key: key, Domain: domain}, Store: tlsStore}
+
+	// The certificate is saved in the shared store before the lock of the resolver is released,
+	// so that the other instances do not request it again.
+	if _, ok := p.Store.(SharedStore); ok {
+		if err := p.Store.SaveCertificates(p.ResolverName, []*CertAndStore{cert}); err != nil {
+			log.FromContext(ctx).Errorf("Unable to save the certificate for domains %v: %v", domain.ToStrArray(), err)
+		}
+	}
+
+	p.certsChan <- cert
+}
+
+// lockStore acquires the lock of the resolver when its store is shared by several instances,
+// and returns the function releasing it.
+func (p *Provider) lockStore(ctx context.Context) (func(), error) {
+	sharedStore, ok := p.Store.(SharedStore)
+	if !ok {
+		return func() {}, nil
+	}
+
+	return sharedStore.Lock(ctx, p.ResolverName)
+}
+
+// getSharedCertificate returns the certificate of the domain saved by another instance in the shared store, if it does not need to be renewed.
+func (p *Provider) getSharedCertificate(ctx context.Context, domain types.Domain, tlsStore string) *CertAndStore {
+	if _, ok := p.Store.(SharedStore); !ok {
+		return nil
+	}
+
+	certificates, err := p.Store.GetCertificates(p.ResolverName)
+	if err != nil {
+		log.FromContext(ctx).Errorf("Unable to get the shared certificates: %v", err)
+		return nil
+	}
+
+	for _, cert := range certificates {
+		if cert.Store != tlsStore || !reflect.DeepEqual(cert.Domain, domain) {
+			continue
+		}
+
+		crt, err := getX509Certificate(ctx, &cert.Certificate)
+		if err != nil || crt == nil || crt.NotAfter.Before(time.Now().Add(renewBefore)) || !matchesKeyType(crt, GetKeyType(ctx, p.KeyType)) {
+			return nil
+		}
+
+		return cert
+	}
+
+	return nil
+}
+
+// parseHostSNI returns the domains of the HostSNI rule, without the catch-all HostSNI(`*`) for which no certificate can be requested.
+func parseHostSNI(rule string) ([]string, error) {
+	domains, err := rules.ParseHostSNI(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	var sniDomains []string
+	for _, domain := range domains {
+		if domain != "*" {
+			sniDomains = append(sniDomains, domain)
+		}
+	}
+
+	return sniDomains, nil
 }
 
 // deleteUnnecessaryDomains deletes from the configuration :
@@ -574,6 +792,7 @@ func deleteUnnecessaryDomains(ctx context.Context, domains []types.Domain) []typ
 
 func (p *Provider) watchCertificate(ctx context.Context) {
 	p.certsChan = make(chan *CertAndStore)
+	p.sharedCertsChan = make(chan []*CertAndStore)
 
 	p.pool.GoCtx(func(ctxPool context.Context) {
 		for {
@@ -595,6 +814,13 @@ func (p *Provider) watchCertificate(ctx context.Context) {
 				if err != nil {
 					log.FromContext(ctx).Error(err)
 				}
+			case certificates := <-p.sharedCertsChan:
+				// The certificates saved by the other instances are only loaded, as they are already in the shared store.
+				merged := mergeStoredCertificates(p.certificates, certificates)
+				if !reflect.DeepEqual(merged, p.certificates) {
+					p.certificates = merged
+					p.refreshCertificates()
+				}
 			case <-ctxPool.Done():
 				return
 			}
@@ -602,6 +828,42 @@ func (p *Provider) watchCertificate(ctx context.Context) {
 	})
 }
 
+// watchSharedCertificates loads the certificates saved by the other instances in the shared store.
+func (p *Provider) watchSharedCertificates(ctx context.Context, sharedStore SharedStore) {
+	p.pool.GoCtx(func(ctxPool context.Context) {
+		operation := func() error {
+			certificatesChan, err := sharedStore.WatchCertificates(ctxPool, p.ResolverName)
+			if err != nil {
+				return fmt.Errorf("failed to watch the shared certificates: %w", err)
+			}
+
+			for certificates := range certificatesChan {
+				select {
+				case p.sharedCertsChan <- certificates:
+				case <-ctxPool.Done():
+					return nil
+				}
+			}
+
+			if ctxPool.Err() != nil {
+				return nil
+			}
+
+			return errors.New("the watch of the shared certificates is closed")
+		}
+
+		notify := func(err error, time time.Duration) {
+			log.FromContext(ctx).Errorf("Shared store error: %v, retrying in %s", err, time)
+		}
+
+		err := backoff.RetryNotify(safe.OperationWithRecover(operation),
+			backoff.WithContext(job.NewBackOff(backoff.NewExponentialBackOff()), ctxPool), notify)
+		if err != nil {
+			log.FromContext(ctx).Errorf("Cannot watch the shared certificates: %v", err)
+		}
+	})
+}
+
 func (p *Provider) saveCertificates() error {
 	err := p.Store.SaveCertificates(p.ResolverName, p.certificates)
 
@@ -641,22 +903,46 @@ func (p *Provider) renewCertificates(ctx context.Context) {
 	logger := log.FromContext(ctx)
 
 	logger.Info("Testing certificate renew...")
+
+	unlock, err := p.lockStore(ctx)
+	if err != nil {
+		logger.Errorf("Unable to renew the certificates: %v", err)
+		return
+	}
+	defer unlock()
+
+	keyType := GetKeyType(ctx, p.KeyType)
+
 	for _, cert := range p.certificates {
 		crt, err := getX509Certificate(ctx, &cert.Certificate)
+		// The certificates issued with another key type than the configured one are issued again with a new key.
+		keyTypeChanged := err == nil && crt != nil && !matchesKeyType(crt, keyType)
 		// If there's an error, we assume the cert is broken, and needs update
 		// <= 30 days left, renew certificate
-		if err != nil || crt == nil || crt.NotAfter.Before(time.Now().Add(24*30*time.Hour)) {
+		if err != nil || crt == nil || crt.NotAfter.Before(time.Now().Add(renewBefore)) || keyTypeChanged {
+			if sharedCert := p.getSharedCertificate(ctx, cert.Domain, cert.Store); sharedCert != nil {
+				logger.Infof("Using the certificate renewed by another instance : %+v", cert.Domain)
+				p.certsChan <- sharedCert
+				continue
+			}
+
 			client, err := p.getClient()
 			if err != nil {
 				logger.Infof("Error renewing certificate from LE : %+v, %v", cert.Domain, err)
 				continue
 			}
 
-			logger.Infof("Renewing certificate from LE : %+v", cert.Domain)
+			privateKey := cert.Key
+			if keyTypeChanged {
+				logger.Infof("Renewing certificate from LE with a %s key : %+v", keyType, cert.Domain)
+				privateKey = nil
+			} else {
+				logger.Infof("Renewing certificate from LE : %+v", cert.Domain)
+			}
 
 			renewedCert, err := client.Certificate.Renew(certificate.Resource{
 				Domain:      cert.Domain.Main,
-				PrivateKey:  cert.Key,
+				PrivateKey:  privateKey,
 				Certificate: cert.Certificate.Certificate,
 			}, true, oscpMustStaple, p.PreferredChain)
 			if err != nil {
@@ -669,7 +955,7 @@ func (p *Provider) renewCertificates(ctx context.Context) {
 				continue
 			}
 
-			p.addCertificateForDomain(cert.Domain, renewedCert.Certificate, renewedCert.PrivateKey, cert.Store)
+			p.addCertificateForDomain(ctx, cert.Domain, renewedCert.Certificate, renewedCert.PrivateKey, cert.Store)
 		}
 	}
 }
@@ -2,11 +2,19 @@ package acme
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
 	"crypto/tls"
+	"crypto/x509"
 	"testing"
+	"time"
 
 	"github.com/go-acme/lego/v4/certcrypto"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/safe"
 	"github.com/traefik/traefik/v2/pkg/types"
 )
@@ -592,3 +600,130 @@ func TestInitAccount(t *testing.T) {
 		})
 	}
 }
+
+func TestProvider_reserveIssuance(t *testing.T) {
+	p := &Provider{
+		Configuration: &Configuration{
+			IssuanceQuota: &IssuanceQuota{Certificates: 2, Period: ptypes.Duration(time.Hour)},
+		},
+		ResolverName: "tenant",
+	}
+
+	now := time.Now()
+
+	require.NoError(t, p.reserveIssuance(now))
+	require.NoError(t, p.reserveIssuance(now.Add(10*time.Minute)))
+	assert.EqualError(t, p.reserveIssuance(now.Add(20*time.Minute)), "issuance quota of the resolver tenant exceeded: 2 certificates requested in the last 1h0m0s")
+
+	// The first request leaves the period.
+	require.NoError(t, p.reserveIssuance(now.Add(61*time.Minute)))
+	assert.Error(t, p.reserveIssuance(now.Add(62*time.Minute)))
+
+	// Without quota.
+	p.IssuanceQuota = nil
+	require.NoError(t, p.reserveIssuance(now.Add(63*time.Minute)))
+}
+
+func TestParseHostSNI(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rule     string
+		expected []string
+	}{
+		{
+			desc:     "one domain",
+			rule:     "HostSNI(`postgres.example.com`)",
+			expected: []string{"postgres.example.com"},
+		},
+		{
+			desc:     "several domains",
+			rule:     "HostSNI(`mqtt.example.com`, `MQTT.example.org`)",
+			expected: []string{"mqtt.example.com", "mqtt.example.org"},
+		},
+		{
+			desc: "catch-all",
+			rule: "HostSNI(`*`)",
+		},
+		{
+			desc:     "catch-all and domain",
+			rule:     "HostSNI(`*`) || HostSNI(`mqtt.example.com`)",
+			expected: []string{"mqtt.example.com"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			domains, err := parseHostSNI(test.rule)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, domains)
+		})
+	}
+}
+
+func TestMatchesKeyType(t *testing.T) {
+	ec256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ec384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	rsa2048Key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc      string
+		publicKey interface{}
+		keyType   certcrypto.KeyType
+		expected  bool
+	}{
+		{
+			desc:      "EC256",
+			publicKey: &ec256Key.PublicKey,
+			keyType:   certcrypto.EC256,
+			expected:  true,
+		},
+		{
+			desc:      "EC384",
+			publicKey: &ec384Key.PublicKey,
+			keyType:   certcrypto.EC384,
+			expected:  true,
+		},
+		{
+			desc:      "RSA2048",
+			publicKey: &rsa2048Key.PublicKey,
+			keyType:   certcrypto.RSA2048,
+			expected:  true,
+		},
+		{
+			desc:      "EC256 instead of EC384",
+			publicKey: &ec256Key.PublicKey,
+			keyType:   certcrypto.EC384,
+		},
+		{
+			desc:      "RSA2048 instead of RSA4096",
+			publicKey: &rsa2048Key.PublicKey,
+			keyType:   certcrypto.RSA4096,
+		},
+		{
+			desc:      "RSA2048 instead of EC256",
+			publicKey: &rsa2048Key.PublicKey,
+			keyType:   certcrypto.EC256,
+		},
+		{
+			desc:      "EC256 instead of RSA4096",
+			publicKey: &ec256Key.PublicKey,
+			keyType:   certcrypto.RSA4096,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, matchesKeyType(&x509.Certificate{PublicKey: test.publicKey}, test.keyType))
+		})
+	}
+}
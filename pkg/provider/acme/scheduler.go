@@ -0,0 +1,315 @@
+package acme
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+	"golang.org/x/net/publicsuffix"
+)
+
+const (
+	// orderBackoffInitialInterval is the delay before ordering again a certificate after its first failure,
+	// doubled after each new failure, which keeps the orders below the failed validations rate limit of Let's Encrypt.
+	orderBackoffInitialInterval = 15 * time.Minute
+	// registeredDomainWindow is the sliding window of the certificates per registered domain rate limit.
+	registeredDomainWindow = 7 * 24 * time.Hour
+)
+
+// OrdersState is the state of the certificate orders of an ACME certificates resolver.
+type OrdersState struct {
+	Resolver          string                     `json:"resolver"`
+	Queued            int                        `json:"queued"`
+	RegisteredDomains []RegisteredDomainAttempts `json:"registeredDomains,omitempty"`
+	Backoffs          []OrderBackoff             `json:"backoffs,omitempty"`
+}
+
+// RegisteredDomainAttempts is the number of certificate orders attempted for a registered domain during the last week.
+type RegisteredDomainAttempts struct {
+	Domain   string `json:"domain"`
+	Attempts int    `json:"attempts"`
+	Limit    int    `json:"limit"`
+}
+
+// OrderBackoff is the backoff of the orders of a certificate which failed.
+type OrderBackoff struct {
+	Domains   []string  `json:"domains"`
+	Failures  int       `json:"failures"`
+	Until     time.Time `json:"until"`
+	LastError string    `json:"lastError"`
+}
+
+// orderScheduler spreads the certificate orders of a resolver, and backs off the orders failing.
+type orderScheduler struct {
+	interval   time.Duration
+	limit      int
+	maxBackoff time.Duration
+
+	// store persists the attempts, so that the limit of the registered domains still applies after a restart.
+	store        Store
+	resolverName string
+
+	mu sync.Mutex
+	// slots are the times reserved for the orders, in ascending order.
+	slots    []time.Time
+	queued   int
+	attempts map[string][]time.Time
+	backoffs map[string]*OrderBackoff
+}
+
+func newOrderScheduler(config *OrderScheduling, store Store, resolverName string) (*orderScheduler, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	attempts, err := store.GetOrderAttempts(resolverName)
+	if err != nil {
+		return nil, err
+	}
+
+	if attempts == nil {
+		attempts = make(map[string][]time.Time)
+	}
+
+	return &orderScheduler{
+		interval:     time.Duration(config.Interval),
+		limit:        config.RegisteredDomainLimit,
+		maxBackoff:   time.Duration(config.MaxBackoff),
+		store:        store,
+		resolverName: resolverName,
+		attempts:     attempts,
+		backoffs:     make(map[string]*OrderBackoff),
+	}, nil
+}
+
+// schedule reserves the time at which the certificate of the domains can be ordered:
+// after the backoff of the domains if their last order failed, and at least one interval away from the other orders.
+// It returns an error if the order would exceed the limit of one of the registered domains.
+func (s *orderScheduler) schedule(domains []string, now time.Time) (time.Time, error) {
+	if s == nil {
+		return now, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	registeredDomains := getRegisteredDomains(domains)
+
+	if s.limit > 0 {
+		for _, registeredDomain := range registeredDomains {
+			attempts := s.pruneAttempts(registeredDomain, now)
+			if len(attempts) >= s.limit {
+				return time.Time{}, fmt.Errorf("certificates per registered domain limit exceeded for %s: %d orders attempted in the last %s",
+					registeredDomain, len(attempts), registeredDomainWindow)
+			}
+		}
+	}
+
+	slot := now
+	if backoff, ok := s.backoffs[strings.Join(domains, ",")]; ok && backoff.Until.After(slot) {
+		slot = backoff.Until
+	}
+
+	var slots []time.Time
+	for _, reserved := range s.slots {
+		if reserved.After(now.Add(-s.interval)) {
+			slots = append(slots, reserved)
+		}
+	}
+
+	for _, reserved := range slots {
+		if slot.Sub(reserved) < s.interval && reserved.Sub(slot) < s.interval {
+			slot = reserved.Add(s.interval)
+		}
+	}
+
+	s.slots = append(slots, slot)
+	sort.Slice(s.slots, func(i, j int) bool { return s.slots[i].Before(s.slots[j]) })
+
+	for _, registeredDomain := range registeredDomains {
+		s.attempts[registeredDomain] = append(s.attempts[registeredDomain], slot)
+	}
+
+	s.saveAttempts(now)
+
+	return slot, nil
+}
+
+// saveAttempts persists a copy of the attempts still in the window in the store.
+// The order is not prevented if the attempts cannot be saved.
+func (s *orderScheduler) saveAttempts(now time.Time) {
+	attempts := make(map[string][]time.Time, len(s.attempts))
+	for registeredDomain := range s.attempts {
+		if domainAttempts := s.pruneAttempts(registeredDomain, now); len(domainAttempts) > 0 {
+			attempts[registeredDomain] = append([]time.Time{}, domainAttempts...)
+		}
+	}
+
+	if err := s.store.SaveOrderAttempts(s.resolverName, attempts); err != nil {
+		log.WithoutContext().WithField(log.ProviderName, s.resolverName+".acme").
+			Errorf("Unable to save the order attempts: %v", err)
+	}
+}
+
+// wait waits until the reserved time, the order being queued meanwhile.
+func (s *orderScheduler) wait(ctx context.Context, slot time.Time) error {
+	delay := time.Until(slot)
+	if s == nil || delay <= 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	s.queued++
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.queued--
+		s.mu.Unlock()
+	}()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// failed backs off the orders of the domains, doubling the backoff after each failure.
+func (s *orderScheduler) failed(domains []string, err error, now time.Time) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.Join(domains, ",")
+
+	backoff, ok := s.backoffs[key]
+	if !ok {
+		backoff = &OrderBackoff{Domains: domains}
+		s.backoffs[key] = backoff
+	}
+
+	maxBackoff := s.maxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = registeredDomainWindow
+	}
+
+	delay := orderBackoffInitialInterval
+	for i := 0; i < backoff.Failures && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+
+	backoff.Failures++
+	backoff.Until = now.Add(delay)
+	backoff.LastError = err.Error()
+}
+
+// succeeded resets the backoff of the orders of the domains.
+func (s *orderScheduler) succeeded(domains []string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.backoffs, strings.Join(domains, ","))
+}
+
+func (s *orderScheduler) state(now time.Time) OrdersState {
+	var state OrdersState
+	if s == nil {
+		return state
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state.Queued = s.queued
+
+	for registeredDomain := range s.attempts {
+		attempts := s.pruneAttempts(registeredDomain, now)
+		if len(attempts) == 0 {
+			continue
+		}
+
+		state.RegisteredDomains = append(state.RegisteredDomains, RegisteredDomainAttempts{
+			Domain:   registeredDomain,
+			Attempts: len(attempts),
+			Limit:    s.limit,
+		})
+	}
+
+	sort.Slice(state.RegisteredDomains, func(i, j int) bool {
+		return state.RegisteredDomains[i].Domain < state.RegisteredDomains[j].Domain
+	})
+
+	for _, backoff := range s.backoffs {
+		state.Backoffs = append(state.Backoffs, *backoff)
+	}
+
+	sort.Slice(state.Backoffs, func(i, j int) bool {
+		return strings.Join(state.Backoffs[i].Domains, ",") < strings.Join(state.Backoffs[j].Domains, ",")
+	})
+
+	return state
+}
+
+// pruneAttempts drops the attempts of the registered domain which left the window, and returns the remaining ones.
+func (s *orderScheduler) pruneAttempts(registeredDomain string, now time.Time) []time.Time {
+	since := now.Add(-registeredDomainWindow)
+
+	var attempts []time.Time
+	for _, attempt := range s.attempts[registeredDomain] {
+		if attempt.After(since) {
+			attempts = append(attempts, attempt)
+		}
+	}
+
+	if len(attempts) == 0 {
+		delete(s.attempts, registeredDomain)
+		return nil
+	}
+
+	s.attempts[registeredDomain] = attempts
+
+	return attempts
+}
+
+// getRegisteredDomains returns the distinct registered domains (e.g. example.co.uk for www.example.co.uk) of the domains.
+func getRegisteredDomains(domains []string) []string {
+	seen := make(map[string]struct{})
+
+	var registeredDomains []string
+	for _, domain := range domains {
+		domain = strings.TrimPrefix(domain, "*.")
+
+		registeredDomain, err := publicsuffix.EffectiveTLDPlusOne(domain)
+		if err != nil {
+			registeredDomain = domain
+		}
+
+		if _, ok := seen[registeredDomain]; ok {
+			continue
+		}
+
+		seen[registeredDomain] = struct{}{}
+		registeredDomains = append(registeredDomains, registeredDomain)
+	}
+
+	return registeredDomains
+}
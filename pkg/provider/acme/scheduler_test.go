@@ -0,0 +1,246 @@
+package acme
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+)
+
+func newTestOrderScheduler(t *testing.T, store Store, limit int) *orderScheduler {
+	t.Helper()
+
+	scheduler, err := newOrderScheduler(&OrderScheduling{
+		Interval:              ptypes.Duration(time.Minute),
+		RegisteredDomainLimit: limit,
+		MaxBackoff:            ptypes.Duration(time.Hour),
+	}, store, "le")
+	require.NoError(t, err)
+
+	return scheduler
+}
+
+func newTestLocalStore(t *testing.T) *LocalStore {
+	t.Helper()
+
+	return NewLocalStore(filepath.Join(t.TempDir(), "acme.json"))
+}
+
+func TestOrderScheduler_schedule(t *testing.T) {
+	scheduler := newTestOrderScheduler(t, newTestLocalStore(t), 0)
+
+	now := time.Now()
+
+	slot, err := scheduler.schedule([]string{"foo.example.com"}, now)
+	require.NoError(t, err)
+	assert.Equal(t, now, slot)
+
+	// The orders are spread by the interval.
+	slot, err = scheduler.schedule([]string{"bar.example.com"}, now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Minute), slot)
+
+	slot, err = scheduler.schedule([]string{"baz.example.org"}, now.Add(10*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(2*time.Minute), slot)
+
+	// The slots left free are used.
+	slot, err = scheduler.schedule([]string{"qux.example.org"}, now.Add(10*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(10*time.Minute), slot)
+}
+
+func TestOrderScheduler_schedule_backoff(t *testing.T) {
+	scheduler := newTestOrderScheduler(t, newTestLocalStore(t), 0)
+
+	now := time.Now()
+	domains := []string{"*.example.com"}
+
+	expectedDelays := []time.Duration{
+		15 * time.Minute,
+		30 * time.Minute,
+		time.Hour,
+		// The backoff is limited to the max backoff.
+		time.Hour,
+	}
+
+	for i, expectedDelay := range expectedDelays {
+		scheduler.failed(domains, errors.New("DNS problem"), now)
+
+		slot, err := scheduler.schedule(domains, now)
+		require.NoError(t, err)
+		assert.Equal(t, now.Add(expectedDelay), slot, "failure %d", i+1)
+
+		now = slot
+	}
+
+	// The other domains are not backed off.
+	slot, err := scheduler.schedule([]string{"foo.example.com"}, now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Minute), slot)
+
+	// A success resets the backoff.
+	scheduler.succeeded(domains)
+
+	slot, err = scheduler.schedule(domains, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Hour), slot)
+}
+
+func TestOrderScheduler_schedule_registeredDomainLimit(t *testing.T) {
+	scheduler := newTestOrderScheduler(t, newTestLocalStore(t), 2)
+
+	now := time.Now()
+
+	_, err := scheduler.schedule([]string{"foo.example.co.uk"}, now)
+	require.NoError(t, err)
+
+	_, err = scheduler.schedule([]string{"bar.example.co.uk", "example.org"}, now.Add(time.Hour))
+	require.NoError(t, err)
+
+	_, err = scheduler.schedule([]string{"*.example.co.uk"}, now.Add(2*time.Hour))
+	assert.EqualError(t, err, "certificates per registered domain limit exceeded for example.co.uk: 2 orders attempted in the last 168h0m0s")
+
+	// The other registered domains are not limited.
+	_, err = scheduler.schedule([]string{"example.org"}, now.Add(2*time.Hour))
+	require.NoError(t, err)
+
+	// The first attempt leaves the window.
+	_, err = scheduler.schedule([]string{"*.example.co.uk"}, now.Add(registeredDomainWindow+time.Minute))
+	require.NoError(t, err)
+}
+
+func TestOrderScheduler_schedule_savedAttempts(t *testing.T) {
+	store := newTestLocalStore(t)
+
+	now := time.Now()
+
+	_, err := newTestOrderScheduler(t, store, 2).schedule([]string{"foo.example.com"}, now.Add(-registeredDomainWindow))
+	require.NoError(t, err)
+
+	scheduler := newTestOrderScheduler(t, store, 2)
+
+	_, err = scheduler.schedule([]string{"bar.example.com"}, now)
+	require.NoError(t, err)
+
+	// The attempts left the window are not saved.
+	attempts, err := store.GetOrderAttempts("le")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]time.Time{"example.com": {now}}, attempts)
+
+	// A restarted scheduler counts the saved attempts.
+	scheduler = newTestOrderScheduler(t, store, 2)
+
+	_, err = scheduler.schedule([]string{"baz.example.com"}, now.Add(time.Hour))
+	require.NoError(t, err)
+
+	_, err = scheduler.schedule([]string{"qux.example.com"}, now.Add(2*time.Hour))
+	assert.EqualError(t, err, "certificates per registered domain limit exceeded for example.com: 2 orders attempted in the last 168h0m0s")
+}
+
+func TestOrderScheduler_wait(t *testing.T) {
+	scheduler := newTestOrderScheduler(t, newTestLocalStore(t), 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	errCh := make(chan error)
+	go func() {
+		errCh <- scheduler.wait(ctx, time.Now().Add(time.Hour))
+	}()
+
+	assert.Eventually(t, func() bool {
+		return scheduler.state(time.Now()).Queued == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+
+	assert.ErrorIs(t, <-errCh, context.Canceled)
+	assert.Equal(t, 0, scheduler.state(time.Now()).Queued)
+
+	// The slots already reached are not waited.
+	assert.NoError(t, scheduler.wait(context.Background(), time.Now()))
+}
+
+func TestProvider_OrdersState(t *testing.T) {
+	p := &Provider{
+		Configuration: &Configuration{
+			OrderScheduling: &OrderScheduling{
+				Interval:              ptypes.Duration(time.Minute),
+				RegisteredDomainLimit: 50,
+				MaxBackoff:            ptypes.Duration(24 * time.Hour),
+			},
+		},
+		ResolverName: "le",
+	}
+	var err error
+	p.scheduler, err = newOrderScheduler(p.OrderScheduling, newTestLocalStore(t), p.ResolverName)
+	require.NoError(t, err)
+
+	now := time.Now()
+
+	_, err = p.scheduler.schedule([]string{"www.example.com", "example.com"}, now)
+	require.NoError(t, err)
+	_, err = p.scheduler.schedule([]string{"*.example.org"}, now)
+	require.NoError(t, err)
+
+	p.scheduler.failed([]string{"*.example.org"}, errors.New("DNS problem"), now)
+
+	expected := OrdersState{
+		Resolver: "le",
+		RegisteredDomains: []RegisteredDomainAttempts{
+			{Domain: "example.com", Attempts: 1, Limit: 50},
+			{Domain: "example.org", Attempts: 1, Limit: 50},
+		},
+		Backoffs: []OrderBackoff{
+			{Domains: []string{"*.example.org"}, Failures: 1, Until: now.Add(15 * time.Minute), LastError: "DNS problem"},
+		},
+	}
+
+	assert.Equal(t, expected, p.OrdersState())
+
+	// Without scheduling.
+	assert.Equal(t, OrdersState{Resolver: "le"}, (&Provider{Configuration: &Configuration{}, ResolverName: "le"}).OrdersState())
+}
+
+func TestGetRegisteredDomains(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		domains  []string
+		expected []string
+	}{
+		{
+			desc:     "subdomains",
+			domains:  []string{"www.example.com", "api.example.com", "example.com"},
+			expected: []string{"example.com"},
+		},
+		{
+			desc:     "public suffix",
+			domains:  []string{"www.example.co.uk", "www.example.com"},
+			expected: []string{"example.co.uk", "example.com"},
+		},
+		{
+			desc:     "wildcard",
+			domains:  []string{"*.example.com"},
+			expected: []string{"example.com"},
+		},
+		{
+			desc:     "no public suffix",
+			domains:  []string{"localhost"},
+			expected: []string{"localhost"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, getRegisteredDomains(test.domains))
+		})
+	}
+}
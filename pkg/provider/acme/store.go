@@ -1,9 +1,15 @@
 package acme
 
+import (
+	"context"
+	"time"
+)
+
 // StoredData represents the data managed by Store.
 type StoredData struct {
-	Account      *Account
-	Certificates []*CertAndStore
+	Account       *Account
+	Certificates  []*CertAndStore
+	OrderAttempts map[string][]time.Time `json:",omitempty"`
 }
 
 // Store is a generic interface that represents a storage.
@@ -12,4 +18,17 @@ type Store interface {
 	SaveAccount(string, *Account) error
 	GetCertificates(string) ([]*CertAndStore, error)
 	SaveCertificates(string, []*CertAndStore) error
+	GetOrderAttempts(string) (map[string][]time.Time, error)
+	SaveOrderAttempts(string, map[string][]time.Time) error
+}
+
+// SharedStore is a Store shared by several Traefik instances.
+type SharedStore interface {
+	Store
+
+	// Lock acquires the lock of the resolver, preventing the instances from requesting the same certificates concurrently,
+	// and returns the function releasing it.
+	Lock(ctx context.Context, resolverName string) (func(), error)
+	// WatchCertificates sends the certificates of the resolver each time they are saved, by any instance.
+	WatchCertificates(ctx context.Context, resolverName string) (<-chan []*CertAndStore, error)
 }
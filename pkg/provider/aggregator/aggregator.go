@@ -83,6 +83,18 @@ func NewProviderAggregator(conf static.Providers) ProviderAggregator {
 		p.quietAddProvider(conf.HTTP)
 	}
 
+	if conf.GRPC != nil {
+		p.quietAddProvider(conf.GRPC)
+	}
+
+	if conf.Kafka != nil {
+		p.quietAddProvider(conf.Kafka)
+	}
+
+	if conf.Eureka != nil {
+		p.quietAddProvider(conf.Eureka)
+	}
+
 	return p
 }
 
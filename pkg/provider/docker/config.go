@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/docker/go-connections/nat"
@@ -17,6 +19,7 @@ import (
 
 func (p *Provider) buildConfiguration(ctx context.Context, containersInspected []dockerData) *dynamic.Configuration {
 	configurations := make(map[string]*dynamic.Configuration)
+	provenance := &dynamic.Provenance{}
 
 	for _, container := range containersInspected {
 		containerName := getServiceName(container) + "-" + container.ID
@@ -61,6 +64,7 @@ func (p *Provider) buildConfiguration(ctx context.Context, containersInspected [
 			len(confFromLabel.HTTP.Middlewares) == 0 &&
 			len(confFromLabel.HTTP.Services) == 0 {
 			configurations[containerName] = confFromLabel
+			addProvenance(provenance, confFromLabel, container)
 			continue
 		}
 
@@ -83,9 +87,61 @@ func (p *Provider) buildConfiguration(ctx context.Context, containersInspected [
 		provider.BuildRouterConfiguration(ctx, confFromLabel.HTTP, serviceName, p.defaultRuleTpl, model)
 
 		configurations[containerName] = confFromLabel
+		addProvenance(provenance, confFromLabel, container)
 	}
 
-	return provider.Merge(ctx, configurations)
+	configuration := provider.Merge(ctx, configurations)
+	configuration.Provenance = provenance
+
+	return configuration
+}
+
+// addProvenance records the given container as the source of the objects of the given configuration.
+func addProvenance(provenance *dynamic.Provenance, conf *dynamic.Configuration, container dockerData) {
+	for name := range conf.HTTP.Routers {
+		provenance.AddRouter(name, containerSource(container, "traefik.http.routers."+name))
+	}
+	for name := range conf.HTTP.Middlewares {
+		provenance.AddMiddleware(name, containerSource(container, "traefik.http.middlewares."+name))
+	}
+	for name := range conf.HTTP.Services {
+		provenance.AddService(name, containerSource(container, "traefik.http.services."+name))
+	}
+	for name := range conf.TCP.Routers {
+		provenance.AddTCPRouter(name, containerSource(container, "traefik.tcp.routers."+name))
+	}
+	for name := range conf.TCP.Middlewares {
+		provenance.AddTCPMiddleware(name, containerSource(container, "traefik.tcp.middlewares."+name))
+	}
+	for name := range conf.TCP.Services {
+		provenance.AddTCPService(name, containerSource(container, "traefik.tcp.services."+name))
+	}
+	for name := range conf.UDP.Routers {
+		provenance.AddUDPRouter(name, containerSource(container, "traefik.udp.routers."+name))
+	}
+	for name := range conf.UDP.Services {
+		provenance.AddUDPService(name, containerSource(container, "traefik.udp.services."+name))
+	}
+}
+
+// containerSource returns the provenance source of an object built from the given container.
+// The label is set to the given prefix when the object is defined by labels,
+// and left empty when the object is built by default.
+func containerSource(container dockerData, labelPrefix string) dynamic.Source {
+	source := dynamic.Source{
+		Kind: "Container",
+		Name: container.Name,
+		UID:  container.ID,
+	}
+
+	for key := range container.Labels {
+		if strings.HasPrefix(key, labelPrefix+".") {
+			source.Label = labelPrefix
+			break
+		}
+	}
+
+	return source
 }
 
 func (p *Provider) buildTCPServiceConfiguration(ctx context.Context, container dockerData, configuration *dynamic.TCPConfiguration) error {
@@ -147,7 +203,7 @@ func (p *Provider) buildServiceConfiguration(ctx context.Context, container dock
 
 	for name, service := range configuration.Services {
 		ctxSvc := log.With(ctx, log.Str(log.ServiceName, name))
-		err := p.addServer(ctxSvc, container, service.LoadBalancer)
+		err := p.addServer(ctxSvc, container, name, service.LoadBalancer)
 		if err != nil {
 			return fmt.Errorf("service %q error: %w", name, err)
 		}
@@ -174,7 +230,7 @@ func (p *Provider) keepContainer(ctx context.Context, container dockerData) bool
 		return false
 	}
 
-	if container.Health != "" && container.Health != "healthy" {
+	if !container.ExtraConf.Docker.IgnoreHealthStatus && container.Health != "" && container.Health != "healthy" {
 		logger.Debug("Filtering unhealthy or starting container")
 		return false
 	}
@@ -242,7 +298,7 @@ func (p *Provider) addServerUDP(ctx context.Context, container dockerData, loadB
 	return nil
 }
 
-func (p *Provider) addServer(ctx context.Context, container dockerData, loadBalancer *dynamic.ServersLoadBalancer) error {
+func (p *Provider) addServer(ctx context.Context, container dockerData, serviceName string, loadBalancer *dynamic.ServersLoadBalancer) error {
 	if loadBalancer == nil {
 		return errors.New("load-balancer is not defined")
 	}
@@ -253,6 +309,13 @@ func (p *Provider) addServer(ctx context.Context, container dockerData, loadBala
 		loadBalancer.Servers[0].Port = ""
 	}
 
+	// The services exposed by the image are a fallback for the port and scheme which are not set by labels.
+	imageServices := getImageServices(container.Labels)
+	if serverPort == "" && len(imageServices) > 0 {
+		serverPort = imageServices[0].port
+		log.FromContext(ctx).Debugf("Using the port %s declared by the %s image label", serverPort, labelExposeServices)
+	}
+
 	ip, port, err := p.getIPPort(ctx, container, serverPort)
 	if err != nil {
 		return err
@@ -269,6 +332,15 @@ func (p *Provider) addServer(ctx context.Context, container dockerData, loadBala
 		return errors.New("port is missing")
 	}
 
+	if !hasServerSchemeLabel(container.Labels, serviceName) {
+		for _, service := range imageServices {
+			if service.port == port && service.scheme != "" {
+				loadBalancer.Servers[0].Scheme = service.scheme
+				break
+			}
+		}
+	}
+
 	loadBalancer.Servers[0].URL = fmt.Sprintf("%s://%s", loadBalancer.Servers[0].Scheme, net.JoinHostPort(ip, port))
 	loadBalancer.Servers[0].Scheme = ""
 
@@ -318,6 +390,14 @@ func (p Provider) getIPAddress(ctx context.Context, container dockerData) string
 				return network.Addr
 			}
 
+			// Docker Compose prefixes the names of the networks it creates with the name of the project.
+			if project := container.Labels[labelDockerComposeProject]; project != "" {
+				network = settings.Networks[project+"_"+container.ExtraConf.Docker.Network]
+				if network != nil {
+					return network.Addr
+				}
+			}
+
 			logger.Warnf("Could not find network named '%s' for container '%s'! Maybe you're missing the project's prefix in the label? Defaulting to first available network.", container.ExtraConf.Docker.Network, container.Name)
 		}
 	}
@@ -363,8 +443,19 @@ func (p Provider) getIPAddress(ctx context.Context, container dockerData) string
 		return p.getIPAddress(ctx, containerParsed)
 	}
 
-	for _, network := range container.NetworkSettings.Networks {
-		return network.Addr
+	// The networks are sorted by name, so that the same network is always picked.
+	var names []string
+	for name := range container.NetworkSettings.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if len(names) > 1 {
+		logger.Debugf("Container '%s' is attached to several networks, using the network named '%s'. Set the traefik.docker.network label to use another one.", container.Name, names[0])
+	}
+
+	if len(names) > 0 {
+		return container.NetworkSettings.Networks[names[0]].Addr
 	}
 
 	logger.Warn("Unable to find the IP address.")
@@ -407,6 +498,50 @@ func getPort(container dockerData, serverPort string) string {
 	return ""
 }
 
+// imageService is a service exposed by the image of a container.
+type imageService struct {
+	port   string
+	scheme string
+}
+
+// getImageServices returns the services declared by the image labels, in order.
+// The scheme of a service is only known when its name is a scheme.
+func getImageServices(labels map[string]string) []imageService {
+	var services []imageService
+	for _, value := range strings.Split(labels[labelExposeServices], ",") {
+		parts := strings.SplitN(strings.TrimSpace(value), ":", 2)
+
+		port := strings.SplitN(parts[0], "/", 2)[0]
+		if _, err := strconv.Atoi(port); err != nil {
+			continue
+		}
+
+		service := imageService{port: port}
+		if len(parts) == 2 {
+			switch name := strings.ToLower(strings.TrimSpace(parts[1])); name {
+			case "http", "https", "h2c":
+				service.scheme = name
+			}
+		}
+
+		services = append(services, service)
+	}
+
+	return services
+}
+
+// hasServerSchemeLabel reports whether the scheme of the servers of the service is set by a label.
+func hasServerSchemeLabel(labels map[string]string, serviceName string) bool {
+	key := "traefik.http.services." + serviceName + ".loadbalancer.server.scheme"
+	for name := range labels {
+		if strings.EqualFold(name, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func getServiceName(container dockerData) string {
 	serviceName := container.ServiceName
 
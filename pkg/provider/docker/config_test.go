@@ -368,6 +368,9 @@ func TestDefaultRule(t *testing.T) {
 
 			configuration := p.buildConfiguration(context.Background(), test.containers)
 
+			// The provenance is checked by Test_buildConfiguration_provenance.
+			configuration.Provenance = nil
+
 			assert.Equal(t, test.expected, configuration)
 		})
 	}
@@ -375,11 +378,12 @@ func TestDefaultRule(t *testing.T) {
 
 func Test_buildConfiguration(t *testing.T) {
 	testCases := []struct {
-		desc          string
-		containers    []dockerData
-		useBindPortIP bool
-		constraints   string
-		expected      *dynamic.Configuration
+		desc               string
+		containers         []dockerData
+		useBindPortIP      bool
+		ignoreHealthStatus bool
+		constraints        string
+		expected           *dynamic.Configuration
 	}{
 		{
 			desc: "invalid HTTP service definition",
@@ -501,6 +505,179 @@ func Test_buildConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "one container with the services declared by the image label",
+			containers: []dockerData{
+				{
+					ServiceName: "Test",
+					Name:        "Test",
+					Labels: map[string]string{
+						"io.openshift.expose-services": "8443/tcp:https,80/tcp:http",
+					},
+					NetworkSettings: networkSettings{
+						Ports: nat.PortMap{
+							nat.Port("80/tcp"):   []nat.PortBinding{},
+							nat.Port("8443/tcp"): []nat.PortBinding{},
+						},
+						Networks: map[string]*networkData{
+							"bridge": {
+								Name: "bridge",
+								Addr: "127.0.0.1",
+							},
+						},
+					},
+				},
+			},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:     map[string]*dynamic.TCPRouter{},
+					Middlewares: map[string]*dynamic.TCPMiddleware{},
+					Services:    map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"Test": {
+							Service: "Test",
+							Rule:    "Host(`Test.traefik.wtf`)",
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"Test": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{
+										URL: "https://127.0.0.1:8443",
+									},
+								},
+								PassHostHeader: Bool(true),
+							},
+						},
+					},
+					ServersTransports: map[string]*dynamic.ServersTransport{},
+				},
+			},
+		},
+		{
+			desc: "one container with the services declared by the image label and a scheme label",
+			containers: []dockerData{
+				{
+					ServiceName: "Test",
+					Name:        "Test",
+					Labels: map[string]string{
+						"io.openshift.expose-services":                              "8443:https",
+						"traefik.http.services.Service1.loadbalancer.server.scheme": "h2c",
+					},
+					NetworkSettings: networkSettings{
+						Ports: nat.PortMap{
+							nat.Port("80/tcp"):   []nat.PortBinding{},
+							nat.Port("8443/tcp"): []nat.PortBinding{},
+						},
+						Networks: map[string]*networkData{
+							"bridge": {
+								Name: "bridge",
+								Addr: "127.0.0.1",
+							},
+						},
+					},
+				},
+			},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:     map[string]*dynamic.TCPRouter{},
+					Middlewares: map[string]*dynamic.TCPMiddleware{},
+					Services:    map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"Test": {
+							Service: "Service1",
+							Rule:    "Host(`Test.traefik.wtf`)",
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"Service1": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{
+										URL: "h2c://127.0.0.1:8443",
+									},
+								},
+								PassHostHeader: Bool(true),
+							},
+						},
+					},
+					ServersTransports: map[string]*dynamic.ServersTransport{},
+				},
+			},
+		},
+		{
+			desc: "one container with the services declared by the image label and a port label",
+			containers: []dockerData{
+				{
+					ServiceName: "Test",
+					Name:        "Test",
+					Labels: map[string]string{
+						"io.openshift.expose-services":                            "8443:https",
+						"traefik.http.services.Service1.loadbalancer.server.port": "80",
+					},
+					NetworkSettings: networkSettings{
+						Ports: nat.PortMap{
+							nat.Port("80/tcp"):   []nat.PortBinding{},
+							nat.Port("8443/tcp"): []nat.PortBinding{},
+						},
+						Networks: map[string]*networkData{
+							"bridge": {
+								Name: "bridge",
+								Addr: "127.0.0.1",
+							},
+						},
+					},
+				},
+			},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:     map[string]*dynamic.TCPRouter{},
+					Middlewares: map[string]*dynamic.TCPMiddleware{},
+					Services:    map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"Test": {
+							Service: "Service1",
+							Rule:    "Host(`Test.traefik.wtf`)",
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"Service1": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{
+										URL: "http://127.0.0.1:80",
+									},
+								},
+								PassHostHeader: Bool(true),
+							},
+						},
+					},
+					ServersTransports: map[string]*dynamic.ServersTransport{},
+				},
+			},
+		},
 		{
 			desc: "one container no label",
 			containers: []dockerData{
@@ -2272,6 +2449,119 @@ func Test_buildConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "one container not healthy, health status ignored by the provider",
+			containers: []dockerData{
+				{
+					ServiceName: "Test",
+					Name:        "Test",
+					Labels:      map[string]string{},
+					NetworkSettings: networkSettings{
+						Ports: nat.PortMap{
+							nat.Port("80/tcp"): []nat.PortBinding{},
+						},
+						Networks: map[string]*networkData{
+							"bridge": {
+								Name: "bridge",
+								Addr: "127.0.0.1",
+							},
+						},
+					},
+					Health: "not_healthy",
+				},
+			},
+			ignoreHealthStatus: true,
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:     map[string]*dynamic.TCPRouter{},
+					Middlewares: map[string]*dynamic.TCPMiddleware{},
+					Services:    map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"Test": {
+							Service: "Test",
+							Rule:    "Host(`Test.traefik.wtf`)",
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"Test": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{
+										URL: "http://127.0.0.1:80",
+									},
+								},
+								PassHostHeader: Bool(true),
+							},
+						},
+					},
+					ServersTransports: map[string]*dynamic.ServersTransport{},
+				},
+			},
+		},
+		{
+			desc: "one container not healthy, health status ignored by label",
+			containers: []dockerData{
+				{
+					ServiceName: "Test",
+					Name:        "Test",
+					Labels: map[string]string{
+						"traefik.docker.ignorehealthstatus": "true",
+					},
+					NetworkSettings: networkSettings{
+						Ports: nat.PortMap{
+							nat.Port("80/tcp"): []nat.PortBinding{},
+						},
+						Networks: map[string]*networkData{
+							"bridge": {
+								Name: "bridge",
+								Addr: "127.0.0.1",
+							},
+						},
+					},
+					Health: "not_healthy",
+				},
+			},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:     map[string]*dynamic.TCPRouter{},
+					Middlewares: map[string]*dynamic.TCPMiddleware{},
+					Services:    map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"Test": {
+							Service: "Test",
+							Rule:    "Host(`Test.traefik.wtf`)",
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"Test": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{
+										URL: "http://127.0.0.1:80",
+									},
+								},
+								PassHostHeader: Bool(true),
+							},
+						},
+					},
+					ServersTransports: map[string]*dynamic.ServersTransport{},
+				},
+			},
+		},
 		{
 			desc: "one container with non matching constraints",
 			containers: []dockerData{
@@ -3058,9 +3348,10 @@ func Test_buildConfiguration(t *testing.T) {
 			t.Parallel()
 
 			p := Provider{
-				ExposedByDefault: true,
-				DefaultRule:      "Host(`{{ normalize .Name }}.traefik.wtf`)",
-				UseBindPortIP:    test.useBindPortIP,
+				ExposedByDefault:   true,
+				DefaultRule:        "Host(`{{ normalize .Name }}.traefik.wtf`)",
+				UseBindPortIP:      test.useBindPortIP,
+				IgnoreHealthStatus: test.ignoreHealthStatus,
 			}
 			p.Constraints = test.constraints
 
@@ -3075,11 +3366,95 @@ func Test_buildConfiguration(t *testing.T) {
 
 			configuration := p.buildConfiguration(context.Background(), test.containers)
 
+			// The provenance is checked by Test_buildConfiguration_provenance.
+			configuration.Provenance = nil
+
 			assert.Equal(t, test.expected, configuration)
 		})
 	}
 }
 
+func Test_buildConfiguration_provenance(t *testing.T) {
+	containers := []dockerData{
+		{
+			ID:          "1",
+			ServiceName: "Test",
+			Name:        "Test",
+			Labels: map[string]string{
+				"traefik.http.middlewares.Middleware1.inflightreq.amount": "42",
+			},
+			NetworkSettings: networkSettings{
+				Ports: nat.PortMap{
+					nat.Port("80/tcp"): []nat.PortBinding{},
+				},
+				Networks: map[string]*networkData{
+					"bridge": {
+						Name: "bridge",
+						Addr: "127.0.0.1",
+					},
+				},
+			},
+		},
+		{
+			ID:          "2",
+			ServiceName: "Test",
+			Name:        "Test",
+			Labels: map[string]string{
+				"traefik.http.middlewares.Middleware1.inflightreq.amount": "42",
+			},
+			NetworkSettings: networkSettings{
+				Ports: nat.PortMap{
+					nat.Port("80/tcp"): []nat.PortBinding{},
+				},
+				Networks: map[string]*networkData{
+					"bridge": {
+						Name: "bridge",
+						Addr: "127.0.0.2",
+					},
+				},
+			},
+		},
+	}
+
+	p := Provider{
+		ExposedByDefault: true,
+		DefaultRule:      "Host(`{{ normalize .Name }}.traefik.wtf`)",
+	}
+
+	err := p.Init()
+	require.NoError(t, err)
+
+	for i := 0; i < len(containers); i++ {
+		containers[i].ExtraConf, err = p.getConfiguration(containers[i])
+		require.NoError(t, err)
+	}
+
+	configuration := p.buildConfiguration(context.Background(), containers)
+
+	expected := &dynamic.Provenance{
+		Routers: map[string][]dynamic.Source{
+			"Test": {
+				{Kind: "Container", Name: "Test", UID: "1"},
+				{Kind: "Container", Name: "Test", UID: "2"},
+			},
+		},
+		Middlewares: map[string][]dynamic.Source{
+			"Middleware1": {
+				{Kind: "Container", Name: "Test", UID: "1", Label: "traefik.http.middlewares.Middleware1"},
+				{Kind: "Container", Name: "Test", UID: "2", Label: "traefik.http.middlewares.Middleware1"},
+			},
+		},
+		Services: map[string][]dynamic.Source{
+			"Test": {
+				{Kind: "Container", Name: "Test", UID: "1"},
+				{Kind: "Container", Name: "Test", UID: "2"},
+			},
+		},
+	}
+
+	assert.Equal(t, expected, configuration.Provenance)
+}
+
 func TestDockerGetIPPort(t *testing.T) {
 	type expected struct {
 		ip    string
@@ -3380,6 +3755,25 @@ func TestDockerGetIPAddress(t *testing.T) {
 			network:  "testnet",
 			expected: "10.11.12.13",
 		},
+		{
+			desc: "two networks, network label without the Docker Compose project prefix",
+			container: containerJSON(
+				labels(map[string]string{labelDockerComposeProject: "stack"}),
+				withNetwork("stack_testnet", ipv4("10.11.12.13")),
+				withNetwork("stack_webnet", ipv4("10.11.12.14")),
+			),
+			network:  "testnet",
+			expected: "10.11.12.13",
+		},
+		{
+			desc: "two networks, unknown network label",
+			container: containerJSON(
+				withNetwork("testnet2", ipv4("10.11.12.14")),
+				withNetwork("testnet", ipv4("10.11.12.13")),
+			),
+			network:  "unknown",
+			expected: "10.11.12.13",
+		},
 		{
 			desc: "no network, no network label, mode host",
 			container: containerJSON(
@@ -28,6 +28,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/job"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/provider"
+	"github.com/traefik/traefik/v2/pkg/provider/constraints"
 	"github.com/traefik/traefik/v2/pkg/safe"
 	"github.com/traefik/traefik/v2/pkg/types"
 	"github.com/traefik/traefik/v2/pkg/version"
@@ -59,6 +60,7 @@ type Provider struct {
 	Network                 string           `description:"Default Docker network used." json:"network,omitempty" toml:"network,omitempty" yaml:"network,omitempty" export:"true"`
 	SwarmModeRefreshSeconds ptypes.Duration  `description:"Polling interval for swarm mode." json:"swarmModeRefreshSeconds,omitempty" toml:"swarmModeRefreshSeconds,omitempty" yaml:"swarmModeRefreshSeconds,omitempty" export:"true"`
 	HTTPClientTimeout       ptypes.Duration  `description:"Client timeout for HTTP connections." json:"httpClientTimeout,omitempty" toml:"httpClientTimeout,omitempty" yaml:"httpClientTimeout,omitempty" export:"true"`
+	IgnoreHealthStatus      bool             `description:"Include the containers in the load balancers whatever their health status." json:"ignoreHealthStatus,omitempty" toml:"ignoreHealthStatus,omitempty" yaml:"ignoreHealthStatus,omitempty" export:"true"`
 	defaultRuleTpl          *template.Template
 }
 
@@ -79,6 +81,19 @@ func (p *Provider) Init() error {
 		return fmt.Errorf("error while parsing default rule: %w", err)
 	}
 
+	// The expression is checked once, since an invalid expression would prune all the containers.
+	if _, err := constraints.MatchLabels(nil, p.Constraints); err != nil {
+		return fmt.Errorf("error while parsing constraints: %w", err)
+	}
+
+	// The TLS configuration is checked once, since an invalid configuration would only fail, and be retried, when connecting.
+	if p.TLS != nil {
+		ctx := log.With(context.Background(), log.Str(log.ProviderName, "docker"))
+		if _, err := p.TLS.CreateTLSConfig(ctx); err != nil {
+			return fmt.Errorf("error while creating TLS configuration: %w", err)
+		}
+	}
+
 	p.defaultRuleTpl = defaultRuleTpl
 	return nil
 }
@@ -274,7 +289,9 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 					// channel closed
 				} else {
 					f := filters.NewArgs()
-					f.Add("type", "container")
+					f.Add("type", eventtypes.ContainerEventType)
+					// Containers connected to or disconnected from a network may have to be reached through another IP.
+					f.Add("type", eventtypes.NetworkEventType)
 					options := dockertypes.EventsOptions{
 						Filters: f,
 					}
@@ -305,9 +322,7 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 					for {
 						select {
 						case event := <-eventsc:
-							if event.Action == "start" ||
-								event.Action == "die" ||
-								strings.HasPrefix(event.Action, "health_status") {
+							if shouldRefresh(event) {
 								startStopHandle(event)
 							}
 						case err := <-errc:
@@ -336,6 +351,17 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 	return nil
 }
 
+// shouldRefresh reports whether the given Docker event can change the configuration.
+func shouldRefresh(event eventtypes.Message) bool {
+	if event.Type == eventtypes.NetworkEventType {
+		return event.Action == "connect" || event.Action == "disconnect"
+	}
+
+	return event.Action == "start" ||
+		event.Action == "die" ||
+		strings.HasPrefix(event.Action, "health_status")
+}
+
 func (p *Provider) listContainers(ctx context.Context, dockerClient client.ContainerAPIClient) ([]dockerData, error) {
 	containerList, err := dockerClient.ContainerList(ctx, dockertypes.ContainerListOptions{})
 	if err != nil {
@@ -477,7 +503,9 @@ func (p *Provider) listServices(ctx context.Context, dockerClient client.APIClie
 			}
 		}
 	}
-	return dockerDataList, err
+
+	// The errors of the services have already been logged, and these services skipped.
+	return dockerDataList, nil
 }
 
 func (p *Provider) parseService(ctx context.Context, service swarmtypes.Service, networkMap map[string]*dockertypes.NetworkResource) (dockerData, error) {
@@ -501,6 +529,8 @@ func (p *Provider) parseService(ctx context.Context, service swarmtypes.Service,
 		if service.Spec.EndpointSpec.Mode == swarmtypes.ResolutionModeDNSRR {
 			if dData.ExtraConf.Docker.LBSwarm {
 				logger.Warnf("Ignored %s endpoint-mode not supported, service name: %s. Fallback to Traefik load balancing", swarmtypes.ResolutionModeDNSRR, service.Spec.Annotations.Name)
+				// There is no virtual IP to load-balance on, so the tasks are used instead.
+				dData.ExtraConf.Docker.LBSwarm = false
 			}
 		} else if service.Spec.EndpointSpec.Mode == swarmtypes.ResolutionModeVIP {
 			dData.NetworkSettings.Networks = make(map[string]*networkData)
@@ -0,0 +1,148 @@
+package docker
+
+import (
+	"testing"
+
+	eventtypes "github.com/docker/docker/api/types/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestShouldRefresh(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		event    eventtypes.Message
+		expected bool
+	}{
+		{
+			desc:     "container start",
+			event:    eventtypes.Message{Type: eventtypes.ContainerEventType, Action: "start"},
+			expected: true,
+		},
+		{
+			desc:     "container die",
+			event:    eventtypes.Message{Type: eventtypes.ContainerEventType, Action: "die"},
+			expected: true,
+		},
+		{
+			desc:     "container health status",
+			event:    eventtypes.Message{Type: eventtypes.ContainerEventType, Action: "health_status: healthy"},
+			expected: true,
+		},
+		{
+			desc:  "container exec",
+			event: eventtypes.Message{Type: eventtypes.ContainerEventType, Action: "exec_start: sh"},
+		},
+		{
+			desc:     "network connect",
+			event:    eventtypes.Message{Type: eventtypes.NetworkEventType, Action: "connect"},
+			expected: true,
+		},
+		{
+			desc:     "network disconnect",
+			event:    eventtypes.Message{Type: eventtypes.NetworkEventType, Action: "disconnect"},
+			expected: true,
+		},
+		{
+			desc:  "network create",
+			event: eventtypes.Message{Type: eventtypes.NetworkEventType, Action: "create"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, shouldRefresh(test.event))
+		})
+	}
+}
+
+func TestProvider_Init_constraints(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		constraints string
+		expectedErr bool
+	}{
+		{
+			desc: "no constraints",
+		},
+		{
+			desc:        "valid constraints",
+			constraints: "Label(`a.label.name`, `foo`) || LabelRegex(`another.label.name`, `b.+`)",
+		},
+		{
+			desc:        "unknown function",
+			constraints: "Tag(`foo`)",
+			expectedErr: true,
+		},
+		{
+			desc:        "missing argument",
+			constraints: "Label(`a.label.name`)",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := Provider{Constraints: test.constraints}
+			p.SetDefaults()
+
+			err := p.Init()
+			if test.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestProvider_Init_tls(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		tls         *types.ClientTLS
+		expectedErr bool
+	}{
+		{
+			desc: "no TLS",
+		},
+		{
+			desc: "insecure skip verify",
+			tls:  &types.ClientTLS{InsecureSkipVerify: true},
+		},
+		{
+			desc:        "missing certificate",
+			tls:         &types.ClientTLS{Key: "foo"},
+			expectedErr: true,
+		},
+		{
+			desc:        "invalid CA",
+			tls:         &types.ClientTLS{CA: "foo", InsecureSkipVerify: true},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := Provider{}
+			p.SetDefaults()
+			p.Endpoint = "tcp://127.0.0.1:2376"
+			p.TLS = test.tls
+
+			err := p.Init()
+			if test.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
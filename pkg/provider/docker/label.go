@@ -9,6 +9,10 @@ import (
 const (
 	labelDockerComposeProject = "com.docker.compose.project"
 	labelDockerComposeService = "com.docker.compose.service"
+
+	// labelExposeServices is the image label declaring the services exposed by the image, as a list of port:name,
+	// e.g. "8080/tcp:http,8443/tcp:https".
+	labelExposeServices = "io.openshift.expose-services"
 )
 
 // configuration Contains information from the labels that are globals (not related to the dynamic configuration)
@@ -19,15 +23,17 @@ type configuration struct {
 }
 
 type specificConfiguration struct {
-	Network string
-	LBSwarm bool
+	Network            string
+	LBSwarm            bool
+	IgnoreHealthStatus bool
 }
 
 func (p *Provider) getConfiguration(container dockerData) (configuration, error) {
 	conf := configuration{
 		Enable: p.ExposedByDefault,
 		Docker: specificConfiguration{
-			Network: p.Network,
+			Network:            p.Network,
+			IgnoreHealthStatus: p.IgnoreHealthStatus,
 		},
 	}
 
@@ -268,6 +268,36 @@ func TestListServices(t *testing.T) {
 				"service2.0",
 			},
 		},
+		{
+			desc: "Should fall back to the tasks of a DNSRR service with LBSwarm",
+			services: []swarm.Service{
+				swarmService(
+					serviceName("service1"),
+					serviceLabels(map[string]string{
+						"traefik.docker.network": "barnet",
+						"traefik.docker.LBSwarm": "true",
+					}),
+					withEndpointSpec(modeDNSSR)),
+			},
+			tasks: []swarm.Task{
+				swarmTask("id1",
+					taskNetworkAttachment("yk6l57rfwizjzxxzftn4amaot", "network_name", "overlay", []string{"127.0.0.1"}),
+					taskStatus(taskState(swarm.TaskStateRunning)),
+				),
+			},
+			dockerVersion: "1.30",
+			networks: []dockertypes.NetworkResource{
+				{
+					Name:   "network_name",
+					ID:     "yk6l57rfwizjzxxzftn4amaot",
+					Scope:  "swarm",
+					Driver: "overlay",
+				},
+			},
+			expectedServices: []string{
+				"service1.0",
+			},
+		},
 	}
 
 	for _, test := range testCases {
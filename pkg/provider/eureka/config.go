@@ -0,0 +1,202 @@
+package eureka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/label"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider"
+	"github.com/traefik/traefik/v2/pkg/provider/constraints"
+)
+
+// eurekaData is an application, with its instances ready to receive traffic.
+type eurekaData struct {
+	Name string
+	// Labels are the metadata of the first instance, in the order of the instance IDs.
+	Labels    map[string]string
+	Instances []instance
+	ExtraConf configuration
+}
+
+func (p *Provider) buildConfiguration(ctx context.Context, apps []application) *dynamic.Configuration {
+	configurations := make(map[string]*dynamic.Configuration)
+
+	for _, app := range apps {
+		data := newEurekaData(app)
+
+		ctxApp := log.With(ctx, log.Str("application", data.Name))
+		logger := log.FromContext(ctxApp)
+
+		if len(data.Instances) == 0 {
+			logger.Debug("Filtering application without instance up.")
+			continue
+		}
+
+		extraConf, err := p.getConfiguration(data.Labels)
+		if err != nil {
+			logger.Errorf("Skip application: %v", err)
+			continue
+		}
+		data.ExtraConf = extraConf
+
+		if !p.keepApplication(ctxApp, data) {
+			continue
+		}
+
+		confFromLabel, err := label.DecodeConfiguration(data.Labels)
+		if err != nil {
+			logger.Error(err)
+			continue
+		}
+
+		if len(confFromLabel.TCP.Routers) > 0 || len(confFromLabel.TCP.Services) > 0 ||
+			len(confFromLabel.UDP.Routers) > 0 || len(confFromLabel.UDP.Services) > 0 {
+			logger.Warn("TCP and UDP configurations are not supported by the Eureka provider, and are ignored.")
+			confFromLabel.TCP = &dynamic.TCPConfiguration{}
+			confFromLabel.UDP = &dynamic.UDPConfiguration{}
+		}
+
+		err = p.buildServiceConfiguration(ctxApp, data, confFromLabel.HTTP)
+		if err != nil {
+			logger.Error(err)
+			continue
+		}
+
+		model := struct {
+			Name   string
+			Labels map[string]string
+		}{
+			Name:   data.Name,
+			Labels: data.Labels,
+		}
+
+		provider.BuildRouterConfiguration(ctxApp, confFromLabel.HTTP, data.Name, p.defaultRuleTpl, model)
+
+		configurations[data.Name] = confFromLabel
+	}
+
+	return provider.Merge(ctx, configurations)
+}
+
+// newEurekaData returns the data of the given application, keeping only its instances which are up.
+func newEurekaData(app application) eurekaData {
+	data := eurekaData{
+		Name: provider.Normalize(strings.ToLower(app.Name)),
+	}
+
+	for _, inst := range app.Instances {
+		if inst.Status == statusUp {
+			data.Instances = append(data.Instances, inst)
+		}
+	}
+
+	sort.Slice(data.Instances, func(i, j int) bool {
+		return data.Instances[i].InstanceID < data.Instances[j].InstanceID
+	})
+
+	if len(data.Instances) > 0 {
+		data.Labels = data.Instances[0].Metadata
+	}
+
+	return data
+}
+
+func (p *Provider) keepApplication(ctx context.Context, data eurekaData) bool {
+	logger := log.FromContext(ctx)
+
+	if !data.ExtraConf.Enable {
+		logger.Debug("Filtering disabled application.")
+		return false
+	}
+
+	matches, err := constraints.MatchLabels(data.Labels, p.Constraints)
+	if err != nil {
+		logger.Errorf("Error matching constraints expression: %v", err)
+		return false
+	}
+	if !matches {
+		logger.Debugf("Application pruned by constraint expression: %q", p.Constraints)
+		return false
+	}
+
+	return true
+}
+
+func (p *Provider) buildServiceConfiguration(ctx context.Context, data eurekaData, configuration *dynamic.HTTPConfiguration) error {
+	if len(configuration.Services) == 0 {
+		configuration.Services = make(map[string]*dynamic.Service)
+		lb := &dynamic.ServersLoadBalancer{}
+		lb.SetDefaults()
+		configuration.Services[data.Name] = &dynamic.Service{
+			LoadBalancer: lb,
+		}
+	}
+
+	for _, confService := range configuration.Services {
+		err := p.addServers(ctx, data, confService.LoadBalancer)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Provider) addServers(ctx context.Context, data eurekaData, loadBalancer *dynamic.ServersLoadBalancer) error {
+	logger := log.FromContext(ctx)
+	logger.Debugf("Trying to add servers for application %s", data.Name)
+
+	if loadBalancer == nil {
+		return errors.New("load-balancer is not defined")
+	}
+
+	if len(loadBalancer.Servers) == 0 {
+		server := dynamic.Server{}
+		server.SetDefaults()
+
+		loadBalancer.Servers = []dynamic.Server{server}
+	}
+
+	serverPort := loadBalancer.Servers[0].Port
+	scheme := loadBalancer.Servers[0].Scheme
+
+	var servers []dynamic.Server
+	for _, inst := range data.Instances {
+		host := inst.IPAddr
+		if host == "" {
+			host = inst.HostName
+		}
+
+		instanceScheme := scheme
+		instancePort := serverPort
+		if instancePort == "" {
+			switch {
+			case inst.Port.enabled():
+				instancePort = inst.Port.Number.String()
+			case inst.SecurePort.enabled():
+				instancePort = inst.SecurePort.Number.String()
+				instanceScheme = "https"
+			default:
+				logger.Debugf("Filtering instance %s without enabled port", inst.InstanceID)
+				continue
+			}
+		}
+
+		servers = append(servers, dynamic.Server{
+			URL: fmt.Sprintf("%s://%s", instanceScheme, net.JoinHostPort(host, instancePort)),
+		})
+	}
+
+	if len(servers) == 0 {
+		return errors.New("no instance with an enabled port")
+	}
+
+	loadBalancer.Servers = servers
+	return nil
+}
@@ -0,0 +1,324 @@
+package eureka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func Bool(v bool) *bool { return &v }
+
+func emptyConfiguration(http *dynamic.HTTPConfiguration) *dynamic.Configuration {
+	return &dynamic.Configuration{
+		TCP: &dynamic.TCPConfiguration{
+			Routers:     map[string]*dynamic.TCPRouter{},
+			Middlewares: map[string]*dynamic.TCPMiddleware{},
+			Services:    map[string]*dynamic.TCPService{},
+		},
+		UDP: &dynamic.UDPConfiguration{
+			Routers:  map[string]*dynamic.UDPRouter{},
+			Services: map[string]*dynamic.UDPService{},
+		},
+		HTTP: http,
+	}
+}
+
+func Test_buildConfiguration(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		applications     []application
+		constraints      string
+		exposedByDefault bool
+		expected         *dynamic.Configuration
+	}{
+		{
+			desc: "one application with two instances up",
+			applications: []application{
+				{
+					Name: "MY-APP",
+					Instances: instances{
+						{InstanceID: "b", IPAddr: "127.0.0.2", Status: "UP", Port: port{Number: "8080", Enabled: "true"}},
+						{InstanceID: "a", IPAddr: "127.0.0.1", Status: "UP", Port: port{Number: "8080", Enabled: true}},
+					},
+				},
+			},
+			exposedByDefault: true,
+			expected: emptyConfiguration(&dynamic.HTTPConfiguration{
+				Routers: map[string]*dynamic.Router{
+					"my-app": {
+						Service: "my-app",
+						Rule:    "Host(`my-app.traefik.wtf`)",
+					},
+				},
+				Middlewares: map[string]*dynamic.Middleware{},
+				Services: map[string]*dynamic.Service{
+					"my-app": {
+						LoadBalancer: &dynamic.ServersLoadBalancer{
+							Servers: []dynamic.Server{
+								{URL: "http://127.0.0.1:8080"},
+								{URL: "http://127.0.0.2:8080"},
+							},
+							PassHostHeader: Bool(true),
+						},
+					},
+				},
+				ServersTransports: map[string]*dynamic.ServersTransport{},
+			}),
+		},
+		{
+			desc: "instances not up are ignored",
+			applications: []application{
+				{
+					Name: "MY-APP",
+					Instances: instances{
+						{InstanceID: "a", IPAddr: "127.0.0.1", Status: "UP", Port: port{Number: "8080", Enabled: "true"}},
+						{InstanceID: "b", IPAddr: "127.0.0.2", Status: "DOWN", Port: port{Number: "8080", Enabled: "true"}},
+						{InstanceID: "c", IPAddr: "127.0.0.3", Status: "OUT_OF_SERVICE", Port: port{Number: "8080", Enabled: "true"}},
+					},
+				},
+				{
+					Name: "OTHER",
+					Instances: instances{
+						{InstanceID: "d", IPAddr: "127.0.0.4", Status: "STARTING", Port: port{Number: "8080", Enabled: "true"}},
+					},
+				},
+			},
+			exposedByDefault: true,
+			expected: emptyConfiguration(&dynamic.HTTPConfiguration{
+				Routers: map[string]*dynamic.Router{
+					"my-app": {
+						Service: "my-app",
+						Rule:    "Host(`my-app.traefik.wtf`)",
+					},
+				},
+				Middlewares: map[string]*dynamic.Middleware{},
+				Services: map[string]*dynamic.Service{
+					"my-app": {
+						LoadBalancer: &dynamic.ServersLoadBalancer{
+							Servers: []dynamic.Server{
+								{URL: "http://127.0.0.1:8080"},
+							},
+							PassHostHeader: Bool(true),
+						},
+					},
+				},
+				ServersTransports: map[string]*dynamic.ServersTransport{},
+			}),
+		},
+		{
+			desc: "secure port and host name",
+			applications: []application{
+				{
+					Name: "MY-APP",
+					Instances: instances{
+						{InstanceID: "a", HostName: "my-app.local", Status: "UP", Port: port{Number: "8080", Enabled: "false"}, SecurePort: port{Number: "8443", Enabled: "true"}},
+						{InstanceID: "b", IPAddr: "127.0.0.2", Status: "UP", Port: port{Number: "8080", Enabled: "false"}},
+					},
+				},
+			},
+			exposedByDefault: true,
+			expected: emptyConfiguration(&dynamic.HTTPConfiguration{
+				Routers: map[string]*dynamic.Router{
+					"my-app": {
+						Service: "my-app",
+						Rule:    "Host(`my-app.traefik.wtf`)",
+					},
+				},
+				Middlewares: map[string]*dynamic.Middleware{},
+				Services: map[string]*dynamic.Service{
+					"my-app": {
+						LoadBalancer: &dynamic.ServersLoadBalancer{
+							Servers: []dynamic.Server{
+								{URL: "https://my-app.local:8443"},
+							},
+							PassHostHeader: Bool(true),
+						},
+					},
+				},
+				ServersTransports: map[string]*dynamic.ServersTransport{},
+			}),
+		},
+		{
+			desc: "labels from the metadata",
+			applications: []application{
+				{
+					Name: "MY-APP",
+					Instances: instances{
+						{
+							InstanceID: "a",
+							IPAddr:     "127.0.0.1",
+							Status:     "UP",
+							Port:       port{Number: "8080", Enabled: "true"},
+							Metadata: map[string]string{
+								"traefik.http.routers.router1.rule":                       "Host(`foo.bar`)",
+								"traefik.http.services.service1.loadbalancer.server.port": "9090",
+							},
+						},
+					},
+				},
+			},
+			exposedByDefault: true,
+			expected: emptyConfiguration(&dynamic.HTTPConfiguration{
+				Routers: map[string]*dynamic.Router{
+					"router1": {
+						Service: "service1",
+						Rule:    "Host(`foo.bar`)",
+					},
+				},
+				Middlewares: map[string]*dynamic.Middleware{},
+				Services: map[string]*dynamic.Service{
+					"service1": {
+						LoadBalancer: &dynamic.ServersLoadBalancer{
+							Servers: []dynamic.Server{
+								{URL: "http://127.0.0.1:9090"},
+							},
+							PassHostHeader: Bool(true),
+						},
+					},
+				},
+				ServersTransports: map[string]*dynamic.ServersTransport{},
+			}),
+		},
+		{
+			desc: "TCP configuration is ignored",
+			applications: []application{
+				{
+					Name: "MY-APP",
+					Instances: instances{
+						{
+							InstanceID: "a",
+							IPAddr:     "127.0.0.1",
+							Status:     "UP",
+							Port:       port{Number: "8080", Enabled: "true"},
+							Metadata: map[string]string{
+								"traefik.tcp.routers.foo.rule": "HostSNI(`foo.bar`)",
+							},
+						},
+					},
+				},
+			},
+			exposedByDefault: true,
+			expected: emptyConfiguration(&dynamic.HTTPConfiguration{
+				Routers: map[string]*dynamic.Router{
+					"my-app": {
+						Service: "my-app",
+						Rule:    "Host(`my-app.traefik.wtf`)",
+					},
+				},
+				Middlewares: map[string]*dynamic.Middleware{},
+				Services: map[string]*dynamic.Service{
+					"my-app": {
+						LoadBalancer: &dynamic.ServersLoadBalancer{
+							Servers: []dynamic.Server{
+								{URL: "http://127.0.0.1:8080"},
+							},
+							PassHostHeader: Bool(true),
+						},
+					},
+				},
+				ServersTransports: map[string]*dynamic.ServersTransport{},
+			}),
+		},
+		{
+			desc: "application not exposed by default",
+			applications: []application{
+				{
+					Name: "MY-APP",
+					Instances: instances{
+						{InstanceID: "a", IPAddr: "127.0.0.1", Status: "UP", Port: port{Number: "8080", Enabled: "true"}},
+					},
+				},
+				{
+					Name: "ENABLED",
+					Instances: instances{
+						{InstanceID: "b", IPAddr: "127.0.0.2", Status: "UP", Port: port{Number: "8080", Enabled: "true"}, Metadata: map[string]string{"traefik.enable": "true"}},
+					},
+				},
+			},
+			expected: emptyConfiguration(&dynamic.HTTPConfiguration{
+				Routers: map[string]*dynamic.Router{
+					"enabled": {
+						Service: "enabled",
+						Rule:    "Host(`enabled.traefik.wtf`)",
+					},
+				},
+				Middlewares: map[string]*dynamic.Middleware{},
+				Services: map[string]*dynamic.Service{
+					"enabled": {
+						LoadBalancer: &dynamic.ServersLoadBalancer{
+							Servers: []dynamic.Server{
+								{URL: "http://127.0.0.2:8080"},
+							},
+							PassHostHeader: Bool(true),
+						},
+					},
+				},
+				ServersTransports: map[string]*dynamic.ServersTransport{},
+			}),
+		},
+		{
+			desc: "application pruned by constraints",
+			applications: []application{
+				{
+					Name: "MY-APP",
+					Instances: instances{
+						{InstanceID: "a", IPAddr: "127.0.0.1", Status: "UP", Port: port{Number: "8080", Enabled: "true"}, Metadata: map[string]string{"tier": "backend"}},
+					},
+				},
+			},
+			constraints:      "Label(`tier`, `frontend`)",
+			exposedByDefault: true,
+			expected: emptyConfiguration(&dynamic.HTTPConfiguration{
+				Routers:           map[string]*dynamic.Router{},
+				Middlewares:       map[string]*dynamic.Middleware{},
+				Services:          map[string]*dynamic.Service{},
+				ServersTransports: map[string]*dynamic.ServersTransport{},
+			}),
+		},
+		{
+			desc: "application without enabled port",
+			applications: []application{
+				{
+					Name: "MY-APP",
+					Instances: instances{
+						{InstanceID: "a", IPAddr: "127.0.0.1", Status: "UP", Port: port{Number: "8080", Enabled: "false"}},
+					},
+				},
+			},
+			exposedByDefault: true,
+			expected: emptyConfiguration(&dynamic.HTTPConfiguration{
+				Routers:           map[string]*dynamic.Router{},
+				Middlewares:       map[string]*dynamic.Middleware{},
+				Services:          map[string]*dynamic.Service{},
+				ServersTransports: map[string]*dynamic.ServersTransport{},
+			}),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := Provider{
+				Endpoint:         "http://127.0.0.1:8761/eureka",
+				RefreshInterval:  ptypes.Duration(time.Second),
+				Constraints:      test.constraints,
+				ExposedByDefault: test.exposedByDefault,
+				DefaultRule:      "Host(`{{ normalize .Name }}.traefik.wtf`)",
+			}
+
+			err := p.Init()
+			require.NoError(t, err)
+
+			configuration := p.buildConfiguration(context.Background(), test.applications)
+
+			assert.Equal(t, test.expected, configuration)
+		})
+	}
+}
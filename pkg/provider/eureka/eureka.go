@@ -0,0 +1,273 @@
+package eureka
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/job"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+const (
+	// DefaultTemplateRule The default template for the default rule.
+	DefaultTemplateRule = "Host(`{{ normalize .Name }}`)"
+)
+
+const providerName = "eureka"
+
+// statusUp is the status of the instances ready to receive traffic.
+const statusUp = "UP"
+
+var _ provider.Provider = (*Provider)(nil)
+
+// Provider holds configurations of the provider.
+type Provider struct {
+	Endpoint         string           `description:"Eureka server endpoint, e.g. http://eureka:8761/eureka." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	Username         string           `description:"Basic Auth username." json:"username,omitempty" toml:"username,omitempty" yaml:"username,omitempty"`
+	Password         string           `description:"Basic Auth password." json:"password,omitempty" toml:"password,omitempty" yaml:"password,omitempty"`
+	TLS              *types.ClientTLS `description:"Enable TLS support." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	RefreshInterval  ptypes.Duration  `description:"Interval between two polls of the Eureka registry." json:"refreshInterval,omitempty" toml:"refreshInterval,omitempty" yaml:"refreshInterval,omitempty" export:"true"`
+	Timeout          ptypes.Duration  `description:"Timeout of the requests to the Eureka server." json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+	Constraints      string           `description:"Constraints is an expression that Traefik matches against the instance's metadata to determine whether to create any route for that application." json:"constraints,omitempty" toml:"constraints,omitempty" yaml:"constraints,omitempty" export:"true"`
+	ExposedByDefault bool             `description:"Expose applications by default." json:"exposedByDefault,omitempty" toml:"exposedByDefault,omitempty" yaml:"exposedByDefault,omitempty" export:"true"`
+	DefaultRule      string           `description:"Default rule." json:"defaultRule,omitempty" toml:"defaultRule,omitempty" yaml:"defaultRule,omitempty"`
+
+	httpClient     *http.Client
+	defaultRuleTpl *template.Template
+}
+
+// SetDefaults sets the default values.
+func (p *Provider) SetDefaults() {
+	p.Endpoint = "http://127.0.0.1:8761/eureka"
+	p.RefreshInterval = ptypes.Duration(30 * time.Second)
+	p.Timeout = ptypes.Duration(5 * time.Second)
+	p.ExposedByDefault = true
+	p.DefaultRule = DefaultTemplateRule
+}
+
+// Init the provider.
+func (p *Provider) Init() error {
+	if p.Endpoint == "" {
+		return errors.New("non-empty endpoint is required")
+	}
+
+	if p.RefreshInterval <= 0 {
+		return errors.New("refresh interval must be greater than 0")
+	}
+
+	defaultRuleTpl, err := provider.MakeDefaultRuleTemplate(p.DefaultRule, nil)
+	if err != nil {
+		return fmt.Errorf("error while parsing default rule: %w", err)
+	}
+
+	p.defaultRuleTpl = defaultRuleTpl
+
+	p.httpClient = &http.Client{
+		Timeout: time.Duration(p.Timeout),
+	}
+
+	if p.TLS != nil {
+		tlsConfig, err := p.TLS.CreateTLSConfig(context.Background())
+		if err != nil {
+			return fmt.Errorf("unable to create TLS configuration: %w", err)
+		}
+
+		p.httpClient.Transport = &http.Transport{
+			TLSClientConfig: tlsConfig,
+		}
+	}
+
+	return nil
+}
+
+// Provide allows the eureka provider to provide configurations to traefik using the given configuration channel.
+func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
+	pool.GoCtx(func(routineCtx context.Context) {
+		ctxLog := log.With(routineCtx, log.Str(log.ProviderName, providerName))
+		logger := log.FromContext(ctxLog)
+
+		operation := func() error {
+			var previous *dynamic.Configuration
+
+			updateConfiguration := func() error {
+				applications, err := p.fetchApplications(ctxLog)
+				if err != nil {
+					return fmt.Errorf("cannot fetch the applications: %w", err)
+				}
+
+				configuration := p.buildConfiguration(ctxLog, applications)
+				if reflect.DeepEqual(previous, configuration) {
+					return nil
+				}
+
+				previous = configuration
+
+				configurationChan <- dynamic.Message{
+					ProviderName:  providerName,
+					Configuration: configuration.DeepCopy(),
+				}
+
+				return nil
+			}
+
+			if err := updateConfiguration(); err != nil {
+				return err
+			}
+
+			ticker := time.NewTicker(time.Duration(p.RefreshInterval))
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ticker.C:
+					if err := updateConfiguration(); err != nil {
+						return err
+					}
+
+				case <-routineCtx.Done():
+					return nil
+				}
+			}
+		}
+
+		notify := func(err error, time time.Duration) {
+			logger.Errorf("Provider connection error %+v, retrying in %s", err, time)
+		}
+		err := backoff.RetryNotify(safe.OperationWithRecover(operation), backoff.WithContext(job.NewBackOff(backoff.NewExponentialBackOff()), ctxLog), notify)
+		if err != nil {
+			logger.Errorf("Cannot connect to Eureka server %+v", err)
+		}
+	})
+
+	return nil
+}
+
+// fetchApplications fetches the applications registered on the Eureka server.
+func (p *Provider) fetchApplications(ctx context.Context) ([]application, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(p.Endpoint, "/")+"/apps", http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if p.Username != "" || p.Password != "" {
+		req.SetBasicAuth(p.Username, p.Password)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("received non-ok response code: %d", resp.StatusCode)
+	}
+
+	var registry struct {
+		Applications struct {
+			Application applications `json:"application"`
+		} `json:"applications"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&registry); err != nil {
+		return nil, fmt.Errorf("unable to decode the applications: %w", err)
+	}
+
+	return registry.Applications.Application, nil
+}
+
+// application is an application registered on the Eureka server.
+type application struct {
+	Name      string    `json:"name"`
+	Instances instances `json:"instance"`
+}
+
+// instance is an instance of an application registered on the Eureka server.
+type instance struct {
+	InstanceID string            `json:"instanceId"`
+	HostName   string            `json:"hostName"`
+	IPAddr     string            `json:"ipAddr"`
+	Status     string            `json:"status"`
+	Port       port              `json:"port"`
+	SecurePort port              `json:"securePort"`
+	Metadata   map[string]string `json:"metadata"`
+}
+
+// port is a port of an instance.
+type port struct {
+	Number json.Number `json:"$"`
+	// Enabled is either the "true" or "false" string, or a boolean, depending on the version of the Eureka server.
+	Enabled interface{} `json:"@enabled"`
+}
+
+func (p port) enabled() bool {
+	return fmt.Sprint(p.Enabled) == "true"
+}
+
+// applications is a list of applications,
+// which the Eureka server encodes as a single object when the list holds a single element.
+type applications []application
+
+func (a *applications) UnmarshalJSON(data []byte) error {
+	if isObject(data) {
+		var app application
+		if err := json.Unmarshal(data, &app); err != nil {
+			return err
+		}
+
+		*a = applications{app}
+		return nil
+	}
+
+	var apps []application
+	if err := json.Unmarshal(data, &apps); err != nil {
+		return err
+	}
+
+	*a = apps
+	return nil
+}
+
+// instances is a list of instances,
+// which the Eureka server encodes as a single object when the list holds a single element.
+type instances []instance
+
+func (i *instances) UnmarshalJSON(data []byte) error {
+	if isObject(data) {
+		var inst instance
+		if err := json.Unmarshal(data, &inst); err != nil {
+			return err
+		}
+
+		*i = instances{inst}
+		return nil
+	}
+
+	var insts []instance
+	if err := json.Unmarshal(data, &insts); err != nil {
+		return err
+	}
+
+	*i = insts
+	return nil
+}
+
+func isObject(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
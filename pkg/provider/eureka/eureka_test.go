@@ -0,0 +1,156 @@
+package eureka
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+)
+
+func TestProvider_fetchApplications(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		username      string
+		password      string
+		body          string
+		statusCode    int
+		expected      []application
+		expectedError bool
+	}{
+		{
+			desc: "applications and instances as arrays",
+			body: `{"applications":{"application":[
+				{"name":"APP1","instance":[
+					{"instanceId":"a","hostName":"app1-a","ipAddr":"10.0.0.1","status":"UP","port":{"$":8080,"@enabled":"true"},"securePort":{"$":8443,"@enabled":"false"},"metadata":{"traefik.enable":"true"}},
+					{"instanceId":"b","hostName":"app1-b","ipAddr":"10.0.0.2","status":"DOWN","port":{"$":8080,"@enabled":true},"securePort":{"$":8443,"@enabled":false}}
+				]},
+				{"name":"APP2","instance":[]}
+			]}}`,
+			expected: []application{
+				{
+					Name: "APP1",
+					Instances: instances{
+						{
+							InstanceID: "a",
+							HostName:   "app1-a",
+							IPAddr:     "10.0.0.1",
+							Status:     "UP",
+							Port:       port{Number: "8080", Enabled: "true"},
+							SecurePort: port{Number: "8443", Enabled: "false"},
+							Metadata:   map[string]string{"traefik.enable": "true"},
+						},
+						{
+							InstanceID: "b",
+							HostName:   "app1-b",
+							IPAddr:     "10.0.0.2",
+							Status:     "DOWN",
+							Port:       port{Number: "8080", Enabled: true},
+							SecurePort: port{Number: "8443", Enabled: false},
+						},
+					},
+				},
+				{
+					Name:      "APP2",
+					Instances: instances{},
+				},
+			},
+		},
+		{
+			desc: "single application and instance as objects",
+			body: `{"applications":{"application":
+				{"name":"APP1","instance":
+					{"instanceId":"a","ipAddr":"10.0.0.1","status":"UP","port":{"$":"8080","@enabled":"true"}}
+				}
+			}}`,
+			expected: []application{
+				{
+					Name: "APP1",
+					Instances: instances{
+						{
+							InstanceID: "a",
+							IPAddr:     "10.0.0.1",
+							Status:     "UP",
+							Port:       port{Number: "8080", Enabled: "true"},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc:     "basic auth",
+			username: "user",
+			password: "pass",
+			body:     `{"applications":{"application":[]}}`,
+			expected: []application{},
+		},
+		{
+			desc:          "non-ok response",
+			statusCode:    http.StatusInternalServerError,
+			expectedError: true,
+		},
+		{
+			desc:          "invalid body",
+			body:          `{"applications":`,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if req.URL.Path != "/eureka/apps" || req.Header.Get("Accept") != "application/json" {
+					http.NotFound(rw, req)
+					return
+				}
+
+				username, password, ok := req.BasicAuth()
+				if ok != (test.username != "") || username != test.username || password != test.password {
+					rw.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				if test.statusCode != 0 {
+					rw.WriteHeader(test.statusCode)
+					return
+				}
+
+				_, _ = rw.Write([]byte(test.body))
+			}))
+			t.Cleanup(server.Close)
+
+			p := Provider{
+				Endpoint:        server.URL + "/eureka/",
+				Username:        test.username,
+				Password:        test.password,
+				RefreshInterval: ptypes.Duration(time.Second),
+				Timeout:         ptypes.Duration(time.Second),
+			}
+			require.NoError(t, p.Init())
+
+			apps, err := p.fetchApplications(context.Background())
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, apps)
+		})
+	}
+}
+
+func TestPort_enabled(t *testing.T) {
+	assert.True(t, port{Enabled: "true"}.enabled())
+	assert.True(t, port{Enabled: true}.enabled())
+	assert.False(t, port{Enabled: "false"}.enabled())
+	assert.False(t, port{Enabled: false}.enabled())
+	assert.False(t, port{}.enabled())
+}
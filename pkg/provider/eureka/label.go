@@ -0,0 +1,22 @@
+package eureka
+
+import (
+	"github.com/traefik/traefik/v2/pkg/config/label"
+)
+
+type configuration struct {
+	Enable bool
+}
+
+func (p *Provider) getConfiguration(labels map[string]string) (configuration, error) {
+	conf := configuration{
+		Enable: p.ExposedByDefault,
+	}
+
+	err := label.Decode(labels, &conf, "traefik.eureka.", "traefik.enable")
+	if err != nil {
+		return configuration{}, err
+	}
+
+	return conf, nil
+}
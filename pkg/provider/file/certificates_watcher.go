@@ -0,0 +1,95 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+	"gopkg.in/fsnotify.v1"
+)
+
+// certificatesWatcher watches the directories of the files referenced by the configuration,
+// and detects the changes of their content.
+type certificatesWatcher struct {
+	watcher *fsnotify.Watcher
+
+	mu          sync.Mutex
+	files       []string
+	directories map[string]struct{}
+	hash        []byte
+}
+
+func newCertificatesWatcher(watcher *fsnotify.Watcher) *certificatesWatcher {
+	return &certificatesWatcher{
+		watcher:     watcher,
+		directories: make(map[string]struct{}),
+	}
+}
+
+// update sets the files referenced by the configuration, watches their directories,
+// and takes their current content as the reference for the next changes.
+func (w *certificatesWatcher) update(ctx context.Context, referencedFiles map[string]struct{}) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	files := make([]string, 0, len(referencedFiles))
+	directories := make(map[string]struct{})
+	for file := range referencedFiles {
+		files = append(files, file)
+		directories[filepath.Dir(file)] = struct{}{}
+	}
+	sort.Strings(files)
+
+	for directory := range directories {
+		if _, ok := w.directories[directory]; ok {
+			continue
+		}
+
+		if err := w.watcher.Add(directory); err != nil {
+			log.FromContext(ctx).Errorf("Unable to watch the certificates directory %s: %v", directory, err)
+			delete(directories, directory)
+		}
+	}
+
+	for directory := range w.directories {
+		if _, ok := directories[directory]; ok {
+			continue
+		}
+
+		if err := w.watcher.Remove(directory); err != nil {
+			log.FromContext(ctx).Debugf("Unable to stop watching the certificates directory %s: %v", directory, err)
+		}
+	}
+
+	w.files = files
+	w.directories = directories
+	w.hash = hashFiles(files)
+}
+
+// changed reports whether the content of the referenced files changed since the last update.
+func (w *certificatesWatcher) changed() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return !bytes.Equal(w.hash, hashFiles(w.files))
+}
+
+// hashFiles hashes the names and the content of the files.
+// The missing files are hashed as empty files.
+func hashFiles(files []string) []byte {
+	hash := sha256.New()
+	for _, file := range files {
+		content, _ := os.ReadFile(file)
+
+		contentHash := sha256.Sum256(content)
+		hash.Write([]byte(file))
+		hash.Write(contentHash[:])
+	}
+
+	return hash.Sum(nil)
+}
@@ -5,11 +5,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
+	"sync"
 	"text/template"
 
+	"github.com/BurntSushi/toml"
 	"github.com/Masterminds/sprig/v3"
 	"github.com/traefik/paerser/file"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
@@ -18,6 +22,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/safe"
 	"github.com/traefik/traefik/v2/pkg/tls"
 	"gopkg.in/fsnotify.v1"
+	"gopkg.in/yaml.v3"
 )
 
 const providerName = "file"
@@ -26,10 +31,17 @@ var _ provider.Provider = (*Provider)(nil)
 
 // Provider holds configurations of the provider.
 type Provider struct {
-	Directory                 string `description:"Load dynamic configuration from one or more .yml or .toml files in a directory." json:"directory,omitempty" toml:"directory,omitempty" yaml:"directory,omitempty" export:"true"`
-	Watch                     bool   `description:"Watch provider." json:"watch,omitempty" toml:"watch,omitempty" yaml:"watch,omitempty" export:"true"`
-	Filename                  string `description:"Load dynamic configuration from a file." json:"filename,omitempty" toml:"filename,omitempty" yaml:"filename,omitempty" export:"true"`
-	DebugLogGeneratedTemplate bool   `description:"Enable debug logging of generated configuration template." json:"debugLogGeneratedTemplate,omitempty" toml:"debugLogGeneratedTemplate,omitempty" yaml:"debugLogGeneratedTemplate,omitempty" export:"true"`
+	Directory                 string   `description:"Load dynamic configuration from one or more .yml or .toml files in a directory." json:"directory,omitempty" toml:"directory,omitempty" yaml:"directory,omitempty" export:"true"`
+	Include                   []string `description:"Glob patterns of the files to load from the directory (e.g. *.yml)." json:"include,omitempty" toml:"include,omitempty" yaml:"include,omitempty" export:"true"`
+	Watch                     bool     `description:"Watch provider." json:"watch,omitempty" toml:"watch,omitempty" yaml:"watch,omitempty" export:"true"`
+	Filename                  string   `description:"Load dynamic configuration from a file." json:"filename,omitempty" toml:"filename,omitempty" yaml:"filename,omitempty" export:"true"`
+	DebugLogGeneratedTemplate bool     `description:"Enable debug logging of generated configuration template." json:"debugLogGeneratedTemplate,omitempty" toml:"debugLogGeneratedTemplate,omitempty" yaml:"debugLogGeneratedTemplate,omitempty" export:"true"`
+	TemplateData              string   `description:"Load the data given to the configuration templates from a YAML, JSON, or TOML file." json:"templateData,omitempty" toml:"templateData,omitempty" yaml:"templateData,omitempty" export:"true"`
+
+	// buildMu serializes the builds of the configuration, which record the files referenced by the configuration.
+	buildMu             sync.Mutex
+	referencedFiles     map[string]struct{}
+	certificatesWatcher *certificatesWatcher
 }
 
 // SetDefaults sets the default values.
@@ -40,6 +52,12 @@ func (p *Provider) SetDefaults() {
 
 // Init the provider.
 func (p *Provider) Init() error {
+	for _, pattern := range p.Include {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid include pattern %q: %w", pattern, err)
+		}
+	}
+
 	return nil
 }
 
@@ -52,20 +70,31 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 	}
 
 	if p.Watch {
-		var watchItem string
+		if err := p.watchCertificates(pool, configurationChan); err != nil {
+			return err
+		}
+
+		var watchItem, watchFile string
 
 		switch {
 		case len(p.Directory) > 0:
 			watchItem = p.Directory
 		case len(p.Filename) > 0:
 			watchItem = filepath.Dir(p.Filename)
+			watchFile = filepath.Base(p.Filename)
 		default:
 			return errors.New("error using file configuration provider, neither filename or directory defined")
 		}
 
-		if err := p.addWatcher(pool, watchItem, configurationChan, p.watcherCallback); err != nil {
+		if err := p.addWatcher(pool, watchItem, watchFile, configurationChan, p.watcherCallback); err != nil {
 			return err
 		}
+
+		if len(p.TemplateData) > 0 {
+			if err := p.addWatcher(pool, filepath.Dir(p.TemplateData), filepath.Base(p.TemplateData), configurationChan, p.watcherCallback); err != nil {
+				return err
+			}
+		}
 	}
 
 	sendConfigToChannel(configurationChan, configuration)
@@ -77,24 +106,93 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 func (p *Provider) BuildConfiguration() (*dynamic.Configuration, error) {
 	ctx := log.With(context.Background(), log.Str(log.ProviderName, providerName))
 
+	p.buildMu.Lock()
+	defer p.buildMu.Unlock()
+
+	p.referencedFiles = make(map[string]struct{})
+
+	configuration, err := p.buildConfiguration(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.certificatesWatcher != nil {
+		p.certificatesWatcher.update(ctx, p.referencedFiles)
+	}
+
+	return configuration, nil
+}
+
+func (p *Provider) buildConfiguration(ctx context.Context) (*dynamic.Configuration, error) {
+	templateData, err := p.loadTemplateData()
+	if err != nil {
+		return nil, err
+	}
+
 	if len(p.Directory) > 0 {
-		return p.loadFileConfigFromDirectory(ctx, p.Directory, nil)
+		return p.loadFileConfigFromDirectory(ctx, p.Directory, templateData)
 	}
 
 	if len(p.Filename) > 0 {
-		return p.loadFileConfig(ctx, p.Filename, true)
+		return p.loadFileConfig(ctx, p.Filename, templateData, true)
 	}
 
 	return nil, errors.New("error using file configuration provider, neither filename or directory defined")
 }
 
-func (p *Provider) addWatcher(pool *safe.Pool, directory string, configurationChan chan<- dynamic.Message, callback func(chan<- dynamic.Message, fsnotify.Event)) error {
+// watchCertificates watches the certificate, key, and CA files referenced by the configuration,
+// and reloads the configuration when their content changes.
+func (p *Provider) watchCertificates(pool *safe.Pool, configurationChan chan<- dynamic.Message) error {
+	ctx := log.With(context.Background(), log.Str(log.ProviderName, providerName))
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("error creating certificates watcher: %w", err)
+	}
+
+	certsWatcher := newCertificatesWatcher(watcher)
+
+	p.buildMu.Lock()
+	p.certificatesWatcher = certsWatcher
+	certsWatcher.update(ctx, p.referencedFiles)
+	p.buildMu.Unlock()
+
+	pool.GoCtx(func(ctxPool context.Context) {
+		defer watcher.Close()
+		for {
+			select {
+			case <-ctxPool.Done():
+				return
+			case evt := <-watcher.Events:
+				// The content is compared, rather than the event checked,
+				// so that the files replaced through symlinks (e.g. Kubernetes secrets) are reloaded too.
+				if !certsWatcher.changed() {
+					continue
+				}
+
+				log.FromContext(ctx).Debugf("Certificate files changed (%s), reloading the configuration", evt.Name)
+				p.watcherCallback(configurationChan, evt)
+			case err := <-watcher.Errors:
+				log.FromContext(ctx).Errorf("Certificates watcher event error: %s", err)
+			}
+		}
+	})
+
+	return nil
+}
+
+// addWatcher watches the given file in the directory or, if no file is given, the whole directory tree.
+func (p *Provider) addWatcher(pool *safe.Pool, directory, filename string, configurationChan chan<- dynamic.Message, callback func(chan<- dynamic.Message, fsnotify.Event)) error {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		return fmt.Errorf("error creating file watcher: %w", err)
 	}
 
-	err = watcher.Add(directory)
+	if filename != "" {
+		err = watcher.Add(directory)
+	} else {
+		err = watchDirectoryTree(watcher, directory)
+	}
 	if err != nil {
 		return fmt.Errorf("error adding file watcher: %w", err)
 	}
@@ -107,13 +205,21 @@ func (p *Provider) addWatcher(pool *safe.Pool, directory string, configurationCh
 			case <-ctx.Done():
 				return
 			case evt := <-watcher.Events:
-				if p.Directory == "" {
+				if filename != "" {
 					_, evtFileName := filepath.Split(evt.Name)
-					_, confFileName := filepath.Split(p.Filename)
-					if evtFileName == confFileName {
+					if evtFileName == filename {
 						callback(configurationChan, evt)
 					}
 				} else {
+					if evt.Op&fsnotify.Create != 0 {
+						// Watches the directories created in the directory tree.
+						if fi, err := os.Stat(evt.Name); err == nil && fi.IsDir() {
+							if err := watchDirectoryTree(watcher, evt.Name); err != nil {
+								log.WithoutContext().WithField(log.ProviderName, providerName).Errorf("Unable to watch %s: %v", evt.Name, err)
+							}
+						}
+					}
+
 					callback(configurationChan, evt)
 				}
 			case err := <-watcher.Errors:
@@ -124,6 +230,21 @@ func (p *Provider) addWatcher(pool *safe.Pool, directory string, configurationCh
 	return nil
 }
 
+// watchDirectoryTree adds the directory, and all its subdirectories, to the watcher.
+func watchDirectoryTree(watcher *fsnotify.Watcher, directory string) error {
+	return filepath.WalkDir(directory, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !entry.IsDir() {
+			return nil
+		}
+
+		return watcher.Add(path)
+	})
+}
+
 func (p *Provider) watcherCallback(configurationChan chan<- dynamic.Message, event fsnotify.Event) {
 	watchItem := p.Filename
 	if len(p.Directory) > 0 {
@@ -153,11 +274,48 @@ func sendConfigToChannel(configurationChan chan<- dynamic.Message, configuration
 	}
 }
 
-func (p *Provider) loadFileConfig(ctx context.Context, filename string, parseTemplate bool) (*dynamic.Configuration, error) {
+// readFileOrContent reads the given file or content, and records the files referenced by the configuration.
+func (p *Provider) readFileOrContent(fileOrContent tls.FileOrContent) ([]byte, error) {
+	if p.referencedFiles != nil && fileOrContent.IsPath() {
+		p.referencedFiles[fileOrContent.String()] = struct{}{}
+	}
+
+	return fileOrContent.Read()
+}
+
+// loadTemplateData loads the data given to the configuration templates, from the TemplateData file.
+func (p *Provider) loadTemplateData() (interface{}, error) {
+	if p.TemplateData == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(p.TemplateData)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read template data file %s: %w", p.TemplateData, err)
+	}
+
+	data := make(map[string]interface{})
+
+	switch strings.ToLower(filepath.Ext(p.TemplateData)) {
+	case ".toml":
+		_, err = toml.Decode(string(content), &data)
+	case ".yaml", ".yml", ".json":
+		err = yaml.Unmarshal(content, &data)
+	default:
+		return nil, fmt.Errorf("unsupported template data file format: %s", p.TemplateData)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode template data file %s: %w", p.TemplateData, err)
+	}
+
+	return data, nil
+}
+
+func (p *Provider) loadFileConfig(ctx context.Context, filename string, templateData interface{}, parseTemplate bool) (*dynamic.Configuration, error) {
 	var err error
 	var configuration *dynamic.Configuration
 	if parseTemplate {
-		configuration, err = p.CreateConfiguration(ctx, filename, template.FuncMap{}, false)
+		configuration, err = p.CreateConfiguration(ctx, filename, template.FuncMap{}, templateData)
 	} else {
 		configuration, err = p.DecodeConfiguration(filename)
 	}
@@ -166,7 +324,7 @@ func (p *Provider) loadFileConfig(ctx context.Context, filename string, parseTem
 	}
 
 	if configuration.TLS != nil {
-		configuration.TLS.Certificates = flattenCertificates(ctx, configuration.TLS)
+		configuration.TLS.Certificates = p.flattenCertificates(ctx, configuration.TLS)
 
 		// TLS Options
 		if configuration.TLS.Options != nil {
@@ -174,7 +332,7 @@ func (p *Provider) loadFileConfig(ctx context.Context, filename string, parseTem
 				var caCerts []tls.FileOrContent
 
 				for _, caFile := range options.ClientAuth.CAFiles {
-					content, err := caFile.Read()
+					content, err := p.readFileOrContent(caFile)
 					if err != nil {
 						log.FromContext(ctx).Error(err)
 						continue
@@ -195,14 +353,14 @@ func (p *Provider) loadFileConfig(ctx context.Context, filename string, parseTem
 					continue
 				}
 
-				content, err := store.DefaultCertificate.CertFile.Read()
+				content, err := p.readFileOrContent(store.DefaultCertificate.CertFile)
 				if err != nil {
 					log.FromContext(ctx).Error(err)
 					continue
 				}
 				store.DefaultCertificate.CertFile = tls.FileOrContent(content)
 
-				content, err = store.DefaultCertificate.KeyFile.Read()
+				content, err = p.readFileOrContent(store.DefaultCertificate.KeyFile)
 				if err != nil {
 					log.FromContext(ctx).Error(err)
 					continue
@@ -219,14 +377,14 @@ func (p *Provider) loadFileConfig(ctx context.Context, filename string, parseTem
 		for name, st := range configuration.HTTP.ServersTransports {
 			var certificates []tls.Certificate
 			for _, cert := range st.Certificates {
-				content, err := cert.CertFile.Read()
+				content, err := p.readFileOrContent(cert.CertFile)
 				if err != nil {
 					log.FromContext(ctx).Error(err)
 					continue
 				}
 				cert.CertFile = tls.FileOrContent(content)
 
-				content, err = cert.KeyFile.Read()
+				content, err = p.readFileOrContent(cert.KeyFile)
 				if err != nil {
 					log.FromContext(ctx).Error(err)
 					continue
@@ -240,7 +398,7 @@ func (p *Provider) loadFileConfig(ctx context.Context, filename string, parseTem
 
 			var rootCAs []tls.FileOrContent
 			for _, rootCA := range st.RootCAs {
-				content, err := rootCA.Read()
+				content, err := p.readFileOrContent(rootCA)
 				if err != nil {
 					log.FromContext(ctx).Error(err)
 					continue
@@ -256,17 +414,17 @@ func (p *Provider) loadFileConfig(ctx context.Context, filename string, parseTem
 	return configuration, nil
 }
 
-func flattenCertificates(ctx context.Context, tlsConfig *dynamic.TLSConfiguration) []*tls.CertAndStores {
+func (p *Provider) flattenCertificates(ctx context.Context, tlsConfig *dynamic.TLSConfiguration) []*tls.CertAndStores {
 	var certs []*tls.CertAndStores
 	for _, cert := range tlsConfig.Certificates {
-		content, err := cert.Certificate.CertFile.Read()
+		content, err := p.readFileOrContent(cert.Certificate.CertFile)
 		if err != nil {
 			log.FromContext(ctx).Error(err)
 			continue
 		}
 		cert.Certificate.CertFile = tls.FileOrContent(string(content))
 
-		content, err = cert.Certificate.KeyFile.Read()
+		content, err = p.readFileOrContent(cert.Certificate.KeyFile)
 		if err != nil {
 			log.FromContext(ctx).Error(err)
 			continue
@@ -279,174 +437,229 @@ func flattenCertificates(ctx context.Context, tlsConfig *dynamic.TLSConfiguratio
 	return certs
 }
 
-func (p *Provider) loadFileConfigFromDirectory(ctx context.Context, directory string, configuration *dynamic.Configuration) (*dynamic.Configuration, error) {
-	fileList, err := os.ReadDir(directory)
+func (p *Provider) loadFileConfigFromDirectory(ctx context.Context, directory string, templateData interface{}) (*dynamic.Configuration, error) {
+	filenames, err := p.listFiles(directory)
 	if err != nil {
-		return configuration, fmt.Errorf("unable to read directory %s: %w", directory, err)
-	}
-
-	if configuration == nil {
-		configuration = &dynamic.Configuration{
-			HTTP: &dynamic.HTTPConfiguration{
-				Routers:           make(map[string]*dynamic.Router),
-				Middlewares:       make(map[string]*dynamic.Middleware),
-				Services:          make(map[string]*dynamic.Service),
-				ServersTransports: make(map[string]*dynamic.ServersTransport),
-			},
-			TCP: &dynamic.TCPConfiguration{
-				Routers:     make(map[string]*dynamic.TCPRouter),
-				Services:    make(map[string]*dynamic.TCPService),
-				Middlewares: make(map[string]*dynamic.TCPMiddleware),
-			},
-			TLS: &dynamic.TLSConfiguration{
-				Stores:  make(map[string]tls.Store),
-				Options: make(map[string]tls.Options),
-			},
-			UDP: &dynamic.UDPConfiguration{
-				Routers:  make(map[string]*dynamic.UDPRouter),
-				Services: make(map[string]*dynamic.UDPService),
-			},
-		}
-	}
-
-	configTLSMaps := make(map[*tls.CertAndStores]struct{})
-
-	for _, item := range fileList {
-		logger := log.FromContext(log.With(ctx, log.Str("filename", item.Name())))
-
-		if item.IsDir() {
-			configuration, err = p.loadFileConfigFromDirectory(ctx, filepath.Join(directory, item.Name()), configuration)
-			if err != nil {
-				return configuration, fmt.Errorf("unable to load content configuration from subdirectory %s: %w", item, err)
-			}
-			continue
-		}
+		return nil, err
+	}
 
-		switch strings.ToLower(filepath.Ext(item.Name())) {
-		case ".toml", ".yaml", ".yml":
-			// noop
-		default:
-			continue
-		}
+	configuration := &dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers:           make(map[string]*dynamic.Router),
+			Middlewares:       make(map[string]*dynamic.Middleware),
+			Services:          make(map[string]*dynamic.Service),
+			ServersTransports: make(map[string]*dynamic.ServersTransport),
+		},
+		TCP: &dynamic.TCPConfiguration{
+			Routers:     make(map[string]*dynamic.TCPRouter),
+			Services:    make(map[string]*dynamic.TCPService),
+			Middlewares: make(map[string]*dynamic.TCPMiddleware),
+		},
+		TLS: &dynamic.TLSConfiguration{
+			Stores:  make(map[string]tls.Store),
+			Options: make(map[string]tls.Options),
+		},
+		UDP: &dynamic.UDPConfiguration{
+			Routers:  make(map[string]*dynamic.UDPRouter),
+			Services: make(map[string]*dynamic.UDPService),
+		},
+	}
+
+	// The files are merged in the lexical order of their paths,
+	// so that the first definition of an element always wins.
+	definitions := make(origins)
+
+	for _, filename := range filenames {
+		logger := log.FromContext(log.With(ctx, log.Str("filename", filename)))
 
 		var c *dynamic.Configuration
-		c, err = p.loadFileConfig(ctx, filepath.Join(directory, item.Name()), true)
+		c, err = p.loadFileConfig(ctx, filename, templateData, true)
 		if err != nil {
-			return configuration, fmt.Errorf("%s: %w", filepath.Join(directory, item.Name()), err)
+			return configuration, fmt.Errorf("%s: %w", filename, err)
 		}
 
 		for name, conf := range c.HTTP.Routers {
-			if _, exists := configuration.HTTP.Routers[name]; exists {
-				logger.WithField(log.RouterName, name).Warn("HTTP router already configured, skipping")
-			} else {
+			previous, exists := configuration.HTTP.Routers[name]
+			if definitions.add(logger, "HTTP router", name, filename, previous, exists, conf) {
 				configuration.HTTP.Routers[name] = conf
 			}
 		}
 
 		for name, conf := range c.HTTP.Middlewares {
-			if _, exists := configuration.HTTP.Middlewares[name]; exists {
-				logger.WithField(log.MiddlewareName, name).Warn("HTTP middleware already configured, skipping")
-			} else {
+			previous, exists := configuration.HTTP.Middlewares[name]
+			if definitions.add(logger, "HTTP middleware", name, filename, previous, exists, conf) {
 				configuration.HTTP.Middlewares[name] = conf
 			}
 		}
 
 		for name, conf := range c.HTTP.Services {
-			if _, exists := configuration.HTTP.Services[name]; exists {
-				logger.WithField(log.ServiceName, name).Warn("HTTP service already configured, skipping")
-			} else {
+			previous, exists := configuration.HTTP.Services[name]
+			if definitions.add(logger, "HTTP service", name, filename, previous, exists, conf) {
 				configuration.HTTP.Services[name] = conf
 			}
 		}
 
 		for name, conf := range c.HTTP.ServersTransports {
-			if _, exists := configuration.HTTP.ServersTransports[name]; exists {
-				logger.WithField(log.ServersTransportName, name).Warn("HTTP servers transport already configured, skipping")
-			} else {
+			previous, exists := configuration.HTTP.ServersTransports[name]
+			if definitions.add(logger, "HTTP servers transport", name, filename, previous, exists, conf) {
 				configuration.HTTP.ServersTransports[name] = conf
 			}
 		}
 
 		for name, conf := range c.TCP.Routers {
-			if _, exists := configuration.TCP.Routers[name]; exists {
-				logger.WithField(log.RouterName, name).Warn("TCP router already configured, skipping")
-			} else {
+			previous, exists := configuration.TCP.Routers[name]
+			if definitions.add(logger, "TCP router", name, filename, previous, exists, conf) {
 				configuration.TCP.Routers[name] = conf
 			}
 		}
 
 		for name, conf := range c.TCP.Middlewares {
-			if _, exists := configuration.TCP.Middlewares[name]; exists {
-				logger.WithField(log.MiddlewareName, name).Warn("TCP middleware already configured, skipping")
-			} else {
+			previous, exists := configuration.TCP.Middlewares[name]
+			if definitions.add(logger, "TCP middleware", name, filename, previous, exists, conf) {
 				configuration.TCP.Middlewares[name] = conf
 			}
 		}
 
 		for name, conf := range c.TCP.Services {
-			if _, exists := configuration.TCP.Services[name]; exists {
-				logger.WithField(log.ServiceName, name).Warn("TCP service already configured, skipping")
-			} else {
+			previous, exists := configuration.TCP.Services[name]
+			if definitions.add(logger, "TCP service", name, filename, previous, exists, conf) {
 				configuration.TCP.Services[name] = conf
 			}
 		}
 
 		for name, conf := range c.UDP.Routers {
-			if _, exists := configuration.UDP.Routers[name]; exists {
-				logger.WithField(log.RouterName, name).Warn("UDP router already configured, skipping")
-			} else {
+			previous, exists := configuration.UDP.Routers[name]
+			if definitions.add(logger, "UDP router", name, filename, previous, exists, conf) {
 				configuration.UDP.Routers[name] = conf
 			}
 		}
 
 		for name, conf := range c.UDP.Services {
-			if _, exists := configuration.UDP.Services[name]; exists {
-				logger.WithField(log.ServiceName, name).Warn("UDP service already configured, skipping")
-			} else {
+			previous, exists := configuration.UDP.Services[name]
+			if definitions.add(logger, "UDP service", name, filename, previous, exists, conf) {
 				configuration.UDP.Services[name] = conf
 			}
 		}
 
 		for _, conf := range c.TLS.Certificates {
-			if _, exists := configTLSMaps[conf]; exists {
-				logger.Warnf("TLS configuration %v already configured, skipping", conf)
-			} else {
-				configTLSMaps[conf] = struct{}{}
+			if containsCertificate(configuration.TLS.Certificates, conf) {
+				logger.Debugf("TLS certificate %s already configured, skipping", conf.Certificate.CertFile)
+				continue
 			}
+
+			configuration.TLS.Certificates = append(configuration.TLS.Certificates, conf)
 		}
 
 		for name, conf := range c.TLS.Options {
-			if _, exists := configuration.TLS.Options[name]; exists {
-				logger.Warnf("TLS options %v already configured, skipping", name)
-			} else {
-				if configuration.TLS.Options == nil {
-					configuration.TLS.Options = map[string]tls.Options{}
-				}
+			previous, exists := configuration.TLS.Options[name]
+			if definitions.add(logger, "TLS options", name, filename, previous, exists, conf) {
 				configuration.TLS.Options[name] = conf
 			}
 		}
 
 		for name, conf := range c.TLS.Stores {
-			if _, exists := configuration.TLS.Stores[name]; exists {
-				logger.Warnf("TLS store %v already configured, skipping", name)
-			} else {
-				if configuration.TLS.Stores == nil {
-					configuration.TLS.Stores = map[string]tls.Store{}
-				}
+			previous, exists := configuration.TLS.Stores[name]
+			if definitions.add(logger, "TLS store", name, filename, previous, exists, conf) {
 				configuration.TLS.Stores[name] = conf
 			}
 		}
 	}
 
-	if len(configTLSMaps) > 0 && configuration.TLS == nil {
-		configuration.TLS = &dynamic.TLSConfiguration{}
+	return configuration, nil
+}
+
+// listFiles returns the configuration files found in the directory tree, sorted by path.
+func (p *Provider) listFiles(directory string) ([]string, error) {
+	var filenames []string
+
+	err := filepath.WalkDir(directory, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".toml", ".yaml", ".yml":
+			// noop
+		default:
+			return nil
+		}
+
+		if p.isIncluded(directory, path) {
+			filenames = append(filenames, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read directory %s: %w", directory, err)
+	}
+
+	return filenames, nil
+}
+
+// isIncluded reports whether the file matches one of the include patterns,
+// either by its name or by its path relative to the directory.
+func (p *Provider) isIncluded(directory, filename string) bool {
+	if len(p.Include) == 0 {
+		return true
 	}
 
-	for conf := range configTLSMaps {
-		configuration.TLS.Certificates = append(configuration.TLS.Certificates, conf)
+	relPath, err := filepath.Rel(directory, filename)
+	if err != nil {
+		relPath = filename
 	}
 
-	return configuration, nil
+	for _, pattern := range p.Include {
+		if matched, _ := filepath.Match(pattern, filepath.Base(filename)); matched {
+			return true
+		}
+
+		if matched, _ := filepath.Match(pattern, relPath); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// origins holds, by kind of element (e.g. HTTP router), the file where each element is defined.
+type origins map[string]map[string]string
+
+// add reports whether the element defined in the given file must be added to the configuration.
+// An element already defined by a previous file is skipped,
+// and a conflict is reported if the definitions are different.
+func (o origins) add(logger log.Logger, kind, name, filename string, previous interface{}, exists bool, conf interface{}) bool {
+	if !exists {
+		if o[kind] == nil {
+			o[kind] = make(map[string]string)
+		}
+		o[kind][name] = filename
+
+		return true
+	}
+
+	if reflect.DeepEqual(previous, conf) {
+		logger.Debugf("%s %s already configured in %s, skipping", kind, name, o[kind][name])
+		return false
+	}
+
+	logger.Errorf("%s %s defined with different configurations in %s and %s, keeping the one from %s", kind, name, o[kind][name], filename, o[kind][name])
+
+	return false
+}
+
+func containsCertificate(certificates []*tls.CertAndStores, certificate *tls.CertAndStores) bool {
+	for _, c := range certificates {
+		if reflect.DeepEqual(c, certificate) {
+			return true
+		}
+	}
+
+	return false
 }
 
 // CreateConfiguration creates a provider configuration from content using templating.
@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"testing"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/safe"
 )
 
@@ -60,7 +62,7 @@ func TestTLSCertificateContent(t *testing.T) {
 	require.NoError(t, err)
 
 	provider := &Provider{}
-	configuration, err := provider.loadFileConfig(context.Background(), fileConfig.Name(), true)
+	configuration, err := provider.loadFileConfig(context.Background(), fileConfig.Name(), nil, true)
 	require.NoError(t, err)
 
 	require.Equal(t, "CONTENT", configuration.TLS.Certificates[0].Certificate.CertFile.String())
@@ -76,6 +78,271 @@ func TestTLSCertificateContent(t *testing.T) {
 	require.Equal(t, "CONTENT", configuration.HTTP.ServersTransports["default"].RootCAs[0].String())
 }
 
+func TestProvider_BuildConfiguration_include(t *testing.T) {
+	tempDir := t.TempDir()
+
+	writeFile(t, filepath.Join(tempDir, "foo.yml"), "http:\n  routers:\n    foo:\n      service: foo\n")
+	writeFile(t, filepath.Join(tempDir, "bar.toml"), "[http.routers.bar]\n  service = \"bar\"\n")
+	writeFile(t, filepath.Join(tempDir, "team", "baz.yml"), "http:\n  routers:\n    baz:\n      service: baz\n")
+	writeFile(t, filepath.Join(tempDir, "team", "ignored.txt"), "http:\n  routers:\n    ignored:\n      service: ignored\n")
+
+	testCases := []struct {
+		desc     string
+		include  []string
+		expected []string
+	}{
+		{
+			desc:     "all the files",
+			expected: []string{"bar", "baz", "foo"},
+		},
+		{
+			desc:     "matching the file names",
+			include:  []string{"*.yml"},
+			expected: []string{"baz", "foo"},
+		},
+		{
+			desc:     "matching the relative paths",
+			include:  []string{filepath.Join("team", "*")},
+			expected: []string{"baz"},
+		},
+		{
+			desc:     "matching several patterns",
+			include:  []string{"foo.*", "*.toml"},
+			expected: []string{"bar", "foo"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			provider := &Provider{Directory: tempDir, Include: test.include}
+			require.NoError(t, provider.Init())
+
+			configuration, err := provider.BuildConfiguration()
+			require.NoError(t, err)
+
+			var routers []string
+			for name := range configuration.HTTP.Routers {
+				routers = append(routers, name)
+			}
+			sort.Strings(routers)
+
+			assert.Equal(t, test.expected, routers)
+		})
+	}
+}
+
+func TestProvider_Init_invalidInclude(t *testing.T) {
+	provider := &Provider{Directory: t.TempDir(), Include: []string{"[*.yml"}}
+
+	assert.Error(t, provider.Init())
+}
+
+func TestProvider_BuildConfiguration_fragments(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// The files are merged in the lexical order of their paths.
+	writeFile(t, filepath.Join(tempDir, "a", "routers.yml"), "http:\n  routers:\n    foo:\n      service: foo\n      rule: Host(`foo.localhost`)\n")
+	writeFile(t, filepath.Join(tempDir, "b", "routers.yml"), "http:\n  routers:\n    foo:\n      service: bar\n      rule: Host(`foo.localhost`)\n")
+	writeFile(t, filepath.Join(tempDir, "a", "services.yml"), "http:\n  services:\n    foo:\n      loadBalancer:\n        servers:\n          - url: http://127.0.0.1\n")
+	writeFile(t, filepath.Join(tempDir, "c.yml"), "http:\n  services:\n    foo:\n      loadBalancer:\n        servers:\n          - url: http://127.0.0.1\n")
+
+	provider := &Provider{Directory: tempDir}
+
+	configuration, err := provider.BuildConfiguration()
+	require.NoError(t, err)
+
+	require.Contains(t, configuration.HTTP.Routers, "foo")
+	assert.Equal(t, "foo", configuration.HTTP.Routers["foo"].Service)
+
+	require.Contains(t, configuration.HTTP.Services, "foo")
+	assert.Equal(t, "http://127.0.0.1", configuration.HTTP.Services["foo"].LoadBalancer.Servers[0].URL)
+}
+
+func TestOrigins_add(t *testing.T) {
+	logger := log.WithoutContext()
+	definitions := make(origins)
+
+	assert.True(t, definitions.add(logger, "HTTP router", "foo", "a.yml", nil, false, &dynamic.Router{Service: "foo"}))
+	assert.True(t, definitions.add(logger, "HTTP service", "foo", "a.yml", nil, false, &dynamic.Service{}))
+
+	// Same definition in another file.
+	assert.False(t, definitions.add(logger, "HTTP router", "foo", "b.yml", &dynamic.Router{Service: "foo"}, true, &dynamic.Router{Service: "foo"}))
+	// Conflicting definition in another file.
+	assert.False(t, definitions.add(logger, "HTTP router", "foo", "c.yml", &dynamic.Router{Service: "foo"}, true, &dynamic.Router{Service: "bar"}))
+
+	assert.Equal(t, origins{
+		"HTTP router":  {"foo": "a.yml"},
+		"HTTP service": {"foo": "a.yml"},
+	}, definitions)
+}
+
+func TestProvideWithWatch_subdirectory(t *testing.T) {
+	tempDir := t.TempDir()
+
+	provider := &Provider{Directory: tempDir, Watch: true}
+	configChan := make(chan dynamic.Message)
+
+	go func() {
+		err := provider.Provide(configChan, safe.NewPool(context.Background()))
+		assert.NoError(t, err)
+	}()
+
+	select {
+	case conf := <-configChan:
+		assert.Empty(t, conf.Configuration.HTTP.Routers)
+	case <-time.After(time.Second):
+		t.Fatal("timeout while waiting for config")
+	}
+
+	subDir := filepath.Join(tempDir, "team")
+	require.NoError(t, os.Mkdir(subDir, 0o755))
+
+	// Gives the watcher the time to add the created directory.
+	time.Sleep(100 * time.Millisecond)
+
+	writeFile(t, filepath.Join(subDir, "routers.yml"), "http:\n  routers:\n    foo:\n      service: foo\n")
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case conf := <-configChan:
+			if _, ok := conf.Configuration.HTTP.Routers["foo"]; ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("timeout while waiting for config")
+		}
+	}
+}
+
+func TestProvideWithWatch_certificates(t *testing.T) {
+	tempDir := t.TempDir()
+
+	certFile := filepath.Join(tempDir, "certs", "tls.crt")
+	keyFile := filepath.Join(tempDir, "certs", "tls.key")
+	writeFile(t, certFile, "CONTENT")
+	writeFile(t, keyFile, "CONTENTKEY")
+
+	configFile := filepath.Join(tempDir, "config", "tls.toml")
+	writeFile(t, configFile, "[[tls.certificates]]\n  certFile = \""+certFile+"\"\n  keyFile = \""+keyFile+"\"\n")
+
+	provider := &Provider{Filename: configFile, Watch: true}
+	configChan := make(chan dynamic.Message)
+
+	go func() {
+		err := provider.Provide(configChan, safe.NewPool(context.Background()))
+		assert.NoError(t, err)
+	}()
+
+	select {
+	case conf := <-configChan:
+		require.Len(t, conf.Configuration.TLS.Certificates, 1)
+		assert.Equal(t, "CONTENT", conf.Configuration.TLS.Certificates[0].Certificate.CertFile.String())
+	case <-time.After(time.Second):
+		t.Fatal("timeout while waiting for config")
+	}
+
+	// The renewed certificate is loaded, while the configuration file is unchanged.
+	writeFile(t, certFile, "RENEWED")
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case conf := <-configChan:
+			certificates := conf.Configuration.TLS.Certificates
+			if len(certificates) == 1 && certificates[0].Certificate.CertFile.String() == "RENEWED" {
+				return
+			}
+		case <-timeout:
+			t.Fatal("timeout while waiting for config")
+		}
+	}
+}
+
+func TestProvider_BuildConfiguration_templateData(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		filename string
+		content  string
+	}{
+		{
+			desc:     "YAML data",
+			filename: "data.yml",
+			content:  "domain: example.com\nteams:\n  - name: foo\n    port: 8080\n  - name: bar\n    port: 8081\n",
+		},
+		{
+			desc:     "JSON data",
+			filename: "data.json",
+			content:  `{"domain": "example.com", "teams": [{"name": "foo", "port": 8080}, {"name": "bar", "port": 8081}]}`,
+		},
+		{
+			desc:     "TOML data",
+			filename: "data.toml",
+			content:  "domain = \"example.com\"\n[[teams]]\n  name = \"foo\"\n  port = 8080\n[[teams]]\n  name = \"bar\"\n  port = 8081\n",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			tempDir := t.TempDir()
+
+			writeFile(t, filepath.Join(tempDir, test.filename), test.content)
+			writeFile(t, filepath.Join(tempDir, "dynamic.yml"), `
+http:
+  routers:
+{{- range .teams }}
+    {{ .name }}:
+      rule: Host(`+"`{{ .name }}.{{ $.domain }}`"+`)
+      service: {{ .name }}
+{{- end }}
+  services:
+{{- range .teams }}
+    {{ .name }}:
+      loadBalancer:
+        servers:
+          - url: http://127.0.0.1:{{ .port }}
+{{- end }}
+`)
+
+			provider := &Provider{
+				Filename:     filepath.Join(tempDir, "dynamic.yml"),
+				TemplateData: filepath.Join(tempDir, test.filename),
+			}
+
+			configuration, err := provider.BuildConfiguration()
+			require.NoError(t, err)
+
+			require.Len(t, configuration.HTTP.Routers, 2)
+			assert.Equal(t, "Host(`foo.example.com`)", configuration.HTTP.Routers["foo"].Rule)
+			assert.Equal(t, "Host(`bar.example.com`)", configuration.HTTP.Routers["bar"].Rule)
+
+			require.Len(t, configuration.HTTP.Services, 2)
+			assert.Equal(t, "http://127.0.0.1:8081", configuration.HTTP.Services["bar"].LoadBalancer.Servers[0].URL)
+		})
+	}
+}
+
+func TestProvider_BuildConfiguration_invalidTemplateData(t *testing.T) {
+	tempDir := t.TempDir()
+
+	writeFile(t, filepath.Join(tempDir, "dynamic.yml"), "http: {}\n")
+	writeFile(t, filepath.Join(tempDir, "data.ini"), "domain = example.com\n")
+
+	provider := &Provider{
+		Filename:     filepath.Join(tempDir, "dynamic.yml"),
+		TemplateData: filepath.Join(tempDir, "data.ini"),
+	}
+
+	_, err := provider.BuildConfiguration()
+	assert.Error(t, err)
+}
+
 func TestErrorWhenEmptyConfig(t *testing.T) {
 	provider := &Provider{}
 	configChan := make(chan dynamic.Message)
@@ -346,3 +613,13 @@ func createTempFile(srcPath, tempDir string) (*os.File, error) {
 	_, err = io.Copy(file, src)
 	return file, err
 }
+
+func writeFile(t *testing.T, filename, content string) {
+	t.Helper()
+
+	err := os.MkdirAll(filepath.Dir(filename), 0o755)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filename, []byte(content), 0o600)
+	require.NoError(t, err)
+}
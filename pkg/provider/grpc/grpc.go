@@ -0,0 +1,159 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/traefik/paerser/file"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/job"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider"
+	providerv1 "github.com/traefik/traefik/v2/pkg/provider/grpc/v1"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/tls"
+	"github.com/traefik/traefik/v2/pkg/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const providerName = "grpc"
+
+var _ provider.Provider = (*Provider)(nil)
+
+// Provider is a provider.Provider implementation that receives the configurations of an external provider over gRPC,
+// through the stream of the Watch method of the traefik.provider.v1.Provider service.
+type Provider struct {
+	Address     string           `description:"Address of the external provider, either host:port or unix:///path/to/socket." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	DialTimeout ptypes.Duration  `description:"Timeout when connecting to the external provider." json:"dialTimeout,omitempty" toml:"dialTimeout,omitempty" yaml:"dialTimeout,omitempty" export:"true"`
+	TLS         *types.ClientTLS `description:"Enable TLS support." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	dialOptions []grpc.DialOption
+}
+
+// SetDefaults sets the default values.
+func (p *Provider) SetDefaults() {
+	p.DialTimeout = ptypes.Duration(5 * time.Second)
+}
+
+// Init the provider.
+func (p *Provider) Init() error {
+	if p.Address == "" {
+		return errors.New("non-empty address is required")
+	}
+
+	p.dialOptions = []grpc.DialOption{grpc.WithInsecure()}
+
+	if p.TLS != nil {
+		tlsConfig, err := p.TLS.CreateTLSConfig(context.Background())
+		if err != nil {
+			return fmt.Errorf("unable to create TLS configuration: %w", err)
+		}
+
+		p.dialOptions = []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}
+	}
+
+	return nil
+}
+
+// Provide allows the provider to provide configurations to traefik using the given configuration channel.
+func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
+	pool.GoCtx(func(routineCtx context.Context) {
+		ctxLog := log.With(routineCtx, log.Str(log.ProviderName, providerName))
+		logger := log.FromContext(ctxLog)
+
+		operation := func() error {
+			return p.watch(ctxLog, configurationChan)
+		}
+
+		notify := func(err error, time time.Duration) {
+			logger.Errorf("Provider connection error %+v, retrying in %s", err, time)
+		}
+		err := backoff.RetryNotify(safe.OperationWithRecover(operation), backoff.WithContext(job.NewBackOff(backoff.NewExponentialBackOff()), ctxLog), notify)
+		if err != nil {
+			logger.Errorf("Cannot connect to external provider %+v", err)
+		}
+	})
+
+	return nil
+}
+
+// watch connects to the external provider, and sends the configurations it streams until the stream ends.
+func (p *Provider) watch(ctx context.Context, configurationChan chan<- dynamic.Message) error {
+	dialCtx, cancel := context.WithTimeout(ctx, time.Duration(p.DialTimeout))
+	defer cancel()
+
+	conn, err := grpc.DialContext(dialCtx, p.Address, append(p.dialOptions, grpc.WithBlock())...)
+	if err != nil {
+		return fmt.Errorf("cannot connect to %s: %w", p.Address, err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	stream, err := providerv1.NewProviderClient(conn).Watch(ctx, &providerv1.WatchRequest{})
+	if err != nil {
+		return fmt.Errorf("cannot watch configurations: %w", err)
+	}
+
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			if errors.Is(err, io.EOF) {
+				return errors.New("the external provider ended the stream")
+			}
+
+			return fmt.Errorf("cannot receive configuration: %w", err)
+		}
+
+		configuration, err := decodeConfiguration(msg.GetJson())
+		if err != nil {
+			// The external provider is still connected, so the configuration is skipped
+			// and the current one is kept until the next one is received.
+			log.FromContext(ctx).Errorf("Cannot decode configuration: %v", err)
+			continue
+		}
+
+		configurationChan <- dynamic.Message{
+			ProviderName:  providerName,
+			Configuration: configuration,
+		}
+	}
+}
+
+// decodeConfiguration decodes and returns the dynamic configuration from the given JSON data.
+func decodeConfiguration(data []byte) (*dynamic.Configuration, error) {
+	configuration := &dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers:           make(map[string]*dynamic.Router),
+			Middlewares:       make(map[string]*dynamic.Middleware),
+			Services:          make(map[string]*dynamic.Service),
+			ServersTransports: make(map[string]*dynamic.ServersTransport),
+		},
+		TCP: &dynamic.TCPConfiguration{
+			Routers:  make(map[string]*dynamic.TCPRouter),
+			Services: make(map[string]*dynamic.TCPService),
+		},
+		TLS: &dynamic.TLSConfiguration{
+			Stores:  make(map[string]tls.Store),
+			Options: make(map[string]tls.Options),
+		},
+		UDP: &dynamic.UDPConfiguration{
+			Routers:  make(map[string]*dynamic.UDPRouter),
+			Services: make(map[string]*dynamic.UDPService),
+		},
+	}
+
+	err := file.DecodeContent(string(data), ".json", configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	return configuration, nil
+}
@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	providerv1 "github.com/traefik/traefik/v2/pkg/provider/grpc/v1"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	"google.golang.org/grpc"
+)
+
+type providerServer struct {
+	configurations [][]byte
+}
+
+func (s *providerServer) Watch(_ *providerv1.WatchRequest, stream providerv1.Provider_WatchServer) error {
+	for _, configuration := range s.configurations {
+		if err := stream.Send(&providerv1.Configuration{Json: configuration}); err != nil {
+			return err
+		}
+	}
+
+	<-stream.Context().Done()
+	return nil
+}
+
+func TestProvider_Init(t *testing.T) {
+	provider := Provider{}
+	provider.SetDefaults()
+
+	assert.Error(t, provider.Init())
+
+	provider.Address = "127.0.0.1:9000"
+	assert.NoError(t, provider.Init())
+}
+
+func TestProvider_Provide(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	providerv1.RegisterProviderServer(server, &providerServer{
+		configurations: [][]byte{
+			[]byte(`{"http":{"routers":{"foo":{"rule":"Host(` + "`foo.com`" + `)","service":"bar"}}}}`),
+			[]byte(`{"http":`),
+			[]byte(`{"http":{"services":{"bar":{"loadBalancer":{"servers":[{"url":"http://127.0.0.1"}]}}}}}`),
+		},
+	})
+
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	provider := Provider{Address: listener.Addr().String()}
+	provider.SetDefaults()
+	require.NoError(t, provider.Init())
+
+	configurationChan := make(chan dynamic.Message)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	pool := safe.NewPool(ctx)
+	t.Cleanup(pool.Stop)
+
+	err = provider.Provide(configurationChan, pool)
+	require.NoError(t, err)
+
+	select {
+	case msg := <-configurationChan:
+		assert.Equal(t, "grpc", msg.ProviderName)
+		assert.Equal(t, &dynamic.Router{Rule: "Host(`foo.com`)", Service: "bar"}, msg.Configuration.HTTP.Routers["foo"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout while waiting for the first configuration")
+	}
+
+	// The invalid configuration is skipped.
+	select {
+	case msg := <-configurationChan:
+		assert.Empty(t, msg.Configuration.HTTP.Routers)
+		require.NotNil(t, msg.Configuration.HTTP.Services["bar"])
+		require.NotNil(t, msg.Configuration.HTTP.Services["bar"].LoadBalancer)
+		assert.Equal(t, []dynamic.Server{{URL: "http://127.0.0.1", Scheme: "http"}}, msg.Configuration.HTTP.Services["bar"].LoadBalancer.Servers)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout while waiting for the second configuration")
+	}
+}
@@ -24,12 +24,15 @@ var _ provider.Provider = (*Provider)(nil)
 
 // Provider is a provider.Provider implementation that queries an HTTP(s) endpoint for a configuration.
 type Provider struct {
-	Endpoint              string           `description:"Load configuration from this endpoint." json:"endpoint" toml:"endpoint" yaml:"endpoint"`
-	PollInterval          ptypes.Duration  `description:"Polling interval for endpoint." json:"pollInterval,omitempty" toml:"pollInterval,omitempty" yaml:"pollInterval,omitempty" export:"true"`
-	PollTimeout           ptypes.Duration  `description:"Polling timeout for endpoint." json:"pollTimeout,omitempty" toml:"pollTimeout,omitempty" yaml:"pollTimeout,omitempty" export:"true"`
-	TLS                   *types.ClientTLS `description:"Enable TLS support." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	Endpoint              string            `description:"Load configuration from this endpoint." json:"endpoint" toml:"endpoint" yaml:"endpoint"`
+	PollInterval          ptypes.Duration   `description:"Polling interval for endpoint." json:"pollInterval,omitempty" toml:"pollInterval,omitempty" yaml:"pollInterval,omitempty" export:"true"`
+	PollTimeout           ptypes.Duration   `description:"Polling timeout for endpoint." json:"pollTimeout,omitempty" toml:"pollTimeout,omitempty" yaml:"pollTimeout,omitempty" export:"true"`
+	TLS                   *types.ClientTLS  `description:"Enable TLS support." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	Headers               map[string]string `description:"Define custom headers to be sent to the endpoint." json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty"`
 	httpClient            *http.Client
 	lastConfigurationHash uint64
+	// lastETag is the entity tag of the last configuration provided, sent back to the endpoint in the If-None-Match header.
+	lastETag string
 }
 
 // SetDefaults sets the default values.
@@ -79,11 +82,16 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 			for {
 				select {
 				case <-ticker.C:
-					configData, err := p.fetchConfigurationData()
+					configData, etag, err := p.fetchConfigurationData()
 					if err != nil {
 						return fmt.Errorf("cannot fetch configuration data: %w", err)
 					}
 
+					if configData == nil {
+						// The endpoint reported that the configuration has not been modified.
+						continue
+					}
+
 					fnvHasher := fnv.New64()
 
 					_, err = fnvHasher.Write(configData)
@@ -96,13 +104,14 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 						continue
 					}
 
-					p.lastConfigurationHash = hash
-
 					configuration, err := decodeConfiguration(configData)
 					if err != nil {
 						return fmt.Errorf("cannot decode configuration data: %w", err)
 					}
 
+					p.lastConfigurationHash = hash
+					p.lastETag = etag
+
 					configurationChan <- dynamic.Message{
 						ProviderName:  "http",
 						Configuration: configuration,
@@ -126,20 +135,43 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 	return nil
 }
 
-// fetchConfigurationData fetches the configuration data from the configured endpoint.
-func (p *Provider) fetchConfigurationData() ([]byte, error) {
-	res, err := p.httpClient.Get(p.Endpoint)
+// fetchConfigurationData fetches the configuration data, and its entity tag, from the configured endpoint.
+// It returns nil data if the endpoint reports that the configuration has not been modified since the last one provided.
+func (p *Provider) fetchConfigurationData() ([]byte, string, error) {
+	req, err := http.NewRequest(http.MethodGet, p.Endpoint, http.NoBody)
 	if err != nil {
-		return nil, err
+		return nil, "", fmt.Errorf("unable to create request: %w", err)
+	}
+
+	for k, v := range p.Headers {
+		req.Header.Set(k, v)
+	}
+
+	if p.lastETag != "" {
+		req.Header.Set("If-None-Match", p.lastETag)
+	}
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, "", err
 	}
 
 	defer res.Body.Close()
 
+	if res.StatusCode == http.StatusNotModified && p.lastETag != "" {
+		return nil, "", nil
+	}
+
 	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("received non-ok response code: %d", res.StatusCode)
+		return nil, "", fmt.Errorf("received non-ok response code: %d", res.StatusCode)
+	}
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, "", err
 	}
 
-	return io.ReadAll(res.Body)
+	return data, res.Header.Get("ETag"), nil
 }
 
 // decodeConfiguration decodes and returns the dynamic configuration from the given data.
@@ -105,7 +105,7 @@ func TestProvider_fetchConfigurationData(t *testing.T) {
 			err := provider.Init()
 			require.NoError(t, err)
 
-			configData, err := provider.fetchConfigurationData()
+			configData, _, err := provider.fetchConfigurationData()
 			if test.expErr {
 				require.Error(t, err)
 				return
@@ -117,6 +117,71 @@ func TestProvider_fetchConfigurationData(t *testing.T) {
 	}
 }
 
+func TestProvider_fetchConfigurationData_headers(t *testing.T) {
+	handler := func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer token" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		rw.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(rw, "{}")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	provider := Provider{
+		Endpoint:     server.URL,
+		PollInterval: ptypes.Duration(1 * time.Second),
+		PollTimeout:  ptypes.Duration(1 * time.Second),
+		Headers:      map[string]string{"Authorization": "Bearer token"},
+	}
+
+	err := provider.Init()
+	require.NoError(t, err)
+
+	configData, _, err := provider.fetchConfigurationData()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("{}"), configData)
+}
+
+func TestProvider_fetchConfigurationData_etag(t *testing.T) {
+	handler := func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("If-None-Match") == `"v1"` {
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		rw.Header().Set("ETag", `"v1"`)
+		rw.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(rw, "{}")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	provider := Provider{
+		Endpoint:     server.URL,
+		PollInterval: ptypes.Duration(1 * time.Second),
+		PollTimeout:  ptypes.Duration(1 * time.Second),
+	}
+
+	err := provider.Init()
+	require.NoError(t, err)
+
+	configData, etag, err := provider.fetchConfigurationData()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("{}"), configData)
+	assert.Equal(t, `"v1"`, etag)
+
+	provider.lastETag = etag
+
+	configData, _, err = provider.fetchConfigurationData()
+	require.NoError(t, err)
+	assert.Nil(t, configData)
+}
+
 func TestProvider_decodeConfiguration(t *testing.T) {
 	tests := []struct {
 		desc       string
@@ -0,0 +1,307 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/cenkalti/backoff/v4"
+	"github.com/traefik/paerser/file"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/job"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+const providerName = "kafka"
+
+var _ provider.Provider = (*Provider)(nil)
+
+// Provider is a provider.Provider implementation that reads the dynamic configuration from a Kafka topic.
+// Each message of the topic holds, in JSON, the part of the configuration identified by the message key,
+// and replaces the previous one with the same key. A message without value removes the part.
+type Provider struct {
+	Brokers  []string         `description:"Addresses of the Kafka brokers." json:"brokers,omitempty" toml:"brokers,omitempty" yaml:"brokers,omitempty"`
+	Topic    string           `description:"Topic holding the configurations." json:"topic,omitempty" toml:"topic,omitempty" yaml:"topic,omitempty" export:"true"`
+	ClientID string           `description:"Client ID used to connect to the Kafka brokers." json:"clientID,omitempty" toml:"clientID,omitempty" yaml:"clientID,omitempty" export:"true"`
+	Version  string           `description:"Version of the Kafka brokers." json:"version,omitempty" toml:"version,omitempty" yaml:"version,omitempty" export:"true"`
+	Username string           `description:"Username for SASL/PLAIN authentication." json:"username,omitempty" toml:"username,omitempty" yaml:"username,omitempty"`
+	Password string           `description:"Password for SASL/PLAIN authentication." json:"password,omitempty" toml:"password,omitempty" yaml:"password,omitempty"`
+	TLS      *types.ClientTLS `description:"Enable TLS support." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+
+	config *sarama.Config
+}
+
+// SetDefaults sets the default values.
+func (p *Provider) SetDefaults() {
+	p.Topic = "traefik"
+	p.ClientID = "traefik"
+	p.Version = sarama.V1_0_0_0.String()
+}
+
+// Init the provider.
+func (p *Provider) Init() error {
+	if len(p.Brokers) == 0 {
+		return errors.New("at least one broker is required")
+	}
+
+	if p.Topic == "" {
+		return errors.New("non-empty topic is required")
+	}
+
+	version, err := sarama.ParseKafkaVersion(p.Version)
+	if err != nil {
+		return fmt.Errorf("invalid Kafka version: %w", err)
+	}
+
+	config := sarama.NewConfig()
+	config.ClientID = p.ClientID
+	config.Version = version
+	config.Consumer.Return.Errors = true
+
+	if p.Username != "" {
+		config.Net.SASL.Enable = true
+		config.Net.SASL.User = p.Username
+		config.Net.SASL.Password = p.Password
+	}
+
+	if p.TLS != nil {
+		config.Net.TLS.Enable = true
+		config.Net.TLS.Config, err = p.TLS.CreateTLSConfig(context.Background())
+		if err != nil {
+			return fmt.Errorf("unable to create TLS configuration: %w", err)
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		return fmt.Errorf("invalid Kafka configuration: %w", err)
+	}
+
+	p.config = config
+
+	return nil
+}
+
+// Provide allows the provider to provide configurations to traefik using the given configuration channel.
+func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
+	pool.GoCtx(func(routineCtx context.Context) {
+		ctxLog := log.With(routineCtx, log.Str(log.ProviderName, providerName))
+		logger := log.FromContext(ctxLog)
+
+		operation := func() error {
+			return p.consume(ctxLog, configurationChan)
+		}
+
+		notify := func(err error, time time.Duration) {
+			logger.Errorf("Provider connection error %+v, retrying in %s", err, time)
+		}
+		err := backoff.RetryNotify(safe.OperationWithRecover(operation), backoff.WithContext(job.NewBackOff(backoff.NewExponentialBackOff()), ctxLog), notify)
+		if err != nil {
+			logger.Errorf("Cannot connect to Kafka %+v", err)
+		}
+	})
+
+	return nil
+}
+
+// consume reads the whole topic from its oldest messages, sends the configuration once they are all read,
+// and then sends it again on each new message, until the context is done or the consumption fails.
+func (p *Provider) consume(ctx context.Context, configurationChan chan<- dynamic.Message) error {
+	client, err := sarama.NewClient(p.Brokers, p.config)
+	if err != nil {
+		return fmt.Errorf("cannot connect to the Kafka brokers: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	partitions, err := client.Partitions(p.Topic)
+	if err != nil {
+		return fmt.Errorf("cannot get the partitions of topic %s: %w", p.Topic, err)
+	}
+
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		return fmt.Errorf("cannot create consumer: %w", err)
+	}
+	defer func() { _ = consumer.Close() }()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	messages := make(chan *sarama.ConsumerMessage)
+	errs := make(chan error, len(partitions))
+
+	pending := make(replay)
+	for _, partition := range partitions {
+		// The newest offset is the one of the next message written to the partition.
+		highWaterMark, err := client.GetOffset(p.Topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			return fmt.Errorf("cannot get the offset of partition %d: %w", partition, err)
+		}
+		pending.wait(partition, highWaterMark)
+
+		partitionConsumer, err := consumer.ConsumePartition(p.Topic, partition, sarama.OffsetOldest)
+		if err != nil {
+			return fmt.Errorf("cannot consume partition %d: %w", partition, err)
+		}
+
+		go forward(ctx, partitionConsumer, messages, errs)
+	}
+
+	confs := make(configurations)
+
+	if pending.done() {
+		p.send(ctx, configurationChan, confs)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case err := <-errs:
+			return err
+
+		case msg := <-messages:
+			replayed := pending.done()
+
+			changed := confs.update(ctx, string(msg.Key), msg.Value)
+			pending.read(msg.Partition, msg.Offset)
+
+			// The configuration is sent once all the messages written before the start are read,
+			// so that the partial configurations read while replaying the topic are not applied.
+			if pending.done() && (changed || !replayed) {
+				p.send(ctx, configurationChan, confs)
+			}
+		}
+	}
+}
+
+// send sends the merged configurations to the given channel.
+func (p *Provider) send(ctx context.Context, configurationChan chan<- dynamic.Message, confs configurations) {
+	select {
+	case <-ctx.Done():
+	case configurationChan <- dynamic.Message{ProviderName: providerName, Configuration: confs.merge(ctx)}:
+	}
+}
+
+// forward forwards the messages, and the first error, of the given partition consumer until the context is done.
+func forward(ctx context.Context, partitionConsumer sarama.PartitionConsumer, messages chan<- *sarama.ConsumerMessage, errs chan<- error) {
+	defer func() { _ = partitionConsumer.Close() }()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case msg, ok := <-partitionConsumer.Messages():
+			if !ok {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case messages <- msg:
+			}
+
+		case err, ok := <-partitionConsumer.Errors():
+			if !ok {
+				return
+			}
+
+			errs <- fmt.Errorf("cannot consume partition %d: %w", err.Partition, err.Err)
+			return
+		}
+	}
+}
+
+// replay tracks, by partition, the offset of the next message written to the partition before the start of the consumption.
+type replay map[int32]int64
+
+// wait records that the messages of the given partition before the given offset have to be read.
+func (r replay) wait(partition int32, highWaterMark int64) {
+	if highWaterMark > 0 {
+		r[partition] = highWaterMark
+	}
+}
+
+// read records that the message of the given partition at the given offset is read.
+func (r replay) read(partition int32, offset int64) {
+	if highWaterMark, ok := r[partition]; ok && offset+1 >= highWaterMark {
+		delete(r, partition)
+	}
+}
+
+// done reports whether all the messages written before the start of the consumption are read.
+func (r replay) done() bool {
+	return len(r) == 0
+}
+
+// configurations holds the configurations read from the topic, by message key.
+type configurations map[string]*dynamic.Configuration
+
+// update applies the message with the given key and value, and reports whether the configurations changed.
+// A message without value removes the configuration with the same key,
+// and an invalid message is skipped, the configuration with the same key being kept.
+func (c configurations) update(ctx context.Context, key string, value []byte) bool {
+	logger := log.FromContext(ctx)
+
+	if key == "" {
+		logger.Error("Skipping message without key")
+		return false
+	}
+
+	if value == nil {
+		_, ok := c[key]
+		delete(c, key)
+		return ok
+	}
+
+	configuration, err := decodeConfiguration(value)
+	if err != nil {
+		logger.Errorf("Cannot decode the configuration with key %q: %v", key, err)
+		return false
+	}
+
+	c[key] = configuration
+
+	return true
+}
+
+// merge merges the configurations into a single one.
+// The objects defined differently by several configurations are skipped.
+func (c configurations) merge(ctx context.Context) *dynamic.Configuration {
+	return provider.Merge(ctx, c)
+}
+
+// decodeConfiguration decodes and returns the dynamic configuration from the given JSON data.
+func decodeConfiguration(data []byte) (*dynamic.Configuration, error) {
+	configuration := &dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers:           make(map[string]*dynamic.Router),
+			Middlewares:       make(map[string]*dynamic.Middleware),
+			Services:          make(map[string]*dynamic.Service),
+			ServersTransports: make(map[string]*dynamic.ServersTransport),
+		},
+		TCP: &dynamic.TCPConfiguration{
+			Routers:     make(map[string]*dynamic.TCPRouter),
+			Middlewares: make(map[string]*dynamic.TCPMiddleware),
+			Services:    make(map[string]*dynamic.TCPService),
+		},
+		UDP: &dynamic.UDPConfiguration{
+			Routers:  make(map[string]*dynamic.UDPRouter),
+			Services: make(map[string]*dynamic.UDPService),
+		},
+	}
+
+	err := file.DecodeContent(string(data), ".json", configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	return configuration, nil
+}
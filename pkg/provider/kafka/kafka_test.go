@@ -0,0 +1,79 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestProvider_Init(t *testing.T) {
+	provider := Provider{}
+	provider.SetDefaults()
+
+	assert.Error(t, provider.Init())
+
+	provider.Brokers = []string{"127.0.0.1:9092"}
+	provider.Version = "foo"
+	assert.Error(t, provider.Init())
+
+	provider.Version = "2.1.0"
+	assert.NoError(t, provider.Init())
+}
+
+func Test_configurations_update(t *testing.T) {
+	ctx := context.Background()
+	confs := make(configurations)
+
+	changed := confs.update(ctx, "team-a/default", []byte(`{"http":{"routers":{"foo":{"rule":"Host(`+"`foo.com`"+`)","service":"foo"}}}}`))
+	assert.True(t, changed)
+
+	changed = confs.update(ctx, "team-b/default", []byte(`{"http":{"services":{"foo":{"loadBalancer":{"servers":[{"url":"http://127.0.0.1"}]}}}}}`))
+	assert.True(t, changed)
+
+	// Skipped messages.
+	assert.False(t, confs.update(ctx, "", []byte(`{}`)))
+	assert.False(t, confs.update(ctx, "team-b/default", []byte(`{"http":`)))
+	assert.False(t, confs.update(ctx, "team-c/default", nil))
+
+	merged := confs.merge(ctx)
+	assert.Equal(t, &dynamic.Router{Rule: "Host(`foo.com`)", Service: "foo"}, merged.HTTP.Routers["foo"])
+	require.NotNil(t, merged.HTTP.Services["foo"])
+	assert.Equal(t, []dynamic.Server{{URL: "http://127.0.0.1", Scheme: "http"}}, merged.HTTP.Services["foo"].LoadBalancer.Servers)
+
+	// Tombstone.
+	changed = confs.update(ctx, "team-a/default", nil)
+	assert.True(t, changed)
+
+	merged = confs.merge(ctx)
+	assert.Empty(t, merged.HTTP.Routers)
+	assert.Len(t, merged.HTTP.Services, 1)
+}
+
+func Test_configurations_merge_conflict(t *testing.T) {
+	ctx := context.Background()
+	confs := make(configurations)
+
+	confs.update(ctx, "team-a/default", []byte(`{"http":{"routers":{"foo":{"rule":"Host(`+"`foo.com`"+`)","service":"foo"}}}}`))
+	confs.update(ctx, "team-b/default", []byte(`{"http":{"routers":{"foo":{"rule":"Host(`+"`bar.com`"+`)","service":"foo"}}}}`))
+
+	assert.Empty(t, confs.merge(ctx).HTTP.Routers)
+}
+
+func Test_replay(t *testing.T) {
+	pending := make(replay)
+	pending.wait(0, 2)
+	pending.wait(1, 0)
+	assert.False(t, pending.done())
+
+	pending.read(0, 0)
+	assert.False(t, pending.done())
+
+	pending.read(0, 1)
+	assert.True(t, pending.done())
+
+	pending.read(1, 5)
+	assert.True(t, pending.done())
+}
@@ -1,6 +1,7 @@
 package crd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -24,7 +25,10 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 )
 
-const resyncPeriod = 10 * time.Minute
+const (
+	resyncPeriod   = 10 * time.Minute
+	defaultTimeout = 5 * time.Second
+)
 
 // Client is a client for the Provider master.
 // WatchAll starts the watch of the Provider resources and updates the stores.
@@ -46,6 +50,8 @@ type Client interface {
 	GetService(namespace, name string) (*corev1.Service, bool, error)
 	GetSecret(namespace, name string) (*corev1.Secret, bool, error)
 	GetEndpoints(namespace, name string) (*corev1.Endpoints, bool, error)
+
+	UpdateIngressRouteAnnotation(ingressRoute *v1alpha1.IngressRoute, key, value string) error
 }
 
 // TODO: add tests for the clientWrapper (and its methods) itself.
@@ -390,6 +396,36 @@ func (c *clientWrapper) GetSecret(namespace, name string) (*corev1.Secret, bool,
 	return secret, exist, err
 }
 
+// UpdateIngressRouteAnnotation sets the given annotation on an IngressRoute, if it does not already have the given value.
+func (c *clientWrapper) UpdateIngressRouteAnnotation(src *v1alpha1.IngressRoute, key, value string) error {
+	if !c.isWatchedNamespace(src.Namespace) {
+		return fmt.Errorf("failed to update ingressRoute %s/%s: namespace is not within watched namespaces", src.Namespace, src.Name)
+	}
+
+	if src.Annotations[key] == value {
+		return nil
+	}
+
+	ingressRoute := src.DeepCopy()
+	if ingressRoute.Annotations == nil {
+		ingressRoute.Annotations = make(map[string]string)
+	}
+	ingressRoute.Annotations[key] = value
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	_, err := c.csCrd.TraefikV1alpha1().IngressRoutes(ingressRoute.Namespace).Update(ctx, ingressRoute, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update ingressRoute %s/%s: %w", src.Namespace, src.Name, err)
+	}
+
+	log.WithoutContext().WithField("namespace", src.Namespace).WithField("ingress", src.Name).
+		Infof("Updated ingressRoute annotation %s", key)
+
+	return nil
+}
+
 // lookupNamespace returns the lookup namespace key for the given namespace.
 // When listening on all namespaces, it returns the client-go identifier ("")
 // for all-namespaces. Otherwise, it returns the given namespace.
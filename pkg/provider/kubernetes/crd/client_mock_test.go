@@ -41,10 +41,13 @@ type clientMock struct {
 	serversTransport []*v1alpha1.ServersTransport
 
 	watchChan chan interface{}
+
+	// annotations records the annotations updated through UpdateIngressRouteAnnotation, keyed by IngressRoute namespace/name.
+	annotations map[string]map[string]string
 }
 
 func newClientMock(paths ...string) clientMock {
-	var c clientMock
+	c := clientMock{annotations: make(map[string]map[string]string)}
 
 	for _, path := range paths {
 		yamlContent, err := os.ReadFile(filepath.FromSlash("./fixtures/" + path))
@@ -187,3 +190,13 @@ func (c clientMock) GetSecret(namespace, name string) (*corev1.Secret, bool, err
 func (c clientMock) WatchAll(namespaces []string, stopCh <-chan struct{}) (<-chan interface{}, error) {
 	return c.watchChan, nil
 }
+
+func (c clientMock) UpdateIngressRouteAnnotation(ingressRoute *v1alpha1.IngressRoute, key, value string) error {
+	id := ingressRoute.Namespace + "/" + ingressRoute.Name
+	if c.annotations[id] == nil {
+		c.annotations[id] = make(map[string]string)
+	}
+	c.annotations[id][key] = value
+
+	return nil
+}
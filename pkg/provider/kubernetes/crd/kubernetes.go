@@ -21,6 +21,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/provider"
 	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd/traefik/v1alpha1"
+	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/k8s"
 	"github.com/traefik/traefik/v2/pkg/safe"
 	"github.com/traefik/traefik/v2/pkg/tls"
 	corev1 "k8s.io/api/core/v1"
@@ -31,6 +32,7 @@ import (
 
 const (
 	annotationKubernetesIngressClass = "kubernetes.io/ingress.class"
+	annotationExternalDNSTarget      = "external-dns.alpha.kubernetes.io/target"
 	traefikDefaultIngressClass       = "traefik"
 )
 
@@ -41,16 +43,27 @@ const (
 
 // Provider holds configurations of the provider.
 type Provider struct {
-	Endpoint                  string          `description:"Kubernetes server endpoint (required for external cluster client)." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty"`
-	Token                     string          `description:"Kubernetes bearer token (not needed for in-cluster client)." json:"token,omitempty" toml:"token,omitempty" yaml:"token,omitempty"`
-	CertAuthFilePath          string          `description:"Kubernetes certificate authority file path (not needed for in-cluster client)." json:"certAuthFilePath,omitempty" toml:"certAuthFilePath,omitempty" yaml:"certAuthFilePath,omitempty"`
-	Namespaces                []string        `description:"Kubernetes namespaces." json:"namespaces,omitempty" toml:"namespaces,omitempty" yaml:"namespaces,omitempty" export:"true"`
-	AllowCrossNamespace       bool            `description:"Allow cross namespace resource reference." json:"allowCrossNamespace,omitempty" toml:"allowCrossNamespace,omitempty" yaml:"allowCrossNamespace,omitempty" export:"true"`
-	AllowExternalNameServices bool            `description:"Allow ExternalName services." json:"allowExternalNameServices,omitempty" toml:"allowExternalNameServices,omitempty" yaml:"allowExternalNameServices,omitempty" export:"true"`
-	LabelSelector             string          `description:"Kubernetes label selector to use." json:"labelSelector,omitempty" toml:"labelSelector,omitempty" yaml:"labelSelector,omitempty" export:"true"`
-	IngressClass              string          `description:"Value of kubernetes.io/ingress.class annotation to watch for." json:"ingressClass,omitempty" toml:"ingressClass,omitempty" yaml:"ingressClass,omitempty" export:"true"`
-	ThrottleDuration          ptypes.Duration `description:"Ingress refresh throttle duration" json:"throttleDuration,omitempty" toml:"throttleDuration,omitempty" yaml:"throttleDuration,omitempty" export:"true"`
+	Endpoint                  string                    `description:"Kubernetes server endpoint (required for external cluster client)." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	Token                     string                    `description:"Kubernetes bearer token (not needed for in-cluster client)." json:"token,omitempty" toml:"token,omitempty" yaml:"token,omitempty"`
+	CertAuthFilePath          string                    `description:"Kubernetes certificate authority file path (not needed for in-cluster client)." json:"certAuthFilePath,omitempty" toml:"certAuthFilePath,omitempty" yaml:"certAuthFilePath,omitempty"`
+	Namespaces                []string                  `description:"Kubernetes namespaces." json:"namespaces,omitempty" toml:"namespaces,omitempty" yaml:"namespaces,omitempty" export:"true"`
+	AllowCrossNamespace       bool                      `description:"Allow cross namespace resource reference." json:"allowCrossNamespace,omitempty" toml:"allowCrossNamespace,omitempty" yaml:"allowCrossNamespace,omitempty" export:"true"`
+	AllowExternalNameServices bool                      `description:"Allow ExternalName services." json:"allowExternalNameServices,omitempty" toml:"allowExternalNameServices,omitempty" yaml:"allowExternalNameServices,omitempty" export:"true"`
+	LabelSelector             string                    `description:"Kubernetes label selector to use." json:"labelSelector,omitempty" toml:"labelSelector,omitempty" yaml:"labelSelector,omitempty" export:"true"`
+	IngressClass              string                    `description:"Value of kubernetes.io/ingress.class annotation to watch for." json:"ingressClass,omitempty" toml:"ingressClass,omitempty" yaml:"ingressClass,omitempty" export:"true"`
+	ThrottleDuration          ptypes.Duration           `description:"Ingress refresh throttle duration" json:"throttleDuration,omitempty" toml:"throttleDuration,omitempty" yaml:"throttleDuration,omitempty" export:"true"`
+	ExternalDNS               *ExternalDNS              `description:"Publish the IngressRoutes endpoint for ExternalDNS." json:"externalDNS,omitempty" toml:"externalDNS,omitempty" yaml:"externalDNS,omitempty" export:"true"`
+	ExternalCertificates      *k8s.ExternalCertificates `description:"Consume only from their secrets the certificates of the IngressRoutes managed by an external issuer, such as cert-manager." json:"externalCertificates,omitempty" toml:"externalCertificates,omitempty" yaml:"externalCertificates,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	lastConfiguration         safe.Safe
+	statusUpdater             *k8s.StatusUpdater
+}
+
+// ExternalDNS holds the endpoint published on the IngressRoutes,
+// through the external-dns.alpha.kubernetes.io/target annotation, for ExternalDNS consumption.
+type ExternalDNS struct {
+	IP               string `description:"IP published for the IngressRoutes." json:"ip,omitempty" toml:"ip,omitempty" yaml:"ip,omitempty"`
+	Hostname         string `description:"Hostname published for the IngressRoutes." json:"hostname,omitempty" toml:"hostname,omitempty" yaml:"hostname,omitempty"`
+	PublishedService string `description:"Published Kubernetes Service to copy the load balancer IPs/hostnames from." json:"publishedService,omitempty" toml:"publishedService,omitempty" yaml:"publishedService,omitempty"`
 }
 
 func (p *Provider) newK8sClient(ctx context.Context) (*clientWrapper, error) {
@@ -110,6 +123,11 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 		logger.Warn("ExternalName service loading is enabled, please ensure that this is expected (see AllowExternalNameServices option)")
 	}
 
+	p.statusUpdater = k8s.NewStatusUpdater()
+	pool.GoCtx(func(ctxPool context.Context) {
+		p.statusUpdater.Run(log.With(ctxPool, log.Str(log.ProviderName, providerName)))
+	})
+
 	pool.GoCtx(func(ctxPool context.Context) {
 		operation := func() error {
 			eventsChan, err := k8sClient.WatchAll(p.Namespaces, ctxPool.Done())
@@ -233,6 +251,12 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			continue
 		}
 
+		circuitBreaker, err := createCircuitBreakerMiddleware(middleware.Spec.CircuitBreaker)
+		if err != nil {
+			log.FromContext(ctxMid).Errorf("Error while reading circuit breaker middleware: %v", err)
+			continue
+		}
+
 		retry, err := createRetryMiddleware(middleware.Spec.Retry)
 		if err != nil {
 			log.FromContext(ctxMid).Errorf("Error while reading retry middleware: %v", err)
@@ -247,6 +271,7 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			ReplacePathRegex:  middleware.Spec.ReplacePathRegex,
 			Chain:             createChainMiddleware(ctxMid, middleware.Namespace, middleware.Spec.Chain),
 			IPWhiteList:       middleware.Spec.IPWhiteList,
+			IPAllowList:       middleware.Spec.IPAllowList,
 			Headers:           middleware.Spec.Headers,
 			Errors:            errorPage,
 			RateLimit:         rateLimit,
@@ -257,7 +282,7 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			ForwardAuth:       forwardAuth,
 			InFlightReq:       middleware.Spec.InFlightReq,
 			Buffering:         middleware.Spec.Buffering,
-			CircuitBreaker:    middleware.Spec.CircuitBreaker,
+			CircuitBreaker:    circuitBreaker,
 			Compress:          middleware.Spec.Compress,
 			PassTLSClientCert: middleware.Spec.PassTLSClientCert,
 			Retry:             retry,
@@ -271,6 +296,7 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 
 		conf.TCP.Middlewares[id] = &dynamic.TCPMiddleware{
 			IPWhiteList: middlewareTCP.Spec.IPWhiteList,
+			IPAllowList: middlewareTCP.Spec.IPAllowList,
 		}
 	}
 
@@ -346,6 +372,7 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			Certificates:        certs,
 			MaxIdleConnsPerHost: serversTransport.Spec.MaxIdleConnsPerHost,
 			ForwardingTimeouts:  forwardingTimeout,
+			HTTP1Fallback:       serversTransport.Spec.HTTP1Fallback,
 		}
 	}
 
@@ -425,12 +452,49 @@ func createRateLimitMiddleware(rateLimit *v1alpha1.RateLimit) (*dynamic.RateLimi
 	return rl, nil
 }
 
+func createCircuitBreakerMiddleware(circuitBreaker *v1alpha1.CircuitBreaker) (*dynamic.CircuitBreaker, error) {
+	if circuitBreaker == nil {
+		return nil, nil
+	}
+
+	cb := &dynamic.CircuitBreaker{Expression: circuitBreaker.Expression}
+	cb.SetDefaults()
+
+	if circuitBreaker.CheckPeriod != nil {
+		if err := cb.CheckPeriod.Set(circuitBreaker.CheckPeriod.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	if circuitBreaker.FallbackDuration != nil {
+		if err := cb.FallbackDuration.Set(circuitBreaker.FallbackDuration.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	if circuitBreaker.RecoveryDuration != nil {
+		if err := cb.RecoveryDuration.Set(circuitBreaker.RecoveryDuration.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	if circuitBreaker.ResponseCode != 0 {
+		cb.ResponseCode = circuitBreaker.ResponseCode
+	}
+
+	return cb, nil
+}
+
 func createRetryMiddleware(retry *v1alpha1.Retry) (*dynamic.Retry, error) {
 	if retry == nil {
 		return nil, nil
 	}
 
-	r := &dynamic.Retry{Attempts: retry.Attempts}
+	r := &dynamic.Retry{
+		Attempts:       retry.Attempts,
+		Status:         retry.Status,
+		IdempotentOnly: retry.IdempotentOnly,
+	}
 
 	err := r.InitialInterval.Set(retry.InitialInterval.String())
 	if err != nil {
@@ -802,6 +866,50 @@ func shouldProcessIngress(ingressClass, ingressClassAnnotation string) bool {
 		(len(ingressClass) == 0 && ingressClassAnnotation == traefikDefaultIngressClass)
 }
 
+// externalDNSTargets returns the IPs/hostnames published on the IngressRoutes for ExternalDNS.
+func (p *Provider) externalDNSTargets(client Client) ([]string, error) {
+	if len(p.ExternalDNS.PublishedService) == 0 {
+		if len(p.ExternalDNS.IP) == 0 && len(p.ExternalDNS.Hostname) == 0 {
+			return nil, errors.New("publishedService or ip or hostname must be defined")
+		}
+
+		var targets []string
+		for _, target := range []string{p.ExternalDNS.IP, p.ExternalDNS.Hostname} {
+			if len(target) > 0 {
+				targets = append(targets, target)
+			}
+		}
+
+		return targets, nil
+	}
+
+	serviceInfo := strings.Split(p.ExternalDNS.PublishedService, "/")
+	if len(serviceInfo) != 2 {
+		return nil, fmt.Errorf("invalid publishedService format (expected 'namespace/service' format): %s", p.ExternalDNS.PublishedService)
+	}
+
+	service, exists, err := client.GetService(serviceInfo[0], serviceInfo[1])
+	if err != nil {
+		return nil, fmt.Errorf("cannot get service %s, received error: %w", p.ExternalDNS.PublishedService, err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("missing service: %s", p.ExternalDNS.PublishedService)
+	}
+
+	var targets []string
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if len(ingress.IP) > 0 {
+			targets = append(targets, ingress.IP)
+		}
+		if len(ingress.Hostname) > 0 {
+			targets = append(targets, ingress.Hostname)
+		}
+	}
+
+	return targets, nil
+}
+
 func getTLS(k8sClient Client, secretName, namespace string) (*tls.CertAndStores, error) {
 	secret, exists, err := k8sClient.GetSecret(namespace, secretName)
 	if err != nil {
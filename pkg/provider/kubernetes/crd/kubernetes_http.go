@@ -31,6 +31,15 @@ func (p *Provider) loadIngressRouteConfiguration(ctx context.Context, client Cli
 		ServersTransports: map[string]*dynamic.ServersTransport{},
 	}
 
+	var externalDNSTargets string
+	if p.ExternalDNS != nil {
+		targets, err := p.externalDNSTargets(client)
+		if err != nil {
+			log.FromContext(ctx).Errorf("Cannot resolve the endpoint published for ExternalDNS: %v", err)
+		}
+		externalDNSTargets = strings.Join(targets, ",")
+	}
+
 	for _, ingressRoute := range client.GetIngressRoutes() {
 		ctxRt := log.With(ctx, log.Str("ingress", ingressRoute.Name), log.Str("namespace", ingressRoute.Namespace))
 		logger := log.FromContext(ctxRt)
@@ -40,6 +49,13 @@ func (p *Provider) loadIngressRouteConfiguration(ctx context.Context, client Cli
 			continue
 		}
 
+		if len(externalDNSTargets) > 0 {
+			ingressRoute := ingressRoute
+			p.statusUpdater.Update("ingressRoute "+ingressRoute.Namespace+"/"+ingressRoute.Name, func() error {
+				return client.UpdateIngressRouteAnnotation(ingressRoute, annotationExternalDNSTarget, externalDNSTargets)
+			})
+		}
+
 		err := getTLSHTTP(ctx, ingressRoute, client, tlsConfigs)
 		if err != nil {
 			logger.Errorf("Error configuring TLS: %v", err)
@@ -118,6 +134,15 @@ func (p *Provider) loadIngressRouteConfiguration(ctx context.Context, client Cli
 					Domains:      ingressRoute.Spec.TLS.Domains,
 				}
 
+				if issuer, ok := p.ExternalCertificates.Issuer(ingressRoute.Annotations); ok {
+					if tlsConf.CertResolver != "" {
+						logger.Warnf("The certificate resolver %q is ignored, the certificates of the IngressRoute are issued externally (%s)", tlsConf.CertResolver, issuer)
+					}
+
+					tlsConf.CertResolver = ""
+					tlsConf.Domains = nil
+				}
+
 				if ingressRoute.Spec.TLS.Options != nil && len(ingressRoute.Spec.TLS.Options.Name) > 0 {
 					tlsOptionsName := ingressRoute.Spec.TLS.Options.Name
 					// Is a Kubernetes CRD reference, (i.e. not a cross-provider reference)
@@ -107,6 +107,15 @@ func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client
 					Domains:      ingressRouteTCP.Spec.TLS.Domains,
 				}
 
+				if issuer, ok := p.ExternalCertificates.Issuer(ingressRouteTCP.Annotations); ok {
+					if ingressRouteTCP.Spec.TLS.CertResolver != "" {
+						logger.Warnf("The certificate resolver %q is ignored, the certificates of the IngressRouteTCP are issued externally (%s)", ingressRouteTCP.Spec.TLS.CertResolver, issuer)
+					}
+
+					conf.Routers[serviceName].TLS.CertResolver = ""
+					conf.Routers[serviceName].TLS.Domains = nil
+				}
+
 				if ingressRouteTCP.Spec.TLS.Options == nil || len(ingressRouteTCP.Spec.TLS.Options.Name) == 0 {
 					continue
 				}
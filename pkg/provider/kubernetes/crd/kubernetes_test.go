@@ -16,6 +16,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd/traefik/v1alpha1"
 	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/k8s"
 	"github.com/traefik/traefik/v2/pkg/tls"
+	traefiktypes "github.com/traefik/traefik/v2/pkg/types"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -126,7 +127,7 @@ func TestLoadIngressRouteTCPs(t *testing.T) {
 							},
 						},
 						"foo-ipwhitelist": {
-							IPWhiteList: &dynamic.TCPIPWhiteList{
+							IPAllowList: &dynamic.TCPIPAllowList{
 								SourceRange: []string{"127.0.0.1/32"},
 							},
 						},
@@ -3450,6 +3451,7 @@ func TestLoadIngressRoutes(t *testing.T) {
 								{CertFile: "TESTCERT3", KeyFile: "TESTKEY3"},
 							},
 							MaxIdleConnsPerHost: 42,
+							HTTP1Fallback:       true,
 							ForwardingTimeouts: &dynamic.ForwardingTimeouts{
 								DialTimeout:           types.Duration(42 * time.Second),
 								ResponseHeaderTimeout: types.Duration(42 * time.Second),
@@ -5190,3 +5192,148 @@ func TestExternalNameService(t *testing.T) {
 		})
 	}
 }
+
+func TestExternalDNS(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		externalDNS *ExternalDNS
+		expected    map[string]map[string]string
+	}{
+		{
+			desc:     "Disabled",
+			expected: map[string]map[string]string{},
+		},
+		{
+			desc:        "IP and hostname",
+			externalDNS: &ExternalDNS{IP: "10.0.0.1", Hostname: "traefik.example.com"},
+			expected: map[string]map[string]string{
+				"default/test.route": {annotationExternalDNSTarget: "10.0.0.1,traefik.example.com"},
+			},
+		},
+		{
+			desc:        "Published service",
+			externalDNS: &ExternalDNS{PublishedService: "traefik/traefik"},
+			expected: map[string]map[string]string{
+				"default/test.route": {annotationExternalDNSTarget: "1.2.3.4,lb.example.com"},
+			},
+		},
+		{
+			desc:        "Missing published service",
+			externalDNS: &ExternalDNS{PublishedService: "traefik/missing"},
+			expected:    map[string]map[string]string{},
+		},
+		{
+			desc:        "Invalid published service",
+			externalDNS: &ExternalDNS{PublishedService: "traefik"},
+			expected:    map[string]map[string]string{},
+		},
+		{
+			desc:        "Empty configuration",
+			externalDNS: &ExternalDNS{},
+			expected:    map[string]map[string]string{},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := Provider{ExternalDNS: test.externalDNS, statusUpdater: k8s.NewStatusUpdater()}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+
+			go p.statusUpdater.Run(ctx)
+
+			clientMock := newClientMock("with_external_dns.yml")
+			p.loadConfigurationFromCRD(context.Background(), clientMock)
+
+			// The annotations are updated asynchronously, in the order of the updates.
+			done := make(chan struct{})
+			p.statusUpdater.Update("done", func() error {
+				close(done)
+				return nil
+			})
+			<-done
+
+			assert.Equal(t, test.expected, clientMock.annotations)
+		})
+	}
+}
+
+func TestExternalCertificates(t *testing.T) {
+	testCases := []struct {
+		desc                 string
+		externalCertificates *k8s.ExternalCertificates
+		expectedHTTP         map[string]*dynamic.RouterTLSConfig
+		expectedTCP          map[string]*dynamic.RouterTCPTLSConfig
+	}{
+		{
+			desc: "Disabled",
+			expectedHTTP: map[string]*dynamic.RouterTLSConfig{
+				"default-test-route-6b204d94623b3df4370c": {
+					CertResolver: "foobar",
+					Domains:      []traefiktypes.Domain{{Main: "foo.com"}},
+				},
+				"default-test2-route-6b204d94623b3df4370c": {CertResolver: "foobar"},
+			},
+			expectedTCP: map[string]*dynamic.RouterTCPTLSConfig{
+				"default-test.route-fdd3e9338e47a45efefc": {CertResolver: "foobar"},
+			},
+		},
+		{
+			desc: "Default annotations",
+			externalCertificates: &k8s.ExternalCertificates{
+				Annotations: []string{"cert-manager.io/issuer", "cert-manager.io/cluster-issuer"},
+			},
+			expectedHTTP: map[string]*dynamic.RouterTLSConfig{
+				"default-test-route-6b204d94623b3df4370c":  {},
+				"default-test2-route-6b204d94623b3df4370c": {CertResolver: "foobar"},
+			},
+			expectedTCP: map[string]*dynamic.RouterTCPTLSConfig{
+				"default-test.route-fdd3e9338e47a45efefc": {},
+			},
+		},
+		{
+			desc: "Other annotation",
+			externalCertificates: &k8s.ExternalCertificates{
+				Annotations: []string{"cert-manager.io/issuer"},
+			},
+			expectedHTTP: map[string]*dynamic.RouterTLSConfig{
+				"default-test-route-6b204d94623b3df4370c": {
+					CertResolver: "foobar",
+					Domains:      []traefiktypes.Domain{{Main: "foo.com"}},
+				},
+				"default-test2-route-6b204d94623b3df4370c": {CertResolver: "foobar"},
+			},
+			expectedTCP: map[string]*dynamic.RouterTCPTLSConfig{
+				"default-test.route-fdd3e9338e47a45efefc": {},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := Provider{ExternalCertificates: test.externalCertificates}
+
+			clientMock := newClientMock("services.yml", "tcp/services.yml", "with_external_certificates.yml")
+			conf := p.loadConfigurationFromCRD(context.Background(), clientMock)
+
+			routersTLS := make(map[string]*dynamic.RouterTLSConfig)
+			for name, router := range conf.HTTP.Routers {
+				routersTLS[name] = router.TLS
+			}
+			assert.Equal(t, test.expectedHTTP, routersTLS)
+
+			tcpRoutersTLS := make(map[string]*dynamic.RouterTCPTLSConfig)
+			for name, router := range conf.TCP.Routers {
+				tcpRoutersTLS[name] = router.TLS
+			}
+			assert.Equal(t, test.expectedTCP, tcpRoutersTLS)
+		})
+	}
+}
@@ -30,6 +30,7 @@ type MiddlewareSpec struct {
 	ReplacePathRegex  *dynamic.ReplacePathRegex      `json:"replacePathRegex,omitempty"`
 	Chain             *Chain                         `json:"chain,omitempty"`
 	IPWhiteList       *dynamic.IPWhiteList           `json:"ipWhiteList,omitempty"`
+	IPAllowList       *dynamic.IPAllowList           `json:"ipAllowList,omitempty"`
 	Headers           *dynamic.Headers               `json:"headers,omitempty"`
 	Errors            *ErrorPage                     `json:"errors,omitempty"`
 	RateLimit         *RateLimit                     `json:"rateLimit,omitempty"`
@@ -40,7 +41,7 @@ type MiddlewareSpec struct {
 	ForwardAuth       *ForwardAuth                   `json:"forwardAuth,omitempty"`
 	InFlightReq       *dynamic.InFlightReq           `json:"inFlightReq,omitempty"`
 	Buffering         *dynamic.Buffering             `json:"buffering,omitempty"`
-	CircuitBreaker    *dynamic.CircuitBreaker        `json:"circuitBreaker,omitempty"`
+	CircuitBreaker    *CircuitBreaker                `json:"circuitBreaker,omitempty"`
 	Compress          *dynamic.Compress              `json:"compress,omitempty"`
 	PassTLSClientCert *dynamic.PassTLSClientCert     `json:"passTLSClientCert,omitempty"`
 	Retry             *Retry                         `json:"retry,omitempty"`
@@ -126,8 +127,21 @@ type RateLimit struct {
 
 // +k8s:deepcopy-gen=true
 
+// CircuitBreaker holds the circuit breaker configuration.
+type CircuitBreaker struct {
+	Expression       string              `json:"expression,omitempty"`
+	CheckPeriod      *intstr.IntOrString `json:"checkPeriod,omitempty"`
+	FallbackDuration *intstr.IntOrString `json:"fallbackDuration,omitempty"`
+	RecoveryDuration *intstr.IntOrString `json:"recoveryDuration,omitempty"`
+	ResponseCode     int                 `json:"responseCode,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // Retry holds the retry configuration.
 type Retry struct {
 	Attempts        int                `json:"attempts,omitempty"`
 	InitialInterval intstr.IntOrString `json:"initialInterval,omitempty"`
+	Status          []string           `json:"status,omitempty"`
+	IdempotentOnly  bool               `json:"idempotentOnly,omitempty"`
 }
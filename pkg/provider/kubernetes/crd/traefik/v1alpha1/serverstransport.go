@@ -35,6 +35,8 @@ type ServersTransportSpec struct {
 	ForwardingTimeouts *ForwardingTimeouts `json:"forwardingTimeouts,omitempty"`
 	// Disable HTTP/2 for connections with backend servers.
 	DisableHTTP2 bool `json:"disableHTTP2,omitempty"`
+	// Retry once over HTTP/1.1 the requests failing with an HTTP/2 protocol error.
+	HTTP1Fallback bool `json:"http1Fallback,omitempty"`
 	// URI used to match against SAN URI during the peer certificate verification.
 	PeerCertURI string `json:"peerCertURI,omitempty"`
 }
@@ -73,6 +73,37 @@ func (in *Chain) DeepCopy() *Chain {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CircuitBreaker) DeepCopyInto(out *CircuitBreaker) {
+	*out = *in
+	if in.CheckPeriod != nil {
+		in, out := &in.CheckPeriod, &out.CheckPeriod
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.FallbackDuration != nil {
+		in, out := &in.FallbackDuration, &out.FallbackDuration
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.RecoveryDuration != nil {
+		in, out := &in.RecoveryDuration, &out.RecoveryDuration
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CircuitBreaker.
+func (in *CircuitBreaker) DeepCopy() *CircuitBreaker {
+	if in == nil {
+		return nil
+	}
+	out := new(CircuitBreaker)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClientAuth) DeepCopyInto(out *ClientAuth) {
 	*out = *in
@@ -651,6 +682,11 @@ func (in *MiddlewareSpec) DeepCopyInto(out *MiddlewareSpec) {
 		*out = new(dynamic.IPWhiteList)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.IPAllowList != nil {
+		in, out := &in.IPAllowList, &out.IPAllowList
+		*out = new(dynamic.IPAllowList)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Headers != nil {
 		in, out := &in.Headers, &out.Headers
 		*out = new(dynamic.Headers)
@@ -703,8 +739,8 @@ func (in *MiddlewareSpec) DeepCopyInto(out *MiddlewareSpec) {
 	}
 	if in.CircuitBreaker != nil {
 		in, out := &in.CircuitBreaker, &out.CircuitBreaker
-		*out = new(dynamic.CircuitBreaker)
-		**out = **in
+		*out = new(CircuitBreaker)
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Compress != nil {
 		in, out := &in.Compress, &out.Compress
@@ -719,7 +755,7 @@ func (in *MiddlewareSpec) DeepCopyInto(out *MiddlewareSpec) {
 	if in.Retry != nil {
 		in, out := &in.Retry, &out.Retry
 		*out = new(Retry)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.ContentType != nil {
 		in, out := &in.ContentType, &out.ContentType
@@ -814,6 +850,11 @@ func (in *MiddlewareTCPSpec) DeepCopyInto(out *MiddlewareTCPSpec) {
 		*out = new(dynamic.TCPIPWhiteList)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.IPAllowList != nil {
+		in, out := &in.IPAllowList, &out.IPAllowList
+		*out = new(dynamic.TCPIPAllowList)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -924,6 +965,11 @@ func (in *RateLimit) DeepCopy() *RateLimit {
 func (in *Retry) DeepCopyInto(out *Retry) {
 	*out = *in
 	out.InitialInterval = in.InitialInterval
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
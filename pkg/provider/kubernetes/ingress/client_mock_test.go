@@ -28,10 +28,13 @@ type clientMock struct {
 	apiIngressStatusError error
 
 	watchChan chan interface{}
+
+	// statuses records the statuses updated through UpdateIngressStatus, keyed by Ingress namespace/name.
+	statuses map[string][]corev1.LoadBalancerIngress
 }
 
 func newClientMock(serverVersion string, paths ...string) clientMock {
-	c := clientMock{}
+	c := clientMock{statuses: make(map[string][]corev1.LoadBalancerIngress)}
 
 	c.serverVersion = version.Must(version.NewVersion(serverVersion))
 
@@ -132,6 +135,12 @@ func (c clientMock) WatchAll(namespaces []string, stopCh <-chan struct{}) (<-cha
 	return c.watchChan, nil
 }
 
-func (c clientMock) UpdateIngressStatus(_ *networkingv1.Ingress, _ []corev1.LoadBalancerIngress) error {
-	return c.apiIngressStatusError
+func (c clientMock) UpdateIngressStatus(ing *networkingv1.Ingress, ingStatus []corev1.LoadBalancerIngress) error {
+	if c.apiIngressStatusError != nil {
+		return c.apiIngressStatusError
+	}
+
+	c.statuses[ing.Namespace+"/"+ing.Name] = ingStatus
+
+	return nil
 }
@@ -8,6 +8,7 @@ import (
 	"math"
 	"net"
 	"os"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -20,6 +21,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/job"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/provider"
+	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/k8s"
 	"github.com/traefik/traefik/v2/pkg/safe"
 	"github.com/traefik/traefik/v2/pkg/tls"
 	corev1 "k8s.io/api/core/v1"
@@ -29,6 +31,7 @@ import (
 
 const (
 	annotationKubernetesIngressClass     = "kubernetes.io/ingress.class"
+	annotationKubernetesRewriteTarget    = annotationsPrefix + "rewrite-target"
 	traefikDefaultIngressClass           = "traefik"
 	traefikDefaultIngressClassController = "traefik.io/ingress-controller"
 	defaultPathMatcher                   = "PathPrefix"
@@ -36,17 +39,19 @@ const (
 
 // Provider holds configurations of the provider.
 type Provider struct {
-	Endpoint                  string           `description:"Kubernetes server endpoint (required for external cluster client)." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty"`
-	Token                     string           `description:"Kubernetes bearer token (not needed for in-cluster client)." json:"token,omitempty" toml:"token,omitempty" yaml:"token,omitempty"`
-	CertAuthFilePath          string           `description:"Kubernetes certificate authority file path (not needed for in-cluster client)." json:"certAuthFilePath,omitempty" toml:"certAuthFilePath,omitempty" yaml:"certAuthFilePath,omitempty"`
-	Namespaces                []string         `description:"Kubernetes namespaces." json:"namespaces,omitempty" toml:"namespaces,omitempty" yaml:"namespaces,omitempty" export:"true"`
-	LabelSelector             string           `description:"Kubernetes Ingress label selector to use." json:"labelSelector,omitempty" toml:"labelSelector,omitempty" yaml:"labelSelector,omitempty" export:"true"`
-	IngressClass              string           `description:"Value of kubernetes.io/ingress.class annotation or IngressClass name to watch for." json:"ingressClass,omitempty" toml:"ingressClass,omitempty" yaml:"ingressClass,omitempty" export:"true"`
-	IngressEndpoint           *EndpointIngress `description:"Kubernetes Ingress Endpoint." json:"ingressEndpoint,omitempty" toml:"ingressEndpoint,omitempty" yaml:"ingressEndpoint,omitempty" export:"true"`
-	ThrottleDuration          ptypes.Duration  `description:"Ingress refresh throttle duration" json:"throttleDuration,omitempty" toml:"throttleDuration,omitempty" yaml:"throttleDuration,omitempty" export:"true"`
-	AllowEmptyServices        bool             `description:"Allow creation of services without endpoints." json:"allowEmptyServices,omitempty" toml:"allowEmptyServices,omitempty" yaml:"allowEmptyServices,omitempty" export:"true"`
-	AllowExternalNameServices bool             `description:"Allow ExternalName services." json:"allowExternalNameServices,omitempty" toml:"allowExternalNameServices,omitempty" yaml:"allowExternalNameServices,omitempty" export:"true"`
+	Endpoint                  string                    `description:"Kubernetes server endpoint (required for external cluster client)." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	Token                     string                    `description:"Kubernetes bearer token (not needed for in-cluster client)." json:"token,omitempty" toml:"token,omitempty" yaml:"token,omitempty"`
+	CertAuthFilePath          string                    `description:"Kubernetes certificate authority file path (not needed for in-cluster client)." json:"certAuthFilePath,omitempty" toml:"certAuthFilePath,omitempty" yaml:"certAuthFilePath,omitempty"`
+	Namespaces                []string                  `description:"Kubernetes namespaces." json:"namespaces,omitempty" toml:"namespaces,omitempty" yaml:"namespaces,omitempty" export:"true"`
+	LabelSelector             string                    `description:"Kubernetes Ingress label selector to use." json:"labelSelector,omitempty" toml:"labelSelector,omitempty" yaml:"labelSelector,omitempty" export:"true"`
+	IngressClass              string                    `description:"Value of kubernetes.io/ingress.class annotation or IngressClass name to watch for." json:"ingressClass,omitempty" toml:"ingressClass,omitempty" yaml:"ingressClass,omitempty" export:"true"`
+	IngressEndpoint           *EndpointIngress          `description:"Kubernetes Ingress Endpoint." json:"ingressEndpoint,omitempty" toml:"ingressEndpoint,omitempty" yaml:"ingressEndpoint,omitempty" export:"true"`
+	ThrottleDuration          ptypes.Duration           `description:"Ingress refresh throttle duration" json:"throttleDuration,omitempty" toml:"throttleDuration,omitempty" yaml:"throttleDuration,omitempty" export:"true"`
+	AllowEmptyServices        bool                      `description:"Allow creation of services without endpoints." json:"allowEmptyServices,omitempty" toml:"allowEmptyServices,omitempty" yaml:"allowEmptyServices,omitempty" export:"true"`
+	AllowExternalNameServices bool                      `description:"Allow ExternalName services." json:"allowExternalNameServices,omitempty" toml:"allowExternalNameServices,omitempty" yaml:"allowExternalNameServices,omitempty" export:"true"`
+	ExternalCertificates      *k8s.ExternalCertificates `description:"Consume only from their secrets the certificates of the Ingresses managed by an external issuer, such as cert-manager." json:"externalCertificates,omitempty" toml:"externalCertificates,omitempty" yaml:"externalCertificates,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	lastConfiguration         safe.Safe
+	statusUpdater             *k8s.StatusUpdater
 }
 
 // EndpointIngress holds the endpoint information for the Kubernetes provider.
@@ -112,6 +117,11 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 		logger.Warn("ExternalName service loading is enabled, please ensure that this is expected (see AllowExternalNameServices option)")
 	}
 
+	p.statusUpdater = k8s.NewStatusUpdater()
+	pool.GoCtx(func(ctxPool context.Context) {
+		p.statusUpdater.Run(log.With(ctxPool, log.Str(log.ProviderName, "kubernetes")))
+	})
+
 	pool.GoCtx(func(ctxPool context.Context) {
 		operation := func() error {
 			eventsChan, err := k8sClient.WatchAll(p.Namespaces, ctxPool.Done())
@@ -186,7 +196,8 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 			Middlewares: map[string]*dynamic.Middleware{},
 			Services:    map[string]*dynamic.Service{},
 		},
-		TCP: &dynamic.TCPConfiguration{},
+		TCP:        &dynamic.TCPConfiguration{},
+		Provenance: &dynamic.Provenance{},
 	}
 
 	serverVersion := client.GetServerVersion()
@@ -222,6 +233,17 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 			continue
 		}
 
+		if issuer, ok := p.ExternalCertificates.Issuer(ingress.Annotations); ok {
+			ignoreCertResolver(ctx, rtConfig, issuer)
+		}
+
+		source := dynamic.Source{
+			Kind:      "Ingress",
+			Namespace: ingress.Namespace,
+			Name:      ingress.Name,
+			UID:       string(ingress.UID),
+		}
+
 		err = getCertificates(ctx, ingress, client, certConfigs)
 		if err != nil {
 			log.FromContext(ctx).Errorf("Error configuring TLS: %v", err)
@@ -264,14 +286,20 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 
 			conf.HTTP.Routers["default-router"] = rt
 			conf.HTTP.Services["default-backend"] = service
+			conf.Provenance.AddRouter("default-router", source)
+			conf.Provenance.AddService("default-backend", source)
 		}
 
 		routers := map[string][]*dynamic.Router{}
 
+		if p.IngressEndpoint != nil && len(ingress.Spec.Rules) > 0 {
+			ingress := ingress
+			p.statusUpdater.Update("ingress "+ingress.Namespace+"/"+ingress.Name, func() error {
+				return p.updateIngressStatus(ingress, client)
+			})
+		}
+
 		for _, rule := range ingress.Spec.Rules {
-			if err := p.updateIngressStatus(ingress, client); err != nil {
-				log.FromContext(ctx).Errorf("Error while updating ingress status: %v", err)
-			}
 
 			if rule.HTTP == nil {
 				continue
@@ -303,15 +331,28 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 
 				serviceName := provider.Normalize(ingress.Namespace + "-" + pa.Backend.Service.Name + "-" + portString)
 				conf.HTTP.Services[serviceName] = service
+				conf.Provenance.AddService(serviceName, source)
 
 				routerKey := strings.TrimPrefix(provider.Normalize(ingress.Name+"-"+ingress.Namespace+"-"+rule.Host+pa.Path), "-")
-				routers[routerKey] = append(routers[routerKey], loadRouter(rule, pa, rtConfig, serviceName))
+				rt := loadRouter(rule, pa, rtConfig, serviceName)
+
+				if rewriteTarget, ok := ingress.Annotations[annotationKubernetesRewriteTarget]; ok {
+					middlewareName := routerKey + "-rewrite-target"
+					conf.HTTP.Middlewares[middlewareName] = buildRewriteTargetMiddleware(pa.Path, rewriteTarget)
+					conf.Provenance.AddMiddleware(middlewareName, source)
+
+					// The path is rewritten after the other middlewares, so that they see the requested path.
+					rt.Middlewares = append(append([]string{}, rt.Middlewares...), middlewareName)
+				}
+
+				routers[routerKey] = append(routers[routerKey], rt)
 			}
 		}
 
 		for routerKey, conflictingRouters := range routers {
 			if len(conflictingRouters) == 1 {
 				conf.HTTP.Routers[routerKey] = conflictingRouters[0]
+				conf.Provenance.AddRouter(routerKey, source)
 				continue
 			}
 
@@ -325,6 +366,7 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 				}
 
 				conf.HTTP.Routers[key] = router
+				conf.Provenance.AddRouter(key, source)
 			}
 		}
 	}
@@ -339,6 +381,21 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 	return conf
 }
 
+// ignoreCertResolver removes the certificate resolver of the routers of an Ingress whose certificates are issued externally,
+// so that they are only consumed from the secrets of the Ingress.
+func ignoreCertResolver(ctx context.Context, rtConfig *RouterConfig, issuer string) {
+	if rtConfig == nil || rtConfig.Router == nil || rtConfig.Router.TLS == nil {
+		return
+	}
+
+	if rtConfig.Router.TLS.CertResolver != "" {
+		log.FromContext(ctx).Warnf("The certificate resolver %q is ignored, the certificates of the Ingress are issued externally (%s)", rtConfig.Router.TLS.CertResolver, issuer)
+	}
+
+	rtConfig.Router.TLS.CertResolver = ""
+	rtConfig.Router.TLS.Domains = nil
+}
+
 func (p *Provider) updateIngressStatus(ing *networkingv1.Ingress, k8sClient Client) error {
 	// Only process if an EndpointIngress has been configured.
 	if p.IngressEndpoint == nil {
@@ -396,10 +453,21 @@ func (p *Provider) shouldProcessIngress(ingress *networkingv1.Ingress, ingressCl
 
 func buildHostRule(host string) string {
 	if strings.HasPrefix(host, "*.") {
-		return "HostRegexp(`" + strings.Replace(host, "*.", "{subdomain:[a-zA-Z0-9-]+}.", 1) + "`)"
+		return "HostRegexp(" + quoteRuleValue(strings.Replace(host, "*.", "{subdomain:[a-zA-Z0-9-]+}.", 1)) + ")"
+	}
+
+	return "Host(" + quoteRuleValue(host) + ")"
+}
+
+// quoteRuleValue quotes a value for a rule matcher, so that it cannot break the rule syntax.
+// The value is quoted with backticks like in the handwritten rules,
+// unless it contains a backtick, as it cannot be escaped in such a raw string.
+func quoteRuleValue(value string) string {
+	if strings.Contains(value, "`") {
+		return strconv.Quote(value)
 	}
 
-	return "Host(`" + host + "`)"
+	return "`" + value + "`"
 }
 
 func getCertificates(ctx context.Context, ingress *networkingv1.Ingress, k8sClient Client, tlsConfigs map[string]*tls.CertAndStores) error {
@@ -628,7 +696,7 @@ func loadRouter(rule networkingv1.IngressRule, pa networkingv1.HTTPIngressPath,
 			matcher = "Path"
 		}
 
-		rules = append(rules, fmt.Sprintf("%s(`%s`)", matcher, pa.Path))
+		rules = append(rules, matcher+"("+quoteRuleValue(pa.Path)+")")
 	}
 
 	rt := &dynamic.Router{
@@ -649,6 +717,17 @@ func loadRouter(rule networkingv1.IngressRule, pa networkingv1.HTTPIngressPath,
 	return rt
 }
 
+// buildRewriteTargetMiddleware returns the middleware replacing the path of the ingress with the rewrite target,
+// while keeping the rest of the request path.
+func buildRewriteTargetMiddleware(path, rewriteTarget string) *dynamic.Middleware {
+	return &dynamic.Middleware{
+		ReplacePathRegex: &dynamic.ReplacePathRegex{
+			Regex:       "^" + regexp.QuoteMeta(strings.TrimSuffix(path, "/")) + "(?:/|$)(.*)",
+			Replacement: strings.TrimSuffix(rewriteTarget, "/") + "/$1",
+		},
+	}
+}
+
 func throttleEvents(ctx context.Context, throttleDuration time.Duration, pool *safe.Pool, eventsChan <-chan interface{}) chan interface{} {
 	if throttleDuration == 0 {
 		return nil
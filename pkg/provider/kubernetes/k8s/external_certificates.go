@@ -0,0 +1,28 @@
+package k8s
+
+// ExternalCertificates identifies, through their annotations, the resources whose certificates are issued by an external issuer, such as cert-manager.
+// The certificates of these resources are only consumed from their secrets, and the certificate resolvers of their routers are ignored.
+type ExternalCertificates struct {
+	Annotations []string `description:"Annotations marking the resources whose certificates are issued externally." json:"annotations,omitempty" toml:"annotations,omitempty" yaml:"annotations,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (e *ExternalCertificates) SetDefaults() {
+	e.Annotations = []string{"cert-manager.io/issuer", "cert-manager.io/cluster-issuer"}
+}
+
+// Issuer returns the first annotation, among the configured ones, set on the resource, e.g. cert-manager.io/issuer=letsencrypt,
+// and reports whether the certificates of the resource are issued externally.
+func (e *ExternalCertificates) Issuer(annotations map[string]string) (string, bool) {
+	if e == nil {
+		return "", false
+	}
+
+	for _, annotation := range e.Annotations {
+		if value, ok := annotations[annotation]; ok {
+			return annotation + "=" + value, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,49 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalCertificates_Issuer(t *testing.T) {
+	testCases := []struct {
+		desc                 string
+		externalCertificates *ExternalCertificates
+		annotations          map[string]string
+		expectedIssuer       string
+		expectedOK           bool
+	}{
+		{
+			desc:        "Disabled",
+			annotations: map[string]string{"cert-manager.io/issuer": "letsencrypt"},
+		},
+		{
+			desc:                 "No annotations",
+			externalCertificates: &ExternalCertificates{Annotations: []string{"cert-manager.io/issuer"}},
+		},
+		{
+			desc:                 "Issuer annotation",
+			externalCertificates: &ExternalCertificates{Annotations: []string{"cert-manager.io/issuer", "cert-manager.io/cluster-issuer"}},
+			annotations:          map[string]string{"cert-manager.io/cluster-issuer": "letsencrypt"},
+			expectedIssuer:       "cert-manager.io/cluster-issuer=letsencrypt",
+			expectedOK:           true,
+		},
+		{
+			desc:                 "Other annotation",
+			externalCertificates: &ExternalCertificates{Annotations: []string{"cert-manager.io/issuer"}},
+			annotations:          map[string]string{"cert-manager.io/cluster-issuer": "letsencrypt"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			issuer, ok := test.externalCertificates.Issuer(test.annotations)
+			assert.Equal(t, test.expectedIssuer, issuer)
+			assert.Equal(t, test.expectedOK, ok)
+		})
+	}
+}
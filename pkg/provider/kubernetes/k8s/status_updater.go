@@ -0,0 +1,75 @@
+package k8s
+
+import (
+	"context"
+	"sync"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// StatusUpdater applies the updates of the Kubernetes resources made by a provider, such as their status or annotations,
+// asynchronously, so that building the configuration does not wait for the API server.
+// Only the last pending update of a resource is applied.
+type StatusUpdater struct {
+	mu      sync.Mutex
+	pending map[string]func() error
+	keys    []string
+
+	ready chan struct{}
+}
+
+// NewStatusUpdater creates a new StatusUpdater.
+func NewStatusUpdater() *StatusUpdater {
+	return &StatusUpdater{
+		pending: make(map[string]func() error),
+		ready:   make(chan struct{}, 1),
+	}
+}
+
+// Update schedules the update of the resource identified by the given key, e.g. "ingress default/foo",
+// replacing the pending update of the resource if any.
+func (u *StatusUpdater) Update(key string, update func() error) {
+	u.mu.Lock()
+	if _, exists := u.pending[key]; !exists {
+		u.keys = append(u.keys, key)
+	}
+	u.pending[key] = update
+	u.mu.Unlock()
+
+	select {
+	case u.ready <- struct{}{}:
+	default:
+	}
+}
+
+// Run applies the updates, in the order they have been scheduled, until the context is done.
+func (u *StatusUpdater) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-u.ready:
+			u.applyPending(ctx)
+		}
+	}
+}
+
+func (u *StatusUpdater) applyPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		u.mu.Lock()
+		if len(u.keys) == 0 {
+			u.mu.Unlock()
+			return
+		}
+
+		key := u.keys[0]
+		u.keys = u.keys[1:]
+		update := u.pending[key]
+		delete(u.pending, key)
+		u.mu.Unlock()
+
+		if err := update(); err != nil {
+			log.FromContext(ctx).Errorf("Error while updating %s: %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusUpdater(t *testing.T) {
+	updater := NewStatusUpdater()
+
+	var mu sync.Mutex
+	var applied []string
+	update := func(name string) func() error {
+		return func() error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			applied = append(applied, name)
+			return nil
+		}
+	}
+
+	// Only the last pending update of a resource is applied.
+	updater.Update("ingress default/foo", update("foo-1"))
+	updater.Update("ingress default/bar", func() error { return errors.New("error") })
+	updater.Update("ingress default/baz", update("baz"))
+	updater.Update("ingress default/foo", update("foo-2"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	go updater.Run(ctx)
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(applied) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	assert.Equal(t, []string{"foo-2", "baz"}, applied)
+	mu.Unlock()
+
+	// The updates scheduled while running are applied too.
+	updater.Update("ingress default/foo", update("foo-3"))
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(applied) == 3 && applied[2] == "foo-3"
+	}, time.Second, 10*time.Millisecond)
+}
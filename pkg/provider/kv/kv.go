@@ -118,7 +118,10 @@ func (p *Provider) watchKv(ctx context.Context, configurationChan chan<- dynamic
 
 				configuration, errC := p.buildConfiguration()
 				if errC != nil {
-					return errC
+					// The watch is still alive, so it is kept to get the next changes,
+					// which may fix the configuration.
+					log.FromContext(ctx).Errorf("Cannot build the configuration: %v", errC)
+					continue
 				}
 
 				if configuration != nil {
@@ -159,22 +162,27 @@ func (p *Provider) buildConfiguration() (*dynamic.Configuration, error) {
 }
 
 func (p *Provider) createKVClient(ctx context.Context) (store.Store, error) {
+	return NewClient(ctx, p.storeType, p.Endpoints, p.Username, p.Password, p.TLS)
+}
+
+// NewClient creates a client of the KV store backend.
+func NewClient(ctx context.Context, storeType store.Backend, endpoints []string, username, password string, clientTLS *types.ClientTLS) (store.Store, error) {
 	storeConfig := &store.Config{
 		ConnectionTimeout: 3 * time.Second,
 		Bucket:            "traefik",
-		Username:          p.Username,
-		Password:          p.Password,
+		Username:          username,
+		Password:          password,
 	}
 
-	if p.TLS != nil {
+	if clientTLS != nil {
 		var err error
-		storeConfig.TLS, err = p.TLS.CreateTLSConfig(ctx)
+		storeConfig.TLS, err = clientTLS.CreateTLSConfig(ctx)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	switch p.storeType {
+	switch storeType {
 	case store.CONSUL:
 		consul.Register()
 	case store.ETCDV3:
@@ -185,7 +193,7 @@ func (p *Provider) createKVClient(ctx context.Context) (store.Store, error) {
 		redis.Register()
 	}
 
-	kvStore, err := valkeyrie.NewStore(p.storeType, p.Endpoints, storeConfig)
+	kvStore, err := valkeyrie.NewStore(storeType, endpoints, storeConfig)
 	if err != nil {
 		return nil, err
 	}
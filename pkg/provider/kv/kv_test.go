@@ -3,6 +3,7 @@ package kv
 import (
 	"context"
 	"errors"
+	"net/http"
 	"testing"
 	"time"
 
@@ -391,7 +392,11 @@ func Test_buildConfiguration(t *testing.T) {
 				},
 				"Middleware04": {
 					CircuitBreaker: &dynamic.CircuitBreaker{
-						Expression: "foobar",
+						Expression:       "foobar",
+						CheckPeriod:      ptypes.Duration(100 * time.Millisecond),
+						FallbackDuration: ptypes.Duration(10 * time.Second),
+						RecoveryDuration: ptypes.Duration(10 * time.Second),
+						ResponseCode:     http.StatusServiceUnavailable,
 					},
 				},
 				"Middleware05": {
@@ -950,6 +955,46 @@ func TestKvWatchTree(t *testing.T) {
 	}
 }
 
+func TestKvWatchTree_buildError(t *testing.T) {
+	returnedChans := make(chan chan []*store.KVPair, 10)
+	provider := Provider{
+		kvClient: &Mock{
+			Error: KvError{
+				List: errors.New("OOPS"),
+			},
+			WatchTreeMethod: func() <-chan []*store.KVPair {
+				c := make(chan []*store.KVPair, 10)
+				returnedChans <- c
+				return c
+			},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	configChan := make(chan dynamic.Message)
+	go func() {
+		err := provider.watchKv(ctx, configChan)
+		assert.NoError(t, err)
+	}()
+
+	select {
+	case c := <-returnedChans:
+		c <- []*store.KVPair{}
+	case <-time.After(1 * time.Second):
+		t.Fatalf("Failed to create a new WatchTree chan")
+	}
+
+	select {
+	case <-configChan:
+		t.Fatalf("configChan should be empty")
+	case <-returnedChans:
+		t.Fatalf("the WatchTree chan should be kept")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
 func mapToPairs(in map[string]string) []*store.KVPair {
 	var out []*store.KVPair
 	for k, v := range in {
@@ -0,0 +1,78 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/abronan/valkeyrie/store"
+	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
+)
+
+var _ traefiktls.SessionTicketKeysStore = (*SessionTicketKeysStore)(nil)
+
+// SessionTicketKeysStore shares the TLS session ticket keys of a cluster of Traefik instances through a KV store.
+type SessionTicketKeysStore struct {
+	client store.Store
+	key    string
+}
+
+// NewSessionTicketKeysStore initializes a new SessionTicketKeysStore connected to the KV store.
+func NewSessionTicketKeysStore(ctx context.Context, config *traefiktls.SessionTicketsKVStorage) (*SessionTicketKeysStore, error) {
+	var backend store.Backend
+	switch config.Backend {
+	case "consul":
+		backend = store.CONSUL
+	case "etcd":
+		backend = store.ETCDV3
+	case "zookeeper":
+		backend = store.ZK
+	case "redis":
+		backend = store.REDIS
+	default:
+		return nil, fmt.Errorf("unsupported KV store backend: %q", config.Backend)
+	}
+
+	if len(config.Endpoints) == 0 {
+		return nil, errors.New("no KV store endpoint")
+	}
+
+	client, err := NewClient(ctx, backend, config.Endpoints, config.Username, config.Password, config.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the KV store: %w", err)
+	}
+
+	return &SessionTicketKeysStore{client: client, key: path.Join(config.RootKey, "sessionTicketKeys")}, nil
+}
+
+// Load returns the shared keys, and the index of their last modification.
+func (s *SessionTicketKeysStore) Load() ([]byte, uint64, error) {
+	pair, err := s.client.Get(s.key, &store.ReadOptions{Consistent: true})
+	if errors.Is(err, store.ErrKeyNotFound) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return pair.Value, pair.LastIndex, nil
+}
+
+// Save saves the keys if they were not modified since the given index.
+func (s *SessionTicketKeysStore) Save(data []byte, version uint64) (bool, error) {
+	var previous *store.KVPair
+	if version > 0 {
+		previous = &store.KVPair{Key: s.key, LastIndex: version}
+	}
+
+	_, _, err := s.client.AtomicPut(s.key, data, previous, nil)
+	if errors.Is(err, store.ErrKeyModified) || errors.Is(err, store.ErrKeyExists) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
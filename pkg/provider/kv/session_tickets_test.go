@@ -0,0 +1,86 @@
+package kv
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/abronan/valkeyrie/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// atomicKVClientMock is an in-memory KV store supporting the atomic puts.
+type atomicKVClientMock struct {
+	store.Store
+
+	mu    sync.Mutex
+	pairs map[string]*store.KVPair
+	index uint64
+}
+
+func (m *atomicKVClientMock) Get(key string, _ *store.ReadOptions) (*store.KVPair, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pair, ok := m.pairs[key]
+	if !ok {
+		return nil, store.ErrKeyNotFound
+	}
+
+	return pair, nil
+}
+
+func (m *atomicKVClientMock) AtomicPut(key string, value []byte, previous *store.KVPair, _ *store.WriteOptions) (bool, *store.KVPair, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.pairs[key]
+	if previous == nil && ok {
+		return false, nil, store.ErrKeyExists
+	}
+	if previous != nil && (!ok || current.LastIndex != previous.LastIndex) {
+		return false, nil, store.ErrKeyModified
+	}
+
+	m.index++
+	m.pairs[key] = &store.KVPair{Key: key, Value: value, LastIndex: m.index}
+
+	return true, m.pairs[key], nil
+}
+
+func TestSessionTicketKeysStore(t *testing.T) {
+	client := &atomicKVClientMock{pairs: make(map[string]*store.KVPair)}
+	s := &SessionTicketKeysStore{client: client, key: "traefik/tls/sessionTicketKeys"}
+
+	data, version, err := s.Load()
+	require.NoError(t, err)
+	assert.Nil(t, data)
+	assert.Equal(t, uint64(0), version)
+
+	saved, err := s.Save([]byte("foo"), 0)
+	require.NoError(t, err)
+	assert.True(t, saved)
+
+	// The keys were saved by another instance meanwhile.
+	saved, err = s.Save([]byte("bar"), 0)
+	require.NoError(t, err)
+	assert.False(t, saved)
+
+	data, version, err = s.Load()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("foo"), data)
+
+	saved, err = s.Save([]byte("bar"), version)
+	require.NoError(t, err)
+	assert.True(t, saved)
+
+	// The keys were modified since the given version.
+	saved, err = s.Save([]byte("baz"), version)
+	require.NoError(t, err)
+	assert.False(t, saved)
+
+	data, _, err = s.Load()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("bar"), data)
+	assert.Equal(t, "traefik/tls/sessionTicketKeys", client.pairs["traefik/tls/sessionTicketKeys"].Key)
+}
@@ -10,7 +10,7 @@ type storeWrapper struct {
 }
 
 func (s *storeWrapper) Put(key string, value []byte, options *store.WriteOptions) error {
-	log.WithoutContext().Debugf("Put: %s", key, string(value))
+	log.WithoutContext().Debugf("Put: %s", key)
 
 	if s.Store == nil {
 		return nil
@@ -91,7 +91,7 @@ func (s *storeWrapper) DeleteTree(directory string) error {
 }
 
 func (s *storeWrapper) AtomicPut(key string, value []byte, previous *store.KVPair, options *store.WriteOptions) (bool, *store.KVPair, error) {
-	log.WithoutContext().Debugf("AtomicPut: %s", key, string(value), previous)
+	log.WithoutContext().Debugf("AtomicPut: %s", key)
 
 	if s.Store == nil {
 		return true, nil, nil
@@ -100,7 +100,7 @@ func (s *storeWrapper) AtomicPut(key string, value []byte, previous *store.KVPai
 }
 
 func (s *storeWrapper) AtomicDelete(key string, previous *store.KVPair) (bool, error) {
-	log.WithoutContext().Debugf("AtomicDelete: %s", key, previous)
+	log.WithoutContext().Debugf("AtomicDelete: %s", key)
 
 	if s.Store == nil {
 		return true, nil
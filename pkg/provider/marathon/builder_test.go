@@ -192,6 +192,17 @@ func ipAddresses(addresses ...string) func(*marathon.Task) {
 	}
 }
 
+func healthCheckResults(alive ...bool) func(*marathon.Task) {
+	return func(t *marathon.Task) {
+		for _, a := range alive {
+			t.HealthCheckResults = append(t.HealthCheckResults, &marathon.HealthCheckResult{
+				TaskID: t.ID,
+				Alive:  a,
+			})
+		}
+	}
+}
+
 func startedAt(timestamp string) func(*marathon.Task) {
 	return func(t *marathon.Task) {
 		t.StartedAt = timestamp
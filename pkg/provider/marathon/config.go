@@ -265,6 +265,14 @@ func (p *Provider) taskFilter(ctx context.Context, task marathon.Task, applicati
 		return false
 	}
 
+	// The tasks without health check results yet are kept, as Marathon reports them only once probed.
+	for _, result := range task.HealthCheckResults {
+		if result != nil && !result.Alive {
+			log.FromContext(ctx).Infof("Filtering unhealthy task %s from application %s", task.ID, application.ID)
+			return false
+		}
+	}
+
 	return true
 }
 
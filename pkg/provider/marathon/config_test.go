@@ -1908,6 +1908,48 @@ func TestApplicationFilterEnabled(t *testing.T) {
 	}
 }
 
+func TestTaskFilter(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		task     marathon.Task
+		expected bool
+	}{
+		{
+			desc:     "running task",
+			task:     task(),
+			expected: true,
+		},
+		{
+			desc:     "staging task",
+			task:     task(taskState(taskStateStaging)),
+			expected: false,
+		},
+		{
+			desc:     "healthy task",
+			task:     task(healthCheckResults(true, true)),
+			expected: true,
+		},
+		{
+			desc:     "task failing one of its health checks",
+			task:     task(healthCheckResults(true, false)),
+			expected: false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			provider := &Provider{}
+
+			app := application(appID("/app"), withTasks(test.task))
+
+			assert.Equal(t, test.expected, provider.taskFilter(context.Background(), test.task, app))
+		})
+	}
+}
+
 func TestGetServer(t *testing.T) {
 	type expected struct {
 		server dynamic.Server
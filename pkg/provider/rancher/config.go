@@ -179,6 +179,11 @@ func (p *Provider) keepService(ctx context.Context, service rancherData) bool {
 		}
 	}
 
+	if p.EnableGlobalServiceFilter && strings.EqualFold(service.Labels[globalLabel], "true") {
+		logger.Debugf("Filtering global service %s", service.Name)
+		return false
+	}
+
 	return true
 }
 
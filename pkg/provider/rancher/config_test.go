@@ -1148,3 +1148,50 @@ func Test_buildConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func Test_keepService_global(t *testing.T) {
+	testCases := []struct {
+		desc                      string
+		enableGlobalServiceFilter bool
+		labels                    map[string]string
+		expected                  bool
+	}{
+		{
+			desc:     "global service, filter disabled",
+			labels:   map[string]string{"io.rancher.scheduler.global": "true"},
+			expected: true,
+		},
+		{
+			desc:                      "global service, filter enabled",
+			enableGlobalServiceFilter: true,
+			labels:                    map[string]string{"io.rancher.scheduler.global": "true"},
+			expected:                  false,
+		},
+		{
+			desc:                      "non-global service, filter enabled",
+			enableGlobalServiceFilter: true,
+			labels:                    map[string]string{},
+			expected:                  true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := Provider{
+				ExposedByDefault:          true,
+				EnableGlobalServiceFilter: test.enableGlobalServiceFilter,
+			}
+
+			service := rancherData{Name: "Test", Labels: test.labels}
+
+			var err error
+			service.ExtraConf, err = p.getConfiguration(service)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, p.keepService(context.Background(), service))
+		})
+	}
+}
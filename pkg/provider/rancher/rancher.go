@@ -26,6 +26,9 @@ const (
 	updatingHealthy = "updating-healthy"
 )
 
+// globalLabel is the Rancher scheduler label of the global services, which run a container on each host.
+const globalLabel = "io.rancher.scheduler.global"
+
 // States.
 const (
 	active          = "active"
@@ -45,6 +48,7 @@ type Provider struct {
 	DefaultRule               string `description:"Default rule." json:"defaultRule,omitempty" toml:"defaultRule,omitempty" yaml:"defaultRule,omitempty"`
 	ExposedByDefault          bool   `description:"Expose containers by default." json:"exposedByDefault,omitempty" toml:"exposedByDefault,omitempty" yaml:"exposedByDefault,omitempty" export:"true"`
 	EnableServiceHealthFilter bool   `description:"Filter services with unhealthy states and inactive states." json:"enableServiceHealthFilter,omitempty" toml:"enableServiceHealthFilter,omitempty" yaml:"enableServiceHealthFilter,omitempty" export:"true"`
+	EnableGlobalServiceFilter bool   `description:"Filter global services, which run a container on each host." json:"enableGlobalServiceFilter,omitempty" toml:"enableGlobalServiceFilter,omitempty" yaml:"enableGlobalServiceFilter,omitempty" export:"true"`
 	RefreshSeconds            int    `description:"Defines the polling interval in seconds." json:"refreshSeconds,omitempty" toml:"refreshSeconds,omitempty" yaml:"refreshSeconds,omitempty" export:"true"`
 	IntervalPoll              bool   `description:"Poll the Rancher metadata service every 'rancher.refreshseconds' (less accurate)." json:"intervalPoll,omitempty" toml:"intervalPoll,omitempty" yaml:"intervalPoll,omitempty" export:"true"`
 	Prefix                    string `description:"Prefix used for accessing the Rancher metadata service." json:"prefix,omitempty" toml:"prefix,omitempty" yaml:"prefix,omitempty"`
@@ -197,6 +197,13 @@ func (i *Provider) entryPointModels(cfg *dynamic.Configuration) {
 				CertResolver: ep.HTTP.TLS.CertResolver,
 				Domains:      ep.HTTP.TLS.Domains,
 			}
+
+			for _, sniCertResolver := range ep.HTTP.TLS.SNICertResolvers {
+				m.SNICertResolvers = append(m.SNICertResolvers, dynamic.SNICertResolver{
+					SNI:          sniCertResolver.SNI,
+					CertResolver: sniCertResolver.CertResolver,
+				})
+			}
 		}
 
 		cfg.HTTP.Models[name] = m
@@ -224,17 +231,6 @@ func (i *Provider) apiConfiguration(cfg *dynamic.Configuration) {
 				Rule:        "PathPrefix(`/`)",
 				Middlewares: []string{"dashboard_redirect@internal", "dashboard_stripprefix@internal"},
 			}
-
-			cfg.HTTP.Middlewares["dashboard_redirect"] = &dynamic.Middleware{
-				RedirectRegex: &dynamic.RedirectRegex{
-					Regex:       `^(http:\/\/(\[[\w:.]+\]|[\w\._-]+)(:\d+)?)\/$`,
-					Replacement: "${1}/dashboard/",
-					Permanent:   true,
-				},
-			}
-			cfg.HTTP.Middlewares["dashboard_stripprefix"] = &dynamic.Middleware{
-				StripPrefix: &dynamic.StripPrefix{Prefixes: []string{"/dashboard/", "/dashboard"}},
-			}
 		}
 
 		if i.staticCfg.API.Debug {
@@ -251,6 +247,19 @@ func (i *Provider) apiConfiguration(cfg *dynamic.Configuration) {
 
 	if i.staticCfg.API.Dashboard {
 		cfg.HTTP.Services["dashboard"] = &dynamic.Service{}
+
+		// The middlewares are declared in secure mode too,
+		// so that they can be used by the routers to the dashboard service defined in the dynamic configuration.
+		cfg.HTTP.Middlewares["dashboard_redirect"] = &dynamic.Middleware{
+			RedirectRegex: &dynamic.RedirectRegex{
+				Regex:       `^(https?:\/\/(\[[\w:.]+\]|[\w\._-]+)(:\d+)?)\/$`,
+				Replacement: "${1}/dashboard/",
+				Permanent:   true,
+			},
+		}
+		cfg.HTTP.Middlewares["dashboard_stripprefix"] = &dynamic.Middleware{
+			StripPrefix: &dynamic.StripPrefix{Prefixes: []string{"/dashboard/", "/dashboard"}},
+		}
 	}
 }
 
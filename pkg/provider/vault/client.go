@@ -0,0 +1,231 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxResponseContentSize is the maximum size of the responses read from Vault.
+const maxResponseContentSize = 10 << 20
+
+// errPermissionDenied is returned when Vault rejects the token, e.g. because it has been revoked.
+var errPermissionDenied = errors.New("permission denied")
+
+// client is a client of the Vault HTTP API, limited to the authentication and the issuance of certificates.
+type client struct {
+	address    string
+	namespace  string
+	auth       *Auth
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	token string
+	// tokenExpiration is the time after which the token is renewed by logging in again.
+	// It is zero for the tokens which are not obtained by logging in.
+	tokenExpiration time.Time
+}
+
+// issuedCertificate holds the certificate issued by the PKI secrets engine, and its private key.
+type issuedCertificate struct {
+	Certificate string   `json:"certificate"`
+	IssuingCA   string   `json:"issuing_ca"`
+	CAChain     []string `json:"ca_chain"`
+	PrivateKey  string   `json:"private_key"`
+}
+
+// bundle returns the certificate followed by its chain.
+func (c issuedCertificate) bundle() []byte {
+	chain := c.CAChain
+	if len(chain) == 0 && c.IssuingCA != "" {
+		chain = []string{c.IssuingCA}
+	}
+
+	return []byte(strings.Join(append([]string{c.Certificate}, chain...), "\n") + "\n")
+}
+
+type issueRequest struct {
+	CommonName string `json:"common_name"`
+	AltNames   string `json:"alt_names,omitempty"`
+	TTL        string `json:"ttl,omitempty"`
+}
+
+type loginResponse struct {
+	Auth struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int    `json:"lease_duration"`
+	} `json:"auth"`
+}
+
+type errorResponse struct {
+	Errors []string `json:"errors"`
+}
+
+// issue requests a certificate for the domains to the PKI secrets engine.
+func (c *client) issue(ctx context.Context, mount, role string, domains []string, ttl time.Duration) (*issuedCertificate, error) {
+	request := issueRequest{
+		CommonName: domains[0],
+		AltNames:   strings.Join(domains[1:], ","),
+	}
+	if ttl > 0 {
+		request.TTL = ttl.String()
+	}
+
+	path := fmt.Sprintf("%s/issue/%s", strings.Trim(mount, "/"), role)
+
+	var response struct {
+		Data issuedCertificate `json:"data"`
+	}
+
+	err := c.doWithToken(ctx, path, request, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Data.Certificate == "" || response.Data.PrivateKey == "" {
+		return nil, errors.New("no certificate or private key in the response")
+	}
+
+	return &response.Data, nil
+}
+
+// doWithToken sends the request with the token, and logs in again once if the token is rejected.
+func (c *client) doWithToken(ctx context.Context, path string, body, result interface{}) error {
+	token, err := c.getToken(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to authenticate: %w", err)
+	}
+
+	err = c.do(ctx, path, token, body, result)
+	if !errors.Is(err, errPermissionDenied) || c.auth.Token != "" {
+		return err
+	}
+
+	c.resetToken(token)
+
+	token, err = c.getToken(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to authenticate: %w", err)
+	}
+
+	return c.do(ctx, path, token, body, result)
+}
+
+// getToken returns the token, and logs in if there is no valid one.
+func (c *client) getToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.auth.Token != "" {
+		return c.auth.Token, nil
+	}
+
+	if c.token != "" && time.Now().Before(c.tokenExpiration) {
+		return c.token, nil
+	}
+
+	var path string
+	var body interface{}
+	switch {
+	case c.auth.AppRole != nil:
+		path = fmt.Sprintf("auth/%s/login", strings.Trim(c.auth.AppRole.Mount, "/"))
+		body = map[string]string{
+			"role_id":   c.auth.AppRole.RoleID,
+			"secret_id": c.auth.AppRole.SecretID,
+		}
+
+	case c.auth.Kubernetes != nil:
+		jwt, err := os.ReadFile(c.auth.Kubernetes.TokenPath)
+		if err != nil {
+			return "", fmt.Errorf("unable to read the service account token: %w", err)
+		}
+
+		path = fmt.Sprintf("auth/%s/login", strings.Trim(c.auth.Kubernetes.Mount, "/"))
+		body = map[string]string{
+			"role": c.auth.Kubernetes.Role,
+			"jwt":  strings.TrimSpace(string(jwt)),
+		}
+
+	default:
+		return "", errors.New("no authentication method")
+	}
+
+	var response loginResponse
+	if err := c.do(ctx, path, "", body, &response); err != nil {
+		return "", err
+	}
+
+	if response.Auth.ClientToken == "" {
+		return "", errors.New("no token in the login response")
+	}
+
+	// The token is renewed once the two thirds of its lease are elapsed.
+	lease := time.Duration(response.Auth.LeaseDuration) * time.Second
+	c.token = response.Auth.ClientToken
+	c.tokenExpiration = time.Now().Add(lease * 2 / 3)
+
+	return c.token, nil
+}
+
+// resetToken drops the token, unless it has already been replaced.
+func (c *client) resetToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token == token {
+		c.token = ""
+	}
+}
+
+func (c *client) do(ctx context.Context, path, token string, body, result interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(c.address, "/")+"/v1/"+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if c.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.namespace)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseContentSize))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp errorResponse
+		_ = json.Unmarshal(content, &errResp)
+
+		err := fmt.Errorf("unexpected status code %d from %s: %s", resp.StatusCode, path, strings.Join(errResp.Errors, ", "))
+		if resp.StatusCode == http.StatusForbidden {
+			return fmt.Errorf("%w: %v", errPermissionDenied, err)
+		}
+
+		return err
+	}
+
+	return json.Unmarshal(content, result)
+}
@@ -0,0 +1,456 @@
+package vault
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider"
+	"github.com/traefik/traefik/v2/pkg/rules"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+const (
+	// renewCheckInterval is the interval between two checks of the certificates to renew.
+	renewCheckInterval = time.Hour
+	requestTimeout     = 30 * time.Second
+)
+
+var _ provider.Provider = (*Provider)(nil)
+
+// Configuration holds the configuration of a certificates resolver requesting the certificates
+// from the PKI secrets engine of HashiCorp Vault.
+type Configuration struct {
+	Address   string           `description:"Address of the Vault server." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	Namespace string           `description:"Vault Enterprise namespace." json:"namespace,omitempty" toml:"namespace,omitempty" yaml:"namespace,omitempty"`
+	TLS       *types.ClientTLS `description:"Enable TLS support." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	Mount     string           `description:"Path of the PKI secrets engine." json:"mount,omitempty" toml:"mount,omitempty" yaml:"mount,omitempty" export:"true"`
+	Role      string           `description:"Role of the PKI secrets engine issuing the certificates." json:"role,omitempty" toml:"role,omitempty" yaml:"role,omitempty" export:"true"`
+	TTL       ptypes.Duration  `description:"Requested lifetime of the certificates. The TTL of the role is used if not set." json:"ttl,omitempty" toml:"ttl,omitempty" yaml:"ttl,omitempty" export:"true"`
+	Auth      *Auth            `description:"Authentication to Vault." json:"auth,omitempty" toml:"auth,omitempty" yaml:"auth,omitempty"`
+}
+
+// SetDefaults sets the default values.
+func (c *Configuration) SetDefaults() {
+	c.Mount = "pki"
+}
+
+// Auth holds the method used to authenticate to Vault.
+type Auth struct {
+	Token      string          `description:"Vault token." json:"token,omitempty" toml:"token,omitempty" yaml:"token,omitempty"`
+	AppRole    *AppRoleAuth    `description:"AppRole authentication." json:"appRole,omitempty" toml:"appRole,omitempty" yaml:"appRole,omitempty"`
+	Kubernetes *KubernetesAuth `description:"Kubernetes authentication." json:"kubernetes,omitempty" toml:"kubernetes,omitempty" yaml:"kubernetes,omitempty"`
+}
+
+// AppRoleAuth holds the credentials of the AppRole authentication method.
+type AppRoleAuth struct {
+	RoleID   string `description:"Role ID." json:"roleID,omitempty" toml:"roleID,omitempty" yaml:"roleID,omitempty"`
+	SecretID string `description:"Secret ID." json:"secretID,omitempty" toml:"secretID,omitempty" yaml:"secretID,omitempty"`
+	Mount    string `description:"Path of the AppRole authentication method." json:"mount,omitempty" toml:"mount,omitempty" yaml:"mount,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (a *AppRoleAuth) SetDefaults() {
+	a.Mount = "approle"
+}
+
+// KubernetesAuth holds the configuration of the Kubernetes authentication method.
+type KubernetesAuth struct {
+	Role      string `description:"Role of the Kubernetes authentication method." json:"role,omitempty" toml:"role,omitempty" yaml:"role,omitempty" export:"true"`
+	TokenPath string `description:"Path of the service account token." json:"tokenPath,omitempty" toml:"tokenPath,omitempty" yaml:"tokenPath,omitempty" export:"true"`
+	Mount     string `description:"Path of the Kubernetes authentication method." json:"mount,omitempty" toml:"mount,omitempty" yaml:"mount,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (k *KubernetesAuth) SetDefaults() {
+	k.TokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	k.Mount = "kubernetes"
+}
+
+// certificate holds a certificate issued by Vault for the domains of the routers.
+type certificate struct {
+	domains     []string
+	certificate []byte
+	key         []byte
+	notBefore   time.Time
+	notAfter    time.Time
+}
+
+// Provider is a certificates resolver requesting the certificates of the routers domains from Vault.
+// The certificates are only kept in memory, and are renewed once the two thirds of their lifetime are elapsed.
+type Provider struct {
+	*Configuration
+	ResolverName string
+
+	client                 *client
+	configFromListenerChan chan dynamic.Configuration
+	configurationChan      chan<- dynamic.Message
+
+	mu           sync.Mutex
+	certificates []*certificate
+	resolving    map[string]struct{}
+}
+
+// SetConfigListenerChan initializes the configFromListenerChan.
+func (p *Provider) SetConfigListenerChan(configFromListenerChan chan dynamic.Configuration) {
+	p.configFromListenerChan = configFromListenerChan
+}
+
+// ListenConfiguration sets a new Configuration into the configFromListenerChan.
+func (p *Provider) ListenConfiguration(config dynamic.Configuration) {
+	p.configFromListenerChan <- config
+}
+
+// Init the provider.
+func (p *Provider) Init() error {
+	if p.Address == "" {
+		return errors.New("the Vault address is required")
+	}
+
+	if p.Role == "" {
+		return errors.New("the PKI role is required")
+	}
+
+	if p.TTL < 0 {
+		return errors.New("the TTL of the certificates must be positive")
+	}
+
+	if p.Auth == nil {
+		return errors.New("the Vault authentication is required")
+	}
+
+	methods := 0
+	if p.Auth.Token != "" {
+		methods++
+	}
+	if p.Auth.AppRole != nil {
+		methods++
+	}
+	if p.Auth.Kubernetes != nil {
+		methods++
+	}
+	if methods != 1 {
+		return errors.New("exactly one Vault authentication method must be set: token, appRole, or kubernetes")
+	}
+
+	httpClient := &http.Client{Timeout: requestTimeout}
+	if p.TLS != nil {
+		tlsConfig, err := p.TLS.CreateTLSConfig(context.Background())
+		if err != nil {
+			return fmt.Errorf("unable to create TLS configuration: %w", err)
+		}
+
+		httpClient.Transport = &http.Transport{TLSClientConfig: tlsConfig}
+	}
+
+	p.client = &client{
+		address:    p.Address,
+		namespace:  p.Namespace,
+		auth:       p.Auth,
+		httpClient: httpClient,
+	}
+
+	p.resolving = make(map[string]struct{})
+
+	return nil
+}
+
+// Provide allows the provider to provide configurations to traefik using the given configuration channel.
+func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
+	ctx := log.With(context.Background(), log.Str(log.ProviderName, p.ResolverName+".vault"))
+
+	p.configurationChan = configurationChan
+
+	pool.GoCtx(func(ctxPool context.Context) {
+		for {
+			select {
+			case config := <-p.configFromListenerChan:
+				for _, domains := range p.routersDomains(ctx, config) {
+					p.resolveDomains(ctx, domains)
+				}
+
+			case <-ctxPool.Done():
+				return
+			}
+		}
+	})
+
+	pool.GoCtx(func(ctxPool context.Context) {
+		ticker := time.NewTicker(renewCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				p.renewCertificates(ctx)
+
+			case <-ctxPool.Done():
+				return
+			}
+		}
+	})
+
+	return nil
+}
+
+// routersDomains returns the domains of the routers using the resolver, by router.
+func (p *Provider) routersDomains(ctx context.Context, config dynamic.Configuration) [][]string {
+	var domains [][]string
+
+	if config.HTTP != nil {
+		for routerName, router := range config.HTTP.Routers {
+			if router.TLS == nil || router.TLS.CertResolver != p.ResolverName {
+				continue
+			}
+
+			domains = append(domains, p.routerDomains(ctx, routerName, router.Rule, router.TLS.Domains, rules.ParseDomains)...)
+		}
+	}
+
+	if config.TCP != nil {
+		for routerName, router := range config.TCP.Routers {
+			if router.TLS == nil || router.TLS.CertResolver != p.ResolverName {
+				continue
+			}
+
+			if router.TLS.Passthrough {
+				log.FromContext(log.With(ctx, log.Str(log.RouterName, routerName))).
+					Warn("No Vault certificate is requested for a router passing the TLS connections through to the services")
+				continue
+			}
+
+			domains = append(domains, p.routerDomains(ctx, routerName, router.Rule, router.TLS.Domains, parseHostSNI)...)
+		}
+	}
+
+	return domains
+}
+
+func (p *Provider) routerDomains(ctx context.Context, routerName, rule string, tlsDomains []types.Domain, parse func(string) ([]string, error)) [][]string {
+	if len(tlsDomains) > 0 {
+		var domains [][]string
+		for _, domain := range tlsDomains {
+			domains = append(domains, domain.ToStrArray())
+		}
+
+		return domains
+	}
+
+	domains, err := parse(rule)
+	if err != nil {
+		log.FromContext(log.With(ctx, log.Str(log.RouterName, routerName), log.Str(log.Rule, rule))).
+			Errorf("Error parsing domains in provider Vault: %v", err)
+		return nil
+	}
+
+	if len(domains) == 0 {
+		return nil
+	}
+
+	return [][]string{domains}
+}
+
+// parseHostSNI returns the domains of the HostSNI rule, without the catch-all HostSNI(`*`) for which no certificate can be requested.
+func parseHostSNI(rule string) ([]string, error) {
+	domains, err := rules.ParseHostSNI(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	var sniDomains []string
+	for _, domain := range domains {
+		if domain != "*" {
+			sniDomains = append(sniDomains, domain)
+		}
+	}
+
+	return sniDomains, nil
+}
+
+// resolveDomains requests a certificate for the domains in the background,
+// unless they are covered by a certificate already, or being requested.
+func (p *Provider) resolveDomains(ctx context.Context, domains []string) {
+	for i, domain := range domains {
+		domains[i] = types.CanonicalDomain(domain)
+	}
+
+	key := domainsKey(domains)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.resolving[key]; ok || p.findCertificate(domains) != nil {
+		return
+	}
+
+	p.resolving[key] = struct{}{}
+
+	safe.Go(func() {
+		defer func() {
+			p.mu.Lock()
+			delete(p.resolving, key)
+			p.mu.Unlock()
+		}()
+
+		if err := p.obtainCertificate(ctx, domains, nil); err != nil {
+			log.FromContext(ctx).Errorf("Unable to obtain the certificate for the domains %q: %v", strings.Join(domains, ","), err)
+		}
+	})
+}
+
+// findCertificate returns the certificate covering all the domains, if any.
+func (p *Provider) findCertificate(domains []string) *certificate {
+	for _, cert := range p.certificates {
+		if coversDomains(cert.domains, domains) {
+			return cert
+		}
+	}
+
+	return nil
+}
+
+// obtainCertificate requests a certificate for the domains, replacing the previous certificate if any.
+func (p *Provider) obtainCertificate(ctx context.Context, domains []string, previous *certificate) error {
+	log.FromContext(ctx).Debugf("Requesting the certificate for the domains %q...", strings.Join(domains, ","))
+
+	issued, err := p.client.issue(ctx, p.Mount, p.Role, domains, time.Duration(p.TTL))
+	if err != nil {
+		return err
+	}
+
+	cert, err := newCertificate(domains, issued)
+	if err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Debugf("Certificate obtained for the domains %q, valid until %s", strings.Join(domains, ","), cert.notAfter)
+
+	p.mu.Lock()
+	replaced := false
+	for i, current := range p.certificates {
+		if current == previous {
+			p.certificates[i] = cert
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		p.certificates = append(p.certificates, cert)
+	}
+	message := p.buildMessage()
+	p.mu.Unlock()
+
+	p.configurationChan <- message
+
+	return nil
+}
+
+// renewCertificates renews the certificates whose two thirds of lifetime are elapsed.
+func (p *Provider) renewCertificates(ctx context.Context) {
+	now := time.Now()
+
+	p.mu.Lock()
+	var toRenew []*certificate
+	for _, cert := range p.certificates {
+		if cert.needsRenewal(now) {
+			toRenew = append(toRenew, cert)
+		}
+	}
+	p.mu.Unlock()
+
+	for _, cert := range toRenew {
+		if err := p.obtainCertificate(ctx, cert.domains, cert); err != nil {
+			log.FromContext(ctx).Errorf("Unable to renew the certificate for the domains %q: %v", strings.Join(cert.domains, ","), err)
+		}
+	}
+}
+
+func (p *Provider) buildMessage() dynamic.Message {
+	conf := dynamic.Message{
+		ProviderName: p.ResolverName + ".vault",
+		Configuration: &dynamic.Configuration{
+			HTTP: &dynamic.HTTPConfiguration{
+				Routers:     map[string]*dynamic.Router{},
+				Middlewares: map[string]*dynamic.Middleware{},
+				Services:    map[string]*dynamic.Service{},
+			},
+			TLS: &dynamic.TLSConfiguration{},
+		},
+	}
+
+	for _, cert := range p.certificates {
+		conf.Configuration.TLS.Certificates = append(conf.Configuration.TLS.Certificates, &traefiktls.CertAndStores{
+			Certificate: traefiktls.Certificate{
+				CertFile: traefiktls.FileOrContent(cert.certificate),
+				KeyFile:  traefiktls.FileOrContent(cert.key),
+			},
+			Stores: []string{traefiktls.DefaultTLSStoreName},
+		})
+	}
+
+	return conf
+}
+
+func newCertificate(domains []string, issued *issuedCertificate) (*certificate, error) {
+	block, _ := pem.Decode([]byte(issued.Certificate))
+	if block == nil {
+		return nil, errors.New("no PEM certificate in the response")
+	}
+
+	crt, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate in the response: %w", err)
+	}
+
+	return &certificate{
+		domains:     domains,
+		certificate: issued.bundle(),
+		key:         []byte(issued.PrivateKey),
+		notBefore:   crt.NotBefore,
+		notAfter:    crt.NotAfter,
+	}, nil
+}
+
+// needsRenewal reports whether the two thirds of the lifetime of the certificate are elapsed.
+func (c *certificate) needsRenewal(now time.Time) bool {
+	lifetime := c.notAfter.Sub(c.notBefore)
+	return !now.Before(c.notBefore.Add(lifetime * 2 / 3))
+}
+
+// coversDomains reports whether all the domains match one of the certificate domains.
+func coversDomains(certDomains, domains []string) bool {
+	for _, domain := range domains {
+		covered := false
+		for _, certDomain := range certDomains {
+			if traefiktls.MatchDomain(domain, certDomain) {
+				covered = true
+				break
+			}
+		}
+
+		if !covered {
+			return false
+		}
+	}
+
+	return true
+}
+
+func domainsKey(domains []string) string {
+	sorted := append([]string{}, domains...)
+	sort.Strings(sorted)
+
+	return strings.Join(sorted, ",")
+}
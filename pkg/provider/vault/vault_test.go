@@ -0,0 +1,433 @@
+package vault
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+// fakeVault is a Vault server supporting the AppRole authentication and the PKI issuance.
+type fakeVault struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	logins   int
+	token    string
+	requests []issueRequest
+}
+
+func newFakeVault(t *testing.T) *fakeVault {
+	t.Helper()
+
+	vault := &fakeVault{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/auth/approle/login", func(rw http.ResponseWriter, req *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+
+		if body["role_id"] != "role" || body["secret_id"] != "secret" {
+			rw.WriteHeader(http.StatusBadRequest)
+			_, _ = rw.Write([]byte(`{"errors":["invalid role or secret ID"]}`))
+			return
+		}
+
+		vault.mu.Lock()
+		vault.logins++
+		vault.token = fmt.Sprintf("token%d", vault.logins)
+		token := vault.token
+		vault.mu.Unlock()
+
+		_, _ = fmt.Fprintf(rw, `{"auth":{"client_token":%q,"lease_duration":3600}}`, token)
+	})
+
+	mux.HandleFunc("/v1/pki/issue/web", func(rw http.ResponseWriter, req *http.Request) {
+		vault.mu.Lock()
+		validToken := vault.token
+		vault.mu.Unlock()
+
+		if req.Header.Get("X-Vault-Token") != validToken {
+			rw.WriteHeader(http.StatusForbidden)
+			_, _ = rw.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+
+		var body issueRequest
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+
+		vault.mu.Lock()
+		vault.requests = append(vault.requests, body)
+		vault.mu.Unlock()
+
+		ttl := time.Hour
+		if body.TTL != "" {
+			var err error
+			ttl, err = time.ParseDuration(body.TTL)
+			require.NoError(t, err)
+		}
+
+		domains := []string{body.CommonName}
+		if body.AltNames != "" {
+			domains = append(domains, strings.Split(body.AltNames, ",")...)
+		}
+
+		cert, key := generateCertificate(t, domains, ttl)
+
+		_ = json.NewEncoder(rw).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"certificate": cert,
+				"issuing_ca":  cert,
+				"private_key": key,
+			},
+		})
+	})
+
+	vault.Server = httptest.NewServer(mux)
+	t.Cleanup(vault.Close)
+
+	return vault
+}
+
+func (v *fakeVault) revokeToken() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.token = ""
+}
+
+func (v *fakeVault) loginCount() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.logins
+}
+
+func (v *fakeVault) issueRequests() []issueRequest {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return append([]issueRequest{}, v.requests...)
+}
+
+func generateCertificate(t *testing.T, domains []string, ttl time.Duration) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: domains[0]},
+		DNSNames:     domains,
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(ttl),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func newTestProvider(t *testing.T, address string) (*Provider, chan dynamic.Message) {
+	t.Helper()
+
+	p := &Provider{
+		Configuration: &Configuration{
+			Address: address,
+			Mount:   "pki",
+			Role:    "web",
+			TTL:     ptypes.Duration(2 * time.Hour),
+			Auth: &Auth{
+				AppRole: &AppRoleAuth{RoleID: "role", SecretID: "secret", Mount: "approle"},
+			},
+		},
+		ResolverName: "vault",
+	}
+	require.NoError(t, p.Init())
+
+	configurationChan := make(chan dynamic.Message, 10)
+	p.configurationChan = configurationChan
+
+	return p, configurationChan
+}
+
+func waitMessage(t *testing.T, configurationChan chan dynamic.Message) dynamic.Message {
+	t.Helper()
+
+	select {
+	case message := <-configurationChan:
+		return message
+	case <-time.After(5 * time.Second):
+		t.Fatal("no configuration received")
+		return dynamic.Message{}
+	}
+}
+
+func TestProvider_Init(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      Configuration
+		expectedErr bool
+	}{
+		{
+			desc:   "token",
+			config: Configuration{Address: "http://vault:8200", Role: "web", Auth: &Auth{Token: "token"}},
+		},
+		{
+			desc:        "no address",
+			config:      Configuration{Role: "web", Auth: &Auth{Token: "token"}},
+			expectedErr: true,
+		},
+		{
+			desc:        "no role",
+			config:      Configuration{Address: "http://vault:8200", Auth: &Auth{Token: "token"}},
+			expectedErr: true,
+		},
+		{
+			desc:        "no authentication",
+			config:      Configuration{Address: "http://vault:8200", Role: "web"},
+			expectedErr: true,
+		},
+		{
+			desc: "several authentication methods",
+			config: Configuration{Address: "http://vault:8200", Role: "web", Auth: &Auth{
+				Token:   "token",
+				AppRole: &AppRoleAuth{RoleID: "role", SecretID: "secret"},
+			}},
+			expectedErr: true,
+		},
+		{
+			desc:        "negative TTL",
+			config:      Configuration{Address: "http://vault:8200", Role: "web", TTL: ptypes.Duration(-time.Hour), Auth: &Auth{Token: "token"}},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := &Provider{Configuration: &test.config, ResolverName: "vault"}
+
+			err := p.Init()
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestProvider_routersDomains(t *testing.T) {
+	p := &Provider{ResolverName: "vault"}
+
+	config := dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"rule": {
+					Rule: "Host(`foo.example.com`, `bar.example.com`)",
+					TLS:  &dynamic.RouterTLSConfig{CertResolver: "vault"},
+				},
+				"domains": {
+					Rule: "Host(`baz.example.com`)",
+					TLS: &dynamic.RouterTLSConfig{
+						CertResolver: "vault",
+						Domains:      []types.Domain{{Main: "example.org", SANs: []string{"www.example.org"}}},
+					},
+				},
+				"other resolver": {
+					Rule: "Host(`other.example.com`)",
+					TLS:  &dynamic.RouterTLSConfig{CertResolver: "acme"},
+				},
+				"no TLS": {
+					Rule: "Host(`notls.example.com`)",
+				},
+			},
+		},
+		TCP: &dynamic.TCPConfiguration{
+			Routers: map[string]*dynamic.TCPRouter{
+				"tcp": {
+					Rule: "HostSNI(`tcp.example.com`)",
+					TLS:  &dynamic.RouterTCPTLSConfig{CertResolver: "vault"},
+				},
+				"catch-all": {
+					Rule: "HostSNI(`*`) || HostSNI(`mqtt.example.com`)",
+					TLS:  &dynamic.RouterTCPTLSConfig{CertResolver: "vault"},
+				},
+				"passthrough": {
+					Rule: "HostSNI(`passthrough.example.com`)",
+					TLS:  &dynamic.RouterTCPTLSConfig{CertResolver: "vault", Passthrough: true},
+				},
+			},
+		},
+	}
+
+	domains := p.routersDomains(context.Background(), config)
+
+	assert.ElementsMatch(t, [][]string{
+		{"foo.example.com", "bar.example.com"},
+		{"example.org", "www.example.org"},
+		{"tcp.example.com"},
+		{"mqtt.example.com"},
+	}, domains)
+}
+
+func TestProvider_resolveDomains(t *testing.T) {
+	vault := newFakeVault(t)
+	p, configurationChan := newTestProvider(t, vault.URL)
+
+	p.resolveDomains(context.Background(), []string{"Foo.example.com", "bar.example.com"})
+
+	message := waitMessage(t, configurationChan)
+	assert.Equal(t, "vault.vault", message.ProviderName)
+	require.Len(t, message.Configuration.TLS.Certificates, 1)
+
+	cert, err := message.Configuration.TLS.Certificates[0].Certificate.GetCertificate()
+	require.NoError(t, err)
+
+	crt, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	assert.Equal(t, []string{"foo.example.com", "bar.example.com"}, crt.DNSNames)
+	assert.Equal(t, []issueRequest{{CommonName: "foo.example.com", AltNames: "bar.example.com", TTL: "2h0m0s"}}, vault.issueRequests())
+
+	// The domains covered by the certificate are not requested again.
+	p.resolveDomains(context.Background(), []string{"bar.example.com"})
+
+	select {
+	case <-configurationChan:
+		t.Fatal("unexpected configuration")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	assert.Len(t, vault.issueRequests(), 1)
+}
+
+func TestProvider_renewCertificates(t *testing.T) {
+	vault := newFakeVault(t)
+	p, configurationChan := newTestProvider(t, vault.URL)
+
+	valid := &certificate{
+		domains:   []string{"valid.example.com"},
+		notBefore: time.Now().Add(-time.Hour),
+		notAfter:  time.Now().Add(time.Hour),
+	}
+	expiring := &certificate{
+		domains:   []string{"expiring.example.com"},
+		notBefore: time.Now().Add(-2 * time.Hour),
+		notAfter:  time.Now().Add(30 * time.Minute),
+	}
+	p.certificates = []*certificate{valid, expiring}
+
+	p.renewCertificates(context.Background())
+
+	message := waitMessage(t, configurationChan)
+	assert.Len(t, message.Configuration.TLS.Certificates, 2)
+
+	require.Len(t, p.certificates, 2)
+	assert.Same(t, valid, p.certificates[0])
+	assert.Equal(t, []string{"expiring.example.com"}, p.certificates[1].domains)
+	assert.True(t, p.certificates[1].notAfter.After(expiring.notAfter))
+
+	assert.Equal(t, []issueRequest{{CommonName: "expiring.example.com", TTL: "2h0m0s"}}, vault.issueRequests())
+}
+
+func TestClient_issue_revokedToken(t *testing.T) {
+	vault := newFakeVault(t)
+	p, _ := newTestProvider(t, vault.URL)
+
+	_, err := p.client.issue(context.Background(), "pki", "web", []string{"foo.example.com"}, 0)
+	require.NoError(t, err)
+
+	// The token is kept while it is valid.
+	_, err = p.client.issue(context.Background(), "pki", "web", []string{"foo.example.com"}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, vault.loginCount())
+
+	// The client logs in again when the token is rejected.
+	vault.revokeToken()
+
+	_, err = p.client.issue(context.Background(), "pki", "web", []string{"foo.example.com"}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, vault.loginCount())
+}
+
+func TestClient_issue_error(t *testing.T) {
+	vault := newFakeVault(t)
+	p, _ := newTestProvider(t, vault.URL)
+
+	_, err := p.client.issue(context.Background(), "pki", "unknown", []string{"foo.example.com"}, 0)
+	assert.Error(t, err)
+
+	p.Auth.AppRole.SecretID = "invalid"
+	p.client.token = ""
+
+	_, err = p.client.issue(context.Background(), "pki", "web", []string{"foo.example.com"}, 0)
+	assert.EqualError(t, err, "unable to authenticate: unexpected status code 400 from auth/approle/login: invalid role or secret ID")
+}
+
+func TestCertificate_needsRenewal(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		desc     string
+		cert     certificate
+		expected bool
+	}{
+		{
+			desc:     "new certificate",
+			cert:     certificate{notBefore: now, notAfter: now.Add(3 * time.Hour)},
+			expected: false,
+		},
+		{
+			desc:     "less than two thirds of the lifetime elapsed",
+			cert:     certificate{notBefore: now.Add(-time.Hour), notAfter: now.Add(time.Hour)},
+			expected: false,
+		},
+		{
+			desc:     "two thirds of the lifetime elapsed",
+			cert:     certificate{notBefore: now.Add(-2 * time.Hour), notAfter: now.Add(time.Hour)},
+			expected: true,
+		},
+		{
+			desc:     "expired certificate",
+			cert:     certificate{notBefore: now.Add(-2 * time.Hour), notAfter: now.Add(-time.Hour)},
+			expected: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, test.cert.needsRenewal(now))
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package rules
+
+import (
+	"sync"
+)
+
+var (
+	httpRulesCache    = newCache()
+	tcpRulesCache     = newCache()
+	httpMatchersCache = newCache()
+)
+
+// SweepCache drops the cached rules which have not been used since the previous sweep.
+// It is meant to be called each time the routers of a new configuration have been built,
+// so that the caches only hold the rules of the current configuration.
+func SweepCache() {
+	httpRulesCache.sweep()
+	tcpRulesCache.sweep()
+	httpMatchersCache.sweep()
+}
+
+// cache caches values built from a rule, keyed by the rule text.
+// As most routers are unchanged between two configuration reloads,
+// it avoids parsing and compiling thousands of identical rules again on each reload.
+type cache struct {
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+type cacheEntry struct {
+	value interface{}
+	used  bool
+}
+
+func newCache() *cache {
+	return &cache{entries: make(map[string]*cacheEntry)}
+}
+
+// get returns the value cached for the given rule, calling build only if it is not already cached.
+// The build is done without holding the lock:
+// two concurrent calls for the same missing rule can both build it, but only the first stored value is kept and returned.
+// Build errors are not cached.
+func (c *cache) get(rule string, build func() (interface{}, error)) (interface{}, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[rule]
+	if ok {
+		entry.used = true
+	}
+	c.mu.Unlock()
+
+	if ok {
+		return entry.value, nil
+	}
+
+	value, err := build()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok = c.entries[rule]; ok {
+		entry.used = true
+		return entry.value, nil
+	}
+
+	c.entries[rule] = &cacheEntry{value: value, used: true}
+
+	return value, nil
+}
+
+func (c *cache) sweep() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for rule, entry := range c.entries {
+		if !entry.used {
+			delete(c.entries, rule)
+			continue
+		}
+
+		entry.used = false
+	}
+}
+
+func (c *cache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.entries)
+}
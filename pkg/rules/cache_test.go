@@ -0,0 +1,199 @@
+package rules
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/middlewares/requestdecorator"
+	"github.com/traefik/traefik/v2/pkg/testhelpers"
+)
+
+func TestCache(t *testing.T) {
+	c := newCache()
+
+	var built int
+	build := func() (interface{}, error) {
+		built++
+		return built, nil
+	}
+
+	value, err := c.get("Host(`foo.bar`)", build)
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	value, err = c.get("Host(`foo.bar`)", build)
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	value, err = c.get("Host(`bar.foo`)", build)
+	require.NoError(t, err)
+	assert.Equal(t, 2, value)
+
+	_, err = c.get("Host(`foo.bar`", func() (interface{}, error) {
+		return nil, errors.New("cannot parse")
+	})
+	require.Error(t, err)
+
+	assert.Equal(t, 2, built)
+	assert.Equal(t, 2, c.len())
+
+	// Both rules have been used since the creation of the cache.
+	c.sweep()
+	assert.Equal(t, 2, c.len())
+
+	_, err = c.get("Host(`foo.bar`)", build)
+	require.NoError(t, err)
+
+	// Only the rules used since the previous sweep are kept.
+	c.sweep()
+	assert.Equal(t, 1, c.len())
+
+	value, err = c.get("Host(`foo.bar`)", build)
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	c.sweep()
+	c.sweep()
+	assert.Equal(t, 0, c.len())
+}
+
+func TestCache_concurrentBuilds(t *testing.T) {
+	c := newCache()
+
+	var wg sync.WaitGroup
+	values := make([]interface{}, 10)
+	for i := range values {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			value, err := c.get("Host(`foo.bar`)", func() (interface{}, error) {
+				return i, nil
+			})
+			assert.NoError(t, err)
+
+			values[i] = value
+		}(i)
+	}
+	wg.Wait()
+
+	for _, value := range values {
+		assert.Equal(t, values[0], value)
+	}
+}
+
+func TestRouter_AddRoute_cachedRuleValuesAreNotShared(t *testing.T) {
+	rule := "Method(`get`) && Host(`FOO`)"
+
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	router, err := NewRouter()
+	require.NoError(t, err)
+
+	err = router.AddRoute(rule, 0, handler)
+	require.NoError(t, err)
+
+	reqHost := requestdecorator.New(nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// Simulates a configuration reload, while the current router is serving requests.
+			reloaded, err := NewRouter()
+			assert.NoError(t, err)
+
+			err = reloaded.AddRoute(rule, 0, handler)
+			assert.NoError(t, err)
+
+			_, err = ParseDomains(rule)
+			assert.NoError(t, err)
+
+			// Compiles the cached parsed rule again, as it happens when the compiled matcher is not cached anymore.
+			buildTree, err := parse(httpRulesCache, rule, newParser)
+			assert.NoError(t, err)
+
+			err = addRuleOnRoute(reloaded.NewRoute(), buildTree())
+			assert.NoError(t, err)
+		}()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			rw := httptest.NewRecorder()
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://foo/", nil)
+			reqHost.ServeHTTP(rw, req, router.ServeHTTP)
+
+			assert.Equal(t, http.StatusOK, rw.Code)
+		}()
+	}
+	wg.Wait()
+
+	buildTree, err := parse(httpRulesCache, rule, newParser)
+	require.NoError(t, err)
+
+	built := buildTree()
+	assert.Equal(t, []string{"get"}, built.ruleLeft.value)
+	assert.Equal(t, []string{"FOO"}, built.ruleRight.value)
+
+	// Each built tree owns its values.
+	built.ruleLeft.value[0] = "post"
+	assert.Equal(t, []string{"get"}, buildTree().ruleLeft.value)
+}
+
+func BenchmarkRouter_AddRoute(b *testing.B) {
+	var rules []string
+	for i := 0; i < 1000; i++ {
+		rules = append(rules, "Host(`foo"+strconv.Itoa(i)+".bar`) && PathPrefix(`/api/{version:v[0-9]+}`) && HeadersRegexp(`X-Foo`, `bar.*`)")
+	}
+
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {})
+
+	reload := func(b *testing.B) {
+		b.Helper()
+
+		router, err := NewRouter()
+		require.NoError(b, err)
+
+		for _, rule := range rules {
+			err = router.AddRoute(rule, 0, handler)
+			require.NoError(b, err)
+		}
+
+		router.SortRoutes()
+	}
+
+	b.Run("unchanged rules", func(b *testing.B) {
+		reload(b)
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			reload(b)
+		}
+	})
+
+	b.Run("new rules", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			// Simulates reloads where none of the rules can be found in the caches.
+			SweepCache()
+			SweepCache()
+
+			reload(b)
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package rules
+
+import (
+	"context"
+	"net/http"
+)
+
+type decisionKey struct{}
+
+// Decision records the evaluation of the routes of a router for a request.
+type Decision struct {
+	Routes []RouteDecision `json:"routes"`
+}
+
+// RouteDecision is the evaluation of the rule of a route.
+type RouteDecision struct {
+	Name     string `json:"name,omitempty"`
+	Rule     string `json:"rule"`
+	Priority int    `json:"priority"`
+	Matched  bool   `json:"matched"`
+}
+
+// Matched returns the name of the route which matched the request, if any.
+func (d *Decision) Matched() (string, bool) {
+	for _, route := range d.Routes {
+		if route.Matched {
+			return route.Name, true
+		}
+	}
+
+	return "", false
+}
+
+// WithDecision returns a copy of the request recording into the decision the routes evaluated for it, in order.
+func WithDecision(req *http.Request, decision *Decision) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), decisionKey{}, decision))
+}
+
+func getDecision(req *http.Request) *Decision {
+	decision, _ := req.Context().Value(decisionKey{}).(*Decision)
+	return decision
+}
@@ -0,0 +1,46 @@
+package rules
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/testhelpers"
+)
+
+func TestRouter_decision(t *testing.T) {
+	router, err := NewRouter()
+	require.NoError(t, err)
+
+	handler := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+	err = router.AddNamedRoute("foo", "PathPrefix(`/baz`)", 0, handler)
+	require.NoError(t, err)
+
+	err = router.AddNamedRoute("bar", "PathPrefix(`/bar`)", 100, handler)
+	require.NoError(t, err)
+
+	err = router.AddNamedRoute("catchall", "PathPrefix(`/`)", 1, handler)
+	require.NoError(t, err)
+
+	router.SortRoutes()
+
+	decision := &Decision{}
+	req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost/baz", nil)
+	router.ServeHTTP(httptest.NewRecorder(), WithDecision(req, decision))
+
+	assert.Equal(t, []RouteDecision{
+		{Name: "bar", Rule: "PathPrefix(`/bar`)", Priority: 100, Matched: false},
+		{Name: "foo", Rule: "PathPrefix(`/baz`)", Priority: 18, Matched: true},
+	}, decision.Routes)
+
+	name, ok := decision.Matched()
+	assert.True(t, ok)
+	assert.Equal(t, "foo", name)
+
+	// Without a decision, nothing is recorded.
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Len(t, decision.Routes, 2)
+}
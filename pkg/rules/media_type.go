@@ -0,0 +1,131 @@
+package rules
+
+import (
+	"fmt"
+	"mime"
+	"strconv"
+	"strings"
+)
+
+// mediaRange is a media range of an Accept header, with its quality value.
+type mediaRange struct {
+	mediaType string
+	quality   float64
+}
+
+// parseAccept returns the media ranges of the given Accept header values.
+// The invalid media ranges are ignored.
+func parseAccept(values []string) []mediaRange {
+	var ranges []mediaRange
+	for _, value := range values {
+		for _, elem := range strings.Split(value, ",") {
+			if strings.TrimSpace(elem) == "" {
+				continue
+			}
+
+			mediaType, params, err := parseMediaType(elem)
+			if err != nil {
+				continue
+			}
+
+			quality := 1.0
+			if q, ok := params["q"]; ok {
+				quality, err = strconv.ParseFloat(q, 64)
+				if err != nil || quality < 0 || quality > 1 {
+					continue
+				}
+			}
+
+			ranges = append(ranges, mediaRange{mediaType: mediaType, quality: quality})
+		}
+	}
+
+	return ranges
+}
+
+// acceptsPreferred reports whether one of the given media types is among the most preferred ones
+// of the given Accept header values.
+// A request without Accept header accepts all media types equally.
+func acceptsPreferred(values []string, mediaTypes []string) bool {
+	ranges := parseAccept(values)
+	if len(ranges) == 0 {
+		return true
+	}
+
+	var preferred float64
+	for _, r := range ranges {
+		if r.quality > preferred {
+			preferred = r.quality
+		}
+	}
+
+	if preferred == 0 {
+		return false
+	}
+
+	for _, mediaType := range mediaTypes {
+		if mediaTypeQuality(ranges, mediaType) == preferred {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mediaTypeQuality returns the quality value of the given media type,
+// from the most specific of the given media ranges matching it, or 0 if none matches.
+func mediaTypeQuality(ranges []mediaRange, mediaType string) float64 {
+	specificity := -1
+	var quality float64
+	for _, r := range ranges {
+		if s := mediaRangeSpecificity(r.mediaType, mediaType); s > specificity {
+			specificity = s
+			quality = r.quality
+		}
+	}
+
+	return quality
+}
+
+// mediaRangeSpecificity returns how specific the given media range matching the given media type is:
+// 2 for an exact match, 1 for a subtype wildcard, 0 for a full wildcard, and -1 if it does not match.
+func mediaRangeSpecificity(mediaRange, mediaType string) int {
+	if mediaRange == mediaType {
+		return 2
+	}
+
+	if mediaRange == "*/*" {
+		return 0
+	}
+
+	rangeType, rangeSubtype := splitMediaType(mediaRange)
+	typ, _ := splitMediaType(mediaType)
+	if rangeSubtype == "*" && rangeType == typ {
+		return 1
+	}
+
+	return -1
+}
+
+// parseMediaType parses the given media type, which must be of the type/subtype form, and returns it in lowercase with its parameters.
+func parseMediaType(value string) (string, map[string]string, error) {
+	mediaType, params, err := mime.ParseMediaType(value)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if _, subtype := splitMediaType(mediaType); subtype == "" {
+		return "", nil, fmt.Errorf("media type %q has no subtype", mediaType)
+	}
+
+	return mediaType, params, nil
+}
+
+func splitMediaType(mediaType string) (string, string) {
+	parts := strings.SplitN(mediaType, "/", 2)
+	if len(parts) < 2 {
+		return parts[0], ""
+	}
+
+	return parts[0], parts[1]
+}
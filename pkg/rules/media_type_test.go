@@ -0,0 +1,85 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_acceptsPreferred(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		values     []string
+		mediaTypes []string
+		expected   bool
+	}{
+		{
+			desc:       "no Accept header",
+			mediaTypes: []string{"application/json"},
+			expected:   true,
+		},
+		{
+			desc:       "exact media type",
+			values:     []string{"application/json"},
+			mediaTypes: []string{"application/json"},
+			expected:   true,
+		},
+		{
+			desc:       "full wildcard",
+			values:     []string{"*/*"},
+			mediaTypes: []string{"application/json"},
+			expected:   true,
+		},
+		{
+			desc:       "subtype wildcard",
+			values:     []string{"application/*"},
+			mediaTypes: []string{"application/json"},
+			expected:   true,
+		},
+		{
+			desc:       "less preferred media type",
+			values:     []string{"text/html, application/json;q=0.5"},
+			mediaTypes: []string{"application/json"},
+			expected:   false,
+		},
+		{
+			desc:       "more specific range takes precedence",
+			values:     []string{"application/*;q=0.2, application/json"},
+			mediaTypes: []string{"application/json"},
+			expected:   true,
+		},
+		{
+			desc:       "excluded by a more specific range",
+			values:     []string{"*/*, application/json;q=0"},
+			mediaTypes: []string{"application/json"},
+			expected:   false,
+		},
+		{
+			desc:       "several Accept headers",
+			values:     []string{"text/html;q=0.5", "application/json"},
+			mediaTypes: []string{"application/json"},
+			expected:   true,
+		},
+		{
+			desc:       "invalid ranges are ignored",
+			values:     []string{"foo, application/json;q=2, application/json;q=0.8"},
+			mediaTypes: []string{"application/json"},
+			expected:   true,
+		},
+		{
+			desc:       "nothing acceptable",
+			values:     []string{"text/html;q=0"},
+			mediaTypes: []string{"text/html"},
+			expected:   false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, acceptsPreferred(test.values, test.mediaTypes))
+		})
+	}
+}
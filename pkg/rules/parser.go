@@ -16,43 +16,70 @@ type treeBuilder func() *tree
 
 // ParseDomains extract domains from rule.
 func ParseDomains(rule string) ([]string, error) {
-	parser, err := newParser()
+	buildTree, err := parse(httpRulesCache, rule, newParser)
 	if err != nil {
 		return nil, err
 	}
 
-	parse, err := parser.Parse(rule)
+	return lower(parseDomain(buildTree())), nil
+}
+
+// ParseHostSNI extracts the HostSNIs declared in a rule.
+// This is a first naive implementation used in TCP routing.
+func ParseHostSNI(rule string) ([]string, error) {
+	buildTree, err := parse(tcpRulesCache, rule, newTCPParser)
 	if err != nil {
 		return nil, err
 	}
 
-	buildTree, ok := parse.(treeBuilder)
-	if !ok {
-		return nil, errors.New("cannot parse")
-	}
-
 	return lower(parseDomain(buildTree())), nil
 }
 
-// ParseHostSNI extracts the HostSNIs declared in a rule.
-// This is a first naive implementation used in TCP routing.
-func ParseHostSNI(rule string) ([]string, error) {
-	parser, err := newTCPParser()
+// ParsePathPrefixes extracts the path prefixes declared in a rule with PathPrefix matchers.
+func ParsePathPrefixes(rule string) ([]string, error) {
+	buildTree, err := parse(httpRulesCache, rule, newParser)
 	if err != nil {
 		return nil, err
 	}
 
-	parse, err := parser.Parse(rule)
+	return parseValues(buildTree(), "PathPrefix"), nil
+}
+
+// ParsePaths extracts the paths declared in a rule with Path matchers.
+func ParsePaths(rule string) ([]string, error) {
+	buildTree, err := parse(httpRulesCache, rule, newParser)
 	if err != nil {
 		return nil, err
 	}
 
-	buildTree, ok := parse.(treeBuilder)
-	if !ok {
-		return nil, errors.New("cannot parse")
+	return parseValues(buildTree(), "Path"), nil
+}
+
+// parse returns the tree builder of the given rule, from the cache if the rule has already been parsed.
+func parse(c *cache, rule string, newParser func() (predicate.Parser, error)) (treeBuilder, error) {
+	value, err := c.get(rule, func() (interface{}, error) {
+		parser, err := newParser()
+		if err != nil {
+			return nil, err
+		}
+
+		parsed, err := parser.Parse(rule)
+		if err != nil {
+			return nil, err
+		}
+
+		buildTree, ok := parsed.(treeBuilder)
+		if !ok {
+			return nil, errors.New("cannot parse")
+		}
+
+		return buildTree, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return lower(parseDomain(buildTree())), nil
+	return value.(treeBuilder), nil
 }
 
 func lower(slice []string) []string {
@@ -74,6 +101,21 @@ func parseDomain(tree *tree) []string {
 	}
 }
 
+// parseValues returns the values of the given matcher in the tree, ignoring the negated ones.
+func parseValues(tree *tree, matcher string) []string {
+	switch tree.matcher {
+	case and, or:
+		return append(parseValues(tree.ruleLeft, matcher), parseValues(tree.ruleRight, matcher)...)
+	case matcher:
+		if tree.not {
+			return nil
+		}
+		return tree.value
+	default:
+		return nil
+	}
+}
+
 func andFunc(left, right treeBuilder) treeBuilder {
 	return func() *tree {
 		return &tree{
@@ -117,6 +159,13 @@ func notFunc(elem treeBuilder) treeBuilder {
 	}
 }
 
+// copyValues returns a copy of the values of a matcher.
+// As the tree builders are cached and the matchers can modify their values (e.g. Method upper-cases them),
+// each built tree must own its values.
+func copyValues(values []string) []string {
+	return append([]string(nil), values...)
+}
+
 func newParser() (predicate.Parser, error) {
 	parserFuncs := make(map[string]interface{})
 
@@ -126,7 +175,7 @@ func newParser() (predicate.Parser, error) {
 			return func() *tree {
 				return &tree{
 					matcher: matcherName,
-					value:   value,
+					value:   copyValues(value),
 				}
 			}
 		}
@@ -155,7 +204,7 @@ func newTCPParser() (predicate.Parser, error) {
 		return func() *tree {
 			return &tree{
 				matcher: matcherName,
-				value:   value,
+				value:   copyValues(value),
 			}
 		}
 	}
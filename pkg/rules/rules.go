@@ -10,7 +10,6 @@ import (
 	"github.com/traefik/traefik/v2/pkg/ip"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/middlewares/requestdecorator"
-	"github.com/vulcand/predicate"
 )
 
 var funcs = map[string]func(*mux.Route, ...string) error{
@@ -24,52 +23,95 @@ var funcs = map[string]func(*mux.Route, ...string) error{
 	"Headers":       headers,
 	"HeadersRegexp": headersRegexp,
 	"Query":         query,
+	"Accept":        accept,
+	"ContentType":   contentType,
 }
 
 // Router handle routing with rules.
 type Router struct {
 	*mux.Router
-	parser predicate.Parser
 }
 
 // NewRouter returns a new router instance.
 func NewRouter() (*Router, error) {
-	parser, err := newParser()
-	if err != nil {
-		return nil, err
-	}
-
 	return &Router{
 		Router: mux.NewRouter().SkipClean(true),
-		parser: parser,
 	}, nil
 }
 
 // AddRoute add a new route to the router.
 func (r *Router) AddRoute(rule string, priority int, handler http.Handler) error {
-	parse, err := r.parser.Parse(rule)
-	if err != nil {
-		return fmt.Errorf("error while parsing rule %s: %w", rule, err)
-	}
+	return r.AddNamedRoute("", rule, priority, handler)
+}
 
-	buildTree, ok := parse.(treeBuilder)
-	if !ok {
-		return fmt.Errorf("error while parsing rule %s", rule)
+// AddNamedRoute adds a new route to the router,
+// with a name identifying it in the decisions recorded for the requests.
+func (r *Router) AddNamedRoute(name, rule string, priority int, handler http.Handler) error {
+	m, err := compileRule(rule)
+	if err != nil {
+		return err
 	}
 
 	if priority == 0 {
 		priority = len(rule)
 	}
 
-	route := r.NewRoute().Handler(handler).Priority(priority)
+	r.NewRoute().Handler(handler).Priority(priority).MatcherFunc(func(req *http.Request, match *mux.RouteMatch) bool {
+		matched := m.match(req, match)
+
+		if decision := getDecision(req); decision != nil {
+			decision.Routes = append(decision.Routes, RouteDecision{Name: name, Rule: rule, Priority: priority, Matched: matched})
+		}
+
+		return matched
+	})
+
+	return nil
+}
+
+// matcher is a compiled rule.
+// It holds no state related to a specific route, so it is shared by all the routes using the same rule,
+// across configuration reloads.
+type matcher struct {
+	router *mux.Router
+}
+
+// compileRule returns the compiled matcher of the given rule, from the cache if the rule has already been compiled.
+func compileRule(rule string) (*matcher, error) {
+	value, err := httpMatchersCache.get(rule, func() (interface{}, error) {
+		buildTree, err := parse(httpRulesCache, rule, newParser)
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing rule %s: %w", rule, err)
+		}
 
-	err = addRuleOnRoute(route, buildTree())
+		router := mux.NewRouter().SkipClean(true)
+
+		err = addRuleOnRoute(router.NewRoute(), buildTree())
+		if err != nil {
+			return nil, err
+		}
+
+		return &matcher{router: router}, nil
+	})
 	if err != nil {
-		route.BuildOnly()
-		return err
+		return nil, err
 	}
 
-	return nil
+	return value.(*matcher), nil
+}
+
+func (m *matcher) match(req *http.Request, match *mux.RouteMatch) bool {
+	var ruleMatch mux.RouteMatch
+	if m.router.Match(req, &ruleMatch) {
+		return true
+	}
+
+	// Reports the method mismatch to the enclosing router, so it can respond with a 405.
+	if ruleMatch.MatchErr == mux.ErrMethodMismatch {
+		match.MatchErr = mux.ErrMethodMismatch
+	}
+
+	return false
 }
 
 type tree struct {
@@ -167,7 +209,8 @@ func clientIP(route *mux.Route, clientIPs ...string) error {
 		return fmt.Errorf("could not initialize IP Checker for \"ClientIP\" matcher: %w", err)
 	}
 
-	strategy := ip.RemoteAddrStrategy{}
+	// The client IP is determined by the entry point, according to its trusted forwarded headers.
+	strategy := ip.ClientIPStrategy{}
 
 	route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
 		ok, err := checker.Contains(strategy.GetIP(req))
@@ -220,6 +263,60 @@ func query(route *mux.Route, query ...string) error {
 	return route.GetError()
 }
 
+func accept(route *mux.Route, mediaTypes ...string) error {
+	for i, mediaType := range mediaTypes {
+		parsed, _, err := parseMediaType(mediaType)
+		if err != nil {
+			return fmt.Errorf("invalid value %q for \"Accept\" matcher: %w", mediaType, err)
+		}
+
+		if strings.Contains(parsed, "*") {
+			return fmt.Errorf("invalid value %q for \"Accept\" matcher, wildcards are not allowed", mediaType)
+		}
+
+		mediaTypes[i] = parsed
+	}
+
+	route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		return acceptsPreferred(req.Header.Values("Accept"), mediaTypes)
+	})
+
+	return nil
+}
+
+func contentType(route *mux.Route, mediaTypes ...string) error {
+	for i, mediaType := range mediaTypes {
+		parsed, _, err := parseMediaType(mediaType)
+		if err != nil {
+			return fmt.Errorf("invalid value %q for \"ContentType\" matcher: %w", mediaType, err)
+		}
+
+		mediaTypes[i] = parsed
+	}
+
+	route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		value := req.Header.Get("Content-Type")
+		if value == "" {
+			return false
+		}
+
+		reqMediaType, _, err := parseMediaType(value)
+		if err != nil {
+			return false
+		}
+
+		for _, mediaType := range mediaTypes {
+			if mediaRangeSpecificity(mediaType, reqMediaType) >= 0 {
+				return true
+			}
+		}
+
+		return false
+	})
+
+	return nil
+}
+
 func addRuleOnRouter(router *mux.Router, rule *tree) error {
 	switch rule.matcher {
 	case "and":
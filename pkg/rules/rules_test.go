@@ -8,6 +8,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/ip"
 	"github.com/traefik/traefik/v2/pkg/middlewares/requestdecorator"
 	"github.com/traefik/traefik/v2/pkg/testhelpers"
 )
@@ -18,6 +19,7 @@ func Test_addRoute(t *testing.T) {
 		rule          string
 		headers       map[string]string
 		remoteAddr    string
+		clientIP      string
 		expected      map[string]int
 		expectedError bool
 	}{
@@ -626,6 +628,117 @@ func Test_addRoute(t *testing.T) {
 				"http://tchouk/toto": http.StatusOK,
 			},
 		},
+		{
+			desc:       "Matching forwarded client IPv6 with CIDR",
+			rule:       "ClientIP(`10.0.0.0/8`, `2001:db8::/32`)",
+			remoteAddr: "192.168.0.1:8456",
+			clientIP:   "2001:db8::1",
+			expected: map[string]int{
+				"http://tchouk/toto": http.StatusOK,
+			},
+		},
+		{
+			desc:       "Non matching forwarded client IP of a matching remote address",
+			rule:       "ClientIP(`10.0.0.0/8`)",
+			remoteAddr: "10.0.0.1:8456",
+			clientIP:   "192.0.2.1",
+			expected: map[string]int{
+				"http://tchouk/toto": http.StatusNotFound,
+			},
+		},
+		{
+			desc:          "Accept empty",
+			rule:          "Accept(``)",
+			expectedError: true,
+		},
+		{
+			desc:          "Invalid Accept",
+			rule:          "Accept(`application/*`)",
+			expectedError: true,
+		},
+		{
+			desc:    "Matching Accept",
+			rule:    "Accept(`application/json`)",
+			headers: map[string]string{"Accept": "application/json"},
+			expected: map[string]int{
+				"http://tchouk/toto": http.StatusOK,
+			},
+		},
+		{
+			desc:    "Matching Accept among several media types",
+			rule:    "Accept(`application/json`, `application/xml`)",
+			headers: map[string]string{"Accept": "application/xml;q=0.9, text/plain;q=0.5"},
+			expected: map[string]int{
+				"http://tchouk/toto": http.StatusOK,
+			},
+		},
+		{
+			desc:    "Non matching Accept of a browser",
+			rule:    "Accept(`application/json`)",
+			headers: map[string]string{"Accept": "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"},
+			expected: map[string]int{
+				"http://tchouk/toto": http.StatusNotFound,
+			},
+		},
+		{
+			desc:    "Matching Accept of a browser",
+			rule:    "Accept(`text/html`)",
+			headers: map[string]string{"Accept": "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"},
+			expected: map[string]int{
+				"http://tchouk/toto": http.StatusOK,
+			},
+		},
+		{
+			desc: "Matching Accept without Accept header",
+			rule: "Accept(`application/json`)",
+			expected: map[string]int{
+				"http://tchouk/toto": http.StatusOK,
+			},
+		},
+		{
+			desc:    "Non matching Accept with a zero quality",
+			rule:    "Accept(`application/json`)",
+			headers: map[string]string{"Accept": "application/json;q=0, */*"},
+			expected: map[string]int{
+				"http://tchouk/toto": http.StatusNotFound,
+			},
+		},
+		{
+			desc:          "Invalid ContentType",
+			rule:          "ContentType(`application`)",
+			expectedError: true,
+		},
+		{
+			desc:    "Matching ContentType",
+			rule:    "ContentType(`application/json`)",
+			headers: map[string]string{"Content-Type": "Application/JSON; charset=utf-8"},
+			expected: map[string]int{
+				"http://tchouk/toto": http.StatusOK,
+			},
+		},
+		{
+			desc:    "Matching ContentType with a wildcard",
+			rule:    "ContentType(`text/*`)",
+			headers: map[string]string{"Content-Type": "text/plain"},
+			expected: map[string]int{
+				"http://tchouk/toto": http.StatusOK,
+			},
+		},
+		{
+			desc:    "Non matching ContentType",
+			rule:    "ContentType(`application/json`)",
+			headers: map[string]string{"Content-Type": "text/html"},
+			expected: map[string]int{
+				"http://tchouk/toto": http.StatusNotFound,
+			},
+		},
+		{
+			desc: "Non matching ContentType without Content-Type header",
+			rule: "ContentType(`application/json`)",
+			expected: map[string]int{
+				"http://tchouk/toto": http.StatusNotFound,
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -655,6 +768,9 @@ func Test_addRoute(t *testing.T) {
 
 					// Useful for the ClientIP matcher
 					req.RemoteAddr = test.remoteAddr
+					if test.clientIP != "" {
+						req = req.WithContext(ip.WithClientIP(req.Context(), test.clientIP))
+					}
 
 					for key, value := range test.headers {
 						req.Header.Set(key, value)
@@ -958,3 +1074,81 @@ func TestParseDomains(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePathPrefixes(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		expression string
+		expected   []string
+	}{
+		{
+			desc:       "no path prefix",
+			expression: "Host(`foo.bar`) && Path(`/test`)",
+		},
+		{
+			desc:       "path prefix",
+			expression: "Host(`foo.bar`) && PathPrefix(`/api`)",
+			expected:   []string{"/api"},
+		},
+		{
+			desc:       "many path prefixes",
+			expression: "Host(`foo.bar`) && (PathPrefix(`/api`, `/v1`) || pathprefix(`/v2`))",
+			expected:   []string{"/api", "/v1", "/v2"},
+		},
+		{
+			desc:       "negated path prefix",
+			expression: "Host(`foo.bar`) && !PathPrefix(`/api`)",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			prefixes, err := ParsePathPrefixes(test.expression)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, prefixes)
+		})
+	}
+}
+
+func TestParsePaths(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		expression string
+		expected   []string
+	}{
+		{
+			desc:       "no path",
+			expression: "Host(`foo.bar`) && PathPrefix(`/test`)",
+		},
+		{
+			desc:       "path",
+			expression: "Host(`foo.bar`) && Path(`/helloworld.Greeter/SayHello`)",
+			expected:   []string{"/helloworld.Greeter/SayHello"},
+		},
+		{
+			desc:       "many paths",
+			expression: "Path(`/api`, `/v1`) || path(`/v2`)",
+			expected:   []string{"/api", "/v1", "/v2"},
+		},
+		{
+			desc:       "negated path",
+			expression: "Host(`foo.bar`) && !Path(`/api`)",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			paths, err := ParsePaths(test.expression)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, paths)
+		})
+	}
+}
@@ -1,11 +1,16 @@
 package server
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/rules"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
 	"github.com/traefik/traefik/v2/pkg/tls"
+	"github.com/traefik/traefik/v2/pkg/types"
 )
 
 func mergeConfiguration(configurations dynamic.Configurations, defaultEntryPoints []string) dynamic.Configuration {
@@ -30,13 +35,23 @@ func mergeConfiguration(configurations dynamic.Configurations, defaultEntryPoint
 			Stores:  make(map[string]tls.Store),
 			Options: make(map[string]tls.Options),
 		},
+		Provenance: &dynamic.Provenance{},
 	}
 
 	var defaultTLSOptionProviders []string
 	var defaultTLSStoreProviders []string
 	for pvd, configuration := range configurations {
+		var provenance dynamic.Provenance
+		if configuration.Provenance != nil {
+			provenance = *configuration.Provenance
+		}
+
 		if configuration.HTTP != nil {
 			for routerName, router := range configuration.HTTP.Routers {
+				if isQualifiedName(pvd, "router", routerName) {
+					continue
+				}
+
 				if len(router.EntryPoints) == 0 {
 					log.WithoutContext().
 						WithField(log.RouterName, routerName).
@@ -44,13 +59,40 @@ func mergeConfiguration(configurations dynamic.Configurations, defaultEntryPoint
 					router.EntryPoints = defaultEntryPoints
 				}
 
+				if len(router.InlineMiddlewares) > 0 {
+					var middlewares map[string]*dynamic.Middleware
+					var err error
+					router, middlewares, err = inlineMiddlewares(routerName, router, configuration.HTTP.Middlewares)
+					if err != nil {
+						log.WithoutContext().WithField(log.ProviderName, pvd).WithField(log.RouterName, routerName).
+							Errorf("Skipping router: %v", err)
+						continue
+					}
+
+					for middlewareName, middleware := range middlewares {
+						conf.HTTP.Middlewares[provider.MakeQualifiedName(pvd, middlewareName)] = middleware
+						addSources(&conf.Provenance.Middlewares, provider.MakeQualifiedName(pvd, middlewareName), pvd, provenance.Routers[routerName])
+					}
+				}
+
 				conf.HTTP.Routers[provider.MakeQualifiedName(pvd, routerName)] = router
+				addSources(&conf.Provenance.Routers, provider.MakeQualifiedName(pvd, routerName), pvd, provenance.Routers[routerName])
 			}
 			for middlewareName, middleware := range configuration.HTTP.Middlewares {
+				if isQualifiedName(pvd, "middleware", middlewareName) {
+					continue
+				}
+
 				conf.HTTP.Middlewares[provider.MakeQualifiedName(pvd, middlewareName)] = middleware
+				addSources(&conf.Provenance.Middlewares, provider.MakeQualifiedName(pvd, middlewareName), pvd, provenance.Middlewares[middlewareName])
 			}
 			for serviceName, service := range configuration.HTTP.Services {
+				if isQualifiedName(pvd, "service", serviceName) {
+					continue
+				}
+
 				conf.HTTP.Services[provider.MakeQualifiedName(pvd, serviceName)] = service
+				addSources(&conf.Provenance.Services, provider.MakeQualifiedName(pvd, serviceName), pvd, provenance.Services[serviceName])
 			}
 			for modelName, model := range configuration.HTTP.Models {
 				conf.HTTP.Models[provider.MakeQualifiedName(pvd, modelName)] = model
@@ -62,6 +104,10 @@ func mergeConfiguration(configurations dynamic.Configurations, defaultEntryPoint
 
 		if configuration.TCP != nil {
 			for routerName, router := range configuration.TCP.Routers {
+				if isQualifiedName(pvd, "TCP router", routerName) {
+					continue
+				}
+
 				if len(router.EntryPoints) == 0 {
 					log.WithoutContext().
 						WithField(log.RouterName, routerName).
@@ -69,21 +115,42 @@ func mergeConfiguration(configurations dynamic.Configurations, defaultEntryPoint
 					router.EntryPoints = defaultEntryPoints
 				}
 				conf.TCP.Routers[provider.MakeQualifiedName(pvd, routerName)] = router
+				addSources(&conf.Provenance.TCPRouters, provider.MakeQualifiedName(pvd, routerName), pvd, provenance.TCPRouters[routerName])
 			}
 			for middlewareName, middleware := range configuration.TCP.Middlewares {
+				if isQualifiedName(pvd, "TCP middleware", middlewareName) {
+					continue
+				}
+
 				conf.TCP.Middlewares[provider.MakeQualifiedName(pvd, middlewareName)] = middleware
+				addSources(&conf.Provenance.TCPMiddlewares, provider.MakeQualifiedName(pvd, middlewareName), pvd, provenance.TCPMiddlewares[middlewareName])
 			}
 			for serviceName, service := range configuration.TCP.Services {
+				if isQualifiedName(pvd, "TCP service", serviceName) {
+					continue
+				}
+
 				conf.TCP.Services[provider.MakeQualifiedName(pvd, serviceName)] = service
+				addSources(&conf.Provenance.TCPServices, provider.MakeQualifiedName(pvd, serviceName), pvd, provenance.TCPServices[serviceName])
 			}
 		}
 
 		if configuration.UDP != nil {
 			for routerName, router := range configuration.UDP.Routers {
+				if isQualifiedName(pvd, "UDP router", routerName) {
+					continue
+				}
+
 				conf.UDP.Routers[provider.MakeQualifiedName(pvd, routerName)] = router
+				addSources(&conf.Provenance.UDPRouters, provider.MakeQualifiedName(pvd, routerName), pvd, provenance.UDPRouters[routerName])
 			}
 			for serviceName, service := range configuration.UDP.Services {
+				if isQualifiedName(pvd, "UDP service", serviceName) {
+					continue
+				}
+
 				conf.UDP.Services[provider.MakeQualifiedName(pvd, serviceName)] = service
+				addSources(&conf.Provenance.UDPServices, provider.MakeQualifiedName(pvd, serviceName), pvd, provenance.UDPServices[serviceName])
 			}
 		}
 
@@ -134,6 +201,60 @@ func mergeConfiguration(configurations dynamic.Configurations, defaultEntryPoint
 	return conf
 }
 
+// isQualifiedName reports whether the given name, of an object defined by the given provider, contains a provider qualifier.
+// Such an object is skipped, as its name would collide with the references to the objects of the other providers.
+func isQualifiedName(pvd, kind, name string) bool {
+	if !strings.Contains(name, "@") {
+		return false
+	}
+
+	log.WithoutContext().WithField(log.ProviderName, pvd).
+		Errorf("Skipping %s %q: the @ character is reserved to reference the objects of a provider, as in name@provider", kind, name)
+
+	return true
+}
+
+// inlineMiddlewares returns a copy of the given router referencing its inline middlewares by name, after its other middlewares,
+// and its inline middlewares by name.
+// The name of an inline middleware is the name of the router, suffixed with "-inline-" and its index,
+// and must not be the one of a middleware of the provider.
+func inlineMiddlewares(routerName string, router *dynamic.Router, providerMiddlewares map[string]*dynamic.Middleware) (*dynamic.Router, map[string]*dynamic.Middleware, error) {
+	router = router.DeepCopy()
+
+	middlewares := make(map[string]*dynamic.Middleware, len(router.InlineMiddlewares))
+	for i := range router.InlineMiddlewares {
+		name := fmt.Sprintf("%s-inline-%d", routerName, i)
+		if _, ok := providerMiddlewares[name]; ok {
+			return nil, nil, fmt.Errorf("the name %q of the inline middleware %d is already used by a middleware", name, i)
+		}
+
+		middlewares[name] = &router.InlineMiddlewares[i]
+		router.Middlewares = append(router.Middlewares, name)
+	}
+
+	router.InlineMiddlewares = nil
+
+	return router, middlewares, nil
+}
+
+// addSources records the sources of the object with the given qualified name, attributed to its provider,
+// or only its provider if the provider did not record any.
+func addSources(sources *map[string][]dynamic.Source, qualifiedName, pvd string, providerSources []dynamic.Source) {
+	if *sources == nil {
+		*sources = make(map[string][]dynamic.Source)
+	}
+
+	if len(providerSources) == 0 {
+		(*sources)[qualifiedName] = []dynamic.Source{{Provider: pvd}}
+		return
+	}
+
+	for _, source := range providerSources {
+		source.Provider = pvd
+		(*sources)[qualifiedName] = append((*sources)[qualifiedName], source)
+	}
+}
+
 func applyModel(cfg dynamic.Configuration) dynamic.Configuration {
 	if cfg.HTTP == nil || len(cfg.HTTP.Models) == 0 {
 		return cfg
@@ -156,6 +277,11 @@ func applyModel(cfg dynamic.Configuration) dynamic.Configuration {
 
 				if cp.TLS == nil {
 					cp.TLS = m.TLS
+
+					if certResolver := sniCertResolver(m.SNICertResolvers, cp.Rule); certResolver != "" {
+						cp.TLS = m.TLS.DeepCopy()
+						cp.TLS.CertResolver = certResolver
+					}
 				}
 
 				cp.Middlewares = append(m.Middlewares, cp.Middlewares...)
@@ -163,6 +289,10 @@ func applyModel(cfg dynamic.Configuration) dynamic.Configuration {
 				rtName := name
 				if len(eps) > 1 {
 					rtName = epName + "-" + name
+
+					if cfg.Provenance != nil && cfg.Provenance.Routers[name] != nil {
+						cfg.Provenance.Routers[rtName] = cfg.Provenance.Routers[name]
+					}
 				}
 				rts[rtName] = cp
 			} else {
@@ -178,6 +308,37 @@ func applyModel(cfg dynamic.Configuration) dynamic.Configuration {
 	return cfg
 }
 
+// sniCertResolver returns the certificate resolver of the first server name pattern matching all the domains of the given rule,
+// or an empty string if there is none.
+func sniCertResolver(sniCertResolvers []dynamic.SNICertResolver, rule string) string {
+	if len(sniCertResolvers) == 0 {
+		return ""
+	}
+
+	domains, err := rules.ParseDomains(rule)
+	if err != nil || len(domains) == 0 {
+		return ""
+	}
+
+	for _, sniCertResolver := range sniCertResolvers {
+		pattern := types.CanonicalDomain(sniCertResolver.SNI)
+
+		matches := true
+		for _, domain := range domains {
+			if !types.MatchDomain(domain, pattern) {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			return sniCertResolver.CertResolver
+		}
+	}
+
+	return ""
+}
+
 func containsACMETLS1(stores []string) bool {
 	for _, store := range stores {
 		if store == tlsalpn01.ACMETLS1Protocol {
@@ -123,6 +123,134 @@ func Test_mergeConfiguration(t *testing.T) {
 	}
 }
 
+func Test_mergeConfiguration_provenance(t *testing.T) {
+	given := dynamic.Configurations{
+		"provider-1": &dynamic.Configuration{
+			HTTP: &dynamic.HTTPConfiguration{
+				Routers: map[string]*dynamic.Router{
+					"router-1": {},
+				},
+				Services: map[string]*dynamic.Service{
+					"service-1": {},
+				},
+			},
+			Provenance: &dynamic.Provenance{
+				Routers: map[string][]dynamic.Source{
+					"router-1": {{Kind: "Ingress", Namespace: "default", Name: "foo"}},
+				},
+			},
+		},
+		"provider-2": &dynamic.Configuration{
+			TCP: &dynamic.TCPConfiguration{
+				Routers: map[string]*dynamic.TCPRouter{
+					"router-1": {},
+				},
+			},
+		},
+	}
+
+	expected := &dynamic.Provenance{
+		Routers: map[string][]dynamic.Source{
+			"router-1@provider-1": {{Provider: "provider-1", Kind: "Ingress", Namespace: "default", Name: "foo"}},
+		},
+		Services: map[string][]dynamic.Source{
+			"service-1@provider-1": {{Provider: "provider-1"}},
+		},
+		TCPRouters: map[string][]dynamic.Source{
+			"router-1@provider-2": {{Provider: "provider-2"}},
+		},
+	}
+
+	actual := mergeConfiguration(given, []string{"defaultEP"})
+	assert.Equal(t, expected, actual.Provenance)
+}
+
+func Test_mergeConfiguration_qualifiedNames(t *testing.T) {
+	given := dynamic.Configurations{
+		"provider-1": &dynamic.Configuration{
+			HTTP: &dynamic.HTTPConfiguration{
+				Routers: map[string]*dynamic.Router{
+					"router-1":            {Middlewares: []string{"auth@provider-2"}},
+					"router-2@provider-2": {},
+				},
+				Middlewares: map[string]*dynamic.Middleware{
+					"auth@provider-2": {},
+				},
+			},
+			TCP: &dynamic.TCPConfiguration{
+				Services: map[string]*dynamic.TCPService{
+					"service-1@provider-2": {},
+				},
+			},
+		},
+		"provider-2": &dynamic.Configuration{
+			HTTP: &dynamic.HTTPConfiguration{
+				Middlewares: map[string]*dynamic.Middleware{
+					"auth": {},
+				},
+			},
+		},
+	}
+
+	actual := mergeConfiguration(given, []string{"defaultEP"})
+
+	assert.Equal(t, map[string]*dynamic.Router{
+		"router-1@provider-1": {EntryPoints: []string{"defaultEP"}, Middlewares: []string{"auth@provider-2"}},
+	}, actual.HTTP.Routers)
+	assert.Equal(t, map[string]*dynamic.Middleware{
+		"auth@provider-2": {},
+	}, actual.HTTP.Middlewares)
+	assert.Empty(t, actual.TCP.Services)
+}
+
+func Test_mergeConfiguration_inlineMiddlewares(t *testing.T) {
+	router := &dynamic.Router{
+		EntryPoints: []string{"web"},
+		Middlewares: []string{"auth"},
+		InlineMiddlewares: []dynamic.Middleware{
+			{StripPrefix: &dynamic.StripPrefix{Prefixes: []string{"/api"}}},
+			{AddPrefix: &dynamic.AddPrefix{Prefix: "/v1"}},
+		},
+	}
+
+	given := dynamic.Configurations{
+		"provider-1": &dynamic.Configuration{
+			HTTP: &dynamic.HTTPConfiguration{
+				Routers: map[string]*dynamic.Router{
+					"router-1": router,
+					"router-2": {
+						EntryPoints:       []string{"web"},
+						InlineMiddlewares: []dynamic.Middleware{{AddPrefix: &dynamic.AddPrefix{Prefix: "/v2"}}},
+					},
+				},
+				Middlewares: map[string]*dynamic.Middleware{
+					"auth":              {},
+					"router-2-inline-0": {},
+				},
+			},
+		},
+	}
+
+	// The configuration of the provider is merged on each change, and must not be altered.
+	for i := 0; i < 2; i++ {
+		actual := mergeConfiguration(given, []string{"defaultEP"})
+
+		assert.Equal(t, map[string]*dynamic.Router{
+			"router-1@provider-1": {EntryPoints: []string{"web"}, Middlewares: []string{"auth", "router-1-inline-0", "router-1-inline-1"}},
+		}, actual.HTTP.Routers)
+		assert.Equal(t, map[string]*dynamic.Middleware{
+			"auth@provider-1":              {},
+			"router-2-inline-0@provider-1": {},
+			"router-1-inline-0@provider-1": {StripPrefix: &dynamic.StripPrefix{Prefixes: []string{"/api"}}},
+			"router-1-inline-1@provider-1": {AddPrefix: &dynamic.AddPrefix{Prefix: "/v1"}},
+		}, actual.HTTP.Middlewares)
+		assert.Equal(t, []dynamic.Source{{Provider: "provider-1"}}, actual.Provenance.Middlewares["router-1-inline-0@provider-1"])
+	}
+
+	assert.Equal(t, []string{"auth"}, router.Middlewares)
+	assert.Len(t, router.InlineMiddlewares, 2)
+}
+
 func Test_mergeConfiguration_tlsCertificates(t *testing.T) {
 	testCases := []struct {
 		desc     string
@@ -577,6 +705,55 @@ func Test_applyModel(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with model, one entry point, and cert resolvers per server name",
+			input: dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"tenant": {
+							EntryPoints: []string{"websecure"},
+							Rule:        "Host(`foo.tenant.com`) || Host(`bar.tenant.com`)",
+						},
+						"other": {
+							EntryPoints: []string{"websecure"},
+							Rule:        "Host(`foo.tenant.com`) || Host(`foo.other.com`)",
+						},
+					},
+					Middlewares: make(map[string]*dynamic.Middleware),
+					Services:    make(map[string]*dynamic.Service),
+					Models: map[string]*dynamic.Model{
+						"websecure@internal": {
+							TLS:              &dynamic.RouterTLSConfig{CertResolver: "ep"},
+							SNICertResolvers: []dynamic.SNICertResolver{{SNI: "*.Tenant.com", CertResolver: "tenant"}},
+						},
+					},
+				},
+			},
+			expected: dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"tenant": {
+							EntryPoints: []string{"websecure"},
+							Rule:        "Host(`foo.tenant.com`) || Host(`bar.tenant.com`)",
+							TLS:         &dynamic.RouterTLSConfig{CertResolver: "tenant"},
+						},
+						"other": {
+							EntryPoints: []string{"websecure"},
+							Rule:        "Host(`foo.tenant.com`) || Host(`foo.other.com`)",
+							TLS:         &dynamic.RouterTLSConfig{CertResolver: "ep"},
+						},
+					},
+					Middlewares: make(map[string]*dynamic.Middleware),
+					Services:    make(map[string]*dynamic.Service),
+					Models: map[string]*dynamic.Model{
+						"websecure@internal": {
+							TLS:              &dynamic.RouterTLSConfig{CertResolver: "ep"},
+							SNICertResolvers: []dynamic.SNICertResolver{{SNI: "*.Tenant.com", CertResolver: "tenant"}},
+						},
+					},
+				},
+			},
+		},
 		{
 			desc: "with model, one entry point, and router with tls",
 			input: dynamic.Configuration{
@@ -3,11 +3,15 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"reflect"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/eapache/channels"
 	"github.com/sirupsen/logrus"
+	"github.com/traefik/traefik/v2/pkg/api"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/provider"
@@ -22,16 +26,30 @@ type ConfigurationWatcher struct {
 	defaultEntryPoints []string
 
 	providersThrottleDuration time.Duration
+	globalThrottleDuration    time.Duration
 
 	currentConfigurations safe.Safe
 
 	configurationChan          chan dynamic.Message
 	configurationValidatedChan chan dynamic.Message
 	providerConfigUpdateMap    map[string]chan dynamic.Message
+	// configurationsUpdatedChan notifies, when the global throttling is enabled,
+	// that the current configurations have to be applied.
+	configurationsUpdatedChan chan struct{}
 
 	requiredProvider       string
 	configurationListeners []func(dynamic.Configuration)
 
+	// pausedProviders holds, for each paused provider,
+	// the last configuration received while the provider was paused (nil if none).
+	pausedProviders   map[string]*dynamic.Message
+	pausedProvidersMu sync.Mutex
+
+	// disabledProviders holds, for each provider disabled by a reload of the static configuration,
+	// the last configuration it provided (nil if none).
+	disabledProviders   map[string]*dynamic.Message
+	disabledProvidersMu sync.Mutex
+
 	routinesPool *safe.Pool
 }
 
@@ -40,6 +58,7 @@ func NewConfigurationWatcher(
 	routinesPool *safe.Pool,
 	pvd provider.Provider,
 	providersThrottleDuration time.Duration,
+	globalThrottleDuration time.Duration,
 	defaultEntryPoints []string,
 	requiredProvider string,
 ) *ConfigurationWatcher {
@@ -49,9 +68,13 @@ func NewConfigurationWatcher(
 		configurationValidatedChan: make(chan dynamic.Message, 100),
 		providerConfigUpdateMap:    make(map[string]chan dynamic.Message),
 		providersThrottleDuration:  providersThrottleDuration,
+		globalThrottleDuration:     globalThrottleDuration,
+		configurationsUpdatedChan:  make(chan struct{}, 1),
 		routinesPool:               routinesPool,
 		defaultEntryPoints:         defaultEntryPoints,
 		requiredProvider:           requiredProvider,
+		pausedProviders:            make(map[string]*dynamic.Message),
+		disabledProviders:          make(map[string]*dynamic.Message),
 	}
 
 	currentConfigurations := make(dynamic.Configurations)
@@ -64,6 +87,9 @@ func NewConfigurationWatcher(
 func (c *ConfigurationWatcher) Start() {
 	c.routinesPool.GoCtx(c.listenProviders)
 	c.routinesPool.GoCtx(c.listenConfigurations)
+	if c.globalThrottleDuration > 0 {
+		c.routinesPool.GoCtx(c.throttleConfigurationsApply)
+	}
 	c.startProvider()
 }
 
@@ -81,6 +107,131 @@ func (c *ConfigurationWatcher) AddListener(listener func(dynamic.Configuration))
 	c.configurationListeners = append(c.configurationListeners, listener)
 }
 
+// PauseProvider pauses the given provider:
+// its last applied configuration is kept, and the configurations it provides are held until it is resumed.
+func (c *ConfigurationWatcher) PauseProvider(providerName string) error {
+	currentConfigurations := c.currentConfigurations.Get().(dynamic.Configurations)
+	if _, ok := currentConfigurations[providerName]; !ok {
+		return fmt.Errorf("%w: %s", api.ErrProviderNotFound, providerName)
+	}
+
+	c.pausedProvidersMu.Lock()
+	defer c.pausedProvidersMu.Unlock()
+
+	if _, ok := c.pausedProviders[providerName]; !ok {
+		c.pausedProviders[providerName] = nil
+		log.WithoutContext().WithField(log.ProviderName, providerName).Info("Provider paused")
+	}
+
+	return nil
+}
+
+// ResumeProvider resumes the given provider,
+// applying the last configuration it provided while it was paused.
+func (c *ConfigurationWatcher) ResumeProvider(providerName string) error {
+	c.pausedProvidersMu.Lock()
+	heldMsg, ok := c.pausedProviders[providerName]
+	delete(c.pausedProviders, providerName)
+	c.pausedProvidersMu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%w: %s", api.ErrProviderNotPaused, providerName)
+	}
+
+	log.WithoutContext().WithField(log.ProviderName, providerName).Info("Provider resumed")
+
+	// The lock is released before sending the held message,
+	// as the listener of the channel needs it to load the message.
+	if heldMsg != nil {
+		c.configurationValidatedChan <- *heldMsg
+	}
+
+	return nil
+}
+
+// PausedProviders returns the names of the paused providers.
+func (c *ConfigurationWatcher) PausedProviders() []string {
+	c.pausedProvidersMu.Lock()
+	defer c.pausedProvidersMu.Unlock()
+
+	names := make([]string, 0, len(c.pausedProviders))
+	for name := range c.pausedProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// DisableProvider disables the given provider:
+// its configuration is removed, and the configurations it provides are held until it is enabled again.
+func (c *ConfigurationWatcher) DisableProvider(providerName string) {
+	c.disabledProvidersMu.Lock()
+	if _, ok := c.disabledProviders[providerName]; ok {
+		c.disabledProvidersMu.Unlock()
+		return
+	}
+	c.disabledProviders[providerName] = nil
+	c.disabledProvidersMu.Unlock()
+
+	log.WithoutContext().WithField(log.ProviderName, providerName).Info("Provider disabled")
+
+	// The current configuration of the provider is sent again, to be held and removed by the listener of the channel.
+	currentConfigurations := c.currentConfigurations.Get().(dynamic.Configurations)
+	if conf, ok := currentConfigurations[providerName]; ok {
+		c.configurationValidatedChan <- dynamic.Message{ProviderName: providerName, Configuration: conf.DeepCopy()}
+	}
+}
+
+// EnableProvider enables again the given disabled provider,
+// applying the last configuration it provided.
+func (c *ConfigurationWatcher) EnableProvider(providerName string) {
+	c.disabledProvidersMu.Lock()
+	heldMsg, ok := c.disabledProviders[providerName]
+	delete(c.disabledProviders, providerName)
+	c.disabledProvidersMu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	log.WithoutContext().WithField(log.ProviderName, providerName).Info("Provider enabled")
+
+	if heldMsg != nil {
+		c.configurationValidatedChan <- *heldMsg
+	}
+}
+
+// holdDisabledProviderMessage holds the given message if its provider is disabled, and reports whether it did.
+func (c *ConfigurationWatcher) holdDisabledProviderMessage(configMsg dynamic.Message) bool {
+	c.disabledProvidersMu.Lock()
+	defer c.disabledProvidersMu.Unlock()
+
+	if _, ok := c.disabledProviders[configMsg.ProviderName]; !ok {
+		return false
+	}
+
+	c.disabledProviders[configMsg.ProviderName] = &configMsg
+
+	return true
+}
+
+// holdPausedProviderMessage holds the given message if its provider is paused, and reports whether it did.
+func (c *ConfigurationWatcher) holdPausedProviderMessage(configMsg dynamic.Message) bool {
+	c.pausedProvidersMu.Lock()
+	defer c.pausedProvidersMu.Unlock()
+
+	if _, ok := c.pausedProviders[configMsg.ProviderName]; !ok {
+		return false
+	}
+
+	c.pausedProviders[configMsg.ProviderName] = &configMsg
+	log.WithoutContext().WithField(log.ProviderName, configMsg.ProviderName).
+		Info("Provider is paused, holding its configuration until it is resumed")
+
+	return true
+}
+
 func (c *ConfigurationWatcher) startProvider() {
 	logger := log.WithoutContext()
 
@@ -139,25 +290,87 @@ func (c *ConfigurationWatcher) listenConfigurations(ctx context.Context) {
 }
 
 func (c *ConfigurationWatcher) loadMessage(configMsg dynamic.Message) {
+	if c.holdDisabledProviderMessage(configMsg) {
+		c.removeConfiguration(configMsg.ProviderName)
+		return
+	}
+
+	if c.holdPausedProviderMessage(configMsg) {
+		return
+	}
+
 	currentConfigurations := c.currentConfigurations.Get().(dynamic.Configurations)
 
 	// Copy configurations to new map so we don't change current if LoadConfig fails
 	newConfigurations := currentConfigurations.DeepCopy()
 	newConfigurations[configMsg.ProviderName] = configMsg.Configuration
 
+	c.updateConfigurations(newConfigurations)
+}
+
+// removeConfiguration removes the configuration of the given provider, if any.
+func (c *ConfigurationWatcher) removeConfiguration(providerName string) {
+	currentConfigurations := c.currentConfigurations.Get().(dynamic.Configurations)
+	if _, ok := currentConfigurations[providerName]; !ok {
+		return
+	}
+
+	newConfigurations := currentConfigurations.DeepCopy()
+	delete(newConfigurations, providerName)
+
+	c.updateConfigurations(newConfigurations)
+}
+
+// updateConfigurations sets the current configurations of the providers, and applies them.
+func (c *ConfigurationWatcher) updateConfigurations(newConfigurations dynamic.Configurations) {
 	c.currentConfigurations.Set(newConfigurations)
 
-	conf := mergeConfiguration(newConfigurations, c.defaultEntryPoints)
+	if c.globalThrottleDuration > 0 {
+		// The configurations received before the pending notification is handled are applied together.
+		select {
+		case c.configurationsUpdatedChan <- struct{}{}:
+		default:
+		}
+		return
+	}
+
+	c.applyConfigurations(newConfigurations)
+}
+
+// applyConfigurations merges the given configurations of the providers, and passes the result to the listeners.
+func (c *ConfigurationWatcher) applyConfigurations(configurations dynamic.Configurations) {
+	conf := mergeConfiguration(configurations, c.defaultEntryPoints)
 	conf = applyModel(conf)
 
 	// We wait for first configuration of the require provider before applying configurations.
-	if _, ok := newConfigurations[c.requiredProvider]; c.requiredProvider == "" || ok {
+	if _, ok := configurations[c.requiredProvider]; c.requiredProvider == "" || ok {
 		for _, listener := range c.configurationListeners {
 			listener(conf)
 		}
 	}
 }
 
+// throttleConfigurationsApply applies the current configurations of all the providers when they are updated,
+// at most once per global throttle duration.
+// The first update is applied immediately, and the updates received during the throttle duration
+// are coalesced into a single one, applied at the end of it.
+func (c *ConfigurationWatcher) throttleConfigurationsApply(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.configurationsUpdatedChan:
+			c.applyConfigurations(c.currentConfigurations.Get().(dynamic.Configurations))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(c.globalThrottleDuration):
+			}
+		}
+	}
+}
+
 func (c *ConfigurationWatcher) preLoadConfiguration(configMsg dynamic.Message) {
 	logger := log.WithoutContext().WithField(log.ProviderName, configMsg.ProviderName)
 	if log.GetLevel() == logrus.DebugLevel {
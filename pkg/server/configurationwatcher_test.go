@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/api"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/safe"
 	th "github.com/traefik/traefik/v2/pkg/testhelpers"
@@ -55,7 +57,7 @@ func TestNewConfigurationWatcher(t *testing.T) {
 		}},
 	}
 
-	watcher := NewConfigurationWatcher(routinesPool, pvd, time.Second, []string{}, "")
+	watcher := NewConfigurationWatcher(routinesPool, pvd, time.Second, 0, []string{}, "")
 
 	run := make(chan struct{})
 
@@ -113,7 +115,7 @@ func TestListenProvidersThrottleProviderConfigReload(t *testing.T) {
 		})
 	}
 
-	watcher := NewConfigurationWatcher(routinesPool, pvd, 30*time.Millisecond, []string{}, "")
+	watcher := NewConfigurationWatcher(routinesPool, pvd, 30*time.Millisecond, 0, []string{}, "")
 
 	publishedConfigCount := 0
 	watcher.AddListener(func(_ dynamic.Configuration) {
@@ -131,13 +133,52 @@ func TestListenProvidersThrottleProviderConfigReload(t *testing.T) {
 	assert.Equal(t, 3, publishedConfigCount, "times configs were published")
 }
 
+func TestListenProvidersGlobalThrottle(t *testing.T) {
+	routinesPool := safe.NewPool(context.Background())
+
+	pvd := &mockProvider{
+		wait: 10 * time.Millisecond,
+	}
+
+	for i := 0; i < 5; i++ {
+		pvd.messages = append(pvd.messages, dynamic.Message{
+			ProviderName: "mock" + strconv.Itoa(i),
+			Configuration: &dynamic.Configuration{
+				HTTP: th.BuildConfiguration(
+					th.WithRouters(th.WithRouter("foo")),
+					th.WithLoadBalancerServices(th.WithService("bar")),
+				),
+			},
+		})
+	}
+
+	watcher := NewConfigurationWatcher(routinesPool, pvd, 0, 100*time.Millisecond, []string{}, "")
+
+	var publishedConfigs []dynamic.Configuration
+	watcher.AddListener(func(conf dynamic.Configuration) {
+		publishedConfigs = append(publishedConfigs, conf)
+	})
+
+	watcher.Start()
+	defer watcher.Stop()
+
+	// give some time so that the configuration can be processed
+	time.Sleep(200 * time.Millisecond)
+
+	// the first config is published immediately,
+	// and the 4 configs received from the other providers during the throttle duration are published together.
+	require.Len(t, publishedConfigs, 2)
+	assert.Len(t, publishedConfigs[0].HTTP.Routers, 1)
+	assert.Len(t, publishedConfigs[1].HTTP.Routers, 5)
+}
+
 func TestListenProvidersSkipsEmptyConfigs(t *testing.T) {
 	routinesPool := safe.NewPool(context.Background())
 	pvd := &mockProvider{
 		messages: []dynamic.Message{{ProviderName: "mock"}},
 	}
 
-	watcher := NewConfigurationWatcher(routinesPool, pvd, time.Second, []string{}, "")
+	watcher := NewConfigurationWatcher(routinesPool, pvd, time.Second, 0, []string{}, "")
 	watcher.AddListener(func(_ dynamic.Configuration) {
 		t.Error("An empty configuration was published but it should not")
 	})
@@ -163,7 +204,7 @@ func TestListenProvidersSkipsSameConfigurationForProvider(t *testing.T) {
 		messages: []dynamic.Message{message, message},
 	}
 
-	watcher := NewConfigurationWatcher(routinesPool, pvd, 0, []string{}, "")
+	watcher := NewConfigurationWatcher(routinesPool, pvd, 0, 0, []string{}, "")
 
 	alreadyCalled := false
 	watcher.AddListener(func(_ dynamic.Configuration) {
@@ -206,7 +247,7 @@ func TestListenProvidersDoesNotSkipFlappingConfiguration(t *testing.T) {
 		},
 	}
 
-	watcher := NewConfigurationWatcher(routinesPool, pvd, 15*time.Millisecond, []string{"defaultEP"}, "")
+	watcher := NewConfigurationWatcher(routinesPool, pvd, 15*time.Millisecond, 0, []string{"defaultEP"}, "")
 
 	var lastConfig dynamic.Configuration
 	watcher.AddListener(func(conf dynamic.Configuration) {
@@ -262,7 +303,7 @@ func TestListenProvidersPublishesConfigForEachProvider(t *testing.T) {
 		},
 	}
 
-	watcher := NewConfigurationWatcher(routinesPool, pvd, 0, []string{"defaultEP"}, "")
+	watcher := NewConfigurationWatcher(routinesPool, pvd, 0, 0, []string{"defaultEP"}, "")
 
 	var publishedProviderConfig dynamic.Configuration
 
@@ -330,7 +371,7 @@ func TestPublishConfigUpdatedByProvider(t *testing.T) {
 		},
 	}
 
-	watcher := NewConfigurationWatcher(routinesPool, pvd, 30*time.Millisecond, []string{}, "")
+	watcher := NewConfigurationWatcher(routinesPool, pvd, 30*time.Millisecond, 0, []string{}, "")
 
 	publishedConfigCount := 0
 	watcher.AddListener(func(configuration dynamic.Configuration) {
@@ -378,7 +419,7 @@ func TestPublishConfigUpdatedByConfigWatcherListener(t *testing.T) {
 		},
 	}
 
-	watcher := NewConfigurationWatcher(routinesPool, pvd, 30*time.Millisecond, []string{}, "")
+	watcher := NewConfigurationWatcher(routinesPool, pvd, 30*time.Millisecond, 0, []string{}, "")
 
 	publishedConfigCount := 0
 	watcher.AddListener(func(configuration dynamic.Configuration) {
@@ -397,3 +438,115 @@ func TestPublishConfigUpdatedByConfigWatcherListener(t *testing.T) {
 
 	assert.Equal(t, 1, publishedConfigCount)
 }
+
+func TestPauseResumeProvider(t *testing.T) {
+	routinesPool := safe.NewPool(context.Background())
+	watcher := NewConfigurationWatcher(routinesPool, &mockProvider{}, time.Second, 0, []string{}, "")
+
+	var publishedRouters []string
+	watcher.AddListener(func(conf dynamic.Configuration) {
+		for name := range conf.HTTP.Routers {
+			publishedRouters = append(publishedRouters, name)
+		}
+	})
+
+	newMessage := func(routerName string) dynamic.Message {
+		return dynamic.Message{
+			ProviderName: "mock",
+			Configuration: &dynamic.Configuration{
+				HTTP: th.BuildConfiguration(
+					th.WithRouters(th.WithRouter(routerName)),
+					th.WithLoadBalancerServices(th.WithService("bar")),
+				),
+			},
+		}
+	}
+
+	watcher.loadMessage(newMessage("foo"))
+	assert.Equal(t, []string{"foo@mock"}, publishedRouters)
+
+	err := watcher.PauseProvider("unknown")
+	assert.ErrorIs(t, err, api.ErrProviderNotFound)
+
+	err = watcher.ResumeProvider("mock")
+	assert.ErrorIs(t, err, api.ErrProviderNotPaused)
+
+	err = watcher.PauseProvider("mock")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mock"}, watcher.PausedProviders())
+
+	// The configuration of a paused provider is held.
+	watcher.loadMessage(newMessage("baz"))
+	watcher.loadMessage(newMessage("qux"))
+	assert.Equal(t, []string{"foo@mock"}, publishedRouters)
+
+	err = watcher.ResumeProvider("mock")
+	require.NoError(t, err)
+	assert.Empty(t, watcher.PausedProviders())
+
+	// Only the last held configuration is applied on resume.
+	select {
+	case msg := <-watcher.configurationValidatedChan:
+		assert.Equal(t, newMessage("qux"), msg)
+		watcher.loadMessage(msg)
+	default:
+		t.Fatal("the held configuration has not been sent on resume")
+	}
+
+	assert.Equal(t, []string{"foo@mock", "qux@mock"}, publishedRouters)
+}
+
+func TestDisableEnableProvider(t *testing.T) {
+	routinesPool := safe.NewPool(context.Background())
+	watcher := NewConfigurationWatcher(routinesPool, &mockProvider{}, time.Second, 0, []string{}, "")
+
+	var lastPublishedRouters []string
+	watcher.AddListener(func(conf dynamic.Configuration) {
+		lastPublishedRouters = []string{}
+		for name := range conf.HTTP.Routers {
+			lastPublishedRouters = append(lastPublishedRouters, name)
+		}
+	})
+
+	newMessage := func(routerName string) dynamic.Message {
+		return dynamic.Message{
+			ProviderName: "mock",
+			Configuration: &dynamic.Configuration{
+				HTTP: th.BuildConfiguration(
+					th.WithRouters(th.WithRouter(routerName)),
+					th.WithLoadBalancerServices(th.WithService("bar")),
+				),
+			},
+		}
+	}
+
+	loadSentMessage := func() {
+		select {
+		case msg := <-watcher.configurationValidatedChan:
+			watcher.loadMessage(msg)
+		default:
+			t.Fatal("no configuration has been sent")
+		}
+	}
+
+	watcher.loadMessage(newMessage("foo"))
+	assert.Equal(t, []string{"foo@mock"}, lastPublishedRouters)
+
+	// Enabling a provider which is not disabled is a no-op.
+	watcher.EnableProvider("mock")
+	assert.Empty(t, watcher.configurationValidatedChan)
+
+	// The configuration of a disabled provider is removed.
+	watcher.DisableProvider("mock")
+	loadSentMessage()
+	assert.Empty(t, lastPublishedRouters)
+
+	// The configurations provided while disabled are held.
+	watcher.loadMessage(newMessage("baz"))
+	assert.Empty(t, lastPublishedRouters)
+
+	// The last held configuration is applied when the provider is enabled again.
+	watcher.EnableProvider("mock")
+	loadSentMessage()
+	assert.Equal(t, []string{"baz@mock"}, lastPublishedRouters)
+}
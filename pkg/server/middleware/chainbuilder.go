@@ -8,17 +8,20 @@ import (
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v2/pkg/middlewares/decisionlog"
 	metricsmiddleware "github.com/traefik/traefik/v2/pkg/middlewares/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares/requestdecorator"
 	mTracing "github.com/traefik/traefik/v2/pkg/middlewares/tracing"
 	"github.com/traefik/traefik/v2/pkg/tracing"
 	"github.com/traefik/traefik/v2/pkg/tracing/jaeger"
+	"github.com/traefik/traefik/v2/pkg/types"
 )
 
 // ChainBuilder Creates a middleware chain by entry point. It is used for middlewares that are created almost systematically and that need to be created before all others.
 type ChainBuilder struct {
 	metricsRegistry        metrics.Registry
 	accessLoggerMiddleware *accesslog.Handler
+	decisionLogger         *decisionlog.Handler
 	tracer                 *tracing.Tracing
 	requestDecorator       *requestdecorator.RequestDecorator
 }
@@ -28,6 +31,7 @@ func NewChainBuilder(staticConfiguration static.Configuration, metricsRegistry m
 	return &ChainBuilder{
 		metricsRegistry:        metricsRegistry,
 		accessLoggerMiddleware: accessLoggerMiddleware,
+		decisionLogger:         setupDecisionLog(staticConfiguration.DecisionLog),
 		tracer:                 setupTracing(staticConfiguration.Tracing),
 		requestDecorator:       requestdecorator.New(staticConfiguration.HostResolver),
 	}
@@ -49,10 +53,25 @@ func (c *ChainBuilder) Build(ctx context.Context, entryPointName string) alice.C
 		chain = chain.Append(metricsmiddleware.WrapEntryPointHandler(ctx, c.metricsRegistry, entryPointName))
 	}
 
-	return chain.Append(requestdecorator.WrapHandler(c.requestDecorator))
+	chain = chain.Append(requestdecorator.WrapHandler(c.requestDecorator))
+
+	if c.decisionLogger != nil {
+		chain = chain.Append(decisionlog.WrapHandler(c.decisionLogger, entryPointName))
+	}
+
+	return chain
 }
 
-// Close accessLogger and tracer.
+// RotateDecisionLog closes and reopens the decision log file, if any.
+func (c *ChainBuilder) RotateDecisionLog() error {
+	if c.decisionLogger == nil {
+		return nil
+	}
+
+	return c.decisionLogger.Rotate()
+}
+
+// Close accessLogger, decision logger, and tracer.
 func (c *ChainBuilder) Close() {
 	if c.accessLoggerMiddleware != nil {
 		if err := c.accessLoggerMiddleware.Close(); err != nil {
@@ -60,11 +79,31 @@ func (c *ChainBuilder) Close() {
 		}
 	}
 
+	if c.decisionLogger != nil {
+		if err := c.decisionLogger.Close(); err != nil {
+			log.WithoutContext().Errorf("Could not close the decision log file: %s", err)
+		}
+	}
+
 	if c.tracer != nil {
 		c.tracer.Close()
 	}
 }
 
+func setupDecisionLog(conf *types.DecisionLog) *decisionlog.Handler {
+	if conf == nil {
+		return nil
+	}
+
+	handler, err := decisionlog.NewHandler(conf)
+	if err != nil {
+		log.WithoutContext().Warnf("Unable to create decision logger: %v", err)
+		return nil
+	}
+
+	return handler
+}
+
 func setupTracing(conf *static.Tracing) *tracing.Tracing {
 	if conf == nil {
 		return nil
@@ -8,25 +8,38 @@ import (
 	"strings"
 
 	"github.com/containous/alice"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/middlewares/addprefix"
 	"github.com/traefik/traefik/v2/pkg/middlewares/auth"
+	"github.com/traefik/traefik/v2/pkg/middlewares/botblocker"
 	"github.com/traefik/traefik/v2/pkg/middlewares/buffering"
 	"github.com/traefik/traefik/v2/pkg/middlewares/chain"
 	"github.com/traefik/traefik/v2/pkg/middlewares/circuitbreaker"
 	"github.com/traefik/traefik/v2/pkg/middlewares/compress"
 	"github.com/traefik/traefik/v2/pkg/middlewares/customerrors"
+	"github.com/traefik/traefik/v2/pkg/middlewares/featureflags"
+	"github.com/traefik/traefik/v2/pkg/middlewares/geoip"
 	"github.com/traefik/traefik/v2/pkg/middlewares/headers"
+	"github.com/traefik/traefik/v2/pkg/middlewares/httpcache"
 	"github.com/traefik/traefik/v2/pkg/middlewares/inflightreq"
+	"github.com/traefik/traefik/v2/pkg/middlewares/ipallowlist"
 	"github.com/traefik/traefik/v2/pkg/middlewares/ipwhitelist"
+	"github.com/traefik/traefik/v2/pkg/middlewares/maintenance"
 	"github.com/traefik/traefik/v2/pkg/middlewares/passtlsclientcert"
 	"github.com/traefik/traefik/v2/pkg/middlewares/ratelimiter"
 	"github.com/traefik/traefik/v2/pkg/middlewares/redirect"
 	"github.com/traefik/traefik/v2/pkg/middlewares/replacepath"
 	"github.com/traefik/traefik/v2/pkg/middlewares/replacepathregex"
+	"github.com/traefik/traefik/v2/pkg/middlewares/requestcollapsing"
+	"github.com/traefik/traefik/v2/pkg/middlewares/requestid"
 	"github.com/traefik/traefik/v2/pkg/middlewares/retry"
+	"github.com/traefik/traefik/v2/pkg/middlewares/rewritebody"
+	"github.com/traefik/traefik/v2/pkg/middlewares/stripheaders"
 	"github.com/traefik/traefik/v2/pkg/middlewares/stripprefix"
 	"github.com/traefik/traefik/v2/pkg/middlewares/stripprefixregex"
+	"github.com/traefik/traefik/v2/pkg/middlewares/threatintel"
 	"github.com/traefik/traefik/v2/pkg/middlewares/tracing"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
 )
@@ -35,13 +48,24 @@ type middlewareStackType int
 
 const (
 	middlewareStackKey middlewareStackType = iota
+	routerKey
 )
 
+// AddRouterInContext adds the name of the router the middlewares are built for in the context,
+// to bypass its auth middlewares on demand.
+func AddRouterInContext(ctx context.Context, routerName string) context.Context {
+	return context.WithValue(ctx, routerKey, routerName)
+}
+
 // Builder the middleware builder.
 type Builder struct {
-	configs        map[string]*runtime.MiddlewareInfo
-	pluginBuilder  PluginsBuilder
-	serviceBuilder serviceBuilder
+	configs             map[string]*runtime.MiddlewareInfo
+	pluginBuilder       PluginsBuilder
+	serviceBuilder      serviceBuilder
+	instances           map[string][]*instance
+	authBypasses        *auth.Bypasses
+	httpCaches          *httpcache.Caches
+	maintenanceSwitches *maintenance.Switches
 }
 
 type serviceBuilder interface {
@@ -50,7 +74,27 @@ type serviceBuilder interface {
 
 // NewBuilder creates a new Builder.
 func NewBuilder(configs map[string]*runtime.MiddlewareInfo, serviceBuilder serviceBuilder, pluginBuilder PluginsBuilder) *Builder {
-	return &Builder{configs: configs, serviceBuilder: serviceBuilder, pluginBuilder: pluginBuilder}
+	return &Builder{
+		configs:        configs,
+		serviceBuilder: serviceBuilder,
+		pluginBuilder:  pluginBuilder,
+		instances:      make(map[string][]*instance),
+	}
+}
+
+// SetAuthBypasses sets the bypasses of the auth middlewares of the chains built afterwards.
+func (b *Builder) SetAuthBypasses(authBypasses *auth.Bypasses) {
+	b.authBypasses = authBypasses
+}
+
+// SetHTTPCaches sets the caches the HTTP cache middlewares built afterwards store their responses in.
+func (b *Builder) SetHTTPCaches(httpCaches *httpcache.Caches) {
+	b.httpCaches = httpCaches
+}
+
+// SetMaintenanceSwitches sets the switches of the maintenance middlewares built afterwards.
+func (b *Builder) SetMaintenanceSwitches(switches *maintenance.Switches) {
+	b.maintenanceSwitches = switches
 }
 
 // BuildChain creates a middleware chain.
@@ -62,7 +106,12 @@ func (b *Builder) BuildChain(ctx context.Context, middlewares []string) *alice.C
 		chain = chain.Append(func(next http.Handler) (http.Handler, error) {
 			constructorContext := provider.AddInContext(ctx, middlewareName)
 			if midInf, ok := b.configs[middlewareName]; !ok || midInf.Middleware == nil {
-				return nil, fmt.Errorf("middleware %q does not exist", middlewareName)
+				names := make([]string, 0, len(b.configs))
+				for name := range b.configs {
+					names = append(names, name)
+				}
+
+				return nil, fmt.Errorf("middleware %q does not exist%s", middlewareName, provider.ReferenceHint(middlewareName, names))
 			}
 
 			var err error
@@ -83,12 +132,23 @@ func (b *Builder) BuildChain(ctx context.Context, middlewares []string) *alice.C
 				return nil, err
 			}
 
+			handler = b.register(constructorContext, middlewareName, next, handler)
+
+			if routerName, ok := ctx.Value(routerKey).(string); ok && b.authBypasses != nil && isAuth(b.configs[middlewareName].Middleware) {
+				handler = b.authBypasses.Wrap(routerName, middlewareName, handler, next)
+			}
+
 			return handler, nil
 		})
 	}
 	return &chain
 }
 
+// isAuth reports whether the given middleware is an auth middleware.
+func isAuth(middleware *dynamic.Middleware) bool {
+	return middleware.BasicAuth != nil || middleware.DigestAuth != nil || middleware.ForwardAuth != nil || middleware.HMACAuth != nil || middleware.LDAPAuth != nil || middleware.OIDCAuth != nil
+}
+
 func checkRecursion(ctx context.Context, middlewareName string) (context.Context, error) {
 	currentStack, ok := ctx.Value(middlewareStackKey).([]string)
 	if !ok {
@@ -208,6 +268,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// FeatureFlags
+	if config.FeatureFlags != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return featureflags.New(ctx, next, *config.FeatureFlags, middlewareName)
+		}
+	}
+
 	// ForwardAuth
 	if config.ForwardAuth != nil {
 		if middleware != nil {
@@ -218,6 +288,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// HMACAuth
+	if config.HMACAuth != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return auth.NewHMAC(ctx, next, *config.HMACAuth, middlewareName)
+		}
+	}
+
 	// Headers
 	if config.Headers != nil {
 		if middleware != nil {
@@ -228,8 +308,20 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// HTTPCache
+	if config.HTTPCache != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return httpcache.New(ctx, next, *config.HTTPCache, middlewareName, b.httpCaches)
+		}
+	}
+
 	// IPWhiteList
 	if config.IPWhiteList != nil {
+		log.FromContext(ctx).Warn("IPWhiteList is deprecated, please use IPAllowList instead.")
+
 		if middleware != nil {
 			return nil, badConf
 		}
@@ -238,6 +330,36 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// IPAllowList
+	if config.IPAllowList != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return ipallowlist.New(ctx, next, *config.IPAllowList, middlewareName)
+		}
+	}
+
+	// GeoIP
+	if config.GeoIP != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return geoip.New(ctx, next, *config.GeoIP, middlewareName)
+		}
+	}
+
+	// BotBlocker
+	if config.BotBlocker != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return botblocker.New(ctx, next, *config.BotBlocker, middlewareName)
+		}
+	}
+
 	// InFlightReq
 	if config.InFlightReq != nil {
 		if middleware != nil {
@@ -248,6 +370,36 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// LDAPAuth
+	if config.LDAPAuth != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return auth.NewLDAP(ctx, next, *config.LDAPAuth, middlewareName)
+		}
+	}
+
+	// Maintenance
+	if config.Maintenance != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return maintenance.New(ctx, next, *config.Maintenance, middlewareName, b.maintenanceSwitches)
+		}
+	}
+
+	// OIDCAuth
+	if config.OIDCAuth != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return auth.NewOIDC(ctx, next, *config.OIDCAuth, middlewareName)
+		}
+	}
+
 	// PassTLSClientCert
 	if config.PassTLSClientCert != nil {
 		if middleware != nil {
@@ -308,6 +460,26 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// RequestCollapsing
+	if config.RequestCollapsing != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return requestcollapsing.New(ctx, next, *config.RequestCollapsing, middlewareName)
+		}
+	}
+
+	// RequestID
+	if config.RequestID != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return requestid.New(ctx, next, *config.RequestID, middlewareName)
+		}
+	}
+
 	// Retry
 	if config.Retry != nil {
 		if middleware != nil {
@@ -319,6 +491,26 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// RewriteBody
+	if config.RewriteBody != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return rewritebody.New(ctx, next, *config.RewriteBody, middlewareName)
+		}
+	}
+
+	// StripHeaders
+	if config.StripHeaders != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return stripheaders.New(ctx, next, *config.StripHeaders, middlewareName)
+		}
+	}
+
 	// StripPrefix
 	if config.StripPrefix != nil {
 		if middleware != nil {
@@ -339,6 +531,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// ThreatIntel
+	if config.ThreatIntel != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return threatintel.New(ctx, next, *config.ThreatIntel, middlewareName)
+		}
+	}
+
 	// Plugin
 	if config.Plugin != nil {
 		if middleware != nil {
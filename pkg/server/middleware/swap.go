@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+)
+
+// instance is a middleware instance built by a Builder,
+// with what is needed to build it again with a new configuration.
+type instance struct {
+	ctx      context.Context
+	next     http.Handler
+	switcher *middlewares.HTTPHandlerSwitcher
+}
+
+// register records the given middleware instance, and returns the handler to use in place of it,
+// which allows to swap the instance without rebuilding the chain it belongs to.
+func (b *Builder) register(ctx context.Context, middlewareName string, next, handler http.Handler) http.Handler {
+	if b.instances == nil {
+		b.instances = make(map[string][]*instance)
+	}
+
+	switcher := middlewares.NewHandlerSwitcher(handler)
+	b.instances[middlewareName] = append(b.instances[middlewareName], &instance{ctx: ctx, next: next, switcher: switcher})
+
+	return switcher
+}
+
+// Update rebuilds in place the instances of the given middlewares with their new configuration,
+// so that the routers using them are not rebuilt.
+// If one of the instances cannot be built, Update returns an error without updating any instance.
+func (b *Builder) Update(configs map[string]*dynamic.Middleware) error {
+	previous := make(map[string]*dynamic.Middleware)
+	defer func() {
+		// Restores the previous configurations if the update failed.
+		for name, config := range previous {
+			b.configs[name].Middleware = config
+		}
+	}()
+
+	handlers := make(map[*middlewares.HTTPHandlerSwitcher]http.Handler)
+	for name, config := range configs {
+		midInf, ok := b.configs[name]
+		if !ok || midInf.Middleware == nil {
+			return fmt.Errorf("middleware %q does not exist", name)
+		}
+
+		if midInf.Status == runtime.StatusDisabled {
+			return fmt.Errorf("middleware %q is disabled", name)
+		}
+
+		// The instances of a chain hold instances of the middlewares of the chain,
+		// which would be left behind.
+		if midInf.Chain != nil || config.Chain != nil {
+			return errors.New("chain middlewares cannot be updated in place")
+		}
+
+		previous[name] = midInf.Middleware
+		midInf.Middleware = config
+
+		for _, inst := range b.instances[name] {
+			constructor, err := b.buildConstructor(inst.ctx, name)
+			if err != nil {
+				return fmt.Errorf("middleware %q: %w", name, err)
+			}
+
+			handler, err := constructor(inst.next)
+			if err != nil {
+				return fmt.Errorf("middleware %q: %w", name, err)
+			}
+
+			handlers[inst.switcher] = handler
+		}
+	}
+
+	for switcher, handler := range handlers {
+		switcher.UpdateHandler(handler)
+	}
+
+	previous = nil
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+)
+
+func TestBuilder_Update(t *testing.T) {
+	configs := map[string]*runtime.MiddlewareInfo{
+		"headers": {
+			Middleware: &dynamic.Middleware{
+				Headers: &dynamic.Headers{
+					CustomRequestHeaders: map[string]string{"X-Foo": "foo"},
+				},
+			},
+			Status: runtime.StatusEnabled,
+		},
+	}
+	builder := NewBuilder(configs, nil, nil)
+
+	var calls int
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+		rw.Header().Set("X-Foo", req.Header.Get("X-Foo"))
+	})
+
+	handler, err := builder.BuildChain(context.Background(), []string{"headers"}).Then(next)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+	assert.Equal(t, "foo", recorder.Header().Get("X-Foo"))
+
+	err = builder.Update(map[string]*dynamic.Middleware{
+		"headers": {
+			Headers: &dynamic.Headers{
+				CustomRequestHeaders: map[string]string{"X-Foo": "bar"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	// The chain built before the update uses the new configuration, and still calls the same next handler.
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+	assert.Equal(t, "bar", recorder.Header().Get("X-Foo"))
+	assert.Equal(t, 2, calls)
+
+	assert.Equal(t, "bar", configs["headers"].Headers.CustomRequestHeaders["X-Foo"])
+}
+
+func TestBuilder_Update_error(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		configs map[string]*dynamic.Middleware
+	}{
+		{
+			desc: "unknown middleware",
+			configs: map[string]*dynamic.Middleware{
+				"unknown": {
+					AddPrefix: &dynamic.AddPrefix{Prefix: "/foo"},
+				},
+			},
+		},
+		{
+			desc: "invalid configuration",
+			configs: map[string]*dynamic.Middleware{
+				"headers": {
+					IPWhiteList: &dynamic.IPWhiteList{SourceRange: []string{"foo"}},
+				},
+			},
+		},
+		{
+			desc: "chain",
+			configs: map[string]*dynamic.Middleware{
+				"headers": {
+					Chain: &dynamic.Chain{Middlewares: []string{"foo"}},
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			middleware := &dynamic.Middleware{
+				Headers: &dynamic.Headers{
+					CustomRequestHeaders: map[string]string{"X-Foo": "foo"},
+				},
+			}
+			configs := map[string]*runtime.MiddlewareInfo{
+				"headers": {Middleware: middleware, Status: runtime.StatusEnabled},
+			}
+			builder := NewBuilder(configs, nil, nil)
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("X-Foo", req.Header.Get("X-Foo"))
+			})
+
+			handler, err := builder.BuildChain(context.Background(), []string{"headers"}).Then(next)
+			require.NoError(t, err)
+
+			err = builder.Update(test.configs)
+			require.Error(t, err)
+
+			// The instance and the configuration are left untouched.
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+			assert.Equal(t, "foo", recorder.Header().Get("X-Foo"))
+
+			assert.Same(t, middleware, configs["headers"].Middleware)
+		})
+	}
+}
@@ -2,10 +2,13 @@ package tcpmiddleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/log"
+	ipallowlist "github.com/traefik/traefik/v2/pkg/middlewares/tcp/ipallowlist"
 	ipwhitelist "github.com/traefik/traefik/v2/pkg/middlewares/tcp/ipwhitelist"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
 	"github.com/traefik/traefik/v2/pkg/tcp"
@@ -37,7 +40,12 @@ func (b *Builder) BuildChain(ctx context.Context, middlewares []string) *tcp.Cha
 		chain = chain.Append(func(next tcp.Handler) (tcp.Handler, error) {
 			constructorContext := provider.AddInContext(ctx, middlewareName)
 			if midInf, ok := b.configs[middlewareName]; !ok || midInf.TCPMiddleware == nil {
-				return nil, fmt.Errorf("middleware %q does not exist", middlewareName)
+				names := make([]string, 0, len(b.configs))
+				for name := range b.configs {
+					names = append(names, name)
+				}
+
+				return nil, fmt.Errorf("middleware %q does not exist%s", middlewareName, provider.ReferenceHint(middlewareName, names))
 			}
 
 			var err error
@@ -84,15 +92,29 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		return nil, fmt.Errorf("invalid middleware %q configuration", middlewareName)
 	}
 
+	badConf := errors.New("cannot create middleware: multi-types middleware not supported, consider declaring two different pieces of middleware instead")
+
 	var middleware tcp.Constructor
 
 	// IPWhiteList
 	if config.IPWhiteList != nil {
+		log.FromContext(ctx).Warn("IPWhiteList is deprecated, please use IPAllowList instead.")
+
 		middleware = func(next tcp.Handler) (tcp.Handler, error) {
 			return ipwhitelist.New(ctx, next, *config.IPWhiteList, middlewareName)
 		}
 	}
 
+	// IPAllowList
+	if config.IPAllowList != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next tcp.Handler) (tcp.Handler, error) {
+			return ipallowlist.New(ctx, next, *config.IPAllowList, middlewareName)
+		}
+	}
+
 	if middleware == nil {
 		return nil, fmt.Errorf("invalid middleware %q configuration: invalid middleware type or middleware does not exist", middlewareName)
 	}
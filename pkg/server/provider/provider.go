@@ -2,6 +2,9 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/traefik/traefik/v2/pkg/log"
@@ -43,3 +46,26 @@ func GetQualifiedName(ctx context.Context, elementName string) string {
 func MakeQualifiedName(providerName, elementName string) string {
 	return elementName + "@" + providerName
 }
+
+// ReferenceHint returns, for the reference to an element which does not exist,
+// a hint naming the elements with the same name defined by other providers, among the given qualified names.
+// It returns an empty string if there is none.
+func ReferenceHint(qualifiedName string, names []string) string {
+	elementName := strings.SplitN(qualifiedName, "@", 2)[0]
+
+	var candidates []string
+	for _, name := range names {
+		parts := strings.SplitN(name, "@", 2)
+		if name != qualifiedName && len(parts) == 2 && parts[0] == elementName {
+			candidates = append(candidates, strconv.Quote(name))
+		}
+	}
+
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	sort.Strings(candidates)
+
+	return fmt.Sprintf(" (did you mean %s?)", strings.Join(candidates, " or "))
+}
@@ -113,3 +113,46 @@ func TestGetQualifiedName(t *testing.T) {
 		})
 	}
 }
+
+func TestReferenceHint(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		qualifiedName string
+		names         []string
+		expected      string
+	}{
+		{
+			desc:          "no element with the same name",
+			qualifiedName: "auth@kubernetescrd",
+			names:         []string{"foo@file", "bar@docker"},
+			expected:      "",
+		},
+		{
+			desc:          "one element with the same name",
+			qualifiedName: "auth@kubernetescrd",
+			names:         []string{"foo@file", "auth@file"},
+			expected:      ` (did you mean "auth@file"?)`,
+		},
+		{
+			desc:          "several elements with the same name",
+			qualifiedName: "auth@kubernetescrd",
+			names:         []string{"auth@file", "authz@file", "auth@docker"},
+			expected:      ` (did you mean "auth@docker" or "auth@file"?)`,
+		},
+		{
+			desc:          "unqualified element name",
+			qualifiedName: "auth",
+			names:         []string{"auth@file"},
+			expected:      ` (did you mean "auth@file"?)`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, ReferenceHint(test.qualifiedName, test.names))
+		})
+	}
+}
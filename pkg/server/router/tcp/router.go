@@ -241,7 +241,7 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 			continue
 		}
 
-		handler, err := m.buildTCPHandler(ctxRouter, routerConfig)
+		handler, err := m.buildTCPHandler(tcpservice.AddRouterInContext(ctxRouter, routerName), routerConfig)
 		if err != nil {
 			routerConfig.AddError(err, true)
 			logger.Error(err)
@@ -2,11 +2,16 @@ package server
 
 import (
 	"context"
+	"reflect"
 
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/grpcreflection"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/rules"
+	"github.com/traefik/traefik/v2/pkg/safe"
 	"github.com/traefik/traefik/v2/pkg/server/middleware"
 	middlewaretcp "github.com/traefik/traefik/v2/pkg/server/middleware/tcp"
 	"github.com/traefik/traefik/v2/pkg/server/router"
@@ -32,6 +37,15 @@ type RouterFactory struct {
 
 	chainBuilder *middleware.ChainBuilder
 	tlsManager   *tls.Manager
+
+	// conf is the last configuration given to UpdateMiddlewares.
+	conf *dynamic.Configuration
+	// rtConf and middlewaresBuilder are the ones of the last created HTTP routers.
+	rtConf             *runtime.Configuration
+	middlewaresBuilder *middleware.Builder
+
+	// cancelGRPCValidation cancels the validation of the gRPC routes of the last created HTTP routers.
+	cancelGRPCValidation context.CancelFunc
 }
 
 // NewRouterFactory creates a new RouterFactory.
@@ -71,6 +85,11 @@ func (f *RouterFactory) CreateRouters(rtConf *runtime.Configuration) (map[string
 	serviceManager := f.managerFactory.Build(rtConf)
 
 	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, f.pluginBuilder)
+	middlewaresBuilder.SetAuthBypasses(f.managerFactory.AuthBypasses())
+	middlewaresBuilder.SetHTTPCaches(f.managerFactory.HTTPCaches())
+	middlewaresBuilder.SetMaintenanceSwitches(f.managerFactory.MaintenanceSwitches())
+	f.rtConf = rtConf
+	f.middlewaresBuilder = middlewaresBuilder
 
 	routerManager := router.NewManager(rtConf, serviceManager, middlewaresBuilder, f.chainBuilder, f.metricsRegistry)
 
@@ -80,7 +99,7 @@ func (f *RouterFactory) CreateRouters(rtConf *runtime.Configuration) (map[string
 	serviceManager.LaunchHealthCheck()
 
 	// TCP
-	svcTCPManager := tcp.NewManager(rtConf)
+	svcTCPManager := tcp.NewManager(rtConf, f.metricsRegistry)
 
 	middlewaresTCPBuilder := middlewaretcp.NewBuilder(rtConf.TCPMiddlewares)
 
@@ -94,5 +113,99 @@ func (f *RouterFactory) CreateRouters(rtConf *runtime.Configuration) (map[string
 
 	rtConf.PopulateUsedBy()
 
+	f.validateGRPCRoutes(rtConf)
+
+	// Drops the rules and the HTTP caches which were only used by the previous configuration.
+	rules.SweepCache()
+	f.managerFactory.HTTPCaches().Sweep()
+
 	return routersTCP, routersUDP
 }
+
+// validateGRPCRoutes validates in the background the gRPC routes of the given routers,
+// and cancels the validation of the previous ones.
+func (f *RouterFactory) validateGRPCRoutes(rtConf *runtime.Configuration) {
+	if f.cancelGRPCValidation != nil {
+		f.cancelGRPCValidation()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f.cancelGRPCValidation = cancel
+
+	safe.Go(func() {
+		grpcreflection.Validate(ctx, rtConf)
+	})
+}
+
+// UpdateMiddlewares updates in place the middlewares of the HTTP routers created by the last call to CreateRouters,
+// when the given configuration differs from the previous one only by the options of existing middlewares.
+// This way, the routers, and the state of their services, are kept.
+// It returns false when the routers have to be created from the given configuration instead.
+func (f *RouterFactory) UpdateMiddlewares(conf dynamic.Configuration) bool {
+	previous := f.conf
+	f.conf = conf.DeepCopy()
+
+	if previous == nil || f.middlewaresBuilder == nil {
+		return false
+	}
+
+	changed, ok := changedMiddlewares(previous, f.conf)
+	if !ok || len(changed) == 0 {
+		return false
+	}
+
+	if err := f.middlewaresBuilder.Update(changed); err != nil {
+		log.WithoutContext().Debugf("Cannot update the middlewares in place, the routers are created again: %v", err)
+		return false
+	}
+
+	for name := range changed {
+		f.rtConf.Middlewares[name].Sources = nil
+		if f.conf.Provenance != nil {
+			f.rtConf.Middlewares[name].Sources = f.conf.Provenance.Middlewares[name]
+		}
+	}
+
+	return true
+}
+
+// changedMiddlewares returns the HTTP middlewares of the next configuration which differ from the previous one.
+// It returns false if anything else differs, including the set of middlewares.
+func changedMiddlewares(previous, next *dynamic.Configuration) (map[string]*dynamic.Middleware, bool) {
+	if previous.HTTP == nil || next.HTTP == nil || len(previous.HTTP.Middlewares) != len(next.HTTP.Middlewares) {
+		return nil, false
+	}
+
+	changed := make(map[string]*dynamic.Middleware)
+	for name, middleware := range next.HTTP.Middlewares {
+		previousMiddleware, ok := previous.HTTP.Middlewares[name]
+		if !ok {
+			return nil, false
+		}
+
+		if !reflect.DeepEqual(previousMiddleware, middleware) {
+			changed[name] = middleware
+		}
+	}
+
+	if !reflect.DeepEqual(withoutMiddlewares(*previous), withoutMiddlewares(*next)) {
+		return nil, false
+	}
+
+	return changed, true
+}
+
+// withoutMiddlewares returns a shallow copy of the given configuration without its HTTP middlewares.
+func withoutMiddlewares(conf dynamic.Configuration) dynamic.Configuration {
+	httpConf := *conf.HTTP
+	httpConf.Middlewares = nil
+	conf.HTTP = &httpConf
+
+	if conf.Provenance != nil {
+		provenance := *conf.Provenance
+		provenance.Middlewares = nil
+		conf.Provenance = &provenance
+	}
+
+	return conf
+}
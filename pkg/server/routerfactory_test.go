@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/config/static"
@@ -50,7 +51,7 @@ func TestReuseService(t *testing.T) {
 
 	roundTripperManager := service.NewRoundTripperManager()
 	roundTripperManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
-	managerFactory := service.NewManagerFactory(staticConfig, nil, metrics.NewVoidRegistry(), roundTripperManager, nil)
+	managerFactory := service.NewManagerFactory(staticConfig, nil, metrics.NewVoidRegistry(), roundTripperManager, nil, nil, nil, nil)
 	tlsManager := tls.NewManager()
 
 	factory := NewRouterFactory(staticConfig, managerFactory, tlsManager, middleware.NewChainBuilder(staticConfig, metrics.NewVoidRegistry(), nil), nil, metrics.NewVoidRegistry())
@@ -186,7 +187,7 @@ func TestServerResponseEmptyBackend(t *testing.T) {
 
 			roundTripperManager := service.NewRoundTripperManager()
 			roundTripperManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
-			managerFactory := service.NewManagerFactory(staticConfig, nil, metrics.NewVoidRegistry(), roundTripperManager, nil)
+			managerFactory := service.NewManagerFactory(staticConfig, nil, metrics.NewVoidRegistry(), roundTripperManager, nil, nil, nil, nil)
 			tlsManager := tls.NewManager()
 
 			factory := NewRouterFactory(staticConfig, managerFactory, tlsManager, middleware.NewChainBuilder(staticConfig, metrics.NewVoidRegistry(), nil), nil, metrics.NewVoidRegistry())
@@ -227,7 +228,7 @@ func TestInternalServices(t *testing.T) {
 
 	roundTripperManager := service.NewRoundTripperManager()
 	roundTripperManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
-	managerFactory := service.NewManagerFactory(staticConfig, nil, metrics.NewVoidRegistry(), roundTripperManager, nil)
+	managerFactory := service.NewManagerFactory(staticConfig, nil, metrics.NewVoidRegistry(), roundTripperManager, nil, nil, nil, nil)
 	tlsManager := tls.NewManager()
 
 	voidRegistry := metrics.NewVoidRegistry()
@@ -243,3 +244,68 @@ func TestInternalServices(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, responseRecorderOk.Result().StatusCode, "status code")
 }
+
+func TestRouterFactory_UpdateMiddlewares(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer testServer.Close()
+
+	staticConfig := static.Configuration{
+		EntryPoints: map[string]*static.EntryPoint{
+			"web": {},
+		},
+	}
+
+	buildConfiguration := func(rule, headerValue string) dynamic.Configuration {
+		return dynamic.Configuration{
+			HTTP: th.BuildConfiguration(
+				th.WithRouters(
+					th.WithRouter("foo",
+						th.WithEntryPoints("web"),
+						th.WithServiceName("bar"),
+						th.WithRule(rule),
+						th.WithRouterMiddlewares("headers")),
+				),
+				th.WithMiddlewares(th.WithMiddleware("headers", func(middleware *dynamic.Middleware) {
+					middleware.Headers = &dynamic.Headers{
+						CustomResponseHeaders: map[string]string{"X-Foo": headerValue},
+					}
+				})),
+				th.WithLoadBalancerServices(th.WithService("bar",
+					th.WithServers(th.WithServer(testServer.URL))),
+				),
+			),
+		}
+	}
+
+	roundTripperManager := service.NewRoundTripperManager()
+	roundTripperManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
+	managerFactory := service.NewManagerFactory(staticConfig, nil, metrics.NewVoidRegistry(), roundTripperManager, nil, nil, nil, nil)
+	tlsManager := tls.NewManager()
+
+	factory := NewRouterFactory(staticConfig, managerFactory, tlsManager, middleware.NewChainBuilder(staticConfig, metrics.NewVoidRegistry(), nil), nil, metrics.NewVoidRegistry())
+
+	conf := buildConfiguration("Path(`/ok`)", "foo")
+	require.False(t, factory.UpdateMiddlewares(conf))
+
+	rtConf := runtime.NewConfig(conf)
+	entryPointsHandlers, _ := factory.CreateRouters(rtConf)
+	handler := entryPointsHandlers["web"].GetHTTPHandler()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, testServer.URL+"/ok", nil))
+	assert.Equal(t, "foo", recorder.Header().Get("X-Foo"))
+
+	// Only the middleware options changed, the existing router uses the new ones.
+	require.True(t, factory.UpdateMiddlewares(buildConfiguration("Path(`/ok`)", "bar")))
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, testServer.URL+"/ok", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "bar", recorder.Header().Get("X-Foo"))
+	assert.Equal(t, "bar", rtConf.Middlewares["headers"].Headers.CustomResponseHeaders["X-Foo"])
+
+	// The router changed, so the routers have to be created again.
+	assert.False(t, factory.UpdateMiddlewares(buildConfiguration("Path(`/other`)", "bar")))
+}
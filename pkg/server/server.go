@@ -7,6 +7,7 @@ import (
 	"os/signal"
 	"time"
 
+	"github.com/traefik/traefik/v2/pkg/config/static"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
@@ -23,6 +24,9 @@ type Server struct {
 
 	accessLoggerMiddleware *accesslog.Handler
 
+	staticConfiguration       *static.Configuration
+	staticConfigurationLoader StaticConfigurationLoader
+
 	signals  chan os.Signal
 	stopChan chan bool
 
@@ -7,6 +7,8 @@ import (
 	stdlog "log"
 	"net"
 	"net/http"
+	"reflect"
+	"sort"
 	"sync"
 	"syscall"
 	"time"
@@ -17,6 +19,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/ip"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/fairqueuing"
 	"github.com/traefik/traefik/v2/pkg/middlewares/forwardedheaders"
 	"github.com/traefik/traefik/v2/pkg/safe"
 	"github.com/traefik/traefik/v2/pkg/server/router"
@@ -27,6 +30,9 @@ import (
 
 var httpServerLogger = stdlog.New(log.WithoutContext().WriterLevel(logrus.DebugLevel), "", 0)
 
+// errListenerDetached is returned by a forwarderListener, when its server has been replaced.
+var errListenerDetached = errors.New("listener detached")
+
 type httpForwarder struct {
 	net.Listener
 	connChan chan net.Conn
@@ -56,6 +62,60 @@ func (h *httpForwarder) Accept() (net.Conn, error) {
 	}
 }
 
+// forwarderListener is the listener of an HTTP server, accepting the connections of an httpForwarder,
+// until it is detached because the server has been replaced.
+type forwarderListener struct {
+	*httpForwarder
+
+	detached   chan struct{}
+	detachOnce sync.Once
+	// served is closed when the server has stopped serving the listener.
+	served chan struct{}
+}
+
+func newForwarderListener(forwarder *httpForwarder) *forwarderListener {
+	return &forwarderListener{
+		httpForwarder: forwarder,
+		detached:      make(chan struct{}),
+		served:        make(chan struct{}),
+	}
+}
+
+// Accept retrieves a served connection in ServeTCP, until the listener is detached.
+func (l *forwarderListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.connChan:
+		return conn, nil
+	case err := <-l.errChan:
+		select {
+		case <-l.detached:
+			// The error is meant for the server which replaced this one.
+			go func() { l.errChan <- err }()
+			return nil, errListenerDetached
+		default:
+			return nil, err
+		}
+	case <-l.detached:
+		return nil, errListenerDetached
+	}
+}
+
+// Close closes the underlying listener, unless the listener is detached,
+// as the underlying listener is then used by the server which replaced this one.
+func (l *forwarderListener) Close() error {
+	select {
+	case <-l.detached:
+		return nil
+	default:
+		return l.httpForwarder.Close()
+	}
+}
+
+// detach stops accepting the connections, without closing the underlying listener.
+func (l *forwarderListener) detach() {
+	l.detachOnce.Do(func() { close(l.detached) })
+}
+
 // TCPEntryPoints holds a map of TCPEntryPoint (the entrypoint names being the keys).
 type TCPEntryPoints map[string]*TCPEntryPoint
 
@@ -91,23 +151,55 @@ func (eps TCPEntryPoints) Start() {
 }
 
 // Stop the server entry points.
+// The entry points are stopped by decreasing shutdown priority:
+// the entry points sharing a priority are stopped concurrently, once the ones with a higher priority are stopped.
 func (eps TCPEntryPoints) Stop() {
-	var wg sync.WaitGroup
+	for _, group := range eps.shutdownGroups() {
+		var wg sync.WaitGroup
 
-	for epn, ep := range eps {
-		wg.Add(1)
+		for _, epn := range group {
+			wg.Add(1)
 
-		go func(entryPointName string, entryPoint *TCPEntryPoint) {
-			defer wg.Done()
+			go func(entryPointName string, entryPoint *TCPEntryPoint) {
+				defer wg.Done()
 
-			ctx := log.With(context.Background(), log.Str(log.EntryPointName, entryPointName))
-			entryPoint.Shutdown(ctx)
+				ctx := log.With(context.Background(), log.Str(log.EntryPointName, entryPointName))
+				entryPoint.Shutdown(ctx)
 
-			log.FromContext(ctx).Debugf("Entry point %s closed", entryPointName)
-		}(epn, ep)
+				log.FromContext(ctx).Debugf("Entry point %s closed", entryPointName)
+			}(epn, eps[epn])
+		}
+
+		wg.Wait()
 	}
+}
 
-	wg.Wait()
+// shutdownGroups returns the names of the entry points, grouped by shutdown priority, by decreasing priority.
+func (eps TCPEntryPoints) shutdownGroups() [][]string {
+	byPriority := make(map[int][]string)
+	for name, entryPoint := range eps {
+		var priority int
+		if transport := entryPoint.transport(); transport != nil && transport.LifeCycle != nil {
+			priority = transport.LifeCycle.ShutdownPriority
+		}
+
+		byPriority[priority] = append(byPriority[priority], name)
+	}
+
+	priorities := make([]int, 0, len(byPriority))
+	for priority := range byPriority {
+		priorities = append(priorities, priority)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(priorities)))
+
+	groups := make([][]string, 0, len(priorities))
+	for _, priority := range priorities {
+		names := byPriority[priority]
+		sort.Strings(names)
+		groups = append(groups, names)
+	}
+
+	return groups
 }
 
 // Switch the TCP routers.
@@ -122,11 +214,15 @@ type TCPEntryPoint struct {
 	listener               net.Listener
 	switcher               *tcp.HandlerSwitcher
 	transportConfiguration *static.EntryPointsTransport
+	transportMu            sync.RWMutex
 	tracker                *connectionTracker
 	httpServer             *httpServer
 	httpsServer            *httpServer
 
 	http3Server *http3server
+
+	sourceIPHandshakeLimiter   *tcp.RateLimiter
+	serverNameHandshakeLimiter *tcp.RateLimiter
 }
 
 // NewTCPEntryPoint creates a new TCPEntryPoint.
@@ -138,7 +234,13 @@ func NewTCPEntryPoint(ctx context.Context, configuration *static.EntryPoint) (*T
 		return nil, fmt.Errorf("error preparing server: %w", err)
 	}
 
+	sourceIPHandshakeLimiter, serverNameHandshakeLimiter, err := buildHandshakeRateLimiters(configuration.TLSHandshakes)
+	if err != nil {
+		return nil, fmt.Errorf("error preparing TLS handshake rate limiters: %w", err)
+	}
+
 	rt := &tcp.Router{}
+	rt.SetHandshakeRateLimiters(sourceIPHandshakeLimiter, serverNameHandshakeLimiter)
 
 	httpServer, err := createHTTPServer(ctx, listener, configuration, true)
 	if err != nil {
@@ -170,9 +272,40 @@ func NewTCPEntryPoint(ctx context.Context, configuration *static.EntryPoint) (*T
 		httpServer:             httpServer,
 		httpsServer:            httpsServer,
 		http3Server:            h3server,
+
+		sourceIPHandshakeLimiter:   sourceIPHandshakeLimiter,
+		serverNameHandshakeLimiter: serverNameHandshakeLimiter,
 	}, nil
 }
 
+// buildHandshakeRateLimiters creates the rate limiters of the TLS handshakes, per source IP and per SNI.
+// The rate limiters are owned by the entry point, so their state is kept across configuration reloads.
+func buildHandshakeRateLimiters(config *static.TLSHandshakes) (*tcp.RateLimiter, *tcp.RateLimiter, error) {
+	if config == nil {
+		return nil, nil, nil
+	}
+
+	build := func(limit *static.HandshakeRateLimit) (*tcp.RateLimiter, error) {
+		if limit == nil || limit.Average <= 0 {
+			return nil, nil
+		}
+
+		return tcp.NewRateLimiter(limit.Average, limit.Burst)
+	}
+
+	sourceIP, err := build(config.PerSourceIP)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serverName, err := build(config.PerServerName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sourceIP, serverName, nil
+}
+
 // Start starts the TCP server.
 func (e *TCPEntryPoint) Start(ctx context.Context) {
 	logger := log.FromContext(ctx)
@@ -203,19 +336,21 @@ func (e *TCPEntryPoint) Start(ctx context.Context) {
 			panic(err)
 		}
 
+		transport := e.transport()
+
 		safe.Go(func() {
 			// Enforce read/write deadlines at the connection level,
 			// because when we're peeking the first byte to determine whether we are doing TLS,
 			// the deadlines at the server level are not taken into account.
-			if e.transportConfiguration.RespondingTimeouts.ReadTimeout > 0 {
-				err := writeCloser.SetReadDeadline(time.Now().Add(time.Duration(e.transportConfiguration.RespondingTimeouts.ReadTimeout)))
+			if transport.RespondingTimeouts.ReadTimeout > 0 {
+				err := writeCloser.SetReadDeadline(time.Now().Add(time.Duration(transport.RespondingTimeouts.ReadTimeout)))
 				if err != nil {
 					logger.Errorf("Error while setting read deadline: %v", err)
 				}
 			}
 
-			if e.transportConfiguration.RespondingTimeouts.WriteTimeout > 0 {
-				err = writeCloser.SetWriteDeadline(time.Now().Add(time.Duration(e.transportConfiguration.RespondingTimeouts.WriteTimeout)))
+			if transport.RespondingTimeouts.WriteTimeout > 0 {
+				err = writeCloser.SetWriteDeadline(time.Now().Add(time.Duration(transport.RespondingTimeouts.WriteTimeout)))
 				if err != nil {
 					logger.Errorf("Error while setting write deadline: %v", err)
 				}
@@ -230,13 +365,15 @@ func (e *TCPEntryPoint) Start(ctx context.Context) {
 func (e *TCPEntryPoint) Shutdown(ctx context.Context) {
 	logger := log.FromContext(ctx)
 
-	reqAcceptGraceTimeOut := time.Duration(e.transportConfiguration.LifeCycle.RequestAcceptGraceTimeout)
+	transport := e.transport()
+
+	reqAcceptGraceTimeOut := time.Duration(transport.LifeCycle.RequestAcceptGraceTimeout)
 	if reqAcceptGraceTimeOut > 0 {
 		logger.Infof("Waiting %s for incoming requests to cease", reqAcceptGraceTimeOut)
 		time.Sleep(reqAcceptGraceTimeOut)
 	}
 
-	graceTimeOut := time.Duration(e.transportConfiguration.LifeCycle.GraceTimeOut)
+	graceTimeOut := time.Duration(transport.LifeCycle.GraceTimeOut)
 	ctx, cancel := context.WithTimeout(ctx, graceTimeOut)
 	logger.Debugf("Waiting %s seconds before killing connections.", graceTimeOut)
 
@@ -261,14 +398,14 @@ func (e *TCPEntryPoint) Shutdown(ctx context.Context) {
 		server.Close()
 	}
 
-	if e.httpServer.Server != nil {
+	if server := e.httpServer.server(); server != nil {
 		wg.Add(1)
-		go shutdownServer(e.httpServer.Server)
+		go shutdownServer(server)
 	}
 
-	if e.httpsServer.Server != nil {
+	if server := e.httpsServer.server(); server != nil {
 		wg.Add(1)
-		go shutdownServer(e.httpsServer.Server)
+		go shutdownServer(server)
 
 		if e.http3Server != nil {
 			wg.Add(1)
@@ -295,8 +432,36 @@ func (e *TCPEntryPoint) Shutdown(ctx context.Context) {
 	cancel()
 }
 
+// transport returns the current transport configuration of the entry point.
+func (e *TCPEntryPoint) transport() *static.EntryPointsTransport {
+	e.transportMu.RLock()
+	defer e.transportMu.RUnlock()
+
+	return e.transportConfiguration
+}
+
+// UpdateTransport updates the transport configuration of the entry point, without dropping the connections:
+// the new connections use the new responding timeouts, while the current ones are gracefully ended with the previous ones.
+// The responding timeouts of the HTTP/3 server are not updated.
+func (e *TCPEntryPoint) UpdateTransport(ctx context.Context, transport *static.EntryPointsTransport) {
+	e.transportMu.Lock()
+	previous := e.transportConfiguration
+	e.transportConfiguration = transport
+	e.transportMu.Unlock()
+
+	if reflect.DeepEqual(previous.RespondingTimeouts, transport.RespondingTimeouts) {
+		return
+	}
+
+	graceTimeOut := time.Duration(transport.LifeCycle.GraceTimeOut)
+	e.httpServer.setRespondingTimeouts(ctx, transport.RespondingTimeouts, graceTimeOut)
+	e.httpsServer.setRespondingTimeouts(ctx, transport.RespondingTimeouts, graceTimeOut)
+}
+
 // SwitchRouter switches the TCP router handler.
 func (e *TCPEntryPoint) SwitchRouter(rt *tcp.Router) {
+	rt.SetHandshakeRateLimiters(e.sourceIPHandshakeLimiter, e.serverNameHandshakeLimiter)
+
 	rt.HTTPForwarder(e.httpServer.Forwarder)
 
 	httpHandler := rt.GetHTTPHandler()
@@ -498,17 +663,90 @@ type httpServer struct {
 	Server    stoppableServer
 	Forwarder *httpForwarder
 	Switcher  *middlewares.HTTPHandlerSwitcher
+
+	// mu protects Server and listener, which are replaced when the responding timeouts are updated.
+	mu       sync.RWMutex
+	listener *forwarderListener
+}
+
+func (h *httpServer) server() stoppableServer {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.Server
+}
+
+// serve serves the connections of the forwarder with the given server, which becomes the current one.
+// It must be called with the lock held.
+func (h *httpServer) serve(ctx context.Context, server *http.Server) {
+	listener := newForwarderListener(h.Forwarder)
+
+	h.Server = server
+	h.listener = listener
+
+	go func() {
+		defer close(listener.served)
+
+		err := server.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) && !errors.Is(err, errListenerDetached) {
+			log.FromContext(ctx).Errorf("Error while starting server: %v", err)
+		}
+	}()
+}
+
+// setRespondingTimeouts replaces the server with a server using the given responding timeouts for the new connections,
+// and gracefully shuts down the previous server within the given grace timeout.
+func (h *httpServer) setRespondingTimeouts(ctx context.Context, timeouts *static.RespondingTimeouts, graceTimeOut time.Duration) {
+	h.mu.Lock()
+
+	previous, ok := h.Server.(*http.Server)
+	if !ok {
+		h.mu.Unlock()
+		return
+	}
+
+	previousListener := h.listener
+
+	h.serve(ctx, &http.Server{
+		Handler:      previous.Handler,
+		ErrorLog:     previous.ErrorLog,
+		ReadTimeout:  time.Duration(timeouts.ReadTimeout),
+		WriteTimeout: time.Duration(timeouts.WriteTimeout),
+		IdleTimeout:  time.Duration(timeouts.IdleTimeout),
+	})
+
+	h.mu.Unlock()
+
+	previousListener.detach()
+
+	go func() {
+		// The previous server is shut down once it no longer serves the listener,
+		// so that the shutdown does not close the underlying listener shared with the new server.
+		<-previousListener.served
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), graceTimeOut)
+		defer cancel()
+
+		if err := previous.Shutdown(shutdownCtx); err != nil {
+			log.FromContext(ctx).Debugf("Previous server failed to shutdown within the grace timeout: %v", err)
+			_ = previous.Close()
+		}
+	}()
 }
 
 func createHTTPServer(ctx context.Context, ln net.Listener, configuration *static.EntryPoint, withH2c bool) (*httpServer, error) {
 	httpSwitcher := middlewares.NewHandlerSwitcher(router.BuildDefaultHTTPRouter())
 
-	var handler http.Handler
+	var handler http.Handler = httpSwitcher
+	if configuration.FairQueuing != nil {
+		handler = fairqueuing.New(handler, *configuration.FairQueuing)
+	}
+
 	var err error
 	handler, err = forwardedheaders.NewXForwarded(
 		configuration.ForwardedHeaders.Insecure,
 		configuration.ForwardedHeaders.TrustedIPs,
-		httpSwitcher)
+		handler)
 
 	if err != nil {
 		return nil, err
@@ -526,18 +764,13 @@ func createHTTPServer(ctx context.Context, ln net.Listener, configuration *stati
 		IdleTimeout:  time.Duration(configuration.Transport.RespondingTimeouts.IdleTimeout),
 	}
 
-	listener := newHTTPForwarder(ln)
-	go func() {
-		err := serverHTTP.Serve(listener)
-		if err != nil && !errors.Is(err, http.ErrServerClosed) {
-			log.FromContext(ctx).Errorf("Error while starting server: %v", err)
-		}
-	}()
-	return &httpServer{
-		Server:    serverHTTP,
-		Forwarder: listener,
+	srv := &httpServer{
+		Forwarder: newHTTPForwarder(ln),
 		Switcher:  httpSwitcher,
-	}, nil
+	}
+	srv.serve(ctx, serverHTTP)
+
+	return srv, nil
 }
 
 func newTrackedConnection(conn tcp.WriteCloser, tracker *connectionTracker) *trackedConnection {
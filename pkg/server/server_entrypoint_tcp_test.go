@@ -227,3 +227,66 @@ func TestReadTimeoutWithFirstByte(t *testing.T) {
 		t.Error("Timeout while read")
 	}
 }
+
+func TestTCPEntryPoint_UpdateTransport(t *testing.T) {
+	epConfig := &static.EntryPointsTransport{}
+	epConfig.SetDefaults()
+
+	entryPoint, err := NewTCPEntryPoint(context.Background(), &static.EntryPoint{
+		Address:          ":0",
+		Transport:        epConfig,
+		ForwardedHeaders: &static.ForwardedHeaders{},
+	})
+	require.NoError(t, err)
+
+	router := &tcp.Router{}
+	router.HTTPHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+
+	conn, err := startEntrypoint(entryPoint, router)
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	newConfig := &static.EntryPointsTransport{}
+	newConfig.SetDefaults()
+	newConfig.RespondingTimeouts.IdleTimeout = ptypes.Duration(42 * time.Second)
+
+	entryPoint.UpdateTransport(context.Background(), newConfig)
+
+	assert.Equal(t, 42*time.Second, entryPoint.httpServer.server().(*http.Server).IdleTimeout)
+	assert.Equal(t, 42*time.Second, entryPoint.httpsServer.server().(*http.Server).IdleTimeout)
+
+	// The new connections are served by the new server.
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	resp, err := client.Get("http://" + entryPoint.listener.Addr().String())
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestTCPEntryPoints_shutdownGroups(t *testing.T) {
+	entryPoint := func(priority int) *TCPEntryPoint {
+		return &TCPEntryPoint{
+			transportConfiguration: &static.EntryPointsTransport{
+				LifeCycle: &static.LifeCycle{ShutdownPriority: priority},
+			},
+		}
+	}
+
+	eps := TCPEntryPoints{
+		"web":       entryPoint(0),
+		"websecure": entryPoint(0),
+		"ping":      entryPoint(10),
+		"metrics":   entryPoint(-1),
+		"traefik":   entryPoint(10),
+	}
+
+	expected := [][]string{
+		{"ping", "traefik"},
+		{"web", "websecure"},
+		{"metrics"},
+	}
+
+	assert.Equal(t, expected, eps.shutdownGroups())
+}
@@ -0,0 +1,248 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// StaticConfigurationLoader loads the static configuration again, from the same sources as at startup.
+type StaticConfigurationLoader func() (*static.Configuration, error)
+
+// SetStaticConfigurationReloader enables the reload of the static configuration, with the given loader.
+// The given static configuration is the one Traefik has been started with.
+func (s *Server) SetStaticConfigurationReloader(staticConfiguration *static.Configuration, loader StaticConfigurationLoader) {
+	s.staticConfiguration = staticConfiguration
+	s.staticConfigurationLoader = loader
+}
+
+// ReloadStaticConfiguration loads the static configuration again, and applies the changes which can be hot reloaded:
+// the log level, the transport timeouts of the entry points, and the disabling of the providers enabled at startup.
+// The changes of the other options are reported, as they require a restart.
+func (s *Server) ReloadStaticConfiguration(ctx context.Context) {
+	logger := log.FromContext(ctx)
+
+	if s.staticConfigurationLoader == nil {
+		logger.Warn("Reload of the static configuration not supported")
+		return
+	}
+
+	logger.Info("Reloading the static configuration")
+
+	configuration, err := s.staticConfigurationLoader()
+	if err != nil {
+		logger.Errorf("Unable to reload the static configuration: %v", err)
+		return
+	}
+
+	s.reloadLogLevel(ctx, configuration)
+	s.reloadEntryPointsTransport(ctx, configuration)
+	s.reloadProviders(configuration)
+
+	changes, err := nonReloadableChanges(s.staticConfiguration, configuration)
+	if err != nil {
+		logger.Errorf("Unable to compare the static configurations: %v", err)
+		return
+	}
+
+	if len(changes) > 0 {
+		logger.Warnf("The following static configuration options cannot be hot reloaded, and require a restart to be applied: %s", strings.Join(changes, ", "))
+	}
+}
+
+func (s *Server) reloadLogLevel(ctx context.Context, configuration *static.Configuration) {
+	levelStr := "error"
+	if configuration.Log != nil && configuration.Log.Level != "" {
+		levelStr = strings.ToLower(configuration.Log.Level)
+	}
+
+	level, err := logrus.ParseLevel(levelStr)
+	if err != nil {
+		log.FromContext(ctx).Errorf("Error getting level: %v", err)
+		return
+	}
+
+	if level == log.GetLevel() {
+		return
+	}
+
+	log.SetLevel(level)
+	log.FromContext(ctx).Infof("Log level set to %s", level)
+}
+
+func (s *Server) reloadEntryPointsTransport(ctx context.Context, configuration *static.Configuration) {
+	for name, entryPoint := range s.tcpEntryPoints {
+		config, ok := configuration.EntryPoints[name]
+		if !ok || config.Transport == nil {
+			continue
+		}
+
+		entryPoint.UpdateTransport(log.With(ctx, log.Str(log.EntryPointName, name)), config.Transport)
+	}
+}
+
+func (s *Server) reloadProviders(configuration *static.Configuration) {
+	if s.watcher == nil || s.staticConfiguration == nil {
+		return
+	}
+
+	enabled := providerNames(configuration.Providers)
+	for name := range providerNames(s.staticConfiguration.Providers) {
+		if _, ok := enabled[name]; ok {
+			s.watcher.EnableProvider(name)
+		} else {
+			s.watcher.DisableProvider(name)
+		}
+	}
+}
+
+// providerNames returns, for each enabled provider, the name of the provider in the dynamic configuration,
+// which is the lowercase name of its option, except for the Kubernetes Ingress provider.
+func providerNames(providers *static.Providers) map[string]string {
+	names := make(map[string]string)
+	if providers == nil {
+		return names
+	}
+
+	value := reflect.ValueOf(providers).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.Type.Kind() != reflect.Ptr || value.Field(i).IsNil() {
+			continue
+		}
+
+		option := strings.Split(field.Tag.Get("json"), ",")[0]
+
+		name := strings.ToLower(option)
+		if field.Name == "KubernetesIngress" {
+			name = "kubernetes"
+		}
+
+		names[name] = option
+	}
+
+	return names
+}
+
+// nonReloadableChanges returns the paths of the options which differ between the given static configurations,
+// and cannot be hot reloaded.
+func nonReloadableChanges(previous, next *static.Configuration) ([]string, error) {
+	previousOptions, err := toOptions(previous)
+	if err != nil {
+		return nil, err
+	}
+
+	nextOptions, err := toOptions(next)
+	if err != nil {
+		return nil, err
+	}
+
+	// The log level is hot reloaded.
+	delete(childOptions(previousOptions, "log"), "level")
+	delete(childOptions(nextOptions, "log"), "level")
+
+	// The transport timeouts of the entry points are hot reloaded.
+	for _, entryPoints := range []map[string]interface{}{childOptions(previousOptions, "entryPoints"), childOptions(nextOptions, "entryPoints")} {
+		for name := range entryPoints {
+			transport := childOptions(childOptions(entryPoints, name), "transport")
+			delete(transport, "respondingTimeouts")
+			delete(transport, "lifeCycle")
+		}
+	}
+
+	// The providers enabled at startup can be disabled.
+	if previous.Providers != nil {
+		nextProviders := providerNames(next.Providers)
+		for name, option := range providerNames(previous.Providers) {
+			if _, ok := nextProviders[name]; !ok {
+				delete(childOptions(previousOptions, "providers"), option)
+			}
+		}
+	}
+
+	var changes []string
+	diffOptions("", previousOptions, nextOptions, &changes)
+	sort.Strings(changes)
+
+	return changes, nil
+}
+
+func toOptions(configuration *static.Configuration) (map[string]interface{}, error) {
+	data, err := json.Marshal(configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	options := make(map[string]interface{})
+	if err := json.Unmarshal(data, &options); err != nil {
+		return nil, err
+	}
+
+	return options, nil
+}
+
+// childOptions returns the options under the given key, or nil if there are none.
+func childOptions(options map[string]interface{}, key string) map[string]interface{} {
+	child, _ := options[key].(map[string]interface{})
+	return child
+}
+
+// diffOptions appends to changes the paths of the options which differ between the given values.
+func diffOptions(path string, previous, next interface{}, changes *[]string) {
+	previousOptions, previousIsMap := previous.(map[string]interface{})
+	nextOptions, nextIsMap := next.(map[string]interface{})
+
+	if !previousIsMap || !nextIsMap {
+		if isEmptyOptions(previous) && isEmptyOptions(next) {
+			return
+		}
+
+		if !reflect.DeepEqual(previous, next) {
+			*changes = append(*changes, path)
+		}
+		return
+	}
+
+	keys := make(map[string]struct{})
+	for key := range previousOptions {
+		keys[key] = struct{}{}
+	}
+	for key := range nextOptions {
+		keys[key] = struct{}{}
+	}
+
+	for key := range keys {
+		childPath := key
+		if path != "" {
+			childPath = path + "." + key
+		}
+
+		diffOptions(childPath, previousOptions[key], nextOptions[key], changes)
+	}
+}
+
+// isEmptyOptions reports whether the given value holds no option.
+func isEmptyOptions(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	options, ok := value.(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	for _, option := range options {
+		if !isEmptyOptions(option) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/provider/docker"
+	"github.com/traefik/traefik/v2/pkg/provider/file"
+	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/ingress"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func Test_nonReloadableChanges(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		update   func(configuration *static.Configuration)
+		expected []string
+	}{
+		{
+			desc:   "no change",
+			update: func(configuration *static.Configuration) {},
+		},
+		{
+			desc: "log level",
+			update: func(configuration *static.Configuration) {
+				configuration.Log.Level = "DEBUG"
+			},
+		},
+		{
+			desc: "default log level",
+			update: func(configuration *static.Configuration) {
+				configuration.Log.Level = ""
+			},
+		},
+		{
+			desc: "log format",
+			update: func(configuration *static.Configuration) {
+				configuration.Log.Format = "json"
+			},
+			expected: []string{"log.format"},
+		},
+		{
+			desc: "entry point timeouts",
+			update: func(configuration *static.Configuration) {
+				configuration.EntryPoints["web"].Transport.RespondingTimeouts.IdleTimeout = ptypes.Duration(42)
+				configuration.EntryPoints["web"].Transport.LifeCycle.GraceTimeOut = ptypes.Duration(42)
+			},
+		},
+		{
+			desc: "entry point address",
+			update: func(configuration *static.Configuration) {
+				configuration.EntryPoints["web"].Address = ":8080"
+			},
+			expected: []string{"entryPoints.web.address"},
+		},
+		{
+			desc: "new entry point",
+			update: func(configuration *static.Configuration) {
+				configuration.EntryPoints["websecure"] = &static.EntryPoint{Address: ":443"}
+			},
+			expected: []string{"entryPoints.websecure"},
+		},
+		{
+			desc: "disabled provider",
+			update: func(configuration *static.Configuration) {
+				configuration.Providers.Docker = nil
+			},
+		},
+		{
+			desc: "enabled provider",
+			update: func(configuration *static.Configuration) {
+				configuration.Providers.File = &file.Provider{Directory: "/etc/traefik"}
+			},
+			expected: []string{"providers.file"},
+		},
+		{
+			desc: "provider options",
+			update: func(configuration *static.Configuration) {
+				configuration.Providers.Docker.Endpoint = "tcp://127.0.0.1:2375"
+				configuration.Providers.Docker.ExposedByDefault = false
+			},
+			expected: []string{"providers.docker.endpoint", "providers.docker.exposedByDefault"},
+		},
+	}
+
+	newConfiguration := func() *static.Configuration {
+		entryPoint := &static.EntryPoint{Address: ":80"}
+		entryPoint.SetDefaults()
+
+		return &static.Configuration{
+			Global:      &static.Global{},
+			EntryPoints: static.EntryPoints{"web": entryPoint},
+			Providers: &static.Providers{
+				Docker: &docker.Provider{Endpoint: "unix:///var/run/docker.sock", ExposedByDefault: true},
+			},
+			Log: &types.TraefikLog{Level: "ERROR", Format: "common"},
+		}
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := newConfiguration()
+			test.update(next)
+
+			changes, err := nonReloadableChanges(newConfiguration(), next)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, changes)
+		})
+	}
+}
+
+func Test_providerNames(t *testing.T) {
+	providers := &static.Providers{
+		Docker:            &docker.Provider{},
+		KubernetesIngress: &ingress.Provider{},
+	}
+
+	expected := map[string]string{
+		"docker":     "docker",
+		"kubernetes": "kubernetesIngress",
+	}
+
+	assert.Equal(t, expected, providerNames(providers))
+	assert.Empty(t, providerNames(nil))
+}
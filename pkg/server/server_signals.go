@@ -11,7 +11,7 @@ import (
 )
 
 func (s *Server) configureSignals() {
-	signal.Notify(s.signals, syscall.SIGUSR1)
+	signal.Notify(s.signals, syscall.SIGUSR1, syscall.SIGHUP)
 }
 
 func (s *Server) listenSignals(ctx context.Context) {
@@ -20,6 +20,10 @@ func (s *Server) listenSignals(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case sig := <-s.signals:
+			if sig == syscall.SIGHUP {
+				s.ReloadStaticConfiguration(ctx)
+			}
+
 			if sig == syscall.SIGUSR1 {
 				log.WithoutContext().Infof("Closing and re-opening log files for rotation: %+v", sig)
 
@@ -29,6 +33,10 @@ func (s *Server) listenSignals(ctx context.Context) {
 					}
 				}
 
+				if err := s.chainBuilder.RotateDecisionLog(); err != nil {
+					log.WithoutContext().Errorf("Error rotating decision log: %v", err)
+				}
+
 				if err := log.RotateFile(); err != nil {
 					log.WithoutContext().Errorf("Error rotating traefik log: %v", err)
 				}
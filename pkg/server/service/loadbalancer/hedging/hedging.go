@@ -0,0 +1,425 @@
+package hedging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+)
+
+const (
+	// latencyWindow is the number of recent response times the hedging delay is computed from.
+	latencyWindow = 100
+	// minLatencies is the number of response times from which the hedging delay is computed,
+	// the maximum delay is used until then.
+	minLatencies = 10
+)
+
+var errNoAvailableServer = errors.New("no available server")
+
+type namedHandler struct {
+	http.Handler
+	name string
+}
+
+// Balancer is a load-balancer of services with hedging.
+// Each request is sent to a service, in turn, and the idempotent ones are also sent to the next service
+// if the first one has not responded within the hedging delay.
+// The first response is sent back to the client, and the other request is canceled.
+type Balancer struct {
+	wantsHealthCheck bool
+	percentile       float64
+	minDelay         time.Duration
+	maxDelay         time.Duration
+
+	handlers []*namedHandler
+	// next is the index of the handler which receives the next request first, accessed atomically.
+	next uint64
+
+	latencies latencies
+
+	mutex sync.RWMutex
+	// status is a record of which child services of the Balancer are healthy, keyed
+	// by name of child service. A service is initially added to the map when it is
+	// created via AddService, and it is later removed or added to the map as needed,
+	// through the SetStatus method.
+	status map[string]struct{}
+	// updaters is the list of hooks that are run (to update the Balancer
+	// parent(s)), whenever the Balancer status changes.
+	updaters []func(bool)
+}
+
+// New creates a new hedging load-balancer.
+// The hedging delay is the given percentile of the recent response times, bounded by minDelay and maxDelay.
+func New(percentile float64, minDelay, maxDelay time.Duration, hc *dynamic.HealthCheck) (*Balancer, error) {
+	if percentile <= 0 || percentile > 100 {
+		return nil, fmt.Errorf("invalid percentile %v, it must be greater than 0 and lower than or equal to 100", percentile)
+	}
+
+	if maxDelay <= 0 {
+		return nil, errors.New("maxDelay must be greater than 0")
+	}
+
+	if minDelay > maxDelay {
+		return nil, fmt.Errorf("minDelay %s is greater than maxDelay %s", minDelay, maxDelay)
+	}
+
+	return &Balancer{
+		wantsHealthCheck: hc != nil,
+		percentile:       percentile,
+		minDelay:         minDelay,
+		maxDelay:         maxDelay,
+		status:           make(map[string]struct{}),
+	}, nil
+}
+
+// AddService adds a handler.
+func (b *Balancer) AddService(name string, handler http.Handler) {
+	b.handlers = append(b.handlers, &namedHandler{Handler: handler, name: name})
+
+	b.mutex.Lock()
+	b.status[name] = struct{}{}
+	b.mutex.Unlock()
+}
+
+// SetStatus sets on the balancer that its given child is now of the given status.
+func (b *Balancer) SetStatus(ctx context.Context, childName string, up bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	upBefore := len(b.status) > 0
+
+	status := "DOWN"
+	if up {
+		status = "UP"
+	}
+	log.FromContext(ctx).Debugf("Setting status of %s to %v", childName, status)
+	if up {
+		b.status[childName] = struct{}{}
+	} else {
+		delete(b.status, childName)
+	}
+
+	upAfter := len(b.status) > 0
+	status = "DOWN"
+	if upAfter {
+		status = "UP"
+	}
+
+	// No Status Change
+	if upBefore == upAfter {
+		// We're still with the same status, no need to propagate
+		log.FromContext(ctx).Debugf("Still %s, no need to propagate", status)
+		return
+	}
+
+	// Status Change
+	log.FromContext(ctx).Debugf("Propagating new %s status", status)
+	for _, fn := range b.updaters {
+		fn(upAfter)
+	}
+}
+
+// RegisterStatusUpdater adds fn to the list of hooks that are run when the
+// status of the Balancer changes.
+// Not thread safe.
+func (b *Balancer) RegisterStatusUpdater(fn func(up bool)) error {
+	if !b.wantsHealthCheck {
+		return errors.New("healthCheck not enabled in config for this hedging service")
+	}
+	b.updaters = append(b.updaters, fn)
+	return nil
+}
+
+func (b *Balancer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	first, second := b.nextServers()
+	if first == nil {
+		http.Error(rw, errNoAvailableServer.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	if !isHedgeable(req) {
+		first.ServeHTTP(rw, req)
+		return
+	}
+
+	r := &race{
+		rw:      rw,
+		decided: make(chan struct{}),
+		record:  b.latencies.record,
+	}
+
+	var wg sync.WaitGroup
+
+	// Each attempt gets its own copy of the request, as the handlers can modify it.
+	r.start(&wg, first, req.Clone(req.Context()))
+
+	timer := time.NewTimer(b.delay())
+	select {
+	case <-r.decided:
+	case <-timer.C:
+		log.FromContext(req.Context()).Debugf("No response from %s within the hedging delay, sending the request to %s", first.name, second.name)
+
+		// The access log datatable is not shared with the hedged request,
+		// as it would result in unguarded concurrent reads/writes on the datatable.
+		ctx := context.WithValue(req.Context(), accesslog.DataTableKey, nil)
+		r.start(&wg, second, req.Clone(ctx))
+	}
+	timer.Stop()
+
+	// The losing attempt is canceled as soon as the first response is received,
+	// and is waited for, so that no handler runs after the request is served.
+	wg.Wait()
+
+	if r.winner != nil && r.winner.panicValue != nil {
+		panic(r.winner.panicValue)
+	}
+}
+
+// nextServers returns the handler receiving the request first and the one receiving the hedged request,
+// which are the next two handlers which are up, in turn.
+// The same handler is returned twice if it is the only one up, and nil if there is none.
+func (b *Balancer) nextServers() (*namedHandler, *namedHandler) {
+	if len(b.handlers) == 0 {
+		return nil, nil
+	}
+
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	start := int((atomic.AddUint64(&b.next, 1) - 1) % uint64(len(b.handlers)))
+
+	var first, second *namedHandler
+	for i := 0; i < len(b.handlers); i++ {
+		handler := b.handlers[(start+i)%len(b.handlers)]
+		if _, ok := b.status[handler.name]; !ok {
+			continue
+		}
+
+		if first == nil {
+			first = handler
+			continue
+		}
+
+		second = handler
+		break
+	}
+
+	if second == nil {
+		second = first
+	}
+
+	return first, second
+}
+
+// delay returns the hedging delay, i.e. the percentile of the recent response times, bounded by minDelay and maxDelay.
+func (b *Balancer) delay() time.Duration {
+	delay, ok := b.latencies.percentile(b.percentile)
+	if !ok || delay > b.maxDelay {
+		return b.maxDelay
+	}
+
+	if delay < b.minDelay {
+		return b.minDelay
+	}
+
+	return delay
+}
+
+// isHedgeable reports whether the request can be sent twice,
+// i.e. whether it is idempotent, without a body, and not a protocol upgrade.
+func isHedgeable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		return false
+	}
+
+	return req.ContentLength == 0 && req.Header.Get("Upgrade") == ""
+}
+
+// latencies records the recent response times.
+type latencies struct {
+	mu     sync.Mutex
+	values [latencyWindow]time.Duration
+	count  int
+	next   int
+}
+
+func (l *latencies) record(latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.values[l.next] = latency
+	l.next = (l.next + 1) % latencyWindow
+	if l.count < latencyWindow {
+		l.count++
+	}
+}
+
+// percentile returns the percentile of the recorded response times,
+// or false if there are not enough of them.
+func (l *latencies) percentile(percentile float64) (time.Duration, bool) {
+	l.mu.Lock()
+	if l.count < minLatencies {
+		l.mu.Unlock()
+		return 0, false
+	}
+
+	values := make([]time.Duration, l.count)
+	copy(values, l.values[:l.count])
+	l.mu.Unlock()
+
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	index := int(math.Ceil(percentile/100*float64(len(values)))) - 1
+	if index < 0 {
+		index = 0
+	}
+
+	return values[index], true
+}
+
+// race is a request sent to several handlers, whose first response is sent back to the client.
+type race struct {
+	rw     http.ResponseWriter
+	record func(time.Duration)
+
+	mu       sync.Mutex
+	attempts []*attempt
+	winner   *attempt
+	// decided is closed when the winner is known.
+	decided chan struct{}
+}
+
+// start sends the request to the handler, unless a response has already been received.
+func (r *race) start(wg *sync.WaitGroup, handler http.Handler, req *http.Request) {
+	ctx, cancel := context.WithCancel(req.Context())
+	a := &attempt{race: r, header: make(http.Header), cancel: cancel, start: time.Now()}
+
+	r.mu.Lock()
+	if r.winner != nil {
+		r.mu.Unlock()
+		cancel()
+		return
+	}
+	r.attempts = append(r.attempts, a)
+	r.mu.Unlock()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer cancel()
+		defer func() {
+			if p := recover(); p != nil {
+				a.panicValue = p
+			}
+
+			// A handler which returns without writing anything still responds.
+			a.claim()
+		}()
+
+		handler.ServeHTTP(a, req.WithContext(ctx))
+	}()
+}
+
+// claim makes the attempt the winner if there is none yet, and cancels the other attempts,
+// it reports whether the attempt is the winner.
+func (r *race) claim(a *attempt) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.winner != nil {
+		return r.winner == a
+	}
+
+	r.winner = a
+	close(r.decided)
+
+	for _, other := range r.attempts {
+		if other != a {
+			other.cancel()
+		}
+	}
+
+	r.record(time.Since(a.start))
+
+	return true
+}
+
+// attempt is the http.ResponseWriter of a handler the request is sent to.
+// The response of the winner is written to the client, and the ones of the others are discarded.
+// It is only used by the goroutine serving the attempt.
+type attempt struct {
+	race   *race
+	header http.Header
+	cancel context.CancelFunc
+	start  time.Time
+
+	won  bool
+	lost bool
+
+	panicValue interface{}
+}
+
+// claim reports whether the attempt is the winner, trying to become it if the winner is not known yet.
+func (a *attempt) claim() bool {
+	if a.won || a.lost {
+		return a.won
+	}
+
+	if !a.race.claim(a) {
+		a.lost = true
+		return false
+	}
+
+	a.won = true
+
+	header := a.race.rw.Header()
+	for name, values := range a.header {
+		header[name] = values
+	}
+
+	return true
+}
+
+func (a *attempt) Header() http.Header {
+	if a.won {
+		return a.race.rw.Header()
+	}
+
+	return a.header
+}
+
+func (a *attempt) WriteHeader(statusCode int) {
+	if a.claim() {
+		a.race.rw.WriteHeader(statusCode)
+	}
+}
+
+func (a *attempt) Write(data []byte) (int, error) {
+	if a.claim() {
+		return a.race.rw.Write(data)
+	}
+
+	return len(data), nil
+}
+
+func (a *attempt) Flush() {
+	if !a.claim() {
+		return
+	}
+
+	if flusher, ok := a.race.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
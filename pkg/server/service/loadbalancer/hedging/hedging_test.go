@@ -0,0 +1,287 @@
+package hedging
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+// server returns a handler responding after the delay, unless its request is canceled,
+// and counting the requests it receives.
+func server(name string, delay time.Duration, requests *int32) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(requests, 1)
+
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return
+		}
+
+		rw.Header().Set("server", name)
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write([]byte(name))
+	})
+}
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		percentile  float64
+		minDelay    time.Duration
+		maxDelay    time.Duration
+		expectedErr bool
+	}{
+		{
+			desc:       "valid",
+			percentile: 95,
+			minDelay:   10 * time.Millisecond,
+			maxDelay:   time.Second,
+		},
+		{
+			desc:        "percentile of zero",
+			maxDelay:    time.Second,
+			expectedErr: true,
+		},
+		{
+			desc:        "percentile greater than 100",
+			percentile:  101,
+			maxDelay:    time.Second,
+			expectedErr: true,
+		},
+		{
+			desc:        "no max delay",
+			percentile:  95,
+			expectedErr: true,
+		},
+		{
+			desc:        "min delay greater than max delay",
+			percentile:  95,
+			minDelay:    2 * time.Second,
+			maxDelay:    time.Second,
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(test.percentile, test.minDelay, test.maxDelay, nil)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestBalancer_ServeHTTP(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		method           string
+		firstDelay       time.Duration
+		secondDelay      time.Duration
+		expectedServer   string
+		expectedFirst    int32
+		expectedSecond   int32
+		expectedCanceled bool
+	}{
+		{
+			desc:           "first service responding within the delay",
+			method:         http.MethodGet,
+			expectedServer: "first",
+			expectedFirst:  1,
+		},
+		{
+			desc:           "first service not responding within the delay",
+			method:         http.MethodGet,
+			firstDelay:     time.Minute,
+			expectedServer: "second",
+			expectedFirst:  1,
+			expectedSecond: 1,
+		},
+		{
+			desc:           "first service responding before the hedged request",
+			method:         http.MethodGet,
+			firstDelay:     100 * time.Millisecond,
+			secondDelay:    time.Minute,
+			expectedServer: "first",
+			expectedFirst:  1,
+			expectedSecond: 1,
+		},
+		{
+			desc:           "request not idempotent",
+			method:         http.MethodPost,
+			firstDelay:     100 * time.Millisecond,
+			expectedServer: "first",
+			expectedFirst:  1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			balancer, err := New(95, 0, 50*time.Millisecond, nil)
+			require.NoError(t, err)
+
+			var firstRequests, secondRequests int32
+			balancer.AddService("first", server("first", test.firstDelay, &firstRequests))
+			balancer.AddService("second", server("second", test.secondDelay, &secondRequests))
+
+			recorder := httptest.NewRecorder()
+			balancer.ServeHTTP(recorder, httptest.NewRequest(test.method, "/", nil))
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, test.expectedServer, recorder.Header().Get("server"))
+			assert.Equal(t, test.expectedServer, recorder.Body.String())
+			assert.Equal(t, test.expectedFirst, atomic.LoadInt32(&firstRequests))
+			assert.Equal(t, test.expectedSecond, atomic.LoadInt32(&secondRequests))
+		})
+	}
+}
+
+func TestBalancer_ServeHTTP_roundRobin(t *testing.T) {
+	balancer, err := New(95, 0, time.Second, nil)
+	require.NoError(t, err)
+
+	var firstRequests, secondRequests int32
+	balancer.AddService("first", server("first", 0, &firstRequests))
+	balancer.AddService("second", server("second", 0, &secondRequests))
+
+	var servers []string
+	for i := 0; i < 4; i++ {
+		recorder := httptest.NewRecorder()
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		servers = append(servers, recorder.Header().Get("server"))
+	}
+
+	assert.Equal(t, []string{"first", "second", "first", "second"}, servers)
+}
+
+func TestBalancer_ServeHTTP_status(t *testing.T) {
+	balancer, err := New(95, 0, 50*time.Millisecond, &dynamic.HealthCheck{})
+	require.NoError(t, err)
+
+	var firstRequests, secondRequests, thirdRequests int32
+	balancer.AddService("first", server("first", time.Minute, &firstRequests))
+	balancer.AddService("second", server("second", time.Minute, &secondRequests))
+	balancer.AddService("third", server("third", 0, &thirdRequests))
+
+	balancer.SetStatus(context.Background(), "second", false)
+
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, "third", recorder.Header().Get("server"))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&firstRequests))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&secondRequests))
+
+	balancer.SetStatus(context.Background(), "first", false)
+	balancer.SetStatus(context.Background(), "third", false)
+
+	recorder = httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
+
+func TestBalancer_ServeHTTP_panic(t *testing.T) {
+	balancer, err := New(95, 0, time.Second, nil)
+	require.NoError(t, err)
+
+	balancer.AddService("first", http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		balancer.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+func TestIsHedgeable(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		method   string
+		body     string
+		header   http.Header
+		expected bool
+	}{
+		{
+			desc:     "GET",
+			method:   http.MethodGet,
+			expected: true,
+		},
+		{
+			desc:     "HEAD",
+			method:   http.MethodHead,
+			expected: true,
+		},
+		{
+			desc:   "POST",
+			method: http.MethodPost,
+		},
+		{
+			desc:   "GET with a body",
+			method: http.MethodGet,
+			body:   "foo",
+		},
+		{
+			desc:   "upgrade",
+			method: http.MethodGet,
+			header: http.Header{"Upgrade": {"websocket"}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(test.method, "/", strings.NewReader(test.body))
+			for name, values := range test.header {
+				req.Header[name] = values
+			}
+
+			assert.Equal(t, test.expected, isHedgeable(req))
+		})
+	}
+}
+
+func TestBalancer_delay(t *testing.T) {
+	balancer, err := New(90, 20*time.Millisecond, 500*time.Millisecond, nil)
+	require.NoError(t, err)
+
+	// The maximum delay is used until enough response times are recorded.
+	assert.Equal(t, 500*time.Millisecond, balancer.delay())
+
+	for i := 1; i <= 10; i++ {
+		balancer.latencies.record(time.Duration(i) * 10 * time.Millisecond)
+	}
+	assert.Equal(t, 90*time.Millisecond, balancer.delay())
+
+	// Only the recent response times are taken into account, and the delay is bounded.
+	for i := 0; i < latencyWindow; i++ {
+		balancer.latencies.record(time.Millisecond)
+	}
+	assert.Equal(t, 20*time.Millisecond, balancer.delay())
+
+	for i := 0; i < latencyWindow; i++ {
+		balancer.latencies.record(time.Minute)
+	}
+	assert.Equal(t, 500*time.Millisecond, balancer.delay())
+}
@@ -0,0 +1,163 @@
+package priority
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+var errNoAvailableServer = errors.New("no available server")
+
+type namedHandler struct {
+	http.Handler
+	name          string
+	maxConcurrent int64
+	// inFlight is the number of requests in progress, accessed atomically.
+	inFlight int64
+}
+
+// acquire reports whether the handler can take one more request, and accounts for it if so.
+func (h *namedHandler) acquire() bool {
+	if atomic.AddInt64(&h.inFlight, 1) > h.maxConcurrent && h.maxConcurrent > 0 {
+		atomic.AddInt64(&h.inFlight, -1)
+		return false
+	}
+
+	return true
+}
+
+func (h *namedHandler) release() {
+	atomic.AddInt64(&h.inFlight, -1)
+}
+
+// Balancer is a load-balancer of services by priority.
+// Each request is sent to the first service, in the order they were added,
+// which is neither saturated (i.e. with as many requests in progress as its maximum) nor down.
+type Balancer struct {
+	wantsHealthCheck bool
+	// spillover counts the requests which are not handled by the first service.
+	spillover metrics.Counter
+
+	handlers []*namedHandler
+
+	mutex sync.RWMutex
+	// status is a record of which child services of the Balancer are healthy, keyed
+	// by name of child service. A service is initially added to the map when it is
+	// created via AddService, and it is later removed or added to the map as needed,
+	// through the SetStatus method.
+	status map[string]struct{}
+	// updaters is the list of hooks that are run (to update the Balancer
+	// parent(s)), whenever the Balancer status changes.
+	updaters []func(bool)
+}
+
+// New creates a new priority load-balancer.
+// The spillover counter, if not nil, is incremented with the pool label set to the service handling the request,
+// for each request which is not handled by the first service.
+func New(hc *dynamic.HealthCheck, spillover metrics.Counter) *Balancer {
+	return &Balancer{
+		wantsHealthCheck: hc != nil,
+		spillover:        spillover,
+		status:           make(map[string]struct{}),
+	}
+}
+
+// AddService adds a handler, with a lower priority than the ones previously added.
+// The handler is saturated once maxConcurrentRequests requests are in progress,
+// and a non-positive maxConcurrentRequests means unlimited.
+func (b *Balancer) AddService(name string, handler http.Handler, maxConcurrentRequests int64) {
+	b.handlers = append(b.handlers, &namedHandler{Handler: handler, name: name, maxConcurrent: maxConcurrentRequests})
+
+	b.mutex.Lock()
+	b.status[name] = struct{}{}
+	b.mutex.Unlock()
+}
+
+// SetStatus sets on the balancer that its given child is now of the given status.
+func (b *Balancer) SetStatus(ctx context.Context, childName string, up bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	upBefore := len(b.status) > 0
+
+	status := "DOWN"
+	if up {
+		status = "UP"
+	}
+	log.FromContext(ctx).Debugf("Setting status of %s to %v", childName, status)
+	if up {
+		b.status[childName] = struct{}{}
+	} else {
+		delete(b.status, childName)
+	}
+
+	upAfter := len(b.status) > 0
+	status = "DOWN"
+	if upAfter {
+		status = "UP"
+	}
+
+	// No Status Change
+	if upBefore == upAfter {
+		// We're still with the same status, no need to propagate
+		log.FromContext(ctx).Debugf("Still %s, no need to propagate", status)
+		return
+	}
+
+	// Status Change
+	log.FromContext(ctx).Debugf("Propagating new %s status", status)
+	for _, fn := range b.updaters {
+		fn(upAfter)
+	}
+}
+
+// RegisterStatusUpdater adds fn to the list of hooks that are run when the
+// status of the Balancer changes.
+// Not thread safe.
+func (b *Balancer) RegisterStatusUpdater(fn func(up bool)) error {
+	if !b.wantsHealthCheck {
+		return errors.New("healthCheck not enabled in config for this priority service")
+	}
+	b.updaters = append(b.updaters, fn)
+	return nil
+}
+
+func (b *Balancer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	handler := b.nextServer()
+	if handler == nil {
+		http.Error(rw, errNoAvailableServer.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	defer handler.release()
+
+	if handler != b.handlers[0] && b.spillover != nil {
+		b.spillover.With("pool", handler.name).Add(1)
+	}
+
+	handler.ServeHTTP(rw, req)
+}
+
+// nextServer returns the first handler which is up and not saturated, after accounting for the request,
+// or nil if there is none.
+func (b *Balancer) nextServer() *namedHandler {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	for _, handler := range b.handlers {
+		if _, ok := b.status[handler.name]; !ok {
+			continue
+		}
+
+		if handler.acquire() {
+			return handler
+		}
+	}
+
+	return nil
+}
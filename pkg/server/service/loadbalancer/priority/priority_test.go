@@ -0,0 +1,136 @@
+package priority
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestBalancer(t *testing.T) {
+	balancer := New(nil, nil)
+
+	balancer.AddService("first", named("first"), 0)
+	balancer.AddService("second", named("second"), 0)
+
+	for i := 0; i < 3; i++ {
+		recorder := httptest.NewRecorder()
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, "first", recorder.Header().Get("server"))
+	}
+}
+
+func TestBalancer_spillover(t *testing.T) {
+	spillover := &counterMock{}
+	balancer := New(nil, spillover)
+
+	release := make(chan struct{})
+	served := make(chan struct{})
+	balancer.AddService("first", http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		served <- struct{}{}
+		<-release
+		rw.Header().Set("server", "first")
+	}), 1)
+	balancer.AddService("second", named("second"), 1)
+	balancer.AddService("third", named("third"), 0)
+
+	done := make(chan struct{})
+	go func() {
+		recorder := httptest.NewRecorder()
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, "first", recorder.Header().Get("server"))
+		close(done)
+	}()
+	<-served
+
+	// The first service is saturated.
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, "second", recorder.Header().Get("server"))
+
+	// The second service is saturated while it handles the request.
+	balancer.handlers[1].inFlight = 1
+
+	recorder = httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, "third", recorder.Header().Get("server"))
+
+	balancer.handlers[1].inFlight = 0
+
+	close(release)
+	<-done
+
+	recorder = httptest.NewRecorder()
+	go func() { <-served }()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, "first", recorder.Header().Get("server"))
+
+	assert.Equal(t, []string{"pool", "second", "pool", "third"}, spillover.labelValues)
+}
+
+func TestBalancer_allSaturated(t *testing.T) {
+	balancer := New(nil, nil)
+
+	balancer.AddService("first", named("first"), 1)
+	balancer.handlers[0].inFlight = 1
+
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
+
+func TestBalancer_status(t *testing.T) {
+	balancer := New(&dynamic.HealthCheck{}, nil)
+
+	balancer.AddService("first", named("first"), 0)
+	balancer.AddService("second", named("second"), 0)
+
+	var statuses []bool
+	err := balancer.RegisterStatusUpdater(func(up bool) {
+		statuses = append(statuses, up)
+	})
+	assert.NoError(t, err)
+
+	balancer.SetStatus(context.Background(), "first", false)
+
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, "second", recorder.Header().Get("server"))
+
+	balancer.SetStatus(context.Background(), "second", false)
+
+	recorder = httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	balancer.SetStatus(context.Background(), "first", true)
+
+	recorder = httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, "first", recorder.Header().Get("server"))
+
+	assert.Equal(t, []bool{false, true}, statuses)
+}
+
+type counterMock struct {
+	labelValues []string
+}
+
+func (c *counterMock) With(labelValues ...string) metrics.Counter {
+	c.labelValues = append(c.labelValues, labelValues...)
+	return c
+}
+
+func (c *counterMock) Add(float64) {}
+
+func named(name string) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.Header().Set("server", name)
+	})
+}
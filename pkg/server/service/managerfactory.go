@@ -2,11 +2,16 @@ package service
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/traefik/traefik/v2/pkg/api"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/config/static"
 	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares/auth"
+	"github.com/traefik/traefik/v2/pkg/middlewares/httpcache"
+	"github.com/traefik/traefik/v2/pkg/middlewares/maintenance"
+	"github.com/traefik/traefik/v2/pkg/provider/acme"
 	"github.com/traefik/traefik/v2/pkg/safe"
 )
 
@@ -23,20 +28,40 @@ type ManagerFactory struct {
 	pingHandler      http.Handler
 	acmeHTTPHandler  http.Handler
 
+	authBypasses        *auth.Bypasses
+	httpCaches          *httpcache.Caches
+	maintenanceSwitches *maintenance.Switches
+
 	routinesPool *safe.Pool
 }
 
 // NewManagerFactory creates a new ManagerFactory.
-func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *safe.Pool, metricsRegistry metrics.Registry, roundTripperManager *RoundTripperManager, acmeHTTPHandler http.Handler) *ManagerFactory {
+// The providers controller, if not nil, backs the API maintenance endpoints,
+// the usage registry, if not nil, backs the API usage endpoint,
+// and the ACME providers, if any, back the API ACME resolvers endpoint.
+func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *safe.Pool, metricsRegistry metrics.Registry, roundTripperManager *RoundTripperManager, acmeHTTPHandler http.Handler, providersController api.ProvidersController, usageRegistry *metrics.UsageRegistry, acmeProviders []*acme.Provider) *ManagerFactory {
 	factory := &ManagerFactory{
 		metricsRegistry:     metricsRegistry,
 		routinesPool:        routinesPool,
 		roundTripperManager: roundTripperManager,
 		acmeHTTPHandler:     acmeHTTPHandler,
+		httpCaches:          httpcache.NewCaches(metricsRegistry),
+		maintenanceSwitches: maintenance.NewSwitches(),
 	}
 
 	if staticConfiguration.API != nil {
-		factory.api = api.NewBuilder(staticConfiguration)
+		if staticConfiguration.API.AuthBypass != nil {
+			factory.authBypasses = auth.NewBypasses(time.Duration(staticConfiguration.API.AuthBypass.MaxDuration))
+		}
+
+		factory.api = api.NewBuilder(staticConfiguration, api.BuilderOptions{
+			Providers:           providersController,
+			AuthBypasses:        factory.authBypasses,
+			Usage:               usageRegistry,
+			HTTPCaches:          factory.httpCaches,
+			MaintenanceSwitches: factory.maintenanceSwitches,
+			ACMEProviders:       acmeProviders,
+		})
 
 		if staticConfiguration.API.Dashboard {
 			factory.dashboardHandler = api.DashboardHandler{Assets: staticConfiguration.API.DashboardAssets}
@@ -61,6 +86,22 @@ func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *s
 	return factory
 }
 
+// AuthBypasses returns the bypasses of the auth middlewares managed through the API,
+// or nil if the auth bypass endpoints are disabled.
+func (f *ManagerFactory) AuthBypasses() *auth.Bypasses {
+	return f.authBypasses
+}
+
+// HTTPCaches returns the caches of the HTTP cache middlewares, which are kept across the configuration reloads.
+func (f *ManagerFactory) HTTPCaches() *httpcache.Caches {
+	return f.httpCaches
+}
+
+// MaintenanceSwitches returns the switches of the maintenance middlewares, which are kept across the configuration reloads.
+func (f *ManagerFactory) MaintenanceSwitches() *maintenance.Switches {
+	return f.maintenanceSwitches
+}
+
 // Build creates a service manager.
 func (f *ManagerFactory) Build(configuration *runtime.Configuration) *InternalHandlers {
 	svcManager := NewManager(configuration.Services, f.metricsRegistry, f.routinesPool, f.roundTripperManager)
@@ -1,10 +1,12 @@
 package service
 
 import (
+	"bytes"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"reflect"
@@ -26,6 +28,46 @@ func (t *h2cTransportWrapper) RoundTrip(req *http.Request) (*http.Response, erro
 	return t.Transport.RoundTrip(req)
 }
 
+// errHTTP1Response is returned when an h2c server responds over HTTP/1.1.
+var errHTTP1Response = errors.New("h2c: the server responded over HTTP/1.1")
+
+var http1ResponsePrefix = []byte("HTTP/1.")
+
+// h2cConn is a connection to an h2c server,
+// which fails with errHTTP1Response if the server responds over HTTP/1.1 instead of HTTP/2.
+type h2cConn struct {
+	net.Conn
+
+	checked bool
+	pending []byte
+}
+
+func (c *h2cConn) Read(p []byte) (int, error) {
+	if !c.checked {
+		c.checked = true
+
+		prefix := make([]byte, len(http1ResponsePrefix))
+		n, err := io.ReadFull(c.Conn, prefix)
+		if bytes.Equal(prefix[:n], http1ResponsePrefix) {
+			return 0, errHTTP1Response
+		}
+
+		if n == 0 {
+			return 0, err
+		}
+
+		c.pending = prefix[:n]
+	}
+
+	if len(c.pending) > 0 {
+		n := copy(p, c.pending)
+		c.pending = c.pending[n:]
+		return n, nil
+	}
+
+	return c.Conn.Read(p)
+}
+
 // NewRoundTripperManager creates a new RoundTripperManager.
 func NewRoundTripperManager() *RoundTripperManager {
 	return &RoundTripperManager{
@@ -155,13 +197,18 @@ func createRoundTripper(cfg *dynamic.ServersTransport) (http.RoundTripper, error
 	transport.RegisterProtocol("h2c", &h2cTransportWrapper{
 		Transport: &http2.Transport{
 			DialTLS: func(netw, addr string, cfg *tls.Config) (net.Conn, error) {
-				return net.Dial(netw, addr)
+				conn, err := net.Dial(netw, addr)
+				if err != nil {
+					return nil, err
+				}
+
+				return &h2cConn{Conn: conn}, nil
 			},
 			AllowHTTP: true,
 		},
 	})
 
-	return newSmartRoundTripper(transport)
+	return newSmartRoundTripper(transport, cfg.HTTP1Fallback)
 }
 
 func createRootCACertPool(rootCAs []traefiktls.FileOrContent) *x509.CertPool {
@@ -3,9 +3,11 @@ package service
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"io"
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync/atomic"
 	"testing"
 
@@ -293,3 +295,131 @@ func TestDisableHTTP2(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTP1Fallback(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		http1Fallback  bool
+		body           io.Reader
+		expectedError  bool
+		expectedProto  string
+		expectedBodies int32
+	}{
+		{
+			desc:          "h2c client with HTTP1 server",
+			expectedError: true,
+		},
+		{
+			desc:           "h2c client with HTTP1 server and fallback",
+			http1Fallback:  true,
+			expectedProto:  "HTTP/1.1",
+			expectedBodies: 1,
+		},
+		{
+			desc:           "h2c client with HTTP1 server and fallback, with a replayable body",
+			http1Fallback:  true,
+			body:           strings.NewReader("foo"),
+			expectedProto:  "HTTP/1.1",
+			expectedBodies: 1,
+		},
+		{
+			desc:          "h2c client with HTTP1 server and fallback, with a consumed body",
+			http1Fallback: true,
+			body:          io.MultiReader(strings.NewReader("foo")),
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var bodies int32
+			srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := io.ReadAll(req.Body)
+				if err == nil && req.Method != "PRI" && (test.body == nil || string(body) == "foo") {
+					atomic.AddInt32(&bodies, 1)
+				}
+				rw.WriteHeader(http.StatusOK)
+			}))
+			t.Cleanup(srv.Close)
+
+			rtManager := NewRoundTripperManager()
+			rtManager.Update(map[string]*dynamic.ServersTransport{
+				"test": {HTTP1Fallback: test.http1Fallback},
+			})
+
+			tr, err := rtManager.Get("test")
+			require.NoError(t, err)
+
+			method := http.MethodGet
+			if test.body != nil {
+				method = http.MethodPost
+			}
+
+			req, err := http.NewRequest(method, "h2c://"+srv.Listener.Addr().String(), test.body)
+			require.NoError(t, err)
+
+			resp, err := tr.RoundTrip(req)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, test.expectedProto, resp.Proto)
+			assert.Equal(t, test.expectedBodies, atomic.LoadInt32(&bodies))
+		})
+	}
+}
+
+func TestH2cConn(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		data          string
+		expectedData  string
+		expectedError error
+	}{
+		{
+			desc:          "HTTP1 response",
+			data:          "HTTP/1.1 400 Bad Request\r\n\r\n",
+			expectedError: errHTTP1Response,
+		},
+		{
+			desc:         "HTTP2 frame",
+			data:         "\x00\x00\x00\x04\x00\x00\x00\x00\x00",
+			expectedData: "\x00\x00\x00\x04\x00\x00\x00\x00\x00",
+		},
+		{
+			desc:         "short data",
+			data:         "HTTP",
+			expectedData: "HTTP",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			client, server := net.Pipe()
+			go func() {
+				_, _ = server.Write([]byte(test.data))
+				_ = server.Close()
+			}()
+
+			data, err := io.ReadAll(&h2cConn{Conn: client})
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedData, string(data))
+		})
+	}
+}
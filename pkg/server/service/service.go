@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/containous/alice"
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/healthcheck"
@@ -23,7 +24,9 @@ import (
 	"github.com/traefik/traefik/v2/pkg/safe"
 	"github.com/traefik/traefik/v2/pkg/server/cookie"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/hedging"
 	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/mirror"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/priority"
 	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/wrr"
 	"github.com/vulcand/oxy/roundrobin"
 	"github.com/vulcand/oxy/roundrobin/stickycookie"
@@ -36,6 +39,11 @@ const (
 
 const defaultMaxBodySize int64 = -1
 
+const (
+	defaultHedgingPercentile = 95
+	defaultHedgingMaxDelay   = time.Second
+)
+
 // RoundTripperGetter is a roundtripper getter interface.
 type RoundTripperGetter interface {
 	Get(name string) (http.RoundTripper, error)
@@ -76,7 +84,12 @@ func (m *Manager) BuildHTTP(rootCtx context.Context, serviceName string) (http.H
 
 	conf, ok := m.configs[serviceName]
 	if !ok {
-		return nil, fmt.Errorf("the service %q does not exist", serviceName)
+		names := make([]string, 0, len(m.configs))
+		for name := range m.configs {
+			names = append(names, name)
+		}
+
+		return nil, fmt.Errorf("the service %q does not exist%s", serviceName, provider.ReferenceHint(serviceName, names))
 	}
 
 	value := reflect.ValueOf(*conf.Service)
@@ -116,6 +129,20 @@ func (m *Manager) BuildHTTP(rootCtx context.Context, serviceName string) (http.H
 			conf.AddError(err, true)
 			return nil, err
 		}
+	case conf.Priority != nil:
+		var err error
+		lb, err = m.getPriorityServiceHandler(ctx, serviceName, conf.Priority)
+		if err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
+	case conf.Hedging != nil:
+		var err error
+		lb, err = m.getHedgingServiceHandler(ctx, serviceName, conf.Hedging)
+		if err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
 	default:
 		sErr := fmt.Errorf("the service %q does not have any type defined", serviceName)
 		conf.AddError(sErr, true)
@@ -186,6 +213,87 @@ func (m *Manager) getWRRServiceHandler(ctx context.Context, serviceName string,
 	return balancer, nil
 }
 
+func (m *Manager) getPriorityServiceHandler(ctx context.Context, serviceName string, config *dynamic.Priority) (http.Handler, error) {
+	var spillover gokitmetrics.Counter
+	if m.metricsRegistry != nil && m.metricsRegistry.IsSvcEnabled() {
+		spillover = m.metricsRegistry.ServiceSpilloverCounter().With("service", serviceName)
+	}
+
+	balancer := priority.New(config.HealthCheck, spillover)
+	for _, service := range config.Services {
+		serviceHandler, err := m.BuildHTTP(ctx, service.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		balancer.AddService(service.Name, serviceHandler, service.MaxConcurrentRequests)
+		if config.HealthCheck == nil {
+			continue
+		}
+
+		childName := service.Name
+		updater, ok := serviceHandler.(healthcheck.StatusUpdater)
+		if !ok {
+			return nil, fmt.Errorf("child service %v of %v not a healthcheck.StatusUpdater (%T)", childName, serviceName, serviceHandler)
+		}
+
+		if err := updater.RegisterStatusUpdater(func(up bool) {
+			balancer.SetStatus(ctx, childName, up)
+		}); err != nil {
+			return nil, fmt.Errorf("cannot register %v as updater for %v: %w", childName, serviceName, err)
+		}
+
+		log.FromContext(ctx).Debugf("Child service %v will update parent %v on status change", childName, serviceName)
+	}
+
+	return balancer, nil
+}
+
+func (m *Manager) getHedgingServiceHandler(ctx context.Context, serviceName string, config *dynamic.Hedging) (http.Handler, error) {
+	percentile := config.Percentile
+	if percentile == 0 {
+		percentile = defaultHedgingPercentile
+	}
+
+	maxDelay := time.Duration(config.MaxDelay)
+	if maxDelay == 0 {
+		maxDelay = defaultHedgingMaxDelay
+	}
+
+	balancer, err := hedging.New(percentile, time.Duration(config.MinDelay), maxDelay, config.HealthCheck)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, service := range config.Services {
+		serviceHandler, err := m.BuildHTTP(ctx, service.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		balancer.AddService(service.Name, serviceHandler)
+		if config.HealthCheck == nil {
+			continue
+		}
+
+		childName := service.Name
+		updater, ok := serviceHandler.(healthcheck.StatusUpdater)
+		if !ok {
+			return nil, fmt.Errorf("child service %v of %v not a healthcheck.StatusUpdater (%T)", childName, serviceName, serviceHandler)
+		}
+
+		if err := updater.RegisterStatusUpdater(func(up bool) {
+			balancer.SetStatus(ctx, childName, up)
+		}); err != nil {
+			return nil, fmt.Errorf("cannot register %v as updater for %v: %w", childName, serviceName, err)
+		}
+
+		log.FromContext(ctx).Debugf("Child service %v will update parent %v on status change", childName, serviceName)
+	}
+
+	return balancer, nil
+}
+
 func (m *Manager) getLoadBalancerServiceHandler(ctx context.Context, serviceName string, service *dynamic.ServersLoadBalancer) (http.Handler, error) {
 	if service.PassHostHeader == nil {
 		defaultPassHostHeader := true
@@ -363,6 +363,58 @@ func TestManager_Build(t *testing.T) {
 			},
 			providerName: "provider-1",
 		},
+		{
+			desc:        "Priority service",
+			serviceName: "serviceName@provider-1",
+			configs: map[string]*runtime.ServiceInfo{
+				"serviceName@provider-1": {
+					Service: &dynamic.Service{
+						Priority: &dynamic.Priority{
+							Services: []dynamic.PriorityService{
+								{Name: "spot", MaxConcurrentRequests: 10},
+								{Name: "reserved"},
+							},
+						},
+					},
+				},
+				"spot@provider-1": {
+					Service: &dynamic.Service{
+						LoadBalancer: &dynamic.ServersLoadBalancer{},
+					},
+				},
+				"reserved@provider-1": {
+					Service: &dynamic.Service{
+						LoadBalancer: &dynamic.ServersLoadBalancer{},
+					},
+				},
+			},
+		},
+		{
+			desc:        "Hedging service",
+			serviceName: "serviceName@provider-1",
+			configs: map[string]*runtime.ServiceInfo{
+				"serviceName@provider-1": {
+					Service: &dynamic.Service{
+						Hedging: &dynamic.Hedging{
+							Services: []dynamic.HedgingService{
+								{Name: "foo"},
+								{Name: "bar"},
+							},
+						},
+					},
+				},
+				"foo@provider-1": {
+					Service: &dynamic.Service{
+						LoadBalancer: &dynamic.ServersLoadBalancer{},
+					},
+				},
+				"bar@provider-1": {
+					Service: &dynamic.Service{
+						LoadBalancer: &dynamic.ServersLoadBalancer{},
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range testCases {
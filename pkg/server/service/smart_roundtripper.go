@@ -1,13 +1,15 @@
 package service
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/traefik/traefik/v2/pkg/log"
 	"golang.org/x/net/http/httpguts"
 	"golang.org/x/net/http2"
 )
 
-func newSmartRoundTripper(transport *http.Transport) (http.RoundTripper, error) {
+func newSmartRoundTripper(transport *http.Transport, http1Fallback bool) (http.RoundTripper, error) {
 	transportHTTP1 := transport.Clone()
 
 	err := http2.ConfigureTransport(transport)
@@ -16,14 +18,18 @@ func newSmartRoundTripper(transport *http.Transport) (http.RoundTripper, error)
 	}
 
 	return &smartRoundTripper{
-		http2: transport,
-		http:  transportHTTP1,
+		http2:         transport,
+		http:          transportHTTP1,
+		http1Fallback: http1Fallback,
 	}, nil
 }
 
 type smartRoundTripper struct {
 	http2 *http.Transport
 	http  *http.Transport
+
+	// http1Fallback enables the retry over HTTP/1.1 of the requests failing with an HTTP/2 protocol error.
+	http1Fallback bool
 }
 
 // smartRoundTripper implements RoundTrip while making sure that HTTP/2 is not used
@@ -34,5 +40,79 @@ func (m *smartRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 		return m.http.RoundTrip(req)
 	}
 
-	return m.http2.RoundTrip(req)
+	if !m.http1Fallback {
+		return m.http2.RoundTrip(req)
+	}
+
+	// The h2c transport rewrites the scheme of the request, so it is saved beforehand.
+	scheme := req.URL.Scheme
+
+	resp, err := m.http2.RoundTrip(req)
+	if err == nil || !isHTTP2ProtocolError(err) {
+		return resp, err
+	}
+
+	fallbackReq, ok := rewindRequest(req)
+	if !ok {
+		return nil, err
+	}
+
+	if scheme == "h2c" {
+		fallbackReq.URL.Scheme = "http"
+	}
+
+	log.FromContext(req.Context()).Debugf("Retrying over HTTP/1.1 the request to %s after an HTTP/2 protocol error: %v", fallbackReq.URL.Host, err)
+
+	return m.http.RoundTrip(fallbackReq)
+}
+
+// isHTTP2ProtocolError reports whether the error is an HTTP/2 protocol-level error,
+// i.e. an error which would not have happened over HTTP/1.1.
+func isHTTP2ProtocolError(err error) bool {
+	if errors.Is(err, errHTTP1Response) {
+		return true
+	}
+
+	var connErr http2.ConnectionError
+	if errors.As(err, &connErr) {
+		return true
+	}
+
+	var streamErr http2.StreamError
+	if errors.As(err, &streamErr) {
+		return isHTTP2ProtocolErrCode(streamErr.Code)
+	}
+
+	var goAwayErr http2.GoAwayError
+	if errors.As(err, &goAwayErr) {
+		return isHTTP2ProtocolErrCode(goAwayErr.ErrCode)
+	}
+
+	return false
+}
+
+func isHTTP2ProtocolErrCode(code http2.ErrCode) bool {
+	return code == http2.ErrCodeProtocol || code == http2.ErrCodeHTTP11Required
+}
+
+// rewindRequest returns a copy of the request which can be sent again,
+// or false if its body has already been consumed.
+func rewindRequest(req *http.Request) (*http.Request, bool) {
+	newReq := req.Clone(req.Context())
+
+	if req.Body == nil || req.Body == http.NoBody {
+		return newReq, true
+	}
+
+	if req.GetBody == nil {
+		return nil, false
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+
+	newReq.Body = body
+	return newReq, true
 }
@@ -7,21 +7,35 @@ import (
 	"net"
 	"time"
 
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
 	"github.com/traefik/traefik/v2/pkg/tcp"
 )
 
+type contextKey int
+
+const routerKey contextKey = iota
+
+// AddRouterInContext adds the name of the router the services are built for in the context,
+// to label the metrics of their connections.
+func AddRouterInContext(ctx context.Context, routerName string) context.Context {
+	return context.WithValue(ctx, routerKey, routerName)
+}
+
 // Manager is the TCPHandlers factory.
 type Manager struct {
-	configs map[string]*runtime.TCPServiceInfo
+	configs         map[string]*runtime.TCPServiceInfo
+	metricsRegistry metrics.Registry
 }
 
 // NewManager creates a new manager.
-func NewManager(conf *runtime.Configuration) *Manager {
+func NewManager(conf *runtime.Configuration, metricsRegistry metrics.Registry) *Manager {
 	return &Manager{
-		configs: conf.TCPServices,
+		configs:         conf.TCPServices,
+		metricsRegistry: metricsRegistry,
 	}
 }
 
@@ -33,10 +47,15 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 
 	conf, ok := m.configs[serviceQualifiedName]
 	if !ok {
-		return nil, fmt.Errorf("the service %q does not exist", serviceQualifiedName)
+		names := make([]string, 0, len(m.configs))
+		for name := range m.configs {
+			names = append(names, name)
+		}
+
+		return nil, fmt.Errorf("the service %q does not exist%s", serviceQualifiedName, provider.ReferenceHint(serviceQualifiedName, names))
 	}
 
-	if conf.LoadBalancer != nil && conf.Weighted != nil {
+	if countTypes(conf.TCPService) > 1 {
 		err := errors.New("cannot create service: multi-types service not supported, consider declaring two different pieces of service instead")
 		conf.AddError(err, true)
 		return nil, err
@@ -59,7 +78,7 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 				continue
 			}
 
-			handler, err := tcp.NewProxy(server.Address, duration, conf.LoadBalancer.ProxyProtocol)
+			handler, err := tcp.NewProxy(server.Address, duration, conf.LoadBalancer.ProxyProtocol, m.proxyMetrics(ctx, serviceQualifiedName, server.Address))
 			if err != nil {
 				logger.Errorf("In service %q server %q: %v", serviceQualifiedName, server.Address, err)
 				continue
@@ -80,9 +99,54 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 			loadBalancer.AddWeightServer(handler, service.Weight)
 		}
 		return loadBalancer, nil
+	case conf.Tunnel != nil:
+		tunnel := tcp.NewTunnel(conf.Tunnel.Tokens)
+		for _, service := range conf.Tunnel.Services {
+			if service.Name == "" {
+				err := errors.New("a tunnel service must have a name")
+				conf.AddError(err, true)
+				return nil, err
+			}
+
+			handler, err := m.BuildTCP(rootCtx, service.Service)
+			if err != nil {
+				logger.Errorf("In service %q: %v", serviceQualifiedName, err)
+				return nil, err
+			}
+			tunnel.AddHandler(service.Name, handler)
+		}
+		return tunnel, nil
 	default:
 		err := fmt.Errorf("the service %q does not have any type defined", serviceQualifiedName)
 		conf.AddError(err, true)
 		return nil, err
 	}
 }
+
+// countTypes returns the number of types defined by the given service.
+func countTypes(service *dynamic.TCPService) int {
+	var count int
+	for _, defined := range []bool{service.LoadBalancer != nil, service.Weighted != nil, service.Tunnel != nil} {
+		if defined {
+			count++
+		}
+	}
+
+	return count
+}
+
+// proxyMetrics returns the metrics of the connections to a server, or nil if the TCP metrics are disabled.
+func (m *Manager) proxyMetrics(ctx context.Context, serviceName, address string) *tcp.ProxyMetrics {
+	if m.metricsRegistry == nil || !m.metricsRegistry.IsTCPEnabled() {
+		return nil
+	}
+
+	routerName, _ := ctx.Value(routerKey).(string)
+	labels := []string{"router", routerName, "service", serviceName, "server", address}
+
+	return &tcp.ProxyMetrics{
+		ConnDurationHistogram: m.metricsRegistry.TCPConnDurationHistogram().With(labels...),
+		BytesCounter:          m.metricsRegistry.TCPBytesCounter().With(labels...),
+		ConnErrorsCounter:     m.metricsRegistry.TCPConnErrorsCounter().With(labels...),
+	}
+}
@@ -25,6 +25,19 @@ func TestManager_BuildTCP(t *testing.T) {
 			configs:       nil,
 			expectedError: `the service "test" does not exist`,
 		},
+		{
+			desc:        "service defined by another provider",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-2": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{},
+					},
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: `the service "serviceName@provider-1" does not exist (did you mean "serviceName@provider-2"?)`,
+		},
 		{
 			desc:        "missing lb configuration",
 			serviceName: "test",
@@ -171,6 +184,55 @@ func TestManager_BuildTCP(t *testing.T) {
 			},
 			providerName: "provider-1",
 		},
+		{
+			desc:        "tunnel",
+			serviceName: "tunnel",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"tunnel@provider-1": {
+					TCPService: &dynamic.TCPService{
+						Tunnel: &dynamic.TCPTunnel{
+							Services: []dynamic.TCPTunnelService{{Name: "db", Service: "db"}},
+							Tokens:   []string{"secret"},
+						},
+					},
+				},
+				"db@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{{Address: "192.168.0.12:5432"}},
+						},
+					},
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "tunnel to a missing service",
+			serviceName: "tunnel",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"tunnel": {
+					TCPService: &dynamic.TCPService{
+						Tunnel: &dynamic.TCPTunnel{
+							Services: []dynamic.TCPTunnelService{{Name: "db", Service: "db"}},
+						},
+					},
+				},
+			},
+			expectedError: `the service "db" does not exist`,
+		},
+		{
+			desc:        "multi-types service",
+			serviceName: "test",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"test": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{},
+						Tunnel:       &dynamic.TCPTunnel{},
+					},
+				},
+			},
+			expectedError: "cannot create service: multi-types service not supported, consider declaring two different pieces of service instead",
+		},
 	}
 
 	for _, test := range testCases {
@@ -180,7 +242,7 @@ func TestManager_BuildTCP(t *testing.T) {
 
 			manager := NewManager(&runtime.Configuration{
 				TCPServices: test.configs,
-			})
+			}, nil)
 
 			ctx := context.Background()
 			if len(test.providerName) > 0 {
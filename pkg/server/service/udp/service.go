@@ -32,7 +32,12 @@ func (m *Manager) BuildUDP(rootCtx context.Context, serviceName string) (udp.Han
 
 	conf, ok := m.configs[serviceQualifiedName]
 	if !ok {
-		return nil, fmt.Errorf("the udp service %q does not exist", serviceQualifiedName)
+		names := make([]string, 0, len(m.configs))
+		for name := range m.configs {
+			names = append(names, name)
+		}
+
+		return nil, fmt.Errorf("the udp service %q does not exist%s", serviceQualifiedName, provider.ReferenceHint(serviceQualifiedName, names))
 	}
 
 	if conf.LoadBalancer != nil && conf.Weighted != nil {
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+// EntryPointTimeouts returns the timeouts of the given entry points,
+// to check the timeouts of the dynamic configuration against them.
+func EntryPointTimeouts(entryPoints static.EntryPoints) map[string]runtime.EntryPointTimeouts {
+	timeouts := make(map[string]runtime.EntryPointTimeouts)
+	for name, ep := range entryPoints {
+		if ep == nil || ep.Transport == nil || ep.Transport.RespondingTimeouts == nil {
+			continue
+		}
+
+		if protocol, err := ep.GetProtocol(); err != nil || protocol == "udp" {
+			continue
+		}
+
+		timeouts[name] = runtime.EntryPointTimeouts{
+			WriteTimeout: time.Duration(ep.Transport.RespondingTimeouts.WriteTimeout),
+		}
+	}
+
+	return timeouts
+}
@@ -1,16 +1,30 @@
 package tcp
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net"
+	"syscall"
 	"time"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/pires/go-proxyproto"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
 )
 
+// ProxyMetrics holds the metrics of the connections forwarded by a Proxy, with their labels already set.
+// The bytes counter is further partitioned by direction: "in" for the bytes received from the client,
+// and "out" for the bytes sent to the client.
+// The errors counter is further partitioned by reason: "dial", "reset", "timeout", or "other".
+type ProxyMetrics struct {
+	ConnDurationHistogram metrics.ScalableHistogram
+	BytesCounter          gokitmetrics.Counter
+	ConnErrorsCounter     gokitmetrics.Counter
+}
+
 // Proxy forwards a TCP request to a TCP service.
 type Proxy struct {
 	address          string
@@ -18,10 +32,12 @@ type Proxy struct {
 	terminationDelay time.Duration
 	proxyProtocol    *dynamic.ProxyProtocol
 	refreshTarget    bool
+	metrics          *ProxyMetrics
 }
 
 // NewProxy creates a new Proxy.
-func NewProxy(address string, terminationDelay time.Duration, proxyProtocol *dynamic.ProxyProtocol) (*Proxy, error) {
+// The metrics of the forwarded connections are recorded if proxyMetrics is not nil.
+func NewProxy(address string, terminationDelay time.Duration, proxyProtocol *dynamic.ProxyProtocol, proxyMetrics *ProxyMetrics) (*Proxy, error) {
 	tcpAddr, err := net.ResolveTCPAddr("tcp", address)
 	if err != nil {
 		return nil, err
@@ -43,6 +59,7 @@ func NewProxy(address string, terminationDelay time.Duration, proxyProtocol *dyn
 		refreshTarget:    refreshTarget,
 		terminationDelay: terminationDelay,
 		proxyProtocol:    proxyProtocol,
+		metrics:          proxyMetrics,
 	}, nil
 }
 
@@ -53,9 +70,12 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 	// needed because of e.g. server.trackedConnection
 	defer conn.Close()
 
+	start := time.Now()
+
 	connBackend, err := p.dialBackend()
 	if err != nil {
 		log.WithoutContext().Errorf("Error while connecting to backend: %v", err)
+		p.countError("dial")
 		return
 	}
 
@@ -71,15 +91,45 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 		}
 	}
 
-	go p.connCopy(conn, connBackend, errChan)
-	go p.connCopy(connBackend, conn, errChan)
+	var bytesIn, bytesOut int64
+
+	go p.connCopy(conn, connBackend, "out", &bytesOut, errChan)
+	go p.connCopy(connBackend, conn, "in", &bytesIn, errChan)
 
 	err = <-errChan
 	if err != nil {
 		log.WithoutContext().Errorf("Error during connection: %v", err)
+		p.countError(errorReason(err))
 	}
 
 	<-errChan
+
+	log.WithoutContext().Debugf("Connection from %s to %s closed after %s: %d bytes in, %d bytes out",
+		conn.RemoteAddr(), p.address, time.Since(start), bytesIn, bytesOut)
+
+	if p.metrics != nil && p.metrics.ConnDurationHistogram != nil {
+		p.metrics.ConnDurationHistogram.ObserveFromStart(start)
+	}
+}
+
+func (p Proxy) countError(reason string) {
+	if p.metrics != nil && p.metrics.ConnErrorsCounter != nil {
+		p.metrics.ConnErrorsCounter.With("reason", reason).Add(1)
+	}
+}
+
+// errorReason returns the reason, as reported by the metrics, of an error interrupting a connection.
+func errorReason(err error) string {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return "timeout"
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return "reset"
+	}
+
+	return "other"
 }
 
 func (p Proxy) dialBackend() (*net.TCPConn, error) {
@@ -95,8 +145,15 @@ func (p Proxy) dialBackend() (*net.TCPConn, error) {
 	return conn.(*net.TCPConn), nil
 }
 
-func (p Proxy) connCopy(dst, src WriteCloser, errCh chan error) {
-	_, err := io.Copy(dst, src)
+func (p Proxy) connCopy(dst, src WriteCloser, direction string, written *int64, errCh chan error) {
+	var w io.Writer = dst
+	if p.metrics != nil && p.metrics.BytesCounter != nil {
+		// Counts the bytes as they are written, so that long-lived connections are not invisible until they are closed.
+		w = &countingWriter{Writer: dst, counter: p.metrics.BytesCounter.With("direction", direction)}
+	}
+
+	n, err := io.Copy(w, src)
+	*written = n
 	errCh <- err
 
 	errClose := dst.CloseWrite()
@@ -112,3 +169,16 @@ func (p Proxy) connCopy(dst, src WriteCloser, errCh chan error) {
 		}
 	}
 }
+
+type countingWriter struct {
+	io.Writer
+	counter gokitmetrics.Counter
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	if n > 0 {
+		w.counter.Add(float64(n))
+	}
+	return n, err
+}
@@ -6,13 +6,17 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/pires/go-proxyproto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/metrics"
 )
 
 func fakeRedis(t *testing.T, listener net.Listener) {
@@ -54,7 +58,7 @@ func TestCloseWrite(t *testing.T) {
 	_, port, err := net.SplitHostPort(backendListener.Addr().String())
 	require.NoError(t, err)
 
-	proxy, err := NewProxy(":"+port, 10*time.Millisecond, nil)
+	proxy, err := NewProxy(":"+port, 10*time.Millisecond, nil, nil)
 	require.NoError(t, err)
 
 	proxyListener, err := net.Listen("tcp", ":0")
@@ -88,6 +92,68 @@ func TestCloseWrite(t *testing.T) {
 	require.Equal(t, "PONG", buffer.String())
 }
 
+func TestProxy_metrics(t *testing.T) {
+	backendListener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+
+	go fakeRedis(t, backendListener)
+
+	bytesCounter := newCounterMock()
+	errorsCounter := newCounterMock()
+	durationHistogram := &histogramMock{}
+
+	proxy, err := NewProxy(backendListener.Addr().String(), 10*time.Millisecond, nil, &ProxyMetrics{
+		ConnDurationHistogram: durationHistogram,
+		BytesCounter:          bytesCounter,
+		ConnErrorsCounter:     errorsCounter,
+	})
+	require.NoError(t, err)
+
+	clientConn, serverConn := tcpConnPair(t)
+
+	done := make(chan struct{})
+	go func() {
+		proxy.ServeTCP(serverConn)
+		close(done)
+	}()
+
+	_, err = clientConn.Write([]byte("ping\n"))
+	require.NoError(t, err)
+
+	err = clientConn.CloseWrite()
+	require.NoError(t, err)
+
+	response, err := io.ReadAll(clientConn)
+	require.NoError(t, err)
+	assert.Equal(t, "PONG", string(response))
+
+	<-done
+
+	assert.Equal(t, map[string]float64{"direction:in": 5, "direction:out": 4}, bytesCounter.get())
+	assert.Empty(t, errorsCounter.get())
+	assert.Equal(t, 1, durationHistogram.count())
+}
+
+func TestProxy_metricsDialError(t *testing.T) {
+	backendListener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+
+	// Nothing listens on the backend address anymore.
+	require.NoError(t, backendListener.Close())
+
+	errorsCounter := newCounterMock()
+
+	proxy, err := NewProxy(backendListener.Addr().String(), 10*time.Millisecond, nil, &ProxyMetrics{ConnErrorsCounter: errorsCounter})
+	require.NoError(t, err)
+
+	clientConn, serverConn := tcpConnPair(t)
+	defer clientConn.Close()
+
+	proxy.ServeTCP(serverConn)
+
+	assert.Equal(t, map[string]float64{"reason:dial": 1}, errorsCounter.get())
+}
+
 func TestProxyProtocol(t *testing.T) {
 	testCases := []struct {
 		desc    string
@@ -133,7 +199,7 @@ func TestProxyProtocol(t *testing.T) {
 			_, port, err := net.SplitHostPort(proxyBackendListener.Addr().String())
 			require.NoError(t, err)
 
-			proxy, err := NewProxy(":"+port, 10*time.Millisecond, &dynamic.ProxyProtocol{Version: test.version})
+			proxy, err := NewProxy(":"+port, 10*time.Millisecond, &dynamic.ProxyProtocol{Version: test.version}, nil)
 			require.NoError(t, err)
 
 			proxyListener, err := net.Listen("tcp", ":0")
@@ -194,7 +260,7 @@ func TestLookupAddress(t *testing.T) {
 		t.Run(test.desc, func(t *testing.T) {
 			t.Parallel()
 
-			proxy, err := NewProxy(test.address, 10*time.Millisecond, nil)
+			proxy, err := NewProxy(test.address, 10*time.Millisecond, nil, nil)
 			require.NoError(t, err)
 
 			require.NotNil(t, proxy.target)
@@ -210,3 +276,75 @@ func TestLookupAddress(t *testing.T) {
 		})
 	}
 }
+
+// tcpConnPair returns both ends of a TCP connection.
+func tcpConnPair(t *testing.T) (*net.TCPConn, *net.TCPConn) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	clientConn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+
+	serverConn, err := listener.Accept()
+	require.NoError(t, err)
+
+	return clientConn.(*net.TCPConn), serverConn.(*net.TCPConn)
+}
+
+type counterMock struct {
+	mu     *sync.Mutex
+	labels []string
+	values map[string]float64
+}
+
+func newCounterMock() *counterMock {
+	return &counterMock{mu: &sync.Mutex{}, values: make(map[string]float64)}
+}
+
+func (c *counterMock) With(labelValues ...string) gokitmetrics.Counter {
+	return &counterMock{mu: c.mu, labels: append(c.labels, labelValues...), values: c.values}
+}
+
+func (c *counterMock) Add(delta float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.values[strings.Join(c.labels, ":")] += delta
+}
+
+func (c *counterMock) get() map[string]float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.values
+}
+
+type histogramMock struct {
+	mu           sync.Mutex
+	observations int
+}
+
+func (h *histogramMock) With(...string) metrics.ScalableHistogram {
+	return h
+}
+
+func (h *histogramMock) Observe(float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.observations++
+}
+
+func (h *histogramMock) ObserveFromStart(time.Time) {
+	h.Observe(0)
+}
+
+func (h *histogramMock) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.observations
+}
@@ -0,0 +1,70 @@
+package tcp
+
+import (
+	"github.com/mailgun/ttlmap"
+	"golang.org/x/time/rate"
+)
+
+const maxSources = 65536
+
+// RateLimiter limits the rate of events (e.g. TLS handshakes) with a set of token buckets,
+// one for each key. The same parameters are applied to all the buckets.
+type RateLimiter struct {
+	rate  rate.Limit
+	burst int
+	// ttl is the number of seconds after which an unused bucket is garbage collected.
+	ttl int
+
+	buckets *ttlmap.TtlMap
+}
+
+// NewRateLimiter creates a RateLimiter allowing, for each key,
+// an average of average events per second with bursts of at most burst events.
+func NewRateLimiter(average, burst int64) (*RateLimiter, error) {
+	return newRateLimiter(average, burst, maxSources)
+}
+
+func newRateLimiter(average, burst int64, capacity int) (*RateLimiter, error) {
+	buckets, err := ttlmap.NewConcurrent(capacity)
+	if err != nil {
+		return nil, err
+	}
+
+	if burst < 1 {
+		burst = 1
+	}
+
+	// Makes the ttl inversely proportional to how often a bucket is supposed to see any activity,
+	// so that a bucket is not garbage collected before it has been refilled.
+	ttl := 2
+	if average > 0 && average < burst {
+		ttl += int(burst / average)
+	}
+
+	return &RateLimiter{
+		rate:    rate.Limit(average),
+		burst:   int(burst),
+		ttl:     ttl,
+		buckets: buckets,
+	}, nil
+}
+
+// Allow reports whether an event for the given key may happen now.
+func (r *RateLimiter) Allow(key string) bool {
+	var bucket *rate.Limiter
+	if b, exists := r.buckets.Get(key); exists {
+		bucket = b.(*rate.Limiter)
+	} else {
+		bucket = rate.NewLimiter(r.rate, r.burst)
+	}
+
+	// We Set even in the case where the key already exists,
+	// because we want to update the expiryTime every time we get the key.
+	// When the buckets are full, the bucket closest to its expiry is evicted to make room for the new one.
+	if err := r.buckets.Set(key, bucket, r.ttl); err != nil {
+		// The event is rejected rather than let through unlimited.
+		return false
+	}
+
+	return bucket.Allow()
+}
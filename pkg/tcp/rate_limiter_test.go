@@ -0,0 +1,41 @@
+package tcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	limiter, err := NewRateLimiter(1, 3)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		assert.True(t, limiter.Allow("10.0.0.1"))
+	}
+	assert.False(t, limiter.Allow("10.0.0.1"))
+
+	// Each key has its own bucket.
+	assert.True(t, limiter.Allow("10.0.0.2"))
+}
+
+func TestRateLimiter_minimalBurst(t *testing.T) {
+	limiter, err := NewRateLimiter(1, 0)
+	require.NoError(t, err)
+
+	assert.True(t, limiter.Allow("foo.bar"))
+	assert.False(t, limiter.Allow("foo.bar"))
+}
+
+func TestRateLimiter_full(t *testing.T) {
+	limiter, err := newRateLimiter(1, 1, 2)
+	require.NoError(t, err)
+
+	assert.True(t, limiter.Allow("10.0.0.1"))
+	assert.True(t, limiter.Allow("10.0.0.2"))
+
+	// The new keys are still limited once the buckets are full.
+	assert.True(t, limiter.Allow("10.0.0.3"))
+	assert.False(t, limiter.Allow("10.0.0.3"))
+}
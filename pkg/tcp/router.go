@@ -27,6 +27,10 @@ type Router struct {
 	httpsTLSConfig    *tls.Config // default TLS config
 	catchAllNoTLS     Handler
 	hostHTTPTLSConfig map[string]*tls.Config // TLS configs keyed by SNI
+
+	// Rate limiters of the TLS handshakes, keyed by source IP and by SNI.
+	sourceIPHandshakeLimiter   *RateLimiter
+	serverNameHandshakeLimiter *RateLimiter
 }
 
 // GetTLSGetClientInfo is called after a ClientHello is received from a client.
@@ -80,26 +84,41 @@ func (r *Router) ServeTCP(conn WriteCloser) {
 
 	// FIXME Optimize and test the routing table before helloServerName
 	serverName = types.CanonicalDomain(serverName)
+
 	if r.routingTable != nil && serverName != "" {
 		if target, ok := r.routingTable[serverName]; ok {
-			target.ServeTCP(r.GetConn(conn, peeked))
+			r.serveTLS(conn, target, serverName, peeked)
 			return
 		}
 	}
 
+	// The server names which are not routed share the same rate limit,
+	// as they can be freely chosen by the clients.
 	// FIXME Needs tests
 	if target, ok := r.routingTable["*"]; ok {
-		target.ServeTCP(r.GetConn(conn, peeked))
+		r.serveTLS(conn, target, "", peeked)
 		return
 	}
 
 	if r.httpsForwarder != nil {
-		r.httpsForwarder.ServeTCP(r.GetConn(conn, peeked))
+		r.serveTLS(conn, r.httpsForwarder, "", peeked)
 	} else {
 		conn.Close()
 	}
 }
 
+// serveTLS forwards the TLS connection to the target.
+// When Traefik terminates the TLS connection, the handshake must be allowed by the rate limiters first,
+// the TLS passthrough connections are not limited.
+func (r *Router) serveTLS(conn WriteCloser, target Handler, serverName, peeked string) {
+	if _, terminated := target.(*TLSHandler); terminated && !r.allowHandshake(conn, serverName) {
+		conn.Close()
+		return
+	}
+
+	target.ServeTCP(r.GetConn(conn, peeked))
+}
+
 // AddRoute defines a handler for a given sniHost (* is the only valid option).
 func (r *Router) AddRoute(sniHost string, target Handler) {
 	if r.routingTable == nil {
@@ -124,6 +143,36 @@ func (r *Router) AddRouteHTTPTLS(sniHost string, config *tls.Config) {
 	r.hostHTTPTLSConfig[sniHost] = config
 }
 
+// SetHandshakeRateLimiters sets the rate limiters applied to the TLS handshakes, per source IP and per SNI.
+// A nil rate limiter disables the corresponding limit.
+func (r *Router) SetHandshakeRateLimiters(sourceIP, serverName *RateLimiter) {
+	r.sourceIPHandshakeLimiter = sourceIP
+	r.serverNameHandshakeLimiter = serverName
+}
+
+// allowHandshake reports whether the TLS handshake of the given connection is allowed by the rate limiters.
+// The server name is the SNI of a route, or empty for the server names which are not routed.
+func (r *Router) allowHandshake(conn WriteCloser, serverName string) bool {
+	if r.sourceIPHandshakeLimiter != nil {
+		sourceIP, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+		if err != nil {
+			sourceIP = conn.RemoteAddr().String()
+		}
+
+		if !r.sourceIPHandshakeLimiter.Allow(sourceIP) {
+			log.WithoutContext().Debugf("TLS handshake rate limit reached for source IP %s", sourceIP)
+			return false
+		}
+	}
+
+	if r.serverNameHandshakeLimiter != nil && !r.serverNameHandshakeLimiter.Allow(serverName) {
+		log.WithoutContext().Debugf("TLS handshake rate limit reached for server name %q", serverName)
+		return false
+	}
+
+	return true
+}
+
 // AddCatchAllNoTLS defines the fallback tcp handler.
 func (r *Router) AddCatchAllNoTLS(handler Handler) {
 	r.catchAllNoTLS = handler
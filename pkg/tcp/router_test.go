@@ -0,0 +1,62 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pipeConn struct {
+	net.Conn
+}
+
+func (c pipeConn) CloseWrite() error {
+	return nil
+}
+
+func TestRouter_handshakeRateLimit(t *testing.T) {
+	serverNameLimiter, err := NewRateLimiter(1, 1)
+	require.NoError(t, err)
+
+	var served string
+	handler := func(name string) Handler {
+		return HandlerFunc(func(conn WriteCloser) {
+			served = name
+			_ = conn.Close()
+		})
+	}
+
+	router := &Router{}
+	router.AddRoute("passthrough.example.com", handler("passthrough"))
+	router.AddRouteTLS("tls.example.com", handler("tls"), &tls.Config{})
+	router.HTTPSHandler(nil, &tls.Config{})
+	router.HTTPSForwarder(handler("https"))
+	router.SetHandshakeRateLimiters(nil, serverNameLimiter)
+
+	serve := func(serverName string) string {
+		server, client := net.Pipe()
+		go func() {
+			_ = tls.Client(client, &tls.Config{ServerName: serverName, InsecureSkipVerify: true}).Handshake()
+			_ = client.Close()
+		}()
+
+		served = ""
+		router.ServeTCP(pipeConn{server})
+
+		return served
+	}
+
+	assert.Equal(t, "tls", serve("tls.example.com"))
+	assert.Empty(t, serve("tls.example.com"))
+
+	// The TLS passthrough connections are not limited.
+	assert.Equal(t, "passthrough", serve("passthrough.example.com"))
+	assert.Equal(t, "passthrough", serve("passthrough.example.com"))
+
+	// The server names which are not routed share the same limit.
+	assert.Equal(t, "https", serve("foo.example.com"))
+	assert.Empty(t, serve("bar.example.com"))
+}
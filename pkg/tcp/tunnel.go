@@ -0,0 +1,160 @@
+package tcp
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// tunnelRequestTimeout is the maximum duration for reading the tunnel request of the client.
+const tunnelRequestTimeout = 10 * time.Second
+
+// Tunnel forwards each connection to the handler requested by the client with an HTTP CONNECT request,
+// e.g. "CONNECT db HTTP/1.1", once the client is authenticated either with a verified TLS client certificate,
+// or with one of the tokens in the Proxy-Authorization header, as a bearer token or as the password of the basic scheme.
+type Tunnel struct {
+	handlers map[string]Handler
+	tokens   []string
+}
+
+// NewTunnel creates a new Tunnel accepting the given tokens.
+func NewTunnel(tokens []string) *Tunnel {
+	return &Tunnel{
+		handlers: make(map[string]Handler),
+		tokens:   tokens,
+	}
+}
+
+// AddHandler makes the given handler reachable through the tunnel under the given name.
+func (t *Tunnel) AddHandler(name string, handler Handler) {
+	t.handlers[name] = handler
+}
+
+// ServeTCP reads the tunnel request of the client, and forwards the connection to the requested handler.
+func (t *Tunnel) ServeTCP(conn WriteCloser) {
+	logger := log.WithoutContext().WithField("remoteAddr", conn.RemoteAddr().String())
+
+	if err := conn.SetReadDeadline(time.Now().Add(tunnelRequestTimeout)); err != nil {
+		logger.Errorf("Error while setting the read deadline of the tunnel request: %v", err)
+		_ = conn.Close()
+		return
+	}
+
+	br := bufio.NewReader(conn)
+	req, err := http.ReadRequest(br)
+	if err != nil {
+		logger.Debugf("Error while reading the tunnel request: %v", err)
+		t.reject(conn, http.StatusBadRequest)
+		return
+	}
+
+	if req.Method != http.MethodConnect {
+		logger.Debugf("Unexpected %s tunnel request", req.Method)
+		t.reject(conn, http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !t.authenticated(conn, req) {
+		logger.Debugf("Unauthenticated tunnel request to %q", req.Host)
+		t.reject(conn, http.StatusProxyAuthRequired)
+		return
+	}
+
+	name := req.Host
+	if host, _, err := net.SplitHostPort(name); err == nil {
+		name = host
+	}
+
+	handler, ok := t.handlers[name]
+	if !ok {
+		logger.Debugf("Tunnel request to the unknown service %q", name)
+		t.reject(conn, http.StatusNotFound)
+		return
+	}
+
+	if err := conn.SetReadDeadline(time.Time{}); err != nil {
+		logger.Errorf("Error while resetting the read deadline: %v", err)
+		_ = conn.Close()
+		return
+	}
+
+	if _, err := fmt.Fprint(conn, "HTTP/1.1 200 Connection established\r\n\r\n"); err != nil {
+		logger.Debugf("Error while answering the tunnel request: %v", err)
+		_ = conn.Close()
+		return
+	}
+
+	// The bytes sent by the client right after its request are forwarded too.
+	var peeked []byte
+	if br.Buffered() > 0 {
+		peeked, _ = br.Peek(br.Buffered())
+	}
+
+	handler.ServeTCP(&Conn{Peeked: peeked, WriteCloser: conn})
+}
+
+// authenticated reports whether the client is authenticated,
+// either with a verified TLS client certificate or with one of the tokens.
+func (t *Tunnel) authenticated(conn WriteCloser, req *http.Request) bool {
+	if tlsConn, ok := conn.(*tls.Conn); ok && len(tlsConn.ConnectionState().VerifiedChains) > 0 {
+		return true
+	}
+
+	token := proxyToken(req.Header.Get("Proxy-Authorization"))
+	if token == "" {
+		return false
+	}
+
+	for _, expected := range t.tokens {
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(token)) == 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reject answers the tunnel request with the given status code, and closes the connection.
+func (t *Tunnel) reject(conn WriteCloser, statusCode int) {
+	_, _ = fmt.Fprintf(conn, "HTTP/1.1 %d %s\r\n", statusCode, http.StatusText(statusCode))
+	if statusCode == http.StatusProxyAuthRequired {
+		_, _ = fmt.Fprint(conn, "Proxy-Authenticate: Bearer\r\n")
+	}
+	_, _ = fmt.Fprint(conn, "Content-Length: 0\r\nConnection: close\r\n\r\n")
+
+	_ = conn.Close()
+}
+
+// proxyToken returns the token of the given Proxy-Authorization header value,
+// either a bearer token or the password of the basic scheme.
+func proxyToken(authorization string) string {
+	scheme, credentials := authorization, ""
+	if i := strings.IndexByte(authorization, ' '); i >= 0 {
+		scheme, credentials = authorization[:i], strings.TrimSpace(authorization[i+1:])
+	}
+
+	switch {
+	case strings.EqualFold(scheme, "Bearer"):
+		return credentials
+
+	case strings.EqualFold(scheme, "Basic"):
+		decoded, err := base64.StdEncoding.DecodeString(credentials)
+		if err != nil {
+			return ""
+		}
+
+		if i := strings.IndexByte(string(decoded), ':'); i >= 0 {
+			return string(decoded[i+1:])
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,108 @@
+package tcp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTunnel(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		request        string
+		expectedStatus int
+	}{
+		{
+			desc:           "bearer token",
+			request:        "CONNECT db HTTP/1.1\r\nHost: db\r\nProxy-Authorization: Bearer secret\r\n\r\n",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "basic password and port",
+			request:        "CONNECT db:5432 HTTP/1.1\r\nHost: db:5432\r\nProxy-Authorization: Basic dXNlcjpzZWNyZXQ=\r\n\r\n",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "missing token",
+			request:        "CONNECT db HTTP/1.1\r\nHost: db\r\n\r\n",
+			expectedStatus: http.StatusProxyAuthRequired,
+		},
+		{
+			desc:           "wrong token",
+			request:        "CONNECT db HTTP/1.1\r\nHost: db\r\nProxy-Authorization: Bearer foo\r\n\r\n",
+			expectedStatus: http.StatusProxyAuthRequired,
+		},
+		{
+			desc:           "unknown service",
+			request:        "CONNECT cache HTTP/1.1\r\nHost: cache\r\nProxy-Authorization: Bearer secret\r\n\r\n",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			desc:           "not a CONNECT request",
+			request:        "GET / HTTP/1.1\r\nHost: db\r\nProxy-Authorization: Bearer secret\r\n\r\n",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			desc:           "invalid request",
+			request:        "foo\r\n\r\n",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			tunnel := NewTunnel([]string{"secret"})
+			tunnel.AddHandler("db", HandlerFunc(func(conn WriteCloser) {
+				defer conn.Close()
+
+				buf := make([]byte, 4)
+				if _, err := io.ReadFull(conn, buf); err != nil {
+					return
+				}
+				_, _ = fmt.Fprintf(conn, "%s-pong", buf)
+			}))
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			defer listener.Close()
+
+			go func() {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				tunnel.ServeTCP(conn.(WriteCloser))
+			}()
+
+			conn, err := net.Dial("tcp", listener.Addr().String())
+			require.NoError(t, err)
+			defer conn.Close()
+
+			// The data sent along with the request are forwarded too.
+			_, err = fmt.Fprint(conn, test.request+"ping")
+			require.NoError(t, err)
+
+			br := bufio.NewReader(conn)
+			resp, err := http.ReadResponse(br, &http.Request{Method: http.MethodConnect})
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+
+			if test.expectedStatus != http.StatusOK {
+				return
+			}
+
+			data, err := io.ReadAll(br)
+			require.NoError(t, err)
+			assert.Equal(t, "ping-pong", string(data))
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"sort"
+	"strings"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// CertificateMetrics reports the expiration date of the served certificates,
+// and counts the TLS handshakes served with each of them.
+type CertificateMetrics struct {
+	notAfterGauge     gokitmetrics.Gauge
+	handshakesCounter gokitmetrics.Counter
+}
+
+// NewCertificateMetrics creates a new CertificateMetrics, reporting to the given metrics.
+func NewCertificateMetrics(notAfterGauge gokitmetrics.Gauge, handshakesCounter gokitmetrics.Counter) *CertificateMetrics {
+	return &CertificateMetrics{
+		notAfterGauge:     notAfterGauge,
+		handshakesCounter: handshakesCounter,
+	}
+}
+
+// update reports the expiration date of the certificates,
+// and returns the counters of the handshakes served with the certificates of each store.
+// The handshake counters are initialized, so that the unused certificates are reported too.
+func (c *CertificateMetrics) update(storesCertificates map[string][]*tls.Certificate) map[string]*storeHandshakes {
+	handshakes := make(map[string]*storeHandshakes)
+	for storeName, certificates := range storesCertificates {
+		store := &storeHandshakes{
+			counter: c.handshakesCounter,
+			labels:  make(map[*tls.Certificate][]string),
+		}
+
+		for _, certificate := range certificates {
+			if certificate == nil || len(certificate.Certificate) == 0 {
+				continue
+			}
+
+			leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+			if err != nil {
+				log.WithoutContext().Debugf("Unable to parse the certificate for the metrics: %v", err)
+				continue
+			}
+
+			c.notAfterGauge.With(certificateMetricLabels(leaf)...).Set(float64(leaf.NotAfter.Unix()))
+
+			// The handshakes are counted by store, as the same certificate can be served by several stores.
+			labels := append(certificateMetricLabels(leaf), "store", storeName)
+			store.labels[certificate] = labels
+			c.handshakesCounter.With(labels...).Add(0)
+		}
+
+		handshakes[storeName] = store
+	}
+
+	return handshakes
+}
+
+// storeHandshakes counts the TLS handshakes served with the certificates of a store.
+type storeHandshakes struct {
+	counter gokitmetrics.Counter
+	labels  map[*tls.Certificate][]string
+}
+
+// count counts a handshake served with the certificate.
+func (h *storeHandshakes) count(certificate *tls.Certificate) {
+	if h == nil {
+		return
+	}
+
+	if labels, ok := h.labels[certificate]; ok {
+		h.counter.With(labels...).Add(1)
+	}
+}
+
+func certificateMetricLabels(certificate *x509.Certificate) []string {
+	sans := make([]string, len(certificate.DNSNames))
+	copy(sans, certificate.DNSNames)
+	sort.Strings(sans)
+
+	return []string{
+		"cn", certificate.Subject.CommonName,
+		"serial", certificate.SerialNumber.String(),
+		"sans", strings.Join(sans, ","),
+	}
+}
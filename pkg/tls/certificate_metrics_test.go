@@ -0,0 +1,162 @@
+package tls
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// FooCert is a PEM-encoded TLS cert.
+// generated from src/crypto/tls:
+// go run generate_cert.go  --rsa-bits 1024 --host foo.org,foo.com  --ca --start-date "Jan 1 00:00:00 1970" --duration=1000000h
+const fooCert = `-----BEGIN CERTIFICATE-----
+MIICHzCCAYigAwIBAgIQXQFLeYRwc5X21t457t2xADANBgkqhkiG9w0BAQsFADAS
+MRAwDgYDVQQKEwdBY21lIENvMCAXDTcwMDEwMTAwMDAwMFoYDzIwODQwMTI5MTYw
+MDAwWjASMRAwDgYDVQQKEwdBY21lIENvMIGfMA0GCSqGSIb3DQEBAQUAA4GNADCB
+iQKBgQDCjn67GSs/khuGC4GNN+tVo1S+/eSHwr/hWzhfMqO7nYiXkFzmxi+u14CU
+Pda6WOeps7T2/oQEFMxKKg7zYOqkLSbjbE0ZfosopaTvEsZm/AZHAAvoOrAsIJOn
+SEiwy8h0tLA4z1SNR6rmIVQWyqBZEPAhBTQM1z7tFp48FakCFwIDAQABo3QwcjAO
+BgNVHQ8BAf8EBAMCAqQwEwYDVR0lBAwwCgYIKwYBBQUHAwEwDwYDVR0TAQH/BAUw
+AwEB/zAdBgNVHQ4EFgQUDHG3ASzeUezElup9zbPpBn/vjogwGwYDVR0RBBQwEoIH
+Zm9vLm9yZ4IHZm9vLmNvbTANBgkqhkiG9w0BAQsFAAOBgQBT+VLMbB9u27tBX8Aw
+ZrGY3rbNdBGhXVTksrjiF+6ZtDpD3iI56GH9zLxnqvXkgn3u0+Ard5TqF/xmdwVw
+NY0V/aWYfcL2G2auBCQrPvM03ozRnVUwVfP23eUzX2ORNHCYhd2ObQx4krrhs7cJ
+SWxtKwFlstoXY3K2g9oRD9UxdQ==
+-----END CERTIFICATE-----`
+
+// BarCert is a PEM-encoded TLS cert.
+// generated from src/crypto/tls:
+// go run generate_cert.go  --rsa-bits 1024 --host bar.org,bar.com  --ca --start-date "Jan 1 00:00:00 1970" --duration=10000h
+const barCert = `-----BEGIN CERTIFICATE-----
+MIICHTCCAYagAwIBAgIQcuIcNEXzBHPoxna5S6wG4jANBgkqhkiG9w0BAQsFADAS
+MRAwDgYDVQQKEwdBY21lIENvMB4XDTcwMDEwMTAwMDAwMFoXDTcxMDIyMTE2MDAw
+MFowEjEQMA4GA1UEChMHQWNtZSBDbzCBnzANBgkqhkiG9w0BAQEFAAOBjQAwgYkC
+gYEAqtcrP+KA7D6NjyztGNIPMup9KiBMJ8QL+preog/YHR7SQLO3kGFhpS3WKMab
+SzMypC3ZX1PZjBP5ZzwaV3PFbuwlCkPlyxR2lOWmullgI7mjY0TBeYLDIclIzGRp
+mpSDDSpkW1ay2iJDSpXjlhmwZr84hrCU7BRTQJo91fdsRTsCAwEAAaN0MHIwDgYD
+VR0PAQH/BAQDAgKkMBMGA1UdJQQMMAoGCCsGAQUFBwMBMA8GA1UdEwEB/wQFMAMB
+Af8wHQYDVR0OBBYEFK8jnzFQvBAgWtfzOyXY4VSkwrTXMBsGA1UdEQQUMBKCB2Jh
+ci5vcmeCB2Jhci5jb20wDQYJKoZIhvcNAQELBQADgYEAJz0ifAExisC/ZSRhWuHz
+7qs1i6Nd4+YgEVR8dR71MChP+AMxucY1/ajVjb9xlLys3GPE90TWSdVppabEVjZY
+Oq11nPKc50ItTt8dMku6t0JHBmzoGdkN0V4zJCBqdQJxhop8JpYJ0S9CW0eT93h3
+ipYQSsmIINGtMXJ8VkP/MlM=
+-----END CERTIFICATE-----`
+
+type labeledCounter struct {
+	values map[string]float64
+	labels []string
+}
+
+func (c *labeledCounter) With(labels ...string) gokitmetrics.Counter {
+	return &labeledCounter{values: c.values, labels: labels}
+}
+
+func (c *labeledCounter) Add(delta float64) { c.values[strings.Join(c.labels, ",")] += delta }
+
+type labeledGauge struct {
+	values map[string]float64
+	labels []string
+}
+
+func (g *labeledGauge) With(labels ...string) gokitmetrics.Gauge {
+	return &labeledGauge{values: g.values, labels: labels}
+}
+
+func (g *labeledGauge) Set(value float64) { g.values[strings.Join(g.labels, ",")] = value }
+
+func (g *labeledGauge) Add(delta float64) { g.values[strings.Join(g.labels, ",")] += delta }
+
+func TestCertificateMetrics_update(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		certs    []string
+		expected map[string]float64
+	}{
+		{
+			desc:     "No certs",
+			certs:    []string{},
+			expected: map[string]float64{},
+		},
+		{
+			desc:  "One cert",
+			certs: []string{fooCert},
+			expected: map[string]float64{
+				"cn,,serial,123624926713171615935660664614975025408,sans,foo.com,foo.org": 3.6e+09,
+			},
+		},
+		{
+			desc:  "Two certs",
+			certs: []string{fooCert, barCert},
+			expected: map[string]float64{
+				"cn,,serial,123624926713171615935660664614975025408,sans,foo.com,foo.org": 3.6e+09,
+				"cn,,serial,152706022658490889223053211416725817058,sans,bar.com,bar.org": 3.6e+07,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var certificates []*tls.Certificate
+			for _, cert := range test.certs {
+				block, _ := pem.Decode([]byte(cert))
+				certificates = append(certificates, &tls.Certificate{Certificate: [][]byte{block.Bytes}})
+			}
+
+			gauge := &labeledGauge{values: make(map[string]float64)}
+
+			certificateMetrics := NewCertificateMetrics(gauge, &labeledCounter{values: make(map[string]float64)})
+			certificateMetrics.update(map[string][]*tls.Certificate{DefaultTLSStoreName: certificates})
+
+			assert.Equal(t, test.expected, gauge.values)
+		})
+	}
+}
+
+func TestManager_certificateMetrics(t *testing.T) {
+	ca := newTestCA(t, "ca")
+
+	notAfter := &labeledGauge{values: make(map[string]float64)}
+	handshakes := &labeledCounter{values: make(map[string]float64)}
+
+	tlsManager := NewManager()
+	tlsManager.SetCertificateMetrics(NewCertificateMetrics(notAfter, handshakes))
+	tlsManager.UpdateConfigs(context.Background(), nil, map[string]Options{DefaultTLSConfigName: {}}, []*CertAndStores{
+		{Certificate: ca.issueServer(t, "foo.example.com", "")},
+		{Certificate: ca.issueServer(t, "bar.example.com", ""), Stores: []string{"bar"}},
+	})
+
+	fooLabels := "cn,foo.example.com,serial,42,sans,foo.example.com,store,default"
+	barLabels := "cn,bar.example.com,serial,42,sans,bar.example.com,store,bar"
+
+	// The generated default certificates are not reported.
+	require.Len(t, notAfter.values, 2)
+	assert.Greater(t, notAfter.values["cn,foo.example.com,serial,42,sans,foo.example.com"], float64(time.Now().Unix()))
+	assert.Greater(t, notAfter.values["cn,bar.example.com,serial,42,sans,bar.example.com"], float64(time.Now().Unix()))
+
+	// The unused certificates are reported too.
+	assert.Equal(t, map[string]float64{fooLabels: 0, barLabels: 0}, handshakes.values)
+
+	config, err := tlsManager.Get(DefaultTLSStoreName, DefaultTLSConfigName)
+	require.NoError(t, err)
+
+	_, err = config.GetCertificate(&tls.ClientHelloInfo{ServerName: "foo.example.com"})
+	require.NoError(t, err)
+	_, err = config.GetCertificate(&tls.ClientHelloInfo{ServerName: "foo.example.com"})
+	require.NoError(t, err)
+
+	// The handshakes served with the generated default certificate are not counted.
+	_, err = config.GetCertificate(&tls.ClientHelloInfo{ServerName: "baz.example.com"})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]float64{fooLabels: 2, barLabels: 0}, handshakes.values)
+}
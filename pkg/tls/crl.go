@@ -0,0 +1,327 @@
+package tls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+const (
+	// DefaultCRLRefreshInterval is the default interval between two reloads of the certificate revocation lists.
+	DefaultCRLRefreshInterval = time.Hour
+
+	crlRetryInterval  = time.Minute
+	crlFetchTimeout   = 10 * time.Second
+	crlMaxContentSize = 10 << 20
+)
+
+// revocationList holds the serial numbers of the revoked certificates, by issuer,
+// read from the certificate revocation lists (CRLs) configured for the client authentication.
+// The CRLs are reloaded asynchronously once the refresh interval is elapsed,
+// and the previous serial numbers are kept if the CRLs cannot be reloaded.
+type revocationList struct {
+	name            string
+	caFiles         []FileOrContent
+	crlFiles        []FileOrContent
+	crlURLs         []string
+	refreshInterval time.Duration
+	client          *http.Client
+
+	refreshMu  sync.Mutex
+	refreshing int32
+
+	mu          sync.RWMutex
+	revoked     map[string]map[string]struct{}
+	nextRefresh time.Time
+}
+
+func newRevocationList(name string, clientAuth ClientAuth) *revocationList {
+	refreshInterval := time.Duration(clientAuth.CRLRefreshInterval)
+	if refreshInterval <= 0 {
+		refreshInterval = DefaultCRLRefreshInterval
+	}
+
+	return &revocationList{
+		name:            name,
+		caFiles:         clientAuth.CAFiles,
+		crlFiles:        clientAuth.CRLFiles,
+		crlURLs:         clientAuth.CRLURLs,
+		refreshInterval: refreshInterval,
+		client:          &http.Client{Timeout: crlFetchTimeout},
+	}
+}
+
+// verifyConnection rejects the client certificates if all the verified chains contain a revoked certificate.
+// Unlike VerifyPeerCertificate, it is also called for the resumed sessions.
+// It fails closed: the certificates are rejected as long as the CRLs have never been loaded.
+func (r *revocationList) verifyConnection(cs tls.ConnectionState) error {
+	chains := cs.VerifiedChains
+	if len(chains) == 0 {
+		if len(cs.PeerCertificates) == 0 {
+			return nil
+		}
+
+		// The verified chains are not kept in the resumed sessions.
+		chains = [][]*x509.Certificate{cs.PeerCertificates}
+	}
+
+	revoked, err := r.getRevoked()
+	if err != nil {
+		return err
+	}
+
+	for _, chain := range chains {
+		if !isChainRevoked(chain, revoked) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("client certificate %s has been revoked", chains[0][0].Subject)
+}
+
+func isChainRevoked(chain []*x509.Certificate, revoked map[string]map[string]struct{}) bool {
+	for _, cert := range chain {
+		if _, ok := revoked[string(cert.RawIssuer)][cert.SerialNumber.String()]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *revocationList) getRevoked() (map[string]map[string]struct{}, error) {
+	r.mu.RLock()
+	revoked, nextRefresh := r.revoked, r.nextRefresh
+	r.mu.RUnlock()
+
+	if revoked == nil {
+		r.refresh()
+
+		r.mu.RLock()
+		revoked = r.revoked
+		r.mu.RUnlock()
+
+		if revoked == nil {
+			return nil, errors.New("certificate revocation lists unavailable")
+		}
+
+		return revoked, nil
+	}
+
+	if time.Now().After(nextRefresh) && atomic.CompareAndSwapInt32(&r.refreshing, 0, 1) {
+		go func() {
+			defer atomic.StoreInt32(&r.refreshing, 0)
+			r.refresh()
+		}()
+	}
+
+	return revoked, nil
+}
+
+// refresh loads the CRLs, unless they have already been loaded, or have failed to load, recently.
+func (r *revocationList) refresh() {
+	r.refreshMu.Lock()
+	defer r.refreshMu.Unlock()
+
+	r.mu.RLock()
+	nextRefresh := r.nextRefresh
+	r.mu.RUnlock()
+
+	now := time.Now()
+	if now.Before(nextRefresh) {
+		return
+	}
+
+	logger := log.WithoutContext()
+
+	revoked, err := r.load()
+	if err != nil {
+		logger.Errorf("Unable to load the certificate revocation lists of the TLS options %s: %v", r.name, err)
+
+		retryInterval := crlRetryInterval
+		if r.refreshInterval < retryInterval {
+			retryInterval = r.refreshInterval
+		}
+
+		r.mu.Lock()
+		r.nextRefresh = now.Add(retryInterval)
+		r.mu.Unlock()
+		return
+	}
+
+	logger.Debugf("Certificate revocation lists of the TLS options %s loaded", r.name)
+
+	r.mu.Lock()
+	r.revoked = revoked
+	r.nextRefresh = now.Add(r.refreshInterval)
+	r.mu.Unlock()
+}
+
+func (r *revocationList) load() (map[string]map[string]struct{}, error) {
+	var cas []*x509.Certificate
+	for _, caFile := range r.caFiles {
+		data, err := caFile.Read()
+		if err != nil {
+			return nil, err
+		}
+
+		certs, err := parseCertificates(data)
+		if err != nil {
+			return nil, err
+		}
+		cas = append(cas, certs...)
+	}
+
+	var crls []*pkix.CertificateList
+	for _, crlFile := range r.crlFiles {
+		data, err := crlFile.Read()
+		if err != nil {
+			return nil, err
+		}
+
+		list, err := parseCRLs(data)
+		if err != nil {
+			if crlFile.IsPath() {
+				return nil, fmt.Errorf("invalid CRL(s) in %s: %w", crlFile, err)
+			}
+			return nil, fmt.Errorf("invalid CRL(s) content: %w", err)
+		}
+		crls = append(crls, list...)
+	}
+
+	for _, crlURL := range r.crlURLs {
+		data, err := r.fetch(crlURL)
+		if err != nil {
+			return nil, err
+		}
+
+		list, err := parseCRLs(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CRL(s) from %s: %w", crlURL, err)
+		}
+		crls = append(crls, list...)
+	}
+
+	revoked := make(map[string]map[string]struct{})
+	for _, crl := range crls {
+		issuer := findCRLIssuer(crl, cas)
+		if issuer == nil {
+			return nil, fmt.Errorf("CRL of %s is not signed by any of the client CAs", crl.TBSCertList.Issuer)
+		}
+
+		serials, ok := revoked[string(issuer.RawSubject)]
+		if !ok {
+			serials = make(map[string]struct{})
+			revoked[string(issuer.RawSubject)] = serials
+		}
+
+		for _, cert := range crl.TBSCertList.RevokedCertificates {
+			serials[cert.SerialNumber.String()] = struct{}{}
+		}
+	}
+
+	return revoked, nil
+}
+
+func (r *revocationList) fetch(crlURL string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), crlFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, crlURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d while fetching %s", resp.StatusCode, crlURL)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, crlMaxContentSize))
+}
+
+func findCRLIssuer(crl *pkix.CertificateList, cas []*x509.Certificate) *x509.Certificate {
+	for _, ca := range cas {
+		if ca.CheckCRLSignature(crl) == nil {
+			return ca
+		}
+	}
+
+	return nil
+}
+
+// parseCRLs parses the PEM encoded CRLs, or a single DER encoded CRL.
+func parseCRLs(data []byte) ([]*pkix.CertificateList, error) {
+	var crls []*pkix.CertificateList
+
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "X509 CRL" {
+			continue
+		}
+
+		crl, err := x509.ParseDERCRL(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		crls = append(crls, crl)
+	}
+
+	if len(crls) > 0 {
+		return crls, nil
+	}
+
+	crl, err := x509.ParseDERCRL(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*pkix.CertificateList{crl}, nil
+}
+
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+
+	return certs, nil
+}
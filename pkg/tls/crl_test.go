@@ -0,0 +1,216 @@
+package tls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  string
+}
+
+func newTestCA(t *testing.T, name string) testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return testCA{
+		cert: cert,
+		key:  key,
+		pem:  string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+	}
+}
+
+func (ca testCA) issue(t *testing.T, serial int64) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert
+}
+
+func (ca testCA) crl(t *testing.T, revokedSerials ...int64) string {
+	t.Helper()
+
+	var revoked []pkix.RevokedCertificate
+	for _, serial := range revokedSerials {
+		revoked = append(revoked, pkix.RevokedCertificate{SerialNumber: big.NewInt(serial), RevocationTime: time.Now()})
+	}
+
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:              big.NewInt(time.Now().UnixNano()),
+		ThisUpdate:          time.Now().Add(-time.Hour),
+		NextUpdate:          time.Now().Add(time.Hour),
+		RevokedCertificates: revoked,
+	}, ca.cert, ca.key)
+	require.NoError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}))
+}
+
+func connectionState(ca testCA, cert *x509.Certificate) tls.ConnectionState {
+	return tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{cert},
+		VerifiedChains:   [][]*x509.Certificate{{cert, ca.cert}},
+	}
+}
+
+func TestRevocationList_verifyConnection(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	otherCA := newTestCA(t, "other")
+
+	testCases := []struct {
+		desc        string
+		crlFiles    []FileOrContent
+		serial      int64
+		resumed     bool
+		expectedErr bool
+	}{
+		{
+			desc:     "certificate not revoked",
+			crlFiles: []FileOrContent{FileOrContent(ca.crl(t, 2))},
+			serial:   1,
+		},
+		{
+			desc:        "certificate revoked",
+			crlFiles:    []FileOrContent{FileOrContent(ca.crl(t, 2))},
+			serial:      2,
+			expectedErr: true,
+		},
+		{
+			desc:        "certificate revoked in a resumed session",
+			crlFiles:    []FileOrContent{FileOrContent(ca.crl(t, 2))},
+			serial:      2,
+			resumed:     true,
+			expectedErr: true,
+		},
+		{
+			desc:     "certificate revoked by another issuer",
+			crlFiles: []FileOrContent{FileOrContent(ca.crl(t)), FileOrContent(otherCA.crl(t, 2))},
+			serial:   2,
+		},
+		{
+			desc:        "CRL not signed by a client CA",
+			crlFiles:    []FileOrContent{FileOrContent(newTestCA(t, "ca").crl(t))},
+			serial:      1,
+			expectedErr: true,
+		},
+		{
+			desc:        "invalid CRL",
+			crlFiles:    []FileOrContent{"foobar"},
+			serial:      1,
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			revocations := newRevocationList("foo", ClientAuth{
+				CAFiles:  []FileOrContent{FileOrContent(ca.pem), FileOrContent(otherCA.pem)},
+				CRLFiles: test.crlFiles,
+			})
+
+			cs := connectionState(ca, ca.issue(t, test.serial))
+			if test.resumed {
+				cs.VerifiedChains = nil
+			}
+
+			err := revocations.verifyConnection(cs)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRevocationList_refresh(t *testing.T) {
+	ca := newTestCA(t, "ca")
+
+	var mu sync.Mutex
+	crl := ca.crl(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		_, _ = rw.Write([]byte(crl))
+	}))
+	t.Cleanup(server.Close)
+
+	revocations := newRevocationList("foo", ClientAuth{
+		CAFiles: []FileOrContent{FileOrContent(ca.pem)},
+		CRLURLs: []string{server.URL},
+	})
+
+	cs := connectionState(ca, ca.issue(t, 2))
+	require.NoError(t, revocations.verifyConnection(cs))
+
+	mu.Lock()
+	crl = ca.crl(t, 2)
+	mu.Unlock()
+
+	// The CRLs are not reloaded before the end of the refresh interval.
+	require.NoError(t, revocations.verifyConnection(cs))
+
+	revocations.mu.Lock()
+	revocations.nextRefresh = time.Now().Add(-time.Second)
+	revocations.mu.Unlock()
+
+	assert.Eventually(t, func() bool {
+		return revocations.verifyConnection(cs) != nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
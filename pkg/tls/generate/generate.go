@@ -1,6 +1,9 @@
 package generate
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
@@ -17,10 +20,31 @@ import (
 // DefaultDomain Traefik domain for the default certificate.
 const DefaultDomain = "TRAEFIK DEFAULT CERT"
 
+// Options configures a generated self-signed certificate.
+// The zero values are replaced by the ones of the default certificate.
+type Options struct {
+	// CommonName is the common name of the certificate subject.
+	CommonName string
+	// Validity is the duration during which the certificate is valid.
+	Validity time.Duration
+	// KeyType is the type of the private key: RSA2048, RSA4096, EC256 or EC384.
+	KeyType string
+}
+
 // DefaultCertificate generates random TLS certificates.
 func DefaultCertificate() (*tls.Certificate, error) {
+	return Certificate(Options{})
+}
+
+// Certificate generates a random self-signed TLS certificate.
+func Certificate(opts Options) (*tls.Certificate, error) {
+	privKey, err := generatePrivateKey(opts.KeyType)
+	if err != nil {
+		return nil, err
+	}
+
 	randomBytes := make([]byte, 100)
-	_, err := rand.Read(randomBytes)
+	_, err = rand.Read(randomBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -28,17 +52,41 @@ func DefaultCertificate() (*tls.Certificate, error) {
 	z := hex.EncodeToString(zBytes[:sha256.Size])
 	domain := fmt.Sprintf("%s.%s.traefik.default", z[:32], z[32:])
 
-	certPEM, keyPEM, err := KeyPair(domain, time.Time{})
-	if err != nil {
-		return nil, err
+	commonName := opts.CommonName
+	if commonName == "" {
+		commonName = DefaultDomain
+	}
+
+	var expiration time.Time
+	if opts.Validity > 0 {
+		expiration = time.Now().Add(opts.Validity)
 	}
 
-	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	derBytes, err := createCertificate(privKey, expiration, commonName, domain)
 	if err != nil {
 		return nil, err
 	}
 
-	return &certificate, nil
+	return &tls.Certificate{
+		Certificate: [][]byte{derBytes},
+		PrivateKey:  privKey,
+	}, nil
+}
+
+// generatePrivateKey generates a private key of the given type, RSA2048 by default.
+func generatePrivateKey(keyType string) (crypto.Signer, error) {
+	switch keyType {
+	case "", "RSA2048":
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case "RSA4096":
+		return rsa.GenerateKey(rand.Reader, 4096)
+	case "EC256":
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "EC384":
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported key type: %q", keyType)
+	}
 }
 
 // KeyPair generates cert and key files.
@@ -67,6 +115,10 @@ func PemCert(privKey *rsa.PrivateKey, domain string, expiration time.Time) ([]by
 }
 
 func derCert(privKey *rsa.PrivateKey, expiration time.Time, domain string) ([]byte, error) {
+	return createCertificate(privKey, expiration, DefaultDomain, domain)
+}
+
+func createCertificate(privKey crypto.Signer, expiration time.Time, commonName, domain string) ([]byte, error) {
 	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
 	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
 	if err != nil {
@@ -80,7 +132,7 @@ func derCert(privKey *rsa.PrivateKey, expiration time.Time, domain string) ([]by
 	template := x509.Certificate{
 		SerialNumber: serialNumber,
 		Subject: pkix.Name{
-			CommonName: DefaultDomain,
+			CommonName: commonName,
 		},
 		NotBefore: time.Now(),
 		NotAfter:  expiration,
@@ -91,5 +143,5 @@ func derCert(privKey *rsa.PrivateKey, expiration time.Time, domain string) ([]by
 		DNSNames:              []string{domain},
 	}
 
-	return x509.CreateCertificate(rand.Reader, &template, &template, &privKey.PublicKey, privKey)
+	return x509.CreateCertificate(rand.Reader, &template, &template, privKey.Public(), privKey)
 }
@@ -0,0 +1,317 @@
+package tls
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"golang.org/x/crypto/ocsp"
+)
+
+const (
+	ocspCheckInterval          = time.Minute
+	ocspRetryInterval          = time.Minute
+	ocspDefaultRefreshInterval = time.Hour
+	ocspFetchTimeout           = 10 * time.Second
+	ocspMaxResponseContentSize = 1 << 20
+	ocspRequestContentType     = "application/ocsp-request"
+	ocspResponseContentType    = "application/ocsp-response"
+)
+
+// OCSPConfig configures the stapling of the OCSP responses to the served certificates.
+type OCSPConfig struct {
+	ResponderOverrides map[string]string `description:"Defines the OCSP responders to query instead of the ones of the certificates, by responder URL." json:"responderOverrides,omitempty" toml:"responderOverrides,omitempty" yaml:"responderOverrides,omitempty" export:"true"`
+}
+
+// OCSPStapler fetches the OCSP responses of the served certificates, and staples them to the certificates.
+// The responses are refreshed in the background at the half of their validity period,
+// and the previous response is kept, as long as it is valid, if the responder cannot be reached.
+type OCSPStapler struct {
+	responderOverrides map[string]string
+	client             *http.Client
+	stapleAgeGauge     gokitmetrics.Gauge
+	failuresCounter    gokitmetrics.Counter
+	updated            chan struct{}
+
+	mu      sync.RWMutex
+	staples map[string]*ocspStaple
+}
+
+// ocspStaple holds the OCSP response of a certificate.
+type ocspStaple struct {
+	leaf         *x509.Certificate
+	issuer       *x509.Certificate
+	responderURL string
+
+	raw         []byte
+	response    *ocsp.Response
+	nextRefresh time.Time
+}
+
+// NewOCSPStapler creates a new OCSPStapler, reporting the age of the stapled responses,
+// and the failures to fetch them, to the given metrics.
+func NewOCSPStapler(config *OCSPConfig, stapleAgeGauge gokitmetrics.Gauge, failuresCounter gokitmetrics.Counter) *OCSPStapler {
+	var responderOverrides map[string]string
+	if config != nil {
+		responderOverrides = config.ResponderOverrides
+	}
+
+	return &OCSPStapler{
+		responderOverrides: responderOverrides,
+		client:             &http.Client{Timeout: ocspFetchTimeout},
+		stapleAgeGauge:     stapleAgeGauge,
+		failuresCounter:    failuresCounter,
+		updated:            make(chan struct{}, 1),
+		staples:            make(map[string]*ocspStaple),
+	}
+}
+
+// Run refreshes the OCSP responses until the context is canceled.
+func (s *OCSPStapler) Run(ctx context.Context) {
+	ticker := time.NewTicker(ocspCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		s.refresh(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.updated:
+		}
+	}
+}
+
+// update sets the served certificates.
+// The responses of the certificates already served are kept, and the new certificates are fetched by the next refresh.
+func (s *OCSPStapler) update(certificates []*tls.Certificate) {
+	logger := log.WithoutContext()
+
+	s.mu.Lock()
+
+	staples := make(map[string]*ocspStaple)
+	for _, certificate := range certificates {
+		if certificate == nil || len(certificate.Certificate) == 0 {
+			continue
+		}
+
+		key := string(certificate.Certificate[0])
+		if _, ok := staples[key]; ok {
+			continue
+		}
+
+		if staple, ok := s.staples[key]; ok {
+			staples[key] = staple
+			continue
+		}
+
+		staple, err := s.newStaple(certificate)
+		if err != nil {
+			logger.Debugf("Unable to staple the OCSP response of a certificate: %v", err)
+			continue
+		}
+
+		if staple != nil {
+			staples[key] = staple
+		}
+	}
+
+	s.staples = staples
+
+	s.mu.Unlock()
+
+	select {
+	case s.updated <- struct{}{}:
+	default:
+	}
+}
+
+// newStaple returns the staple of the certificate, or nil if the certificate has no OCSP responder.
+func (s *OCSPStapler) newStaple(certificate *tls.Certificate) (*ocspStaple, error) {
+	leaf := certificate.Leaf
+	if leaf == nil {
+		var err error
+		leaf, err = x509.ParseCertificate(certificate.Certificate[0])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(leaf.OCSPServer) == 0 {
+		return nil, nil
+	}
+
+	if len(certificate.Certificate) < 2 {
+		return nil, fmt.Errorf("no issuer certificate in the chain of the certificate %s", leaf.Subject)
+	}
+
+	issuer, err := x509.ParseCertificate(certificate.Certificate[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid issuer certificate in the chain of the certificate %s: %w", leaf.Subject, err)
+	}
+
+	responderURL := leaf.OCSPServer[0]
+	if override, ok := s.responderOverrides[responderURL]; ok {
+		responderURL = override
+	}
+
+	return &ocspStaple{
+		leaf:         leaf,
+		issuer:       issuer,
+		responderURL: responderURL,
+	}, nil
+}
+
+// staple returns a copy of the certificate with its OCSP response, if there is a valid one.
+func (s *OCSPStapler) staple(certificate *tls.Certificate) *tls.Certificate {
+	if s == nil || certificate == nil || len(certificate.Certificate) == 0 {
+		return certificate
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	staple, ok := s.staples[string(certificate.Certificate[0])]
+	if !ok || staple.response == nil || isOCSPResponseExpired(staple.response, time.Now()) {
+		return certificate
+	}
+
+	stapled := *certificate
+	stapled.OCSPStaple = staple.raw
+
+	return &stapled
+}
+
+// refresh fetches the OCSP responses which have never been fetched, or which have to be refreshed,
+// and reports the age of the stapled responses.
+func (s *OCSPStapler) refresh(ctx context.Context) {
+	now := time.Now()
+
+	s.mu.RLock()
+	var staples []*ocspStaple
+	for _, staple := range s.staples {
+		if !now.Before(staple.nextRefresh) {
+			staples = append(staples, staple)
+		}
+	}
+	s.mu.RUnlock()
+
+	logger := log.FromContext(ctx)
+
+	for _, staple := range staples {
+		raw, response, err := s.fetch(ctx, staple)
+
+		s.mu.Lock()
+		if err != nil {
+			staple.nextRefresh = time.Now().Add(ocspRetryInterval)
+			s.mu.Unlock()
+
+			logger.Errorf("Unable to fetch the OCSP response of the certificate %s from %s: %v", staple.leaf.Subject, staple.responderURL, err)
+			s.failuresCounter.With(ocspMetricLabels(staple.leaf)...).Add(1)
+			continue
+		}
+
+		staple.raw = raw
+		staple.response = response
+		staple.nextRefresh = nextOCSPRefresh(response, time.Now())
+		s.mu.Unlock()
+
+		if response.Status == ocsp.Revoked {
+			logger.Warnf("The certificate %s has been revoked at %s", staple.leaf.Subject, response.RevokedAt)
+		} else {
+			logger.Debugf("OCSP response of the certificate %s refreshed", staple.leaf.Subject)
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, staple := range s.staples {
+		if staple.response != nil {
+			s.stapleAgeGauge.With(ocspMetricLabels(staple.leaf)...).Set(time.Since(staple.response.ThisUpdate).Seconds())
+		}
+	}
+}
+
+func (s *OCSPStapler) fetch(ctx context.Context, staple *ocspStaple) ([]byte, *ocsp.Response, error) {
+	request, err := ocsp.CreateRequest(staple.leaf, staple.issuer, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ocspFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, staple.responderURL, bytes.NewReader(request))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", ocspRequestContentType)
+	req.Header.Set("Accept", ocspResponseContentType)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, ocspMaxResponseContentSize))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response, err := ocsp.ParseResponseForCert(raw, staple.leaf, staple.issuer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if response.Status == ocsp.Unknown {
+		return nil, nil, errors.New("unknown certificate status")
+	}
+
+	if isOCSPResponseExpired(response, time.Now()) {
+		return nil, nil, fmt.Errorf("expired OCSP response, next update was at %s", response.NextUpdate)
+	}
+
+	return raw, response, nil
+}
+
+// nextOCSPRefresh returns the time at which the response has to be refreshed,
+// i.e. at the half of its validity period.
+func nextOCSPRefresh(response *ocsp.Response, now time.Time) time.Time {
+	if response.NextUpdate.IsZero() {
+		return now.Add(ocspDefaultRefreshInterval)
+	}
+
+	next := response.ThisUpdate.Add(response.NextUpdate.Sub(response.ThisUpdate) / 2)
+	if next.Before(now.Add(ocspRetryInterval)) {
+		return now.Add(ocspRetryInterval)
+	}
+
+	return next
+}
+
+func isOCSPResponseExpired(response *ocsp.Response, now time.Time) bool {
+	return !response.NextUpdate.IsZero() && now.After(response.NextUpdate)
+}
+
+func ocspMetricLabels(certificate *x509.Certificate) []string {
+	return []string{
+		"cn", certificate.Subject.CommonName,
+		"serial", certificate.SerialNumber.String(),
+	}
+}
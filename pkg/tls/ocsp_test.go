@@ -0,0 +1,276 @@
+package tls
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+type collectingCounter struct {
+	value float64
+}
+
+func (c *collectingCounter) With(...string) gokitmetrics.Counter { return c }
+
+func (c *collectingCounter) Add(delta float64) { c.value += delta }
+
+type collectingGauge struct {
+	value float64
+}
+
+func (g *collectingGauge) With(...string) gokitmetrics.Gauge { return g }
+
+func (g *collectingGauge) Set(value float64) { g.value = value }
+
+func (g *collectingGauge) Add(delta float64) { g.value += delta }
+
+// issueServer issues a server certificate for the domain, with the OCSP responder, and returns it with its chain.
+func (ca testCA) issueServer(t *testing.T, domain, ocspServer string) Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: domain},
+		DNSNames:     []string{domain},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if ocspServer != "" {
+		template.OCSPServer = []string{ocspServer}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})) + ca.pem
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+
+	return Certificate{CertFile: FileOrContent(certPEM), KeyFile: FileOrContent(keyPEM)}
+}
+
+// newOCSPResponder returns an OCSP responder answering with the given status,
+// and a response valid until the given time.
+func newOCSPResponder(t *testing.T, ca testCA, status int, nextUpdate time.Time) *httptest.Server {
+	t.Helper()
+
+	responder := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if status == ocsp.ServerFailed {
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		request, err := ocsp.ParseRequest(body)
+		require.NoError(t, err)
+
+		response, err := ocsp.CreateResponse(ca.cert, ca.cert, ocsp.Response{
+			Status:       status,
+			SerialNumber: request.SerialNumber,
+			ThisUpdate:   time.Now().Add(-time.Minute),
+			NextUpdate:   nextUpdate,
+			RevokedAt:    time.Now().Add(-time.Minute),
+		}, ca.key)
+		require.NoError(t, err)
+
+		rw.Header().Set("Content-Type", "application/ocsp-response")
+		_, _ = rw.Write(response)
+	}))
+	t.Cleanup(responder.Close)
+
+	return responder
+}
+
+func TestManager_ocspStapling(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	responder := newOCSPResponder(t, ca, ocsp.Good, time.Now().Add(time.Hour))
+
+	gauge := &collectingGauge{}
+	stapler := NewOCSPStapler(nil, gauge, &collectingCounter{})
+
+	tlsManager := NewManager()
+	tlsManager.SetOCSPStapler(stapler)
+	tlsManager.UpdateConfigs(context.Background(), nil, map[string]Options{DefaultTLSConfigName: {}}, []*CertAndStores{{
+		Certificate: ca.issueServer(t, "foo.example.com", responder.URL),
+	}})
+
+	stapler.refresh(context.Background())
+
+	config, err := tlsManager.Get(DefaultTLSStoreName, DefaultTLSConfigName)
+	require.NoError(t, err)
+
+	certificate, err := config.GetCertificate(&tls.ClientHelloInfo{ServerName: "foo.example.com"})
+	require.NoError(t, err)
+	require.NotEmpty(t, certificate.OCSPStaple)
+
+	response, err := ocsp.ParseResponse(certificate.OCSPStaple, ca.cert)
+	require.NoError(t, err)
+	assert.Equal(t, ocsp.Good, response.Status)
+	assert.GreaterOrEqual(t, gauge.value, time.Minute.Seconds())
+
+	// The certificate stored in the manager is left untouched.
+	stored := tlsManager.GetStore(DefaultTLSStoreName).GetBestCertificate(&tls.ClientHelloInfo{ServerName: "foo.example.com"})
+	assert.Empty(t, stored.OCSPStaple)
+}
+
+func TestOCSPStapler_refresh(t *testing.T) {
+	ca := newTestCA(t, "ca")
+
+	testCases := []struct {
+		desc             string
+		status           int
+		nextUpdate       time.Time
+		noOCSPServer     bool
+		override         bool
+		expectedStatus   int
+		expectedStapled  bool
+		expectedFailures float64
+	}{
+		{
+			desc:            "good",
+			status:          ocsp.Good,
+			nextUpdate:      time.Now().Add(time.Hour),
+			expectedStatus:  ocsp.Good,
+			expectedStapled: true,
+		},
+		{
+			desc:            "revoked",
+			status:          ocsp.Revoked,
+			nextUpdate:      time.Now().Add(time.Hour),
+			expectedStatus:  ocsp.Revoked,
+			expectedStapled: true,
+		},
+		{
+			desc:            "responder override",
+			status:          ocsp.Good,
+			nextUpdate:      time.Now().Add(time.Hour),
+			override:        true,
+			expectedStatus:  ocsp.Good,
+			expectedStapled: true,
+		},
+		{
+			desc:         "no OCSP server",
+			status:       ocsp.Good,
+			nextUpdate:   time.Now().Add(time.Hour),
+			noOCSPServer: true,
+		},
+		{
+			desc:             "responder failure",
+			status:           ocsp.ServerFailed,
+			expectedFailures: 1,
+		},
+		{
+			desc:             "unknown status",
+			status:           ocsp.Unknown,
+			nextUpdate:       time.Now().Add(time.Hour),
+			expectedFailures: 1,
+		},
+		{
+			desc:             "expired response",
+			status:           ocsp.Good,
+			nextUpdate:       time.Now().Add(-time.Second),
+			expectedFailures: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			responder := newOCSPResponder(t, ca, test.status, test.nextUpdate)
+
+			ocspServer := responder.URL
+			config := &OCSPConfig{}
+			if test.override {
+				ocspServer = "http://ocsp.example.com"
+				config.ResponderOverrides = map[string]string{ocspServer: responder.URL}
+			}
+			if test.noOCSPServer {
+				ocspServer = ""
+			}
+
+			certificate := ca.issueServer(t, "foo.example.com", ocspServer)
+			cert, err := certificate.GetCertificate()
+			require.NoError(t, err)
+
+			failures := &collectingCounter{}
+			stapler := NewOCSPStapler(config, &collectingGauge{}, failures)
+			stapler.update([]*tls.Certificate{&cert})
+			stapler.refresh(context.Background())
+
+			stapled := stapler.staple(&cert)
+			assert.Equal(t, test.expectedFailures, failures.value)
+
+			if !test.expectedStapled {
+				assert.Empty(t, stapled.OCSPStaple)
+				return
+			}
+
+			require.NotEmpty(t, stapled.OCSPStaple)
+
+			response, err := ocsp.ParseResponse(stapled.OCSPStaple, ca.cert)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedStatus, response.Status)
+		})
+	}
+}
+
+func TestNextOCSPRefresh(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		desc     string
+		response *ocsp.Response
+		expected time.Time
+	}{
+		{
+			desc:     "half of the validity period",
+			response: &ocsp.Response{ThisUpdate: now.Add(-time.Hour), NextUpdate: now.Add(3 * time.Hour)},
+			expected: now.Add(time.Hour),
+		},
+		{
+			desc:     "no next update",
+			response: &ocsp.Response{ThisUpdate: now.Add(-time.Hour)},
+			expected: now.Add(ocspDefaultRefreshInterval),
+		},
+		{
+			desc:     "validity period almost over",
+			response: &ocsp.Response{ThisUpdate: now.Add(-time.Hour), NextUpdate: now.Add(time.Second)},
+			expected: now.Add(ocspRetryInterval),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, nextOCSPRefresh(test.response, now))
+		})
+	}
+}
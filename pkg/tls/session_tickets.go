@@ -0,0 +1,246 @@
+package tls
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+const (
+	sessionTicketCheckInterval = time.Minute
+	// sessionTicketKeysKept is the number of keys decrypting the session tickets:
+	// the current key, which encrypts the new tickets, and the previous ones.
+	sessionTicketKeysKept = 3
+	// sessionTicketSaveAttempts is the number of attempts to save the keys rotated concurrently by other instances.
+	sessionTicketSaveAttempts = 3
+)
+
+// SessionTicketsConfig configures the keys encrypting the TLS session tickets.
+type SessionTicketsConfig struct {
+	Keys             []string                 `description:"Session ticket keys, base64 encoded 32-byte keys: the first one encrypts the tickets, and all of them decrypt them." json:"keys,omitempty" toml:"keys,omitempty" yaml:"keys,omitempty"`
+	RotationInterval ptypes.Duration          `description:"Interval at which a new session ticket key is generated, when no keys are defined." json:"rotationInterval,omitempty" toml:"rotationInterval,omitempty" yaml:"rotationInterval,omitempty" export:"true"`
+	KVStorage        *SessionTicketsKVStorage `description:"KV store sharing the generated session ticket keys between the Traefik instances." json:"kvStorage,omitempty" toml:"kvStorage,omitempty" yaml:"kvStorage,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (c *SessionTicketsConfig) SetDefaults() {
+	c.RotationInterval = ptypes.Duration(12 * time.Hour)
+}
+
+// SessionTicketsKVStorage holds the configuration of the KV store sharing the session ticket keys of a cluster of Traefik instances.
+type SessionTicketsKVStorage struct {
+	Backend   string           `description:"KV store backend: consul, etcd, zookeeper or redis." json:"backend,omitempty" toml:"backend,omitempty" yaml:"backend,omitempty" export:"true"`
+	Endpoints []string         `description:"KV store endpoints." json:"endpoints,omitempty" toml:"endpoints,omitempty" yaml:"endpoints,omitempty"`
+	Username  string           `description:"KV store username." json:"username,omitempty" toml:"username,omitempty" yaml:"username,omitempty"`
+	Password  string           `description:"KV store password." json:"password,omitempty" toml:"password,omitempty" yaml:"password,omitempty"`
+	TLS       *types.ClientTLS `description:"Enable TLS support." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	RootKey   string           `description:"Root key of the session ticket keys." json:"rootKey,omitempty" toml:"rootKey,omitempty" yaml:"rootKey,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (s *SessionTicketsKVStorage) SetDefaults() {
+	s.Backend = "consul"
+	s.RootKey = "traefik/tls"
+}
+
+// SessionTicketKeysStore shares the session ticket keys between the Traefik instances.
+type SessionTicketKeysStore interface {
+	// Load returns the shared keys, nil if there are none yet, and their version.
+	Load() ([]byte, uint64, error)
+	// Save saves the keys if the shared ones were not modified since the given version,
+	// or if there were none for the version 0, and reports whether they were saved.
+	Save(data []byte, version uint64) (bool, error)
+}
+
+// sessionTicketKey is a session ticket key, and the time at which it was generated.
+type sessionTicketKey struct {
+	Key       []byte    `json:"key"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// SessionTicketRotator provides the keys encrypting the session tickets:
+// either the configured keys, or keys generated at the rotation interval,
+// the keys older than the last rotations being dropped to preserve the forward secrecy.
+// The generated keys are shared through the store, if any, so that every instance can resume the sessions of the others.
+type SessionTicketRotator struct {
+	staticKeys [][32]byte
+	interval   time.Duration
+	store      SessionTicketKeysStore
+	apply      func(keys [][32]byte)
+
+	mu   sync.Mutex
+	keys []sessionTicketKey
+}
+
+// NewSessionTicketRotator creates a new SessionTicketRotator, applying the keys with the given function each time they change.
+func NewSessionTicketRotator(config *SessionTicketsConfig, store SessionTicketKeysStore, apply func(keys [][32]byte)) (*SessionTicketRotator, error) {
+	var staticKeys [][32]byte
+	for i, encoded := range config.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid session ticket key %d: %w", i, err)
+		}
+
+		if len(key) != 32 {
+			return nil, fmt.Errorf("invalid session ticket key %d: %d bytes instead of 32", i, len(key))
+		}
+
+		var staticKey [32]byte
+		copy(staticKey[:], key)
+		staticKeys = append(staticKeys, staticKey)
+	}
+
+	if len(staticKeys) == 0 && config.RotationInterval <= 0 {
+		return nil, errors.New("the session ticket keys rotation interval must be positive")
+	}
+
+	return &SessionTicketRotator{
+		staticKeys: staticKeys,
+		interval:   time.Duration(config.RotationInterval),
+		store:      store,
+		apply:      apply,
+	}, nil
+}
+
+// Run applies the keys, and rotates them until the context is canceled.
+func (r *SessionTicketRotator) Run(ctx context.Context) {
+	if len(r.staticKeys) > 0 {
+		r.apply(r.staticKeys)
+		return
+	}
+
+	checkInterval := sessionTicketCheckInterval
+	if r.interval < checkInterval {
+		checkInterval = r.interval
+	}
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		r.rotate(time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// rotate generates a new key once the current one is older than the rotation interval,
+// or uses the one generated by another instance, and applies the keys if they changed.
+func (r *SessionTicketRotator) rotate(now time.Time) {
+	logger := log.WithoutContext()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys, err := r.rotateShared(now)
+	if err != nil {
+		logger.Errorf("Unable to share the session ticket keys, rotating them locally: %v", err)
+	}
+
+	if keys == nil {
+		keys, err = rotateSessionTicketKeys(r.keys, r.interval, now)
+		if err != nil {
+			logger.Errorf("Unable to generate a session ticket key: %v", err)
+			return
+		}
+	}
+
+	if len(keys) == len(r.keys) && keys[0].CreatedAt.Equal(r.keys[0].CreatedAt) {
+		return
+	}
+
+	r.keys = keys
+
+	applied := make([][32]byte, len(keys))
+	for i, key := range keys {
+		copy(applied[i][:], key.Key)
+	}
+
+	r.apply(applied)
+}
+
+// rotateShared rotates the keys shared through the store, and returns them.
+func (r *SessionTicketRotator) rotateShared(now time.Time) ([]sessionTicketKey, error) {
+	if r.store == nil {
+		return nil, nil
+	}
+
+	for i := 0; i < sessionTicketSaveAttempts; i++ {
+		data, version, err := r.store.Load()
+		if err != nil {
+			return nil, err
+		}
+
+		var stored []sessionTicketKey
+		if data != nil {
+			if err := json.Unmarshal(data, &stored); err != nil {
+				return nil, fmt.Errorf("invalid shared session ticket keys: %w", err)
+			}
+		}
+
+		keys, err := rotateSessionTicketKeys(stored, r.interval, now)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(stored) > 0 && keys[0].CreatedAt.Equal(stored[0].CreatedAt) {
+			return keys, nil
+		}
+
+		data, err = json.Marshal(keys)
+		if err != nil {
+			return nil, err
+		}
+
+		saved, err := r.store.Save(data, version)
+		if err != nil {
+			return nil, err
+		}
+
+		if saved {
+			return keys, nil
+		}
+	}
+
+	return nil, errors.New("session ticket keys rotated concurrently")
+}
+
+// rotateSessionTicketKeys returns the keys, with a new key first if the current one is older than the interval,
+// and without the keys older than the last rotations.
+func rotateSessionTicketKeys(keys []sessionTicketKey, interval time.Duration, now time.Time) ([]sessionTicketKey, error) {
+	var valid []sessionTicketKey
+	for _, key := range keys {
+		if len(key.Key) == 32 && now.Sub(key.CreatedAt) < sessionTicketKeysKept*interval {
+			valid = append(valid, key)
+		}
+	}
+
+	if len(valid) > 0 && now.Sub(valid[0].CreatedAt) < interval {
+		return valid, nil
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	rotated := append([]sessionTicketKey{{Key: key, CreatedAt: now}}, valid...)
+	if len(rotated) > sessionTicketKeysKept {
+		rotated = rotated[:sessionTicketKeysKept]
+	}
+
+	return rotated, nil
+}
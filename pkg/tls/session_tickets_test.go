@@ -0,0 +1,267 @@
+package tls
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+)
+
+// memorySessionTicketKeysStore is a SessionTicketKeysStore keeping the keys in memory.
+type memorySessionTicketKeysStore struct {
+	mu      sync.Mutex
+	data    []byte
+	version uint64
+}
+
+func (s *memorySessionTicketKeysStore) Load() ([]byte, uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.data, s.version, nil
+}
+
+func (s *memorySessionTicketKeysStore) Save(data []byte, version uint64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if version != s.version {
+		return false, nil
+	}
+
+	s.data = data
+	s.version++
+
+	return true, nil
+}
+
+// appliedKeys collects the keys applied by a SessionTicketRotator.
+type appliedKeys struct {
+	mu    sync.Mutex
+	calls int
+	keys  [][32]byte
+}
+
+func (a *appliedKeys) apply(keys [][32]byte) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.calls++
+	a.keys = keys
+}
+
+func (a *appliedKeys) get() (int, [][32]byte) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.calls, a.keys
+}
+
+func TestNewSessionTicketRotator(t *testing.T) {
+	validKey := base64.StdEncoding.EncodeToString(make([]byte, 32))
+
+	testCases := []struct {
+		desc        string
+		config      SessionTicketsConfig
+		expectedErr string
+	}{
+		{
+			desc:   "static keys",
+			config: SessionTicketsConfig{Keys: []string{validKey, validKey}},
+		},
+		{
+			desc:   "rotation",
+			config: SessionTicketsConfig{RotationInterval: ptypes.Duration(time.Hour)},
+		},
+		{
+			desc:        "invalid base64 key",
+			config:      SessionTicketsConfig{Keys: []string{validKey, "not base64!"}},
+			expectedErr: "invalid session ticket key 1: illegal base64 data at input byte 3",
+		},
+		{
+			desc:        "invalid key length",
+			config:      SessionTicketsConfig{Keys: []string{base64.StdEncoding.EncodeToString(make([]byte, 16))}},
+			expectedErr: "invalid session ticket key 0: 16 bytes instead of 32",
+		},
+		{
+			desc:        "no rotation interval",
+			config:      SessionTicketsConfig{},
+			expectedErr: "the session ticket keys rotation interval must be positive",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewSessionTicketRotator(&test.config, nil, func([][32]byte) {})
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSessionTicketRotator_Run_staticKeys(t *testing.T) {
+	key := [32]byte{1, 2, 3}
+
+	applied := &appliedKeys{}
+	rotator, err := NewSessionTicketRotator(&SessionTicketsConfig{
+		Keys: []string{base64.StdEncoding.EncodeToString(key[:])},
+	}, nil, applied.apply)
+	require.NoError(t, err)
+
+	// The static keys are applied once, and never rotated.
+	rotator.Run(context.Background())
+
+	calls, keys := applied.get()
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, [][32]byte{key}, keys)
+}
+
+func TestSessionTicketRotator_rotate(t *testing.T) {
+	applied := &appliedKeys{}
+	rotator, err := NewSessionTicketRotator(&SessionTicketsConfig{RotationInterval: ptypes.Duration(time.Hour)}, nil, applied.apply)
+	require.NoError(t, err)
+
+	now := time.Now()
+
+	rotator.rotate(now)
+
+	calls, keys := applied.get()
+	assert.Equal(t, 1, calls)
+	require.Len(t, keys, 1)
+	first := keys[0]
+
+	// The keys are not rotated before the interval.
+	rotator.rotate(now.Add(30 * time.Minute))
+
+	calls, _ = applied.get()
+	assert.Equal(t, 1, calls)
+
+	// The new key encrypts the tickets, the previous one still decrypts them.
+	rotator.rotate(now.Add(time.Hour))
+
+	calls, keys = applied.get()
+	assert.Equal(t, 2, calls)
+	require.Len(t, keys, 2)
+	assert.NotEqual(t, first, keys[0])
+	assert.Equal(t, first, keys[1])
+
+	rotator.rotate(now.Add(2 * time.Hour))
+	rotator.rotate(now.Add(3 * time.Hour))
+
+	// The oldest key is dropped.
+	calls, keys = applied.get()
+	assert.Equal(t, 4, calls)
+	require.Len(t, keys, sessionTicketKeysKept)
+	assert.NotContains(t, keys, first)
+
+	// The keys generated before a long pause are all dropped.
+	rotator.rotate(now.Add(10 * time.Hour))
+
+	calls, keys = applied.get()
+	assert.Equal(t, 5, calls)
+	assert.Len(t, keys, 1)
+}
+
+func TestSessionTicketRotator_rotate_shared(t *testing.T) {
+	store := &memorySessionTicketKeysStore{}
+	config := &SessionTicketsConfig{RotationInterval: ptypes.Duration(time.Hour)}
+
+	appliedA := &appliedKeys{}
+	rotatorA, err := NewSessionTicketRotator(config, store, appliedA.apply)
+	require.NoError(t, err)
+
+	appliedB := &appliedKeys{}
+	rotatorB, err := NewSessionTicketRotator(config, store, appliedB.apply)
+	require.NoError(t, err)
+
+	now := time.Now()
+
+	rotatorA.rotate(now)
+	rotatorB.rotate(now.Add(time.Minute))
+
+	_, keysA := appliedA.get()
+	_, keysB := appliedB.get()
+	require.Len(t, keysA, 1)
+	assert.Equal(t, keysA, keysB)
+
+	// The instance checking first rotates the keys, and the other one uses them.
+	rotatorB.rotate(now.Add(time.Hour))
+	rotatorA.rotate(now.Add(time.Hour + time.Minute))
+
+	_, keysA = appliedA.get()
+	_, keysB = appliedB.get()
+	require.Len(t, keysA, 2)
+	assert.Equal(t, keysA, keysB)
+	assert.Equal(t, uint64(2), store.version)
+}
+
+func TestManager_SetSessionTicketKeys(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	dynamicConfigs := []*CertAndStores{{Certificate: ca.issueServer(t, "foo.example.com", "")}}
+
+	newServerConfig := func(keys [][32]byte) *tls.Config {
+		tlsManager := NewManager()
+		tlsManager.UpdateConfigs(context.Background(), nil, map[string]Options{DefaultTLSConfigName: {}}, dynamicConfigs)
+
+		config, err := tlsManager.Get(DefaultTLSStoreName, DefaultTLSConfigName)
+		require.NoError(t, err)
+
+		// The keys are applied on the configurations already built.
+		tlsManager.SetSessionTicketKeys(keys)
+
+		return config
+	}
+
+	sharedKeys := [][32]byte{{1}, {2}}
+
+	serverA := newServerConfig(sharedKeys)
+	serverB := newServerConfig(sharedKeys)
+	serverC := newServerConfig([][32]byte{{3}})
+
+	clientConfig := &tls.Config{
+		ServerName:         "foo.example.com",
+		InsecureSkipVerify: true,
+		MaxVersion:         tls.VersionTLS12,
+		ClientSessionCache: tls.NewLRUClientSessionCache(1),
+	}
+
+	assert.False(t, handshake(t, serverA, clientConfig).DidResume)
+
+	// The session is resumed by the other instances sharing the keys only.
+	assert.True(t, handshake(t, serverB, clientConfig).DidResume)
+	assert.False(t, handshake(t, serverC, clientConfig).DidResume)
+}
+
+func handshake(t *testing.T, serverConfig, clientConfig *tls.Config) tls.ConnectionState {
+	t.Helper()
+
+	serverConn, clientConn := net.Pipe()
+	defer func() { _ = serverConn.Close() }()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- tls.Server(serverConn, serverConfig).Handshake()
+	}()
+
+	client := tls.Client(clientConn, clientConfig)
+	defer func() { _ = client.Close() }()
+
+	require.NoError(t, client.Handshake())
+	require.NoError(t, <-errCh)
+
+	return client.ConnectionState()
+}
@@ -1,5 +1,7 @@
 package tls
 
+import ptypes "github.com/traefik/paerser/types"
+
 const certificateHeader = "-----BEGIN CERTIFICATE-----\n"
 
 // +k8s:deepcopy-gen=true
@@ -8,8 +10,14 @@ const certificateHeader = "-----BEGIN CERTIFICATE-----\n"
 type ClientAuth struct {
 	CAFiles []FileOrContent `json:"caFiles,omitempty" toml:"caFiles,omitempty" yaml:"caFiles,omitempty"`
 	// ClientAuthType defines the client authentication type to apply.
-	// The available values are: "NoClientCert", "RequestClientCert", "VerifyClientCertIfGiven" and "RequireAndVerifyClientCert".
+	// The available values are: "NoClientCert", "RequestClientCert", "RequireAnyClientCert", "VerifyClientCertIfGiven" and "RequireAndVerifyClientCert".
 	ClientAuthType string `json:"clientAuthType,omitempty" toml:"clientAuthType,omitempty" yaml:"clientAuthType,omitempty" export:"true"`
+	// CRLFiles defines the certificate revocation lists, as file paths or contents, used to reject the revoked client certificates.
+	CRLFiles []FileOrContent `json:"crlFiles,omitempty" toml:"crlFiles,omitempty" yaml:"crlFiles,omitempty"`
+	// CRLURLs defines the URLs from which the certificate revocation lists are downloaded.
+	CRLURLs []string `json:"crlURLs,omitempty" toml:"crlURLs,omitempty" yaml:"crlURLs,omitempty"`
+	// CRLRefreshInterval defines the interval between two reloads of the certificate revocation lists.
+	CRLRefreshInterval ptypes.Duration `json:"crlRefreshInterval,omitempty" toml:"crlRefreshInterval,omitempty" yaml:"crlRefreshInterval,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -20,6 +28,7 @@ type Options struct {
 	MaxVersion               string     `json:"maxVersion,omitempty" toml:"maxVersion,omitempty" yaml:"maxVersion,omitempty" export:"true"`
 	CipherSuites             []string   `json:"cipherSuites,omitempty" toml:"cipherSuites,omitempty" yaml:"cipherSuites,omitempty" export:"true"`
 	CurvePreferences         []string   `json:"curvePreferences,omitempty" toml:"curvePreferences,omitempty" yaml:"curvePreferences,omitempty" export:"true"`
+	ALPNProtocols            []string   `json:"alpnProtocols,omitempty" toml:"alpnProtocols,omitempty" yaml:"alpnProtocols,omitempty" export:"true"`
 	ClientAuth               ClientAuth `json:"clientAuth,omitempty" toml:"clientAuth,omitempty" yaml:"clientAuth,omitempty"`
 	SniStrict                bool       `json:"sniStrict,omitempty" toml:"sniStrict,omitempty" yaml:"sniStrict,omitempty" export:"true"`
 	PreferServerCipherSuites bool       `json:"preferServerCipherSuites,omitempty" toml:"preferServerCipherSuites,omitempty" yaml:"preferServerCipherSuites,omitempty" export:"true"`
@@ -30,6 +39,21 @@ type Options struct {
 // Store holds the options for a given Store.
 type Store struct {
 	DefaultCertificate *Certificate `json:"defaultCertificate,omitempty" toml:"defaultCertificate,omitempty" yaml:"defaultCertificate,omitempty" export:"true"`
+	// GeneratedCertificate configures the self-signed certificate generated when no default certificate is given.
+	GeneratedCertificate *GeneratedCertificate `json:"generatedCertificate,omitempty" toml:"generatedCertificate,omitempty" yaml:"generatedCertificate,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// GeneratedCertificate holds the options of the generated self-signed default certificate.
+type GeneratedCertificate struct {
+	// CommonName defines the common name of the certificate subject, "TRAEFIK DEFAULT CERT" by default.
+	CommonName string `json:"commonName,omitempty" toml:"commonName,omitempty" yaml:"commonName,omitempty" export:"true"`
+	// Validity defines the duration during which the certificate is valid, one year by default.
+	Validity ptypes.Duration `json:"validity,omitempty" toml:"validity,omitempty" yaml:"validity,omitempty" export:"true"`
+	// KeyType defines the type of the private key.
+	// The available values are: "RSA2048" (default), "RSA4096", "EC256" and "EC384".
+	KeyType string `json:"keyType,omitempty" toml:"keyType,omitempty" yaml:"keyType,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
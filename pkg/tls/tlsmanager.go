@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
 	"github.com/sirupsen/logrus"
@@ -33,6 +34,16 @@ type Manager struct {
 	stores       map[string]*CertificateStore
 	configs      map[string]Options
 	certs        []*CertAndStores
+	revocations  map[string]*revocationList
+	ocspStapler  *OCSPStapler
+
+	certificateMetrics *CertificateMetrics
+	handshakes         map[string]*storeHandshakes
+
+	sessionTicketsLock sync.Mutex
+	sessionTicketKeys  [][32]byte
+	// sessionTicketConfigs are the configurations built since the last configuration update, on which the keys are applied.
+	sessionTicketConfigs []*tls.Config
 }
 
 // NewManager creates a new Manager.
@@ -55,6 +66,30 @@ func (m *Manager) UpdateConfigs(ctx context.Context, stores map[string]Store, co
 	m.storesConfig = stores
 	m.certs = certs
 
+	m.sessionTicketsLock.Lock()
+	m.sessionTicketConfigs = nil
+	m.sessionTicketsLock.Unlock()
+
+	// The options are validated at load time, rather than only when the routers using them are built.
+	for configName, config := range configs {
+		if _, err := buildTLSConfig(config); err != nil {
+			log.FromContext(ctx).Errorf("Invalid TLS options %s: %v", configName, err)
+		}
+	}
+
+	m.revocations = make(map[string]*revocationList)
+	for configName, config := range configs {
+		if len(config.ClientAuth.CRLFiles) == 0 && len(config.ClientAuth.CRLURLs) == 0 {
+			continue
+		}
+
+		revocations := newRevocationList(configName, config.ClientAuth)
+		m.revocations[configName] = revocations
+
+		// Loads the CRLs ahead of the first client certificate verification.
+		go revocations.refresh()
+	}
+
 	if m.storesConfig == nil {
 		m.storesConfig = make(map[string]Store)
 	}
@@ -103,6 +138,89 @@ func (m *Manager) UpdateConfigs(ctx context.Context, stores map[string]Store, co
 		}
 		st.DynamicCerts.Set(certs)
 	}
+
+	if m.ocspStapler != nil {
+		m.ocspStapler.update(m.servedCertificates())
+	}
+
+	if m.certificateMetrics != nil {
+		m.handshakes = m.certificateMetrics.update(m.reportedCertificates())
+	}
+}
+
+// SetOCSPStapler sets the OCSP stapler stapling the OCSP responses to the served certificates.
+// It must be set before the first configuration update.
+func (m *Manager) SetOCSPStapler(stapler *OCSPStapler) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.ocspStapler = stapler
+}
+
+// SetCertificateMetrics sets the metrics of the served certificates.
+// It must be set before the first configuration update.
+func (m *Manager) SetCertificateMetrics(certificateMetrics *CertificateMetrics) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.certificateMetrics = certificateMetrics
+}
+
+// SetSessionTicketKeys sets the keys encrypting the session tickets, on the configurations already built and the next ones.
+func (m *Manager) SetSessionTicketKeys(keys [][32]byte) {
+	m.sessionTicketsLock.Lock()
+	defer m.sessionTicketsLock.Unlock()
+
+	m.sessionTicketKeys = keys
+	for _, tlsConfig := range m.sessionTicketConfigs {
+		tlsConfig.SetSessionTicketKeys(keys)
+	}
+}
+
+// servedCertificates returns the default and dynamic certificates of the stores, except the ACME challenge store.
+func (m *Manager) servedCertificates() []*tls.Certificate {
+	var certificates []*tls.Certificate
+	for storeName, store := range m.stores {
+		if storeName == tlsalpn01.ACMETLS1Protocol {
+			continue
+		}
+
+		if store.DefaultCertificate != nil {
+			certificates = append(certificates, store.DefaultCertificate)
+		}
+
+		if store.DynamicCerts != nil && store.DynamicCerts.Get() != nil {
+			for _, cert := range store.DynamicCerts.Get().(map[string]*tls.Certificate) {
+				certificates = append(certificates, cert)
+			}
+		}
+	}
+
+	return certificates
+}
+
+// reportedCertificates returns, by store, the dynamic certificates and the configured default certificate of the stores,
+// except the ACME challenge store.
+// The generated default certificates are left out, as they are generated again on each configuration update.
+func (m *Manager) reportedCertificates() map[string][]*tls.Certificate {
+	certificates := make(map[string][]*tls.Certificate)
+	for storeName, store := range m.stores {
+		if storeName == tlsalpn01.ACMETLS1Protocol {
+			continue
+		}
+
+		if storeConfig, ok := m.storesConfig[storeName]; ok && storeConfig.DefaultCertificate != nil && store.DefaultCertificate != nil {
+			certificates[storeName] = append(certificates[storeName], store.DefaultCertificate)
+		}
+
+		if store.DynamicCerts != nil && store.DynamicCerts.Get() != nil {
+			for _, cert := range store.DynamicCerts.Get().(map[string]*tls.Certificate) {
+				certificates[storeName] = append(certificates[storeName], cert)
+			}
+		}
+	}
+
+	return certificates
 }
 
 // Get gets the TLS configuration to use for a given store / configuration.
@@ -118,6 +236,9 @@ func (m *Manager) Get(storeName, configName string) (*tls.Config, error) {
 	if ok {
 		sniStrict = config.SniStrict
 		tlsConfig, err = buildTLSConfig(config)
+		if revocations, ok := m.revocations[configName]; ok && err == nil {
+			tlsConfig.VerifyConnection = revocations.verifyConnection
+		}
 	} else {
 		err = fmt.Errorf("unknown TLS options: %s", configName)
 	}
@@ -134,6 +255,8 @@ func (m *Manager) Get(storeName, configName string) (*tls.Config, error) {
 		err = fmt.Errorf("ACME TLS store %s not found", tlsalpn01.ACMETLS1Protocol)
 	}
 
+	ocspStapler := m.ocspStapler
+	handshakes := m.handshakes[storeName]
 	tlsConfig.GetCertificate = func(clientHello *tls.ClientHelloInfo) (*tls.Certificate, error) {
 		domainToCheck := types.CanonicalDomain(clientHello.ServerName)
 
@@ -148,7 +271,8 @@ func (m *Manager) Get(storeName, configName string) (*tls.Config, error) {
 
 		bestCertificate := store.GetBestCertificate(clientHello)
 		if bestCertificate != nil {
-			return bestCertificate, nil
+			handshakes.count(bestCertificate)
+			return ocspStapler.staple(bestCertificate), nil
 		}
 
 		if sniStrict {
@@ -156,8 +280,16 @@ func (m *Manager) Get(storeName, configName string) (*tls.Config, error) {
 		}
 
 		log.WithoutContext().Debugf("Serving default certificate for request: %q", domainToCheck)
-		return store.DefaultCertificate, nil
+		handshakes.count(store.DefaultCertificate)
+		return ocspStapler.staple(store.DefaultCertificate), nil
+	}
+
+	m.sessionTicketsLock.Lock()
+	if len(m.sessionTicketKeys) > 0 {
+		tlsConfig.SetSessionTicketKeys(m.sessionTicketKeys)
 	}
+	m.sessionTicketConfigs = append(m.sessionTicketConfigs, tlsConfig)
+	m.sessionTicketsLock.Unlock()
 
 	return tlsConfig, err
 }
@@ -220,9 +352,23 @@ func buildCertificateStore(ctx context.Context, tlsStore Store, storename string
 	}
 
 	log.FromContext(ctx).Debug("No default certificate, generating one")
-	cert, err := generate.DefaultCertificate()
+
+	var opts generate.Options
+	if tlsStore.GeneratedCertificate != nil {
+		if tlsStore.GeneratedCertificate.Validity < 0 {
+			return certificateStore, errors.New("the validity of the generated certificate must be positive")
+		}
+
+		opts = generate.Options{
+			CommonName: tlsStore.GeneratedCertificate.CommonName,
+			Validity:   time.Duration(tlsStore.GeneratedCertificate.Validity),
+			KeyType:    tlsStore.GeneratedCertificate.KeyType,
+		}
+	}
+
+	cert, err := generate.Certificate(opts)
 	if err != nil {
-		return certificateStore, err
+		return certificateStore, fmt.Errorf("unable to generate the default certificate: %w", err)
 	}
 	certificateStore.DefaultCertificate = cert
 	return certificateStore, nil
@@ -235,6 +381,22 @@ func buildTLSConfig(tlsOption Options) (*tls.Config, error) {
 	// ensure http2 enabled
 	conf.NextProtos = []string{"h2", "http/1.1", tlsalpn01.ACMETLS1Protocol}
 
+	if len(tlsOption.ALPNProtocols) > 0 {
+		conf.NextProtos = make([]string, 0, len(tlsOption.ALPNProtocols)+1)
+		for _, protocol := range tlsOption.ALPNProtocols {
+			if protocol == "" {
+				return nil, errors.New("invalid empty ALPN protocol")
+			}
+
+			if protocol != tlsalpn01.ACMETLS1Protocol {
+				conf.NextProtos = append(conf.NextProtos, protocol)
+			}
+		}
+
+		// The ACME TLS-ALPN-01 challenge protocol is always negotiated, so that the certificates can still be obtained.
+		conf.NextProtos = append(conf.NextProtos, tlsalpn01.ACMETLS1Protocol)
+	}
+
 	if len(tlsOption.ClientAuth.CAFiles) > 0 {
 		pool := x509.NewCertPool()
 		for _, caFile := range tlsOption.ClientAuth.CAFiles {
@@ -254,6 +416,10 @@ func buildTLSConfig(tlsOption Options) (*tls.Config, error) {
 		conf.ClientAuth = tls.RequireAndVerifyClientCert
 	}
 
+	if conf.ClientCAs == nil && (len(tlsOption.ClientAuth.CRLFiles) > 0 || len(tlsOption.ClientAuth.CRLURLs) > 0) {
+		return nil, errors.New("CAFiles is required to verify the certificate revocation lists")
+	}
+
 	clientAuthType := tlsOption.ClientAuth.ClientAuthType
 	if len(clientAuthType) > 0 {
 		if conf.ClientCAs == nil && (clientAuthType == "VerifyClientCertIfGiven" ||
@@ -261,6 +427,11 @@ func buildTLSConfig(tlsOption Options) (*tls.Config, error) {
 			return nil, fmt.Errorf("invalid clientAuthType: %s, CAFiles is required", clientAuthType)
 		}
 
+		if (len(tlsOption.ClientAuth.CRLFiles) > 0 || len(tlsOption.ClientAuth.CRLURLs) > 0) &&
+			clientAuthType != "VerifyClientCertIfGiven" && clientAuthType != "RequireAndVerifyClientCert" {
+			return nil, fmt.Errorf("invalid clientAuthType: %s, the certificate revocation lists require the verification of the client certificates", clientAuthType)
+		}
+
 		switch clientAuthType {
 		case "NoClientCert":
 			conf.ClientAuth = tls.NoClientCert
@@ -281,17 +452,31 @@ func buildTLSConfig(tlsOption Options) (*tls.Config, error) {
 	conf.PreferServerCipherSuites = tlsOption.PreferServerCipherSuites
 
 	// Set the minimum TLS version if set in the config
-	if minConst, exists := MinVersion[tlsOption.MinVersion]; exists {
+	if len(tlsOption.MinVersion) > 0 {
+		minConst, exists := MinVersion[tlsOption.MinVersion]
+		if !exists {
+			return nil, fmt.Errorf("invalid minVersion: %s", tlsOption.MinVersion)
+		}
+
 		conf.PreferServerCipherSuites = true
 		conf.MinVersion = minConst
 	}
 
 	// Set the maximum TLS version if set in the config TOML
-	if maxConst, exists := MaxVersion[tlsOption.MaxVersion]; exists {
+	if len(tlsOption.MaxVersion) > 0 {
+		maxConst, exists := MaxVersion[tlsOption.MaxVersion]
+		if !exists {
+			return nil, fmt.Errorf("invalid maxVersion: %s", tlsOption.MaxVersion)
+		}
+
 		conf.PreferServerCipherSuites = true
 		conf.MaxVersion = maxConst
 	}
 
+	if conf.MinVersion != 0 && conf.MaxVersion != 0 && conf.MinVersion > conf.MaxVersion {
+		return nil, fmt.Errorf("minVersion %s is greater than maxVersion %s", tlsOption.MinVersion, tlsOption.MaxVersion)
+	}
+
 	// Set the list of CipherSuites if set in the config
 	if tlsOption.CipherSuites != nil {
 		// if our list of CipherSuites is defined in the entryPoint config, we can re-initialize the suites list as empty
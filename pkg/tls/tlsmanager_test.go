@@ -2,13 +2,18 @@ package tls
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
 )
 
 // LocalhostCert is a PEM-encoded TLS cert with SAN IPs
@@ -91,6 +96,73 @@ func TestTLSInvalidStore(t *testing.T) {
 	}
 }
 
+func TestManager_generatedCertificate(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		config             *GeneratedCertificate
+		expectedCommonName string
+		expectedValidity   time.Duration
+		expectedKey        interface{}
+		expectedErr        bool
+	}{
+		{
+			desc:               "default",
+			expectedCommonName: "TRAEFIK DEFAULT CERT",
+			expectedValidity:   365 * 24 * time.Hour,
+			expectedKey:        &rsa.PrivateKey{},
+		},
+		{
+			desc: "custom",
+			config: &GeneratedCertificate{
+				CommonName: "example.com",
+				Validity:   ptypes.Duration(24 * time.Hour),
+				KeyType:    "EC256",
+			},
+			expectedCommonName: "example.com",
+			expectedValidity:   24 * time.Hour,
+			expectedKey:        &ecdsa.PrivateKey{},
+		},
+		{
+			desc:        "unsupported key type",
+			config:      &GeneratedCertificate{KeyType: "DSA"},
+			expectedErr: true,
+		},
+		{
+			desc:        "negative validity",
+			config:      &GeneratedCertificate{Validity: ptypes.Duration(-time.Hour)},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			tlsManager := NewManager()
+			tlsManager.UpdateConfigs(context.Background(), map[string]Store{
+				"default": {GeneratedCertificate: test.config},
+			}, nil, nil)
+
+			store := tlsManager.GetStore("default")
+			if test.expectedErr {
+				assert.Nil(t, store)
+				return
+			}
+
+			require.NotNil(t, store)
+			require.NotNil(t, store.DefaultCertificate)
+
+			leaf, err := x509.ParseCertificate(store.DefaultCertificate.Certificate[0])
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedCommonName, leaf.Subject.CommonName)
+			assert.WithinDuration(t, time.Now().Add(test.expectedValidity), leaf.NotAfter, time.Minute)
+			assert.IsType(t, test.expectedKey, store.DefaultCertificate.PrivateKey)
+		})
+	}
+}
+
 func TestManager_Get(t *testing.T) {
 	dynamicConfigs := []*CertAndStores{{
 		Certificate: Certificate{
@@ -152,6 +224,160 @@ func TestManager_Get(t *testing.T) {
 	}
 }
 
+func TestManager_Get_sniStrict(t *testing.T) {
+	ca := newTestCA(t, "ca")
+	dynamicConfigs := []*CertAndStores{{Certificate: ca.issueServer(t, "foo.example.com", "")}}
+
+	testCases := []struct {
+		desc               string
+		sniStrict          bool
+		serverName         string
+		expectedCommonName string
+		expectedErr        bool
+	}{
+		{
+			desc:               "matching certificate",
+			sniStrict:          true,
+			serverName:         "foo.example.com",
+			expectedCommonName: "foo.example.com",
+		},
+		{
+			desc:        "no matching certificate",
+			sniStrict:   true,
+			serverName:  "bar.example.com",
+			expectedErr: true,
+		},
+		{
+			desc:        "no SNI",
+			sniStrict:   true,
+			expectedErr: true,
+		},
+		{
+			desc:               "no matching certificate without strict SNI",
+			serverName:         "bar.example.com",
+			expectedCommonName: "TRAEFIK DEFAULT CERT",
+		},
+		{
+			desc:               "no SNI without strict SNI",
+			expectedCommonName: "TRAEFIK DEFAULT CERT",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			tlsManager := NewManager()
+			tlsManager.UpdateConfigs(context.Background(), nil, map[string]Options{
+				DefaultTLSConfigName: {SniStrict: test.sniStrict},
+			}, dynamicConfigs)
+
+			config, err := tlsManager.Get(DefaultTLSStoreName, DefaultTLSConfigName)
+			require.NoError(t, err)
+
+			// The local address of the connection is checked when the client does not send the SNI.
+			conn, _ := net.Pipe()
+			defer func() { _ = conn.Close() }()
+
+			certificate, err := config.GetCertificate(&tls.ClientHelloInfo{ServerName: test.serverName, Conn: conn})
+			if test.expectedErr {
+				assert.Error(t, err)
+				assert.Nil(t, certificate)
+				return
+			}
+
+			require.NoError(t, err)
+
+			leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedCommonName, leaf.Subject.CommonName)
+		})
+	}
+}
+
+func TestBuildTLSConfig(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		options            Options
+		expectedNextProtos []string
+		expectedMinVersion uint16
+		expectedMaxVersion uint16
+		expectedErr        bool
+	}{
+		{
+			desc:               "default options",
+			expectedNextProtos: []string{"h2", "http/1.1", "acme-tls/1"},
+		},
+		{
+			desc:               "ALPN protocols",
+			options:            Options{ALPNProtocols: []string{"http/1.1"}},
+			expectedNextProtos: []string{"http/1.1", "acme-tls/1"},
+		},
+		{
+			desc:               "ALPN protocols with the ACME protocol",
+			options:            Options{ALPNProtocols: []string{"acme-tls/1", "h2"}},
+			expectedNextProtos: []string{"h2", "acme-tls/1"},
+		},
+		{
+			desc:        "empty ALPN protocol",
+			options:     Options{ALPNProtocols: []string{""}},
+			expectedErr: true,
+		},
+		{
+			desc:               "TLS 1.3 only",
+			options:            Options{MinVersion: "VersionTLS13"},
+			expectedNextProtos: []string{"h2", "http/1.1", "acme-tls/1"},
+			expectedMinVersion: tls.VersionTLS13,
+		},
+		{
+			desc:               "TLS 1.2 range",
+			options:            Options{MinVersion: "VersionTLS12", MaxVersion: "VersionTLS12"},
+			expectedNextProtos: []string{"h2", "http/1.1", "acme-tls/1"},
+			expectedMinVersion: tls.VersionTLS12,
+			expectedMaxVersion: tls.VersionTLS12,
+		},
+		{
+			desc:        "unknown minimum version",
+			options:     Options{MinVersion: "VersionTLS14"},
+			expectedErr: true,
+		},
+		{
+			desc:        "unknown maximum version",
+			options:     Options{MaxVersion: "TLS12"},
+			expectedErr: true,
+		},
+		{
+			desc:        "minimum version greater than the maximum version",
+			options:     Options{MinVersion: "VersionTLS13", MaxVersion: "VersionTLS12"},
+			expectedErr: true,
+		},
+		{
+			desc:        "unknown cipher suite",
+			options:     Options{CipherSuites: []string{"TLS_UNKNOWN"}},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config, err := buildTLSConfig(test.options)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedNextProtos, config.NextProtos)
+			assert.Equal(t, test.expectedMinVersion, config.MinVersion)
+			assert.Equal(t, test.expectedMaxVersion, config.MaxVersion)
+		})
+	}
+}
+
 func TestClientAuth(t *testing.T) {
 	tlsConfigs := map[string]Options{
 		"eca": {
@@ -196,6 +422,23 @@ func TestClientAuth(t *testing.T) {
 		"ucat": {
 			ClientAuth: ClientAuth{ClientAuthType: "Unknown"},
 		},
+		"crl": {
+			ClientAuth: ClientAuth{
+				CAFiles:        []FileOrContent{localhostCert},
+				CRLURLs:        []string{"http://localhost:0/crl"},
+				ClientAuthType: "RequireAndVerifyClientCert",
+			},
+		},
+		"crlwca": {
+			ClientAuth: ClientAuth{CRLURLs: []string{"http://localhost:0/crl"}},
+		},
+		"crlrcc": {
+			ClientAuth: ClientAuth{
+				CAFiles:        []FileOrContent{localhostCert},
+				CRLURLs:        []string{"http://localhost:0/crl"},
+				ClientAuthType: "RequestClientCert",
+			},
+		},
 	}
 
 	block, _ := pem.Decode([]byte(localhostCert))
@@ -207,6 +450,7 @@ func TestClientAuth(t *testing.T) {
 		tlsOptionsName     string
 		expectedClientAuth tls.ClientAuthType
 		expectedRawSubject []byte
+		expectedCRLCheck   bool
 		expectedError      bool
 	}{
 		{
@@ -269,6 +513,25 @@ func TestClientAuth(t *testing.T) {
 			expectedClientAuth: tls.NoClientCert,
 			expectedError:      true,
 		},
+		{
+			desc:               "Certificate revocation lists should get the verification of the revoked client certificates",
+			tlsOptionsName:     "crl",
+			expectedClientAuth: tls.RequireAndVerifyClientCert,
+			expectedRawSubject: cert.RawSubject,
+			expectedCRLCheck:   true,
+		},
+		{
+			desc:               "Certificate revocation lists without CAFiles yields a default ClientAuthType (NoClientCert)",
+			tlsOptionsName:     "crlwca",
+			expectedClientAuth: tls.NoClientCert,
+			expectedError:      true,
+		},
+		{
+			desc:               "Certificate revocation lists without the verification of the client certificates yields a default ClientAuthType (NoClientCert)",
+			tlsOptionsName:     "crlrcc",
+			expectedClientAuth: tls.NoClientCert,
+			expectedError:      true,
+		},
 	}
 
 	tlsManager := NewManager()
@@ -295,6 +558,7 @@ func TestClientAuth(t *testing.T) {
 			}
 
 			assert.Equal(t, config.ClientAuth, test.expectedClientAuth)
+			assert.Equal(t, test.expectedCRLCheck, config.VerifyConnection != nil)
 		})
 	}
 }
@@ -58,6 +58,16 @@ func (in *ClientAuth) DeepCopyInto(out *ClientAuth) {
 		*out = make([]FileOrContent, len(*in))
 		copy(*out, *in)
 	}
+	if in.CRLFiles != nil {
+		in, out := &in.CRLFiles, &out.CRLFiles
+		*out = make([]FileOrContent, len(*in))
+		copy(*out, *in)
+	}
+	if in.CRLURLs != nil {
+		in, out := &in.CRLURLs, &out.CRLURLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -71,6 +81,22 @@ func (in *ClientAuth) DeepCopy() *ClientAuth {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GeneratedCertificate) DeepCopyInto(out *GeneratedCertificate) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeneratedCertificate.
+func (in *GeneratedCertificate) DeepCopy() *GeneratedCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(GeneratedCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Options) DeepCopyInto(out *Options) {
 	*out = *in
@@ -84,6 +110,11 @@ func (in *Options) DeepCopyInto(out *Options) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ALPNProtocols != nil {
+		in, out := &in.ALPNProtocols, &out.ALPNProtocols
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.ClientAuth.DeepCopyInto(&out.ClientAuth)
 	return
 }
@@ -106,6 +137,11 @@ func (in *Store) DeepCopyInto(out *Store) {
 		*out = new(Certificate)
 		**out = **in
 	}
+	if in.GeneratedCertificate != nil {
+		in, out := &in.GeneratedCertificate, &out.GeneratedCertificate
+		*out = new(GeneratedCertificate)
+		**out = **in
+	}
 	return
 }
 
@@ -35,7 +35,8 @@ func (l *TraefikLog) SetDefaults() {
 // AccessLog holds the configuration settings for the access logger (middlewares/accesslog).
 type AccessLog struct {
 	FilePath      string            `description:"Access log file path. Stdout is used when omitted or empty." json:"filePath,omitempty" toml:"filePath,omitempty" yaml:"filePath,omitempty"`
-	Format        string            `description:"Access log format: json | common" json:"format,omitempty" toml:"format,omitempty" yaml:"format,omitempty" export:"true"`
+	Format        string            `description:"Access log format: json | common | combined | w3c | template" json:"format,omitempty" toml:"format,omitempty" yaml:"format,omitempty" export:"true"`
+	Template      string            `description:"Access log template, used by the template format." json:"template,omitempty" toml:"template,omitempty" yaml:"template,omitempty" export:"true"`
 	Filters       *AccessLogFilters `description:"Access log filters, used to keep only specific access logs." json:"filters,omitempty" toml:"filters,omitempty" yaml:"filters,omitempty" export:"true"`
 	Fields        *AccessLogFields  `description:"AccessLogFields." json:"fields,omitempty" toml:"fields,omitempty" yaml:"fields,omitempty" export:"true"`
 	BufferingSize int64             `description:"Number of access log lines to process in a buffered way." json:"bufferingSize,omitempty" toml:"bufferingSize,omitempty" yaml:"bufferingSize,omitempty" export:"true"`
@@ -50,6 +51,20 @@ func (l *AccessLog) SetDefaults() {
 	l.Fields.SetDefaults()
 }
 
+// DecisionLog holds the configuration settings for the decision log,
+// which records how the HTTP routers are evaluated for a sample of the requests.
+type DecisionLog struct {
+	FilePath    string  `description:"Decision log file path. Stdout is used when omitted or empty." json:"filePath,omitempty" toml:"filePath,omitempty" yaml:"filePath,omitempty"`
+	SampleRate  float64 `description:"Fraction of the requests whose routing decision is logged, between 0 and 1." json:"sampleRate,omitempty" toml:"sampleRate,omitempty" yaml:"sampleRate,omitempty" export:"true"`
+	TokenHeader string  `description:"Name of the request header carrying the debug token." json:"tokenHeader,omitempty" toml:"tokenHeader,omitempty" yaml:"tokenHeader,omitempty" export:"true"`
+	Token       string  `description:"Debug token, whose bearers always get their routing decision logged." json:"token,omitempty" toml:"token,omitempty" yaml:"token,omitempty"`
+}
+
+// SetDefaults sets the default values.
+func (l *DecisionLog) SetDefaults() {
+	l.TokenHeader = "X-Traefik-Debug"
+}
+
 // AccessLogFilters holds filters configuration.
 type AccessLogFilters struct {
 	StatusCodes   []string       `description:"Keep access logs with status codes in the specified range." json:"statusCodes,omitempty" toml:"statusCodes,omitempty" yaml:"statusCodes,omitempty" export:"true"`
@@ -0,0 +1,8 @@
+package types
+
+// Runtime holds the Go runtime tuning options.
+type Runtime struct {
+	GCPercent   int   `description:"Garbage collection target percentage, overriding the GOGC environment variable when not zero (-1 disables the garbage collection)." json:"gcPercent,omitempty" toml:"gcPercent,omitempty" yaml:"gcPercent,omitempty" export:"true"`
+	MemoryLimit int64 `description:"Soft memory limit of the Go runtime, in bytes." json:"memoryLimit,omitempty" toml:"memoryLimit,omitempty" yaml:"memoryLimit,omitempty" export:"true"`
+	Ballast     int64 `description:"Size of the memory ballast, in bytes, allocated to reduce the garbage collection frequency." json:"ballast,omitempty" toml:"ballast,omitempty" yaml:"ballast,omitempty" export:"true"`
+}